@@ -207,12 +207,15 @@ func (c *Cluster) goFlushStore(sCtx signal.Context) {
 			Encoder:     c.Codec,
 		}
 		flush.FlushSync(sCtx, c.Store.CopyState())
-		c.OnChange(func(_ context.Context, change Change) {
+		c.OnChange(func(_ context.Context, _ Change) {
 			select {
 			case <-sCtx.Done():
 				return
 			default:
-				flush.Flush(sCtx, change.State)
+				// Changes may be delivered after the state has moved on (e.g. a change
+				// emitted before the cluster key was set), so we always flush the
+				// latest state instead of the state attached to the change.
+				flush.Flush(sCtx, c.Store.CopyState())
 			}
 		})
 		sCtx.Go(func(ctx context.Context) error {
@@ -0,0 +1,665 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	"github.com/synnaxlabs/cesium/internal/meta"
+	"github.com/synnaxlabs/cesium/internal/unary"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+const (
+	// alterDirInfix marks the directory that the migrated data of a channel is written
+	// to while it is being altered.
+	alterDirInfix = "-ALTER-"
+	// replacedDirInfix marks the directory that the original data of an altered channel
+	// is moved to while the migrated data is moved into place.
+	replacedDirInfix = "-REPLACED-"
+)
+
+// AlterChannelConfig defines the changes made to a channel by DB.AlterChannel.
+type AlterChannelConfig struct {
+	// DataType is the new data type of the channel. Every sample is cast to the new
+	// data type, and the alter fails if any sample cannot be represented in it. If
+	// zero, the data type is left unchanged.
+	DataType telem.DataType
+	// Index is the key of the new index channel. The index must have the exact same
+	// timestamps as the current index (or rate) over every domain of the channel. If
+	// zero, the index is left unchanged.
+	Index ChannelKey
+	// OnProgress is called after each domain of the channel is migrated.
+	// [OPTIONAL]
+	OnProgress func(AlterProgress)
+}
+
+// AlterProgress reports the progress of a DB.AlterChannel call.
+type AlterProgress struct {
+	// Processed is the number of bytes of channel data migrated so far.
+	Processed int64
+	// Total is the number of bytes of channel data known to need migration. Total may
+	// grow while the alter is running if data is written to the channel.
+	Total int64
+}
+
+// AlterChannel changes the data type and/or index of the channel with the given key.
+//
+// The channel's data is migrated online: the committed domains of the channel are
+// cast to the new data type in the background while the channel continues to serve
+// reads and writes. Once the bulk of the data is migrated, AlterChannel blocks the
+// opening of new writers on the database, migrates anything committed in the meantime,
+// and swaps the migrated data in. AlterChannel returns an error if there are open
+// writers or iterators on the channel at the time of the swap, in which case the
+// channel is left unchanged.
+func (db *DB) AlterChannel(ctx context.Context, key ChannelKey, cfg AlterChannelConfig) (err error) {
+	if db.closed.Load() {
+		return errDBClosed
+	}
+	ctx, span := db.T.Prod(ctx, "alter_channel")
+	defer func() {
+		lo.Ternary(err == nil, db.L.Debug, db.L.Error)(
+			"altering channel",
+			zap.Uint32("key", key),
+			zap.String("data_type", string(cfg.DataType)),
+			zap.Uint32("index", cfg.Index),
+			zap.Error(err),
+		)
+		err = span.EndWith(err)
+	}()
+	a := &alter{db: db, cfg: cfg}
+	if err = a.validate(key); err != nil {
+		return err
+	}
+	tmpName := keyToDirName(key) + alterDirInfix + strconv.Itoa(rand.Int())
+	defer func() {
+		if err != nil {
+			err = errors.CombineErrors(err, db.fs.Remove(tmpName))
+		}
+	}()
+	if a.fs, err = db.fs.Sub(tmpName); err != nil {
+		return err
+	}
+	if err = a.openDst(); err != nil {
+		return err
+	}
+	defer func() {
+		if a.dst != nil {
+			err = errors.CombineErrors(err, a.dst.Close())
+		}
+	}()
+	if err = a.migrateBulk(ctx); err != nil {
+		return err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return a.swap(ctx, tmpName)
+}
+
+type alter struct {
+	db  *DB
+	cfg AlterChannelConfig
+	// ch is the channel as it was when the alter started.
+	ch Channel
+	// fs is the file system of the temporary directory the migrated data is written to.
+	fs xfs.FS
+	// dst is the domain DB holding the migrated data.
+	dst *domain.DB
+	// migrated contains the domains of the original channel that have been migrated
+	// to dst.
+	migrated map[domain.Domain]struct{}
+	// oldIdx and newIdx are the current and new index of the channel, and are only
+	// set if the index is being changed.
+	oldIdx, newIdx unary.DB
+	progress       AlterProgress
+}
+
+// validate checks that the alter can be applied to the channel with the given key,
+// setting a.ch to the current state of the channel.
+func (a *alter) validate(key ChannelKey) error {
+	a.db.mu.RLock()
+	defer a.db.mu.RUnlock()
+	if _, ok := a.db.virtualDBs[key]; ok {
+		return errors.Wrapf(validate.Error, "cannot alter virtual channel %d", key)
+	}
+	u, ok := a.db.unaryDBs[key]
+	if !ok {
+		return core.NewErrChannelNotFound(key)
+	}
+	a.ch = u.Channel()
+	if err := a.validateChannel(a.ch); err != nil {
+		return err
+	}
+	a.newIdx = a.db.unaryDBs[a.cfg.Index]
+	a.oldIdx = a.db.unaryDBs[a.ch.Index]
+	return nil
+}
+
+func (a *alter) validateChannel(ch Channel) error {
+	v := validate.New("cesium.alter")
+	v.Ternaryf("key", ch.IsIndex, "cannot alter index channel %d", ch.Key)
+	v.Ternaryf("data_type", a.cfg.DataType != "" && !castable(a.cfg.DataType),
+		"cannot alter channel to data type %s", a.cfg.DataType)
+	v.Ternaryf("data_type", a.cfg.DataType != "" && !castable(ch.DataType),
+		"cannot alter channel %d with data type %s", ch.Key, ch.DataType)
+	if a.cfg.Index != 0 {
+		v.Ternaryf("index", a.cfg.Index == ch.Key, "channel %d cannot index itself", ch.Key)
+		validate.MapContainsf(v, a.cfg.Index, a.db.unaryDBs, "index channel <%d> does not exist", a.cfg.Index)
+		if idx, ok := a.db.unaryDBs[a.cfg.Index]; ok {
+			v.Ternaryf("index", !idx.Channel().IsIndex, "channel %v is not an index", idx.Channel())
+		}
+	}
+	return v.Error()
+}
+
+func (a *alter) dataType() telem.DataType {
+	if a.cfg.DataType != "" {
+		return a.cfg.DataType
+	}
+	return a.ch.DataType
+}
+
+func (a *alter) openDst() (err error) {
+	a.migrated = make(map[domain.Domain]struct{})
+	a.dst, err = domain.Open(domain.Config{
+		FS:              a.fs,
+		Instrumentation: a.db.Instrumentation,
+		FileSize:        a.db.fileSize,
+		Variable:        a.ch.DataType.IsVariable(),
+		DataType:        a.dataType(),
+	})
+	return err
+}
+
+// migrateBulk migrates every domain of the channel that is unlikely to change before
+// the swap. The last domain is skipped, as it is typically still being extended by an
+// open writer.
+func (a *alter) migrateBulk(ctx context.Context) error {
+	a.db.mu.RLock()
+	u, ok := a.db.unaryDBs[a.ch.Key]
+	a.db.mu.RUnlock()
+	if !ok {
+		return core.NewErrChannelNotFound(a.ch.Key)
+	}
+	s, err := u.OpenSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	domains := s.Domains()
+	if len(domains) > 0 {
+		domains = domains[:len(domains)-1]
+	}
+	for _, d := range domains {
+		a.progress.Total += d.Size()
+	}
+	return errors.CombineErrors(a.migrate(ctx, s, domains), s.Close())
+}
+
+// swap migrates the domains committed since migrateBulk ran and replaces the
+// channel's data with the migrated data. The database lock must be held.
+func (a *alter) swap(ctx context.Context, tmpName string) error {
+	u, ok := a.db.unaryDBs[a.ch.Key]
+	if !ok {
+		return core.NewErrChannelNotFound(a.ch.Key)
+	}
+	ch := u.Channel()
+	if ch.DataType != a.ch.DataType || ch.Index != a.ch.Index {
+		return errors.Newf("channel %d was altered concurrently", ch.Key)
+	}
+	if err := a.validateChannel(ch); err != nil {
+		return err
+	}
+	// Closing the channel guarantees that no writers or iterators are open on it, and
+	// that no more data can be committed until the swap completes.
+	if err := u.Close(); err != nil {
+		return err
+	}
+	delete(a.db.unaryDBs, ch.Key)
+	altered, oldName, err := a.replace(ctx, ch, tmpName)
+	if err != nil {
+		// Reopen the original channel so that it can keep serving reads and writes,
+		// unless it could not be moved back into place, in which case it is restored
+		// when the database is next opened.
+		exists, eErr := a.db.fs.Exists(keyToDirName(ch.Key))
+		if eErr != nil || !exists {
+			return errors.CombineErrors(err, eErr)
+		}
+		return errors.CombineErrors(err, a.db.openVirtualOrUnary(ch))
+	}
+	if err = a.db.openVirtualOrUnary(altered); err != nil {
+		return err
+	}
+	return errors.CombineErrors(a.db.removeColdDir(oldName), a.db.fs.Remove(oldName))
+}
+
+// replace migrates the remaining data of the closed channel and moves the migrated
+// data into the channel's directory, returning the altered channel and the name the
+// original directory was moved to. If replace fails, the original directory is left
+// in place.
+func (a *alter) replace(
+	ctx context.Context,
+	ch Channel,
+	tmpName string,
+) (Channel, string, error) {
+	if err := a.migrateRemaining(ctx); err != nil {
+		return ch, "", err
+	}
+	if err := a.dst.Close(); err != nil {
+		return ch, "", err
+	}
+	a.dst = nil
+	ch.DataType = a.dataType()
+	if a.cfg.Index != 0 {
+		ch.Index = a.cfg.Index
+	}
+	if err := meta.Create(a.fs, a.db.metaCodec, ch); err != nil {
+		return ch, "", err
+	}
+	var (
+		name    = keyToDirName(ch.Key)
+		oldName = name + replacedDirInfix + strconv.Itoa(rand.Int())
+	)
+	// The hot directory is moved before the cold one so that a cold directory is only
+	// ever left behind with its hot directory, allowing recoverAlters to restore both.
+	// The migrated data is entirely stored in the hot tier, so the cold files of the
+	// original channel are discarded along with its directory.
+	if err := a.db.fs.Rename(name, oldName); err != nil {
+		return ch, "", err
+	}
+	if err := a.db.renameColdDir(name, oldName); err != nil {
+		return ch, "", errors.CombineErrors(err, a.db.fs.Rename(oldName, name))
+	}
+	if err := a.db.fs.Rename(tmpName, name); err != nil {
+		return ch, "", errors.CombineErrors(err, errors.CombineErrors(
+			a.db.renameColdDir(oldName, name),
+			a.db.fs.Rename(oldName, name),
+		))
+	}
+	return ch, oldName, nil
+}
+
+// recoverDirectories removes the directories left behind by channel deletions and
+// alters that were interrupted, and restores the original data of any channel whose
+// alter was interrupted before its migrated data was moved into place.
+func (db *DB) recoverDirectories() error {
+	infos, err := db.fs.List("")
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() {
+			continue
+		}
+		switch {
+		case strings.Contains(name, replacedDirInfix):
+			err = db.recoverReplaced(name)
+		case strings.Contains(name, deleteDirInfix):
+			err = errors.CombineErrors(db.removeColdDir(name), db.fs.Remove(name))
+		case strings.Contains(name, alterDirInfix):
+			err = db.fs.Remove(name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if db.coldTier == nil {
+		return nil
+	}
+	if infos, err = db.coldTier.FS.List(""); err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if info.IsDir() && strings.Contains(info.Name(), deleteDirInfix) {
+			if err = db.coldTier.FS.Remove(info.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// recoverReplaced removes the directory holding the original data of an altered
+// channel if the migrated data was moved into place, and moves it back into place
+// otherwise.
+func (db *DB) recoverReplaced(oldName string) error {
+	name := oldName[:strings.Index(oldName, replacedDirInfix)]
+	exists, err := db.fs.Exists(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.CombineErrors(db.removeColdDir(oldName), db.fs.Remove(oldName))
+	}
+	db.L.Info("restoring channel from interrupted alter", zap.String("dir", name))
+	if err = db.renameColdDir(oldName, name); err != nil {
+		return err
+	}
+	return db.fs.Rename(oldName, name)
+}
+
+// migrateRemaining migrates every domain of the closed channel that has not yet been
+// migrated. If a domain that was already migrated changed in the meantime (e.g. it was
+// extended, deleted, or rewritten by garbage collection), all migrated data is
+// discarded and the channel is migrated from scratch.
+func (a *alter) migrateRemaining(ctx context.Context) (err error) {
+	fs, err := a.db.fs.Sub(keyToDirName(a.ch.Key))
+	if err != nil {
+		return err
+	}
+	coldFS, err := a.db.coldFS(a.ch.Key)
+	if err != nil {
+		return err
+	}
+	u, err := unary.Open(unary.Config{
+		FS:              fs,
+		ColdFS:          coldFS,
+		MetaCodec:       a.db.metaCodec,
+		Instrumentation: a.db.Instrumentation,
+		FileSize:        a.db.fileSize,
+		GCThreshold:     a.db.gcCfg.GCThreshold,
+	})
+	if err != nil {
+		return err
+	}
+	s, err := u.OpenSnapshot(ctx)
+	if err != nil {
+		return errors.CombineErrors(err, u.Close())
+	}
+	defer func() {
+		err = errors.CombineErrors(err, s.Close())
+		err = errors.CombineErrors(err, u.Close())
+	}()
+	var (
+		domains   = s.Domains()
+		remaining = make([]domain.Domain, 0, len(domains))
+		found     int
+	)
+	for _, d := range domains {
+		if _, ok := a.migrated[d]; ok {
+			found++
+		} else {
+			remaining = append(remaining, d)
+		}
+	}
+	if found != len(a.migrated) {
+		if err = a.reset(); err != nil {
+			return err
+		}
+		a.progress = AlterProgress{}
+		remaining = domains
+	}
+	for _, d := range remaining {
+		a.progress.Total += d.Size()
+	}
+	return a.migrate(ctx, s, remaining)
+}
+
+// reset discards all migrated data.
+func (a *alter) reset() error {
+	if err := a.dst.Close(); err != nil {
+		return err
+	}
+	a.dst = nil
+	files, err := a.fs.List("")
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err = a.fs.Remove(f.Name()); err != nil {
+			return err
+		}
+	}
+	return a.openDst()
+}
+
+// migrate validates, casts, and writes the given domains of the snapshot to dst.
+func (a *alter) migrate(ctx context.Context, s *domain.Snapshot, domains []domain.Domain) error {
+	for _, d := range domains {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.Size() == 0 {
+			a.migrated[d] = struct{}{}
+			continue
+		}
+		data, err := s.Read(ctx, d)
+		if err != nil {
+			return err
+		}
+		if a.cfg.Index != 0 && a.cfg.Index != a.ch.Index {
+			if err = a.validateAlignment(ctx, d.TimeRange(), data); err != nil {
+				return err
+			}
+		}
+		if data, err = cast(data, a.ch.DataType, a.dataType()); err != nil {
+			return errors.Wrapf(err, "failed to cast domain %s of channel %d", d.TimeRange(), a.ch.Key)
+		}
+		if err = domain.Write(ctx, a.dst, d.TimeRange(), data); err != nil {
+			return err
+		}
+		a.migrated[d] = struct{}{}
+		a.progress.Processed += d.Size()
+		if a.cfg.OnProgress != nil {
+			a.cfg.OnProgress(a.progress)
+		}
+	}
+	return nil
+}
+
+// validateAlignment checks that the new index has exactly the same timestamps as the
+// channel's current index (or rate) over the given domain.
+func (a *alter) validateAlignment(ctx context.Context, tr telem.TimeRange, data []byte) error {
+	var (
+		n        = telem.Series{DataType: a.ch.DataType, Data: data}.Len()
+		expected []byte
+	)
+	if a.ch.Index == 0 {
+		stamps := make([]telem.TimeStamp, n)
+		for i := range stamps {
+			stamps[i] = tr.Start.Add(a.ch.Rate.Span(i))
+		}
+		expected = telem.NewSeriesV[telem.TimeStamp](stamps...).Data
+	} else {
+		fr, err := a.oldIdx.Read(ctx, tr)
+		if err != nil {
+			return err
+		}
+		expected = seriesData(fr)
+	}
+	fr, err := a.newIdx.Read(ctx, tr)
+	if err != nil {
+		return err
+	}
+	if actual := seriesData(fr); !bytes.Equal(expected, actual) {
+		return errors.Wrapf(
+			validate.Error,
+			"index channel %d is not aligned with the data of channel %d over %s: expected %d timestamps, found %d",
+			a.cfg.Index, a.ch.Key, tr, n, telem.TimeStampT.Density().SampleCount(telem.Size(len(actual))),
+		)
+	}
+	return nil
+}
+
+func seriesData(fr core.Frame) []byte {
+	var b []byte
+	for _, s := range fr.Series {
+		b = append(b, s.Data...)
+	}
+	return b
+}
+
+// castable returns true if the data of channels with the given data type can be cast
+// by AlterChannel.
+func castable(dt telem.DataType) bool { return dt.IsNumeric() || dt == telem.TimeStampT }
+
+// number is a numeric value decoded without loss from any castable data type.
+type number struct {
+	kind uint8
+	i    int64
+	u    uint64
+	f    float64
+}
+
+const (
+	kindInt uint8 = iota
+	kindUint
+	kindFloat
+)
+
+func decodeNumber(dt telem.DataType, b []byte) number {
+	switch dt {
+	case telem.Float64T, telem.Float32T:
+		return number{kind: kindFloat, f: telem.UnmarshalF[float64](dt)(b)}
+	case telem.Uint64T, telem.Uint32T, telem.Uint16T, telem.Uint8T:
+		return number{kind: kindUint, u: telem.UnmarshalF[uint64](dt)(b)}
+	default:
+		return number{kind: kindInt, i: telem.UnmarshalF[int64](dt)(b)}
+	}
+}
+
+func (n number) String() string {
+	switch n.kind {
+	case kindInt:
+		return strconv.FormatInt(n.i, 10)
+	case kindUint:
+		return strconv.FormatUint(n.u, 10)
+	default:
+		return strconv.FormatFloat(n.f, 'g', -1, 64)
+	}
+}
+
+// toInt returns the value as a signed integer with the given number of bits, returning
+// false if the value is out of range or not a whole number.
+func (n number) toInt(bits int) (int64, bool) {
+	var (
+		lo = int64(-1) << (bits - 1)
+		hi = int64(uint64(1)<<(bits-1) - 1)
+	)
+	switch n.kind {
+	case kindInt:
+		return n.i, n.i >= lo && n.i <= hi
+	case kindUint:
+		return int64(n.u), n.u <= uint64(hi)
+	default:
+		ok := n.f == math.Trunc(n.f) && n.f >= float64(lo) && n.f < -float64(lo)
+		return int64(n.f), ok
+	}
+}
+
+// toUint returns the value as an unsigned integer with the given number of bits,
+// returning false if the value is out of range or not a whole number.
+func (n number) toUint(bits int) (uint64, bool) {
+	hi := uint64(math.MaxUint64) >> (64 - bits)
+	switch n.kind {
+	case kindInt:
+		return uint64(n.i), n.i >= 0 && uint64(n.i) <= hi
+	case kindUint:
+		return n.u, n.u <= hi
+	default:
+		ok := n.f == math.Trunc(n.f) && n.f >= 0 && n.f < math.Ldexp(1, bits)
+		return uint64(n.f), ok
+	}
+}
+
+func (n number) toFloat() float64 {
+	switch n.kind {
+	case kindInt:
+		return float64(n.i)
+	case kindUint:
+		return float64(n.u)
+	default:
+		return n.f
+	}
+}
+
+func encodeNumber(dt telem.DataType, b []byte, n number) bool {
+	switch dt {
+	case telem.Float64T:
+		telem.ByteOrder.PutUint64(b, math.Float64bits(n.toFloat()))
+	case telem.Float32T:
+		f := n.toFloat()
+		if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+			return false
+		}
+		telem.ByteOrder.PutUint32(b, math.Float32bits(float32(f)))
+	case telem.Int64T, telem.TimeStampT:
+		v, ok := n.toInt(64)
+		telem.ByteOrder.PutUint64(b, uint64(v))
+		return ok
+	case telem.Int32T:
+		v, ok := n.toInt(32)
+		telem.ByteOrder.PutUint32(b, uint32(v))
+		return ok
+	case telem.Int16T:
+		v, ok := n.toInt(16)
+		telem.ByteOrder.PutUint16(b, uint16(v))
+		return ok
+	case telem.Int8T:
+		v, ok := n.toInt(8)
+		b[0] = byte(v)
+		return ok
+	case telem.Uint64T:
+		v, ok := n.toUint(64)
+		telem.ByteOrder.PutUint64(b, v)
+		return ok
+	case telem.Uint32T:
+		v, ok := n.toUint(32)
+		telem.ByteOrder.PutUint32(b, uint32(v))
+		return ok
+	case telem.Uint16T:
+		v, ok := n.toUint(16)
+		telem.ByteOrder.PutUint16(b, uint16(v))
+		return ok
+	case telem.Uint8T:
+		v, ok := n.toUint(8)
+		b[0] = byte(v)
+		return ok
+	}
+	return true
+}
+
+// cast converts the samples in data from one data type to another, returning an error
+// if any sample cannot be represented in the new data type. Floating point values are
+// only cast to integers if they are whole numbers, and values are never clamped.
+func cast(data []byte, from, to telem.DataType) ([]byte, error) {
+	if from == to {
+		return data, nil
+	}
+	var (
+		fromDensity = int(from.Density())
+		toDensity   = int(to.Density())
+		n           = len(data) / fromDensity
+		out         = make([]byte, n*toDensity)
+	)
+	for i := 0; i < n; i++ {
+		v := decodeNumber(from, data[i*fromDensity:])
+		if !encodeNumber(to, out[i*toDensity:(i+1)*toDensity], v) {
+			return nil, errors.Wrapf(
+				validate.Error,
+				"sample %d with value %s cannot be represented as %s",
+				i, v, to,
+			)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,324 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/cesium"
+	"github.com/synnaxlabs/cesium/internal/testutil"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Alter", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db                  *cesium.DB
+				fs                  xfs.FS
+				cleanUp             func() error
+				index, index2, data cesium.ChannelKey
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = openDBOnFS(fs)
+				index = testutil.GenerateChannelKey()
+				index2 = testutil.GenerateChannelKey()
+				data = testutil.GenerateChannelKey()
+				Expect(db.CreateChannel(
+					ctx,
+					cesium.Channel{Key: index, IsIndex: true, DataType: telem.TimeStampT},
+					cesium.Channel{Key: index2, IsIndex: true, DataType: telem.TimeStampT},
+					cesium.Channel{Key: data, Index: index, DataType: telem.Int64T},
+				)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+			write := func(key cesium.ChannelKey, start telem.TimeStamp, series ...telem.Series) {
+				Expect(db.Write(ctx, start, cesium.NewFrame(
+					[]cesium.ChannelKey{key, data},
+					series,
+				))).To(Succeed())
+			}
+			read := func(key cesium.ChannelKey) []byte {
+				var b []byte
+				for _, s := range MustSucceed(db.Read(ctx, telem.TimeRangeMax, key)).Series {
+					b = append(b, s.Data...)
+				}
+				return b
+			}
+
+			Describe("DataType", func() {
+				It("Should cast the data of the channel to the new data type", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11, 12), telem.NewSeriesV[int64](1, -2, 3))
+					write(index, 20*telem.SecondTS, telem.NewSecondsTSV(20, 21), telem.NewSeriesV[int64](4, 5))
+					var progress []cesium.AlterProgress
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{
+						DataType:   telem.Float32T,
+						OnProgress: func(p cesium.AlterProgress) { progress = append(progress, p) },
+					})).To(Succeed())
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).DataType).To(Equal(telem.Float32T))
+					Expect(read(data)).To(Equal(telem.NewSeriesV[float32](1, -2, 3, 4, 5).Data))
+					Expect(progress).ToNot(BeEmpty())
+					last := progress[len(progress)-1]
+					Expect(last.Processed).To(Equal(last.Total))
+					Expect(last.Total).To(Equal(int64(5 * 8)))
+
+					By("Writing to the channel with the new data type")
+					write(index, 30*telem.SecondTS, telem.NewSecondsTSV(30), telem.NewSeriesV[float32](6.5))
+					Expect(read(data)).To(Equal(telem.NewSeriesV[float32](1, -2, 3, 4, 5, 6.5).Data))
+
+					By("Reopening the database")
+					Expect(db.Close()).To(Succeed())
+					db = openDBOnFS(fs)
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).DataType).To(Equal(telem.Float32T))
+					Expect(read(data)).To(Equal(telem.NewSeriesV[float32](1, -2, 3, 4, 5, 6.5).Data))
+				})
+
+				DescribeTable("Should not cast values that cannot be represented", func(
+					dt telem.DataType,
+					s telem.Series,
+				) {
+					ch := testutil.GenerateChannelKey()
+					expected := bytes.Clone(s.Data)
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: ch, Index: index, DataType: s.DataType})).To(Succeed())
+					Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{index, ch},
+						[]telem.Series{telem.NewSecondsTSV(10, 11), s},
+					))).To(Succeed())
+					err := db.AlterChannel(ctx, ch, cesium.AlterChannelConfig{DataType: dt})
+					Expect(err).To(HaveOccurredAs(validate.Error))
+					Expect(err).To(MatchError(ContainSubstring("sample 1")))
+					Expect(MustSucceed(db.RetrieveChannel(ctx, ch)).DataType).To(Equal(s.DataType))
+					Expect(read(ch)).To(Equal(expected))
+				},
+					Entry("Fractional float to int", telem.Int32T, telem.NewSeriesV[float64](1, 1.5)),
+					Entry("Int overflow", telem.Int8T, telem.NewSeriesV[int64](127, 128)),
+					Entry("Negative to unsigned", telem.Uint16T, telem.NewSeriesV[int32](0, -1)),
+					Entry("Unsigned overflow", telem.Int64T, telem.NewSeriesV[uint64](1, 1<<63)),
+					Entry("Float32 overflow", telem.Float32T, telem.NewSeriesV[float64](1, 1e300)),
+				)
+
+				It("Should migrate data written during the alter", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11), telem.NewSeriesV[int64](1, 2))
+					write(index, 20*telem.SecondTS, telem.NewSecondsTSV(20, 21), telem.NewSeriesV[int64](3, 4))
+					written := false
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{
+						DataType: telem.Int32T,
+						OnProgress: func(cesium.AlterProgress) {
+							if !written {
+								written = true
+								write(index, 30*telem.SecondTS, telem.NewSecondsTSV(30), telem.NewSeriesV[int64](5))
+							}
+						},
+					})).To(Succeed())
+					Expect(written).To(BeTrue())
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int32](1, 2, 3, 4, 5).Data))
+				})
+
+				It("Should migrate from scratch if migrated data is deleted during the alter", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11), telem.NewSeriesV[int64](1, 2))
+					write(index, 20*telem.SecondTS, telem.NewSecondsTSV(20, 21), telem.NewSeriesV[int64](3, 4))
+					deleted := false
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{
+						DataType: telem.Int32T,
+						OnProgress: func(cesium.AlterProgress) {
+							if !deleted {
+								deleted = true
+								Expect(db.DeleteTimeRange(
+									ctx,
+									[]cesium.ChannelKey{data},
+									(11 * telem.SecondTS).Range(12*telem.SecondTS),
+								)).To(Succeed())
+							}
+						},
+					})).To(Succeed())
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int32](1, 3, 4).Data))
+				})
+
+				It("Should not alter a channel with an open writer", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10), telem.NewSeriesV[int64](1))
+					w := MustSucceed(db.OpenWriter(ctx, cesium.WriterConfig{
+						Start:    20 * telem.SecondTS,
+						Channels: []cesium.ChannelKey{index, data},
+					}))
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{DataType: telem.Int32T})).
+						To(MatchError(ContainSubstring("open")))
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).DataType).To(Equal(telem.Int64T))
+					Expect(w.Close()).To(Succeed())
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{DataType: telem.Int32T})).To(Succeed())
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int32](1).Data))
+				})
+
+				It("Should not alter an index channel", func() {
+					Expect(db.AlterChannel(ctx, index, cesium.AlterChannelConfig{DataType: telem.Int64T})).
+						To(MatchError(ContainSubstring("cannot alter index channel")))
+				})
+
+				It("Should not alter a channel to a variable density data type", func() {
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{DataType: telem.StringT})).
+						To(MatchError(ContainSubstring("cannot alter channel to data type")))
+				})
+
+				It("Should return an error if the channel does not exist", func() {
+					Expect(db.AlterChannel(ctx, testutil.GenerateChannelKey(), cesium.AlterChannelConfig{
+						DataType: telem.Int32T,
+					})).To(HaveOccurredAs(cesium.ErrChannelNotFound))
+				})
+			})
+
+			Describe("Index", func() {
+				It("Should reattach the channel to an aligned index", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11, 12), telem.NewSeriesV[int64](1, 2, 3))
+					Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{index2},
+						[]telem.Series{telem.NewSecondsTSV(10, 11, 12)},
+					))).To(Succeed())
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{Index: index2})).To(Succeed())
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).Index).To(Equal(index2))
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int64](1, 2, 3).Data))
+
+					By("Deleting the old index")
+					Expect(db.DeleteChannel(index)).To(Succeed())
+					Expect(db.Write(ctx, 20*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{index2, data},
+						[]telem.Series{telem.NewSecondsTSV(20), telem.NewSeriesV[int64](4)},
+					))).To(Succeed())
+					f := MustSucceed(db.Read(ctx, (11 * telem.SecondTS).Range(21*telem.SecondTS), data))
+					var b []byte
+					for _, s := range f.Series {
+						b = append(b, s.Data...)
+					}
+					Expect(b).To(Equal(telem.NewSeriesV[int64](2, 3, 4).Data))
+				})
+
+				It("Should reattach a rate based channel to an aligned index", func() {
+					rated := testutil.GenerateChannelKey()
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: rated, Rate: 1 * telem.Hz, DataType: telem.Int64T})).To(Succeed())
+					Expect(db.WriteArray(ctx, rated, 10*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3))).To(Succeed())
+					Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{index2},
+						[]telem.Series{telem.NewSecondsTSV(10, 11, 12)},
+					))).To(Succeed())
+					Expect(db.AlterChannel(ctx, rated, cesium.AlterChannelConfig{Index: index2})).To(Succeed())
+					Expect(read(rated)).To(Equal(telem.NewSeriesV[int64](1, 2, 3).Data))
+				})
+
+				It("Should not reattach the channel to a misaligned index", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11, 12), telem.NewSeriesV[int64](1, 2, 3))
+					Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{index2},
+						[]telem.Series{telem.NewSecondsTSV(10, 12)},
+					))).To(Succeed())
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{Index: index2})).
+						To(MatchError(ContainSubstring("not aligned")))
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).Index).To(Equal(index))
+				})
+
+				It("Should not reattach the channel to a channel that is not an index", func() {
+					other := testutil.GenerateChannelKey()
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: other, Index: index, DataType: telem.Int64T})).To(Succeed())
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{Index: other})).
+						To(MatchError(ContainSubstring("is not an index")))
+				})
+			})
+
+			Describe("Recovery", func() {
+				dirs := func() []string {
+					return lo.Map(MustSucceed(fs.List("")), func(info os.FileInfo, _ int) string {
+						return info.Name()
+					})
+				}
+				channelDirs := func() []string {
+					return []string{channelKeyToPath(index), channelKeyToPath(index2), channelKeyToPath(data)}
+				}
+
+				DescribeTable("Should keep the original channel if a rename fails", func(
+					fail func(oldName, newName string) bool,
+				) {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11), telem.NewSeriesV[int64](1, 2))
+					Expect(db.Close()).To(Succeed())
+					db = openDBOnFS(renameFailFS{FS: fs, fail: fail})
+					Expect(db.AlterChannel(ctx, data, cesium.AlterChannelConfig{DataType: telem.Int32T})).
+						To(MatchError(errRenameFailed))
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).DataType).To(Equal(telem.Int64T))
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int64](1, 2).Data))
+					write(index, 20*telem.SecondTS, telem.NewSecondsTSV(20), telem.NewSeriesV[int64](3))
+					Expect(dirs()).To(ConsistOf(channelDirs()))
+
+					By("Reopening the database")
+					Expect(db.Close()).To(Succeed())
+					db = openDBOnFS(fs)
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int64](1, 2, 3).Data))
+				},
+					Entry("Moving the original data", func(oldName, _ string) bool {
+						return !strings.Contains(oldName, "-")
+					}),
+					Entry("Moving the migrated data", func(oldName, _ string) bool {
+						return strings.Contains(oldName, "-ALTER-")
+					}),
+				)
+
+				It("Should restore a channel whose alter was interrupted", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11), telem.NewSeriesV[int64](1, 2))
+					Expect(db.Close()).To(Succeed())
+					name := channelKeyToPath(data)
+					Expect(fs.Rename(name, name+"-REPLACED-1")).To(Succeed())
+					MustSucceed(fs.Sub(name + "-ALTER-2"))
+					db = openDBOnFS(fs)
+					Expect(MustSucceed(db.RetrieveChannel(ctx, data)).DataType).To(Equal(telem.Int64T))
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int64](1, 2).Data))
+					Expect(dirs()).To(ConsistOf(channelDirs()))
+				})
+
+				It("Should remove the original data of a channel whose alter completed", func() {
+					write(index, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11), telem.NewSeriesV[int64](1, 2))
+					Expect(db.Close()).To(Succeed())
+					MustSucceed(fs.Sub(channelKeyToPath(data) + "-REPLACED-1"))
+					MustSucceed(fs.Sub(channelKeyToPath(index2) + "-DELETE-2"))
+					db = openDBOnFS(fs)
+					Expect(read(data)).To(Equal(telem.NewSeriesV[int64](1, 2).Data))
+					Expect(dirs()).To(ConsistOf(channelDirs()))
+				})
+			})
+		})
+	}
+})
+
+var errRenameFailed = errors.New("rename failed")
+
+// renameFailFS fails every rename for which fail returns true.
+type renameFailFS struct {
+	xfs.FS
+	fail func(oldName, newName string) bool
+}
+
+func (f renameFailFS) Sub(name string) (xfs.FS, error) {
+	sub, err := f.FS.Sub(name)
+	return renameFailFS{FS: sub, fail: f.fail}, err
+}
+
+func (f renameFailFS) Rename(oldName, newName string) error {
+	if f.fail(oldName, newName) {
+		return errRenameFailed
+	}
+	return f.FS.Rename(oldName, newName)
+}
@@ -162,6 +162,8 @@ func (db *DB) validateNewChannel(ch Channel) error {
 	v := validate.New("cesium")
 	validate.Positive(v, "key", ch.Key)
 	validate.NotEmptyString(v, "data_type", ch.DataType)
+	validate.NonNegative(v, "cold_after", ch.ColdAfter)
+	validate.NonNegative(v, "quota", ch.Quota)
 	v.Exec(func() error {
 		_, uOk := db.unaryDBs[ch.Key]
 		_, vOk := db.virtualDBs[ch.Key]
@@ -174,7 +176,6 @@ func (db *DB) validateNewChannel(ch Channel) error {
 		v.Ternaryf("index", ch.Index != 0, "virtual channel cannot be indexed")
 		v.Ternaryf("index", ch.Rate != 0, "virtual channel cannot have a rate")
 	} else {
-		v.Ternary("index", ch.DataType == telem.StringT, "persisted channels cannot have string data types")
 		if ch.IsIndex {
 			v.Ternary("data_type", ch.DataType != telem.TimeStampT, "index channel must be of type timestamp")
 			v.Ternaryf("index", ch.Index != 0 && ch.Index != ch.Key, "index channel cannot be indexed by another channel")
@@ -217,10 +218,17 @@ func (db *DB) RekeyChannel(oldKey ChannelKey, newKey core.ChannelKey) error {
 		if err := db.fs.Rename(oldDir, newDir); err != nil {
 			return err
 		}
+		if err := db.renameColdDir(oldDir, newDir); err != nil {
+			return err
+		}
 		newFS, err := db.fs.Sub(keyToDirName(newKey))
 		if err != nil {
 			return err
 		}
+		coldFS, err := db.coldFS(newKey)
+		if err != nil {
+			return err
+		}
 		newCh := udb.Channel()
 		newCh.Key = newKey
 		if newCh.IsIndex {
@@ -231,6 +239,7 @@ func (db *DB) RekeyChannel(oldKey ChannelKey, newKey core.ChannelKey) error {
 			MetaCodec:       db.metaCodec,
 			Channel:         newCh,
 			FS:              newFS,
+			ColdFS:          coldFS,
 		})
 		if err != nil {
 			return err
@@ -0,0 +1,269 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	. "github.com/synnaxlabs/cesium/internal/testutil"
+	"github.com/synnaxlabs/x/config"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Conflict Policies", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db              *cesium.DB
+				fs              xfs.FS
+				cleanUp         func() error
+				idxKey, dataKey cesium.ChannelKey
+				write           = func(
+					policy cesium.ConflictPolicy,
+					start telem.TimeStamp,
+					keys []cesium.ChannelKey,
+					series ...telem.Series,
+				) error {
+					w, err := db.OpenWriter(ctx, cesium.WriterConfig{
+						Channels:       keys,
+						Start:          start,
+						ConflictPolicy: policy,
+					})
+					if err != nil {
+						return err
+					}
+					w.Write(cesium.NewFrame(keys, series))
+					w.Commit()
+					return w.Close()
+				}
+				read = func(key cesium.ChannelKey) []int64 {
+					fr := MustSucceed(db.Read(ctx, telem.TimeRangeMax, key))
+					var values []int64
+					for _, s := range fr.Series {
+						values = append(values, telem.Unmarshal[int64](s)...)
+					}
+					return values
+				}
+				readStamps = func(key cesium.ChannelKey) []telem.TimeStamp {
+					fr := MustSucceed(db.Read(ctx, telem.TimeRangeMax, key))
+					var values []telem.TimeStamp
+					for _, s := range fr.Series {
+						values = append(values, telem.Unmarshal[telem.TimeStamp](s)...)
+					}
+					return values
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = openDBOnFS(fs)
+				idxKey, dataKey = GenerateChannelKey(), GenerateChannelKey()
+				Expect(db.CreateChannel(
+					ctx,
+					cesium.Channel{Key: idxKey, IsIndex: true, DataType: telem.TimeStampT},
+					cesium.Channel{Key: dataKey, Index: idxKey, DataType: telem.Int64T},
+				)).To(Succeed())
+				Expect(write(
+					cesium.ConflictFail,
+					1*telem.SecondTS,
+					[]cesium.ChannelKey{idxKey, dataKey},
+					telem.NewSecondsTSV(1, 2, 3, 4, 5, 6),
+					telem.NewSeriesV[int64](1, 2, 3, 4, 5, 6),
+				)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			Describe("Fail", func() {
+				It("Should not write over existing data", func() {
+					Expect(write(
+						cesium.ConflictFail,
+						3*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(3, 4),
+						telem.NewSeriesV[int64](30, 40),
+					)).To(HaveOccurredAs(validate.Error))
+					Expect(read(dataKey)).To(Equal([]int64{1, 2, 3, 4, 5, 6}))
+				})
+			})
+
+			Describe("Overwrite", func() {
+				It("Should replace the overlapped region of a domain", func() {
+					Expect(write(
+						cesium.ConflictOverwrite,
+						3*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSeriesV(3*telem.SecondTS, 3500*telem.MillisecondTS, 4*telem.SecondTS),
+						telem.NewSeriesV[int64](30, 35, 40),
+					)).To(Succeed())
+					Expect(read(dataKey)).To(Equal([]int64{1, 2, 30, 35, 40, 5, 6}))
+					Expect(readStamps(idxKey)).To(Equal([]telem.TimeStamp{
+						1 * telem.SecondTS,
+						2 * telem.SecondTS,
+						3 * telem.SecondTS,
+						3500 * telem.MillisecondTS,
+						4 * telem.SecondTS,
+						5 * telem.SecondTS,
+						6 * telem.SecondTS,
+					}))
+				})
+
+				It("Should replace multiple domains", func() {
+					Expect(write(
+						cesium.ConflictFail,
+						10*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(10, 11, 12),
+						telem.NewSeriesV[int64](10, 11, 12),
+					)).To(Succeed())
+					Expect(write(
+						cesium.ConflictOverwrite,
+						5*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(5, 8, 11),
+						telem.NewSeriesV[int64](50, 80, 110),
+					)).To(Succeed())
+					Expect(read(dataKey)).To(Equal([]int64{1, 2, 3, 4, 50, 80, 110, 12}))
+				})
+
+				It("Should replace data on every commit of an auto committing writer", func() {
+					w := MustSucceed(db.OpenWriter(ctx, cesium.WriterConfig{
+						Channels:         []cesium.ChannelKey{idxKey, dataKey},
+						Start:            2 * telem.SecondTS,
+						ConflictPolicy:   cesium.ConflictOverwrite,
+						EnableAutoCommit: config.True(),
+					}))
+					Expect(w.Write(cesium.NewFrame(
+						[]cesium.ChannelKey{idxKey, dataKey},
+						[]telem.Series{telem.NewSecondsTSV(2, 3), telem.NewSeriesV[int64](20, 30)},
+					))).To(BeTrue())
+					Expect(w.Write(cesium.NewFrame(
+						[]cesium.ChannelKey{idxKey, dataKey},
+						[]telem.Series{telem.NewSecondsTSV(4, 5), telem.NewSeriesV[int64](40, 50)},
+					))).To(BeTrue())
+					Expect(w.Close()).To(Succeed())
+					Expect(read(dataKey)).To(Equal([]int64{1, 20, 30, 40, 50, 6}))
+				})
+
+				It("Should overwrite data in a rate based channel", func() {
+					key := GenerateChannelKey()
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: key, Rate: 1 * telem.Hz, DataType: telem.Int64T})).To(Succeed())
+					Expect(db.WriteArray(ctx, key, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5))).To(Succeed())
+					Expect(write(
+						cesium.ConflictOverwrite,
+						2*telem.SecondTS,
+						[]cesium.ChannelKey{key},
+						telem.NewSeriesV[int64](20, 30),
+					)).To(Succeed())
+					Expect(read(key)).To(Equal([]int64{1, 20, 30, 4, 5}))
+				})
+
+				It("Should replace the entire extent of a domain", func() {
+					Expect(write(
+						cesium.ConflictOverwrite,
+						1*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(1, 2, 3, 4, 5, 6),
+						telem.NewSeriesV[int64](10, 20, 30, 40, 50, 60),
+					)).To(Succeed())
+					Expect(read(dataKey)).To(Equal([]int64{10, 20, 30, 40, 50, 60}))
+				})
+			})
+
+			Describe("Fill Gaps", func() {
+				It("Should only write samples that do not overlap with existing data", func() {
+					Expect(write(
+						cesium.ConflictFail,
+						10*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(10, 11),
+						telem.NewSeriesV[int64](10, 11),
+					)).To(Succeed())
+					Expect(write(
+						cesium.ConflictFillGaps,
+						5*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(5, 6, 7, 8, 10, 11, 12, 13),
+						telem.NewSeriesV[int64](0, 0, 70, 80, 0, 0, 120, 130),
+					)).To(Succeed())
+					Expect(read(dataKey)).To(Equal([]int64{1, 2, 3, 4, 5, 6, 70, 80, 10, 11, 120, 130}))
+					Expect(readStamps(idxKey)).To(Equal([]telem.TimeStamp{
+						1 * telem.SecondTS,
+						2 * telem.SecondTS,
+						3 * telem.SecondTS,
+						4 * telem.SecondTS,
+						5 * telem.SecondTS,
+						6 * telem.SecondTS,
+						7 * telem.SecondTS,
+						8 * telem.SecondTS,
+						10 * telem.SecondTS,
+						11 * telem.SecondTS,
+						12 * telem.SecondTS,
+						13 * telem.SecondTS,
+					}))
+				})
+
+				It("Should fill gaps across multiple writes", func() {
+					Expect(write(
+						cesium.ConflictFail,
+						10*telem.SecondTS,
+						[]cesium.ChannelKey{idxKey, dataKey},
+						telem.NewSecondsTSV(10),
+						telem.NewSeriesV[int64](10),
+					)).To(Succeed())
+					w := MustSucceed(db.OpenWriter(ctx, cesium.WriterConfig{
+						Channels:       []cesium.ChannelKey{idxKey, dataKey},
+						Start:          7 * telem.SecondTS,
+						ConflictPolicy: cesium.ConflictFillGaps,
+					}))
+					Expect(w.Write(cesium.NewFrame(
+						[]cesium.ChannelKey{idxKey, dataKey},
+						[]telem.Series{telem.NewSecondsTSV(7, 8, 9), telem.NewSeriesV[int64](70, 80, 90)},
+					))).To(BeTrue())
+					Expect(w.Write(cesium.NewFrame(
+						[]cesium.ChannelKey{idxKey, dataKey},
+						[]telem.Series{telem.NewSecondsTSV(10, 11), telem.NewSeriesV[int64](0, 110)},
+					))).To(BeTrue())
+					_, ok := w.Commit()
+					Expect(ok).To(BeTrue())
+					Expect(w.Close()).To(Succeed())
+					Expect(read(dataKey)).To(Equal([]int64{1, 2, 3, 4, 5, 6, 70, 80, 90, 10, 110}))
+				})
+
+				It("Should fill gaps in a rate based channel", func() {
+					key := GenerateChannelKey()
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: key, Rate: 1 * telem.Hz, DataType: telem.Int64T})).To(Succeed())
+					Expect(db.WriteArray(ctx, key, 3*telem.SecondTS, telem.NewSeriesV[int64](3, 4))).To(Succeed())
+					Expect(write(
+						cesium.ConflictFillGaps,
+						1*telem.SecondTS,
+						[]cesium.ChannelKey{key},
+						telem.NewSeriesV[int64](10, 20, 0, 0, 50, 60),
+					)).To(Succeed())
+					Expect(read(key)).To(Equal([]int64{10, 20, 3, 4, 50, 60}))
+				})
+
+				It("Should not fill gaps in an indexed channel without writing to its index", func() {
+					_, err := db.OpenWriter(ctx, cesium.WriterConfig{
+						Channels:       []cesium.ChannelKey{dataKey},
+						Start:          10 * telem.SecondTS,
+						ConflictPolicy: cesium.ConflictFillGaps,
+					})
+					Expect(err).To(HaveOccurredAs(validate.Error))
+				})
+			})
+		})
+	}
+})
@@ -12,10 +12,12 @@ package cesium
 import (
 	"context"
 	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/domain"
 	"github.com/synnaxlabs/cesium/internal/unary"
 	"github.com/synnaxlabs/cesium/internal/virtual"
 	"github.com/synnaxlabs/x/confluence"
 	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/observe"
 	"github.com/synnaxlabs/x/telem"
 	"io"
 	"sync"
@@ -26,6 +28,11 @@ type (
 	Channel    = core.Channel
 	ChannelKey = core.ChannelKey
 	Frame      = core.Frame
+	// BlockCacheConfig configures the block cache of the database. Size is the only
+	// required parameter.
+	BlockCacheConfig = domain.BlockCacheConfig
+	// BlockCacheStats are the hit, miss, and eviction metrics of the block cache.
+	BlockCacheStats = domain.BlockCacheStats
 )
 
 func NewFrame(keys []core.ChannelKey, series []telem.Series) Frame {
@@ -50,6 +57,26 @@ type DB struct {
 	}
 	closed   *atomic.Bool
 	shutdown io.Closer
+	// blockCache is shared by all unary DBs, and is nil if the database was not
+	// configured with one.
+	blockCache *domain.BlockCache
+	quota      struct {
+		// mu serializes the enforcement of quotas.
+		mu sync.Mutex
+		// states stores the last known state of each quota, keyed by the channel the
+		// quota belongs to, or zero for the NodeLimit.
+		states map[ChannelKey]quotaState
+		events observe.Observer[QuotaEvent]
+	}
+}
+
+// BlockCacheStats returns the metrics of the block cache of the database, or zero
+// values if the database has no block cache.
+func (db *DB) BlockCacheStats() BlockCacheStats {
+	if db.blockCache == nil {
+		return BlockCacheStats{}
+	}
+	return db.blockCache.Stats()
 }
 
 // Write writes the frame to database at the specified start time.
@@ -87,14 +114,14 @@ func (db *DB) Read(ctx context.Context, tr telem.TimeRange, keys ...core.Channel
 	if err != nil {
 		return
 	}
-	defer func() { err = iter.Close() }()
+	defer func() { err = errors.CombineErrors(err, iter.Close()) }()
 	if !iter.SeekFirst() {
-		return
+		return frame, iter.Error()
 	}
 	for iter.Next(telem.TimeSpanMax) {
 		frame = frame.AppendFrame(iter.Value())
 	}
-	return
+	return frame, iter.Error()
 }
 
 // Close closes the database.
@@ -45,6 +45,10 @@ var DefaultGCConfig = GCConfig{
 	GCThreshold:   0.2,
 }
 
+// deleteDirInfix marks the directory that the data of a channel is moved to while it
+// is being deleted.
+const deleteDirInfix = "-DELETE-"
+
 func keyToDirName(ch ChannelKey) string {
 	return strconv.Itoa(int(ch))
 }
@@ -62,7 +66,7 @@ func (db *DB) DeleteChannel(ch ChannelKey) error {
 	// Rename the file to have a random suffix in case the channel is repeatedly created
 	// and deleted.
 	oldName := keyToDirName(ch)
-	newName := oldName + "-DELETE-" + strconv.Itoa(rand.Int())
+	newName := oldName + deleteDirInfix + strconv.Itoa(rand.Int())
 	if err := (func() error {
 		db.mu.Lock()
 		defer db.mu.Unlock()
@@ -73,11 +77,14 @@ func (db *DB) DeleteChannel(ch ChannelKey) error {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		return db.renameColdDir(oldName, newName)
 	})(); err != nil {
 		return err
 	}
-	return db.fs.Remove(newName)
+	return errors.CombineErrors(db.fs.Remove(newName), db.removeColdDir(newName))
 }
 
 // DeleteChannels deletes many channels by their keys.
@@ -100,6 +107,7 @@ func (db *DB) DeleteChannels(chs []ChannelKey) (err error) {
 		c := errors.NewCatcher(errors.WithAggregation())
 		for _, name := range directoriesToRemove {
 			c.Exec(func() error { return db.fs.Remove(name) })
+			c.Exec(func() error { return db.removeColdDir(name) })
 		}
 		err = errors.CombineErrors(err, c.Error())
 	}()
@@ -123,11 +131,14 @@ func (db *DB) DeleteChannels(chs []ChannelKey) (err error) {
 		// Rename the files first, so we can avoid hogging the mutex while deleting
 		// the directory, which may take a longer time.
 		oldName := keyToDirName(ch)
-		newName := oldName + "-DELETE-" + strconv.Itoa(rand.Int())
+		newName := oldName + deleteDirInfix + strconv.Itoa(rand.Int())
 		err = db.fs.Rename(oldName, newName)
 		if err != nil {
 			return
 		}
+		if err = db.renameColdDir(oldName, newName); err != nil {
+			return
+		}
 
 		directoriesToRemove = append(directoriesToRemove, newName)
 	}
@@ -140,11 +151,14 @@ func (db *DB) DeleteChannels(chs []ChannelKey) (err error) {
 		}
 
 		oldName := keyToDirName(ch)
-		newName := oldName + "-DELETE-" + strconv.Itoa(rand.Int())
+		newName := oldName + deleteDirInfix + strconv.Itoa(rand.Int())
 		err = db.fs.Rename(oldName, newName)
 		if err != nil {
 			return
 		}
+		if err = db.renameColdDir(oldName, newName); err != nil {
+			return
+		}
 
 		directoriesToRemove = append(directoriesToRemove, newName)
 	}
@@ -60,6 +60,8 @@ var (
 	numGoRoutines     = flag.Int64("g", 1, "goroutine count")
 	streamOnly        = flag.Bool("only_stream", false, "writer streamOnly mode")
 	commitInterval    = flag.Int("commit", -1, "writer commit interval")
+	blockCacheSize    = flag.Int("cache", 64, "block cache size in megabytes for read_cached")
+	iterConcurrency   = flag.Int("iter", 0, "iterator concurrency for read, defaults to the number of CPUs")
 	ctx               = context.TODO()
 )
 
@@ -94,7 +96,17 @@ func BenchmarkCesium(b *testing.B) {
 	dataSeries, channels, keys := testutil.GenerateDataAndChannels(benchCfg.numIndexChannels, benchCfg.numDataChannels, benchCfg.numRateChannels, benchCfg.samplesPerDomain)
 
 	b.Run("write", func(b *testing.B) { bench_write(b, writeCfg, dataSeries, channels, keys, fs) })
-	b.Run("read", func(b *testing.B) { bench_read(b, benchCfg, dataSeries, channels, keys, fs) })
+	b.Run("read", func(b *testing.B) {
+		bench_read(b, benchCfg, dataSeries, channels, keys, fs, cesium.WithIteratorConcurrency(*iterConcurrency))
+	})
+	b.Run("read_sequential", func(b *testing.B) {
+		bench_read(b, benchCfg, dataSeries, channels, keys, fs, cesium.WithIteratorConcurrency(1))
+	})
+	b.Run("read_cached", func(b *testing.B) {
+		bench_read(b, benchCfg, dataSeries, channels, keys, fs, cesium.WithBlockCache(&cesium.BlockCacheConfig{
+			Size: telem.Size(*blockCacheSize) * telem.Megabyte,
+		}))
+	})
 	b.Run("stream", func(b *testing.B) {
 		bench_stream(b, streamCfg, dataSeries, channels, keys, fs)
 	})
@@ -268,7 +280,7 @@ func bench_write(b *testing.B, cfg WriteBenchmarkConfig, dataSeries telem.Series
 	}
 }
 
-func bench_read(b *testing.B, cfg BenchmarkConfig, dataSeries telem.Series, channels []cesium.Channel, keys []cesium.ChannelKey, fs xfs.FS) {
+func bench_read(b *testing.B, cfg BenchmarkConfig, dataSeries telem.Series, channels []cesium.Channel, keys []cesium.ChannelKey, fs xfs.FS, opts ...cesium.Option) {
 	var (
 		db        *cesium.DB
 		err       error
@@ -277,7 +289,7 @@ func bench_read(b *testing.B, cfg BenchmarkConfig, dataSeries telem.Series, chan
 		hwm       telem.TimeStamp = 0
 	)
 
-	db, err = cesium.Open("benchmark_read_test", cesium.WithFS(fs))
+	db, err = cesium.Open("benchmark_read_test", append([]cesium.Option{cesium.WithFS(fs)}, opts...)...)
 	err = db.CreateChannel(ctx, channels...)
 	if err != nil {
 		b.Errorf("Error during channel creation: %s", err)
@@ -354,6 +366,11 @@ func bench_read(b *testing.B, cfg BenchmarkConfig, dataSeries telem.Series, chan
 			break
 		}
 	}
+	b.StopTimer()
+
+	if stats := db.BlockCacheStats(); stats.Hits+stats.Misses > 0 {
+		b.ReportMetric(float64(stats.Hits)/float64(stats.Hits+stats.Misses), "hit_ratio")
+	}
 
 	err = db.Close()
 	if err != nil {
@@ -68,6 +68,18 @@ type Channel struct {
 	Concurrency control.Concurrency `json:"concurrency" msgpack:"concurrency"`
 	// Version specifies the format of files stored in this channel.
 	Version version.Version `json:"version" msgpack:"version"`
+	// ColdAfter is the age after which the channel's data is moved to the cold tier of
+	// the database, if one is configured. If zero, the database's default is used.
+	// [OPTIONAL]
+	ColdAfter telem.TimeSpan `json:"cold_after" msgpack:"cold_after"`
+	// Quota is the maximum size of the channel's data files. When it is exceeded, the
+	// channel's oldest data is evicted. Only enforced if the database is configured with
+	// a quota. If zero, the channel has no quota.
+	// [OPTIONAL]
+	Quota telem.Size `json:"quota" msgpack:"quota"`
+	// Pinned specifies whether the channel's data is never evicted to satisfy a quota.
+	// [OPTIONAL]
+	Pinned bool `json:"pinned" msgpack:"pinned"`
 }
 
 func (c Channel) String() string {
@@ -17,6 +17,11 @@ import (
 // ErrChannelNotFound is returned when a channel or a range of data cannot be found in the DB.
 var ErrChannelNotFound = errors.Wrap(query.NotFound, "channel not found")
 
+// ErrQuotaExceeded is returned when data is written to a channel whose storage quota,
+// or the storage quota of the database, is exceeded and evicting old data could not
+// free enough space.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
 func NewErrChannelNotFound(ch ChannelKey) error {
 	return errors.Wrapf(ErrChannelNotFound, "channel %d not found in the database", ch)
 }
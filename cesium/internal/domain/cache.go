@@ -0,0 +1,287 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	xio "github.com/synnaxlabs/x/io"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// BlockCacheConfig is the configuration for a BlockCache.
+type BlockCacheConfig struct {
+	// Size is the maximum total size of the blocks held by the cache.
+	// [REQUIRED]
+	Size telem.Size
+	// BlockSize is the size of the aligned blocks that files are read and cached in.
+	// [OPTIONAL] Default: 64KB
+	BlockSize telem.Size
+	// Shards is the number of independently locked partitions of the cache. Each shard
+	// holds at most Size / Shards bytes of blocks.
+	// [OPTIONAL] Default: 16
+	Shards int
+}
+
+var (
+	_ config.Config[BlockCacheConfig] = BlockCacheConfig{}
+	// DefaultBlockCacheConfig is the default configuration for a BlockCache.
+	DefaultBlockCacheConfig = BlockCacheConfig{BlockSize: 64 * telem.Kilobyte, Shards: 16}
+)
+
+// Validate implements config.Config.
+func (c BlockCacheConfig) Validate() error {
+	v := validate.New("domain.block_cache")
+	validate.Positive(v, "size", c.Size)
+	validate.Positive(v, "block_size", c.BlockSize)
+	validate.Positive(v, "shards", c.Shards)
+	return v.Error()
+}
+
+// Override implements config.Config.
+func (c BlockCacheConfig) Override(other BlockCacheConfig) BlockCacheConfig {
+	c.Size = override.Numeric(c.Size, other.Size)
+	c.BlockSize = override.Numeric(c.BlockSize, other.BlockSize)
+	c.Shards = override.Numeric(c.Shards, other.Shards)
+	return c
+}
+
+// BlockCacheStats is a snapshot of the metrics of a BlockCache.
+type BlockCacheStats struct {
+	// Hits is the number of block reads served from the cache.
+	Hits uint64
+	// Misses is the number of block reads that had to go to the filesystem.
+	Misses uint64
+	// Evictions is the number of blocks evicted to keep the cache under its size.
+	Evictions uint64
+	// Blocks is the number of blocks held by the cache.
+	Blocks int
+	// Size is the total size of the blocks held by the cache.
+	Size telem.Size
+}
+
+// Report implements alamos.ReportProvider.
+func (s BlockCacheStats) Report() alamos.Report {
+	return alamos.Report{
+		"hits":      s.Hits,
+		"misses":    s.Misses,
+		"evictions": s.Evictions,
+		"blocks":    s.Blocks,
+		"size":      s.Size,
+	}
+}
+
+// BlockCache is a bounded, sharded LRU cache of the blocks of domain files. A single
+// cache can be shared by many DBs by setting Config.BlockCache, and is safe for
+// concurrent use.
+//
+// Data files are append only, so the bytes of a cached block never change while the
+// file exists, with two exceptions: the last block of a file grows as data is written
+// to it, and garbage collection rewrites files. A cached block that is shorter than a
+// read requires is reloaded from the file, and garbage collection removes every
+// block of the files it rewrites. Deletions only modify the index of a DB, and never
+// the bytes of its files.
+type BlockCache struct {
+	cfg       BlockCacheConfig
+	shards    []*cacheShard
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// NewBlockCache opens a new BlockCache using the provided configurations.
+func NewBlockCache(cfgs ...BlockCacheConfig) (*BlockCache, error) {
+	cfg, err := config.New(DefaultBlockCacheConfig, cfgs...)
+	if err != nil {
+		return nil, err
+	}
+	c := &BlockCache{cfg: cfg, shards: make([]*cacheShard, cfg.Shards)}
+	for i := range c.shards {
+		c.shards[i] = &cacheShard{
+			capacity: int64(cfg.Size) / int64(cfg.Shards),
+			entries:  make(map[blockKey]*list.Element),
+			lru:      list.New(),
+		}
+	}
+	return c, nil
+}
+
+// Stats returns a snapshot of the metrics of the cache.
+func (c *BlockCache) Stats() BlockCacheStats {
+	s := BlockCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+	for _, sh := range c.shards {
+		sh.mu.Lock()
+		s.Blocks += sh.lru.Len()
+		s.Size += telem.Size(sh.size)
+		sh.mu.Unlock()
+	}
+	return s
+}
+
+// blockKey identifies a block of a file of a DB.
+type blockKey struct {
+	db    uint64
+	file  uint16
+	block int64
+}
+
+type cacheEntry struct {
+	key  blockKey
+	data []byte
+}
+
+type cacheShard struct {
+	mu       sync.Mutex
+	capacity int64
+	size     int64
+	entries  map[blockKey]*list.Element
+	lru      *list.List
+}
+
+func (c *BlockCache) shard(key blockKey) *cacheShard {
+	h := key.db*0x9E3779B97F4A7C15 ^ uint64(key.file)<<48 ^ uint64(key.block)
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	return c.shards[h%uint64(len(c.shards))]
+}
+
+// get returns the cached block with the given key if it holds at least minLen bytes.
+func (c *BlockCache) get(key blockKey, minLen int64) ([]byte, bool) {
+	sh := c.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	el, ok := sh.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if int64(len(e.data)) < minLen {
+		return nil, false
+	}
+	sh.lru.MoveToFront(el)
+	return e.data, true
+}
+
+// put caches the block with the given key, evicting the least recently used blocks of
+// its shard to stay within capacity.
+func (c *BlockCache) put(key blockKey, data []byte) {
+	sh := c.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if int64(len(data)) > sh.capacity {
+		return
+	}
+	if el, ok := sh.entries[key]; ok {
+		sh.remove(el)
+	}
+	sh.entries[key] = sh.lru.PushFront(&cacheEntry{key: key, data: data})
+	sh.size += int64(len(data))
+	for sh.size > sh.capacity {
+		sh.remove(sh.lru.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// invalidate removes every block of the given file of the given DB from the cache.
+func (c *BlockCache) invalidate(db uint64, file uint16) {
+	c.removeWhere(func(k blockKey) bool { return k.db == db && k.file == file })
+}
+
+// purge removes every block of the given DB from the cache.
+func (c *BlockCache) purge(db uint64) {
+	c.removeWhere(func(k blockKey) bool { return k.db == db })
+}
+
+func (c *BlockCache) removeWhere(f func(blockKey) bool) {
+	for _, sh := range c.shards {
+		sh.mu.Lock()
+		for k, el := range sh.entries {
+			if f(k) {
+				sh.remove(el)
+			}
+		}
+		sh.mu.Unlock()
+	}
+}
+
+func (sh *cacheShard) remove(el *list.Element) {
+	e := sh.lru.Remove(el).(*cacheEntry)
+	delete(sh.entries, e.key)
+	sh.size -= int64(len(e.data))
+}
+
+// cachedReader reads a file of a DB through a BlockCache.
+type cachedReader struct {
+	xio.ReaderAtCloser
+	cache *BlockCache
+	db    uint64
+	file  uint16
+}
+
+var _ xio.ReaderAtCloser = (*cachedReader)(nil)
+
+// ReadAt implements io.ReaderAt.
+func (r *cachedReader) ReadAt(p []byte, off int64) (n int, err error) {
+	bs := int64(r.cache.cfg.BlockSize)
+	for n < len(p) {
+		var (
+			pos   = off + int64(n)
+			key   = blockKey{db: r.db, file: r.file, block: pos / bs}
+			start = pos - key.block*bs
+			need  = min(bs, start+int64(len(p)-n))
+		)
+		data, ok := r.cache.get(key, need)
+		if ok {
+			r.cache.hits.Add(1)
+		} else {
+			r.cache.misses.Add(1)
+			if data, err = r.readBlock(key.block * bs); err != nil {
+				return n, err
+			}
+			r.cache.put(key, data)
+		}
+		if start >= int64(len(data)) {
+			return n, io.EOF
+		}
+		n += copy(p[n:], data[start:])
+		if int64(len(data)) < bs && n < len(p) {
+			return n, io.EOF
+		}
+	}
+	return n, nil
+}
+
+// readBlock reads the block starting at the given offset from the file. The block is
+// shorter than the block size if the file ends within it.
+func (r *cachedReader) readBlock(off int64) ([]byte, error) {
+	buf := make([]byte, r.cache.cfg.BlockSize)
+	n, err := r.ReaderAtCloser.ReadAt(buf, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if int64(n) < int64(len(buf)) {
+		return bytes.Clone(buf[:n]), nil
+	}
+	return buf, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Block Cache", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db      *domain.DB
+				cache   *domain.BlockCache
+				fs      xfs.FS
+				cleanUp func() error
+				read    = func(tr telem.TimeRange) []byte {
+					i := db.OpenIterator(domain.IterRange(tr))
+					Expect(i.SeekFirst(ctx)).To(BeTrue())
+					r := MustSucceed(i.OpenReader(ctx))
+					buf := make([]byte, r.Len())
+					MustSucceed(r.ReadAt(buf, 0))
+					Expect(r.Close()).To(Succeed())
+					Expect(i.Close()).To(Succeed())
+					return buf
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				cache = MustSucceed(domain.NewBlockCache(domain.BlockCacheConfig{
+					Size:      64 * telem.ByteSize,
+					BlockSize: 4 * telem.ByteSize,
+					Shards:    2,
+				}))
+				db = MustSucceed(domain.Open(domain.Config{
+					FS:              fs,
+					FileSize:        100 * telem.ByteSize,
+					BlockCache:      cache,
+					Instrumentation: PanicLogger(),
+				}))
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			It("Should serve repeated reads from the cache", func() {
+				tr := (10 * telem.SecondTS).Range(19*telem.SecondTS + 1)
+				Expect(domain.Write(ctx, db, tr, []byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19})).To(Succeed())
+				Expect(read(tr)).To(Equal([]byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}))
+				Expect(cache.Stats()).To(Equal(domain.BlockCacheStats{Misses: 3, Blocks: 3, Size: 10}))
+				Expect(read(tr)).To(Equal([]byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}))
+				Expect(cache.Stats().Hits).To(Equal(uint64(3)))
+				Expect(cache.Stats().Misses).To(Equal(uint64(3)))
+			})
+
+			It("Should reload the last block of a file after it is written to", func() {
+				first := (10 * telem.SecondTS).Range(15*telem.SecondTS + 1)
+				Expect(domain.Write(ctx, db, first, []byte{10, 11, 12, 13, 14, 15})).To(Succeed())
+				Expect(read(first)).To(Equal([]byte{10, 11, 12, 13, 14, 15}))
+				second := (20 * telem.SecondTS).Range(23*telem.SecondTS + 1)
+				Expect(domain.Write(ctx, db, second, []byte{20, 21, 22, 23})).To(Succeed())
+				Expect(read(second)).To(Equal([]byte{20, 21, 22, 23}))
+				Expect(read(first)).To(Equal([]byte{10, 11, 12, 13, 14, 15}))
+			})
+
+			It("Should invalidate the blocks of a file rewritten by garbage collection", func() {
+				tr := (10 * telem.SecondTS).Range(19*telem.SecondTS + 1)
+				Expect(domain.Write(ctx, db, tr, []byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19})).To(Succeed())
+				Expect(read(tr)).To(HaveLen(10))
+				Expect(db.Delete(ctx, createCalcOffset(0), createCalcOffset(4), (10 * telem.SecondTS).Range(14*telem.SecondTS), telem.Density(1))).To(Succeed())
+				Expect(db.Compact(ctx)).To(Succeed())
+				Expect(read(telem.TimeRangeMax)).To(Equal([]byte{14, 15, 16, 17, 18, 19}))
+			})
+
+			It("Should evict the least recently used blocks", func() {
+				data := make([]byte, 80)
+				for i := range data {
+					data[i] = byte(i)
+				}
+				expected := bytes.Clone(data)
+				tr := (10 * telem.SecondTS).Range(89*telem.SecondTS + 1)
+				Expect(domain.Write(ctx, db, tr, data)).To(Succeed())
+				Expect(read(tr)).To(Equal(expected))
+				s := cache.Stats()
+				Expect(s.Evictions).To(BeNumerically(">", 0))
+				Expect(s.Size).To(BeNumerically("<=", 64))
+			})
+
+			It("Should remove the blocks of a DB when it is closed", func() {
+				tr := (10 * telem.SecondTS).Range(13*telem.SecondTS + 1)
+				Expect(domain.Write(ctx, db, tr, []byte{10, 11, 12, 13})).To(Succeed())
+				Expect(read(tr)).To(HaveLen(4))
+				Expect(db.Close()).To(Succeed())
+				Expect(cache.Stats().Blocks).To(BeZero())
+			})
+
+			It("Should not create a cache without a size", func() {
+				Expect(domain.NewBlockCache(domain.BlockCacheConfig{})).Error().
+					To(HaveOccurredAs(validate.FieldError{Field: "size", Message: "must be positive"}))
+			})
+		})
+	}
+})
@@ -22,6 +22,7 @@ import (
 	"github.com/synnaxlabs/x/telem"
 	"github.com/synnaxlabs/x/validate"
 	"math"
+	"sync"
 	"sync/atomic"
 )
 
@@ -75,8 +76,16 @@ type DB struct {
 	fc          *fileController
 	closed      *atomic.Bool
 	entityCount *atomic.Int64
+	// gcLock is held for reading by open snapshots and for writing by garbage
+	// collection, ensuring that files are never rewritten while being copied.
+	gcLock sync.RWMutex
+	// id uniquely identifies the DB's files in the block cache.
+	id uint64
 }
 
+// dbCounter is used to assign a unique id to each DB.
+var dbCounter atomic.Uint64
+
 // Config is the configuration for opening a DB.
 type Config struct {
 	alamos.Instrumentation
@@ -85,6 +94,12 @@ type Config struct {
 	// exclusive access, and it should be empty when the DB is first opened.
 	// [REQUIRED]
 	FS xfs.FS
+	// ColdFS is a secondary filesystem that MoveToColdTier moves files to once all of
+	// their data is older than a threshold. Files in the cold tier remain readable,
+	// and the DB keeps track of which filesystem each file is stored in. As with FS,
+	// the DB should have exclusive access to ColdFS.
+	// [OPTIONAL]
+	ColdFS xfs.FS
 	// FileSize is the maximum size, in bytes, for a writer to be created on a file.
 	// Note while that a file's size may still exceed this value, it is not likely
 	// to exceed by much with frequent commits.
@@ -101,6 +116,20 @@ type Config struct {
 	// that the exact performance impact of changing this value is still relatively unknown.
 	// [OPTIONAL] Default: 100
 	MaxDescriptors int
+	// Variable sets whether the DB stores variable density, newline-delimited samples.
+	// If true, the DB maintains a sample offset table for each of its files, allowing
+	// samples to be located by their index within a domain.
+	// [OPTIONAL] Default: false
+	Variable bool
+	// DataType is the data type of the samples stored in the DB. If the data type is
+	// numeric, the DB maintains a Summary of the samples in each of its domains, which
+	// can be accessed through Iterator.Summary.
+	// [OPTIONAL]
+	DataType telem.DataType
+	// BlockCache caches the blocks of the DB's files as they are read. The cache can be
+	// shared between many DBs.
+	// [OPTIONAL] Default: no cache
+	BlockCache *BlockCache
 }
 
 var (
@@ -129,8 +158,12 @@ func (c Config) Override(other Config) Config {
 	c.MaxDescriptors = override.Numeric(c.MaxDescriptors, other.MaxDescriptors)
 	c.FileSize = override.Numeric(c.FileSize, other.FileSize)
 	c.FS = override.Nil(c.FS, other.FS)
+	c.ColdFS = override.Nil(c.ColdFS, other.ColdFS)
 	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
 	c.GCThreshold = override.Numeric(c.GCThreshold, other.GCThreshold)
+	c.Variable = override.If(c.Variable, other.Variable, other.Variable)
+	c.DataType = override.String(c.DataType, other.DataType)
+	c.BlockCache = override.Nil(c.BlockCache, other.BlockCache)
 	// Store 0.8 * the desired maximum file size as file size since we must leave some
 	// buffer for when we stop acquiring a new writer on a file.
 	c.FileSize = telem.Size(math.Round(0.8 * float64(c.FileSize)))
@@ -145,7 +178,7 @@ func Open(configs ...Config) (*DB, error) {
 		return nil, err
 	}
 	idx := &index{}
-	idxPst, err := openIndexPersist(idx, cfg.FS)
+	idxPst, err := openIndexPersist(idx, cfg.FS, Summarizable(cfg.DataType))
 	if err != nil {
 		return nil, err
 	}
@@ -158,12 +191,18 @@ func Open(configs ...Config) (*DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.Variable {
+		if err = controller.rebuildOffsetTables(); err != nil {
+			return nil, err
+		}
+	}
 	return &DB{
 		cfg:         cfg,
 		idx:         idx,
 		fc:          controller,
 		closed:      &atomic.Bool{},
 		entityCount: &atomic.Int64{},
+		id:          dbCounter.Add(1),
 	}, nil
 }
 
@@ -172,7 +211,16 @@ func (db *DB) newReader(ctx context.Context, ptr pointer) (*Reader, error) {
 	if err != nil {
 		return nil, err
 	}
-	reader := xio.NewSectionReaderAtCloser(internal, int64(ptr.offset), int64(ptr.length))
+	var file xio.ReaderAtCloser = internal
+	if db.cfg.BlockCache != nil {
+		file = &cachedReader{
+			ReaderAtCloser: internal,
+			cache:          db.cfg.BlockCache,
+			db:             db.id,
+			file:           ptr.fileKey,
+		}
+	}
+	reader := xio.NewSectionReaderAtCloser(file, int64(ptr.offset), int64(ptr.length))
 	return &Reader{ptr: ptr, ReaderAtCloser: reader}, nil
 }
 
@@ -191,6 +239,69 @@ func (db *DB) HasDataFor(ctx context.Context, tr telem.TimeRange) (bool, error)
 	return false, i.Close()
 }
 
+// Size returns the total size of the data files of the DB across both storage tiers.
+// This includes the space occupied by deleted data that has not yet been garbage
+// collected.
+func (db *DB) Size() (telem.Size, error) {
+	if db.closed.Load() {
+		return 0, errDBClosed
+	}
+	// Files are renamed while they are garbage collected or moved between tiers.
+	db.gcLock.RLock()
+	defer db.gcLock.RUnlock()
+	var size telem.Size
+	for fileKey := uint16(1); fileKey <= uint16(db.fc.counter.Value()); fileKey++ {
+		s, err := db.fc.fsFor(fileKey).Stat(fileKeyToName(fileKey))
+		if err != nil {
+			return 0, err
+		}
+		size += telem.Size(s.Size())
+	}
+	return size, nil
+}
+
+// Usage is the disk usage of a DB.
+type Usage struct {
+	// Size is the total size of the data files of the DB across both storage tiers.
+	Size telem.Size
+	// Files is the number of data files of the DB.
+	Files int
+	// Reclaimable is the space occupied by deleted data that garbage collection can
+	// return to the filesystem.
+	Reclaimable telem.Size
+}
+
+// Usage returns the disk usage of the DB, including the space occupied by deleted data
+// that has not yet been garbage collected.
+func (db *DB) Usage() (Usage, error) {
+	if db.closed.Load() {
+		return Usage{}, errDBClosed
+	}
+	db.gcLock.RLock()
+	defer db.gcLock.RUnlock()
+	var (
+		usage = Usage{Files: int(db.fc.counter.Value())}
+		live  = make(map[uint16]telem.Size, usage.Files)
+	)
+	db.idx.mu.RLock()
+	for _, ptr := range db.idx.mu.pointers {
+		live[ptr.fileKey] += telem.Size(ptr.length)
+	}
+	db.idx.mu.RUnlock()
+	for fileKey := uint16(1); fileKey <= uint16(usage.Files); fileKey++ {
+		s, err := db.fc.fsFor(fileKey).Stat(fileKeyToName(fileKey))
+		if err != nil {
+			return Usage{}, err
+		}
+		size := telem.Size(s.Size())
+		usage.Size += size
+		if size > live[fileKey] {
+			usage.Reclaimable += size - live[fileKey]
+		}
+	}
+	return usage, nil
+}
+
 // Close closes the DB. Close should not be called concurrently with any other DB methods.
 // If close fails for a reason other than unclosed writers/readers, the database will
 // still be marked closed and no read/write operations are allowed on it to protect
@@ -205,6 +316,9 @@ func (db *DB) Close() error {
 		db.closed.Store(false)
 		return err
 	}
+	if db.cfg.BlockCache != nil {
+		db.cfg.BlockCache.purge(db.id)
+	}
 	w := errors.NewCatcher(errors.WithAggregation())
 	w.Exec(db.fc.close)
 	w.Exec(db.idx.close)
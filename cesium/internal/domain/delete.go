@@ -12,10 +12,20 @@ package domain
 import (
 	"context"
 	"github.com/cockroachdb/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
 	"github.com/synnaxlabs/x/telem"
 	"os"
 )
 
+// OffsetCalculator calculates the number of samples between a domain's start and the
+// given timestamp, "snapping" the timestamp to the nearest sample. It returns the
+// calculated offset, the snapped timestamp, and any errors encountered.
+type OffsetCalculator = func(
+	ctx context.Context,
+	domainStart telem.TimeStamp,
+	ts telem.TimeStamp,
+) (int64, telem.TimeStamp, error)
+
 // Delete adds all pointers ranging from
 // [db.get(startPosition).offset + startOffset, db.get(endPosition).offset + length - endOffset)
 // into tombstone.
@@ -28,16 +38,8 @@ import (
 // positions in the index.
 func (db *DB) Delete(
 	ctx context.Context,
-	calculateStartOffset func(
-		ctx context.Context,
-		domainStart telem.TimeStamp,
-		ts telem.TimeStamp,
-	) (int64, telem.TimeStamp, error),
-	calculateEndOffset func(
-		ctx context.Context,
-		domainStart telem.TimeStamp,
-		ts telem.TimeStamp,
-	) (int64, telem.TimeStamp, error),
+	calculateStartOffset OffsetCalculator,
+	calculateEndOffset OffsetCalculator,
 	tr telem.TimeRange,
 	den telem.Density,
 ) (err error) {
@@ -58,136 +60,210 @@ func (db *DB) Delete(
 	db.idx.deleteLock.Lock()
 	defer db.idx.deleteLock.Unlock()
 
-	var (
-		startPosition, endPosition int
-		startOffset, endOffset     int64
-		start, end                 pointer
-		newPointers                = make([]pointer, 0)
-	)
+	d, ok, err := db.resolveDeletion(ctx, calculateStartOffset, calculateEndOffset, tr, den)
+	if err != nil || !ok {
+		return span.Error(err)
+	}
+
+	db.idx.mu.Lock()
+	defer db.idx.mu.Unlock()
+
+	startPosition, ok, err := db.idx.unprotectedDelete(d)
+	if err != nil || !ok {
+		return span.Error(err)
+	}
+
+	persist := db.idx.indexPersist.prepare(startPosition)
+	// We choose to keep the mutex locked while persisting to index.
+	return span.Error(persist())
+}
+
+// deletion describes the pointers and offsets affected by deleting a time range from the
+// DB, as resolved by resolveDeletion.
+type deletion struct {
+	// tr is the deleted time range, snapped to the nearest samples.
+	tr                         telem.TimeRange
+	start, end                 pointer
+	startPosition, endPosition int
+	// startOffset is the number of bytes to keep at the start of the start pointer, and
+	// endOffset is the number of bytes to keep at the end of the end pointer.
+	startOffset, endOffset int64
+}
+
+// resolveDeletion resolves the pointers and offsets affected by deleting the given
+// time range from the DB. resolveDeletion returns false if the time range does not
+// overlap with any domains. The caller must hold idx.deleteLock, and must not hold
+// idx.mu, as the offset calculators may need to read from the DB.
+func (db *DB) resolveDeletion(
+	ctx context.Context,
+	calculateStartOffset OffsetCalculator,
+	calculateEndOffset OffsetCalculator,
+	tr telem.TimeRange,
+	den telem.Density,
+) (d deletion, ok bool, err error) {
+	var exact bool
 
 	// Search for the start position: the first domain greater or containing tr.Start.
 	db.idx.mu.RLock()
-	startPosition, exact := db.idx.unprotectedSearch(tr.Start.SpanRange(0))
+	d.startPosition, exact = db.idx.unprotectedSearch(tr.Start.SpanRange(0))
 	if exact {
-		start = db.idx.mu.pointers[startPosition]
+		d.start = db.idx.mu.pointers[d.startPosition]
 		db.idx.mu.RUnlock()
-		startOffset, tr.Start, err = calculateStartOffset(ctx, start.Start, tr.Start)
+		d.startOffset, tr.Start, err = calculateStartOffset(ctx, d.start.Start, tr.Start)
 		if err != nil {
 			return
 		}
-		startOffset = int64(den.Size(startOffset))
+		if d.startOffset, err = db.sampleSize(d.start, d.startOffset, den); err != nil {
+			return
+		}
 	} else {
 		// Non-exact: tr.Start is not contained within any domain.
 		// Add 1 since we want the first domain greater than tr.Start.
-		startPosition += 1
+		d.startPosition += 1
 
-		if startPosition == len(db.idx.mu.pointers) {
+		if d.startPosition == len(db.idx.mu.pointers) {
 			// delete nothing
 			db.idx.mu.RUnlock()
 			return
 		}
 
-		start = db.idx.mu.pointers[startPosition]
+		d.start = db.idx.mu.pointers[d.startPosition]
 		db.idx.mu.RUnlock()
-		startOffset = 0
-		tr.Start = start.Start
+		d.startOffset = 0
+		tr.Start = d.start.Start
 	}
 
 	// Search for the end position: the first domain less or containing tr.End.
 	db.idx.mu.RLock()
-	endPosition, exact = db.idx.unprotectedSearch(tr.End.SpanRange(0))
+	d.endPosition, exact = db.idx.unprotectedSearch(tr.End.SpanRange(0))
 	if exact {
-		end = db.idx.mu.pointers[endPosition]
+		d.end = db.idx.mu.pointers[d.endPosition]
 		db.idx.mu.RUnlock()
-		endOffset, tr.End, err = calculateEndOffset(ctx, end.Start, tr.End)
+		d.endOffset, tr.End, err = calculateEndOffset(ctx, d.end.Start, tr.End)
 		if err != nil {
 			return
 		}
-		endOffset = int64(end.length) - int64(den.Size(endOffset))
+		if d.endOffset, err = db.sampleSize(d.end, d.endOffset, den); err != nil {
+			return
+		}
+		d.endOffset = int64(d.end.length) - d.endOffset
 	} else {
 		// Non-exact: tr.End is not contained within any domain.
-		if endPosition == -1 {
+		if d.endPosition == -1 {
 			// delete nothing
 			db.idx.mu.RUnlock()
 			return
 		}
 
-		end = db.idx.mu.pointers[endPosition]
+		d.end = db.idx.mu.pointers[d.endPosition]
 		db.idx.mu.RUnlock()
-		endOffset = 0
-		tr.End = end.End
+		d.endOffset = 0
+		tr.End = d.end.End
 	}
+	d.tr = tr
+	return d, true, nil
+}
 
-	db.idx.mu.Lock()
-	defer db.idx.mu.Unlock()
-
+// unprotectedDelete removes the resolved deletion from the index, returning the
+// position of the first pointer modified. unprotectedDelete returns false if there
+// is nothing to delete. The caller must hold idx.mu.
+func (idx *index) unprotectedDelete(d deletion) (int, bool, error) {
+	var (
+		exact       bool
+		newPointers = make([]pointer, 0)
+	)
 	// Repêchage: the location of start/end may have changed during the index lookup.
-	if db.idx.mu.pointers[startPosition] != start {
-		startPosition, exact = db.idx.unprotectedSearch(start.TimeRange)
+	if idx.mu.pointers[d.startPosition] != d.start {
+		d.startPosition, exact = idx.unprotectedSearch(d.start.TimeRange)
 		// Edge cases such as startPosition is after the end must have been already
 		// handled before: a time range that existed in the domain before must not cease
 		// to exist.
 		if !exact {
-			startPosition += 1
+			d.startPosition += 1
 		}
 	}
-	if db.idx.mu.pointers[endPosition] != end {
-		endPosition, _ = db.idx.unprotectedSearch(end.TimeRange)
+	if idx.mu.pointers[d.endPosition] != d.end {
+		d.endPosition, _ = idx.unprotectedSearch(d.end.TimeRange)
 	}
 
-	err, ok := validateDelete(startPosition, endPosition, &startOffset, &endOffset, db.idx)
+	err, ok := validateDelete(d.startPosition, d.endPosition, &d.startOffset, &d.endOffset, idx)
 	if err != nil || !ok {
-		return span.Error(err)
+		return 0, false, err
 	}
 
 	// Remove old pointers.
-	db.idx.mu.pointers = append(db.idx.mu.pointers[:startPosition], db.idx.mu.pointers[endPosition+1:]...)
+	idx.mu.pointers = append(idx.mu.pointers[:d.startPosition], idx.mu.pointers[d.endPosition+1:]...)
 
-	if startOffset != 0 {
-		newPointers = append(newPointers, pointer{
-			TimeRange: telem.TimeRange{Start: start.Start, End: tr.Start},
-			fileKey:   start.fileKey,
-			offset:    start.offset,
-			length:    uint32(startOffset), // length from start.Start to tr.Start
-		})
+	if d.startOffset != 0 {
+		newPointers = append(newPointers, keepSummary(d.start, pointer{
+			TimeRange: telem.TimeRange{Start: d.start.Start, End: d.tr.Start},
+			fileKey:   d.start.fileKey,
+			offset:    d.start.offset,
+			length:    uint32(d.startOffset), // length from start.Start to tr.Start
+		}))
 	}
 
-	if endOffset != 0 {
-		newPointers = append(newPointers, pointer{
-			TimeRange: telem.TimeRange{Start: tr.End, End: end.End},
-			fileKey:   end.fileKey,
-			offset:    end.offset + end.length - uint32(endOffset),
-			length:    uint32(endOffset), // length from tr.End to end.End
-		})
+	if d.endOffset != 0 {
+		newPointers = append(newPointers, keepSummary(d.end, pointer{
+			TimeRange: telem.TimeRange{Start: d.tr.End, End: d.end.End},
+			fileKey:   d.end.fileKey,
+			offset:    d.end.offset + d.end.length - uint32(d.endOffset),
+			length:    uint32(d.endOffset), // length from tr.End to end.End
+		}))
 	}
 
 	if len(newPointers) != 0 {
-		db.idx.mu.pointers = append(
-			db.idx.mu.pointers[:startPosition],
-			append(newPointers, db.idx.mu.pointers[startPosition:]...)...,
+		idx.mu.pointers = append(
+			idx.mu.pointers[:d.startPosition],
+			append(newPointers, idx.mu.pointers[d.startPosition:]...)...,
 		)
 	}
+	return d.startPosition, true, nil
+}
 
-	persist := db.idx.indexPersist.prepare(startPosition)
-	// We choose to keep the mutex locked while persisting to index.
-	return span.Error(persist())
+// keepSummary carries the summary of the original pointer over to the pointer that
+// replaces it after a deletion, as long as the deletion left the domain intact.
+func keepSummary(original, replacement pointer) pointer {
+	if replacement.TimeRange == original.TimeRange && replacement.length == original.length {
+		replacement.summary = original.summary
+	}
+	return replacement
 }
 
 // GarbageCollect rewrites all files that are over the size limit of a file and has
 // enough tombstones to garbage collect, as defined by GCThreshold.
 func (db *DB) GarbageCollect(ctx context.Context) error {
-	ctx, span := db.cfg.T.Bench(ctx, "garbage_collect")
+	_, span := db.cfg.T.Bench(ctx, "garbage_collect")
+	defer span.End()
+	return span.Error(db.garbageCollect(false))
+}
+
+// Compact rewrites every file that contains tombstones, regardless of its size or the
+// GCThreshold, so that the space occupied by deleted data is returned to the
+// filesystem. Files that have a writer or reader in use are skipped.
+func (db *DB) Compact(ctx context.Context) error {
+	_, span := db.cfg.T.Bench(ctx, "compact")
 	defer span.End()
+	return span.Error(db.garbageCollect(true))
+}
 
+// garbageCollect rewrites the files of the DB to remove their tombstones. If force is
+// false, only full files with enough tombstones to pass the GCThreshold are rewritten.
+// Otherwise, every file with tombstones is rewritten.
+func (db *DB) garbageCollect(force bool) error {
 	if db.closed.Load() {
 		return errDBClosed
 	}
 	db.entityCount.Add(1)
 	defer db.entityCount.Add(-1)
 
+	// Wait for any open snapshots to finish copying before rewriting files.
+	db.gcLock.Lock()
+	defer db.gcLock.Unlock()
+
 	_, err := db.fc.gcWriters()
 	if err != nil {
-		return span.Error(err)
+		return err
 	}
 
 	// There also cannot be any readers open on the file, since any iterators that
@@ -214,23 +290,26 @@ func (db *DB) GarbageCollect(ctx context.Context) error {
 	// be problematic since some readers may never get closed).
 	_, err = db.fc.gcReaders()
 	if err != nil {
-		return span.Error(err)
+		return err
 	}
 
+	threshold := int64(db.cfg.GCThreshold * float32(db.cfg.FileSize))
 	for fileKey := uint16(1); fileKey <= uint16(db.fc.counter.Value()); fileKey++ {
-		if db.fc.hasWriter(fileKey) {
+		// Compaction closes idle writers on the files it rewrites.
+		if !force && db.fc.hasWriter(fileKey) {
 			continue
 		}
-		s, err := db.cfg.FS.Stat(fileKeyToName(fileKey))
+		s, err := db.fc.fsFor(fileKey).Stat(fileKeyToName(fileKey))
 		if err != nil {
-			return span.Error(err)
+			return err
 		}
-		if s.Size() < int64(db.cfg.FileSize) {
-			continue
+		if force {
+			err = db.compactFile(fileKey, s.Size())
+		} else if s.Size() >= int64(db.cfg.FileSize) {
+			err = db.garbageCollectFile(fileKey, s.Size(), threshold)
 		}
-
-		if err = db.garbageCollectFile(fileKey, s.Size()); err != nil {
-			return span.Error(err)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -243,7 +322,22 @@ func (db *DB) GarbageCollect(ctx context.Context) error {
 	return persist()
 }
 
-func (db *DB) garbageCollectFile(key uint16, size int64) error {
+// compactFile garbage collects a file that may not be full. Any idle writer on the
+// file is closed and new writers are prevented from acquiring the file while it is
+// rewritten. The file is made available to writers again afterward. Files with a
+// writer in use are skipped.
+func (db *DB) compactFile(key uint16, size int64) (err error) {
+	ok, err := db.fc.retireWriter(key)
+	if !ok || err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, db.fc.rejuvenate(key)) }()
+	return db.garbageCollectFile(key, size, 1)
+}
+
+// garbageCollectFile rewrites the file with the given key without its tombstones, as
+// long as the tombstones occupy at least minTombstoneSize bytes.
+func (db *DB) garbageCollectFile(key uint16, size, minTombstoneSize int64) error {
 	var (
 		name                 = fileKeyToName(key)
 		copyName             = name + "_gc"
@@ -254,6 +348,9 @@ func (db *DB) garbageCollectFile(key uint16, size int64) error {
 		// between its new offset and its old offset. Note that time ranges are
 		// necessarily unique within a domain.
 		offsetDeltaMap = make(map[telem.TimeRange]uint32)
+		// The file is rewritten in the tier it is stored in. The tier cannot change
+		// during GC, since moving files to the cold tier also requires the GC lock.
+		fs = db.fc.fsFor(key)
 	)
 
 	db.fc.readers.RLock()
@@ -286,18 +383,18 @@ func (db *DB) garbageCollectFile(key uint16, size int64) error {
 	db.idx.mu.RUnlock()
 
 	// Decide whether we should GC
-	if tombstoneSize < int64(db.cfg.GCThreshold*float32(db.cfg.FileSize)) {
+	if tombstoneSize < minTombstoneSize {
 		return nil
 	}
 
 	// Open a reader on the old file.
-	r, err := db.cfg.FS.Open(name, os.O_RDONLY)
+	r, err := fs.Open(name, os.O_RDONLY)
 	if err != nil {
 		return err
 	}
 
 	// Open a writer to the copy file.
-	w, err := db.cfg.FS.Open(copyName, os.O_WRONLY|os.O_CREATE)
+	w, err := fs.Open(copyName, os.O_WRONLY|os.O_CREATE)
 	if err != nil {
 		return err
 	}
@@ -329,6 +426,12 @@ func (db *DB) garbageCollectFile(key uint16, size int64) error {
 		return err
 	}
 
+	if db.cfg.Variable {
+		if err = db.garbageCollectOffsetTable(fs, copyName, offsetTableName(key)+"_gc"); err != nil {
+			return err
+		}
+	}
+
 	// Update the file and index while holding the mutex lock.
 	// Note: the index might be different at this point than before: old pointers on
 	// this file may be split into multiple smaller pointers with different offsets.
@@ -351,21 +454,57 @@ func (db *DB) garbageCollectFile(key uint16, size int64) error {
 		}
 	}
 
-	if err = db.cfg.FS.Rename(name, name+"_temp"); err != nil {
+	if err = fs.Rename(name, name+"_temp"); err != nil {
 		db.idx.mu.Unlock()
 		return err
 	}
-	if err = db.cfg.FS.Rename(copyName, name); err != nil {
+	if err = fs.Rename(copyName, name); err != nil {
 		db.idx.mu.Unlock()
 		return err
 	}
+	if db.cfg.Variable {
+		tableName := offsetTableName(key)
+		if err = db.cfg.FS.Rename(tableName, tableName+"_temp"); err != nil {
+			db.idx.mu.Unlock()
+			return err
+		}
+		if err = db.cfg.FS.Rename(tableName+"_gc", tableName); err != nil {
+			db.idx.mu.Unlock()
+			return err
+		}
+		db.fc.offsetTableEpoch.Add(1)
+	}
+	// No readers can be acquired on the file until the readers lock is released, so
+	// the blocks of the old file cannot be cached again.
+	if db.cfg.BlockCache != nil {
+		db.cfg.BlockCache.invalidate(db.id, key)
+	}
 	db.idx.mu.Unlock()
 
 	if err = db.fc.rejuvenate(key); err != nil {
 		return err
 	}
 
-	return db.cfg.FS.Remove(name + "_temp")
+	if db.cfg.Variable {
+		if err = db.cfg.FS.Remove(offsetTableName(key) + "_temp"); err != nil {
+			return err
+		}
+	}
+	return fs.Remove(name + "_temp")
+}
+
+// garbageCollectOffsetTable writes the sample offset table for a garbage collected copy
+// of a data file. Since the copy only contains the domains that remain in the index,
+// which always start and end at sample boundaries, the table can be built by scanning
+// the copy from the beginning. The copy is read from the given filesystem, while the
+// table is always written to the hot tier.
+func (db *DB) garbageCollectOffsetTable(fs xfs.FS, copyName, tableName string) (err error) {
+	r, err := fs.Open(copyName, os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, r.Close()) }()
+	return writeOffsetTable(db.cfg.FS, tableName, r)
 }
 
 func resolvePointerOffset(ptrRange telem.TimeRange, offsetDeltaMap map[telem.TimeRange]uint32) (uint32, bool) {
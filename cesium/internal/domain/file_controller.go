@@ -54,9 +54,18 @@ type fileController struct {
 		sync.RWMutex
 		files map[uint16]*fileReaders
 	}
+	// cold is the set of keys of files that are stored in ColdFS instead of FS. When
+	// both are held, cold must be locked before readers.
+	cold struct {
+		sync.RWMutex
+		keys map[uint16]struct{}
+	}
 	release     chan struct{}
 	counter     *xio.Int32Counter
 	counterFile io.Closer
+	// offsetTableEpoch is incremented whenever garbage collection replaces the sample
+	// offset table of a file, invalidating any offsetTable opened before it.
+	offsetTableEpoch atomic.Uint64
 }
 
 const counterFile = "counter" + extension
@@ -79,6 +88,9 @@ func openFileController(cfg Config) (*fileController, error) {
 	fc.readers.files = make(map[uint16]*fileReaders)
 	fc.release = make(chan struct{}, cfg.MaxDescriptors)
 
+	if fc.cold.keys, err = fc.scanColdFiles(); err != nil {
+		return nil, err
+	}
 	fc.writers.unopened, err = fc.scanUnopenedFiles()
 	return fc, err
 }
@@ -299,7 +311,11 @@ func (fc *fileController) acquireReader(ctx context.Context, key uint16) (*contr
 func (fc *fileController) newReader(ctx context.Context, key uint16) (*controlledReader, error) {
 	ctx, span := fc.T.Bench(ctx, "newReader")
 	defer span.End()
-	file, err := fc.FS.Open(
+	// Hold the cold lock until the reader is registered, so that the file cannot be
+	// moved to the cold tier in between.
+	fc.cold.RLock()
+	defer fc.cold.RUnlock()
+	file, err := fc.tierFS(key).Open(
 		fileKeyToName(key),
 		os.O_RDONLY,
 	)
@@ -400,6 +416,10 @@ func (fc *fileController) rejuvenate(fileKey uint16) error {
 		delete(fc.writers.open, fileKey)
 	}
 
+	// Files in the cold tier are never written to again.
+	if fc.isCold(fileKey) {
+		return nil
+	}
 	s, err := fc.FS.Stat(fileKeyToName(fileKey))
 	if err != nil {
 		return err
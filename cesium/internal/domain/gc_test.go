@@ -48,10 +48,14 @@ var _ = Describe("Garbage Collection", Ordered, func() {
 					Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(19*telem.SecondTS+1), []byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19})).To(Succeed())
 					Expect(db.Delete(ctx, createCalcOffset(3), createCalcOffset(7), telem.TimeRange{Start: 12*telem.SecondTS + 1, End: 16*telem.SecondTS + 1}, telem.Density(1))).To(Succeed())
 
+					By("Reporting the deleted data as reclaimable")
+					Expect(db.Usage()).To(Equal(domain.Usage{Size: 10, Files: 2, Reclaimable: 4}))
+
 					By("Garbage collecting and asserting the file got smaller")
 					Expect(MustSucceed(fs.Stat("1.domain")).Size()).To(Equal(int64(10)))
 					Expect(db.GarbageCollect(ctx)).To(Succeed())
 					Expect(MustSucceed(fs.Stat("1.domain")).Size()).To(Equal(int64(6)))
+					Expect(db.Usage()).To(Equal(domain.Usage{Size: 6, Files: 2, Reclaimable: 0}))
 
 					By("Asserting that we can still write to the file")
 					Expect(domain.Write(ctx, db, (20 * telem.SecondTS).Range(28*telem.SecondTS+1), []byte{20, 21, 22, 23, 24, 25, 26, 27, 28})).To(Succeed())
@@ -552,6 +556,62 @@ var _ = Describe("Garbage Collection", Ordered, func() {
 				})
 			})
 
+			Context("Compact", func() {
+				It("Should rewrite a file that is not full", func() {
+					db = MustSucceed(domain.Open(domain.Config{
+						FS:              fs,
+						FileSize:        100 * telem.ByteSize,
+						GCThreshold:     0.5,
+						Instrumentation: PanicLogger(),
+					}))
+					Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(19*telem.SecondTS+1), []byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19})).To(Succeed())
+					Expect(db.Delete(ctx, createCalcOffset(0), createCalcOffset(4), (10 * telem.SecondTS).Range(14*telem.SecondTS), telem.Density(1))).To(Succeed())
+
+					By("Asserting that garbage collection does not rewrite the file")
+					Expect(db.GarbageCollect(ctx)).To(Succeed())
+					Expect(db.Size()).To(Equal(10 * telem.ByteSize))
+
+					By("Compacting the file")
+					Expect(db.Compact(ctx)).To(Succeed())
+					Expect(db.Size()).To(Equal(6 * telem.ByteSize))
+					Expect(MustSucceed(fs.Stat("1.domain")).Size()).To(Equal(int64(6)))
+
+					By("Asserting that the data is correct")
+					i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+					Expect(i.SeekFirst(ctx)).To(BeTrue())
+					Expect(i.TimeRange()).To(Equal((14 * telem.SecondTS).Range(19*telem.SecondTS + 1)))
+					r := MustSucceed(i.OpenReader(ctx))
+					buf := make([]byte, 6)
+					MustSucceed(r.ReadAt(buf, 0))
+					Expect(buf).To(Equal([]byte{14, 15, 16, 17, 18, 19}))
+					Expect(r.Close()).To(Succeed())
+					Expect(i.Close()).To(Succeed())
+
+					By("Asserting that we can still write to the file")
+					Expect(domain.Write(ctx, db, (20 * telem.SecondTS).Range(22*telem.SecondTS+1), []byte{20, 21, 22})).To(Succeed())
+					Expect(db.Size()).To(Equal(9 * telem.ByteSize))
+				})
+
+				It("Should not rewrite a file with an open reader", func() {
+					db = MustSucceed(domain.Open(domain.Config{
+						FS:              fs,
+						FileSize:        100 * telem.ByteSize,
+						Instrumentation: PanicLogger(),
+					}))
+					Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(19*telem.SecondTS+1), []byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19})).To(Succeed())
+					Expect(db.Delete(ctx, createCalcOffset(0), createCalcOffset(4), (10 * telem.SecondTS).Range(14*telem.SecondTS), telem.Density(1))).To(Succeed())
+					i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+					Expect(i.SeekFirst(ctx)).To(BeTrue())
+					r := MustSucceed(i.OpenReader(ctx))
+					Expect(db.Compact(ctx)).To(Succeed())
+					Expect(db.Size()).To(Equal(10 * telem.ByteSize))
+					Expect(r.Close()).To(Succeed())
+					Expect(i.Close()).To(Succeed())
+					Expect(db.Compact(ctx)).To(Succeed())
+					Expect(db.Size()).To(Equal(6 * telem.ByteSize))
+				})
+			})
+
 			Context("Close", func() {
 				It("Should not allow GC on a closed DB", func() {
 					db = MustSucceed(domain.Open(domain.Config{
@@ -35,12 +35,28 @@ func (idx *index) insert(ctx context.Context, p pointer, persist bool) error {
 
 	defer span.End()
 
+	if err := idx.unprotectedInsert(p); err != nil {
+		idx.mu.Unlock()
+		return span.Error(err)
+	}
+
+	if persist {
+		persistPointers := idx.indexPersist.prepare(idx.persistHead)
+		idx.mu.Unlock()
+		return persistPointers()
+	}
+
+	idx.mu.Unlock()
+	return nil
+}
+
+// unprotectedInsert adds a new pointer to the index. The caller must hold idx.mu.
+func (idx *index) unprotectedInsert(p pointer) error {
 	insertAt := 0
 
 	if p.fileKey == 0 {
 		idx.L.DPanic("fileKey must be set")
-		idx.mu.Unlock()
-		return span.Error(errors.New("inserted pointer cannot have key 0"))
+		return errors.New("inserted pointer cannot have key 0")
 	}
 	if len(idx.mu.pointers) != 0 {
 		// Hot path optimization for appending to the end of the index.
@@ -49,8 +65,7 @@ func (idx *index) insert(ctx context.Context, p pointer, persist bool) error {
 		} else if !idx.beforeFirst(p.End) {
 			i, overlap := idx.unprotectedSearch(p.TimeRange)
 			if overlap {
-				idx.mu.Unlock()
-				return span.Error(NewErrWriteConflict(p.TimeRange, idx.mu.pointers[i].TimeRange))
+				return NewErrWriteConflict(p.TimeRange, idx.mu.pointers[i].TimeRange)
 			}
 			insertAt = i + 1
 		}
@@ -65,14 +80,6 @@ func (idx *index) insert(ctx context.Context, p pointer, persist bool) error {
 	}
 
 	idx.persistHead = min(idx.persistHead, insertAt)
-
-	if persist {
-		persistPointers := idx.indexPersist.prepare(idx.persistHead)
-		idx.mu.Unlock()
-		return persistPointers()
-	}
-
-	idx.mu.Unlock()
 	return nil
 }
 
@@ -98,11 +105,28 @@ func (idx *index) update(ctx context.Context, p pointer, persist bool) error {
 
 	defer span.End()
 
+	if err := idx.unprotectedUpdate(p); err != nil {
+		idx.mu.Unlock()
+		return span.Error(err)
+	}
+
+	if persist {
+		persistPointers := idx.indexPersist.prepare(idx.persistHead)
+		idx.mu.Unlock()
+		return persistPointers()
+	}
+
+	idx.mu.Unlock()
+	return nil
+}
+
+// unprotectedUpdate replaces the pointer in the index that has the same start
+// timestamp as p. The caller must hold idx.mu.
+func (idx *index) unprotectedUpdate(p pointer) error {
 	if len(idx.mu.pointers) == 0 {
 		// This should be inconceivable since update would not be called with no pointers.
 		idx.L.DPanic("cannot update a database with no domains")
-		idx.mu.Unlock()
-		return span.Error(NewErrRangeNotFound(p.TimeRange))
+		return NewErrRangeNotFound(p.TimeRange)
 	}
 	lastI := len(idx.mu.pointers) - 1
 	updateAt := lastI
@@ -118,30 +142,17 @@ func (idx *index) update(ctx context.Context, p pointer, persist bool) error {
 		// must have the same Start timestamp. Unhandled race conditions might cause the
 		// database to reach this inconceivable state.
 		idx.L.DPanic("cannot update a pointer with a different start timestamp")
-		idx.mu.Unlock()
-		return span.Error(NewErrRangeNotFound(p.TimeRange))
+		return NewErrRangeNotFound(p.TimeRange)
 	}
 	overlapsWithNext := updateAt != len(ptrs)-1 && ptrs[updateAt+1].OverlapsWith(p.TimeRange)
 	overlapsWithPrev := updateAt != 0 && ptrs[updateAt-1].OverlapsWith(p.TimeRange)
 	if overlapsWithPrev {
-		idx.mu.Unlock()
-		return span.Error(NewErrWriteConflict(p.TimeRange, ptrs[updateAt-1].TimeRange))
+		return NewErrWriteConflict(p.TimeRange, ptrs[updateAt-1].TimeRange)
 	} else if overlapsWithNext {
-		idx.mu.Unlock()
-		return span.Error(NewErrWriteConflict(p.TimeRange, ptrs[updateAt+1].TimeRange))
-	} else {
-		idx.mu.pointers[updateAt] = p
+		return NewErrWriteConflict(p.TimeRange, ptrs[updateAt+1].TimeRange)
 	}
-
+	idx.mu.pointers[updateAt] = p
 	idx.persistHead = min(idx.persistHead, updateAt)
-
-	if persist {
-		persistPointers := idx.indexPersist.prepare(idx.persistHead)
-		idx.mu.Unlock()
-		return persistPointers()
-	}
-
-	idx.mu.Unlock()
 	return nil
 }
 
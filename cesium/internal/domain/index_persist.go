@@ -11,6 +11,7 @@ package domain
 
 import (
 	"encoding/binary"
+	"github.com/synnaxlabs/x/errors"
 	"github.com/synnaxlabs/x/io/fs"
 	"github.com/synnaxlabs/x/telem"
 	"os"
@@ -23,22 +24,40 @@ type indexPersist struct {
 	Config
 	p   *pointerPersist
 	idx *index
+	// s persists the summaries of the pointers in the index. Only set when the DB
+	// stores summarizable data.
+	s *summaryPersist
 }
 
-func openIndexPersist(idx *index, fs fs.FS) (*indexPersist, error) {
+func openIndexPersist(idx *index, fs fs.FS, summarize bool) (*indexPersist, error) {
 	p, err := openPointerPersist(fs)
-
+	if err != nil {
+		return nil, err
+	}
 	ip := &indexPersist{p: p, idx: idx}
-	return ip, err
+	if summarize {
+		if ip.s, err = openSummaryPersist(fs); err != nil {
+			return nil, errors.CombineErrors(err, p.Close())
+		}
+	}
+	return ip, nil
 }
 
 func (ip *indexPersist) load() ([]pointer, error) {
-	return ip.p.load()
+	ptrs, err := ip.p.load()
+	if err != nil || ip.s == nil {
+		return ptrs, err
+	}
+	return ptrs, ip.s.load(ptrs)
 }
 
 func (ip *indexPersist) prepare(start int) func() error {
 	pointerEncoded := ip.p.encode(start, ip.idx.mu.pointers)
 	lenOfPointers := len(ip.idx.mu.pointers)
+	var summaryEncoded []byte
+	if ip.s != nil {
+		summaryEncoded = encodeSummaries(start, ip.idx.mu.pointers)
+	}
 
 	return func() error {
 		ip.p.Lock()
@@ -49,12 +68,18 @@ func (ip *indexPersist) prepare(start int) func() error {
 			return err
 		}
 		_, err = ip.p.WriteAt(pointerEncoded, int64(start*pointerByteSize))
-		return err
+		if err != nil || ip.s == nil {
+			return err
+		}
+		return ip.s.write(start, lenOfPointers, summaryEncoded)
 	}
 }
 
 func (ip *indexPersist) Close() error {
-	return ip.p.Close()
+	if ip.s == nil {
+		return ip.p.Close()
+	}
+	return errors.CombineErrors(ip.p.Close(), ip.s.Close())
 }
 
 type pointerPersist struct {
@@ -57,6 +57,11 @@ type Iterator struct {
 	valid bool
 	// readerFactory gets a new reader for the given domain pointer.
 	readerFactory func(ctx context.Context, ptr pointer) (*Reader, error)
+	// fc is used to look up the sample offset tables of variable density domains.
+	fc *fileController
+	// offsets is the sample offset table of the file the current domain is stored in.
+	// It is kept open across calls to avoid reopening the table on every lookup.
+	offsets *offsetTable
 	// closed stores whether the iterator is still open
 	closed bool
 	// onClose is called when the iterator is closed.
@@ -71,6 +76,7 @@ func (db *DB) OpenIterator(cfg IteratorConfig) *Iterator {
 		Instrumentation: db.cfg.Instrumentation.Child("iterator"),
 		idx:             db.idx,
 		readerFactory:   db.newReader,
+		fc:              db.fc,
 		onClose:         func() { db.entityCount.Add(-1) },
 	}
 	i.SetBounds(cfg.Bounds)
@@ -180,12 +186,80 @@ func (i *Iterator) OpenReader(ctx context.Context) (*Reader, error) {
 // Len returns the number of bytes occupied by the telemetry in the current domain.
 func (i *Iterator) Len() int64 { return int64(i.value.length) }
 
+// Summary returns a summary of the samples in the current domain. Summary returns false
+// if the domain has not been summarized, which is always the case for DBs that do not
+// store numeric data (see Config.DataType).
+func (i *Iterator) Summary() (Summary, bool) {
+	if i.value.summary == nil {
+		return Summary{}, false
+	}
+	return *i.value.summary, true
+}
+
+// SampleCount returns the number of samples in the current domain. SampleCount is only
+// valid for DBs that store variable density data (see Config.Variable).
+func (i *Iterator) SampleCount() (int64, error) {
+	if i.closed {
+		return 0, errIteratorClosed
+	}
+	t, err := i.offsetTable()
+	if err != nil {
+		return 0, err
+	}
+	return t.sampleCount(i.value)
+}
+
+// SampleOffset returns the byte offset of the sample at the given index relative to
+// the start of the current domain. Indexes at or beyond the number of samples in the
+// domain resolve to the length of the domain. SampleOffset is only valid for DBs that
+// store variable density data (see Config.Variable).
+func (i *Iterator) SampleOffset(sample int64) (int64, error) {
+	if i.closed {
+		return 0, errIteratorClosed
+	}
+	if sample <= 0 {
+		return 0, nil
+	}
+	t, err := i.offsetTable()
+	if err != nil {
+		return 0, err
+	}
+	return t.sampleOffset(i.value, sample)
+}
+
+// offsetTable returns the sample offset table of the file the current domain is stored
+// in, reopening it if the iterator has moved to a different file or the table has been
+// replaced by garbage collection.
+func (i *Iterator) offsetTable() (*offsetTable, error) {
+	if i.offsets != nil &&
+		(i.offsets.key != i.value.fileKey || i.offsets.epoch != i.fc.offsetTableEpoch.Load()) {
+		err := i.offsets.Close()
+		i.offsets = nil
+		if err != nil {
+			return nil, err
+		}
+	}
+	if i.offsets == nil {
+		t, err := i.fc.openOffsetTable(i.value.fileKey)
+		if err != nil {
+			return nil, err
+		}
+		i.offsets = t
+	}
+	return i.offsets, nil
+}
+
 // Close closes the iterator.
 func (i *Iterator) Close() error {
 	i.closed = true
 	i.valid = false
 	i.onClose()
-	return nil
+	if i.offsets == nil {
+		return nil
+	}
+	err := i.offsets.Close()
+	i.offsets = nil
+	return err
 }
 
 func (i *Iterator) reload() bool {
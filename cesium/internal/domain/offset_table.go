@@ -0,0 +1,328 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// Domains of variable density data (see Config.Variable) store newline-delimited
+// samples, so the number of samples in a domain cannot be derived from its size. For
+// these DBs, every data file has an accompanying sample offset table that stores the
+// byte offset at which each sample in the file starts. Offsets are appended to the
+// table as samples are written, and are therefore sorted in ascending order. Pointers
+// always start and end at sample boundaries, so the samples in a domain are exactly
+// the table entries that fall within the byte range of its pointer.
+
+const (
+	offsetTableExtension = ".offsets"
+	// offsetEntrySize is the number of bytes occupied by a single entry in a sample
+	// offset table.
+	offsetEntrySize = 4
+	sampleDelimiter = '\n'
+)
+
+// ErrCorruptOffsetTable is returned when the sample offset table of a data file is
+// inconsistent with the domains stored in the file.
+var ErrCorruptOffsetTable = errors.New("sample offset table is corrupt")
+
+func offsetTableName(key uint16) string {
+	return strconv.Itoa(int(key)) + offsetTableExtension
+}
+
+// offsetTableWriter appends the starting offsets of samples written to a data file to
+// the file's sample offset table.
+type offsetTableWriter struct {
+	f xfs.File
+	// pos is the offset in the data file at which the next write will be placed.
+	pos uint32
+	// sampleStart is the offset in the data file at which the sample currently being
+	// written starts.
+	sampleStart uint32
+	// buf holds the entries of the samples found by the last call to scan, and ends
+	// holds the offset at which each of those samples ends.
+	buf  []byte
+	ends []uint32
+}
+
+// openOffsetTableWriter opens a writer that appends to the sample offset table of the
+// data file with the given key. fileSize is the current size of the data file, and
+// marks the offset at which the next sample will start.
+func (fc *fileController) openOffsetTableWriter(key uint16, fileSize int64) (*offsetTableWriter, error) {
+	f, err := fc.FS.Open(offsetTableName(key), os.O_CREATE|os.O_WRONLY|os.O_APPEND)
+	if err != nil {
+		return nil, err
+	}
+	return &offsetTableWriter{f: f, pos: uint32(fileSize), sampleStart: uint32(fileSize)}, nil
+}
+
+// scan finds the samples terminated within p, which must be the next bytes written to
+// the data file. scan must be called before p is written, as writing to a file may
+// modify the contents of p.
+func (t *offsetTableWriter) scan(p []byte) {
+	t.buf, t.ends = t.buf[:0], t.ends[:0]
+	start := t.sampleStart
+	for i, b := range p {
+		if b == sampleDelimiter {
+			t.buf = byteOrder.AppendUint32(t.buf, start)
+			start = t.pos + uint32(i) + 1
+			t.ends = append(t.ends, start)
+		}
+	}
+}
+
+// write records the offsets of the samples found by the last call to scan that fall
+// within the first n bytes written to the data file. write returns the number of
+// samples recorded.
+func (t *offsetTableWriter) write(n int) (int64, error) {
+	t.pos += uint32(n)
+	count := 0
+	for count < len(t.ends) && t.ends[count] <= t.pos {
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	t.sampleStart = t.ends[count-1]
+	_, err := t.f.Write(t.buf[:count*offsetEntrySize])
+	return int64(count), err
+}
+
+func (t *offsetTableWriter) Close() error { return t.f.Close() }
+
+// offsetTable provides read access to the sample offset table of a data file. An
+// offsetTable can be kept open across lookups, as entries are only ever appended to the
+// table until the file is garbage collected.
+type offsetTable struct {
+	f   xfs.File
+	key uint16
+	// epoch is the garbage collection epoch of the file controller when the table was
+	// opened. See fileController.offsetTableEpoch.
+	epoch uint64
+	// len is the number of entries in the table when it was last stat'd.
+	len int64
+}
+
+func (fc *fileController) openOffsetTable(key uint16) (*offsetTable, error) {
+	epoch := fc.offsetTableEpoch.Load()
+	f, err := fc.FS.Open(offsetTableName(key), os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	t := &offsetTable{f: f, key: key, epoch: epoch}
+	if _, err = t.refresh(); err != nil {
+		return nil, errors.CombineErrors(err, f.Close())
+	}
+	return t, nil
+}
+
+// refresh updates the length of the table to include any entries appended since it was
+// last refreshed, returning true if the length changed.
+func (t *offsetTable) refresh() (bool, error) {
+	s, err := t.f.Stat()
+	if err != nil {
+		return false, err
+	}
+	l := s.Size() / offsetEntrySize
+	changed := l != t.len
+	t.len = l
+	return changed, nil
+}
+
+// at returns the entry at the given position in the table.
+func (t *offsetTable) at(i int64) (uint32, error) {
+	var b [offsetEntrySize]byte
+	if _, err := t.f.ReadAt(b[:], i*offsetEntrySize); err != nil {
+		return 0, err
+	}
+	return byteOrder.Uint32(b[:]), nil
+}
+
+// search returns the position of the first entry in the table that is greater than or
+// equal to the given offset, or the length of the table if no such entry exists.
+func (t *offsetTable) search(offset uint32) (int64, error) {
+	lo, hi := int64(0), t.len
+	for lo < hi {
+		mid := int64(uint64(lo+hi) >> 1)
+		v, err := t.at(mid)
+		if err != nil {
+			return 0, err
+		}
+		if v < offset {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// searchEnd is identical to search, but refreshes the table before concluding that no
+// entry is greater than or equal to the given offset, as the entries of samples
+// committed after the table was opened may not have been loaded yet.
+func (t *offsetTable) searchEnd(offset uint32) (int64, error) {
+	i, err := t.search(offset)
+	if err != nil || i < t.len {
+		return i, err
+	}
+	if changed, err := t.refresh(); err != nil || !changed {
+		return i, err
+	}
+	return t.search(offset)
+}
+
+func (t *offsetTable) Close() error { return t.f.Close() }
+
+// sampleCount returns the number of samples in the domain referenced by the given
+// pointer, which must be stored in the table's data file.
+func (t *offsetTable) sampleCount(ptr pointer) (int64, error) {
+	start, err := t.searchEnd(ptr.offset)
+	if err != nil {
+		return 0, err
+	}
+	end, err := t.searchEnd(ptr.offset + ptr.length)
+	if err != nil {
+		return 0, err
+	}
+	if end < start {
+		return 0, errors.Wrapf(ErrCorruptOffsetTable, "file %d", ptr.fileKey)
+	}
+	return end - start, nil
+}
+
+// sampleOffset returns the offset, relative to the start of the domain referenced by
+// the given pointer, of the sample at the given index within the domain. Indexes at or
+// beyond the number of samples in the domain resolve to the length of the domain, and
+// negative indexes resolve to zero. The pointer must be stored in the table's data
+// file.
+func (t *offsetTable) sampleOffset(ptr pointer, sample int64) (int64, error) {
+	if sample <= 0 {
+		return 0, nil
+	}
+	start, err := t.searchEnd(ptr.offset)
+	if err != nil {
+		return 0, err
+	}
+	if start+sample >= t.len {
+		if _, err = t.refresh(); err != nil {
+			return 0, err
+		}
+		if start+sample >= t.len {
+			return int64(ptr.length), nil
+		}
+	}
+	v, err := t.at(start + sample)
+	if err != nil {
+		return 0, err
+	}
+	// Entries are sorted, so the sample must start after both the start of the domain
+	// and the sample before it.
+	prev, err := t.at(start + sample - 1)
+	if err != nil {
+		return 0, err
+	}
+	if v < ptr.offset || v < prev {
+		return 0, errors.Wrapf(ErrCorruptOffsetTable, "file %d", ptr.fileKey)
+	}
+	return min(int64(v-ptr.offset), int64(ptr.length)), nil
+}
+
+// sampleOffset opens the sample offset table of the data file of the given pointer and
+// returns the offset of the sample at the given index within the domain. See
+// offsetTable.sampleOffset for more details. Callers that look up many samples should
+// keep an offsetTable open instead.
+func (fc *fileController) sampleOffset(ptr pointer, sample int64) (off int64, err error) {
+	if sample <= 0 {
+		return 0, nil
+	}
+	t, err := fc.openOffsetTable(ptr.fileKey)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = errors.CombineErrors(err, t.Close()) }()
+	return t.sampleOffset(ptr, sample)
+}
+
+// sampleSize returns the number of bytes occupied by the first n samples of the domain
+// referenced by the given pointer, using the sample offset table if the DB stores
+// variable density data, and the provided density otherwise.
+func (db *DB) sampleSize(ptr pointer, n int64, den telem.Density) (int64, error) {
+	if !db.cfg.Variable {
+		return int64(den.Size(n)), nil
+	}
+	return db.fc.sampleOffset(ptr, n)
+}
+
+// writeOffsetTable writes a sample offset table for the data read from r to a file
+// with the given name. The data must start at offset 0 of its data file.
+func writeOffsetTable(fs xfs.FS, name string, r io.Reader) (err error) {
+	f, err := fs.Open(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, f.Close()) }()
+	var (
+		t   = &offsetTableWriter{f: f}
+		buf = make([]byte, 64*telem.Kilobyte)
+	)
+	for {
+		n, rErr := r.Read(buf)
+		t.scan(buf[:n])
+		if _, err = t.write(n); err != nil {
+			return err
+		}
+		if errors.Is(rErr, io.EOF) {
+			return nil
+		}
+		if rErr != nil {
+			return rErr
+		}
+	}
+}
+
+// rebuildOffsetTables writes a sample offset table for every data file in the DB that
+// is missing one, such as files copied by a snapshot or written before the DB stored
+// offset tables.
+func (fc *fileController) rebuildOffsetTables() error {
+	for key := uint16(1); key <= uint16(fc.counter.Value()); key++ {
+		exists, err := fc.fsFor(key).Exists(fileKeyToName(key))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if exists, err = fc.FS.Exists(offsetTableName(key)); err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err = fc.rebuildOffsetTable(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fc *fileController) rebuildOffsetTable(key uint16) (err error) {
+	f, err := fc.fsFor(key).Open(fileKeyToName(key), os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, f.Close()) }()
+	return writeOffsetTable(fc.FS, offsetTableName(key), f)
+}
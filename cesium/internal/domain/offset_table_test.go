@@ -0,0 +1,188 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain_test
+
+import (
+	"math"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Sample Offset Tables", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db      *domain.DB
+				fs      xfs.FS
+				cleanUp func() error
+				open    = func(cfg domain.Config) *domain.DB {
+					cfg.FS = fs
+					cfg.Variable = true
+					cfg.Instrumentation = PanicLogger()
+					return MustSucceed(domain.Open(cfg))
+				}
+				readAll = func(i *domain.Iterator) string {
+					r := MustSucceed(i.OpenReader(ctx))
+					b := make([]byte, r.Len())
+					MustSucceed(r.ReadAt(b, 0))
+					Expect(r.Close()).To(Succeed())
+					return string(b)
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = open(domain.Config{})
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			It("Should locate samples within a domain", func() {
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				Expect(domain.Write(ctx, db, (13 * telem.SecondTS).Range(14*telem.SecondTS), []byte("dddd\n"))).To(Succeed())
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(i.SampleCount()).To(BeEquivalentTo(3))
+				Expect(i.SampleOffset(0)).To(BeEquivalentTo(0))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(2))
+				Expect(i.SampleOffset(2)).To(BeEquivalentTo(5))
+				Expect(i.SampleOffset(3)).To(BeEquivalentTo(9))
+				Expect(i.SampleOffset(10)).To(BeEquivalentTo(9))
+				Expect(i.Next()).To(BeTrue())
+				Expect(i.SampleCount()).To(BeEquivalentTo(1))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(5))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should count samples split across multiple writes", func() {
+				w := MustSucceed(db.OpenWriter(ctx, domain.WriterConfig{Start: 10 * telem.SecondTS}))
+				MustSucceed(w.Write([]byte("a\nb")))
+				Expect(w.SampleCount()).To(BeEquivalentTo(1))
+				MustSucceed(w.Write([]byte("b\n")))
+				Expect(w.SampleCount()).To(BeEquivalentTo(2))
+				Expect(w.Commit(ctx, 12*telem.SecondTS)).To(Succeed())
+				Expect(w.Close()).To(Succeed())
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(i.SampleCount()).To(BeEquivalentTo(2))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(2))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should delete samples by their index", func() {
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				Expect(db.Delete(
+					ctx,
+					createCalcOffset(1),
+					createCalcOffset(2),
+					(11 * telem.SecondTS).Range(12*telem.SecondTS),
+					telem.DensityUnknown,
+				)).To(Succeed())
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(readAll(i)).To(Equal("a\n"))
+				Expect(i.SampleCount()).To(BeEquivalentTo(1))
+				Expect(i.Next()).To(BeTrue())
+				Expect(readAll(i)).To(Equal("ccc\n"))
+				Expect(i.SampleCount()).To(BeEquivalentTo(1))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(4))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should rewrite the offset table when garbage collecting", func() {
+				Expect(db.Close()).To(Succeed())
+				db = open(domain.Config{FileSize: 5 * telem.ByteSize, GCThreshold: math.SmallestNonzeroFloat32})
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				Expect(db.Delete(
+					ctx,
+					createCalcOffset(0),
+					createCalcOffset(2),
+					(10 * telem.SecondTS).Range(12*telem.SecondTS),
+					telem.DensityUnknown,
+				)).To(Succeed())
+				Expect(db.GarbageCollect(ctx)).To(Succeed())
+				Expect(MustSucceed(fs.Stat("1.domain")).Size()).To(Equal(int64(4)))
+				Expect(MustSucceed(fs.Stat("1.offsets")).Size()).To(Equal(int64(4)))
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(readAll(i)).To(Equal("ccc\n"))
+				Expect(i.SampleCount()).To(BeEquivalentTo(1))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(4))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should locate samples committed after the table was read", func() {
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(i.SampleCount()).To(BeEquivalentTo(3))
+				Expect(domain.Write(ctx, db, (13 * telem.SecondTS).Range(15*telem.SecondTS), []byte("dddd\nee\n"))).To(Succeed())
+				Expect(i.Next()).To(BeTrue())
+				Expect(i.SampleCount()).To(BeEquivalentTo(2))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(5))
+				Expect(i.SampleOffset(2)).To(BeEquivalentTo(8))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should reread the offset table after garbage collection rewrites it", func() {
+				Expect(db.Close()).To(Succeed())
+				db = open(domain.Config{FileSize: 5 * telem.ByteSize, GCThreshold: math.SmallestNonzeroFloat32})
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(i.SampleOffset(2)).To(BeEquivalentTo(5))
+				Expect(db.Delete(
+					ctx,
+					createCalcOffset(0),
+					createCalcOffset(2),
+					(10 * telem.SecondTS).Range(12*telem.SecondTS),
+					telem.DensityUnknown,
+				)).To(Succeed())
+				Expect(db.GarbageCollect(ctx)).To(Succeed())
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(readAll(i)).To(Equal("ccc\n"))
+				Expect(i.SampleCount()).To(BeEquivalentTo(1))
+				Expect(i.SampleOffset(1)).To(BeEquivalentTo(4))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should rebuild missing offset tables when opened", func() {
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				Expect(db.Close()).To(Succeed())
+				Expect(fs.Remove("1.offsets")).To(Succeed())
+				db = open(domain.Config{})
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				Expect(i.SampleCount()).To(BeEquivalentTo(3))
+				Expect(i.SampleOffset(2)).To(BeEquivalentTo(5))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should return an error when the offset table is out of order", func() {
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(13*telem.SecondTS), []byte("a\nbb\nccc\n"))).To(Succeed())
+				f := MustSucceed(fs.Open("1.offsets", os.O_WRONLY|os.O_TRUNC))
+				MustSucceed(f.Write([]byte{5, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}))
+				Expect(f.Close()).To(Succeed())
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				_, err := i.SampleOffset(2)
+				Expect(err).To(HaveOccurredAs(domain.ErrCorruptOffsetTable))
+				Expect(i.Close()).To(Succeed())
+			})
+		})
+	}
+})
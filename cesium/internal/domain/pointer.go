@@ -27,4 +27,7 @@ type pointer struct {
 	offset uint32
 	// length is the length of the domain within the file.
 	length uint32
+	// summary holds statistics over the samples in the domain, or is nil if the
+	// domain has not been summarized. A summary is never modified once created.
+	summary *Summary
 }
@@ -0,0 +1,216 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/synnaxlabs/x/errors"
+	xio "github.com/synnaxlabs/x/io"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// Snapshot is a point-in-time view of the committed domains in a DB. A Snapshot holds
+// a lock that prevents garbage collection from rewriting domain files, so it must be
+// released (via Close) as soon as the caller is done with it.
+type Snapshot struct {
+	db       *DB
+	pointers []pointer
+	counter  int32
+	released bool
+}
+
+// OpenSnapshot captures the current set of committed domains in the DB. Domain files
+// are append-only between garbage collections, so the bytes referenced by the captured
+// pointers are guaranteed to remain unchanged until the snapshot is closed, even if
+// writers continue to commit new domains or deletes tombstone existing ones.
+func (db *DB) OpenSnapshot(ctx context.Context) (*Snapshot, error) {
+	s, thaw, err := db.OpenFrozenSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	thaw()
+	return s, nil
+}
+
+// OpenFrozenSnapshot is identical to OpenSnapshot, except that new domains cannot be
+// committed to or deleted from the DB until the returned thaw function is called. This
+// allows the caller to capture the state of other DBs at the same point in time as the
+// snapshot. thaw must be called exactly once, and as soon as possible, as writers to
+// the DB block until it is.
+func (db *DB) OpenFrozenSnapshot(ctx context.Context) (*Snapshot, func(), error) {
+	_, span := db.cfg.T.Bench(ctx, "open_snapshot")
+	defer span.End()
+	if db.closed.Load() {
+		return nil, nil, errDBClosed
+	}
+	db.gcLock.RLock()
+	db.entityCount.Add(1)
+	db.idx.mu.RLock()
+	ptrs := make([]pointer, len(db.idx.mu.pointers))
+	copy(ptrs, db.idx.mu.pointers)
+	s := &Snapshot{db: db, pointers: ptrs, counter: db.fc.counter.Value()}
+	return s, db.idx.mu.RUnlock, nil
+}
+
+// Size returns the total number of bytes occupied by the domains in the snapshot.
+func (s *Snapshot) Size() int64 {
+	var size int64
+	for _, ptr := range s.pointers {
+		size += int64(ptr.length)
+	}
+	return size
+}
+
+// Domain is a committed domain captured by a Snapshot. Two domains are equal if and
+// only if they reference the same bytes on disk over the same time range.
+type Domain struct{ ptr pointer }
+
+// TimeRange returns the time range occupied by the domain.
+func (d Domain) TimeRange() telem.TimeRange { return d.ptr.TimeRange }
+
+// Size returns the number of bytes occupied by the domain.
+func (d Domain) Size() int64 { return int64(d.ptr.length) }
+
+// Domains returns the domains captured by the snapshot in time order.
+func (s *Snapshot) Domains() []Domain {
+	domains := make([]Domain, len(s.pointers))
+	for i, ptr := range s.pointers {
+		// Summaries are reloaded whenever the DB is opened, so they are excluded to
+		// keep domains comparable across DB instances.
+		ptr.summary = nil
+		domains[i] = Domain{ptr: ptr}
+	}
+	return domains
+}
+
+// Read reads the entire contents of the given domain, which must have been captured by
+// the snapshot.
+func (s *Snapshot) Read(ctx context.Context, d Domain) ([]byte, error) {
+	if s.released {
+		return nil, errors.New("snapshot has already been closed")
+	}
+	r, err := s.db.newReader(ctx, d.ptr)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, r.Len())
+	if _, err = r.ReadAt(b, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.CombineErrors(err, r.Close())
+	}
+	return b, r.Close()
+}
+
+// WriteTo copies the domains in the snapshot, along with the index required to read
+// them and their summaries, to the root of the provided file system. The resulting directory can be opened
+// as a standalone DB. The destination file system should be empty. Sample offset
+// tables are not copied, and are instead rebuilt when the copy is opened.
+func (s *Snapshot) WriteTo(ctx context.Context, dst xfs.FS) error {
+	_, span := s.db.cfg.T.Bench(ctx, "snapshot_write_to")
+	defer span.End()
+	if s.released {
+		return span.Error(errors.New("snapshot has already been closed"))
+	}
+	// Only copy the committed prefix of each file. Any bytes beyond the last committed
+	// pointer belong to uncommitted writes and must not end up in the snapshot.
+	ends := make(map[uint16]int64)
+	for _, ptr := range s.pointers {
+		ends[ptr.fileKey] = max(ends[ptr.fileKey], int64(ptr.offset)+int64(ptr.length))
+	}
+	for key, end := range ends {
+		if err := s.copyFile(dst, key, end); err != nil {
+			return span.Error(err)
+		}
+	}
+	if err := s.writeIndex(dst); err != nil {
+		return span.Error(err)
+	}
+	if err := s.writeSummaries(dst); err != nil {
+		return span.Error(err)
+	}
+	return span.Error(s.writeCounter(dst))
+}
+
+func (s *Snapshot) copyFile(dst xfs.FS, key uint16, size int64) (err error) {
+	name := fileKeyToName(key)
+	src, err := s.db.fc.fsFor(key).Open(name, os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, src.Close()) }()
+	out, err := dst.Open(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, io.NewSectionReader(src, 0, size)); err != nil {
+		return errors.CombineErrors(err, out.Close())
+	}
+	return out.Close()
+}
+
+func (s *Snapshot) writeIndex(dst xfs.FS) error {
+	f, err := dst.Open(indexFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	var codec pointerCodec
+	if len(s.pointers) > 0 {
+		if _, err = f.Write(codec.encode(0, s.pointers)); err != nil {
+			return errors.CombineErrors(err, f.Close())
+		}
+	}
+	return f.Close()
+}
+
+func (s *Snapshot) writeSummaries(dst xfs.FS) error {
+	if !Summarizable(s.db.cfg.DataType) {
+		return nil
+	}
+	f, err := dst.Open(summaryFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if len(s.pointers) > 0 {
+		if _, err = f.Write(encodeSummaries(0, s.pointers)); err != nil {
+			return errors.CombineErrors(err, f.Close())
+		}
+	}
+	return f.Close()
+}
+
+func (s *Snapshot) writeCounter(dst xfs.FS) error {
+	f, err := dst.Open(counterFile, os.O_CREATE|os.O_RDWR)
+	if err != nil {
+		return err
+	}
+	c, err := xio.NewInt32Counter(f)
+	if err != nil {
+		return errors.CombineErrors(err, f.Close())
+	}
+	if _, err = c.Add(s.counter - c.Value()); err != nil {
+		return errors.CombineErrors(err, f.Close())
+	}
+	return f.Close()
+}
+
+// Close releases the snapshot, allowing garbage collection to resume. Close is
+// idempotent.
+func (s *Snapshot) Close() error {
+	if s.released {
+		return nil
+	}
+	s.released = true
+	s.db.entityCount.Add(-1)
+	s.db.gcLock.RUnlock()
+	return nil
+}
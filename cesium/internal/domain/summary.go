@@ -0,0 +1,208 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain
+
+import (
+	"math"
+	"os"
+	"sync"
+
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// DBs that store numeric data (see Config.DataType) compute a Summary of the samples in
+// each domain as it is written, allowing statistics over a time range to be computed
+// without reading the domains that the range fully contains. Summaries are persisted
+// to a file that holds one fixed-size record for each pointer in the index, in the same
+// order, and is rewritten alongside the index. Each record also stores the time range
+// of its pointer, so a record that is out of sync with the index (e.g. due to a crash
+// between the two writes) is discarded when the DB is opened.
+//
+// Summaries are only kept for domains that hold exactly the data they were written
+// with. Domains that are shortened by a deletion lose their summary, as do domains of
+// DBs that were written to before summaries were introduced.
+
+const (
+	summaryFile = "summary" + extension
+	// summaryByteSize is the number of bytes occupied by a single summary record.
+	summaryByteSize = 48
+)
+
+// Summary holds statistics over the numeric samples of one or more domains.
+type Summary struct {
+	// Count is the number of samples summarized.
+	Count int64
+	// Min and Max are the smallest and largest samples summarized, ignoring NaN
+	// values.
+	Min, Max float64
+	// Sum is the sum of all samples summarized.
+	Sum float64
+}
+
+// Merge returns a summary of the samples summarized by both s and o.
+func (s Summary) Merge(o Summary) Summary {
+	if s.Count == 0 {
+		return o
+	}
+	if o.Count == 0 {
+		return s
+	}
+	return Summary{
+		Count: s.Count + o.Count,
+		Min:   min(s.Min, o.Min),
+		Max:   max(s.Max, o.Max),
+		Sum:   s.Sum + o.Sum,
+	}
+}
+
+func (s *Summary) add(v float64) {
+	if s.Count == 0 {
+		s.Min, s.Max = math.Inf(1), math.Inf(-1)
+	}
+	s.Count++
+	s.Sum += v
+	if v < s.Min {
+		s.Min = v
+	}
+	if v > s.Max {
+		s.Max = v
+	}
+}
+
+// Summarizable returns true if samples of the given data type can be summarized.
+func Summarizable(dt telem.DataType) bool { return dt.IsNumeric() }
+
+// Summarize returns a summary of the samples of the given data type in b. Summarize
+// panics if the data type is not summarizable.
+func Summarize(dt telem.DataType, b []byte) Summary {
+	s := newSummarizer(dt)
+	s.write(b)
+	return s.Summary
+}
+
+// summarizer incrementally summarizes samples written to a domain. Samples may be
+// split across calls to write.
+type summarizer struct {
+	Summary
+	decode  func(b []byte) float64
+	density int
+	// partial holds the leading bytes of a sample split across writes.
+	partial []byte
+}
+
+func newSummarizer(dt telem.DataType) *summarizer {
+	if !Summarizable(dt) {
+		panic("cannot summarize samples of data type " + string(dt))
+	}
+	return &summarizer{decode: telem.UnmarshalF[float64](dt), density: int(dt.Density())}
+}
+
+func (s *summarizer) write(p []byte) {
+	if len(s.partial) > 0 {
+		n := min(s.density-len(s.partial), len(p))
+		s.partial = append(s.partial, p[:n]...)
+		p = p[n:]
+		if len(s.partial) < s.density {
+			return
+		}
+		s.add(s.decode(s.partial))
+		s.partial = s.partial[:0]
+	}
+	for ; len(p) >= s.density; p = p[s.density:] {
+		s.add(s.decode(p[:s.density]))
+	}
+	s.partial = append(s.partial, p...)
+}
+
+func (s *summarizer) reset() {
+	s.Summary = Summary{}
+	s.partial = s.partial[:0]
+}
+
+// summaryPersist persists the summaries of the pointers in the index.
+type summaryPersist struct {
+	xfs.File
+	sync.Mutex
+}
+
+func openSummaryPersist(fs xfs.FS) (*summaryPersist, error) {
+	f, err := fs.Open(summaryFile, os.O_CREATE|os.O_RDWR)
+	return &summaryPersist{File: f}, err
+}
+
+// load attaches the persisted summaries to the given pointers.
+func (p *summaryPersist) load(ptrs []pointer) error {
+	info, err := p.Stat()
+	if err != nil {
+		return err
+	}
+	b := make([]byte, min(info.Size(), int64(len(ptrs))*summaryByteSize))
+	if len(b) != 0 {
+		if _, err = p.ReadAt(b, 0); err != nil {
+			return err
+		}
+	}
+	decodeSummaries(b, ptrs)
+	return nil
+}
+
+// write writes the encoded summaries of the pointers from position start onwards,
+// truncating the file to hold exactly n records.
+func (p *summaryPersist) write(start, n int, encoded []byte) error {
+	p.Lock()
+	defer p.Unlock()
+	if err := p.Truncate(int64(n) * summaryByteSize); err != nil {
+		return err
+	}
+	_, err := p.WriteAt(encoded, int64(start*summaryByteSize))
+	return err
+}
+
+// encodeSummaries encodes the summaries of the pointers from position start onwards.
+// Pointers without a summary are encoded as empty records.
+func encodeSummaries(start int, ptrs []pointer) []byte {
+	b := make([]byte, (len(ptrs)-start)*summaryByteSize)
+	for i := start; i < len(ptrs); i++ {
+		ptr := ptrs[i]
+		if ptr.summary == nil {
+			continue
+		}
+		base := (i - start) * summaryByteSize
+		byteOrder.PutUint64(b[base:base+8], uint64(ptr.Start))
+		byteOrder.PutUint64(b[base+8:base+16], uint64(ptr.End))
+		byteOrder.PutUint64(b[base+16:base+24], uint64(ptr.summary.Count))
+		byteOrder.PutUint64(b[base+24:base+32], math.Float64bits(ptr.summary.Min))
+		byteOrder.PutUint64(b[base+32:base+40], math.Float64bits(ptr.summary.Max))
+		byteOrder.PutUint64(b[base+40:base+48], math.Float64bits(ptr.summary.Sum))
+	}
+	return b
+}
+
+// decodeSummaries attaches the summaries encoded in b to the pointers at the same
+// positions, skipping empty records and records whose time range does not match.
+func decodeSummaries(b []byte, ptrs []pointer) {
+	for i := 0; i < len(b)/summaryByteSize && i < len(ptrs); i++ {
+		base := i * summaryByteSize
+		s := &Summary{
+			Count: int64(byteOrder.Uint64(b[base+16 : base+24])),
+			Min:   math.Float64frombits(byteOrder.Uint64(b[base+24 : base+32])),
+			Max:   math.Float64frombits(byteOrder.Uint64(b[base+32 : base+40])),
+			Sum:   math.Float64frombits(byteOrder.Uint64(b[base+40 : base+48])),
+		}
+		tr := telem.TimeRange{
+			Start: telem.TimeStamp(byteOrder.Uint64(b[base : base+8])),
+			End:   telem.TimeStamp(byteOrder.Uint64(b[base+8 : base+16])),
+		}
+		if s.Count > 0 && tr == ptrs[i].TimeRange {
+			ptrs[i].summary = s
+		}
+	}
+}
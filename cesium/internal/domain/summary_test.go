@@ -0,0 +1,181 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Summary", func() {
+	Describe("Summarize", func() {
+		It("Should summarize signed integers", func() {
+			s := domain.Summarize(telem.Int32T, telem.NewSeriesV[int32](-5, 3, 10).Data)
+			Expect(s).To(Equal(domain.Summary{Count: 3, Min: -5, Max: 10, Sum: 8}))
+		})
+		It("Should ignore NaN values in the minimum and maximum", func() {
+			s := domain.Summarize(telem.Float64T, telem.NewSeriesV[float64](1, math.NaN(), 2).Data)
+			Expect(s.Count).To(Equal(int64(3)))
+			Expect(s.Min).To(Equal(1.0))
+			Expect(s.Max).To(Equal(2.0))
+			Expect(math.IsNaN(s.Sum)).To(BeTrue())
+		})
+		It("Should not summarize non-numeric data types", func() {
+			Expect(domain.Summarizable(telem.StringT)).To(BeFalse())
+			Expect(domain.Summarizable(telem.TimeStampT)).To(BeFalse())
+			Expect(domain.Summarizable(telem.Uint8T)).To(BeTrue())
+		})
+	})
+
+	Describe("Merge", func() {
+		It("Should merge two summaries", func() {
+			a := domain.Summary{Count: 2, Min: 1, Max: 5, Sum: 6}
+			b := domain.Summary{Count: 1, Min: -1, Max: -1, Sum: -1}
+			Expect(a.Merge(b)).To(Equal(domain.Summary{Count: 3, Min: -1, Max: 5, Sum: 5}))
+		})
+		It("Should ignore empty summaries", func() {
+			a := domain.Summary{Count: 2, Min: 1, Max: 5, Sum: 6}
+			Expect(a.Merge(domain.Summary{})).To(Equal(a))
+			Expect(domain.Summary{}.Merge(a)).To(Equal(a))
+		})
+	})
+
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db      *domain.DB
+				fs      xfs.FS
+				cleanUp func() error
+				open    = func(fs xfs.FS) *domain.DB {
+					return MustSucceed(domain.Open(domain.Config{
+						FS:              fs,
+						FileSize:        1 * telem.Megabyte,
+						GCThreshold:     math.SmallestNonzeroFloat32,
+						DataType:        telem.Int64T,
+						Instrumentation: PanicLogger(),
+					}))
+				}
+				summaries = func(db *domain.DB) (s []*domain.Summary) {
+					i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+					for ok := i.SeekFirst(ctx); ok; ok = i.Next() {
+						if ds, summarized := i.Summary(); summarized {
+							s = append(s, &ds)
+						} else {
+							s = append(s, nil)
+						}
+					}
+					Expect(i.Close()).To(Succeed())
+					return s
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = open(fs)
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(15*telem.SecondTS), telem.NewSeriesV[int64](1, 2, 3, 4, 5).Data)).To(Succeed())
+				Expect(domain.Write(ctx, db, (20 * telem.SecondTS).Range(22*telem.SecondTS), telem.NewSeriesV[int64](-7, 9).Data)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			It("Should summarize each domain as it is written", func() {
+				Expect(summaries(db)).To(Equal([]*domain.Summary{
+					{Count: 5, Min: 1, Max: 5, Sum: 15},
+					{Count: 2, Min: -7, Max: 9, Sum: 2},
+				}))
+			})
+
+			It("Should summarize samples split across writes and commits", func() {
+				w := MustSucceed(db.OpenWriter(ctx, domain.WriterConfig{Start: 30 * telem.SecondTS}))
+				data := telem.NewSeriesV[int64](100, 200, 300).Data
+				MustSucceed(w.Write(data[:5]))
+				MustSucceed(w.Write(data[5:12]))
+				Expect(w.Commit(ctx, 32*telem.SecondTS)).To(Succeed())
+				MustSucceed(w.Write(data[12:]))
+				Expect(w.Commit(ctx, 33*telem.SecondTS)).To(Succeed())
+				Expect(w.Close()).To(Succeed())
+				Expect(summaries(db)[2]).To(Equal(&domain.Summary{Count: 3, Min: 100, Max: 300, Sum: 600}))
+			})
+
+			It("Should persist summaries across reopens", func() {
+				Expect(db.Close()).To(Succeed())
+				db = open(fs)
+				Expect(summaries(db)).To(Equal([]*domain.Summary{
+					{Count: 5, Min: 1, Max: 5, Sum: 15},
+					{Count: 2, Min: -7, Max: 9, Sum: 2},
+				}))
+			})
+
+			It("Should discard the summary of a domain shortened by a deletion", func() {
+				Expect(db.Delete(ctx, createCalcOffset(3), createCalcOffset(0), (13 * telem.SecondTS).Range(20*telem.SecondTS), telem.Bit64)).To(Succeed())
+				Expect(summaries(db)).To(Equal([]*domain.Summary{nil, {Count: 2, Min: -7, Max: 9, Sum: 2}}))
+				Expect(db.Close()).To(Succeed())
+				db = open(fs)
+				Expect(summaries(db)).To(Equal([]*domain.Summary{nil, {Count: 2, Min: -7, Max: 9, Sum: 2}}))
+			})
+
+			It("Should keep summaries when garbage collecting", func() {
+				// Reopen the DB with a tiny file size so that its only file is collected.
+				Expect(db.Close()).To(Succeed())
+				db = MustSucceed(domain.Open(domain.Config{
+					FS:              fs,
+					FileSize:        1 * telem.ByteSize,
+					GCThreshold:     math.SmallestNonzeroFloat32,
+					DataType:        telem.Int64T,
+					Instrumentation: PanicLogger(),
+				}))
+				Expect(db.Delete(ctx, createCalcOffset(0), createCalcOffset(0), (10 * telem.SecondTS).Range(15*telem.SecondTS), telem.Bit64)).To(Succeed())
+				Expect(db.GarbageCollect(ctx)).To(Succeed())
+				Expect(MustSucceed(fs.Stat("1.domain")).Size()).To(Equal(int64(16)))
+				Expect(summaries(db)).To(Equal([]*domain.Summary{{Count: 2, Min: -7, Max: 9, Sum: 2}}))
+			})
+
+			It("Should not summarize existing domains if the summary file is missing", func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(fs.Remove("summary.domain")).To(Succeed())
+				db = open(fs)
+				Expect(summaries(db)).To(Equal([]*domain.Summary{nil, nil}))
+			})
+
+			It("Should copy summaries to a snapshot", func() {
+				dstFS, dstCleanUp := makeFS()
+				s := MustSucceed(db.OpenSnapshot(ctx))
+				Expect(s.WriteTo(ctx, dstFS)).To(Succeed())
+				Expect(s.Close()).To(Succeed())
+				dst := open(dstFS)
+				Expect(summaries(dst)).To(Equal(summaries(db)))
+				Expect(dst.Close()).To(Succeed())
+				Expect(dstCleanUp()).To(Succeed())
+			})
+
+			It("Should not summarize domains of non-numeric data", func() {
+				strFS, strCleanUp := makeFS()
+				strDB := MustSucceed(domain.Open(domain.Config{
+					FS:              strFS,
+					DataType:        telem.StringT,
+					Variable:        true,
+					Instrumentation: PanicLogger(),
+				}))
+				Expect(domain.Write(ctx, strDB, (10 * telem.SecondTS).Range(12*telem.SecondTS), []byte("a\nb\n"))).To(Succeed())
+				Expect(summaries(strDB)).To(Equal([]*domain.Summary{nil}))
+				Expect(MustSucceed(strFS.Exists("summary.domain"))).To(BeFalse())
+				Expect(strDB.Close()).To(Succeed())
+				Expect(strCleanUp()).To(Succeed())
+			})
+		})
+	}
+})
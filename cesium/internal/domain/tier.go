@@ -0,0 +1,242 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// coldCopySuffix is appended to the name of a file while it is being copied to the cold
+// tier, so that a partial copy is never mistaken for a complete one.
+const coldCopySuffix = "_cold"
+
+// MoveToColdTier moves every file whose domains all end at or before the given
+// timestamp from the DB's filesystem to its cold filesystem. Files that have a writer
+// or reader in use are skipped, and will be moved on a later call. Moved files can
+// still be read from, deleted from, and garbage collected, while new data is always
+// written to the DB's primary filesystem. MoveToColdTier is a no-op if the DB was not
+// configured with a ColdFS.
+func (db *DB) MoveToColdTier(ctx context.Context, before telem.TimeStamp) error {
+	if db.cfg.ColdFS == nil {
+		return nil
+	}
+	ctx, span := db.cfg.T.Bench(ctx, "move_to_cold_tier")
+	defer span.End()
+
+	if db.closed.Load() {
+		return errDBClosed
+	}
+	// Avoid blocking snapshots and garbage collection when there is nothing to move.
+	if len(db.coldCandidates(before)) == 0 {
+		return nil
+	}
+	db.entityCount.Add(1)
+	defer db.entityCount.Add(-1)
+
+	// Hold the GC lock to prevent files from being rewritten or copied by a snapshot
+	// while they are moved.
+	db.gcLock.Lock()
+	defer db.gcLock.Unlock()
+
+	for _, key := range db.coldCandidates(before) {
+		if _, err := db.fc.moveToCold(key); err != nil {
+			return span.Error(err)
+		}
+	}
+	return nil
+}
+
+// coldCandidates returns the keys of the files in the hot tier that contain at least
+// one domain, and whose domains all end at or before the given timestamp.
+func (db *DB) coldCandidates(before telem.TimeStamp) []uint16 {
+	eligible := make(map[uint16]bool)
+	db.idx.mu.RLock()
+	for _, ptr := range db.idx.mu.pointers {
+		ok, seen := eligible[ptr.fileKey]
+		eligible[ptr.fileKey] = (ok || !seen) && ptr.End <= before
+	}
+	db.idx.mu.RUnlock()
+	keys := make([]uint16, 0, len(eligible))
+	for key, ok := range eligible {
+		if ok && !db.fc.isCold(key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// scanColdFiles returns the set of keys of files that are stored in the cold tier. A
+// file that exists in both tiers was already copied by an interrupted move, so its hot
+// copy is removed.
+func (fc *fileController) scanColdFiles() (map[uint16]struct{}, error) {
+	cold := make(map[uint16]struct{})
+	if fc.ColdFS == nil {
+		return cold, nil
+	}
+	for i := 1; i <= int(fc.counter.Value()); i++ {
+		name := fileKeyToName(uint16(i))
+		if err := fc.ColdFS.Remove(name + coldCopySuffix); err != nil {
+			return cold, err
+		}
+		e, err := fc.ColdFS.Exists(name)
+		if err != nil {
+			return cold, err
+		}
+		if !e {
+			continue
+		}
+		cold[uint16(i)] = struct{}{}
+		if err = fc.FS.Remove(name); err != nil {
+			return cold, err
+		}
+	}
+	return cold, nil
+}
+
+// isCold returns true if the file with the given key is stored in the cold tier.
+func (fc *fileController) isCold(key uint16) bool {
+	fc.cold.RLock()
+	defer fc.cold.RUnlock()
+	_, ok := fc.cold.keys[key]
+	return ok
+}
+
+// fsFor returns the filesystem that the file with the given key is stored in.
+func (fc *fileController) fsFor(key uint16) xfs.FS {
+	fc.cold.RLock()
+	defer fc.cold.RUnlock()
+	return fc.tierFS(key)
+}
+
+// tierFS is the same as fsFor, but expects the caller to hold the cold lock.
+func (fc *fileController) tierFS(key uint16) xfs.FS {
+	if _, ok := fc.cold.keys[key]; ok {
+		return fc.ColdFS
+	}
+	return fc.FS
+}
+
+// moveToCold copies the file with the given key to the cold tier and removes it from
+// the hot tier. It returns false if the file has a writer or reader in use, in which
+// case the file is left in the hot tier.
+func (fc *fileController) moveToCold(key uint16) (moved bool, err error) {
+	if ok, err := fc.retireWriter(key); !ok || err != nil {
+		return false, err
+	}
+	name := fileKeyToName(key)
+	defer func() {
+		if moved {
+			return
+		}
+		// Remove any copy from the cold tier before making the file writable again,
+		// since the cold copy takes precedence when the DB is reopened.
+		if rErr := fc.ColdFS.Remove(name + coldCopySuffix); rErr != nil {
+			err = errors.CombineErrors(err, rErr)
+			return
+		}
+		if rErr := fc.ColdFS.Remove(name); rErr != nil {
+			err = errors.CombineErrors(err, rErr)
+			return
+		}
+		err = errors.CombineErrors(err, fc.rejuvenate(key))
+	}()
+	if err = copyAcross(fc.FS, fc.ColdFS, name, name+coldCopySuffix); err != nil {
+		return false, err
+	}
+	if err = fc.ColdFS.Rename(name+coldCopySuffix, name); err != nil {
+		return false, err
+	}
+	fc.cold.Lock()
+	fc.readers.Lock()
+	if moved, err = fc.closeReaders(key); moved {
+		fc.cold.keys[key] = struct{}{}
+	}
+	fc.readers.Unlock()
+	fc.cold.Unlock()
+	if !moved {
+		return false, err
+	}
+	return true, fc.FS.Remove(name)
+}
+
+// retireWriter closes any open writer on the file with the given key, and prevents new
+// writers from being opened on it. It returns false if the writer is in use.
+func (fc *fileController) retireWriter(key uint16) (bool, error) {
+	fc.writers.Lock()
+	defer fc.writers.Unlock()
+	if w, ok := fc.writers.open[key]; ok {
+		if !w.tryAcquire() {
+			return false, nil
+		}
+		if err := w.HardClose(); err != nil {
+			return false, err
+		}
+		delete(fc.writers.open, key)
+	}
+	delete(fc.writers.unopened, key)
+	return true, nil
+}
+
+// closeReaders closes all readers on the file with the given key that are not in use.
+// It returns true if no readers remain open on the file. The caller must hold the
+// readers lock.
+func (fc *fileController) closeReaders(key uint16) (closed bool, err error) {
+	f, ok := fc.readers.files[key]
+	if !ok {
+		return true, nil
+	}
+	f.Lock()
+	defer f.Unlock()
+	f.open = lo.Filter(f.open, func(r controlledReader, _ int) bool {
+		if !r.tryAcquire() {
+			return true
+		}
+		if cErr := r.HardClose(); cErr != nil {
+			err = errors.CombineErrors(err, cErr)
+			return true
+		}
+		return false
+	})
+	if len(f.open) > 0 {
+		return false, err
+	}
+	delete(fc.readers.files, key)
+	return true, err
+}
+
+// copyAcross copies the file with the given name in src to a file named dstName in
+// the destination filesystem, syncing the copy before returning.
+func copyAcross(src, dst xfs.FS, name, dstName string) (err error) {
+	in, err := src.Open(name, os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, in.Close()) }()
+	out, err := dst.Open(dstName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		return errors.CombineErrors(err, out.Close())
+	}
+	if err = out.Sync(); err != nil {
+		return errors.CombineErrors(err, out.Close())
+	}
+	return out.Close()
+}
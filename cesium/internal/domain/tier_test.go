@@ -0,0 +1,139 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package domain_test
+
+import (
+	"io"
+	"math"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Cold Tier", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db                   *domain.DB
+				fs, coldFS           xfs.FS
+				cleanUp, coldCleanUp func() error
+				open                 = func() *domain.DB {
+					return MustSucceed(domain.Open(domain.Config{
+						FS:              fs,
+						ColdFS:          coldFS,
+						FileSize:        10 * telem.ByteSize,
+						GCThreshold:     math.SmallestNonzeroFloat32,
+						Instrumentation: PanicLogger(),
+					}))
+				}
+				readAll = func() (data []byte) {
+					i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+					for ok := i.SeekFirst(ctx); ok; ok = i.Next() {
+						r := MustSucceed(i.OpenReader(ctx))
+						buf := make([]byte, r.Len())
+						MustSucceed(r.ReadAt(buf, 0))
+						Expect(r.Close()).To(Succeed())
+						data = append(data, buf...)
+					}
+					Expect(i.Close()).To(Succeed())
+					return data
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				coldFS, coldCleanUp = makeFS()
+				db = open()
+				Expect(domain.Write(ctx, db, (10 * telem.SecondTS).Range(20*telem.SecondTS), []byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19})).To(Succeed())
+				Expect(domain.Write(ctx, db, (30 * telem.SecondTS).Range(40*telem.SecondTS), []byte{30, 31, 32, 33, 34, 35, 36, 37, 38, 39})).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+				Expect(coldCleanUp()).To(Succeed())
+			})
+
+			It("Should move files with only old data to the cold tier", func() {
+				Expect(db.MoveToColdTier(ctx, 25*telem.SecondTS)).To(Succeed())
+				Expect(MustSucceed(fs.Exists("1.domain"))).To(BeFalse())
+				Expect(MustSucceed(coldFS.Exists("1.domain"))).To(BeTrue())
+				Expect(MustSucceed(fs.Exists("2.domain"))).To(BeTrue())
+				Expect(MustSucceed(coldFS.Exists("2.domain"))).To(BeFalse())
+				Expect(readAll()).To(Equal([]byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39}))
+			})
+
+			It("Should not move files that contain recent data", func() {
+				Expect(db.MoveToColdTier(ctx, 35*telem.SecondTS)).To(Succeed())
+				Expect(MustSucceed(coldFS.Exists("1.domain"))).To(BeTrue())
+				Expect(MustSucceed(coldFS.Exists("2.domain"))).To(BeFalse())
+			})
+
+			It("Should not move a file that is being read from", func() {
+				i := db.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+				Expect(i.SeekFirst(ctx)).To(BeTrue())
+				r := MustSucceed(i.OpenReader(ctx))
+				Expect(db.MoveToColdTier(ctx, 25*telem.SecondTS)).To(Succeed())
+				Expect(MustSucceed(fs.Exists("1.domain"))).To(BeTrue())
+				Expect(MustSucceed(coldFS.Exists("1.domain"))).To(BeFalse())
+				Expect(r.Close()).To(Succeed())
+				Expect(i.Close()).To(Succeed())
+				Expect(db.MoveToColdTier(ctx, 25*telem.SecondTS)).To(Succeed())
+				Expect(MustSucceed(coldFS.Exists("1.domain"))).To(BeTrue())
+			})
+
+			It("Should write new data to the hot tier", func() {
+				Expect(db.MoveToColdTier(ctx, 45*telem.SecondTS)).To(Succeed())
+				Expect(domain.Write(ctx, db, (50 * telem.SecondTS).Range(52*telem.SecondTS), []byte{50, 51})).To(Succeed())
+				Expect(MustSucceed(fs.Exists("3.domain"))).To(BeTrue())
+				Expect(MustSucceed(coldFS.Exists("3.domain"))).To(BeFalse())
+				Expect(readAll()[18:]).To(Equal([]byte{38, 39, 50, 51}))
+			})
+
+			It("Should delete and garbage collect data in the cold tier", func() {
+				Expect(db.MoveToColdTier(ctx, 25*telem.SecondTS)).To(Succeed())
+				Expect(db.Delete(ctx, createCalcOffset(2), createCalcOffset(8), (12 * telem.SecondTS).Range(18*telem.SecondTS), telem.Density(1))).To(Succeed())
+				Expect(db.GarbageCollect(ctx)).To(Succeed())
+				Expect(MustSucceed(coldFS.Stat("1.domain")).Size()).To(Equal(int64(4)))
+				Expect(MustSucceed(fs.Exists("1.domain"))).To(BeFalse())
+				Expect(readAll()).To(Equal([]byte{10, 11, 18, 19, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39}))
+			})
+
+			It("Should read from the cold tier after reopening the DB", func() {
+				Expect(db.MoveToColdTier(ctx, 25*telem.SecondTS)).To(Succeed())
+				Expect(db.Close()).To(Succeed())
+				db = open()
+				Expect(readAll()[:10]).To(Equal([]byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}))
+			})
+
+			It("Should recover from a move that was interrupted after copying the file", func() {
+				Expect(db.Close()).To(Succeed())
+				src := MustSucceed(fs.Open("1.domain", os.O_RDONLY))
+				dst := MustSucceed(coldFS.Open("1.domain", os.O_CREATE|os.O_WRONLY))
+				MustSucceed(io.Copy(dst, src))
+				Expect(src.Close()).To(Succeed())
+				Expect(dst.Close()).To(Succeed())
+				db = open()
+				Expect(MustSucceed(fs.Exists("1.domain"))).To(BeFalse())
+				Expect(readAll()[:10]).To(Equal([]byte{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}))
+			})
+
+			It("Should do nothing without a cold filesystem", func() {
+				Expect(db.Close()).To(Succeed())
+				db = MustSucceed(domain.Open(domain.Config{FS: fs, Instrumentation: PanicLogger()}))
+				Expect(db.MoveToColdTier(ctx, telem.TimeStampMax)).To(Succeed())
+				Expect(MustSucceed(fs.Exists("1.domain"))).To(BeTrue())
+			})
+		})
+	}
+})
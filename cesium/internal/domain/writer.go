@@ -11,6 +11,7 @@ package domain
 
 import (
 	"context"
+	"slices"
 
 	"github.com/samber/lo"
 	"github.com/synnaxlabs/alamos"
@@ -47,11 +48,55 @@ type WriterConfig struct {
 	// Setting an AutoIndexPersistInterval is invalid if EnableAutoCommit is off.
 	// [OPTIONAL] Defaults to 1s
 	AutoIndexPersistInterval telem.TimeSpan
+
+	// ConflictPolicy determines how the writer handles domains that overlap with
+	// existing data in the DB. See the ConflictPolicy documentation for more.
+	// [OPTIONAL] - Defaults to ConflictFail.
+	ConflictPolicy ConflictPolicy
+	// Overwrite is used to resolve the regions of existing domains replaced by a writer
+	// with the ConflictOverwrite policy.
+	// [REQUIRED] - If ConflictPolicy is ConflictOverwrite.
+	Overwrite OverwriteConfig
+}
+
+// ConflictPolicy determines how a Writer handles domains that overlap with existing
+// data in the DB.
+type ConflictPolicy uint8
+
+const (
+	// ConflictFail causes the writer to return an ErrWriteConflict when it is opened
+	// or committed over existing data.
+	ConflictFail ConflictPolicy = iota + 1
+	// ConflictOverwrite causes the writer to replace the regions of existing domains
+	// that overlap with the domain being committed. The replaced data is removed from
+	// the index in the same operation that commits the new domain, and its bytes are
+	// reclaimed by garbage collection.
+	ConflictOverwrite
+	// ConflictFillGaps allows the writer to be opened over existing data, but still
+	// returns an ErrWriteConflict when a commit overlaps with it. Callers are
+	// responsible for discarding overlapping samples, and for calling Writer.Restart
+	// to begin a new domain after any existing data they skip over.
+	ConflictFillGaps
+)
+
+// OverwriteConfig is used to resolve the regions of existing domains replaced by a
+// writer with the ConflictOverwrite policy.
+type OverwriteConfig struct {
+	// StartOffset calculates the offset of the first sample to replace in a domain.
+	StartOffset OffsetCalculator
+	// EndOffset calculates the offset of the first sample to keep in a domain.
+	EndOffset OffsetCalculator
+	// Density is the density of the samples in the DB.
+	Density telem.Density
 }
 
 var (
 	errWriterClosed     = core.EntityClosed("domain.writer")
-	DefaultWriterConfig = WriterConfig{EnableAutoCommit: config.False(), AutoIndexPersistInterval: 1 * telem.Second}
+	DefaultWriterConfig = WriterConfig{
+		EnableAutoCommit:         config.False(),
+		AutoIndexPersistInterval: 1 * telem.Second,
+		ConflictPolicy:           ConflictFail,
+	}
 )
 
 const AlwaysIndexPersistOnAutoCommit telem.TimeSpan = -1
@@ -67,8 +112,12 @@ func (w WriterConfig) Domain() telem.TimeRange {
 
 func (w WriterConfig) Validate() error {
 	v := validate.New("domain.WriterConfig")
-	v.Ternary("end", w.End.Before(w.Start), "end timestamp must be after or equal to start timestamp")
-	return nil
+	v.Ternary("end", !w.End.IsZero() && w.End.Before(w.Start), "end timestamp must be after or equal to start timestamp")
+	if w.ConflictPolicy == ConflictOverwrite {
+		v.Ternary("overwrite.start_offset", w.Overwrite.StartOffset == nil, "must be non-nil")
+		v.Ternary("overwrite.end_offset", w.Overwrite.EndOffset == nil, "must be non-nil")
+	}
+	return v.Error()
 }
 
 func (w WriterConfig) Override(other WriterConfig) WriterConfig {
@@ -76,6 +125,8 @@ func (w WriterConfig) Override(other WriterConfig) WriterConfig {
 	w.End = override.Zero(w.End, other.End)
 	w.EnableAutoCommit = override.Nil(w.EnableAutoCommit, other.EnableAutoCommit)
 	w.AutoIndexPersistInterval = override.Zero(w.AutoIndexPersistInterval, other.AutoIndexPersistInterval)
+	w.ConflictPolicy = override.Numeric(w.ConflictPolicy, other.ConflictPolicy)
+	w.Overwrite = override.If(w.Overwrite, other.Overwrite, other.Overwrite.StartOffset != nil)
 	return w
 }
 
@@ -118,6 +169,9 @@ type Writer struct {
 	prevCommit telem.TimeStamp
 	// idx is the underlying index for the database that stores locations of domains in FS.
 	idx *index
+	// db is the DB the writer belongs to. It is used to resolve the data replaced by
+	// writers with the ConflictOverwrite policy.
+	db *DB
 	// fileKey represents the key of the file written to by the writer. One can convert it
 	// to a filename via the fileKeyToName function.
 	fileKey uint16
@@ -127,6 +181,15 @@ type Writer struct {
 	fileSize telem.Size
 	// len is the number of bytes written by all internal writers of the domain writer.
 	len int64
+	// offsets appends to the sample offset table of the writer's file. Only set when
+	// the DB stores variable density data.
+	offsets *offsetTableWriter
+	// sampleCount is the number of samples written by the writer. Only tracked when
+	// the DB stores variable density data.
+	sampleCount int64
+	// summary summarizes the samples written to the domain. Only set when the DB
+	// stores summarizable data.
+	summary *summarizer
 	// internal is a TrackedWriteCloser used to write telemetry to FS.
 	internal xio.TrackedWriteCloser
 	// presetEnd denotes whether the writer has a preset end as part of its WriterConfig.
@@ -153,7 +216,7 @@ func (db *DB) OpenWriter(ctx context.Context, cfg WriterConfig) (*Writer, error)
 	if err != nil {
 		return nil, err
 	}
-	if db.idx.overlap(cfg.Domain()) {
+	if cfg.ConflictPolicy == ConflictFail && db.idx.overlap(cfg.Domain()) {
 		return nil, errors.Wrap(
 			NewErrWriteConflict(cfg.Domain(), db.idx.timeRange()),
 			"cannot open writer because there is already data in the writer's time range",
@@ -163,6 +226,16 @@ func (db *DB) OpenWriter(ctx context.Context, cfg WriterConfig) (*Writer, error)
 	if err != nil {
 		return nil, err
 	}
+	var offsets *offsetTableWriter
+	if db.cfg.Variable {
+		if offsets, err = db.fc.openOffsetTableWriter(key, size); err != nil {
+			return nil, errors.CombineErrors(err, internal.Close())
+		}
+	}
+	var summary *summarizer
+	if Summarizable(db.cfg.DataType) {
+		summary = newSummarizer(db.cfg.DataType)
+	}
 	db.entityCount.Add(1)
 	w := &Writer{
 		WriterConfig:     cfg,
@@ -171,7 +244,10 @@ func (db *DB) OpenWriter(ctx context.Context, cfg WriterConfig) (*Writer, error)
 		fc:               db.fc,
 		fileSize:         telem.Size(size),
 		internal:         internal,
+		offsets:          offsets,
+		summary:          summary,
 		idx:              db.idx,
+		db:               db,
 		presetEnd:        !cfg.End.IsZero(),
 		lastIndexPersist: telem.Now(),
 		onClose: func() {
@@ -195,6 +271,10 @@ func (db *DB) OpenWriter(ctx context.Context, cfg WriterConfig) (*Writer, error)
 // Len returns the number of bytes written to the domain.
 func (w *Writer) Len() int64 { return w.len }
 
+// SampleCount returns the number of samples written to the domain. SampleCount is
+// only tracked for DBs that store variable density data (see Config.Variable).
+func (w *Writer) SampleCount() int64 { return w.sampleCount }
+
 // Writer writes binary telemetry to the domain. Write is not safe to call concurrently
 // with any other Writer methods. The contents of p are safe to modify after Write
 // returns.
@@ -202,10 +282,23 @@ func (w *Writer) Write(p []byte) (int, error) {
 	if w.closed {
 		return 0, errWriterClosed
 	}
+	// Summarize and scan p before writing it, as writing to a file may modify the
+	// contents of p.
+	if w.summary != nil {
+		w.summary.write(p)
+	}
+	if w.offsets != nil {
+		w.offsets.scan(p)
+	}
 	n, err := w.internal.Write(p)
 	w.fileSize += telem.Size(n)
 	w.len += int64(n)
-	return n, err
+	if w.offsets == nil {
+		return n, err
+	}
+	samples, oErr := w.offsets.write(n)
+	w.sampleCount += samples
+	return n, errors.CombineErrors(err, oErr)
 }
 
 // Commit commits the domain to the DB, making it available for reading by other processes.
@@ -259,9 +352,17 @@ func (w *Writer) commit(ctx context.Context, end telem.TimeStamp, persist bool)
 		length:    uint32(length),
 		fileKey:   w.fileKey,
 	}
-	f := lo.Ternary(w.prevCommit.IsZero(), w.idx.insert, w.idx.update)
-
-	err := span.Error(f(ctx, ptr, persist))
+	if w.summary != nil {
+		s := w.summary.Summary
+		ptr.summary = &s
+	}
+	var err error
+	if w.ConflictPolicy == ConflictOverwrite {
+		err = w.overwrite(ctx, ptr, persist)
+	} else {
+		f := lo.Ternary(w.prevCommit.IsZero(), w.idx.insert, w.idx.update)
+		err = f(ctx, ptr, persist)
+	}
 	if err != nil {
 		return span.Error(err)
 	}
@@ -277,6 +378,18 @@ func (w *Writer) commit(ctx context.Context, end telem.TimeStamp, persist bool)
 			return span.Error(err)
 		}
 
+		if w.offsets != nil {
+			if err = w.offsets.Close(); err != nil {
+				return span.Error(err)
+			}
+			if w.offsets, err = w.fc.openOffsetTableWriter(newFileKey, newFileSize); err != nil {
+				return span.Error(err)
+			}
+		}
+
+		if w.summary != nil {
+			w.summary.reset()
+		}
 		w.fileKey = newFileKey
 		w.internal = newInternalWriter
 		w.fileSize = telem.Size(newFileSize)
@@ -289,6 +402,94 @@ func (w *Writer) commit(ctx context.Context, end telem.TimeStamp, persist bool)
 	return nil
 }
 
+// overwrite commits the given pointer to the index, replacing any regions of existing
+// domains that overlap with the part of the pointer that has not been committed yet.
+// The replaced regions are removed in the same critical section that commits the
+// pointer, so readers never observe both the old and new data, or neither.
+func (w *Writer) overwrite(ctx context.Context, ptr pointer, persist bool) error {
+	w.idx.deleteLock.Lock()
+	defer w.idx.deleteLock.Unlock()
+
+	tr := ptr.TimeRange
+	if !w.prevCommit.IsZero() {
+		// Any data overlapping with the previously committed part of the domain was
+		// already replaced by the previous commit.
+		tr.Start = w.prevCommit
+	}
+	var (
+		d   deletion
+		ok  bool
+		err error
+	)
+	if tr.Start.Before(tr.End) {
+		d, ok, err = w.db.resolveDeletion(
+			ctx,
+			w.Overwrite.StartOffset,
+			w.Overwrite.EndOffset,
+			tr,
+			w.Overwrite.Density,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.idx.mu.Lock()
+	// Keep a copy of the pointers so that the replaced regions can be restored if the
+	// new pointer cannot be committed.
+	prevPointers := slices.Clone(w.idx.mu.pointers)
+	if ok {
+		position, _, err := w.idx.unprotectedDelete(d)
+		if err != nil {
+			w.idx.mu.Unlock()
+			return err
+		}
+		w.idx.persistHead = min(w.idx.persistHead, position)
+	}
+	if w.prevCommit.IsZero() {
+		err = w.idx.unprotectedInsert(ptr)
+	} else {
+		err = w.idx.unprotectedUpdate(ptr)
+	}
+	if err != nil {
+		w.idx.mu.pointers = prevPointers
+		w.idx.mu.Unlock()
+		return err
+	}
+	if !persist {
+		w.idx.mu.Unlock()
+		return nil
+	}
+	persistPointers := w.idx.indexPersist.prepare(w.idx.persistHead)
+	w.idx.mu.Unlock()
+	return persistPointers()
+}
+
+// Restart begins a new domain starting at the given timestamp. Restart is used by
+// writers with the ConflictFillGaps policy to skip over existing data. All data
+// written to the current domain must be committed before calling Restart, as any
+// uncommitted data will be discarded.
+func (w *Writer) Restart(start telem.TimeStamp) error {
+	if w.closed {
+		return errWriterClosed
+	}
+	if w.presetEnd && !start.Before(w.End) {
+		return errors.Wrapf(
+			validate.Error,
+			"restart timestamp %s must be before the preset end timestamp %s",
+			start,
+			w.End,
+		)
+	}
+	w.internal.Reset()
+	if w.summary != nil {
+		w.summary.reset()
+	}
+	w.Start = start
+	w.prevCommit = 0
+	return nil
+}
+
 // resolveCommitEnd returns whether a file change is needed, the resolved commit end, and any errors.
 func (w *Writer) resolveCommitEnd(end telem.TimeStamp) (telem.TimeStamp, bool) {
 	// fc.Config.Filesize is the nominal file size to not exceed, in reality, this value
@@ -313,6 +514,11 @@ func (w *Writer) Close() error {
 	if err := w.internal.Close(); err != nil {
 		return err
 	}
+	if w.offsets != nil {
+		if err := w.offsets.Close(); err != nil {
+			return err
+		}
+	}
 	if *w.EnableAutoCommit && w.AutoIndexPersistInterval > 0 {
 		w.idx.mu.RLock()
 		persistPointers := w.idx.indexPersist.prepare(w.idx.persistHead)
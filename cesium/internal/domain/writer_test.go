@@ -424,6 +424,87 @@ var _ = Describe("Writer Behavior", Ordered, func() {
 					})
 				})
 			})
+			Describe("Conflict Policies", func() {
+				var readDomains = func() (ranges []telem.TimeRange, data [][]byte) {
+					iter := db.OpenIterator(domain.IteratorConfig{Bounds: telem.TimeRangeMax})
+					for iter.SeekFirst(ctx); iter.Valid(); iter.Next() {
+						r := MustSucceed(iter.OpenReader(ctx))
+						p := make([]byte, iter.Len())
+						MustSucceed(r.ReadAt(p, 0))
+						Expect(r.Close()).To(Succeed())
+						ranges = append(ranges, iter.TimeRange())
+						data = append(data, p)
+					}
+					Expect(iter.Close()).To(Succeed())
+					return
+				}
+				BeforeEach(func() {
+					w := MustSucceed(db.OpenWriter(ctx, domain.WriterConfig{Start: 10 * telem.SecondTS}))
+					MustSucceed(w.Write([]byte{10, 11, 12, 13, 14, 15}))
+					Expect(w.Commit(ctx, 16*telem.SecondTS)).To(Succeed())
+					Expect(w.Close()).To(Succeed())
+				})
+				Context("Overwrite", func() {
+					It("Should replace the overlapped region of an existing domain", func() {
+						w := MustSucceed(db.OpenWriter(ctx, domain.WriterConfig{
+							Start:          12 * telem.SecondTS,
+							ConflictPolicy: domain.ConflictOverwrite,
+							Overwrite: domain.OverwriteConfig{
+								StartOffset: createCalcOffset(2),
+								EndOffset:   createCalcOffset(4),
+								Density:     telem.Density(1),
+							},
+						}))
+						MustSucceed(w.Write([]byte{22, 23}))
+						Expect(w.Commit(ctx, 14*telem.SecondTS)).To(Succeed())
+						Expect(w.Close()).To(Succeed())
+						ranges, data := readDomains()
+						Expect(ranges).To(Equal([]telem.TimeRange{
+							(10 * telem.SecondTS).Range(12 * telem.SecondTS),
+							(12 * telem.SecondTS).Range(14 * telem.SecondTS),
+							(14 * telem.SecondTS).Range(16 * telem.SecondTS),
+						}))
+						Expect(data).To(Equal([][]byte{{10, 11}, {22, 23}, {14, 15}}))
+					})
+					It("Should not allow an overwrite without offset calculators", func() {
+						_, err := db.OpenWriter(ctx, domain.WriterConfig{
+							Start:          12 * telem.SecondTS,
+							ConflictPolicy: domain.ConflictOverwrite,
+						})
+						Expect(err).To(MatchError(ContainSubstring("overwrite.start_offset")))
+					})
+				})
+				Context("Fill Gaps", func() {
+					It("Should open the writer over existing data but fail to commit over it", func() {
+						w := MustSucceed(db.OpenWriter(ctx, domain.WriterConfig{
+							Start:          12 * telem.SecondTS,
+							ConflictPolicy: domain.ConflictFillGaps,
+						}))
+						MustSucceed(w.Write([]byte{22, 23}))
+						Expect(w.Commit(ctx, 14*telem.SecondTS)).To(HaveOccurredAs(domain.ErrWriteConflict))
+						Expect(w.Close()).To(Succeed())
+					})
+					It("Should restart the writer after existing data", func() {
+						w := MustSucceed(db.OpenWriter(ctx, domain.WriterConfig{
+							Start:          5 * telem.SecondTS,
+							ConflictPolicy: domain.ConflictFillGaps,
+						}))
+						MustSucceed(w.Write([]byte{5, 6}))
+						Expect(w.Commit(ctx, 7*telem.SecondTS)).To(Succeed())
+						Expect(w.Restart(16 * telem.SecondTS)).To(Succeed())
+						MustSucceed(w.Write([]byte{16, 17}))
+						Expect(w.Commit(ctx, 18*telem.SecondTS)).To(Succeed())
+						Expect(w.Close()).To(Succeed())
+						ranges, data := readDomains()
+						Expect(ranges).To(Equal([]telem.TimeRange{
+							(5 * telem.SecondTS).Range(7 * telem.SecondTS),
+							(10 * telem.SecondTS).Range(16 * telem.SecondTS),
+							(16 * telem.SecondTS).Range(18 * telem.SecondTS),
+						}))
+						Expect(data).To(Equal([][]byte{{5, 6}, {10, 11, 12, 13, 14, 15}, {16, 17}}))
+					})
+				})
+			})
 			Describe("AutoPersist", func() {
 				It("Should persist to disk every subsequent call after the set time interval", func() {
 					By("Opening a writer")
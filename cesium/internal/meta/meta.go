@@ -95,7 +95,6 @@ func validateMeta(ch core.Channel) error {
 		v.Ternaryf("index", ch.Index != 0, "virtual channel cannot be indexed")
 		v.Ternaryf("rate", ch.Rate != 0, "virtual channel cannot have a rate")
 	} else {
-		v.Ternary("data_type", ch.DataType == telem.StringT, "persisted channels cannot have string data types")
 		if ch.IsIndex {
 			v.Ternary("data_type", ch.DataType != telem.TimeStampT, "index channel must be of type timestamp")
 			v.Ternaryf("index", ch.Index != 0 && ch.Index != ch.Key, "index channel cannot be indexed by another channel")
@@ -27,6 +27,10 @@ var FileSystems = map[string]FSFactory{
 		dirName := MustSucceed(os.MkdirTemp("", "test-*"))
 		return MustSucceed(xfs.Default.Sub(dirName)), func() error { return xfs.Default.Remove(dirName) }
 	},
+	"encryptedMemFS": func() (xfs.FS, func() error) {
+		k := MustSucceed(xfs.NewKeyring(make([]byte, 32)))
+		return MustSucceed(xfs.NewEncrypted(xfs.NewMem(), k).Sub("testdata")), func() error { return nil }
+	},
 }
 
 var FileSystemsWithoutAssertion = map[string]FSFactory{
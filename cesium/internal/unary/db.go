@@ -38,6 +38,9 @@ type DB struct {
 	wrapError        func(error) error
 	closed           *atomic.Bool
 	leadingAlignment *atomic.Uint32
+	// quotaExceeded stores whether persisted writes to the DB should be refused
+	// because a storage quota is exceeded.
+	quotaExceeded *atomic.Bool
 }
 
 // ErrDBClosed is returned when an operation is attempted on a closed unary database.
@@ -102,6 +105,44 @@ func (db *DB) HasDataFor(ctx context.Context, tr telem.TimeRange) (bool, error)
 	return ok, db.wrapError(err)
 }
 
+// Domains returns the time ranges of all domains in the DB that overlap with the given
+// time range, in ascending order.
+func (db *DB) Domains(ctx context.Context, tr telem.TimeRange) ([]telem.TimeRange, error) {
+	if db.closed.Load() {
+		return nil, ErrDBClosed
+	}
+	var (
+		ranges []telem.TimeRange
+		i      = db.domain.OpenIterator(domain.IterRange(tr))
+	)
+	for ok := i.SeekFirst(ctx); ok; ok = i.Next() {
+		ranges = append(ranges, i.TimeRange())
+	}
+	return ranges, db.wrapError(i.Close())
+}
+
+// Extent is the time range and size of a domain in the DB.
+type Extent struct {
+	TimeRange telem.TimeRange
+	Size      telem.Size
+}
+
+// Extents returns the time range and size of every domain in the DB, in ascending
+// order.
+func (db *DB) Extents(ctx context.Context) ([]Extent, error) {
+	if db.closed.Load() {
+		return nil, ErrDBClosed
+	}
+	var (
+		extents []Extent
+		i       = db.domain.OpenIterator(domain.IterRange(telem.TimeRangeMax))
+	)
+	for ok := i.SeekFirst(ctx); ok; ok = i.Next() {
+		extents = append(extents, Extent{TimeRange: i.TimeRange(), Size: telem.Size(i.Len())})
+	}
+	return extents, db.wrapError(i.Close())
+}
+
 // Read reads a Time Range of data at the unary level.
 func (db *DB) Read(ctx context.Context, tr telem.TimeRange) (frame core.Frame, err error) {
 	defer func() { err = db.wrapError(err) }()
@@ -181,3 +222,25 @@ func (db *DB) SetChannelKeyInMeta(key core.ChannelKey) error {
 	db.cfg.Channel.Key = key
 	return meta.Create(db.cfg.FS, db.cfg.MetaCodec, db.cfg.Channel)
 }
+
+// OpenSnapshot captures a point-in-time view of the channel's committed data. The
+// returned snapshot must be closed after use. See domain.DB.OpenSnapshot for more
+// details.
+func (db *DB) OpenSnapshot(ctx context.Context) (*domain.Snapshot, error) {
+	if db.closed.Load() {
+		return nil, ErrDBClosed
+	}
+	s, err := db.domain.OpenSnapshot(ctx)
+	return s, db.wrapError(err)
+}
+
+// OpenFrozenSnapshot captures a point-in-time view of the channel's committed data and
+// blocks commits to the channel until the returned thaw function is called. See
+// domain.DB.OpenFrozenSnapshot for more details.
+func (db *DB) OpenFrozenSnapshot(ctx context.Context) (*domain.Snapshot, func(), error) {
+	if db.closed.Load() {
+		return nil, nil, ErrDBClosed
+	}
+	s, thaw, err := db.domain.OpenFrozenSnapshot(ctx)
+	return s, thaw, db.wrapError(err)
+}
@@ -13,6 +13,7 @@ import (
 	"context"
 	"github.com/cockroachdb/errors"
 	"github.com/google/uuid"
+	"github.com/synnaxlabs/cesium/internal/domain"
 	"github.com/synnaxlabs/cesium/internal/index"
 	"github.com/synnaxlabs/x/control"
 	"github.com/synnaxlabs/x/telem"
@@ -36,6 +37,46 @@ func (db *DB) GarbageCollect(ctx context.Context) error {
 	return db.wrapError(db.domain.GarbageCollect(ctx))
 }
 
+// MoveToColdTier moves the files of the unaryDB whose data all ends at or before the
+// given timestamp to the cold tier. It is a no-op if the DB has no cold tier.
+func (db *DB) MoveToColdTier(ctx context.Context, before telem.TimeStamp) error {
+	if db.closed.Load() {
+		return ErrDBClosed
+	}
+	return db.wrapError(db.domain.MoveToColdTier(ctx, before))
+}
+
+// Compact rewrites every file of the unaryDB that contains deleted data, regardless of
+// its size, returning the space occupied by that data to the filesystem.
+func (db *DB) Compact(ctx context.Context) error {
+	if db.closed.Load() {
+		return ErrDBClosed
+	}
+	return db.wrapError(db.domain.Compact(ctx))
+}
+
+// Size returns the total size of the data files of the unaryDB.
+func (db *DB) Size() (telem.Size, error) {
+	if db.closed.Load() {
+		return 0, ErrDBClosed
+	}
+	s, err := db.domain.Size()
+	return s, db.wrapError(err)
+}
+
+// Usage returns the disk usage of the data files of the unaryDB.
+func (db *DB) Usage() (domain.Usage, error) {
+	if db.closed.Load() {
+		return domain.Usage{}, ErrDBClosed
+	}
+	u, err := db.domain.Usage()
+	return u, db.wrapError(err)
+}
+
+// SetQuotaExceeded sets whether persisted writes to the unaryDB should be refused with
+// core.ErrQuotaExceeded because a storage quota is exceeded.
+func (db *DB) SetQuotaExceeded(exceeded bool) { db.quotaExceeded.Store(exceeded) }
+
 func (db *DB) delete(ctx context.Context, tr telem.TimeRange) error {
 	if !tr.Valid() {
 		return errors.Newf("delete start %d cannot be after delete end %d", tr.Start, tr.End)
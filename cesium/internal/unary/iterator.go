@@ -225,17 +225,22 @@ func (i *Iterator) autoNext(ctx context.Context) bool {
 			i.err = err
 			return false
 		}
-		startOffset := i.Channel.DataType.Density().Size(startApprox.Upper)
+		startSample := startApprox.Upper
 		if !startApprox.Exact() && !startApprox.StartExact {
 			// If we are starting from a cutoff dmn, use the lower offset.
-			startOffset = i.Channel.DataType.Density().Size(startApprox.Lower)
+			startSample = startApprox.Lower
 		}
-		series, err := i.read(
-			ctx,
-			dmn,
-			startOffset,
-			i.Channel.DataType.Density().Size(nRemaining),
-		)
+		startOffset, err := i.sampleOffset(startSample)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		endOffset, err := i.sampleOffset(startSample + nRemaining)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		series, err := i.read(ctx, dmn, startOffset, endOffset-startOffset)
 		if err != nil && !errors.Is(err, io.EOF) {
 			i.err = err
 			return false
@@ -360,6 +365,11 @@ func (i *Iterator) read(
 ) (series telem.Series, err error) {
 	series.DataType = i.Channel.DataType
 	series.TimeRange = i.internal.TimeRange().BoundBy(i.view)
+	if size < 0 {
+		// Only possible if the sample offsets of a variable density domain are
+		// inconsistent with each other.
+		return series, errors.Wrapf(domain.ErrCorruptOffsetTable, "invalid read of %v at offset %v", size, offset)
+	}
 	series.Data = make([]byte, size)
 	// set the first 32 bits to the domain index, and the last 32 bits to the alignment
 	series.Alignment = alignment
@@ -391,41 +401,66 @@ func (i *Iterator) sliceDomain(ctx context.Context) (
 	if err != nil {
 		return 0, align, 0, err
 	}
-	startOffset := i.Channel.DataType.Density().Size(startApprox.Upper)
+	startSample := startApprox.Upper
 	// Split into cases to determine which offsets to use. See unary/delete.go's
 	// calculateStartOffset function for more detail.
 	if !startApprox.Exact() && !startApprox.StartExact {
 		if startApprox.EndExact {
 			// If the start of the domain is inexact due to cutoff, but the end
 			// approximation is exact, we want to use the lower approximation.
-			startOffset = i.Channel.DataType.Density().Size(startApprox.Lower)
+			startSample = startApprox.Lower
 		} else {
-			off := (startApprox.Lower + startApprox.Upper) / 2
-			startOffset = i.Channel.DataType.Density().Size(off)
+			startSample = (startApprox.Lower + startApprox.Upper) / 2
 		}
 	}
+	startOffset, err := i.sampleOffset(startSample)
+	if err != nil {
+		return 0, align, 0, err
+	}
 	endApprox, err := i.approximateEnd(ctx)
 	if err != nil {
 		return 0, align, 0, err
 	}
-	endOffset := i.Channel.DataType.Density().Size(endApprox.Upper)
+	endSample := endApprox.Upper
 	// Split into cases to determine which offsets to use. See unary/delete.go's
 	// calculateEndOffset function for more detail.
 	if !endApprox.Exact() && !endApprox.StartExact {
 		if endApprox.EndExact {
 			// If the start of the domain is inexact due to cutoff, but the end
 			// approximation is exact, we want to use the lower approximation.
-			endOffset = i.Channel.DataType.Density().Size(endApprox.Lower)
+			endSample = endApprox.Lower
 		} else {
-			off := (endApprox.Lower + endApprox.Upper) / 2
-			endOffset = i.Channel.DataType.Density().Size(off)
+			endSample = (endApprox.Lower + endApprox.Upper) / 2
 		}
 	}
+	endOffset, err := i.sampleOffset(endSample)
+	if err != nil {
+		return 0, align, 0, err
+	}
 
 	size := endOffset - startOffset
 	return startOffset, align, size, nil
 }
 
+// sampleOffset returns the byte offset of the sample at the given index relative to
+// the start of the current domain. Fixed density channels compute the offset directly,
+// while variable density channels look it up in the domain's sample offset table.
+func (i *Iterator) sampleOffset(sample int64) (telem.Offset, error) {
+	if !i.Channel.DataType.IsVariable() {
+		return i.Channel.DataType.Density().Size(sample), nil
+	}
+	off, err := i.internal.SampleOffset(sample)
+	return telem.Offset(off), err
+}
+
+// sampleCount returns the number of samples in the current domain.
+func (i *Iterator) sampleCount() (int64, error) {
+	if !i.Channel.DataType.IsVariable() {
+		return i.Channel.DataType.Density().SampleCount(telem.Size(i.internal.Len())), nil
+	}
+	return i.internal.SampleCount()
+}
+
 // approximateStart approximates the number of samples between the start of the current
 // domain and the start of the current iterator view. If the start of the current view is
 // before the start of the range, the returned value will be zero.
@@ -447,7 +482,11 @@ func (i *Iterator) approximateStart(ctx context.Context) (
 // after the end of the range, the returned value will be the number of samples in the
 // range.
 func (i *Iterator) approximateEnd(ctx context.Context) (endApprox index.DistanceApproximation, err error) {
-	endApprox.Approximation = index.Exactly(i.Channel.DataType.Density().SampleCount(telem.Size(i.internal.Len())))
+	n, err := i.sampleCount()
+	if err != nil {
+		return endApprox, err
+	}
+	endApprox.Approximation = index.Exactly(n)
 	if i.internal.TimeRange().End.After(i.view.End) {
 		target := i.internal.TimeRange().Start.Range(i.view.End)
 		endApprox, _, err = i.idx.Distance(ctx, target, true)
@@ -979,7 +979,7 @@ var _ = Describe("Iterator Behavior", Ordered, func() {
 				MustSucceed(unaryW.Commit(ctx))
 				MustSucceed(unaryW.Close())
 				// Assert that we've rolled over the correct number of files
-				Expect(unaryFS.List(".")).To(HaveLen(4 /* meta, index, counter, and 1 data file*/))
+				Expect(unaryFS.List(".")).To(HaveLen(5 /* meta, index, summary, counter, and 1 data file*/))
 
 				tr := telem.TimeRange{Start: 7 * telem.SecondTS, End: 8 * telem.SecondTS}
 				iterCfg := unary.IteratorConfig{Bounds: tr}
@@ -1087,7 +1087,7 @@ var _ = Describe("Iterator Behavior", Ordered, func() {
 
 				MustSucceed(unaryW.Close())
 				// Assert that we've rolled over the correct number of files
-				Expect(uFS1.List(".")).To(HaveLen(5 /* meta, index, counter, and 2 data files*/))
+				Expect(uFS1.List(".")).To(HaveLen(6 /* meta, index, summary, counter, and 2 data files*/))
 
 				// Write to the second data channel
 				unaryW, _ = MustSucceed2(db2.OpenWriter(
@@ -1102,7 +1102,7 @@ var _ = Describe("Iterator Behavior", Ordered, func() {
 				MustSucceed(unaryW.Commit(ctx))
 				MustSucceed(unaryW.Close())
 				// Assert that we've rolled over the correct number of files
-				Expect(uFS2.List(".")).To(HaveLen(4 /* meta, index, counter, and 1 data file*/))
+				Expect(uFS2.List(".")).To(HaveLen(5 /* meta, index, summary, counter, and 1 data file*/))
 
 				tr := telem.TimeRange{Start: 11 * telem.SecondTS, End: 12 * telem.SecondTS}
 				iterCfg := unary.IteratorConfig{Bounds: tr}
@@ -46,6 +46,10 @@ type Config struct {
 	// exclusive access, and it should be empty when the DB is first opened.
 	// [REQUIRED]
 	FS xfs.FS
+	// ColdFS is the filesystem that MoveToColdTier moves old data to. If nil, the DB
+	// stores all of its data in FS.
+	// [OPTIONAL]
+	ColdFS xfs.FS
 	// FileSize is the maximum size, in bytes, for a writer to be created on a file.
 	// Note while that a file's size may still exceed this value, it is not likely
 	// to exceed by much with frequent commits.
@@ -57,6 +61,10 @@ type Config struct {
 	// instead, set it to a very small number greater than 0.
 	// [OPTIONAL] Default: 0.2
 	GCThreshold float32
+	// BlockCache caches the blocks of the DB's files as they are read, and is typically
+	// shared between all the unary DBs of a database.
+	// [OPTIONAL] Default: no cache
+	BlockCache *domain.BlockCache
 }
 
 var (
@@ -76,6 +84,7 @@ func (cfg Config) Validate() error {
 // Override implements config.GateConfig.
 func (cfg Config) Override(other Config) Config {
 	cfg.FS = override.Nil(cfg.FS, other.FS)
+	cfg.ColdFS = override.Nil(cfg.ColdFS, other.ColdFS)
 	if cfg.Channel.Key == 0 {
 		cfg.Channel = other.Channel
 	}
@@ -83,6 +92,7 @@ func (cfg Config) Override(other Config) Config {
 	cfg.FileSize = override.Numeric(cfg.FileSize, other.FileSize)
 	cfg.GCThreshold = override.Numeric(cfg.GCThreshold, other.GCThreshold)
 	cfg.MetaCodec = override.Nil(cfg.MetaCodec, other.MetaCodec)
+	cfg.BlockCache = override.Nil(cfg.BlockCache, other.BlockCache)
 	return cfg
 }
 
@@ -101,10 +111,17 @@ func Open(configs ...Config) (*DB, error) {
 	}
 	domainDB, err := domain.Open(domain.Config{
 		FS:              cfg.FS,
+		ColdFS:          cfg.ColdFS,
 		Instrumentation: cfg.Instrumentation,
 		FileSize:        cfg.FileSize,
 		GCThreshold:     cfg.GCThreshold,
+		Variable:        cfg.Channel.DataType.IsVariable(),
+		DataType:        cfg.Channel.DataType,
+		BlockCache:      cfg.BlockCache,
 	})
+	if err != nil {
+		return nil, err
+	}
 	c, err := controller.New[*controlledWriter](controller.Config{
 		Concurrency:     cfg.Channel.Concurrency,
 		Instrumentation: cfg.Instrumentation,
@@ -119,6 +136,7 @@ func Open(configs ...Config) (*DB, error) {
 		wrapError:        wrapError,
 		closed:           &atomic.Bool{},
 		leadingAlignment: &atomic.Uint32{},
+		quotaExceeded:    &atomic.Bool{},
 	}
 	db.leadingAlignment.Store(telem.ZeroLeadingAlignment)
 	if cfg.Channel.IsIndex {
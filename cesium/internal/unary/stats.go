@@ -0,0 +1,102 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package unary
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/cesium/internal/domain"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Stats returns a summary of the channel's samples in the given time range. Domains
+// that are fully contained in the time range are summarized using the summaries
+// persisted by the underlying domain DB, so only the data of domains that partially
+// overlap with the time range, or that have not been summarized, is read. Stats
+// returns an error if the channel's data type is not numeric.
+func (db *DB) Stats(ctx context.Context, tr telem.TimeRange) (s domain.Summary, err error) {
+	if db.closed.Load() {
+		return s, ErrDBClosed
+	}
+	if err = db.validateSummarizable(); err != nil {
+		return s, err
+	}
+	i := db.domain.OpenIterator(domain.IterRange(tr))
+	defer func() { err = errors.CombineErrors(err, db.wrapError(i.Close())) }()
+	for ok := i.SeekFirst(ctx); ok; ok = i.Next() {
+		if ds, summarized := i.Summary(); summarized && tr.ContainsRange(i.TimeRange()) {
+			s = s.Merge(ds)
+			continue
+		}
+		ds, err := db.summarizeRange(ctx, i.TimeRange().Intersection(tr))
+		if err != nil {
+			return s, err
+		}
+		s = s.Merge(ds)
+	}
+	return s, nil
+}
+
+// CandidateDomains returns the time ranges of all domains in the DB that overlap with
+// the given time range, and that may contain samples matching a search. mayMatch is
+// called with the summary of each summarized domain, and should return false if no
+// sample within the summarized bounds can match, allowing the domain to be skipped.
+// Domains that have not been summarized are always returned.
+func (db *DB) CandidateDomains(
+	ctx context.Context,
+	tr telem.TimeRange,
+	mayMatch func(domain.Summary) bool,
+) ([]telem.TimeRange, error) {
+	if db.closed.Load() {
+		return nil, ErrDBClosed
+	}
+	if err := db.validateSummarizable(); err != nil {
+		return nil, err
+	}
+	var (
+		ranges []telem.TimeRange
+		i      = db.domain.OpenIterator(domain.IterRange(tr))
+	)
+	for ok := i.SeekFirst(ctx); ok; ok = i.Next() {
+		if s, summarized := i.Summary(); summarized && !mayMatch(s) {
+			continue
+		}
+		ranges = append(ranges, i.TimeRange())
+	}
+	return ranges, db.wrapError(i.Close())
+}
+
+func (db *DB) validateSummarizable() error {
+	if dt := db.cfg.Channel.DataType; !domain.Summarizable(dt) {
+		return db.wrapError(errors.Wrapf(
+			validate.Error,
+			"cannot compute statistics for channel with non-numeric data type %s",
+			dt,
+		))
+	}
+	return nil
+}
+
+// summarizeRange reads and summarizes the channel's samples in the given time range.
+func (db *DB) summarizeRange(ctx context.Context, tr telem.TimeRange) (s domain.Summary, err error) {
+	i := db.OpenIterator(IterRange(tr))
+	defer func() { err = errors.CombineErrors(err, i.Close()) }()
+	if !i.SeekFirst(ctx) {
+		return s, i.Error()
+	}
+	for i.Next(ctx, telem.TimeSpanMax) {
+		for _, series := range i.Value().Series {
+			s = s.Merge(domain.Summarize(series.DataType, series.Data))
+		}
+	}
+	return s, i.Error()
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package unary_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	"github.com/synnaxlabs/cesium/internal/unary"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Stats", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS:"+fsName, func() {
+			var (
+				db      *unary.DB
+				indexDB *unary.DB
+				fs      xfs.FS
+				cleanUp func() error
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				indexDB = MustSucceed(unary.Open(unary.Config{
+					FS:        MustSucceed(fs.Sub("index")),
+					MetaCodec: codec,
+					Channel: core.Channel{
+						Key:      1,
+						DataType: telem.TimeStampT,
+						IsIndex:  true,
+						Index:    1,
+					},
+					Instrumentation: PanicLogger(),
+				}))
+				db = MustSucceed(unary.Open(unary.Config{
+					FS:        MustSucceed(fs.Sub("data")),
+					MetaCodec: codec,
+					Channel: core.Channel{
+						Key:      2,
+						DataType: telem.Float64T,
+						Index:    1,
+					},
+					Instrumentation: PanicLogger(),
+				}))
+				db.SetIndex(indexDB.Index())
+				Expect(unary.Write(ctx, indexDB, 10*telem.SecondTS, telem.NewSecondsTSV(10, 11, 12, 13, 14))).To(Succeed())
+				Expect(unary.Write(ctx, db, 10*telem.SecondTS, telem.NewSeriesV[float64](1, 2, 3, 4, 5))).To(Succeed())
+				Expect(unary.Write(ctx, indexDB, 20*telem.SecondTS, telem.NewSecondsTSV(20, 21, 22))).To(Succeed())
+				Expect(unary.Write(ctx, db, 20*telem.SecondTS, telem.NewSeriesV[float64](100, -100, 50))).To(Succeed())
+				Expect(unary.Write(ctx, indexDB, 30*telem.SecondTS, telem.NewSecondsTSV(30, 31))).To(Succeed())
+				Expect(unary.Write(ctx, db, 30*telem.SecondTS, telem.NewSeriesV[float64](7, 8))).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(indexDB.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			Describe("Stats", func() {
+				It("Should compute statistics over all domains", func() {
+					Expect(db.Stats(ctx, telem.TimeRangeMax)).To(Equal(domain.Summary{
+						Count: 10,
+						Min:   -100,
+						Max:   100,
+						Sum:   80,
+					}))
+				})
+
+				It("Should only include the samples of partial domains in the range", func() {
+					Expect(db.Stats(ctx, (12 * telem.SecondTS).Range(31*telem.SecondTS))).To(Equal(domain.Summary{
+						Count: 7,
+						Min:   -100,
+						Max:   100,
+						Sum:   69,
+					}))
+				})
+
+				It("Should compute statistics for a range within a single domain", func() {
+					Expect(db.Stats(ctx, (11 * telem.SecondTS).Range(13*telem.SecondTS))).To(Equal(domain.Summary{
+						Count: 2,
+						Min:   2,
+						Max:   3,
+						Sum:   5,
+					}))
+				})
+
+				It("Should return an empty summary for a range with no data", func() {
+					Expect(db.Stats(ctx, (40 * telem.SecondTS).Range(50*telem.SecondTS))).To(Equal(domain.Summary{}))
+				})
+
+				It("Should compute statistics after data is deleted", func() {
+					Expect(db.Delete(ctx, (21 * telem.SecondTS).Range(22*telem.SecondTS))).To(Succeed())
+					Expect(db.Stats(ctx, telem.TimeRangeMax)).To(Equal(domain.Summary{
+						Count: 9,
+						Min:   1,
+						Max:   100,
+						Sum:   180,
+					}))
+				})
+
+				It("Should not compute statistics for non-numeric channels", func() {
+					Expect(indexDB.Stats(ctx, telem.TimeRangeMax)).Error().To(HaveOccurredAs(validate.Error))
+				})
+			})
+
+			Describe("CandidateDomains", func() {
+				It("Should skip domains whose summaries cannot match", func() {
+					Expect(db.CandidateDomains(ctx, telem.TimeRangeMax, func(s domain.Summary) bool {
+						return s.Max > 50
+					})).To(Equal([]telem.TimeRange{(20 * telem.SecondTS).Range(22*telem.SecondTS + 1)}))
+				})
+
+				It("Should return domains that have not been summarized", func() {
+					Expect(db.Delete(ctx, (12 * telem.SecondTS).Range(13*telem.SecondTS))).To(Succeed())
+					Expect(db.CandidateDomains(ctx, telem.TimeRangeMax, func(domain.Summary) bool {
+						return false
+					})).To(Equal([]telem.TimeRange{
+						(10 * telem.SecondTS).Range(12 * telem.SecondTS),
+						(13 * telem.SecondTS).Range(14*telem.SecondTS + 1),
+					}))
+				})
+			})
+		})
+	}
+})
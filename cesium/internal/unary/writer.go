@@ -23,6 +23,7 @@ import (
 	"github.com/synnaxlabs/x/override"
 	"github.com/synnaxlabs/x/telem"
 	"github.com/synnaxlabs/x/validate"
+	"sync/atomic"
 )
 
 type WriterConfig struct {
@@ -58,6 +59,10 @@ type WriterConfig struct {
 	// [OPTIONAL] - Defaults to false
 	ErrOnUnauthorized    *bool
 	AlignmentDomainIndex uint32
+	// ConflictPolicy determines how the writer handles writes that overlap with
+	// existing data. See the domain.ConflictPolicy documentation for more.
+	// [OPTIONAL] - Defaults to domain.ConflictFail.
+	ConflictPolicy domain.ConflictPolicy
 }
 
 var (
@@ -67,6 +72,7 @@ var (
 		EnableAutoCommit:         config.False(),
 		AutoIndexPersistInterval: 1 * telem.Second,
 		ErrOnUnauthorized:        config.False(),
+		ConflictPolicy:           domain.ConflictFail,
 	}
 	errWriterClosed = core.EntityClosed("unary.writer")
 )
@@ -91,11 +97,18 @@ func (c WriterConfig) Override(other WriterConfig) WriterConfig {
 	c.AutoIndexPersistInterval = override.Zero(c.AutoIndexPersistInterval, other.AutoIndexPersistInterval)
 	c.ErrOnUnauthorized = override.Nil(c.ErrOnUnauthorized, other.ErrOnUnauthorized)
 	c.AlignmentDomainIndex = override.Numeric(c.AlignmentDomainIndex, other.AlignmentDomainIndex)
+	c.ConflictPolicy = override.Numeric(c.ConflictPolicy, other.ConflictPolicy)
 	return c
 }
 
 func (c WriterConfig) domain() domain.WriterConfig {
-	return domain.WriterConfig{Start: c.Start, End: c.End, EnableAutoCommit: c.EnableAutoCommit, AutoIndexPersistInterval: c.AutoIndexPersistInterval}
+	return domain.WriterConfig{
+		Start:                    c.Start,
+		End:                      c.End,
+		EnableAutoCommit:         c.EnableAutoCommit,
+		AutoIndexPersistInterval: c.AutoIndexPersistInterval,
+		ConflictPolicy:           c.ConflictPolicy,
+	}
 }
 
 func (c WriterConfig) controlTimeRange() telem.TimeRange {
@@ -141,6 +154,9 @@ type Writer struct {
 	// closed stores whether the writer is closed. Operations like Write and Commit do not
 	// succeed on closed writers.
 	closed bool
+	// quotaExceeded is shared with the unaryDB, and stores whether persisted writes
+	// should be refused because a storage quota is exceeded.
+	quotaExceeded *atomic.Bool
 }
 
 func (db *DB) OpenWriter(ctx context.Context, cfgs ...WriterConfig) (
@@ -156,10 +172,11 @@ func (db *DB) OpenWriter(ctx context.Context, cfgs ...WriterConfig) (
 		return nil, transfer, err
 	}
 	w = &Writer{
-		cfg:       cfg,
-		Channel:   db.cfg.Channel,
-		idx:       db.index(),
-		wrapError: db.wrapError,
+		cfg:           cfg,
+		Channel:       db.cfg.Channel,
+		idx:           db.index(),
+		wrapError:     db.wrapError,
+		quotaExceeded: db.quotaExceeded,
 	}
 	gateCfg := controller.GateConfig{
 		TimeRange: cfg.controlTimeRange(),
@@ -167,8 +184,16 @@ func (db *DB) OpenWriter(ctx context.Context, cfgs ...WriterConfig) (
 		Subject:   cfg.Subject,
 	}
 	var g *controller.Gate[*controlledWriter]
+	domainCfg := cfg.domain()
+	if cfg.ConflictPolicy == domain.ConflictOverwrite {
+		domainCfg.Overwrite = domain.OverwriteConfig{
+			StartOffset: db.calculateStartOffset,
+			EndOffset:   db.calculateEndOffset,
+			Density:     db.cfg.Channel.DataType.Density(),
+		}
+	}
 	g, transfer, err = db.controller.OpenGateAndMaybeRegister(gateCfg, func() (*controlledWriter, error) {
-		dw, err := db.domain.OpenWriter(ctx, cfg.domain())
+		dw, err := db.domain.OpenWriter(ctx, domainCfg)
 		cw := &controlledWriter{
 			Writer:     dw,
 			channelKey: db.cfg.Channel.Key,
@@ -218,6 +243,9 @@ func Write(
 }
 
 func (w *Writer) len(dw *domain.Writer) int64 {
+	if w.Channel.DataType.IsVariable() {
+		return dw.SampleCount()
+	}
 	return w.Channel.DataType.Density().SampleCount(telem.Size(dw.Len()))
 }
 
@@ -226,9 +254,15 @@ func (w *Writer) Write(series telem.Series) (telem.AlignmentPair, error) {
 	if w.closed {
 		return 0, w.wrapError(errWriterClosed)
 	}
+	if *w.cfg.Persist && w.quotaExceeded.Load() {
+		return 0, w.wrapError(core.ErrQuotaExceeded)
+	}
 	if err := w.Channel.ValidateSeries(series); err != nil {
 		return 0, w.wrapError(err)
 	}
+	if series.DataType.IsVariable() && len(series.Data) > 0 && series.Data[len(series.Data)-1] != '\n' {
+		return 0, w.wrapError(errors.Wrap(validate.Error, "variable density samples must be newline terminated"))
+	}
 	dw, err := w.control.Authorize()
 	if err != nil {
 		return 0, w.wrapError(err)
@@ -245,6 +279,24 @@ func (w *Writer) Write(series telem.Series) (telem.AlignmentPair, error) {
 	return dw.alignment, w.wrapError(err)
 }
 
+// Restart begins a new domain starting at the given timestamp. All data written by the
+// writer must be committed before calling Restart. Restart is used by writers with the
+// domain.ConflictFillGaps policy to skip over existing data.
+func (w *Writer) Restart(start telem.TimeStamp) error {
+	if w.closed {
+		return w.wrapError(errWriterClosed)
+	}
+	dw, err := w.control.Authorize()
+	if err != nil {
+		return w.wrapError(err)
+	}
+	w.cfg.Start = start
+	if *w.cfg.Persist {
+		err = dw.Restart(start)
+	}
+	return w.wrapError(err)
+}
+
 func (w *Writer) DomainIndex() uint32 {
 	return w.control.PeekEntity().alignment.DomainIndex()
 }
@@ -26,7 +26,7 @@ import (
 
 func filterDataFiles(l []os.FileInfo) []os.FileInfo {
 	return lo.Filter(l, func(item os.FileInfo, _ int) bool {
-		return item.Name() != "counter.domain" && item.Name() != "index.domain" && item.Name() != "tombstone.domain" && item.Name() != "meta.json"
+		return item.Name() != "counter.domain" && item.Name() != "index.domain" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain" && item.Name() != "meta.json"
 	})
 }
 
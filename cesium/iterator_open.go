@@ -55,5 +55,5 @@ func (db *DB) newStreamIterator(cfg IteratorConfig) (*streamIterator, error) {
 		internal[i] = uDB.OpenIterator(unary.IteratorConfig{Bounds: cfg.Bounds, AutoChunkSize: cfg.AutoChunkSize})
 	}
 
-	return &streamIterator{internal: internal}, nil
+	return &streamIterator{internal: internal, concurrency: db.iterConcurrency}, nil
 }
@@ -11,6 +11,9 @@ package cesium
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+
 	"github.com/synnaxlabs/cesium/internal/core"
 	"github.com/synnaxlabs/cesium/internal/unary"
 	"github.com/synnaxlabs/x/confluence"
@@ -114,7 +117,12 @@ type streamIterator struct {
 	confluence.UnarySink[IteratorRequest]
 	confluence.AbstractUnarySource[IteratorResponse]
 	internal []*unary.Iterator
-	seqNum   int
+	// concurrency is the maximum number of internal iterators that execute a request
+	// at once.
+	concurrency int
+	// oks stores whether the last request succeeded on each internal iterator.
+	oks    []bool
+	seqNum int
 }
 
 type IteratorConfig struct {
@@ -175,8 +183,11 @@ func (s *streamIterator) exec(ctx context.Context, req IteratorRequest) (ok bool
 }
 
 func (s *streamIterator) execWithResponse(f func(i *unary.Iterator) bool, cmd IteratorCommand) (ok bool) {
-	for _, i := range s.internal {
-		if f(i) {
+	s.execAll(f)
+	// Frames are sent in the order of the channels once every iterator has finished,
+	// so the responses do not depend on which iterators finish first.
+	for j, i := range s.internal {
+		if s.oks[j] {
 			ok = true
 			s.Out.Inlet() <- IteratorResponse{
 				Variant: IteratorDataResponse,
@@ -190,14 +201,48 @@ func (s *streamIterator) execWithResponse(f func(i *unary.Iterator) bool, cmd It
 }
 
 func (s *streamIterator) execWithoutResponse(f func(i *unary.Iterator) bool) (ok bool) {
-	for _, i := range s.internal {
-		if f(i) {
+	s.execAll(f)
+	for _, iOk := range s.oks {
+		if iOk {
 			ok = true
 		}
 	}
 	return
 }
 
+// execAll calls f on every internal iterator, spreading the calls across a pool of at
+// most s.concurrency goroutines, and stores the result of each call in s.oks. Each
+// internal iterator is only ever accessed by one goroutine at a time.
+func (s *streamIterator) execAll(f func(i *unary.Iterator) bool) {
+	if len(s.oks) != len(s.internal) {
+		s.oks = make([]bool, len(s.internal))
+	}
+	workers := s.concurrency
+	if workers > len(s.internal) {
+		workers = len(s.internal)
+	}
+	if workers <= 1 {
+		for j, i := range s.internal {
+			s.oks[j] = f(i)
+		}
+		return
+	}
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for j := int(next.Add(1)) - 1; j < len(s.internal); j = int(next.Add(1)) - 1 {
+				s.oks[j] = f(s.internal[j])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func (s *streamIterator) error() error {
 	for _, i := range s.internal {
 		if err := i.Error(); err != nil {
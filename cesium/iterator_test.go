@@ -254,6 +254,55 @@ var _ = Describe("Iterator Behavior", func() {
 				})
 			})
 
+			Describe("Concurrency", func() {
+				It("Should return frames in channel order when reading channels concurrently", func() {
+					sub := MustSucceed(fs.Sub("concurrent-fs"))
+					subDB := MustSucceed(cesium.Open(
+						"",
+						cesium.WithFS(sub),
+						cesium.WithInstrumentation(PanicLogger()),
+						cesium.WithIteratorConcurrency(4),
+					))
+					var keys []cesium.ChannelKey
+					for j := 0; j < 8; j++ {
+						index := GenerateChannelKey()
+						data := GenerateChannelKey()
+						Expect(subDB.CreateChannel(
+							ctx,
+							cesium.Channel{Key: index, IsIndex: true, DataType: telem.TimeStampT},
+							cesium.Channel{Key: data, Index: index, DataType: telem.Int64T},
+						)).To(Succeed())
+						Expect(subDB.Write(ctx, 0, cesium.NewFrame(
+							[]cesium.ChannelKey{index, data},
+							[]telem.Series{
+								telem.NewSecondsTSV(0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+								telem.NewSeriesV[int64](0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+							},
+						))).To(Succeed())
+						// Order the data channels before the indexes, in reverse, so that the
+						// order of the frames differs from the order of channel creation.
+						keys = append([]cesium.ChannelKey{data}, append(keys, index)...)
+					}
+					for k := 0; k < 10; k++ {
+						i := MustSucceed(subDB.OpenIterator(cesium.IteratorConfig{Bounds: telem.TimeRangeMax, Channels: keys}))
+						Expect(i.SeekFirst()).To(BeTrue())
+						for _, start := range []telem.TimeStamp{0, 5 * telem.SecondTS} {
+							Expect(i.Next(5 * telem.Second)).To(BeTrue())
+							f := i.Value()
+							Expect(f.Keys).To(Equal(keys))
+							for j, key := range f.Keys {
+								Expect(f.Series[j].TimeRange.Start).To(Equal(start), "channel %d", key)
+								Expect(f.Series[j].Len()).To(Equal(int64(5)), "channel %d", key)
+							}
+						}
+						Expect(i.Next(5 * telem.Second)).To(BeFalse())
+						Expect(i.Close()).To(Succeed())
+					}
+					Expect(subDB.Close()).To(Succeed())
+					Expect(fs.Remove("concurrent-fs")).To(Succeed())
+				})
+			})
+
 			Describe("Close", func() {
 				It("Should not allow operations on a closed iterator", func() {
 					key := GenerateChannelKey()
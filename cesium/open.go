@@ -12,10 +12,12 @@ package cesium
 import (
 	"fmt"
 	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/domain"
 	"github.com/synnaxlabs/cesium/internal/unary"
 	"github.com/synnaxlabs/cesium/internal/virtual"
 	"github.com/synnaxlabs/x/errors"
 	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/observe"
 	"github.com/synnaxlabs/x/signal"
 	"github.com/synnaxlabs/x/validate"
 	"go.uber.org/zap"
@@ -29,6 +31,23 @@ import (
 // execution.
 func Open(dirname string, opts ...Option) (*DB, error) {
 	o := newOptions(dirname, opts...)
+	if o.coldTier != nil {
+		if err := o.coldTier.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if o.quotaCfg != nil {
+		if err := o.quotaCfg.validate(); err != nil {
+			return nil, err
+		}
+	}
+	var blockCache *domain.BlockCache
+	if o.cacheCfg != nil {
+		var err error
+		if blockCache, err = domain.NewBlockCache(*o.cacheCfg); err != nil {
+			return nil, err
+		}
+	}
 	if err := openFS(o); err != nil {
 		return nil, err
 	}
@@ -37,18 +56,23 @@ func Open(dirname string, opts ...Option) (*DB, error) {
 
 	sCtx, cancel := signal.Isolated(signal.WithInstrumentation(o.Instrumentation))
 
-	info, err := o.fs.List("")
-	if err != nil {
-		return nil, err
-	}
 	db := &DB{
 		options:    o,
-		unaryDBs:   make(map[core.ChannelKey]unary.DB, len(info)),
-		virtualDBs: make(map[core.ChannelKey]virtual.DB, len(info)),
 		relay:      newRelay(sCtx),
 		closed:     &atomic.Bool{},
 		shutdown:   signal.NewShutdown(sCtx, cancel),
+		blockCache: blockCache,
+	}
+	if err := db.recoverDirectories(); err != nil {
+		return nil, err
+	}
+	info, err := o.fs.List("")
+	if err != nil {
+		return nil, err
 	}
+	db.unaryDBs = make(map[core.ChannelKey]unary.DB, len(info))
+	db.virtualDBs = make(map[core.ChannelKey]virtual.DB, len(info))
+	db.quota.events = observe.New[QuotaEvent]()
 	for _, i := range info {
 		if i.IsDir() {
 			key, err := strconv.Atoi(i.Name())
@@ -73,6 +97,8 @@ func Open(dirname string, opts ...Option) (*DB, error) {
 	}
 
 	db.startGC(sCtx, o)
+	db.startColdTier(sCtx, o)
+	db.startQuotas(sCtx, o)
 
 	return db, nil
 }
@@ -100,13 +126,19 @@ func (db *DB) openUnary(ch Channel, fs xfs.FS) error {
 	if isOpen {
 		return nil
 	}
+	coldFS, err := db.coldFS(ch.Key)
+	if err != nil {
+		return err
+	}
 	u, err := unary.Open(unary.Config{
 		FS:              fs,
+		ColdFS:          coldFS,
 		MetaCodec:       db.metaCodec,
 		Channel:         ch,
 		Instrumentation: db.options.Instrumentation,
 		FileSize:        db.options.fileSize,
 		GCThreshold:     db.options.gcCfg.GCThreshold,
+		BlockCache:      db.blockCache,
 	})
 	if err != nil {
 		return err
@@ -115,18 +147,15 @@ func (db *DB) openUnary(ch Channel, fs xfs.FS) error {
 	// need to set the index on the unary database. Otherwise, we assume the database
 	// is self-indexing.
 	if u.Channel().Index != 0 && !u.Channel().IsIndex {
-		idxDB, ok := db.unaryDBs[u.Channel().Index]
-		if ok {
-			u.SetIndex(idxDB.Index())
-		}
 		err = db.openVirtualOrUnary(Channel{Key: u.Channel().Index})
 		if err != nil {
 			return err
 		}
-		idxDB, ok = db.unaryDBs[u.Channel().Index]
+		idxDB, ok := db.unaryDBs[u.Channel().Index]
 		if !ok {
 			return validate.FieldError{Field: "index", Message: fmt.Sprintf("index channel <%v> does not exist", u.Channel().Index)}
 		}
+		u.SetIndex(idxDB.Index())
 	}
 	db.unaryDBs[ch.Key] = *u
 	return nil
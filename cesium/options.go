@@ -10,6 +10,8 @@
 package cesium
 
 import (
+	"runtime"
+
 	"github.com/synnaxlabs/alamos"
 	"github.com/synnaxlabs/x/binary"
 	xfs "github.com/synnaxlabs/x/io/fs"
@@ -25,7 +27,13 @@ type options struct {
 	fs        xfs.FS
 	metaCodec binary.Codec
 	gcCfg     *GCConfig
+	coldTier  *ColdTierConfig
+	quotaCfg  *QuotaConfig
+	cacheCfg  *BlockCacheConfig
 	fileSize  telem.Size
+	// iterConcurrency is the maximum number of channels that an iterator reads from
+	// at once.
+	iterConcurrency int
 }
 
 func (o *options) Report() alamos.Report {
@@ -48,6 +56,18 @@ func mergeDefaultOptions(o *options) {
 	o.fs = override.Nil[xfs.FS](xfs.Default, o.fs)
 	o.gcCfg = override.Nil[*GCConfig](&DefaultGCConfig, o.gcCfg)
 	o.fileSize = override.Numeric(1*telem.Gigabyte, o.fileSize)
+	o.iterConcurrency = override.Numeric(runtime.NumCPU(), o.iterConcurrency)
+	if o.coldTier != nil {
+		coldTier := *o.coldTier
+		coldTier.TryInterval = override.Numeric(DefaultColdTierConfig.TryInterval, coldTier.TryInterval)
+		o.coldTier = &coldTier
+	}
+	if o.quotaCfg != nil {
+		quota := *o.quotaCfg
+		quota.WarnThreshold = override.Numeric(DefaultQuotaConfig.WarnThreshold, quota.WarnThreshold)
+		quota.TryInterval = override.Numeric(DefaultQuotaConfig.TryInterval, quota.TryInterval)
+		o.quotaCfg = &quota
+	}
 }
 
 func WithFS(fs xfs.FS) Option {
@@ -62,6 +82,44 @@ func WithGC(config *GCConfig) Option {
 	}
 }
 
+// WithColdTier configures a secondary filesystem that the database moves old data to
+// in the background. See ColdTierConfig for more details.
+// [OPTIONAL] Default: no cold tier
+func WithColdTier(config *ColdTierConfig) Option {
+	return func(o *options) {
+		o.coldTier = config
+	}
+}
+
+// WithQuota configures limits on the disk space used by the database, which are
+// enforced in the background by evicting old data. See QuotaConfig for more details.
+// [OPTIONAL] Default: no quotas
+func WithQuota(config *QuotaConfig) Option {
+	return func(o *options) {
+		o.quotaCfg = config
+	}
+}
+
+// WithBlockCache configures a cache of recently read blocks of data files that is
+// shared by all channels in the database. See BlockCacheConfig for more details.
+// [OPTIONAL] Default: no cache
+func WithBlockCache(config *BlockCacheConfig) Option {
+	return func(o *options) {
+		o.cacheCfg = config
+	}
+}
+
+// WithIteratorConcurrency sets the maximum number of channels that an iterator reads
+// from at once when executing a request. Iterators over many channels spread the reads
+// of each request across a pool of this many workers, and still return frames in the
+// order of their channels. Setting concurrency to 1 reads one channel at a time.
+// [OPTIONAL] Default: the number of CPUs
+func WithIteratorConcurrency(concurrency int) Option {
+	return func(o *options) {
+		o.iterConcurrency = concurrency
+	}
+}
+
 func WithInstrumentation(i alamos.Instrumentation) Option {
 	return func(o *options) {
 		o.Instrumentation = i
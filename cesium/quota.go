@@ -0,0 +1,448 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/unary"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/observe"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// ErrQuotaExceeded is returned when writing to a channel whose quota, or the quota of
+// the database, is exceeded and evicting old data could not free enough space.
+var ErrQuotaExceeded = core.ErrQuotaExceeded
+
+// QuotaConfig configures limits on the disk space used by the data files of the
+// database. When a quota is exceeded, the oldest data of the channels it applies to is
+// deleted and garbage collected until the quota is satisfied. Channels can set their
+// own quota with Channel.Quota, and can be protected from eviction with Channel.Pinned.
+// Writes are only refused when eviction cannot bring usage under a quota.
+type QuotaConfig struct {
+	// NodeLimit is the maximum total size of the data files of all channels in the
+	// database. If zero, only the quotas of individual channels are enforced.
+	// [OPTIONAL]
+	NodeLimit telem.Size
+	// WarnThreshold is the proportion of a quota that must be used before a
+	// QuotaApproached event is emitted. Must be in (0, 1].
+	// [OPTIONAL] Default: 0.9
+	WarnThreshold float32
+	// TryInterval is the interval of time between two checks of the quotas.
+	// [OPTIONAL] Default: 10 seconds
+	TryInterval time.Duration
+}
+
+// DefaultQuotaConfig is the default configuration for quotas, which does not limit the
+// total size of the database.
+var DefaultQuotaConfig = QuotaConfig{WarnThreshold: 0.9, TryInterval: 10 * time.Second}
+
+func (c QuotaConfig) validate() error {
+	v := validate.New("cesium.quota")
+	validate.NonNegative(v, "node_limit", c.NodeLimit)
+	validate.Positive(v, "warn_threshold", c.WarnThreshold)
+	validate.LessThanEq(v, "warn_threshold", c.WarnThreshold, 1)
+	return v.Error()
+}
+
+// QuotaEventVariant is the kind of change in the state of a quota that a QuotaEvent
+// reports.
+type QuotaEventVariant uint8
+
+const (
+	// QuotaApproached is emitted when usage passes the WarnThreshold of a quota.
+	QuotaApproached QuotaEventVariant = iota + 1
+	// QuotaEvicted is emitted when data is evicted to satisfy a quota.
+	QuotaEvicted
+	// QuotaExceeded is emitted when eviction cannot bring usage under a quota. Writes
+	// to the channels the quota applies to are refused with ErrQuotaExceeded.
+	QuotaExceeded
+	// QuotaRestored is emitted when usage returns under an exceeded quota, and writes
+	// are accepted again.
+	QuotaRestored
+)
+
+// QuotaEvent reports a change in the state of a quota.
+type QuotaEvent struct {
+	// Variant is the kind of the event.
+	Variant QuotaEventVariant
+	// Channel is the key of the channel the quota belongs to, or zero if the event is
+	// for the NodeLimit of the database.
+	Channel ChannelKey
+	// Usage is the size of the data files the quota applies to.
+	Usage telem.Size
+	// Limit is the size of the quota.
+	Limit telem.Size
+	// Evicted is the time range of the evicted data. Only set for QuotaEvicted.
+	Evicted telem.TimeRange
+}
+
+type quotaState uint8
+
+const (
+	quotaOK quotaState = iota
+	quotaApproaching
+	quotaExceeded
+)
+
+// QuotaEvents returns an observable that is notified of changes in the state of the
+// quotas of the database.
+func (db *DB) QuotaEvents() observe.Observable[QuotaEvent] { return db.quota.events }
+
+// EnforceQuotas checks the usage of every quota of the database, evicting the oldest
+// data of the channels that exceed their quota, and then the oldest data of the
+// database if it exceeds its NodeLimit. Writes to channels are refused if eviction
+// cannot satisfy their quotas. EnforceQuotas is a no-op if the database was not
+// configured with quotas.
+func (db *DB) EnforceQuotas(ctx context.Context) error {
+	if db.closed.Load() {
+		return errDBClosed
+	}
+	if db.quotaCfg == nil {
+		return nil
+	}
+	ctx, span := db.T.Debug(ctx, "enforce_quotas")
+	defer span.End()
+	db.quota.mu.Lock()
+	defer db.quota.mu.Unlock()
+	db.mu.RLock()
+	udbs := make([]unary.DB, 0, len(db.unaryDBs))
+	for _, udb := range db.unaryDBs {
+		udbs = append(udbs, udb)
+	}
+	db.mu.RUnlock()
+
+	var (
+		c         = errors.NewCatcher(errors.WithAggregation())
+		states    = make(map[ChannelKey]quotaState, len(udbs))
+		exceeded  = make(map[ChannelKey]bool, len(udbs))
+		nodeUsage telem.Size
+	)
+	for _, udb := range udbs {
+		ch := udb.Channel()
+		usage, err := udb.Size()
+		if err == nil && ch.Quota > 0 && usage > ch.Quota {
+			usage, err = db.evictChannel(ctx, udb, ch.Quota)
+		}
+		// The channel may have been deleted since the databases were collected.
+		if errors.Is(err, unary.ErrDBClosed) {
+			continue
+		}
+		c.Exec(func() error { return err })
+		if ch.Quota > 0 {
+			states[ch.Key] = db.updateQuotaState(ctx, ch.Key, usage, ch.Quota)
+			exceeded[ch.Key] = states[ch.Key] == quotaExceeded
+		}
+		nodeUsage += usage
+	}
+	nodeExceeded := false
+	if limit := db.quotaCfg.NodeLimit; limit > 0 {
+		if nodeUsage > limit {
+			var err error
+			nodeUsage, err = db.evictOldest(ctx, udbs, limit)
+			c.Exec(func() error { return err })
+		}
+		states[0] = db.updateQuotaState(ctx, 0, nodeUsage, limit)
+		nodeExceeded = states[0] == quotaExceeded
+	}
+	db.quota.states = states
+	for _, udb := range udbs {
+		udb.SetQuotaExceeded(nodeExceeded || exceeded[udb.Channel().Key])
+	}
+	return span.Error(c.Error())
+}
+
+// updateQuotaState computes the state of the quota with the given key from its usage,
+// and notifies observers if the state changed. It returns the new state.
+func (db *DB) updateQuotaState(
+	ctx context.Context,
+	key ChannelKey,
+	usage telem.Size,
+	limit telem.Size,
+) quotaState {
+	state := quotaOK
+	if usage > limit {
+		state = quotaExceeded
+	} else if float64(usage) >= float64(db.quotaCfg.WarnThreshold)*float64(limit) {
+		state = quotaApproaching
+	}
+	prev := db.quota.states[key]
+	e := QuotaEvent{Channel: key, Usage: usage, Limit: limit}
+	switch {
+	case state == quotaExceeded && prev != quotaExceeded:
+		e.Variant = QuotaExceeded
+	case prev == quotaExceeded && state != quotaExceeded:
+		e.Variant = QuotaRestored
+	case state == quotaApproaching && prev == quotaOK:
+		e.Variant = QuotaApproached
+	default:
+		return state
+	}
+	db.quota.events.Notify(ctx, e)
+	return state
+}
+
+// evictChannel brings the usage of a channel under the given limit by first reclaiming
+// the space of previously deleted data, and then evicting its oldest data. If the
+// channel is an index, the data of the channels it indexes is evicted as well. It
+// returns the usage of the channel after eviction.
+func (db *DB) evictChannel(
+	ctx context.Context,
+	udb unary.DB,
+	limit telem.Size,
+) (usage telem.Size, err error) {
+	var (
+		ch      = udb.Channel()
+		evicted telem.TimeRange
+		keys    = []ChannelKey{ch.Key}
+	)
+	defer func() { db.notifyEvicted(ctx, ch.Key, usage, limit, evicted) }()
+	if ch.IsIndex {
+		dependents, evictable := db.dependents(ch.Key)
+		if !evictable {
+			return udb.Size()
+		}
+		keys = append(keys, dependents...)
+	}
+	for {
+		var extents []unary.Extent
+		if usage, extents, err = compactAndMeasure(ctx, udb); err != nil || usage <= limit || ch.Pinned {
+			return usage, err
+		}
+		// Any remaining space occupied by deleted data is reclaimed on a later try, so
+		// only evict enough live data to fit within the limit.
+		tr, ok := evictionRange(extents, liveSize(extents)-limit)
+		if !ok {
+			return usage, nil
+		}
+		if err = db.evict(ctx, keys, tr); err != nil {
+			return usage, err
+		}
+		evicted = extendEvicted(evicted, tr)
+	}
+}
+
+// evictOldest brings the total usage of the given databases under the given limit by
+// first reclaiming the space of previously deleted data, and then evicting the oldest
+// data across all channels that are not pinned. It returns the total usage after
+// eviction.
+func (db *DB) evictOldest(
+	ctx context.Context,
+	udbs []unary.DB,
+	limit telem.Size,
+) (usage telem.Size, err error) {
+	var evicted telem.TimeRange
+	defer func() { db.notifyEvicted(ctx, 0, usage, limit, evicted) }()
+	for {
+		var (
+			live      telem.Size
+			keys      []ChannelKey
+			evictable []unary.Extent
+		)
+		usage = 0
+		for _, udb := range udbs {
+			size, extents, err := compactAndMeasure(ctx, udb)
+			// The channel may have been deleted since the databases were collected.
+			if errors.Is(err, unary.ErrDBClosed) {
+				continue
+			}
+			if err != nil {
+				return usage, err
+			}
+			usage += size
+			live += liveSize(extents)
+			if db.evictable(udb.Channel()) {
+				keys = append(keys, udb.Channel().Key)
+				evictable = append(evictable, extents...)
+			}
+		}
+		if usage <= limit {
+			return usage, nil
+		}
+		tr, ok := evictionRange(evictable, live-limit)
+		if !ok {
+			return usage, nil
+		}
+		if err = db.evict(ctx, keys, tr); err != nil {
+			return usage, err
+		}
+		evicted = extendEvicted(evicted, tr)
+	}
+}
+
+// notifyEvicted notifies observers that the given time range was evicted to satisfy
+// the quota with the given key. It is a no-op if nothing was evicted.
+func (db *DB) notifyEvicted(
+	ctx context.Context,
+	key ChannelKey,
+	usage telem.Size,
+	limit telem.Size,
+	evicted telem.TimeRange,
+) {
+	if evicted.IsZero() {
+		return
+	}
+	db.quota.events.Notify(ctx, QuotaEvent{
+		Variant: QuotaEvicted,
+		Channel: key,
+		Usage:   usage,
+		Limit:   limit,
+		Evicted: evicted,
+	})
+}
+
+// extendEvicted extends the time range of evicted data with the time range of the next
+// eviction.
+func extendEvicted(evicted, next telem.TimeRange) telem.TimeRange {
+	if evicted.IsZero() {
+		return next
+	}
+	return evicted.Union(next)
+}
+
+// evict deletes the given time range from the channels with the given keys, and
+// garbage collects their files. The data channels are deleted from before the index
+// channels, so that indexes can be deleted along with the channels they index.
+func (db *DB) evict(ctx context.Context, keys []ChannelKey, tr telem.TimeRange) error {
+	db.mu.RLock()
+	udbs := make([]unary.DB, 0, len(keys))
+	for _, key := range keys {
+		if udb, ok := db.unaryDBs[key]; ok {
+			udbs = append(udbs, udb)
+		}
+	}
+	db.mu.RUnlock()
+	slices.SortStableFunc(udbs, func(a, b unary.DB) int {
+		if a.Channel().IsIndex == b.Channel().IsIndex {
+			return 0
+		}
+		if a.Channel().IsIndex {
+			return 1
+		}
+		return -1
+	})
+	c := errors.NewCatcher(errors.WithAggregation())
+	for _, udb := range udbs {
+		c.Exec(func() error {
+			err := db.DeleteTimeRange(ctx, []ChannelKey{udb.Channel().Key}, tr)
+			if err == nil {
+				err = udb.Compact(ctx)
+			}
+			if errors.Is(err, unary.ErrDBClosed) || errors.Is(err, ErrChannelNotFound) {
+				return nil
+			}
+			return err
+		})
+	}
+	return c.Error()
+}
+
+// evictable returns true if the data of the given channel can be evicted to satisfy
+// the NodeLimit of the database.
+func (db *DB) evictable(ch Channel) bool {
+	if ch.Pinned {
+		return false
+	}
+	if !ch.IsIndex {
+		return true
+	}
+	_, evictable := db.dependents(ch.Key)
+	return evictable
+}
+
+// dependents returns the keys of the channels indexed by the index channel with the
+// given key, and whether none of them are pinned.
+func (db *DB) dependents(key ChannelKey) ([]ChannelKey, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var keys []ChannelKey
+	for otherKey, udb := range db.unaryDBs {
+		ch := udb.Channel()
+		if otherKey == key || ch.Index != key {
+			continue
+		}
+		if ch.Pinned {
+			return nil, false
+		}
+		keys = append(keys, otherKey)
+	}
+	return keys, true
+}
+
+// compactAndMeasure reclaims the space of the deleted data of the given database, and
+// returns the size of its data files along with the extents of its domains.
+func compactAndMeasure(ctx context.Context, udb unary.DB) (telem.Size, []unary.Extent, error) {
+	if err := udb.Compact(ctx); err != nil {
+		return 0, nil, err
+	}
+	size, err := udb.Size()
+	if err != nil {
+		return 0, nil, err
+	}
+	extents, err := udb.Extents(ctx)
+	return size, extents, err
+}
+
+// liveSize returns the total size of the data in the given extents.
+func liveSize(extents []unary.Extent) (size telem.Size) {
+	for _, e := range extents {
+		size += e.Size
+	}
+	return size
+}
+
+// evictionRange returns the time range, starting at the oldest of the given extents,
+// whose deletion frees at least the given number of bytes. Data is assumed to be
+// spread evenly over the time range of each extent. It returns false if there is
+// nothing to evict.
+func evictionRange(extents []unary.Extent, size telem.Size) (telem.TimeRange, bool) {
+	if size <= 0 || len(extents) == 0 {
+		return telem.TimeRange{}, false
+	}
+	slices.SortFunc(extents, func(a, b unary.Extent) int {
+		return cmp.Compare(a.TimeRange.Start, b.TimeRange.Start)
+	})
+	var (
+		start = extents[0].TimeRange.Start
+		end   telem.TimeStamp
+		freed telem.Size
+	)
+	for _, e := range extents {
+		if freed+e.Size >= size {
+			fraction := float64(size-freed) / float64(e.Size)
+			span := math.Ceil(fraction * float64(e.TimeRange.Span()))
+			return start.Range(e.TimeRange.Start + telem.TimeStamp(span)), true
+		}
+		freed += e.Size
+		end = max(end, e.TimeRange.End)
+	}
+	return start.Range(end), true
+}
+
+func (db *DB) startQuotas(sCtx signal.Context, opts *options) {
+	if opts.quotaCfg == nil {
+		return
+	}
+	signal.GoTick(sCtx, opts.quotaCfg.TryInterval, func(ctx context.Context, _ time.Time) error {
+		if err := db.EnforceQuotas(ctx); err != nil {
+			db.L.Error("failed to enforce quotas", zap.Error(err))
+		}
+		return nil
+	}, signal.WithRetryOnPanic(10), signal.RecoverWithoutErrOnPanic())
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	. "github.com/synnaxlabs/cesium/internal/testutil"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Quota", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db      *cesium.DB
+				fs      xfs.FS
+				cleanUp func() error
+				mu      sync.Mutex
+				events  []cesium.QuotaEvent
+				open    = func(nodeLimit telem.Size) {
+					db = MustSucceed(cesium.Open("",
+						cesium.WithFS(fs),
+						cesium.WithQuota(&cesium.QuotaConfig{
+							NodeLimit:     nodeLimit,
+							WarnThreshold: 0.5,
+							TryInterval:   time.Hour,
+						}),
+						cesium.WithInstrumentation(PanicLogger()),
+					))
+					db.QuotaEvents().OnChange(func(_ context.Context, e cesium.QuotaEvent) {
+						mu.Lock()
+						defer mu.Unlock()
+						events = append(events, e)
+					})
+				}
+				variants = func() []cesium.QuotaEventVariant {
+					mu.Lock()
+					defer mu.Unlock()
+					v := make([]cesium.QuotaEventVariant, len(events))
+					for i, e := range events {
+						v[i] = e.Variant
+					}
+					return v
+				}
+				create = func(ch cesium.Channel) cesium.ChannelKey {
+					ch.Key = GenerateChannelKey()
+					ch.Rate = 1 * telem.Hz
+					ch.DataType = telem.Int64T
+					Expect(db.CreateChannel(ctx, ch)).To(Succeed())
+					return ch.Key
+				}
+				read = func(k cesium.ChannelKey) []int64 {
+					fr := MustSucceed(db.Read(ctx, telem.TimeRangeMax, k))
+					var values []int64
+					for _, s := range fr.Series {
+						values = append(values, telem.Unmarshal[int64](s)...)
+					}
+					return values
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				events = nil
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			Describe("Channel", func() {
+				BeforeEach(func() { open(0) })
+
+				It("Should evict the oldest data of a channel that exceeds its quota", func() {
+					k := create(cesium.Channel{Quota: 40 * telem.ByteSize})
+					Expect(db.WriteArray(ctx, k, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5, 6, 7, 8, 9, 10))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(k)).To(Equal([]int64{6, 7, 8, 9, 10}))
+					Expect(variants()).To(Equal([]cesium.QuotaEventVariant{cesium.QuotaEvicted, cesium.QuotaApproached}))
+					Expect(events[0].Channel).To(Equal(k))
+					Expect(events[0].Usage).To(Equal(40 * telem.ByteSize))
+					Expect(events[0].Evicted.Start).To(Equal(1 * telem.SecondTS))
+
+					By("Continuing to accept writes")
+					Expect(db.WriteArray(ctx, k, 11*telem.SecondTS, telem.NewSeriesV[int64](11, 12))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(k)).To(Equal([]int64{8, 9, 10, 11, 12}))
+				})
+
+				It("Should evict the data of the channels an index channel indexes", func() {
+					idx, data := GenerateChannelKey(), GenerateChannelKey()
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: idx, IsIndex: true, DataType: telem.TimeStampT, Quota: 40 * telem.ByteSize})).To(Succeed())
+					Expect(db.CreateChannel(ctx, cesium.Channel{Key: data, Index: idx, DataType: telem.Int64T})).To(Succeed())
+					Expect(db.Write(ctx, 1*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{idx, data},
+						[]telem.Series{
+							telem.NewSecondsTSV(1, 2, 3, 4, 5, 6, 7, 8),
+							telem.NewSeriesV[int64](1, 2, 3, 4, 5, 6, 7, 8),
+						},
+					))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(data)).To(Equal([]int64{4, 5, 6, 7, 8}))
+				})
+
+				It("Should refuse writes to a pinned channel that exceeds its quota", func() {
+					k := create(cesium.Channel{Quota: 16 * telem.ByteSize, Pinned: true})
+					Expect(db.WriteArray(ctx, k, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(k)).To(Equal([]int64{1, 2, 3}))
+					Expect(variants()).To(Equal([]cesium.QuotaEventVariant{cesium.QuotaExceeded}))
+					Expect(db.WriteArray(ctx, k, 10*telem.SecondTS, telem.NewSeriesV[int64](10))).
+						To(HaveOccurredAs(cesium.ErrQuotaExceeded))
+
+					By("Accepting writes once the data is deleted")
+					Expect(db.DeleteTimeRange(ctx, []cesium.ChannelKey{k}, (1 * telem.SecondTS).Range(3*telem.SecondTS))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(variants()).To(Equal([]cesium.QuotaEventVariant{cesium.QuotaExceeded, cesium.QuotaRestored}))
+					Expect(db.WriteArray(ctx, k, 10*telem.SecondTS, telem.NewSeriesV[int64](10))).To(Succeed())
+				})
+
+				It("Should not enforce the quota of channels without a quota", func() {
+					k := create(cesium.Channel{})
+					Expect(db.WriteArray(ctx, k, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(k)).To(Equal([]int64{1, 2, 3}))
+					Expect(variants()).To(BeEmpty())
+				})
+			})
+
+			Describe("Node", func() {
+				BeforeEach(func() { open(64 * telem.ByteSize) })
+
+				It("Should evict the oldest data across channels", func() {
+					oldest := create(cesium.Channel{})
+					newest := create(cesium.Channel{})
+					Expect(db.WriteArray(ctx, oldest, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5))).To(Succeed())
+					Expect(db.WriteArray(ctx, newest, 100*telem.SecondTS, telem.NewSeriesV[int64](100, 101, 102, 103, 104))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(oldest)).To(Equal([]int64{3, 4, 5}))
+					Expect(read(newest)).To(Equal([]int64{100, 101, 102, 103, 104}))
+					Expect(variants()).To(Equal([]cesium.QuotaEventVariant{cesium.QuotaEvicted, cesium.QuotaApproached}))
+					Expect(events[0].Channel).To(BeZero())
+				})
+
+				It("Should not evict the data of pinned channels", func() {
+					pinned := create(cesium.Channel{Pinned: true})
+					other := create(cesium.Channel{})
+					Expect(db.WriteArray(ctx, pinned, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5))).To(Succeed())
+					Expect(db.WriteArray(ctx, other, 100*telem.SecondTS, telem.NewSeriesV[int64](100, 101, 102, 103, 104))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(pinned)).To(Equal([]int64{1, 2, 3, 4, 5}))
+					Expect(read(other)).To(Equal([]int64{102, 103, 104}))
+				})
+
+				It("Should refuse writes when eviction cannot free enough space", func() {
+					pinned := create(cesium.Channel{Pinned: true})
+					other := create(cesium.Channel{})
+					Expect(db.WriteArray(ctx, pinned, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5, 6, 7, 8, 9))).To(Succeed())
+					Expect(db.WriteArray(ctx, other, 100*telem.SecondTS, telem.NewSeriesV[int64](100))).To(Succeed())
+					Expect(db.EnforceQuotas(ctx)).To(Succeed())
+					Expect(read(other)).To(BeEmpty())
+					Expect(variants()).To(Equal([]cesium.QuotaEventVariant{cesium.QuotaEvicted, cesium.QuotaExceeded}))
+					Expect(db.WriteArray(ctx, other, 200*telem.SecondTS, telem.NewSeriesV[int64](200))).
+						To(HaveOccurredAs(cesium.ErrQuotaExceeded))
+				})
+
+				It("Should enforce quotas in the background", func() {
+					Expect(db.Close()).To(Succeed())
+					db = MustSucceed(cesium.Open("",
+						cesium.WithFS(fs),
+						cesium.WithQuota(&cesium.QuotaConfig{
+							NodeLimit:   64 * telem.ByteSize,
+							TryInterval: 10 * time.Millisecond,
+						}),
+						cesium.WithInstrumentation(PanicLogger()),
+					))
+					k := create(cesium.Channel{})
+					Expect(db.WriteArray(ctx, k, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5, 6, 7, 8, 9, 10))).To(Succeed())
+					Eventually(func() []int64 { return read(k) }).Should(Equal([]int64{3, 4, 5, 6, 7, 8, 9, 10}))
+				})
+			})
+
+			It("Should not open a database with an invalid warn threshold", func() {
+				open(0)
+				_, err := cesium.Open("", cesium.WithFS(fs), cesium.WithQuota(&cesium.QuotaConfig{WarnThreshold: 2}))
+				Expect(err).To(HaveOccurredAs(validate.FieldError{
+					Field:   "warn_threshold",
+					Message: "must be less than or equal to 1",
+				}))
+			})
+		})
+	}
+})
@@ -0,0 +1,130 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/cesium/internal/domain"
+	"github.com/synnaxlabs/cesium/internal/meta"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+)
+
+type channelSnapshot struct {
+	ch     Channel
+	domain *domain.Snapshot
+}
+
+// Snapshot writes a consistent, point-in-time copy of every channel in the database
+// to the root of the provided file system. The resulting directory can be opened
+// as a standalone database using Open.
+//
+// Snapshot is safe to call while the database is being written to. It captures the
+// committed domains of all channels at once, and then copies their data while garbage
+// collection is paused. Any data committed after the capture is not included in the
+// snapshot. Channels cannot be deleted while a snapshot is in progress.
+func (db *DB) Snapshot(ctx context.Context, dst xfs.FS) error {
+	return db.SnapshotWithBarrier(ctx, dst, nil)
+}
+
+// SnapshotWithBarrier is identical to Snapshot, except that it calls barrier once the
+// committed domains of every channel have been captured, and before any further data
+// can be committed to or deleted from the database. Writers, as well as channel
+// creation and deletion, block until barrier returns, which allows the caller to
+// capture the state of another store at the same point in time as the snapshot. If
+// barrier returns an error, the snapshot is aborted and the error is returned.
+func (db *DB) SnapshotWithBarrier(
+	ctx context.Context,
+	dst xfs.FS,
+	barrier func() error,
+) (err error) {
+	if db.closed.Load() {
+		return errDBClosed
+	}
+	ctx, span := db.T.Prod(ctx, "snapshot")
+	defer func() { err = span.EndWith(err) }()
+
+	snapshots, virtualChannels, err := db.captureSnapshots(ctx, barrier)
+	defer func() {
+		c := errors.NewCatcher(errors.WithAggregation())
+		for _, s := range snapshots {
+			c.Exec(s.domain.Close)
+		}
+		err = errors.CombineErrors(err, c.Error())
+	}()
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range virtualChannels {
+		sub, err := dst.Sub(keyToDirName(ch.Key))
+		if err != nil {
+			return err
+		}
+		if err = meta.Create(sub, db.metaCodec, ch); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range snapshots {
+		sub, err := dst.Sub(keyToDirName(s.ch.Key))
+		if err != nil {
+			return err
+		}
+		if err = meta.Create(sub, db.metaCodec, s.ch); err != nil {
+			return err
+		}
+		if err = s.domain.WriteTo(ctx, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// captureSnapshots opens a domain snapshot on every unary channel in the database while
+// holding the database lock, guaranteeing that the set of captured channels is
+// consistent. Commits to every channel are blocked until all snapshots have been
+// opened and the provided barrier, if any, has returned. All returned snapshots must be
+// closed, even if an error is returned.
+func (db *DB) captureSnapshots(
+	ctx context.Context,
+	barrier func() error,
+) ([]channelSnapshot, []Channel, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var (
+		snapshots       = make([]channelSnapshot, 0, len(db.unaryDBs))
+		virtualChannels = make([]Channel, 0, len(db.virtualDBs))
+		thaws           = make([]func(), 0, len(db.unaryDBs))
+	)
+	defer func() {
+		for _, thaw := range thaws {
+			thaw()
+		}
+	}()
+	for _, u := range db.unaryDBs {
+		s, thaw, err := u.OpenFrozenSnapshot(ctx)
+		if err != nil {
+			return snapshots, virtualChannels, err
+		}
+		thaws = append(thaws, thaw)
+		snapshots = append(snapshots, channelSnapshot{ch: u.Channel(), domain: s})
+	}
+	for _, v := range db.virtualDBs {
+		virtualChannels = append(virtualChannels, v.Channel())
+	}
+	if barrier != nil {
+		if err := barrier(); err != nil {
+			return snapshots, virtualChannels, err
+		}
+	}
+	return snapshots, virtualChannels, nil
+}
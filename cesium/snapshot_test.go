@@ -0,0 +1,148 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	"github.com/synnaxlabs/cesium/internal/testutil"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Snapshot", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db         *cesium.DB
+				fs, dstFS  xfs.FS
+				cleanUp    func() error
+				dstCleanUp func() error
+				index      cesium.ChannelKey
+				data       cesium.ChannelKey
+				virtual    cesium.ChannelKey
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				dstFS, dstCleanUp = makeFS()
+				db = openDBOnFS(fs)
+				index = testutil.GenerateChannelKey()
+				data = testutil.GenerateChannelKey()
+				virtual = testutil.GenerateChannelKey()
+				Expect(db.CreateChannel(
+					ctx,
+					cesium.Channel{Key: index, IsIndex: true, DataType: telem.TimeStampT},
+					cesium.Channel{Key: data, Index: index, DataType: telem.Int64T},
+					cesium.Channel{Key: virtual, Virtual: true, DataType: telem.Int64T},
+				)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+				Expect(dstCleanUp()).To(Succeed())
+			})
+			It("Should write a copy of the database that can be opened independently", func() {
+				Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{index, data},
+					[]telem.Series{
+						telem.NewSecondsTSV(10, 11, 12, 13),
+						telem.NewSeriesV[int64](1, 2, 3, 4),
+					},
+				))).To(Succeed())
+				Expect(db.Snapshot(ctx, dstFS)).To(Succeed())
+
+				By("Writing more data after the snapshot")
+				Expect(db.Write(ctx, 20*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{index, data},
+					[]telem.Series{
+						telem.NewSecondsTSV(20, 21),
+						telem.NewSeriesV[int64](5, 6),
+					},
+				))).To(Succeed())
+
+				By("Opening the snapshot")
+				snapshotDB := openDBOnFS(dstFS)
+				chs := MustSucceed(snapshotDB.RetrieveChannels(ctx, index, data, virtual))
+				Expect(chs).To(HaveLen(3))
+				f := MustSucceed(snapshotDB.Read(ctx, telem.TimeRangeMax, data))
+				Expect(f.Series).To(HaveLen(1))
+				Expect(f.Series[0].Data).To(Equal(telem.NewSeriesV[int64](1, 2, 3, 4).Data))
+				Expect(snapshotDB.Close()).To(Succeed())
+			})
+			It("Should exclude data that has been deleted before the snapshot", func() {
+				Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{index, data},
+					[]telem.Series{
+						telem.NewSecondsTSV(10, 11, 12, 13),
+						telem.NewSeriesV[int64](1, 2, 3, 4),
+					},
+				))).To(Succeed())
+				Expect(db.DeleteTimeRange(
+					ctx,
+					[]cesium.ChannelKey{data},
+					(12 * telem.SecondTS).Range(14*telem.SecondTS),
+				)).To(Succeed())
+				Expect(db.Snapshot(ctx, dstFS)).To(Succeed())
+				snapshotDB := openDBOnFS(dstFS)
+				f := MustSucceed(snapshotDB.Read(ctx, telem.TimeRangeMax, data))
+				Expect(f.Series).To(HaveLen(1))
+				Expect(f.Series[0].Data).To(Equal(telem.NewSeriesV[int64](1, 2).Data))
+				Expect(snapshotDB.Close()).To(Succeed())
+			})
+			It("Should release all channels once the snapshot completes", func() {
+				Expect(db.Snapshot(ctx, dstFS)).To(Succeed())
+				Expect(db.DeleteChannel(data)).To(Succeed())
+			})
+			Describe("Barrier", func() {
+				It("Should block commits until the barrier returns", func() {
+					Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+						[]cesium.ChannelKey{index, data},
+						[]telem.Series{
+							telem.NewSecondsTSV(10, 11),
+							telem.NewSeriesV[int64](1, 2),
+						},
+					))).To(Succeed())
+					written := make(chan struct{})
+					Expect(db.SnapshotWithBarrier(ctx, dstFS, func() error {
+						go func() {
+							defer GinkgoRecover()
+							defer close(written)
+							Expect(db.Write(ctx, 20*telem.SecondTS, cesium.NewFrame(
+								[]cesium.ChannelKey{index, data},
+								[]telem.Series{
+									telem.NewSecondsTSV(20, 21),
+									telem.NewSeriesV[int64](3, 4),
+								},
+							))).To(Succeed())
+						}()
+						Consistently(written, "50ms").ShouldNot(BeClosed())
+						return nil
+					})).To(Succeed())
+					Eventually(written).Should(BeClosed())
+					snapshotDB := openDBOnFS(dstFS)
+					f := MustSucceed(snapshotDB.Read(ctx, telem.TimeRangeMax, data))
+					Expect(f.Series).To(HaveLen(1))
+					Expect(f.Series[0].Data).To(Equal(telem.NewSeriesV[int64](1, 2).Data))
+					Expect(snapshotDB.Close()).To(Succeed())
+				})
+				It("Should abort the snapshot and release all channels if the barrier fails", func() {
+					Expect(db.SnapshotWithBarrier(ctx, dstFS, func() error {
+						return errors.New("barrier failed")
+					})).To(MatchError(ContainSubstring("barrier failed")))
+					Expect(MustSucceed(dstFS.List(""))).To(BeEmpty())
+					Expect(db.DeleteChannel(data)).To(Succeed())
+				})
+			})
+		})
+	}
+})
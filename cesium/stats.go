@@ -0,0 +1,75 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/domain"
+	"github.com/synnaxlabs/cesium/internal/unary"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// Stats holds the number of samples of a channel in a time range, along with their
+// minimum, maximum, and sum. Min and Max are zero if there are no samples.
+type Stats = domain.Summary
+
+// Stats returns statistics over the samples of each of the given channels in the given
+// time range. Cesium maintains a summary of every domain of a numeric channel as it is
+// written, so the statistics are computed without reading the data of domains that
+// the time range fully contains. Stats returns an error if any of the channels is
+// virtual or not numeric.
+func (db *DB) Stats(
+	ctx context.Context,
+	tr telem.TimeRange,
+	keys ...ChannelKey,
+) (map[ChannelKey]Stats, error) {
+	if db.closed.Load() {
+		return nil, errDBClosed
+	}
+	ctx, span := db.T.Bench(ctx, "stats")
+	defer span.End()
+	udbs, err := db.unaryDBsFor(keys)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	stats := make(map[ChannelKey]Stats, len(keys))
+	for _, udb := range udbs {
+		s, err := udb.Stats(ctx, tr)
+		if err != nil {
+			return nil, span.Error(err)
+		}
+		stats[udb.Channel().Key] = s
+	}
+	return stats, nil
+}
+
+// unaryDBsFor returns the unary databases of the given channels.
+func (db *DB) unaryDBsFor(keys []ChannelKey) ([]unary.DB, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	udbs := make([]unary.DB, len(keys))
+	for i, key := range keys {
+		udb, ok := db.unaryDBs[key]
+		if !ok {
+			if vdb, vok := db.virtualDBs[key]; vok {
+				return nil, errors.Newf(
+					"cannot compute statistics for virtual channel %v",
+					vdb.Channel,
+				)
+			}
+			return nil, core.NewErrChannelNotFound(key)
+		}
+		udbs[i] = udb
+	}
+	return udbs, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	. "github.com/synnaxlabs/cesium/internal/testutil"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Stats", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db        *cesium.DB
+				fs        xfs.FS
+				cleanUp   func() error
+				index     = GenerateChannelKey()
+				data      = GenerateChannelKey()
+				rate      = GenerateChannelKey()
+				str       = GenerateChannelKey()
+				virtual   = GenerateChannelKey()
+				unwritten = GenerateChannelKey()
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = openDBOnFS(fs)
+				Expect(db.CreateChannel(
+					ctx,
+					cesium.Channel{Key: index, IsIndex: true, DataType: telem.TimeStampT},
+					cesium.Channel{Key: data, Index: index, DataType: telem.Float32T},
+					cesium.Channel{Key: rate, Rate: 1 * telem.Hz, DataType: telem.Uint16T},
+					cesium.Channel{Key: str, Rate: 1 * telem.Hz, DataType: telem.StringT},
+					cesium.Channel{Key: virtual, Virtual: true, DataType: telem.Int64T},
+					cesium.Channel{Key: unwritten, Rate: 1 * telem.Hz, DataType: telem.Int64T},
+				)).To(Succeed())
+				Expect(db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{index, data},
+					[]telem.Series{
+						telem.NewSecondsTSV(10, 11, 12, 13),
+						telem.NewSeriesV[float32](1.5, -2.5, 3.5, 4.5),
+					},
+				))).To(Succeed())
+				Expect(db.Write(ctx, 20*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{index, data},
+					[]telem.Series{
+						telem.NewSecondsTSV(20, 21),
+						telem.NewSeriesV[float32](10, 20),
+					},
+				))).To(Succeed())
+				Expect(db.WriteArray(ctx, rate, 10*telem.SecondTS, telem.NewSeriesV[uint16](5, 6, 7))).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			It("Should compute statistics for multiple channels", func() {
+				stats := MustSucceed(db.Stats(ctx, (11 * telem.SecondTS).Range(21*telem.SecondTS), data, rate, unwritten))
+				Expect(stats).To(Equal(map[cesium.ChannelKey]cesium.Stats{
+					data:      {Count: 4, Min: -2.5, Max: 10, Sum: 15.5},
+					rate:      {Count: 2, Min: 6, Max: 7, Sum: 13},
+					unwritten: {},
+				}))
+			})
+
+			It("Should compute statistics after reopening the database", func() {
+				Expect(db.Close()).To(Succeed())
+				db = openDBOnFS(fs)
+				Expect(db.Stats(ctx, telem.TimeRangeMax, data)).To(Equal(map[cesium.ChannelKey]cesium.Stats{
+					data: {Count: 6, Min: -2.5, Max: 20, Sum: 37},
+				}))
+			})
+
+			It("Should not compute statistics for a non-numeric channel", func() {
+				Expect(db.Stats(ctx, telem.TimeRangeMax, str)).Error().To(HaveOccurredAs(validate.Error))
+			})
+
+			It("Should not compute statistics for a virtual channel", func() {
+				Expect(db.Stats(ctx, telem.TimeRangeMax, virtual)).Error().To(MatchError(ContainSubstring("virtual")))
+			})
+
+			It("Should return an error if a channel does not exist", func() {
+				Expect(db.Stats(ctx, telem.TimeRangeMax, GenerateChannelKey())).Error().To(HaveOccurredAs(cesium.ErrChannelNotFound))
+			})
+		})
+	}
+})
@@ -0,0 +1,128 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"context"
+	"time"
+
+	"github.com/synnaxlabs/cesium/internal/unary"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// ColdTierConfig configures a secondary filesystem, typically larger and slower than
+// the primary one, that old data is moved to in the background. Data in the cold tier
+// remains readable, and can still be deleted and garbage collected.
+type ColdTierConfig struct {
+	// FS is the filesystem of the cold tier. The cold data of each channel is stored
+	// in a subdirectory named after its key. The database should have exclusive access
+	// to FS.
+	// [REQUIRED]
+	FS xfs.FS
+	// MoveAfter is the default age after which data is moved to the cold tier. A file
+	// is only moved once all of its data is older than the threshold. Channels can
+	// override the default by setting Channel.ColdAfter. If zero, only the data of
+	// channels that set ColdAfter is moved.
+	// [OPTIONAL]
+	MoveAfter telem.TimeSpan
+	// TryInterval is the interval of time between two tries of moving data to the cold
+	// tier.
+	// [OPTIONAL] Default: 1 minute
+	TryInterval time.Duration
+}
+
+// DefaultColdTierConfig is the default configuration for the cold tier, excluding its
+// filesystem.
+var DefaultColdTierConfig = ColdTierConfig{TryInterval: 1 * time.Minute}
+
+func (c ColdTierConfig) validate() error {
+	v := validate.New("cesium.cold_tier")
+	validate.NotNil(v, "fs", c.FS)
+	validate.NonNegative(v, "move_after", c.MoveAfter)
+	return v.Error()
+}
+
+// coldFS returns the filesystem that the cold data of the channel with the given key
+// is stored in, or nil if the database has no cold tier.
+func (db *DB) coldFS(key ChannelKey) (xfs.FS, error) {
+	if db.coldTier == nil {
+		return nil, nil
+	}
+	return db.coldTier.FS.Sub(keyToDirName(key))
+}
+
+// renameColdDir renames the directory of a channel in the cold tier, if it exists.
+func (db *DB) renameColdDir(oldName, newName string) error {
+	if db.coldTier == nil {
+		return nil
+	}
+	exists, err := db.coldTier.FS.Exists(oldName)
+	if err != nil || !exists {
+		return err
+	}
+	return db.coldTier.FS.Rename(oldName, newName)
+}
+
+// removeColdDir removes the directory of a channel in the cold tier, if it exists.
+func (db *DB) removeColdDir(name string) error {
+	if db.coldTier == nil {
+		return nil
+	}
+	return db.coldTier.FS.Remove(name)
+}
+
+// moveToColdTier moves the data of every channel that is older than the channel's
+// threshold at the given time to the cold tier.
+func (db *DB) moveToColdTier(ctx context.Context, now telem.TimeStamp) error {
+	_, span := db.T.Debug(ctx, "move_to_cold_tier")
+	defer span.End()
+	db.mu.RLock()
+	udbs := make([]unary.DB, 0, len(db.unaryDBs))
+	for _, udb := range db.unaryDBs {
+		udbs = append(udbs, udb)
+	}
+	db.mu.RUnlock()
+	c := errors.NewCatcher(errors.WithAggregation())
+	for _, udb := range udbs {
+		after := udb.Channel().ColdAfter
+		if after == 0 {
+			after = db.coldTier.MoveAfter
+		}
+		if after == 0 {
+			continue
+		}
+		c.Exec(func() error {
+			err := udb.MoveToColdTier(ctx, now.Sub(after))
+			// The channel may have been deleted since the databases were collected.
+			if errors.Is(err, unary.ErrDBClosed) {
+				return nil
+			}
+			return err
+		})
+	}
+	return span.Error(c.Error())
+}
+
+func (db *DB) startColdTier(sCtx signal.Context, opts *options) {
+	if opts.coldTier == nil {
+		return
+	}
+	signal.GoTick(sCtx, opts.coldTier.TryInterval, func(ctx context.Context, t time.Time) error {
+		if err := db.moveToColdTier(ctx, telem.NewTimeStamp(t)); err != nil {
+			db.L.Error("failed to move data to the cold tier", zap.Error(err))
+		}
+		return nil
+	}, signal.WithRetryOnPanic(10), signal.RecoverWithoutErrOnPanic())
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	. "github.com/synnaxlabs/cesium/internal/testutil"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Cold Tier", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db                   *cesium.DB
+				fs, coldFS           xfs.FS
+				cleanUp, coldCleanUp func() error
+				key                  cesium.ChannelKey
+				coldFile             = func(k cesium.ChannelKey, file string) func() bool {
+					return func() bool {
+						return MustSucceed(coldFS.Exists(channelKeyToPath(k) + "/" + file))
+					}
+				}
+				read = func(k cesium.ChannelKey) []int64 {
+					fr := MustSucceed(db.Read(ctx, telem.TimeRangeMax, k))
+					var values []int64
+					for _, s := range fr.Series {
+						values = append(values, telem.Unmarshal[int64](s)...)
+					}
+					return values
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				coldFS, coldCleanUp = makeFS()
+				db = MustSucceed(cesium.Open("",
+					cesium.WithFS(fs),
+					cesium.WithFileSize(40*telem.ByteSize),
+					cesium.WithColdTier(&cesium.ColdTierConfig{
+						FS:          coldFS,
+						MoveAfter:   telem.Hour,
+						TryInterval: 10 * time.Millisecond,
+					}),
+					cesium.WithInstrumentation(PanicLogger()),
+				))
+				key = GenerateChannelKey()
+				Expect(db.CreateChannel(ctx, cesium.Channel{Key: key, Rate: 1 * telem.Hz, DataType: telem.Int64T})).To(Succeed())
+				Expect(db.WriteArray(ctx, key, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3, 4, 5))).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+				Expect(coldCleanUp()).To(Succeed())
+			})
+
+			It("Should move old data to the cold tier and keep it readable", func() {
+				Eventually(coldFile(key, "1.domain")).Should(BeTrue())
+				Expect(MustSucceed(fs.Exists(channelKeyToPath(key) + "/1.domain"))).To(BeFalse())
+				Expect(read(key)).To(Equal([]int64{1, 2, 3, 4, 5}))
+			})
+
+			It("Should keep writing new data to the primary filesystem", func() {
+				Eventually(coldFile(key, "1.domain")).Should(BeTrue())
+				Expect(db.WriteArray(ctx, key, 10*telem.SecondTS, telem.NewSeriesV[int64](10, 11))).To(Succeed())
+				Expect(read(key)).To(Equal([]int64{1, 2, 3, 4, 5, 10, 11}))
+			})
+
+			It("Should not move data of channels with a longer threshold", func() {
+				k := GenerateChannelKey()
+				Expect(db.CreateChannel(ctx, cesium.Channel{
+					Key:       k,
+					Rate:      1 * telem.Hz,
+					DataType:  telem.Int64T,
+					ColdAfter: telem.TimeSpan(telem.Now()) + telem.Hour,
+				})).To(Succeed())
+				Expect(db.WriteArray(ctx, k, 1*telem.SecondTS, telem.NewSeriesV[int64](1, 2))).To(Succeed())
+				Eventually(coldFile(key, "1.domain")).Should(BeTrue())
+				Consistently(coldFile(k, "1.domain"), 50*time.Millisecond).Should(BeFalse())
+			})
+
+			It("Should delete data in the cold tier when the channel is deleted", func() {
+				Eventually(coldFile(key, "1.domain")).Should(BeTrue())
+				// The channel can't be deleted while data is being moved.
+				Eventually(func() error { return db.DeleteChannel(key) }).Should(Succeed())
+				Expect(MustSucceed(coldFS.Exists(channelKeyToPath(key)))).To(BeFalse())
+			})
+
+			It("Should move data in the cold tier when the channel is rekeyed", func() {
+				Eventually(coldFile(key, "1.domain")).Should(BeTrue())
+				newKey := GenerateChannelKey()
+				Eventually(func() error { return db.RekeyChannel(key, newKey) }).Should(Succeed())
+				Expect(coldFile(newKey, "1.domain")()).To(BeTrue())
+				Expect(read(newKey)).To(Equal([]int64{1, 2, 3, 4, 5}))
+			})
+
+			It("Should not open a database with a cold tier without a filesystem", func() {
+				_, err := cesium.Open("", cesium.WithFS(fs), cesium.WithColdTier(&cesium.ColdTierConfig{}))
+				Expect(err).To(HaveOccurredAs(validate.FieldError{Field: "fs", Message: "must be non-nil"}))
+			})
+
+			It("Should not create a channel with a negative threshold", func() {
+				Expect(db.CreateChannel(ctx, cesium.Channel{
+					Key:       GenerateChannelKey(),
+					Rate:      1 * telem.Hz,
+					DataType:  telem.Int64T,
+					ColdAfter: -telem.Second,
+				})).To(HaveOccurredAs(validate.FieldError{Field: "cold_after", Message: "field must be non-negative"}))
+			})
+		})
+	}
+})
@@ -0,0 +1,181 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"context"
+	"sort"
+
+	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/unary"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// ChannelUsage is the disk usage of a channel. Virtual channels do not store any data,
+// so their usage is always zero.
+type ChannelUsage struct {
+	// Channel is the key of the channel.
+	Channel ChannelKey
+	// Size is the total size of the data files of the channel, including the space
+	// occupied by deleted data.
+	Size telem.Size
+	// Files is the number of data files of the channel.
+	Files int
+	// Reclaimable is the space occupied by deleted data that garbage collection can
+	// return to the filesystem.
+	Reclaimable telem.Size
+}
+
+// Usage returns the disk usage of each of the given channels, or of every channel in
+// the database if no keys are provided. The usages are sorted by channel key.
+func (db *DB) Usage(ctx context.Context, keys ...ChannelKey) ([]ChannelUsage, error) {
+	if db.closed.Load() {
+		return nil, errDBClosed
+	}
+	_, span := db.T.Bench(ctx, "usage")
+	defer span.End()
+	udbs, virtual, err := db.channelDBs(keys)
+	if err != nil {
+		return nil, span.Error(err)
+	}
+	usages := make([]ChannelUsage, 0, len(udbs)+len(virtual))
+	for _, udb := range udbs {
+		u, err := udb.Usage()
+		if err != nil {
+			return nil, span.Error(err)
+		}
+		usages = append(usages, ChannelUsage{
+			Channel:     udb.Channel().Key,
+			Size:        u.Size,
+			Files:       u.Files,
+			Reclaimable: u.Reclaimable,
+		})
+	}
+	for _, key := range virtual {
+		usages = append(usages, ChannelUsage{Channel: key})
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Channel < usages[j].Channel })
+	return usages, nil
+}
+
+// GCMode determines which files are rewritten by DB.GarbageCollect.
+type GCMode uint8
+
+const (
+	// GCModeThreshold only rewrites full files whose deleted data exceeds the
+	// GCThreshold of the database, in the same way as background garbage collection.
+	GCModeThreshold GCMode = iota
+	// GCModeCompact rewrites every file that holds deleted data, regardless of its
+	// size or the GCThreshold.
+	GCModeCompact
+)
+
+// GCProgress reports the progress of DB.GarbageCollect after it has finished with a
+// channel.
+type GCProgress struct {
+	// Channel is the key of the channel that was garbage collected.
+	Channel ChannelKey
+	// Completed is the number of channels that have been garbage collected so far,
+	// including Channel.
+	Completed int
+	// Total is the number of channels that will be garbage collected.
+	Total int
+	// Reclaimed is the space returned to the filesystem by garbage collecting Channel.
+	Reclaimed telem.Size
+}
+
+// GarbageCollect rewrites the data files of the given channels, or of every channel in
+// the database if no keys are provided, to return the space occupied by deleted data to
+// the filesystem. Channels are garbage collected one at a time, and onProgress, if not
+// nil, is called after each one. Virtual channels store no data and are ignored. Files
+// that have a reader or writer open on them are skipped, so some deleted data may
+// remain reclaimable afterward.
+func (db *DB) GarbageCollect(
+	ctx context.Context,
+	mode GCMode,
+	onProgress func(GCProgress),
+	keys ...ChannelKey,
+) error {
+	if db.closed.Load() {
+		return errDBClosed
+	}
+	ctx, span := db.T.Bench(ctx, "garbage_collect_channels")
+	defer span.End()
+	udbs, _, err := db.channelDBs(keys)
+	if err != nil {
+		return span.Error(err)
+	}
+	for i, udb := range udbs {
+		if err = ctx.Err(); err != nil {
+			return span.Error(err)
+		}
+		before, err := udb.Size()
+		if err != nil {
+			return span.Error(err)
+		}
+		if mode == GCModeCompact {
+			err = udb.Compact(ctx)
+		} else {
+			err = udb.GarbageCollect(ctx)
+		}
+		if err != nil {
+			return span.Error(err)
+		}
+		after, err := udb.Size()
+		if err != nil {
+			return span.Error(err)
+		}
+		if onProgress == nil {
+			continue
+		}
+		p := GCProgress{Channel: udb.Channel().Key, Completed: i + 1, Total: len(udbs)}
+		if before > after {
+			p.Reclaimed = before - after
+		}
+		onProgress(p)
+	}
+	return nil
+}
+
+// channelDBs returns the unary databases of the given channels, along with the keys of
+// those that are virtual. If no keys are provided, it returns the databases of every
+// channel, with the unary databases sorted by channel key.
+func (db *DB) channelDBs(keys []ChannelKey) ([]unary.DB, []ChannelKey, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var (
+		udbs    []unary.DB
+		virtual []ChannelKey
+	)
+	if len(keys) == 0 {
+		udbs = make([]unary.DB, 0, len(db.unaryDBs))
+		for _, udb := range db.unaryDBs {
+			udbs = append(udbs, udb)
+		}
+		for key := range db.virtualDBs {
+			virtual = append(virtual, key)
+		}
+		sort.Slice(udbs, func(i, j int) bool {
+			return udbs[i].Channel().Key < udbs[j].Channel().Key
+		})
+		return udbs, virtual, nil
+	}
+	udbs = make([]unary.DB, 0, len(keys))
+	for _, key := range keys {
+		if udb, ok := db.unaryDBs[key]; ok {
+			udbs = append(udbs, udb)
+		} else if _, ok = db.virtualDBs[key]; ok {
+			virtual = append(virtual, key)
+		} else {
+			return nil, nil, core.NewErrChannelNotFound(key)
+		}
+	}
+	return udbs, virtual, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	. "github.com/synnaxlabs/cesium/internal/testutil"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Usage", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db      *cesium.DB
+				fs      xfs.FS
+				cleanUp func() error
+				basic   = GenerateChannelKey()
+				other   = GenerateChannelKey()
+				virtual = GenerateChannelKey()
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = openDBOnFS(fs)
+				Expect(db.CreateChannel(
+					ctx,
+					cesium.Channel{Key: basic, Rate: 1 * telem.Hz, DataType: telem.Int64T},
+					cesium.Channel{Key: other, Rate: 1 * telem.Hz, DataType: telem.Int64T},
+					cesium.Channel{Key: virtual, Virtual: true, DataType: telem.Int64T},
+				)).To(Succeed())
+				Expect(db.WriteArray(ctx, basic, 10*telem.SecondTS, telem.NewSeriesV[int64](10, 11, 12, 13, 14, 15, 16, 17, 18, 19))).To(Succeed())
+				Expect(db.WriteArray(ctx, other, 10*telem.SecondTS, telem.NewSeriesV[int64](1, 2, 3))).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			Describe("Usage", func() {
+				It("Should report the usage of every channel", func() {
+					usages := MustSucceed(db.Usage(ctx))
+					Expect(usages).To(HaveLen(3))
+					Expect(usages).To(ContainElements(
+						cesium.ChannelUsage{Channel: basic, Size: 80, Files: 1},
+						cesium.ChannelUsage{Channel: other, Size: 24, Files: 1},
+						cesium.ChannelUsage{Channel: virtual},
+					))
+					Expect(usages[0].Channel < usages[1].Channel).To(BeTrue())
+					Expect(usages[1].Channel < usages[2].Channel).To(BeTrue())
+				})
+
+				It("Should report deleted data as reclaimable", func() {
+					Expect(db.DeleteTimeRange(ctx, []cesium.ChannelKey{basic}, (12 * telem.SecondTS).Range(15*telem.SecondTS))).To(Succeed())
+					Expect(db.Usage(ctx, basic)).To(ConsistOf(
+						cesium.ChannelUsage{Channel: basic, Size: 80, Files: 1, Reclaimable: 24},
+					))
+				})
+
+				It("Should return an error if a channel does not exist", func() {
+					Expect(db.Usage(ctx, 9999)).Error().To(MatchError(cesium.ErrChannelNotFound))
+				})
+			})
+
+			Describe("GarbageCollect", func() {
+				It("Should compact the given channels and report progress", func() {
+					Expect(db.DeleteTimeRange(ctx, []cesium.ChannelKey{basic}, (12 * telem.SecondTS).Range(15*telem.SecondTS))).To(Succeed())
+					Expect(db.DeleteTimeRange(ctx, []cesium.ChannelKey{other}, (10 * telem.SecondTS).Range(11*telem.SecondTS))).To(Succeed())
+					var progress []cesium.GCProgress
+					Expect(db.GarbageCollect(ctx, cesium.GCModeCompact, func(p cesium.GCProgress) {
+						progress = append(progress, p)
+					}, basic, virtual)).To(Succeed())
+					Expect(progress).To(Equal([]cesium.GCProgress{
+						{Channel: basic, Completed: 1, Total: 1, Reclaimed: 24},
+					}))
+					Expect(db.Usage(ctx, basic, other)).To(ConsistOf(
+						cesium.ChannelUsage{Channel: basic, Size: 56, Files: 1},
+						cesium.ChannelUsage{Channel: other, Size: 24, Files: 1, Reclaimable: 8},
+					))
+
+					By("Reading the remaining data back")
+					f := MustSucceed(db.Read(ctx, telem.TimeRangeMax, basic))
+					Expect(f.Series).To(HaveLen(2))
+					Expect(f.Series[0].Data).To(Equal(telem.NewSeriesV[int64](10, 11).Data))
+					Expect(f.Series[1].Data).To(Equal(telem.NewSeriesV[int64](15, 16, 17, 18, 19).Data))
+				})
+
+				It("Should garbage collect every channel when no keys are given", func() {
+					var progress []cesium.GCProgress
+					Expect(db.GarbageCollect(ctx, cesium.GCModeThreshold, func(p cesium.GCProgress) {
+						progress = append(progress, p)
+					})).To(Succeed())
+					Expect(progress).To(HaveLen(2))
+					Expect(progress[1].Completed).To(Equal(2))
+					Expect(progress[1].Total).To(Equal(2))
+				})
+
+				It("Should return an error if a channel does not exist", func() {
+					Expect(db.GarbageCollect(ctx, cesium.GCModeCompact, nil, 9999)).To(MatchError(cesium.ErrChannelNotFound))
+				})
+			})
+		})
+	}
+})
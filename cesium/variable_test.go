@@ -0,0 +1,154 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium_test
+
+import (
+	"os"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	. "github.com/synnaxlabs/cesium/internal/testutil"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Variable Density Channels", func() {
+	for fsName, makeFS := range fileSystems {
+		Context("FS: "+fsName, func() {
+			var (
+				db              *cesium.DB
+				fs              xfs.FS
+				cleanUp         func() error
+				idxKey, logKey  cesium.ChannelKey
+				strings         = func(s telem.Series) []string { return telem.UnmarshalStrings(s.Data) }
+				framesToStrings = func(fr cesium.Frame, key cesium.ChannelKey) (out []string) {
+					for _, s := range fr.Get(key) {
+						out = append(out, strings(s)...)
+					}
+					return out
+				}
+			)
+			BeforeEach(func() {
+				fs, cleanUp = makeFS()
+				db = openDBOnFS(fs)
+				idxKey, logKey = GenerateChannelKey(), GenerateChannelKey()
+				Expect(db.CreateChannel(
+					ctx,
+					cesium.Channel{Key: idxKey, IsIndex: true, DataType: telem.TimeStampT},
+					cesium.Channel{Key: logKey, Index: idxKey, DataType: telem.StringT},
+				)).To(Succeed())
+				Expect(db.Write(ctx, 1*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{idxKey, logKey},
+					[]telem.Series{
+						telem.NewSecondsTSV(1, 2, 3, 4, 5),
+						telem.NewStringsV("valve open", "", "pressure nominal", "ignition", "😀 done"),
+					},
+				))).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(db.Close()).To(Succeed())
+				Expect(cleanUp()).To(Succeed())
+			})
+
+			It("Should read variable density data", func() {
+				fr := MustSucceed(db.Read(ctx, telem.TimeRangeMax, logKey))
+				Expect(framesToStrings(fr, logKey)).To(Equal([]string{
+					"valve open", "", "pressure nominal", "ignition", "😀 done",
+				}))
+			})
+
+			It("Should read a time range that starts and ends within a domain", func() {
+				fr := MustSucceed(db.Read(ctx, (2 * telem.SecondTS).Range(4*telem.SecondTS), logKey))
+				Expect(framesToStrings(fr, logKey)).To(Equal([]string{"", "pressure nominal"}))
+			})
+
+			It("Should iterate over variable density data by time span", func() {
+				i := MustSucceed(db.OpenIterator(cesium.IteratorConfig{
+					Bounds:   telem.TimeRangeMax,
+					Channels: []cesium.ChannelKey{logKey},
+				}))
+				Expect(i.SeekFirst()).To(BeTrue())
+				Expect(i.Next(2 * telem.Second)).To(BeTrue())
+				Expect(framesToStrings(i.Value(), logKey)).To(Equal([]string{"valve open", ""}))
+				Expect(i.Next(2 * telem.Second)).To(BeTrue())
+				Expect(framesToStrings(i.Value(), logKey)).To(Equal([]string{"pressure nominal", "ignition"}))
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should chunk variable density data by sample count", func() {
+				i := MustSucceed(db.OpenIterator(cesium.IteratorConfig{
+					Bounds:        telem.TimeRangeMax,
+					Channels:      []cesium.ChannelKey{logKey},
+					AutoChunkSize: 2,
+				}))
+				Expect(i.SeekFirst()).To(BeTrue())
+				Expect(i.Next(cesium.AutoSpan)).To(BeTrue())
+				Expect(framesToStrings(i.Value(), logKey)).To(Equal([]string{"valve open", ""}))
+				Expect(i.Next(cesium.AutoSpan)).To(BeTrue())
+				Expect(framesToStrings(i.Value(), logKey)).To(Equal([]string{"pressure nominal", "ignition"}))
+				Expect(i.Next(cesium.AutoSpan)).To(BeTrue())
+				Expect(framesToStrings(i.Value(), logKey)).To(Equal([]string{"😀 done"}))
+				Expect(i.Next(cesium.AutoSpan)).To(BeFalse())
+				Expect(i.Close()).To(Succeed())
+			})
+
+			It("Should delete a time range of variable density data", func() {
+				Expect(db.DeleteTimeRange(ctx, []cesium.ChannelKey{logKey}, (2 * telem.SecondTS).Range(4*telem.SecondTS))).To(Succeed())
+				fr := MustSucceed(db.Read(ctx, telem.TimeRangeMax, logKey))
+				Expect(framesToStrings(fr, logKey)).To(Equal([]string{"valve open", "ignition", "😀 done"}))
+			})
+
+			It("Should persist variable density data across restarts", func() {
+				Expect(db.Close()).To(Succeed())
+				db = openDBOnFS(fs)
+				fr := MustSucceed(db.Read(ctx, (4 * telem.SecondTS).Range(6*telem.SecondTS), logKey))
+				Expect(framesToStrings(fr, logKey)).To(Equal([]string{"ignition", "😀 done"}))
+			})
+
+			It("Should return an error when reading with a corrupt offset table", func() {
+				Expect(db.Close()).To(Succeed())
+				sub := MustSucceed(fs.Sub(strconv.Itoa(int(logKey))))
+				f := MustSucceed(sub.Open("1.offsets", os.O_WRONLY|os.O_TRUNC))
+				MustSucceed(f.Write([]byte{5, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}))
+				Expect(f.Close()).To(Succeed())
+				db = openDBOnFS(fs)
+				_, err := db.Read(ctx, (2 * telem.SecondTS).Range(4*telem.SecondTS), logKey)
+				Expect(err).To(MatchError(ContainSubstring("sample offset table is corrupt")))
+			})
+
+			It("Should store JSON data on a rate based channel", func() {
+				key := GenerateChannelKey()
+				Expect(db.CreateChannel(ctx, cesium.Channel{Key: key, Rate: 1 * telem.Hz, DataType: telem.JSONT})).To(Succeed())
+				series := telem.Series{
+					DataType: telem.JSONT,
+					Data:     telem.MarshalStrings([]string{`{"a":1}`, `{"b":[1,2]}`, `{}`}, telem.JSONT),
+				}
+				Expect(db.WriteArray(ctx, key, 10*telem.SecondTS, series)).To(Succeed())
+				fr := MustSucceed(db.Read(ctx, (11 * telem.SecondTS).Range(13*telem.SecondTS), key))
+				Expect(framesToStrings(fr, key)).To(Equal([]string{`{"b":[1,2]}`, `{}`}))
+			})
+
+			It("Should not accept samples that are not newline terminated", func() {
+				err := db.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+					[]cesium.ChannelKey{idxKey, logKey},
+					[]telem.Series{
+						telem.NewSecondsTSV(10),
+						{DataType: telem.StringT, Data: []byte("dangling\n")[:8]},
+					},
+				))
+				Expect(err).To(HaveOccurredAs(validate.Error))
+			})
+		})
+	}
+})
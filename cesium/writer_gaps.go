@@ -0,0 +1,219 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cesium
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// sampleRun is a contiguous run of samples in a series, starting at the sample at
+// index start and ending before the sample at index end.
+type sampleRun struct{ start, end int }
+
+// fillGaps writes the samples in the frame whose timestamps do not overlap with
+// existing data in any of the writer's channels, and discards the rest. Samples are
+// discarded at the same positions for all channels, so the channels stay aligned with
+// their index. When the writer skips over existing data, the current domain is
+// committed and a new domain is started after the existing data.
+func (w *idxWriter) fillGaps(ctx context.Context, fr Frame) (Frame, error) {
+	var out, group Frame
+	for i, key := range fr.Keys {
+		if _, ok := w.internal[key]; ok {
+			group = group.Append(key, fr.Series[i])
+		} else {
+			out = out.Append(key, fr.Series[i])
+		}
+	}
+	if len(group.Keys) == 0 || group.Series[0].Len() == 0 {
+		return fr, nil
+	}
+	stamps, err := w.stamps(ctx, group)
+	if err != nil {
+		return fr, err
+	}
+	existing, err := w.existing(ctx, w.domainEnd().Range(stamps[len(stamps)-1]+1))
+	if err != nil {
+		return fr, err
+	}
+	for _, run := range gapRuns(stamps, existing) {
+		if overlapsAny(existing, w.domainEnd().Range(stamps[run.start])) {
+			if err = w.restart(ctx, stamps[run.start]); err != nil {
+				return fr, err
+			}
+		}
+		written, err := w.write(sliceFrame(group, run))
+		if err != nil {
+			return fr, err
+		}
+		out = out.AppendFrame(written)
+		w.gaps.last = stamps[run.end-1]
+	}
+	return out, nil
+}
+
+// domainEnd returns the exclusive end of the data written to the writer's current
+// domain, or the start of the domain if no data has been written to it.
+func (w *idxWriter) domainEnd() telem.TimeStamp {
+	if w.sampleCount == 0 {
+		return w.start
+	}
+	return w.gaps.last + 1
+}
+
+// stamps returns the timestamps of the samples in the given frame, which must only
+// contain series for the writer's channels.
+func (w *idxWriter) stamps(ctx context.Context, group Frame) ([]telem.TimeStamp, error) {
+	if w.writingToIdx {
+		s := group.Get(w.idx.key)[0]
+		if s.DataType != telem.TimeStampT && s.DataType != telem.Int64T {
+			return nil, errors.Wrapf(
+				validate.Error,
+				"invalid data type for channel %d, expected %s, got %s",
+				w.idx.key,
+				telem.TimeStampT,
+				s.DataType,
+			)
+		}
+		stamps := make([]telem.TimeStamp, s.Len())
+		for i := range stamps {
+			stamps[i] = telem.ValueAt[telem.TimeStamp](s, int64(i))
+		}
+		return stamps, nil
+	}
+	stamps := make([]telem.TimeStamp, group.Series[0].Len()+1)
+	for i := range stamps {
+		approx, err := w.idx.Stamp(ctx, w.gaps.next, int64(i), true)
+		if err != nil {
+			return nil, err
+		}
+		stamps[i] = approx.Lower
+	}
+	w.gaps.next = stamps[len(stamps)-1]
+	return stamps[:len(stamps)-1], nil
+}
+
+// existing returns the time ranges of all existing domains of the writer's channels
+// that overlap with the given time range, sorted by their start.
+func (w *idxWriter) existing(ctx context.Context, tr telem.TimeRange) ([]telem.TimeRange, error) {
+	var ranges []telem.TimeRange
+	for _, chW := range w.internal {
+		r, err := chW.db.Domains(ctx, tr)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r...)
+	}
+	slices.SortFunc(ranges, func(a, b telem.TimeRange) int { return cmp.Compare(a.Start, b.Start) })
+	return ranges, nil
+}
+
+// restart commits the data written to the writer's current domain, and starts a new
+// domain at the given timestamp.
+func (w *idxWriter) restart(ctx context.Context, start telem.TimeStamp) error {
+	if w.sampleCount > 0 {
+		if _, err := w.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	for _, chW := range w.internal {
+		if err := chW.Restart(start); err != nil {
+			return err
+		}
+	}
+	w.start = start
+	w.sampleOffset += w.sampleCount
+	w.sampleCount = 0
+	return nil
+}
+
+// gapRuns returns the runs of samples whose timestamps are not contained in any of the
+// existing time ranges, which must be sorted by their start. The timestamps must be
+// sorted in ascending order.
+func gapRuns(stamps []telem.TimeStamp, existing []telem.TimeRange) []sampleRun {
+	var (
+		runs []sampleRun
+		j    = 0
+		run  = sampleRun{start: -1}
+	)
+	for i, ts := range stamps {
+		for j < len(existing) && existing[j].End <= ts {
+			j++
+		}
+		if j < len(existing) && existing[j].ContainsStamp(ts) {
+			if run.start != -1 {
+				run.end = i
+				runs = append(runs, run)
+				run.start = -1
+			}
+			continue
+		}
+		if run.start == -1 {
+			run.start = i
+		}
+	}
+	if run.start != -1 {
+		run.end = len(stamps)
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// overlapsAny returns true if the given time range overlaps with any of the existing
+// time ranges. Empty time ranges never overlap.
+func overlapsAny(existing []telem.TimeRange, tr telem.TimeRange) bool {
+	for _, e := range existing {
+		if e.Start < tr.End && tr.Start < e.End {
+			return true
+		}
+	}
+	return false
+}
+
+// sliceFrame returns a frame containing the given run of samples from each series in
+// the frame.
+func sliceFrame(fr Frame, run sampleRun) Frame {
+	out := Frame{Keys: fr.Keys, Series: make([]telem.Series, len(fr.Series))}
+	for i, s := range fr.Series {
+		out.Series[i] = sliceSeries(s, run)
+	}
+	return out
+}
+
+// sliceSeries returns a series containing the given run of samples from the series.
+func sliceSeries(s telem.Series, run sampleRun) telem.Series {
+	if run.start == 0 && int64(run.end) == s.Len() {
+		return s
+	}
+	if !s.DataType.IsVariable() {
+		den := int(s.DataType.Density())
+		return telem.Series{DataType: s.DataType, Data: s.Data[run.start*den : run.end*den]}
+	}
+	var start, end, sample int
+	for i, b := range s.Data {
+		if b != '\n' {
+			continue
+		}
+		sample++
+		if sample == run.start {
+			start = i + 1
+		}
+		if sample == run.end {
+			end = i + 1
+			break
+		}
+	}
+	return telem.Series{DataType: s.DataType, Data: s.Data[start:end]}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/synnaxlabs/cesium/internal/controller"
 	"github.com/synnaxlabs/cesium/internal/core"
+	"github.com/synnaxlabs/cesium/internal/domain"
 	"github.com/synnaxlabs/cesium/internal/index"
 	"github.com/synnaxlabs/cesium/internal/unary"
 	"github.com/synnaxlabs/cesium/internal/virtual"
@@ -41,6 +42,26 @@ const (
 	WriterStreamOnly
 )
 
+// ConflictPolicy determines how a writer handles writes that overlap with existing
+// data in the DB.
+type ConflictPolicy = domain.ConflictPolicy
+
+const (
+	// ConflictFail causes the writer to fail when writing over existing data.
+	ConflictFail = domain.ConflictFail
+	// ConflictOverwrite causes the writer to replace any existing data in the time
+	// range of each commit. The existing data is replaced atomically for each channel,
+	// and its bytes are reclaimed by garbage collection.
+	ConflictOverwrite = domain.ConflictOverwrite
+	// ConflictFillGaps causes the writer to only write samples whose timestamps do not
+	// overlap with existing data for any channel sharing the same index. Samples that
+	// overlap are discarded for all channels sharing the index. When the writer skips
+	// over existing data, the samples written before it are committed, and the writer
+	// continues in a new domain after it. Domain indexed channels can only be written
+	// with this policy if their index is also written to.
+	ConflictFillGaps = domain.ConflictFillGaps
+)
+
 // WriterConfig sets the configuration used to open a new writer on the DB.
 type WriterConfig struct {
 	// Name sets the human-readable name for the writer, which is useful for identifying
@@ -85,6 +106,10 @@ type WriterConfig struct {
 	// to AlwaysIndexPersistOnAutoCommit.
 	// [OPTIONAL] - Defaults to 1s.
 	AutoIndexPersistInterval telem.TimeSpan
+	// ConflictPolicy determines how the writer handles writes that overlap with
+	// existing data. See the ConflictPolicy documentation for more.
+	// [OPTIONAL] - Defaults to ConflictFail.
+	ConflictPolicy ConflictPolicy
 }
 
 const AlwaysIndexPersistOnAutoCommit telem.TimeSpan = -1
@@ -104,6 +129,7 @@ func DefaultWriterConfig() WriterConfig {
 		Mode:                     WriterPersistStream,
 		EnableAutoCommit:         config.Bool(false),
 		AutoIndexPersistInterval: 1 * telem.Second,
+		ConflictPolicy:           ConflictFail,
 	}
 }
 
@@ -119,6 +145,11 @@ func (c WriterConfig) Validate() error {
 		len(c.Authorities) != len(c.Channels) && len(c.Authorities) != 1,
 		"authority count must be 1 or equal to channel count",
 	)
+	v.Ternary(
+		"conflict_policy",
+		c.ConflictPolicy < ConflictFail || c.ConflictPolicy > ConflictFillGaps,
+		"invalid conflict policy",
+	)
 	return v.Error()
 }
 
@@ -134,6 +165,7 @@ func (c WriterConfig) Override(other WriterConfig) WriterConfig {
 	c.Mode = override.Numeric(c.Mode, other.Mode)
 	c.EnableAutoCommit = override.Nil(c.EnableAutoCommit, other.EnableAutoCommit)
 	c.AutoIndexPersistInterval = override.Zero(c.AutoIndexPersistInterval, other.AutoIndexPersistInterval)
+	c.ConflictPolicy = override.Numeric(c.ConflictPolicy, other.ConflictPolicy)
 	return c
 }
 
@@ -206,6 +238,7 @@ func (db *DB) newStreamWriter(ctx context.Context, cfgs ...WriterConfig) (w *str
 			Persist:                  config.Bool(cfg.Mode.Persist()),
 			Authority:                cfg.authority(i),
 			AlignmentDomainIndex:     domainAlignment,
+			ConflictPolicy:           cfg.ConflictPolicy,
 		}
 	}
 
@@ -268,7 +301,7 @@ func (db *DB) newStreamWriter(ctx context.Context, cfgs ...WriterConfig) (w *str
 				}
 				idxW.writingToIdx = true
 				idxW.domainAlignment = unaryW.DomainIndex()
-				idxW.internal[key] = &unaryWriterState{Writer: *unaryW}
+				idxW.internal[key] = &unaryWriterState{Writer: *unaryW, db: u}
 				domainWriters[u.Channel().Index] = idxW
 			} else {
 				// Hot path optimization: in the common case we only write to a rate based
@@ -282,7 +315,7 @@ func (db *DB) newStreamWriter(ctx context.Context, cfgs ...WriterConfig) (w *str
 					idxW = db.openRateIdxWriter(u.Channel().Rate, cfg)
 					rateWriters[u.Channel().Rate] = idxW
 				}
-				idxW.internal[key] = &unaryWriterState{Writer: *unaryW}
+				idxW.internal[key] = &unaryWriterState{Writer: *unaryW, db: u}
 			}
 		}
 		if transfer.Occurred() {
@@ -299,6 +332,14 @@ func (db *DB) newStreamWriter(ctx context.Context, cfgs ...WriterConfig) (w *str
 		}
 		idxW, ok := domainWriters[u.Channel().Index]
 		if !ok {
+			if cfg.ConflictPolicy == ConflictFillGaps {
+				return nil, errors.Wrapf(
+					validate.Error,
+					"cannot fill gaps in channel %d without also writing to its index %d",
+					key,
+					u.Channel().Index,
+				)
+			}
 			if domainWriters == nil {
 				domainWriters = make(map[ChannelKey]*idxWriter)
 			}
@@ -319,7 +360,7 @@ func (db *DB) newStreamWriter(ctx context.Context, cfgs ...WriterConfig) (w *str
 		if transfer.Occurred() {
 			controlUpdate.Transfers = append(controlUpdate.Transfers, transfer)
 		}
-		idxW.internal[key] = &unaryWriterState{Writer: *unaryW}
+		idxW.internal[key] = &unaryWriterState{Writer: *unaryW, db: u}
 	}
 
 	if len(controlUpdate.Transfers) > 0 {
@@ -362,6 +403,8 @@ func (db *DB) openDomainIdxWriter(
 	w.idx.highWaterMark = cfg.Start
 	w.writingToIdx = false
 	w.start = cfg.Start
+	w.overwrite = cfg.ConflictPolicy == ConflictOverwrite
+	w.gaps.enabled = cfg.ConflictPolicy == ConflictFillGaps
 	return w, nil
 }
 
@@ -373,5 +416,7 @@ func (db *DB) openRateIdxWriter(
 	w := &idxWriter{internal: make(map[ChannelKey]*unaryWriterState)}
 	w.idx.Index = idx
 	w.start = cfg.Start
+	w.gaps.enabled = cfg.ConflictPolicy == ConflictFillGaps
+	w.gaps.next = cfg.Start
 	return w
 }
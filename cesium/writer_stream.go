@@ -219,7 +219,7 @@ func (w *streamWriter) sendRes(req WriterRequest, ack bool, err error, end telem
 
 func (w *streamWriter) write(ctx context.Context, req WriterRequest) (err error) {
 	for _, idx := range w.internal {
-		req.Frame, err = idx.Write(req.Frame)
+		req.Frame, err = idx.Write(ctx, req.Frame)
 		if err != nil {
 			if errors.Is(err, control.Unauthorized) && !*w.SendAuthErrors {
 				return nil
@@ -303,6 +303,8 @@ func (w *streamWriter) close(ctx context.Context) error {
 type unaryWriterState struct {
 	timesWritten int
 	unary.Writer
+	// db is the unary DB the writer writes to.
+	db unary.DB
 }
 
 // idxWriter is a writer to a set of channels that all share the same index.
@@ -327,19 +329,41 @@ type idxWriter struct {
 		// is only relevant when writingToIdx is true.
 		highWaterMark telem.TimeStamp
 	}
-	// sampleCount is the total number of samples written to the index as if it were
-	// a single logical channel. i.e. N channels with M samples will result in a sample
-	// count of M.
+	// sampleCount is the total number of samples written to the current domain of the
+	// index as if it were a single logical channel. i.e. N channels with M samples will
+	// result in a sample count of M.
 	sampleCount int64
+	// sampleOffset is the number of samples written to domains before the current one.
+	// It is only non-zero for writers that restart domains to fill gaps.
+	sampleOffset int64
+	// overwrite is true when the writer replaces existing data with the
+	// ConflictOverwrite policy.
+	overwrite bool
+	// gaps tracks the state of writers with the ConflictFillGaps policy.
+	gaps struct {
+		// enabled is true when the writer only writes samples into gaps in existing
+		// data.
+		enabled bool
+		// next is the timestamp of the next sample written to a rate based index.
+		next telem.TimeStamp
+		// last is the timestamp of the last sample written to the current domain. It is
+		// only valid when sampleCount is greater than zero.
+		last telem.TimeStamp
+	}
 }
 
-func (w *idxWriter) Write(fr Frame) (Frame, error) {
+func (w *idxWriter) Write(ctx context.Context, fr Frame) (Frame, error) {
 	w.numWriteCalls++
-	err := w.validateWrite(fr)
-	if err != nil {
+	if err := w.validateWrite(fr); err != nil {
 		return fr, err
 	}
+	if w.gaps.enabled {
+		return w.fillGaps(ctx, fr)
+	}
+	return w.write(fr)
+}
 
+func (w *idxWriter) write(fr Frame) (Frame, error) {
 	var incrementedSampleCount bool
 
 	for i, series := range fr.Series {
@@ -351,17 +375,20 @@ func (w *idxWriter) Write(fr Frame) (Frame, error) {
 		}
 
 		if w.writingToIdx && w.idx.key == key {
-			if err = w.updateHighWater(series); err != nil {
+			if err := w.updateHighWater(series); err != nil {
 				return fr, err
 			}
 		}
 
+		// Count samples before writing, as writing to a file may modify the contents
+		// of the series.
+		n := series.Len()
 		alignment, err := uWriter.Write(series)
 		if err != nil {
 			return fr, err
 		}
 		if !incrementedSampleCount {
-			w.sampleCount = int64(alignment.SampleIndex()) + series.Len()
+			w.sampleCount = int64(alignment.SampleIndex()) + n - w.sampleOffset
 			incrementedSampleCount = true
 		}
 		series.Alignment = alignment
@@ -382,8 +409,21 @@ func (w *idxWriter) Commit(ctx context.Context) (telem.TimeStamp, error) {
 	// because the range is exclusive, we need to add 1 nanosecond to the end
 	end.Lower++
 	c := errors.NewCatcher(errors.WithAggregation())
-	for _, chW := range w.internal {
-		c.Exec(func() error { return chW.CommitWithEnd(ctx, end.Lower) })
+	idxW, writingToIdx := w.internal[w.idx.key]
+	// The index is normally committed first so that readers never see data that
+	// extends past the end of its index. When overwriting, the index is committed last
+	// so that the other channels resolve the regions they replace against the index
+	// before it is overwritten.
+	if writingToIdx && !w.overwrite {
+		c.Exec(func() error { return idxW.CommitWithEnd(ctx, end.Lower) })
+	}
+	for key, chW := range w.internal {
+		if key != w.idx.key {
+			c.Exec(func() error { return chW.CommitWithEnd(ctx, end.Lower) })
+		}
+	}
+	if writingToIdx && w.overwrite {
+		c.Exec(func() error { return idxW.CommitWithEnd(ctx, end.Lower) })
 	}
 	return end.Lower, c.Error()
 }
@@ -421,7 +461,7 @@ func (w *idxWriter) validateWrite(fr Frame) error {
 			s := fr.Series[i]
 			// Data type of first series must be known since we use it to calculate the
 			// length of series in the frame
-			if s.DataType.Density() == telem.DensityUnknown {
+			if s.DataType.Density() == telem.DensityUnknown && !s.DataType.IsVariable() {
 				return errors.Wrapf(
 					validate.Error,
 					"invalid data type for channel %d, expected %s, got %s",
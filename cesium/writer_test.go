@@ -895,7 +895,7 @@ var _ = Describe("Writer Behavior", func() {
 							subFS := MustSucceed(fs.Sub("size-capped-db"))
 							l := MustSucceed(subFS.List(strconv.Itoa(int(index))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(3))
 							Expect(l[0].Size()).To(Equal(int64(6 * telem.Int64T.Density())))
@@ -903,7 +903,7 @@ var _ = Describe("Writer Behavior", func() {
 							Expect(l[2].Size()).To(Equal(int64(3 * telem.Int64T.Density())))
 							l = MustSucceed(subFS.List(strconv.Itoa(int(basic))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(3))
 							Expect(l[0].Size()).To(Equal(int64(6 * telem.Int64T.Density())))
@@ -911,7 +911,7 @@ var _ = Describe("Writer Behavior", func() {
 							Expect(l[2].Size()).To(Equal(int64(3 * telem.Int64T.Density())))
 							l = MustSucceed(subFS.List(strconv.Itoa(int(rate))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(6 * telem.Int64T.Density())))
@@ -1015,21 +1015,21 @@ var _ = Describe("Writer Behavior", func() {
 						By("Asserting that the first two channels have 2 files, while the last channel has an oversize file", func() {
 							l := MustSucceed(subFS.List(strconv.Itoa(int(index))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(10 * telem.Int64T.Density())))
 							Expect(l[1].Size()).To(Equal(int64(5 * telem.Int64T.Density())))
 							l = MustSucceed(subFS.List(strconv.Itoa(int(basic))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(10 * telem.Int64T.Density())))
 							Expect(l[1].Size()).To(Equal(int64(5 * telem.Int64T.Density())))
 							l = MustSucceed(subFS.List(strconv.Itoa(int(rate))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(11 * telem.Int64T.Density())))
@@ -1162,21 +1162,21 @@ var _ = Describe("Writer Behavior", func() {
 							subFS := MustSucceed(fs.Sub("size-capped-db"))
 							l := MustSucceed(subFS.List(strconv.Itoa(int(index))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(13 * telem.Int64T.Density())))
 							Expect(l[1].Size()).To(Equal(int64(3 * telem.Int64T.Density())))
 							l = MustSucceed(subFS.List(strconv.Itoa(int(basic))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(13 * telem.Int64T.Density())))
 							Expect(l[1].Size()).To(Equal(int64(3 * telem.Int64T.Density())))
 							l = MustSucceed(subFS.List(strconv.Itoa(int(rate))))
 							l = lo.Filter(l, func(item os.FileInfo, _ int) bool {
-								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain"
+								return item.Name() != "index.domain" && item.Name() != "counter.domain" && item.Name() != "meta.json" && item.Name() != "tombstone.domain" && item.Name() != "summary.domain"
 							})
 							Expect(l).To(HaveLen(2))
 							Expect(l[0].Size()).To(Equal(int64(6 * telem.Int64T.Density())))
@@ -0,0 +1,116 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/x/errors"
+)
+
+const (
+	authorizationParam = "Authorization"
+	tokenPrefix        = "Bearer "
+	// tokenRefreshParam is the response parameter a node sets when it issues a new
+	// token to replace one that is close to expiring.
+	tokenRefreshParam = "refresh-token"
+)
+
+// authenticator logs in to a node and attaches the resulting token to every request.
+// It replaces the token whenever the node issues a refreshed one, and logs in again
+// if the node rejects the token as invalid (i.e. after it expires).
+type authenticator struct {
+	creds auth.InsecureCredentials
+	login func(context.Context, api.AuthLoginRequest) (api.AuthLoginResponse, error)
+	mu    sync.RWMutex
+	token string
+	user  user.User
+}
+
+// authenticate logs in using the authenticator's credentials, replacing the current
+// token.
+func (a *authenticator) authenticate(ctx context.Context) error {
+	res, err := a.login(ctx, api.AuthLoginRequest{InsecureCredentials: a.creds})
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.token, a.user = res.Token, res.User
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *authenticator) currentToken() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.token
+}
+
+func (a *authenticator) setToken(tk string) {
+	a.mu.Lock()
+	a.token = tk
+	a.mu.Unlock()
+}
+
+// middleware returns freighter middleware that authenticates outgoing requests.
+func (a *authenticator) middleware() freighter.Middleware {
+	return freighter.MiddlewareFunc(func(
+		ctx freighter.Context,
+		next freighter.Next,
+	) (freighter.Context, error) {
+		tk := a.currentToken()
+		oCtx, err := a.exec(ctx, next, tk)
+		if !errors.Is(err, auth.InvalidToken) {
+			return oCtx, err
+		}
+		// Another request may have already logged in again, in which case we retry with
+		// the new token instead of logging in a second time.
+		if a.currentToken() == tk {
+			if lErr := a.authenticate(ctx); lErr != nil {
+				return oCtx, errors.CombineErrors(err, lErr)
+			}
+		}
+		return a.exec(ctx, next, a.currentToken())
+	})
+}
+
+func (a *authenticator) exec(
+	ctx freighter.Context,
+	next freighter.Next,
+	tk string,
+) (freighter.Context, error) {
+	ctx.Params[authorizationParam] = tokenPrefix + tk
+	oCtx, err := next(ctx)
+	if refreshed, ok := refreshedToken(oCtx.Params); ok {
+		a.setToken(refreshed)
+	}
+	return oCtx, err
+}
+
+// refreshedToken looks for a refreshed token in the given response parameters. Nodes
+// may send the parameter under a transport specific prefix and casing, so keys are
+// matched by suffix.
+func refreshedToken(p freighter.Params) (string, bool) {
+	for k, v := range p {
+		if !strings.HasSuffix(strings.ToLower(k), tokenRefreshParam) {
+			continue
+		}
+		if tk, ok := v.(string); ok && tk != "" {
+			return tk, true
+		}
+	}
+	return "", false
+}
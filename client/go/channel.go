@@ -0,0 +1,110 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/query"
+)
+
+// Channel is a logical collection of samples emitted by or representing the values of
+// a single source, such as a sensor, actuator, or software generated signal.
+type Channel = api.Channel
+
+// ChannelRetrieveRequest is used to filter the channels returned by
+// ChannelClient.Retrieve.
+type ChannelRetrieveRequest = api.ChannelRetrieveRequest
+
+// ChannelClient creates, retrieves, and deletes channels.
+type ChannelClient struct{ client *Client }
+
+// Create creates the given channels, returning them with their keys and leaseholders
+// assigned by the node.
+func (c *ChannelClient) Create(ctx context.Context, channels ...Channel) ([]Channel, error) {
+	t := c.client.t
+	res, err := t.channelCreate.Send(
+		ctx,
+		c.client.target(t.endpoints.channelCreate),
+		api.ChannelCreateRequest{Channels: channels},
+	)
+	return res.Channels, err
+}
+
+// Retrieve retrieves the channels matching the given request.
+func (c *ChannelClient) Retrieve(
+	ctx context.Context,
+	req ChannelRetrieveRequest,
+) ([]Channel, error) {
+	t := c.client.t
+	res, err := t.channelRetrieve.Send(ctx, c.client.target(t.endpoints.channelRetrieve), req)
+	return res.Channels, err
+}
+
+// RetrieveByKeys retrieves the channels with the given keys. Returns a query.NotFound
+// error if any of the channels don't exist.
+func (c *ChannelClient) RetrieveByKeys(ctx context.Context, keys ...channel.Key) ([]Channel, error) {
+	channels, err := c.Retrieve(ctx, ChannelRetrieveRequest{Keys: keys})
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) != len(channel.Keys(keys).Unique()) {
+		return channels, errors.Wrapf(query.NotFound, "channels with keys %v not found", keys)
+	}
+	return channels, nil
+}
+
+// RetrieveOne retrieves the channel with the given name. Returns a query.NotFound
+// error if no channel has the name, and a query.UniqueViolation error if more than one
+// does.
+func (c *ChannelClient) RetrieveOne(ctx context.Context, name string) (Channel, error) {
+	channels, err := c.Retrieve(ctx, ChannelRetrieveRequest{Names: []string{name}})
+	if err != nil {
+		return Channel{}, err
+	}
+	if len(channels) == 0 {
+		return Channel{}, errors.Wrapf(query.NotFound, "channel %s not found", name)
+	}
+	if len(channels) > 1 {
+		return Channel{}, errors.Wrapf(
+			query.UniqueViolation,
+			"%d channels found with name %s",
+			len(channels),
+			name,
+		)
+	}
+	return channels[0], nil
+}
+
+// Delete deletes the channels with the given keys, along with all of their data.
+func (c *ChannelClient) Delete(ctx context.Context, keys ...channel.Key) error {
+	t := c.client.t
+	_, err := t.channelDelete.Send(
+		ctx,
+		c.client.target(t.endpoints.channelDelete),
+		api.ChannelDeleteRequest{Keys: keys},
+	)
+	return err
+}
+
+// DeleteByNames deletes the channels with the given names, along with all of their
+// data.
+func (c *ChannelClient) DeleteByNames(ctx context.Context, names ...string) error {
+	t := c.client.t
+	_, err := t.channelDelete.Send(
+		ctx,
+		c.client.target(t.endpoints.channelDelete),
+		api.ChannelDeleteRequest{Names: names},
+	)
+	return err
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/client"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Channel", func() {
+	for _, t := range transports {
+		Describe(string(t), Ordered, func() {
+			var c *client.Client
+			BeforeAll(func() { c = openClient(t) })
+			AfterAll(func() { Expect(c.Close()).To(Succeed()) })
+			Describe("Create", func() {
+				It("Should create channels and assign them keys", func() {
+					channels := MustSucceed(c.Channels.Create(
+						ctx,
+						client.Channel{Name: "create_" + string(t), DataType: telem.Float64T, Virtual: true},
+						client.Channel{Name: "create_2_" + string(t), DataType: telem.Float32T, Virtual: true},
+					))
+					Expect(channels).To(HaveLen(2))
+					Expect(channels[0].Key).ToNot(BeZero())
+					Expect(channels[1].Key).ToNot(Equal(channels[0].Key))
+				})
+				It("Should return an error if the channel is invalid", func() {
+					_, err := c.Channels.Create(ctx, client.Channel{Name: "invalid_" + string(t)})
+					Expect(err).To(HaveOccurred())
+				})
+			})
+			Describe("Retrieve", func() {
+				It("Should retrieve channels by key", func() {
+					created := MustSucceed(c.Channels.Create(
+						ctx,
+						client.Channel{Name: "by_key_" + string(t), DataType: telem.Int64T, Virtual: true},
+					))
+					channels := MustSucceed(c.Channels.RetrieveByKeys(ctx, created[0].Key))
+					Expect(channels).To(HaveLen(1))
+					Expect(channels[0].Name).To(Equal("by_key_" + string(t)))
+				})
+				It("Should retrieve a channel by name", func() {
+					MustSucceed(c.Channels.Create(
+						ctx,
+						client.Channel{Name: "by_name_" + string(t), DataType: telem.Int64T, Virtual: true},
+					))
+					ch := MustSucceed(c.Channels.RetrieveOne(ctx, "by_name_"+string(t)))
+					Expect(ch.DataType).To(Equal(telem.Int64T))
+				})
+				It("Should return a query.NotFound error if the channel doesn't exist", func() {
+					_, err := c.Channels.RetrieveOne(ctx, "does_not_exist")
+					Expect(err).To(HaveOccurredAs(query.NotFound))
+				})
+			})
+			Describe("Delete", func() {
+				It("Should delete channels by key", func() {
+					created := MustSucceed(c.Channels.Create(
+						ctx,
+						client.Channel{Name: "delete_" + string(t), DataType: telem.Int64T, Virtual: true},
+					))
+					Expect(c.Channels.Delete(ctx, created[0].Key)).To(Succeed())
+					_, err := c.Channels.RetrieveByKeys(ctx, created[0].Key)
+					Expect(err).To(HaveOccurredAs(query.NotFound))
+				})
+				It("Should delete channels by name", func() {
+					MustSucceed(c.Channels.Create(
+						ctx,
+						client.Channel{Name: "delete_by_name_" + string(t), DataType: telem.Int64T, Virtual: true},
+					))
+					Expect(c.Channels.DeleteByNames(ctx, "delete_by_name_"+string(t))).To(Succeed())
+					_, err := c.Channels.RetrieveOne(ctx, "delete_by_name_"+string(t))
+					Expect(err).To(HaveOccurredAs(query.NotFound))
+				})
+			})
+		})
+	}
+})
@@ -0,0 +1,143 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package client is the Go client for Synnax. It mirrors the Python and TypeScript
+// clients, providing access to channels, ranges, and the reading, writing, and
+// streaming of telemetry.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/password"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/x/address"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Config is the configuration for opening a Client.
+type Config struct {
+	alamos.Instrumentation
+	// Host is the address of the Synnax node to connect to, e.g. "localhost:9090".
+	// [REQUIRED]
+	Host address.Address
+	// Username is the username to authenticate with.
+	// [REQUIRED]
+	Username string
+	// Password is the password to authenticate with.
+	// [REQUIRED]
+	Password string
+	// TLS is the TLS configuration used to connect to the node. If nil, the client
+	// connects without TLS, which requires the node to be running in insecure mode.
+	// [OPTIONAL]
+	TLS *tls.Config
+	// Transport is the protocol used to communicate with the node.
+	// [OPTIONAL] - Defaults to HTTP.
+	Transport Transport
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for a Client.
+	DefaultConfig = Config{Transport: HTTP}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.Host = override.String(c.Host, other.Host)
+	c.Username = override.String(c.Username, other.Username)
+	c.Password = override.String(c.Password, other.Password)
+	c.TLS = override.Nil(c.TLS, other.TLS)
+	c.Transport = override.String(c.Transport, other.Transport)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("client")
+	validate.NotEmptyString(v, "host", c.Host)
+	validate.NotEmptyString(v, "username", c.Username)
+	validate.NotEmptyString(v, "password", c.Password)
+	v.Ternaryf(
+		"transport",
+		c.Transport != HTTP && c.Transport != GRPC,
+		"must be one of %s or %s",
+		HTTP,
+		GRPC,
+	)
+	return v.Error()
+}
+
+// Client is an authenticated connection to a Synnax node. A Client is safe for
+// concurrent use, and must be closed when it's no longer needed.
+type Client struct {
+	// Channels is used to create, retrieve, and delete channels.
+	Channels *ChannelClient
+	// Ranges is used to create, retrieve, and delete ranges, along with their
+	// key-value metadata and channel aliases.
+	Ranges *RangeClient
+	cfg    Config
+	t      transport
+	auth   *authenticator
+}
+
+// Open connects to the Synnax node specified in the provided configuration, logging
+// in with the configured credentials.
+func Open(ctx context.Context, configs ...Config) (*Client, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	t := newHTTPTransport(cfg.TLS)
+	if cfg.Transport == GRPC {
+		t = newGRPCTransport(cfg.TLS)
+	}
+	c := &Client{cfg: cfg, t: t}
+	c.auth = &authenticator{
+		creds: auth.InsecureCredentials{
+			Username: cfg.Username,
+			Password: password.Raw(cfg.Password),
+		},
+		login: func(ctx context.Context, req api.AuthLoginRequest) (api.AuthLoginResponse, error) {
+			return t.authLogin.Send(ctx, c.target(t.endpoints.authLogin), req)
+		},
+	}
+	if err = c.auth.authenticate(ctx); err != nil {
+		return nil, errors.CombineErrors(
+			errors.Wrapf(err, "failed to log in to node at %s", cfg.Host),
+			t.close(),
+		)
+	}
+	t.use(c.auth.middleware())
+	c.Channels = &ChannelClient{client: c}
+	c.Ranges = &RangeClient{client: c}
+	return c, nil
+}
+
+// User returns the user that the client is logged in as.
+func (c *Client) User() user.User {
+	c.auth.mu.RLock()
+	defer c.auth.mu.RUnlock()
+	return c.auth.user
+}
+
+// Close closes the client, releasing any open connections to the node.
+func (c *Client) Close() error { return c.t.close() }
+
+func (c *Client) target(endpoint string) address.Address {
+	return c.cfg.Host + address.Address(endpoint)
+}
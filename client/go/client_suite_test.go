@@ -0,0 +1,228 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/freighter/fhttp"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	grpcapi "github.com/synnaxlabs/synnax/pkg/api/grpc"
+	httpapi "github.com/synnaxlabs/synnax/pkg/api/http"
+	"github.com/synnaxlabs/synnax/pkg/distribution/mock"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	securitymock "github.com/synnaxlabs/synnax/pkg/security/mock"
+	"github.com/synnaxlabs/synnax/pkg/server"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/access/rbac"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/synnax/pkg/service/alter"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/password"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/token"
+	"github.com/synnaxlabs/synnax/pkg/service/framer"
+	"github.com/synnaxlabs/synnax/pkg/service/hardware"
+	"github.com/synnaxlabs/synnax/pkg/service/ingest"
+	"github.com/synnaxlabs/synnax/pkg/service/label"
+	"github.com/synnaxlabs/synnax/pkg/service/notify"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/synnax/pkg/service/replay"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/lineplot"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/log"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/schematic"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/table"
+	"github.com/synnaxlabs/x/address"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/gorp"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+const (
+	host     address.Address = "localhost:26590"
+	username                 = "synnax"
+	pass                     = "seldon"
+)
+
+var (
+	ctx      = context.Background()
+	builder  *mock.Builder
+	tokenSvc *token.Service
+	srv      *server.Server
+	closers  []io.Closer
+)
+
+func TestClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Client Suite")
+}
+
+// openNode starts an in-process, insecure Synnax node backed by an in-memory
+// distribution layer, with a single user registered.
+func openNode() {
+	builder = mock.NewBuilder()
+	dist := builder.New(ctx)
+	db := dist.Storage.Gorpify()
+	userSvc := MustSucceed(user.NewService(ctx, user.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	roleSvc := MustSucceed(role.OpenService(ctx, role.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	rbacSvc := MustSucceed(rbac.NewService(rbac.Config{DB: db, Role: roleSvc}))
+	key := MustSucceed(rsa.GenerateKey(rand.Reader, 1024))
+	tokenSvc = &token.Service{
+		KeyProvider: securitymock.KeyProvider{Key: key},
+		Expiration:  time.Hour,
+	}
+	authenticator := &auth.KV{DB: db}
+	rangeSvc := MustSucceed(ranger.OpenService(ctx, ranger.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+		Signals:  dist.Signals,
+	}))
+	workspaceSvc := MustSucceed(workspace.NewService(ctx, workspace.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	schematicSvc := MustSucceed(schematic.NewService(schematic.Config{DB: db, Ontology: dist.Ontology}))
+	linePlotSvc := MustSucceed(lineplot.NewService(lineplot.Config{DB: db, Ontology: dist.Ontology}))
+	labelSvc := MustSucceed(label.OpenService(ctx, label.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+		Signals:  dist.Signals,
+	}))
+	logSvc := MustSucceed(log.NewService(log.Config{DB: db, Ontology: dist.Ontology}))
+	tableSvc := MustSucceed(table.NewService(table.Config{DB: db, Ontology: dist.Ontology}))
+	hardwareSvc := MustSucceed(hardware.OpenService(ctx, hardware.Config{
+		DB:           db,
+		Ontology:     dist.Ontology,
+		Group:        dist.Group,
+		HostProvider: dist.Cluster,
+		Signals:      dist.Signals,
+		Channel:      dist.Channel,
+	}))
+	closers = append(closers, hardwareSvc, rangeSvc)
+	frameSvc := MustSucceed(framer.NewService(dist.Framer))
+	ingestSvc := MustSucceed(ingest.OpenService(ingest.Config{
+		DB:      db,
+		Channel: dist.Channel,
+		Framer:  dist.Framer,
+	}))
+	alterSvc := MustSucceed(alter.OpenService(alter.Config{Channel: dist.Channel}))
+	replaySvc := MustSucceed(replay.OpenService(replay.ServiceConfig{
+		Channel: dist.Channel,
+		Framer:  dist.Framer,
+	}))
+	alarmSvc := MustSucceed(alarm.OpenService(ctx, alarm.Config{
+		DB:           db,
+		Ontology:     dist.Ontology,
+		Group:        dist.Group,
+		HostProvider: dist.Cluster,
+		Channel:      dist.Channel,
+		Framer:       dist.Framer,
+	}))
+	notifySvc := MustSucceed(notify.OpenService(ctx, notify.Config{
+		DB:           db,
+		Ontology:     dist.Ontology,
+		Group:        dist.Group,
+		HostProvider: dist.Cluster,
+		Signals:      dist.Signals,
+	}))
+	closers = append(closers, alarmSvc, notifySvc, alterSvc)
+	Expect(db.WithTx(ctx, func(tx gorp.Tx) error {
+		creds := auth.InsecureCredentials{Username: username, Password: password.Raw(pass)}
+		if err := authenticator.NewWriter(tx).Register(ctx, creds); err != nil {
+			return err
+		}
+		u := user.User{Username: username, RootUser: true}
+		if err := userSvc.NewWriter(tx).Create(ctx, &u); err != nil {
+			return err
+		}
+		return rbacSvc.NewWriter(tx).Create(ctx, &rbac.Policy{
+			Subjects: []ontology.ID{user.OntologyID(u.Key)},
+			Objects:  []ontology.ID{rbac.AllowAllOntologyID},
+			Actions:  []access.Action{},
+		})
+	})).To(Succeed())
+	_api := MustSucceed(api.New(api.Config{
+		Authenticator: authenticator,
+		Enforcer:      &access.AllowAll{},
+		RBAC:          rbacSvc,
+		Schematic:     schematicSvc,
+		LinePlot:      linePlotSvc,
+		Insecure:      config.Bool(true),
+		Channel:       dist.Channel,
+		Framer:        frameSvc,
+		Storage:       dist.Storage,
+		User:          userSvc,
+		Token:         tokenSvc,
+		Table:         tableSvc,
+		Cluster:       dist.Cluster,
+		Ontology:      dist.Ontology,
+		Group:         dist.Group,
+		Ranger:        rangeSvc,
+		Log:           logSvc,
+		Workspace:     workspaceSvc,
+		Label:         labelSvc,
+		Role:          roleSvc,
+		Hardware:      hardwareSvc,
+		Ingest:        ingestSvc,
+		Alter:         alterSvc,
+		Replay:        replaySvc,
+		Alarm:         alarmSvc,
+		Notify:        notifySvc,
+	}))
+	r := fhttp.NewRouter()
+	_api.BindTo(httpapi.New(r))
+	grpcAPI, grpcTransports := grpcapi.New()
+	_api.BindTo(grpcAPI)
+	srv = MustSucceed(server.New(server.Config{
+		ListenAddress: host,
+		Security:      server.SecurityConfig{Insecure: config.Bool(true)},
+		Debug:         config.Bool(false),
+		Branches: []server.Branch{
+			&server.SecureHTTPBranch{Transports: []fhttp.BindableTransport{r}},
+			&server.GRPCBranch{Transports: grpcTransports},
+		},
+	}))
+	go func() {
+		defer GinkgoRecover()
+		Expect(srv.Serve()).To(Succeed())
+	}()
+	Eventually(srv.Started()).Should(BeClosed())
+}
+
+var _ = BeforeSuite(openNode)
+
+var _ = AfterSuite(func() {
+	srv.Stop()
+	for _, c := range closers {
+		Expect(c.Close()).To(Succeed())
+	}
+	Expect(builder.Close()).To(Succeed())
+	Expect(builder.Cleanup()).To(Succeed())
+})
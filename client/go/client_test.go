@@ -0,0 +1,69 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/client"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var transports = []client.Transport{client.HTTP, client.GRPC}
+
+func openClient(t client.Transport) *client.Client {
+	return MustSucceed(client.Open(ctx, client.Config{
+		Host:      host,
+		Username:  username,
+		Password:  pass,
+		Transport: t,
+	}))
+}
+
+var _ = Describe("Client", func() {
+	for _, t := range transports {
+		Describe(string(t), func() {
+			Describe("Open", func() {
+				It("Should log in as the configured user", func() {
+					c := openClient(t)
+					Expect(c.User().Username).To(Equal(username))
+					Expect(c.Close()).To(Succeed())
+				})
+				It("Should return an error if the credentials are invalid", func() {
+					_, err := client.Open(ctx, client.Config{
+						Host:      host,
+						Username:  username,
+						Password:  "wrong",
+						Transport: t,
+					})
+					Expect(err).To(MatchError(auth.InvalidCredentials))
+				})
+			})
+			Describe("Token Expiration", func() {
+				It("Should log in again when the token expires", func() {
+					tokenSvc.Expiration = time.Second
+					c := openClient(t)
+					tokenSvc.Expiration = time.Hour
+					time.Sleep(2 * time.Second)
+					Expect(c.Channels.Retrieve(ctx, client.ChannelRetrieveRequest{})).Error().ToNot(HaveOccurred())
+					Expect(c.Close()).To(Succeed())
+				})
+			})
+		})
+	}
+	It("Should return an error if the configuration is invalid", func() {
+		_, err := client.Open(ctx, client.Config{Host: host})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("username"))
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// Frame is a collection of telem.Series, each belonging to the channel at the same
+// index in Keys.
+type Frame = api.Frame
+
+// NewFrame creates a frame from the given keys and series, which must be the same
+// length.
+func NewFrame(keys channel.Keys, series ...telem.Series) Frame {
+	return Frame{Keys: keys, Series: series}
+}
+
+// Read reads all data from the given channels within the given time range.
+func (c *Client) Read(ctx context.Context, tr telem.TimeRange, keys ...channel.Key) (Frame, error) {
+	iter, err := c.OpenIterator(ctx, IteratorConfig{Keys: keys, Bounds: tr})
+	if err != nil {
+		return Frame{}, err
+	}
+	var f Frame
+	for iter.SeekFirst(); iter.Next(AutoSpan); {
+		v := iter.Value()
+		f.Keys = append(f.Keys, v.Keys...)
+		f.Series = append(f.Series, v.Series...)
+	}
+	return f, errors.CombineErrors(iter.Error(), iter.Close())
+}
+
+// Write writes the given frame starting at the given timestamp, committing it before
+// returning. Write returns the end timestamp of the committed data.
+func (c *Client) Write(ctx context.Context, start telem.TimeStamp, frame Frame) (telem.TimeStamp, error) {
+	w, err := c.OpenWriter(ctx, WriterConfig{Keys: frame.Keys.Unique(), Start: start})
+	if err != nil {
+		return 0, err
+	}
+	if err = w.Write(frame); err != nil {
+		return 0, errors.CombineErrors(err, w.Close())
+	}
+	end, err := w.Commit()
+	return end, errors.CombineErrors(err, w.Close())
+}
+
+// Delete deletes the data in the given time range from the given channels. The
+// channels themselves are not deleted.
+func (c *Client) Delete(ctx context.Context, tr telem.TimeRange, keys ...channel.Key) error {
+	_, err := c.t.frameDelete.Send(
+		ctx,
+		c.target(c.t.endpoints.frameDelete),
+		api.FrameDeleteRequest{Keys: keys, Bounds: tr},
+	)
+	return err
+}
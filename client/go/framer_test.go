@@ -0,0 +1,125 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/client"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Framer", func() {
+	for _, t := range transports {
+		Describe(string(t), Ordered, func() {
+			var (
+				c    *client.Client
+				idx  client.Channel
+				data client.Channel
+				keys channel.Keys
+			)
+			BeforeAll(func() {
+				c = openClient(t)
+				idx = MustSucceed(c.Channels.Create(ctx, client.Channel{
+					Name:     "framer_time_" + string(t),
+					DataType: telem.TimeStampT,
+					IsIndex:  true,
+				}))[0]
+				data = MustSucceed(c.Channels.Create(ctx, client.Channel{
+					Name:     "framer_data_" + string(t),
+					DataType: telem.Float64T,
+					Index:    idx.Key,
+				}))[0]
+				keys = channel.Keys{idx.Key, data.Key}
+			})
+			AfterAll(func() { Expect(c.Close()).To(Succeed()) })
+			Describe("Write and Read", func() {
+				It("Should write a frame and read it back", func() {
+					end := MustSucceed(c.Write(ctx, 1*telem.SecondTS, client.NewFrame(
+						keys,
+						telem.NewSecondsTSV(1, 2, 3),
+						telem.NewSeriesV[float64](1, 2, 3),
+					)))
+					Expect(end).To(Equal(3*telem.SecondTS + 1))
+					f := MustSucceed(c.Read(ctx, telem.TimeRangeMax, data.Key))
+					Expect(f.Keys).To(Equal(channel.Keys{data.Key}))
+					Expect(telem.Unmarshal[float64](f.Series[0])).To(Equal([]float64{1, 2, 3}))
+				})
+			})
+			Describe("Writer", func() {
+				It("Should write multiple frames in a single commit", func() {
+					w := MustSucceed(c.OpenWriter(ctx, client.WriterConfig{
+						Keys:  keys,
+						Start: 10 * telem.SecondTS,
+					}))
+					Expect(w.Write(client.NewFrame(
+						keys,
+						telem.NewSecondsTSV(10, 11),
+						telem.NewSeriesV[float64](10, 11),
+					))).To(Succeed())
+					Expect(w.Write(client.NewFrame(
+						keys,
+						telem.NewSecondsTSV(12),
+						telem.NewSeriesV[float64](12),
+					))).To(Succeed())
+					Expect(w.Commit()).To(Equal(12*telem.SecondTS + 1))
+					Expect(w.Close()).To(Succeed())
+				})
+				It("Should return an error when writing to a channel that doesn't exist", func() {
+					_, err := c.OpenWriter(ctx, client.WriterConfig{
+						Keys:  channel.Keys{channel.NewKey(1, 50000)},
+						Start: telem.SecondTS,
+					})
+					Expect(err).To(HaveOccurred())
+				})
+			})
+			Describe("Iterator", func() {
+				It("Should iterate over written data", func() {
+					iter := MustSucceed(c.OpenIterator(ctx, client.IteratorConfig{
+						Keys:      channel.Keys{data.Key},
+						Bounds:    (10 * telem.SecondTS).Range(13 * telem.SecondTS),
+						ChunkSize: 2,
+					}))
+					Expect(iter.SeekFirst()).To(BeTrue())
+					Expect(iter.Next(client.AutoSpan)).To(BeTrue())
+					Expect(telem.Unmarshal[float64](iter.Value().Series[0])).To(Equal([]float64{10, 11}))
+					Expect(iter.Next(client.AutoSpan)).To(BeTrue())
+					Expect(telem.Unmarshal[float64](iter.Value().Series[0])).To(Equal([]float64{12}))
+					Expect(iter.Next(client.AutoSpan)).To(BeFalse())
+					Expect(iter.Error()).To(Succeed())
+					Expect(iter.Close()).To(Succeed())
+				})
+			})
+			Describe("Streamer", func() {
+				It("Should receive frames written after the streamer is opened", func() {
+					s := MustSucceed(c.OpenStreamer(ctx, client.StreamerConfig{Keys: channel.Keys{data.Key}}))
+					MustSucceed(c.Write(ctx, 20*telem.SecondTS, client.NewFrame(
+						keys,
+						telem.NewSecondsTSV(20, 21),
+						telem.NewSeriesV[float64](20, 21),
+					)))
+					f := MustSucceed(s.Read())
+					Expect(f.Keys).To(Equal(channel.Keys{data.Key}))
+					Expect(telem.Unmarshal[float64](f.Series[0])).To(Equal([]float64{20, 21}))
+					Expect(s.Close()).To(Succeed())
+				})
+			})
+			Describe("Delete", func() {
+				It("Should delete data in a time range", func() {
+					Expect(c.Delete(ctx, (20 * telem.SecondTS).Range(22*telem.SecondTS), keys...)).To(Succeed())
+					f := MustSucceed(c.Read(ctx, (20 * telem.SecondTS).Range(22*telem.SecondTS), data.Key))
+					Expect(f.Series).To(BeEmpty())
+				})
+			})
+		})
+	}
+})
@@ -0,0 +1,194 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// AutoSpan can be passed to Iterator.Next and Iterator.Prev to move the iterator by
+// the configured chunk size instead of a fixed time span.
+const AutoSpan = iterator.AutoSpan
+
+// IteratorConfig is the configuration for opening an Iterator.
+type IteratorConfig struct {
+	// Keys are the keys of the channels to iterate over.
+	// [REQUIRED]
+	Keys channel.Keys
+	// Bounds is the time range the iterator is restricted to.
+	// [REQUIRED]
+	Bounds telem.TimeRange
+	// ChunkSize is the number of samples read per channel when moving the iterator
+	// with AutoSpan.
+	// [OPTIONAL]
+	ChunkSize int64
+	// Resample resamples the frames returned by the iterator onto a single index or
+	// fixed rate. See iterator.Resample for details.
+	// [OPTIONAL]
+	Resample iterator.Resample
+	// Scaled converts the values of channels that have a scale into engineering units.
+	// [OPTIONAL]
+	Scaled bool
+}
+
+// Iterator reads historical telemetry from a set of channels. The iterator must be
+// positioned using one of its seek methods before reading values. An Iterator is not
+// safe for concurrent use, and must be closed after use.
+type Iterator struct {
+	stream freighter.ClientStream[api.FrameIteratorRequest, api.FrameIteratorResponse]
+	value  []Frame
+	err    error
+}
+
+// OpenIterator opens a new Iterator using the given configuration.
+func (c *Client) OpenIterator(ctx context.Context, cfg IteratorConfig) (*Iterator, error) {
+	stream, err := c.t.frameIterator.Stream(ctx, c.target(c.t.endpoints.frameIterator))
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.Send(api.FrameIteratorRequest{
+		Keys:      cfg.Keys,
+		Bounds:    cfg.Bounds,
+		ChunkSize: cfg.ChunkSize,
+		Resample:  cfg.Resample,
+		Scaled:    cfg.Scaled,
+	}); err != nil {
+		return nil, errors.CombineErrors(err, stream.CloseSend())
+	}
+	if _, err = stream.Receive(); err != nil {
+		return nil, err
+	}
+	return &Iterator{stream: stream}, nil
+}
+
+// SeekFirst seeks the iterator to the start of its bounds, returning true if the
+// iterator has data to read.
+func (i *Iterator) SeekFirst() bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.SeekFirst})
+}
+
+// SeekLast seeks the iterator to the end of its bounds, returning true if the iterator
+// has data to read.
+func (i *Iterator) SeekLast() bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.SeekLast})
+}
+
+// SeekLE seeks the iterator to the first timestamp less than or equal to the given
+// timestamp, returning true if the iterator has data to read.
+func (i *Iterator) SeekLE(stamp telem.TimeStamp) bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.SeekLE, Stamp: stamp})
+}
+
+// SeekGE seeks the iterator to the first timestamp greater than or equal to the given
+// timestamp, returning true if the iterator has data to read.
+func (i *Iterator) SeekGE(stamp telem.TimeStamp) bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.SeekGE, Stamp: stamp})
+}
+
+// Next reads the data in the given span after the iterator's current position,
+// returning true if any data was read. Pass AutoSpan to read the configured chunk
+// size.
+func (i *Iterator) Next(span telem.TimeSpan) bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.Next, Span: span})
+}
+
+// Prev reads the data in the given span before the iterator's current position,
+// returning true if any data was read. Pass AutoSpan to read the configured chunk
+// size.
+func (i *Iterator) Prev(span telem.TimeSpan) bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.Prev, Span: span})
+}
+
+// Valid returns true if the iterator's current value is valid.
+func (i *Iterator) Valid() bool {
+	return i.exec(api.FrameIteratorRequest{Command: iterator.Valid})
+}
+
+// Value returns the data read by the iterator's last call to Next or Prev.
+func (i *Iterator) Value() Frame {
+	var f Frame
+	for _, v := range i.value {
+		f.Keys = append(f.Keys, v.Keys...)
+		f.Series = append(f.Series, v.Series...)
+	}
+	return f
+}
+
+// Error returns the error that caused the last command executed on the iterator to
+// fail, if any.
+func (i *Iterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+	if err := i.stream.Send(api.FrameIteratorRequest{Command: iterator.Error}); err != nil {
+		return err
+	}
+	res, err := i.receive(iterator.Error)
+	if err != nil {
+		return err
+	}
+	return res.Error
+}
+
+// Close closes the iterator, releasing any resources it holds on the node.
+func (i *Iterator) Close() error {
+	if err := i.stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		if _, err := i.stream.Receive(); err != nil {
+			if errors.Is(err, freighter.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (i *Iterator) exec(req api.FrameIteratorRequest) bool {
+	i.value = nil
+	if i.err != nil {
+		return false
+	}
+	if i.err = i.stream.Send(req); i.err != nil {
+		return false
+	}
+	res, err := i.receive(req.Command)
+	if err != nil {
+		i.err = err
+		return false
+	}
+	return res.Ack
+}
+
+// receive accumulates the data responses sent by the node until it acknowledges the
+// given command.
+func (i *Iterator) receive(cmd iterator.Command) (api.FrameIteratorResponse, error) {
+	for {
+		res, err := i.stream.Receive()
+		if err != nil {
+			return res, err
+		}
+		if res.Variant == iterator.DataResponse {
+			i.value = append(i.value, res.Frame)
+			continue
+		}
+		if res.Command == cmd {
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/query"
+)
+
+// Range is a user defined region of a cluster's data. It's identified by a name, time
+// range, and uniquely generated key.
+type Range = api.Range
+
+// RangeRetrieveRequest is used to filter the ranges returned by RangeClient.Retrieve.
+type RangeRetrieveRequest = api.RangeRetrieveRequest
+
+// RangeClient creates, retrieves, and deletes ranges, and provides access to their
+// key-value metadata and channel aliases.
+type RangeClient struct{ client *Client }
+
+// Create creates the given ranges, returning them with their keys assigned by the
+// node.
+func (r *RangeClient) Create(ctx context.Context, ranges ...Range) ([]Range, error) {
+	t := r.client.t
+	res, err := t.rangeCreate.Send(
+		ctx,
+		r.client.target(t.endpoints.rangeCreate),
+		api.RangeCreateRequest{Ranges: ranges},
+	)
+	return res.Ranges, err
+}
+
+// Retrieve retrieves the ranges matching the given request.
+func (r *RangeClient) Retrieve(ctx context.Context, req RangeRetrieveRequest) ([]Range, error) {
+	t := r.client.t
+	res, err := t.rangeRetrieve.Send(ctx, r.client.target(t.endpoints.rangeRetrieve), req)
+	return res.Ranges, err
+}
+
+// RetrieveByKey retrieves the range with the given key. Returns a query.NotFound
+// error if the range doesn't exist.
+func (r *RangeClient) RetrieveByKey(ctx context.Context, key uuid.UUID) (Range, error) {
+	ranges, err := r.Retrieve(ctx, RangeRetrieveRequest{Keys: []uuid.UUID{key}})
+	if err != nil {
+		return Range{}, err
+	}
+	if len(ranges) == 0 {
+		return Range{}, errors.Wrapf(query.NotFound, "range with key %s not found", key)
+	}
+	return ranges[0], nil
+}
+
+// Delete deletes the ranges with the given keys.
+func (r *RangeClient) Delete(ctx context.Context, keys ...uuid.UUID) error {
+	t := r.client.t
+	_, err := t.rangeDelete.Send(
+		ctx,
+		r.client.target(t.endpoints.rangeDelete),
+		api.RangeDeleteRequest{Keys: keys},
+	)
+	return err
+}
+
+// KV returns the key-value metadata store of the range with the given key.
+func (r *RangeClient) KV(rng uuid.UUID) *RangeKV {
+	return &RangeKV{client: r.client, rng: rng}
+}
+
+// Aliases returns the channel aliases of the range with the given key.
+func (r *RangeClient) Aliases(rng uuid.UUID) *RangeAliases {
+	return &RangeAliases{client: r.client, rng: rng}
+}
+
+// RangeKV is the key-value metadata store of a range.
+type RangeKV struct {
+	client *Client
+	rng    uuid.UUID
+}
+
+// Get returns the values of the given keys. If no keys are provided, all values in the
+// store are returned.
+func (kv *RangeKV) Get(ctx context.Context, keys ...string) (map[string]string, error) {
+	t := kv.client.t
+	res, err := t.rangeKVGet.Send(
+		ctx,
+		kv.client.target(t.endpoints.rangeKVGet),
+		api.RangeKVGetRequest{Range: kv.rng, Keys: keys},
+	)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(res.Pairs))
+	for _, p := range res.Pairs {
+		values[p.Key] = p.Value
+	}
+	return values, nil
+}
+
+// Set sets the given key-value pairs, overwriting any existing values.
+func (kv *RangeKV) Set(ctx context.Context, pairs map[string]string) error {
+	req := api.RangeKVSetRequest{Range: kv.rng, Pairs: make([]api.RangeKVPair, 0, len(pairs))}
+	for k, v := range pairs {
+		req.Pairs = append(req.Pairs, api.RangeKVPair{Range: kv.rng, Key: k, Value: v})
+	}
+	t := kv.client.t
+	_, err := t.rangeKVSet.Send(ctx, kv.client.target(t.endpoints.rangeKVSet), req)
+	return err
+}
+
+// Delete deletes the given keys.
+func (kv *RangeKV) Delete(ctx context.Context, keys ...string) error {
+	t := kv.client.t
+	_, err := t.rangeKVDelete.Send(
+		ctx,
+		kv.client.target(t.endpoints.rangeKVDelete),
+		api.RangeKVDeleteRequest{Range: kv.rng, Keys: keys},
+	)
+	return err
+}
+
+// RangeAliases are the alternative names that channels are given within a range.
+type RangeAliases struct {
+	client *Client
+	rng    uuid.UUID
+}
+
+// Set sets the aliases of the given channels.
+func (a *RangeAliases) Set(ctx context.Context, aliases map[channel.Key]string) error {
+	t := a.client.t
+	_, err := t.rangeAliasSet.Send(
+		ctx,
+		a.client.target(t.endpoints.rangeAliasSet),
+		api.RangeAliasSetRequest{Range: a.rng, Aliases: aliases},
+	)
+	return err
+}
+
+// Resolve returns the keys of the channels with the given aliases. Aliases that don't
+// exist are omitted from the result.
+func (a *RangeAliases) Resolve(ctx context.Context, aliases ...string) (map[string]channel.Key, error) {
+	t := a.client.t
+	res, err := t.rangeAliasResolve.Send(
+		ctx,
+		a.client.target(t.endpoints.rangeAliasResolve),
+		api.RangeAliasResolveRequest{Range: a.rng, Aliases: aliases},
+	)
+	return res.Aliases, err
+}
+
+// List returns the aliases of all channels in the range.
+func (a *RangeAliases) List(ctx context.Context) (map[channel.Key]string, error) {
+	t := a.client.t
+	res, err := t.rangeAliasList.Send(
+		ctx,
+		a.client.target(t.endpoints.rangeAliasList),
+		api.RangeAliasListRequest{Range: a.rng},
+	)
+	return res.Aliases, err
+}
+
+// Delete deletes the aliases of the given channels.
+func (a *RangeAliases) Delete(ctx context.Context, channels ...channel.Key) error {
+	t := a.client.t
+	_, err := t.rangeAliasDelete.Send(
+		ctx,
+		a.client.target(t.endpoints.rangeAliasDelete),
+		api.RangeAliasDeleteRequest{Range: a.rng, Channels: channels},
+	)
+	return err
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/client"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Range", func() {
+	for _, t := range transports {
+		Describe(string(t), Ordered, func() {
+			var (
+				c   *client.Client
+				rng client.Range
+			)
+			BeforeAll(func() {
+				c = openClient(t)
+				ranges := MustSucceed(c.Ranges.Create(ctx, client.Range{
+					Name:      "range_" + string(t),
+					TimeRange: telem.SecondTS.Range(10 * telem.SecondTS),
+				}))
+				Expect(ranges).To(HaveLen(1))
+				rng = ranges[0]
+			})
+			AfterAll(func() { Expect(c.Close()).To(Succeed()) })
+			Describe("Retrieve", func() {
+				It("Should retrieve a range by key", func() {
+					r := MustSucceed(c.Ranges.RetrieveByKey(ctx, rng.Key))
+					Expect(r.Name).To(Equal("range_" + string(t)))
+				})
+				It("Should retrieve ranges by name", func() {
+					ranges := MustSucceed(c.Ranges.Retrieve(ctx, client.RangeRetrieveRequest{
+						Names: []string{"range_" + string(t)},
+					}))
+					Expect(ranges).To(HaveLen(1))
+					Expect(ranges[0].Key).To(Equal(rng.Key))
+				})
+			})
+			Describe("KV", func() {
+				It("Should set, get, and delete key-value pairs", func() {
+					kv := c.Ranges.KV(rng.Key)
+					Expect(kv.Set(ctx, map[string]string{"a": "1", "b": "2"})).To(Succeed())
+					Expect(kv.Get(ctx, "a")).To(Equal(map[string]string{"a": "1"}))
+					Expect(kv.Get(ctx)).To(Equal(map[string]string{"a": "1", "b": "2"}))
+					Expect(kv.Delete(ctx, "a")).To(Succeed())
+					Expect(kv.Get(ctx)).To(Equal(map[string]string{"b": "2"}))
+				})
+			})
+			Describe("Aliases", func() {
+				It("Should set, resolve, list, and delete aliases", func() {
+					ch := MustSucceed(c.Channels.Create(ctx, client.Channel{
+						Name:     "aliased_" + string(t),
+						DataType: telem.Float64T,
+						Virtual:  true,
+					}))[0]
+					aliases := c.Ranges.Aliases(rng.Key)
+					Expect(aliases.Set(ctx, map[channel.Key]string{ch.Key: "alias"})).To(Succeed())
+					Expect(aliases.Resolve(ctx, "alias")).To(Equal(map[string]channel.Key{"alias": ch.Key}))
+					Expect(aliases.List(ctx)).To(Equal(map[channel.Key]string{ch.Key: "alias"}))
+					Expect(aliases.Delete(ctx, ch.Key)).To(Succeed())
+					Expect(aliases.List(ctx)).To(BeEmpty())
+				})
+			})
+			Describe("Delete", func() {
+				It("Should delete a range", func() {
+					r := MustSucceed(c.Ranges.Create(ctx, client.Range{
+						Name:      "delete_" + string(t),
+						TimeRange: telem.SecondTS.Range(2 * telem.SecondTS),
+					}))[0]
+					Expect(c.Ranges.Delete(ctx, r.Key)).To(Succeed())
+					_, err := c.Ranges.RetrieveByKey(ctx, r.Key)
+					Expect(err).To(HaveOccurredAs(query.NotFound))
+				})
+			})
+		})
+	}
+})
@@ -0,0 +1,74 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/errors"
+)
+
+// StreamerConfig is the configuration for opening a Streamer.
+type StreamerConfig = api.FrameStreamerRequest
+
+// Streamer receives frames of telemetry written to a set of channels in real-time. A
+// Streamer is not safe for concurrent use, and must be closed after use.
+type Streamer struct {
+	stream freighter.ClientStream[api.FrameStreamerRequest, api.FrameStreamerResponse]
+}
+
+// OpenStreamer opens a new Streamer using the given configuration. Only data written
+// after OpenStreamer returns is guaranteed to be received.
+func (c *Client) OpenStreamer(ctx context.Context, cfg StreamerConfig) (*Streamer, error) {
+	stream, err := c.t.frameStreamer.Stream(ctx, c.target(c.t.endpoints.frameStreamer))
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.Send(cfg); err != nil {
+		return nil, errors.CombineErrors(err, stream.CloseSend())
+	}
+	// The node sends an empty response once the streamer is ready.
+	if _, err = stream.Receive(); err != nil {
+		return nil, err
+	}
+	return &Streamer{stream: stream}, nil
+}
+
+// Read blocks until the next frame is received.
+func (s *Streamer) Read() (Frame, error) {
+	res, err := s.stream.Receive()
+	if err != nil {
+		return Frame{}, err
+	}
+	return res.Frame, res.Error
+}
+
+// Update replaces the channels the streamer receives data from.
+func (s *Streamer) Update(keys ...channel.Key) error {
+	return s.stream.Send(api.FrameStreamerRequest{Keys: keys})
+}
+
+// Close closes the streamer, discarding any frames that have not yet been read.
+func (s *Streamer) Close() error {
+	if err := s.stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		if _, err := s.stream.Receive(); err != nil {
+			if errors.Is(err, freighter.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"go/types"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/freighter/fgrpc"
+	"github.com/synnaxlabs/freighter/fhttp"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	grpcapi "github.com/synnaxlabs/synnax/pkg/api/grpc"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/writer"
+	"github.com/synnaxlabs/x/address"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/httputil"
+	"github.com/synnaxlabs/x/telem"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Transport is the protocol used to communicate with a Synnax node.
+type Transport string
+
+const (
+	// HTTP sends unary requests over HTTP and streams frames over websockets.
+	HTTP Transport = "http"
+	// GRPC sends all requests over gRPC.
+	GRPC Transport = "grpc"
+)
+
+// transport holds the clients for all API services used by the Go client. The
+// endpoints of each client are relative to the address of the node.
+type transport struct {
+	authLogin         freighter.UnaryClient[api.AuthLoginRequest, api.AuthLoginResponse]
+	channelCreate     freighter.UnaryClient[api.ChannelCreateRequest, api.ChannelCreateResponse]
+	channelRetrieve   freighter.UnaryClient[api.ChannelRetrieveRequest, api.ChannelRetrieveResponse]
+	channelDelete     freighter.UnaryClient[api.ChannelDeleteRequest, types.Nil]
+	rangeCreate       freighter.UnaryClient[api.RangeCreateRequest, api.RangeCreateResponse]
+	rangeRetrieve     freighter.UnaryClient[api.RangeRetrieveRequest, api.RangeRetrieveResponse]
+	rangeDelete       freighter.UnaryClient[api.RangeDeleteRequest, types.Nil]
+	rangeKVGet        freighter.UnaryClient[api.RangeKVGetRequest, api.RangeKVGetResponse]
+	rangeKVSet        freighter.UnaryClient[api.RangeKVSetRequest, types.Nil]
+	rangeKVDelete     freighter.UnaryClient[api.RangeKVDeleteRequest, types.Nil]
+	rangeAliasSet     freighter.UnaryClient[api.RangeAliasSetRequest, types.Nil]
+	rangeAliasResolve freighter.UnaryClient[api.RangeAliasResolveRequest, api.RangeAliasResolveResponse]
+	rangeAliasList    freighter.UnaryClient[api.RangeAliasListRequest, api.RangeAliasListResponse]
+	rangeAliasDelete  freighter.UnaryClient[api.RangeAliasDeleteRequest, types.Nil]
+	frameWriter       freighter.StreamClient[api.FrameWriterRequest, api.FrameWriterResponse]
+	frameIterator     freighter.StreamClient[api.FrameIteratorRequest, api.FrameIteratorResponse]
+	frameStreamer     freighter.StreamClient[api.FrameStreamerRequest, api.FrameStreamerResponse]
+	frameDelete       freighter.UnaryClient[api.FrameDeleteRequest, types.Nil]
+	// endpoints maps each client to the path it's served at, relative to the node.
+	endpoints endpoints
+	// close releases any resources held by the transport.
+	close func() error
+}
+
+type endpoints struct {
+	authLogin, channelCreate, channelRetrieve, channelDelete, rangeCreate,
+	rangeRetrieve, rangeDelete, rangeKVGet, rangeKVSet, rangeKVDelete, rangeAliasSet,
+	rangeAliasResolve, rangeAliasList, rangeAliasDelete, frameWriter, frameIterator,
+	frameStreamer, frameDelete string
+}
+
+// use adds the given middleware to every client in the transport except the login
+// client, which must be able to authenticate without credentials.
+func (t transport) use(middleware ...freighter.Middleware) {
+	for _, c := range []freighter.Transport{
+		t.channelCreate,
+		t.channelRetrieve,
+		t.channelDelete,
+		t.rangeCreate,
+		t.rangeRetrieve,
+		t.rangeDelete,
+		t.rangeKVGet,
+		t.rangeKVSet,
+		t.rangeKVDelete,
+		t.rangeAliasSet,
+		t.rangeAliasResolve,
+		t.rangeAliasList,
+		t.rangeAliasDelete,
+		t.frameWriter,
+		t.frameIterator,
+		t.frameStreamer,
+		t.frameDelete,
+	} {
+		c.Use(middleware...)
+	}
+}
+
+func newHTTPTransport(tlsCfg *tls.Config) transport {
+	f := fhttp.NewClientFactory(fhttp.ClientFactoryConfig{
+		Codec: httputil.JSONCodec,
+		TLS:   tlsCfg,
+	})
+	return transport{
+		authLogin:         fhttp.UnaryClient[api.AuthLoginRequest, api.AuthLoginResponse](f),
+		channelCreate:     fhttp.UnaryClient[api.ChannelCreateRequest, api.ChannelCreateResponse](f),
+		channelRetrieve:   fhttp.UnaryClient[api.ChannelRetrieveRequest, api.ChannelRetrieveResponse](f),
+		channelDelete:     fhttp.UnaryClient[api.ChannelDeleteRequest, types.Nil](f),
+		rangeCreate:       fhttp.UnaryClient[api.RangeCreateRequest, api.RangeCreateResponse](f),
+		rangeRetrieve:     fhttp.UnaryClient[api.RangeRetrieveRequest, api.RangeRetrieveResponse](f),
+		rangeDelete:       fhttp.UnaryClient[api.RangeDeleteRequest, types.Nil](f),
+		rangeKVGet:        fhttp.UnaryClient[api.RangeKVGetRequest, api.RangeKVGetResponse](f),
+		rangeKVSet:        fhttp.UnaryClient[api.RangeKVSetRequest, types.Nil](f),
+		rangeKVDelete:     fhttp.UnaryClient[api.RangeKVDeleteRequest, types.Nil](f),
+		rangeAliasSet:     fhttp.UnaryClient[api.RangeAliasSetRequest, types.Nil](f),
+		rangeAliasResolve: fhttp.UnaryClient[api.RangeAliasResolveRequest, api.RangeAliasResolveResponse](f),
+		rangeAliasList:    fhttp.UnaryClient[api.RangeAliasListRequest, api.RangeAliasListResponse](f),
+		rangeAliasDelete:  fhttp.UnaryClient[api.RangeAliasDeleteRequest, types.Nil](f),
+		frameWriter: &decodingStreamClient[api.FrameWriterRequest, api.FrameWriterResponse, frameWriterResponse]{
+			StreamClient: fhttp.StreamClient[api.FrameWriterRequest, frameWriterResponse](f),
+			decode:       frameWriterResponse.decode,
+		},
+		frameIterator: &decodingStreamClient[api.FrameIteratorRequest, api.FrameIteratorResponse, frameIteratorResponse]{
+			StreamClient: fhttp.StreamClient[api.FrameIteratorRequest, frameIteratorResponse](f),
+			decode:       frameIteratorResponse.decode,
+		},
+		frameStreamer: &decodingStreamClient[api.FrameStreamerRequest, api.FrameStreamerResponse, frameStreamerResponse]{
+			StreamClient: fhttp.StreamClient[api.FrameStreamerRequest, frameStreamerResponse](f),
+			decode:       frameStreamerResponse.decode,
+		},
+		frameDelete: fhttp.UnaryClient[api.FrameDeleteRequest, types.Nil](f),
+		endpoints: endpoints{
+			authLogin:         "/api/v1/auth/login",
+			channelCreate:     "/api/v1/channel/create",
+			channelRetrieve:   "/api/v1/channel/retrieve",
+			channelDelete:     "/api/v1/channel/delete",
+			rangeCreate:       "/api/v1/range/create",
+			rangeRetrieve:     "/api/v1/range/retrieve",
+			rangeDelete:       "/api/v1/range/delete",
+			rangeKVGet:        "/api/v1/range/kv/get",
+			rangeKVSet:        "/api/v1/range/kv/set",
+			rangeKVDelete:     "/api/v1/range/kv/delete",
+			rangeAliasSet:     "/api/v1/range/alias/set",
+			rangeAliasResolve: "/api/v1/range/alias/resolve",
+			rangeAliasList:    "/api/v1/range/alias/list",
+			rangeAliasDelete:  "/api/v1/range/alias/delete",
+			frameWriter:       "/api/v1/frame/write",
+			frameIterator:     "/api/v1/frame/iterate",
+			frameStreamer:     "/api/v1/frame/stream",
+			frameDelete:       "/api/v1/frame/delete",
+		},
+		close: func() error { return nil },
+	}
+}
+
+func newGRPCTransport(tlsCfg *tls.Config) transport {
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+	pool := fgrpc.NewPool(grpc.WithTransportCredentials(creds))
+	c := grpcapi.NewClient(pool)
+	// gRPC clients route requests using their service descriptors, so every endpoint
+	// is empty and requests are sent directly to the address of the node.
+	return transport{
+		authLogin:         c.AuthLogin,
+		channelCreate:     c.ChannelCreate,
+		channelRetrieve:   c.ChannelRetrieve,
+		channelDelete:     c.ChannelDelete,
+		rangeCreate:       c.RangeCreate,
+		rangeRetrieve:     c.RangeRetrieve,
+		rangeDelete:       c.RangeDelete,
+		rangeKVGet:        c.RangeKVGet,
+		rangeKVSet:        c.RangeKVSet,
+		rangeKVDelete:     c.RangeKVDelete,
+		rangeAliasSet:     c.RangeAliasSet,
+		rangeAliasResolve: c.RangeAliasResolve,
+		rangeAliasList:    c.RangeAliasList,
+		rangeAliasDelete:  c.RangeAliasDelete,
+		frameWriter:       c.FrameWriter,
+		frameIterator:     c.FrameIterator,
+		frameStreamer:     c.FrameStreamer,
+		frameDelete:       c.FrameDelete,
+		close:             pool.Close,
+	}
+}
+
+// The distribution layer responses sent over the frame streams carry errors as
+// encoded payloads, which can't be decoded into an error interface. The HTTP transport
+// decodes them into the following mirrors instead, and then decodes the payloads.
+
+type frameWriterResponse struct {
+	Command writer.Command  `json:"command" msgpack:"command"`
+	Ack     bool            `json:"ack" msgpack:"ack"`
+	SeqNum  int             `json:"seq_num" msgpack:"seq_num"`
+	Error   *errors.Payload `json:"error" msgpack:"error"`
+	End     telem.TimeStamp `json:"end" msgpack:"end"`
+}
+
+func (r frameWriterResponse) decode(ctx context.Context) api.FrameWriterResponse {
+	return api.FrameWriterResponse{
+		Command: r.Command,
+		Ack:     r.Ack,
+		SeqNum:  r.SeqNum,
+		Error:   decodeError(ctx, r.Error),
+		End:     r.End,
+	}
+}
+
+type frameIteratorResponse struct {
+	Variant iterator.ResponseVariant `json:"variant" msgpack:"variant"`
+	Command iterator.Command         `json:"command" msgpack:"command"`
+	Frame   api.Frame                `json:"frame" msgpack:"frame"`
+	Ack     bool                     `json:"ack" msgpack:"ack"`
+	SeqNum  int                      `json:"seq_num" msgpack:"seq_num"`
+	Error   *errors.Payload          `json:"error" msgpack:"error"`
+}
+
+func (r frameIteratorResponse) decode(ctx context.Context) api.FrameIteratorResponse {
+	return api.FrameIteratorResponse{
+		Variant: r.Variant,
+		Command: r.Command,
+		Frame:   r.Frame,
+		Ack:     r.Ack,
+		SeqNum:  r.SeqNum,
+		Error:   decodeError(ctx, r.Error),
+	}
+}
+
+type frameStreamerResponse struct {
+	Frame api.Frame       `json:"frame" msgpack:"frame"`
+	Error *errors.Payload `json:"error" msgpack:"error"`
+}
+
+func (r frameStreamerResponse) decode(ctx context.Context) api.FrameStreamerResponse {
+	return api.FrameStreamerResponse{Frame: r.Frame, Error: decodeError(ctx, r.Error)}
+}
+
+func decodeError(ctx context.Context, p *errors.Payload) error {
+	if p == nil {
+		return nil
+	}
+	return errors.Decode(ctx, *p)
+}
+
+// decodingStreamClient wraps a stream client that receives responses of type W,
+// decoding them into responses of type RS.
+type decodingStreamClient[RQ, RS, W freighter.Payload] struct {
+	freighter.StreamClient[RQ, W]
+	decode func(W, context.Context) RS
+}
+
+// Stream implements freighter.StreamClient.
+func (c *decodingStreamClient[RQ, RS, W]) Stream(
+	ctx context.Context,
+	target address.Address,
+) (freighter.ClientStream[RQ, RS], error) {
+	s, err := c.StreamClient.Stream(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return &decodingClientStream[RQ, RS, W]{ClientStream: s, ctx: ctx, decode: c.decode}, nil
+}
+
+type decodingClientStream[RQ, RS, W freighter.Payload] struct {
+	freighter.ClientStream[RQ, W]
+	ctx    context.Context
+	decode func(W, context.Context) RS
+}
+
+// Receive implements freighter.ClientStream.
+func (s *decodingClientStream[RQ, RS, W]) Receive() (res RS, err error) {
+	w, err := s.ClientStream.Receive()
+	if err != nil {
+		return res, err
+	}
+	return s.decode(w, s.ctx), nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/writer"
+	"github.com/synnaxlabs/x/control"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// WriterConfig is the configuration for opening a Writer. At a minimum, the keys of
+// the channels to write to and the starting timestamp of the write must be provided.
+type WriterConfig = api.FrameWriterConfig
+
+// Writer writes frames of telemetry to a set of channels. Written data is not durable
+// until it is committed. A Writer is not safe for concurrent use, and must be closed
+// after use.
+type Writer struct {
+	stream freighter.ClientStream[api.FrameWriterRequest, api.FrameWriterResponse]
+}
+
+// OpenWriter opens a new Writer using the given configuration.
+func (c *Client) OpenWriter(ctx context.Context, cfg WriterConfig) (*Writer, error) {
+	stream, err := c.t.frameWriter.Stream(ctx, c.target(c.t.endpoints.frameWriter))
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{stream: stream}
+	if err = stream.Send(api.FrameWriterRequest{Command: writer.Open, Config: cfg}); err != nil {
+		return nil, w.closeWithError(err)
+	}
+	// The node terminates the stream if it fails to open the writer, so there's no
+	// need to close it here.
+	if _, err = w.receive(writer.Open); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write writes the given frame to the writer's channels. Write does not wait for the
+// node to process the frame, so any errors encountered while writing are returned by
+// subsequent calls to Commit or Close.
+func (w *Writer) Write(frame Frame) error {
+	return w.stream.Send(api.FrameWriterRequest{Command: writer.Data, Frame: frame})
+}
+
+// Commit makes all written data durable and available to readers, returning the end
+// timestamp of the committed data.
+func (w *Writer) Commit() (telem.TimeStamp, error) {
+	if err := w.stream.Send(api.FrameWriterRequest{Command: writer.Commit}); err != nil {
+		return 0, err
+	}
+	res, err := w.receive(writer.Commit)
+	if err != nil {
+		return 0, err
+	}
+	if !res.Ack {
+		if err = w.Error(); err == nil {
+			err = errors.New("commit failed")
+		}
+		return 0, err
+	}
+	return res.End, nil
+}
+
+// SetAuthority sets the control authority of the writer over the given channels. If
+// no channels are provided, the authority is set on all of the writer's channels.
+func (w *Writer) SetAuthority(authority control.Authority, keys ...channel.Key) error {
+	req := api.FrameWriterRequest{
+		Command: writer.SetAuthority,
+		Config:  api.FrameWriterConfig{Keys: keys, Authorities: []uint32{uint32(authority)}},
+	}
+	return w.stream.Send(req)
+}
+
+// Error returns the error that caused the writer to fail, if any, and resets the
+// writer's error state so that it can continue writing.
+func (w *Writer) Error() error {
+	if err := w.stream.Send(api.FrameWriterRequest{Command: writer.Error}); err != nil {
+		return err
+	}
+	res, err := w.receive(writer.Error)
+	if err != nil {
+		return err
+	}
+	return res.Error
+}
+
+// Close closes the writer, discarding any uncommitted data. Close returns any error
+// the writer encountered that was not already returned by a previous call.
+func (w *Writer) Close() error { return w.closeWithError(nil) }
+
+func (w *Writer) closeWithError(err error) error {
+	if sErr := w.stream.CloseSend(); sErr != nil {
+		return errors.CombineErrors(err, sErr)
+	}
+	for {
+		res, rErr := w.stream.Receive()
+		if rErr != nil {
+			if errors.Is(rErr, freighter.EOF) {
+				return err
+			}
+			return errors.CombineErrors(err, rErr)
+		}
+		if res.Error != nil {
+			err = errors.CombineErrors(err, res.Error)
+		}
+	}
+}
+
+// receive waits for the response to the given command, skipping any control digests
+// the node sends in the meantime.
+func (w *Writer) receive(cmd writer.Command) (api.FrameWriterResponse, error) {
+	for {
+		res, err := w.stream.Receive()
+		if err != nil {
+			return res, err
+		}
+		if res.Variant != writer.Control && res.Command == cmd {
+			return res, nil
+		}
+	}
+}
@@ -10,6 +10,10 @@
 package fhttp
 
 import (
+	"crypto/tls"
+	"net/http"
+
+	ws "github.com/fasthttp/websocket"
 	"github.com/synnaxlabs/freighter"
 	"github.com/synnaxlabs/x/config"
 	"github.com/synnaxlabs/x/httputil"
@@ -19,6 +23,9 @@ import (
 
 type ClientFactoryConfig struct {
 	Codec httputil.Codec
+	// TLS is the TLS configuration to use when connecting to servers. If nil, clients
+	// will connect over plain HTTP and websockets.
+	TLS *tls.Config
 }
 
 func (c ClientFactoryConfig) Validate() error {
@@ -29,6 +36,7 @@ func (c ClientFactoryConfig) Validate() error {
 
 func (c ClientFactoryConfig) Override(other ClientFactoryConfig) ClientFactoryConfig {
 	c.Codec = override.Nil(c.Codec, other.Codec)
+	c.TLS = override.Nil(c.TLS, other.TLS)
 	return c
 }
 
@@ -49,9 +57,26 @@ func NewClientFactory(configs ...ClientFactoryConfig) *ClientFactory {
 }
 
 func StreamClient[RQ, RS freighter.Payload](c *ClientFactory) freighter.StreamClient[RQ, RS] {
-	return &streamClient[RQ, RS]{codec: c.Codec}
+	return &streamClient[RQ, RS]{
+		codec:  c.Codec,
+		secure: c.TLS != nil,
+		dialer: ws.Dialer{TLSClientConfig: c.TLS},
+	}
 }
 
 func UnaryClient[RQ, RS freighter.Payload](c *ClientFactory) freighter.UnaryClient[RQ, RS] {
-	return &unaryClient[RQ, RS]{codec: c.Codec}
+	return &unaryClient[RQ, RS]{
+		codec:  c.Codec,
+		secure: c.TLS != nil,
+		client: newHTTPClient(c.TLS),
+	}
+}
+
+func newHTTPClient(cfg *tls.Config) *http.Client {
+	if cfg == nil {
+		return &http.Client{}
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = cfg
+	return &http.Client{Transport: t}
 }
@@ -20,6 +20,7 @@ import (
 	ws "github.com/fasthttp/websocket"
 	"github.com/gofiber/fiber/v2"
 	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/samber/lo"
 	"github.com/synnaxlabs/alamos"
 	"github.com/synnaxlabs/freighter"
 	"github.com/synnaxlabs/x/address"
@@ -151,6 +152,7 @@ func (c *streamCore[I, O]) listenForContextCancellation() {
 type streamClient[RQ, RS freighter.Payload] struct {
 	alamos.Instrumentation
 	codec  httputil.Codec
+	secure bool
 	dialer ws.Dialer
 	freighter.Reporter
 	freighter.MiddlewareCollector
@@ -175,7 +177,11 @@ func (s *streamClient[RQ, RS]) Stream(
 		},
 		freighter.FinalizerFunc(func(ctx freighter.Context) (oCtx freighter.Context, err error) {
 			ctx.Params[fiber.HeaderContentType] = s.codec.ContentType()
-			conn, res, err := s.dialer.DialContext(ctx, "ws://"+target.String(), mdToHeaders(ctx))
+			conn, res, err := s.dialer.DialContext(
+				ctx,
+				lo.Ternary(s.secure, "wss://", "ws://")+target.String(),
+				mdToHeaders(ctx),
+			)
 			oCtx = parseResponseCtx(res, target)
 			if err != nil {
 				return oCtx, err
@@ -71,7 +71,9 @@ func (s *unaryServer[RQ, RS]) fiberHandler(c *fiber.Ctx) error {
 type unaryClient[RQ, RS freighter.Payload] struct {
 	freighter.Reporter
 	freighter.MiddlewareCollector
-	codec httputil.Codec
+	codec  httputil.Codec
+	secure bool
+	client *http.Client
 }
 
 func (u *unaryClient[RQ, RS]) Send(
@@ -84,6 +86,7 @@ func (u *unaryClient[RQ, RS]) Send(
 			Context:  ctx,
 			Protocol: unaryReporter.Protocol,
 			Target:   target,
+			Params:   make(freighter.Params),
 		},
 		freighter.FinalizerFunc(func(iMD freighter.Context) (oMD freighter.Context, err error) {
 			b, err := u.codec.Encode(nil, req)
@@ -93,7 +96,7 @@ func (u *unaryClient[RQ, RS]) Send(
 			httpReq, err := http.NewRequestWithContext(
 				ctx,
 				"POST",
-				"http://"+target.String(),
+				lo.Ternary(u.secure, "https://", "http://")+target.String(),
 				bytes.NewReader(b),
 			)
 			if err != nil {
@@ -102,7 +105,7 @@ func (u *unaryClient[RQ, RS]) Send(
 			setRequestCtx(httpReq, iMD)
 			httpReq.Header.Set(fiber.HeaderContentType, u.codec.ContentType())
 
-			httpRes, err := (&http.Client{}).Do(httpReq)
+			httpRes, err := u.client.Do(httpReq)
 			oMD = parseResponseCtx(httpRes, target)
 			if err != nil {
 				return oMD, err
@@ -163,9 +166,11 @@ func parseSecurityInfo(c *fiber.Ctx) (info freighter.SecurityInfo) {
 }
 
 func setRequestCtx(c *http.Request, ctx freighter.Context) {
+	// Servers parse request headers directly into params, so we don't prefix them
+	// like we do for websocket query strings.
 	for k, v := range ctx.Params {
 		if vStr, ok := v.(string); ok {
-			c.Header.Set(freighterCtxPrefix+k, vStr)
+			c.Header.Set(k, vStr)
 		}
 	}
 }
@@ -184,12 +189,15 @@ func parseResponseCtx(c *http.Response, target address.Address) freighter.Contex
 		Variant:  freighter.Unary,
 		Protocol: unaryReporter.Protocol,
 		Target:   target,
-		Params: lo.Ternary(
-			len(c.Header) > 0,
-			make(freighter.Params, len(c.Header)),
-			nil,
-		),
 	}
+	if c == nil {
+		return md
+	}
+	md.Params = lo.Ternary(
+		len(c.Header) > 0,
+		make(freighter.Params, len(c.Header)),
+		nil,
+	)
 	for k, v := range c.Header {
 		md.Params[k] = v[0]
 	}
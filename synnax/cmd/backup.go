@@ -0,0 +1,182 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/freighter/fhttp"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/storage"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"go.uber.org/zap"
+)
+
+const clusterKeyFlag = "cluster-key"
+
+var backupCmd = &cobra.Command{
+	Use:   "backup [archive]",
+	Short: "Take a backup of a running Synnax node.",
+	Long: `
+Takes a consistent, point-in-time backup of the key-value and time-series storage of the
+running Synnax node at the address specified by the --host flag. The node continues to
+accept reads and writes while the backup is in progress, although telemetry writes are
+briefly paused while the key-value and time-series storage are captured together. The
+archive is streamed from the node and written to the provided path on the local file
+system, which must not already exist. It can be restored into a fresh data directory
+using the restore command.
+	`,
+	Example: `synnax backup /mnt/backups/synnax.tar.gz --host localhost:9090`,
+	Args:    cobra.ExactArgs(1),
+	PreRun:  func(cmd *cobra.Command, _ []string) { bindFlags(cmd) },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ins, prettyLogger := configureInstrumentation("")
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+		c, err := openNodeClient(cmd.Context(), ins)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, xfs.OS_USER_RW)
+		if err != nil {
+			return err
+		}
+		m, err := receiveBackup(cmd.Context(), c, f)
+		if err = errors.CombineErrors(err, f.Close()); err != nil {
+			return errors.CombineErrors(err, os.Remove(path))
+		}
+		prettyLogger.Info(
+			"backup complete",
+			zap.String("path", path),
+			zap.Stringer("cluster_key", m.ClusterKey),
+			zap.String("version", m.Version),
+		)
+		return nil
+	},
+}
+
+// receiveBackup requests a backup from the node and writes the streamed archive to w,
+// returning the manifest of the backup.
+func receiveBackup(ctx context.Context, c nodeClient, w io.Writer) (storage.BackupManifest, error) {
+	client := fhttp.StreamClient[api.BackupCreateRequest, api.BackupCreateResponse](c.Factory)
+	client.Use(c.Auth)
+	stream, err := client.Stream(ctx, c.Host+"/api/v1/backup/create")
+	if err != nil {
+		return storage.BackupManifest{}, err
+	}
+	if err = stream.Send(api.BackupCreateRequest{}); err != nil {
+		return storage.BackupManifest{}, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return storage.BackupManifest{}, err
+	}
+	res, err := stream.Receive()
+	if err != nil {
+		return storage.BackupManifest{}, err
+	}
+	m := res.Manifest
+	for {
+		res, err = stream.Receive()
+		if errors.Is(err, freighter.EOF) {
+			return m, nil
+		}
+		if err != nil {
+			return m, err
+		}
+		if _, err = w.Write(res.Data); err != nil {
+			return m, err
+		}
+	}
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [archive]",
+	Short: "Restore a backup into a fresh data directory.",
+	Long: `
+Restores a backup archive produced by the backup command into the data directory
+specified by the --data flag. The directory must either not exist or be empty. If the
+--cluster-key flag is provided, the restore will fail if the backup was taken from a
+different cluster. If the backup was taken from an encrypted node, the
+--encryption-key-file flag must point to the key the node was encrypted with, and the
+restore will fail before writing any files if the key does not match. Once the restore
+completes, a node can be started on the restored directory using the start command.
+	`,
+	Example: `synnax restore /mnt/backups/synnax.tar.gz --data /mnt/ssd1`,
+	Args:    cobra.ExactArgs(1),
+	PreRun:  func(cmd *cobra.Command, _ []string) { bindFlags(cmd) },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, prettyLogger := configureInstrumentation("")
+		cfg := storage.RestoreConfig{Dirname: viper.GetString(dataFlag)}
+		if keyStr := viper.GetString(clusterKeyFlag); keyStr != "" {
+			key, err := uuid.Parse(keyStr)
+			if err != nil {
+				return errors.Wrap(err, "invalid cluster key")
+			}
+			cfg.ClusterKey = &key
+		}
+		key, err := readEncryptionKeyFile(viper.GetString(encryptionKeyFileFlag))
+		if err != nil {
+			return err
+		}
+		cfg.EncryptionKey = key
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		m, err := storage.Restore(f, cfg)
+		if err = errors.CombineErrors(err, f.Close()); err != nil {
+			return err
+		}
+		prettyLogger.Info(
+			"restore complete",
+			zap.String("data", cfg.Dirname),
+			zap.Stringer("cluster_key", m.ClusterKey),
+			zap.String("version", m.Version),
+			zap.Bool("encrypted", m.Encrypted),
+		)
+		return nil
+	},
+}
+
+func configureBackupFlags() {
+	configureClientFlags(backupCmd)
+	restoreCmd.Flags().StringP(
+		dataFlag,
+		"d",
+		"synnax-data",
+		"Dirname to restore the backup into.",
+	)
+	restoreCmd.Flags().String(
+		clusterKeyFlag,
+		"",
+		"The key of the cluster the backup is expected to belong to.",
+	)
+	restoreCmd.Flags().String(
+		encryptionKeyFileFlag,
+		"",
+		"Path to the file holding the key the backup was encrypted with. Required if, and only if, the backup is encrypted.",
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	configureBackupFlags()
+}
@@ -60,11 +60,36 @@ var certNode = &cobra.Command{
 	},
 }
 
+var certRotate = &cobra.Command{
+	Use:   "rotate [hosts...]",
+	Short: "Replace the node certificate with a new one issued by the existing CA.",
+	Long: `
+Issues a new node certificate and private key from the existing CA and atomically
+replaces the current node pair. If no hosts are provided, the hosts of the current node
+certificate are reused. A running node watches its certificate files and picks up the
+new pair for new connections without dropping existing ones.
+	`,
+	Example: `synnax cert rotate --certs-dir /usr/local/synnax/certs`,
+	RunE: func(cmd *cobra.Command, hosts []string) error {
+		ins, _ := configureInstrumentation("")
+		cfg := buildCertFactoryConfig(ins)
+		for _, host := range hosts {
+			cfg.Hosts = append(cfg.Hosts, address.Address(host))
+		}
+		factory, err := cert.NewFactory(cfg)
+		if err != nil {
+			return err
+		}
+		return factory.RotateNodePair()
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(certCmd)
 
 	certCmd.AddCommand(certCA)
 	certCmd.AddCommand(certNode)
+	certCmd.AddCommand(certRotate)
 }
 
 func buildCertLoaderConfig(ins alamos.Instrumentation) cert.LoaderConfig {
@@ -0,0 +1,104 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/freighter/fhttp"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/password"
+	"github.com/synnaxlabs/x/address"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/httputil"
+)
+
+const hostFlag = "host"
+
+// nodeClient is an authenticated connection to a running Synnax node.
+type nodeClient struct {
+	// Host is the address of the node.
+	Host address.Address
+	// Factory is used to open transport clients to the node.
+	Factory *fhttp.ClientFactory
+	// Auth is middleware that authenticates requests sent to the node. It must be
+	// used on every client opened using Factory.
+	Auth freighter.Middleware
+}
+
+// openNodeClient logs in to the node at the address specified by the --host flag using
+// the credentials specified by the --username and --password flags.
+func openNodeClient(ctx context.Context, ins alamos.Instrumentation) (nodeClient, error) {
+	secProvider, err := configureSecurity(ins, viper.GetBool(insecureFlag))
+	if err != nil {
+		return nodeClient{}, err
+	}
+	c := nodeClient{
+		Host: address.Address(viper.GetString(hostFlag)),
+		Factory: fhttp.NewClientFactory(fhttp.ClientFactoryConfig{
+			Codec: httputil.JSONCodec,
+			TLS:   secProvider.TLS(),
+		}),
+	}
+	login := fhttp.UnaryClient[api.AuthLoginRequest, api.AuthLoginResponse](c.Factory)
+	res, err := login.Send(ctx, c.Host+"/api/v1/auth/login", api.AuthLoginRequest{
+		InsecureCredentials: auth.InsecureCredentials{
+			Username: viper.GetString(usernameFlag),
+			Password: password.Raw(viper.GetString(passwordFlag)),
+		},
+	})
+	if err != nil {
+		return c, errors.Wrapf(err, "failed to log in to node at %s", c.Host)
+	}
+	c.Auth = authorizationMiddleware(res.Token)
+	return c, nil
+}
+
+// configureClientFlags adds the flags used by openNodeClient to the provided command.
+func configureClientFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
+		hostFlag,
+		"localhost:9090",
+		"The address of the Synnax node to connect to.",
+	)
+	cmd.Flags().BoolP(
+		insecureFlag,
+		"i",
+		false,
+		"Connect to the node without TLS.",
+	)
+	cmd.Flags().String(
+		usernameFlag,
+		"synnax",
+		"Username to authenticate with.",
+	)
+	cmd.Flags().String(
+		passwordFlag,
+		"seldon",
+		"Password to authenticate with.",
+	)
+}
+
+// authorizationMiddleware attaches the provided token to all outgoing requests.
+func authorizationMiddleware(token string) freighter.Middleware {
+	return freighter.MiddlewareFunc(func(
+		ctx freighter.Context,
+		next freighter.Next,
+	) (freighter.Context, error) {
+		ctx.Params[fiber.HeaderAuthorization] = "Bearer " + token
+		return next(ctx)
+	})
+}
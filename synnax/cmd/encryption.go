@@ -0,0 +1,126 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/synnaxlabs/synnax/pkg/storage"
+	"github.com/synnaxlabs/x/errors"
+	"go.uber.org/zap"
+)
+
+const newEncryptionKeyFileFlag = "new-encryption-key-file"
+
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Re-encrypt the data directory of a stopped node with a new key.",
+	Long: `
+Re-encrypts every file in the data directory specified by the --data flag, and the cold
+data directory specified by the --cold-data flag, with the key in the file specified by
+the --new-encryption-key-file flag. Files that are not encrypted yet are encrypted with
+the new key, so omitting the --encryption-key-file flag encrypts the data directory of
+a node that was started without encryption.
+
+Rotation is performed offline. The node must be stopped for the entire rotation, and the
+command refuses to run while the node holds the lock on its data directory. Data is not
+re-encrypted in the background while the node serves requests.
+
+Files are re-encrypted concurrently, and files that are already encrypted with the new
+key are skipped, so an interrupted rotation can be resumed by running the command again.
+Once the rotation completes, start the node with the new key file.
+	`,
+	Example: `synnax rotate-key --data /mnt/ssd1 --encryption-key-file old.key --new-encryption-key-file new.key`,
+	Args:    cobra.NoArgs,
+	PreRun:  func(cmd *cobra.Command, _ []string) { bindFlags(cmd) },
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ins, prettyLogger := configureInstrumentation("")
+		oldKey, err := readEncryptionKeyFile(viper.GetString(encryptionKeyFileFlag))
+		if err != nil {
+			return err
+		}
+		newKey, err := readEncryptionKeyFile(viper.GetString(newEncryptionKeyFileFlag))
+		if err != nil {
+			return err
+		}
+		if newKey == nil {
+			return errors.Newf("--%s must be provided", newEncryptionKeyFileFlag)
+		}
+		cfg := storage.RotateKeyConfig{
+			Instrumentation: ins.Child("storage"),
+			Dirname:         viper.GetString(dataFlag),
+			ColdDirname:     viper.GetString(coldDataFlag),
+			Key:             newKey,
+		}
+		if oldKey != nil {
+			cfg.PreviousKeys = [][]byte{oldKey}
+		}
+		n, err := storage.RotateKey(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		prettyLogger.Info(
+			"key rotation complete",
+			zap.String("data", cfg.Dirname),
+			zap.Int("files", n),
+		)
+		return nil
+	},
+}
+
+// readEncryptionKeyFile reads a hex encoded encryption key from the file at the given
+// path. It returns a nil key if the path is empty.
+func readEncryptionKeyFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read encryption key file %s", path)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "encryption key file %s must contain a hex encoded key", path)
+	}
+	return key, nil
+}
+
+func configureRotateKeyFlags() {
+	rotateKeyCmd.Flags().StringP(
+		dataFlag,
+		"d",
+		"synnax-data",
+		"Dirname of the data directory to re-encrypt.",
+	)
+	rotateKeyCmd.Flags().String(
+		coldDataFlag,
+		"",
+		"Dirname of the cold data directory to re-encrypt.",
+	)
+	rotateKeyCmd.Flags().String(
+		encryptionKeyFileFlag,
+		"",
+		"Path to the file holding the key the data directory is currently encrypted with. Omit to encrypt an unencrypted data directory.",
+	)
+	rotateKeyCmd.Flags().String(
+		newEncryptionKeyFileFlag,
+		"",
+		"Path to the file holding the key to re-encrypt the data directory with.",
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(rotateKeyCmd)
+	configureRotateKeyFlags()
+}
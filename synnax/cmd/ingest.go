@@ -0,0 +1,207 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/freighter/fhttp"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	"github.com/synnaxlabs/synnax/pkg/service/ingest"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+	"go.uber.org/zap"
+)
+
+const (
+	indexFlag      = "index"
+	columnFlag     = "column"
+	delimiterFlag  = "delimiter"
+	timeFormatFlag = "time-format"
+	chunkSizeFlag  = "chunk-size"
+	dryRunFlag     = "dry-run"
+)
+
+// ingestChunkSize is the maximum number of file bytes sent in a single
+// IngestCSVRequest.
+const ingestChunkSize = 512 * 1024
+
+var ingestCmd = &cobra.Command{
+	Use:   "ingest [file]",
+	Short: "Ingest a CSV file into a running Synnax node.",
+	Long: `
+Ingests a CSV file with a header row into the Synnax node at the address specified by
+the --host flag. The --index flag selects the column containing the timestamp of each
+row, which is written to an index channel. Each --column flag maps a column to a data
+channel in the form column[=channel][:data_type]. If no --column flags are provided,
+every other column is written to a channel with the same name as the column.
+
+Channels that do not exist are created. The data type of a created channel is inferred
+from the values in its column unless one is provided. Use the --dry-run flag to
+validate the file and see which channels would be created without writing any data.
+	`,
+	Example: `synnax ingest test.csv --index time --column pressure=pt_01:float32 --dry-run`,
+	Args:    cobra.ExactArgs(1),
+	PreRun:  func(cmd *cobra.Command, _ []string) { bindFlags(cmd) },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ins, prettyLogger := configureInstrumentation("")
+		if viper.GetString(indexFlag) == "" {
+			return errors.New("the --index flag must be provided")
+		}
+		req := api.IngestCSVRequest{
+			Mapping: ingest.Mapping{
+				Index: parseColumnSpec(viper.GetString(indexFlag)),
+			},
+			Delimiter:  viper.GetString(delimiterFlag),
+			TimeFormat: ingest.TimeFormat(viper.GetString(timeFormatFlag)),
+			ChunkSize:  viper.GetInt(chunkSizeFlag),
+			DryRun:     viper.GetBool(dryRunFlag),
+		}
+		for _, spec := range viper.GetStringSlice(columnFlag) {
+			req.Mapping.Columns = append(req.Mapping.Columns, parseColumnSpec(spec))
+		}
+		f, err := os.Open(filepath.Clean(args[0]))
+		if err != nil {
+			return err
+		}
+		c, err := openNodeClient(cmd.Context(), ins)
+		if err != nil {
+			return errors.CombineErrors(err, f.Close())
+		}
+		res, err := sendIngest(cmd.Context(), c, f, req)
+		if err = errors.CombineErrors(err, f.Close()); err != nil {
+			return err
+		}
+		r := res.Report
+		for _, ch := range append([]ingest.ChannelReport{r.Index}, r.Channels...) {
+			prettyLogger.Info(
+				"channel",
+				zap.String("column", ch.Column),
+				zap.String("channel", ch.Channel.Name),
+				zap.String("data_type", string(ch.Channel.DataType)),
+				zap.Bool("exists", ch.Exists),
+			)
+		}
+		for _, msg := range r.Errors {
+			prettyLogger.Error(msg)
+		}
+		if !r.Valid() {
+			return errors.Newf("file has %d problems", r.ErrorCount)
+		}
+		prettyLogger.Info(
+			lo.Ternary(req.DryRun, "validation complete", "ingestion complete"),
+			zap.Int("rows", r.Rows),
+			zap.Stringer("time_range", r.TimeRange),
+			zap.String("time_format", string(r.TimeFormat)),
+		)
+		return nil
+	},
+}
+
+// sendIngest streams the file in r to the node in chunks, sending the options in req
+// along with the first chunk, and returns the node's response once the file has been
+// processed.
+func sendIngest(
+	ctx context.Context,
+	c nodeClient,
+	r io.Reader,
+	req api.IngestCSVRequest,
+) (api.IngestCSVResponse, error) {
+	client := fhttp.StreamClient[api.IngestCSVRequest, api.IngestCSVResponse](c.Factory)
+	client.Use(c.Auth)
+	stream, err := client.Stream(ctx, c.Host+"/api/v1/ingest/csv")
+	if err != nil {
+		return api.IngestCSVResponse{}, err
+	}
+	buf := make([]byte, ingestChunkSize)
+	for first := true; ; first = false {
+		n, rErr := io.ReadFull(r, buf)
+		if rErr != nil && !errors.Is(rErr, io.EOF) && !errors.Is(rErr, io.ErrUnexpectedEOF) {
+			return api.IngestCSVResponse{}, errors.CombineErrors(rErr, stream.CloseSend())
+		}
+		if n > 0 || first {
+			req.Data = buf[:n]
+			// The node stops receiving early if the file fails validation or the
+			// subject is not allowed to ingest it, in which case the reason is
+			// returned by Receive.
+			if err = stream.Send(req); errors.Is(err, freighter.EOF) {
+				break
+			}
+			if err != nil {
+				return api.IngestCSVResponse{}, err
+			}
+			req = api.IngestCSVRequest{}
+		}
+		if rErr != nil {
+			break
+		}
+	}
+	if err = stream.CloseSend(); err != nil && !errors.Is(err, freighter.EOF) {
+		return api.IngestCSVResponse{}, err
+	}
+	return stream.Receive()
+}
+
+// parseColumnSpec parses a column mapping in the form column[=channel][:data_type].
+func parseColumnSpec(spec string) ingest.Column {
+	var c ingest.Column
+	if i := strings.LastIndex(spec, ":"); i != -1 {
+		spec, c.DataType = spec[:i], telem.DataType(spec[i+1:])
+	}
+	c.Column, c.Channel, _ = strings.Cut(spec, "=")
+	return c
+}
+
+func configureIngestFlags() {
+	configureClientFlags(ingestCmd)
+	ingestCmd.Flags().String(
+		indexFlag,
+		"",
+		"The column containing the timestamp of each row in the form column[=channel].",
+	)
+	ingestCmd.Flags().StringArray(
+		columnFlag,
+		nil,
+		"A column to ingest in the form column[=channel][:data_type]. Can be repeated.",
+	)
+	ingestCmd.Flags().String(
+		delimiterFlag,
+		",",
+		"The character separating columns in the file.",
+	)
+	ingestCmd.Flags().String(
+		timeFormatFlag,
+		"",
+		"The format of the index column (iso8601, unix_s, unix_ms, unix_us, or unix_ns). Detected automatically if not provided.",
+	)
+	ingestCmd.Flags().Int(
+		chunkSizeFlag,
+		ingest.DefaultChunkSize,
+		"The number of rows to write in a single frame.",
+	)
+	ingestCmd.Flags().Bool(
+		dryRunFlag,
+		false,
+		"Validate the file without creating channels or writing data.",
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(ingestCmd)
+	configureIngestFlags()
+}
@@ -33,14 +33,22 @@ import (
 	"github.com/synnaxlabs/synnax/pkg/server"
 	"github.com/synnaxlabs/synnax/pkg/service/access"
 	"github.com/synnaxlabs/synnax/pkg/service/access/rbac"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/synnax/pkg/service/alter"
 	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/oidc"
 	"github.com/synnaxlabs/synnax/pkg/service/auth/password"
 	"github.com/synnaxlabs/synnax/pkg/service/auth/token"
 	"github.com/synnaxlabs/synnax/pkg/service/framer"
 	"github.com/synnaxlabs/synnax/pkg/service/hardware"
 	"github.com/synnaxlabs/synnax/pkg/service/hardware/embedded"
+	"github.com/synnaxlabs/synnax/pkg/service/ingest"
 	"github.com/synnaxlabs/synnax/pkg/service/label"
+	"github.com/synnaxlabs/synnax/pkg/service/notify"
 	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/synnax/pkg/service/replay"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
+	"github.com/synnaxlabs/synnax/pkg/service/trash"
 	"github.com/synnaxlabs/synnax/pkg/service/user"
 	"github.com/synnaxlabs/synnax/pkg/service/workspace"
 	"github.com/synnaxlabs/synnax/pkg/service/workspace/lineplot"
@@ -54,6 +62,7 @@ import (
 	"github.com/synnaxlabs/x/errors"
 	"github.com/synnaxlabs/x/gorp"
 	xsignal "github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -62,6 +71,11 @@ import (
 
 const stopKeyWord = "stop"
 
+// tokenLifetime is the duration that session tokens issued by the node are valid for.
+// Keys retired by certificate reloads are kept for the same duration so that tokens
+// signed with them remain valid until they expire.
+const tokenLifetime = 24 * time.Hour
+
 var integrations = []string{"opc", "ni", "labjack"}
 
 func scanForStopKeyword(interruptC chan os.Signal) {
@@ -134,13 +148,21 @@ func start(cmd *cobra.Command) {
 		if err != nil {
 			return err
 		}
+		if interval := viper.GetDuration(certReloadIntervalFlag); !insecure && interval > 0 {
+			sCtx.Go(func(ctx context.Context) error {
+				return secProvider.Watch(ctx, interval)
+			}, xsignal.WithKey("cert-watcher"))
+		}
 
 		// An array to hold the grpcServerTransports we use for cluster internal communication.
 		grpcServerTransports := &[]fgrpc.BindableTransport{}
 		grpcClientPool := configureClientGRPC(secProvider, insecure)
 
 		// Open the distribution layer.
-		storageCfg := buildStorageConfig(ins)
+		storageCfg, err := buildStorageConfig(ins)
+		if err != nil {
+			return err
+		}
 		distConfig, err := buildDistributionConfig(
 			grpcClientPool,
 			ins,
@@ -169,12 +191,23 @@ func start(cmd *cobra.Command) {
 		if err != nil {
 			return err
 		}
-		rbacSvc, err := rbac.NewService(rbac.Config{DB: gorpDB})
+		roleSvc, err := role.OpenService(ctx, role.Config{
+			DB:       gorpDB,
+			Ontology: dist.Ontology,
+			Group:    dist.Group,
+		})
+		if err != nil {
+			return err
+		}
+		rbacSvc, err := rbac.NewService(rbac.Config{DB: gorpDB, Role: roleSvc})
+		if err != nil {
+			return err
+		}
+		tokenSvc := &token.Service{KeyProvider: secProvider, Expiration: tokenLifetime}
+		authenticator, err := configureAuthenticator(ins, gorpDB, userSvc)
 		if err != nil {
 			return err
 		}
-		tokenSvc := &token.Service{KeyProvider: secProvider, Expiration: 24 * time.Hour}
-		authenticator := &auth.KV{DB: gorpDB}
 		rangeSvc, err := ranger.OpenService(ctx, ranger.Config{
 			DB:       gorpDB,
 			Ontology: dist.Ontology,
@@ -225,9 +258,81 @@ func start(cmd *cobra.Command) {
 		if err != nil {
 			return err
 		}
+		ingestSvc, err := ingest.OpenService(ingest.Config{
+			Instrumentation: ins.Child("ingest"),
+			DB:              gorpDB,
+			Channel:         dist.Channel,
+			Framer:          dist.Framer,
+		})
+		if err != nil {
+			return err
+		}
+		alterSvc, err := alter.OpenService(alter.Config{
+			Instrumentation: ins.Child("alter"),
+			Channel:         dist.Channel,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.CombineErrors(err, alterSvc.Close())
+		}()
+		trashSvc, err := trash.OpenService(trash.Config{
+			Instrumentation: ins.Child("trash"),
+			DB:              gorpDB,
+			HostProvider:    dist.Cluster,
+			Channel:         dist.Channel,
+			Ranger:          rangeSvc,
+			Workspace:       workspaceSvc,
+			Schematic:       schematicSvc,
+			Retention:       telem.TimeSpan(viper.GetDuration(trashRetentionFlag)),
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.CombineErrors(err, trashSvc.Close())
+		}()
+		replaySvc, err := replay.OpenService(replay.ServiceConfig{
+			Instrumentation: ins.Child("replay"),
+			Channel:         dist.Channel,
+			Framer:          dist.Framer,
+		})
+		if err != nil {
+			return err
+		}
 		defer func() {
 			err = errors.CombineErrors(err, hardwareSvc.Close())
 		}()
+		alarmSvc, err := alarm.OpenService(ctx, alarm.Config{
+			Instrumentation: ins.Child("alarm"),
+			DB:              gorpDB,
+			Ontology:        dist.Ontology,
+			Group:           dist.Group,
+			HostProvider:    dist.Cluster,
+			Channel:         dist.Channel,
+			Framer:          dist.Framer,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.CombineErrors(err, alarmSvc.Close())
+		}()
+		notifySvc, err := notify.OpenService(ctx, notify.Config{
+			Instrumentation: ins.Child("notify"),
+			DB:              gorpDB,
+			Ontology:        dist.Ontology,
+			Group:           dist.Group,
+			HostProvider:    dist.Cluster,
+			Signals:         dist.Signals,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.CombineErrors(err, notifySvc.Close())
+		}()
 
 		// Provision the root user.
 		if err = maybeProvisionRootUser(ctx, gorpDB, authenticator, userSvc, rbacSvc); err != nil {
@@ -261,7 +366,13 @@ func start(cmd *cobra.Command) {
 			Log:             logSvc,
 			Workspace:       workspaceSvc,
 			Label:           labelSvc,
+			Role:            roleSvc,
 			Hardware:        hardwareSvc,
+			Ingest:          ingestSvc,
+			Alter:           alterSvc,
+			Replay:          replaySvc,
+			Alarm:           alarmSvc,
+			Notify:          notifySvc,
 		})
 		if err != nil {
 			return err
@@ -346,12 +457,20 @@ func init() {
 
 func buildStorageConfig(
 	ins alamos.Instrumentation,
-) storage.Config {
+) (storage.Config, error) {
+	key, err := readEncryptionKeyFile(viper.GetString(encryptionKeyFileFlag))
+	if err != nil {
+		return storage.Config{}, err
+	}
 	return storage.Config{
 		Instrumentation: ins.Child("storage"),
 		MemBacked:       config.Bool(viper.GetBool(memFlag)),
 		Dirname:         viper.GetString(dataFlag),
-	}
+		ColdDirname:     viper.GetString(coldDataFlag),
+		ColdAfter:       telem.TimeSpan(viper.GetDuration(coldAfterFlag)),
+		DiskQuota:       telem.Size(viper.GetFloat64(diskQuotaFlag) * float64(telem.Gigabyte)),
+		EncryptionKey:   key,
+	}, nil
 }
 
 func parsePeerAddresses() ([]address.Address, error) {
@@ -431,9 +550,10 @@ func buildEmbeddedDriverConfig(
 
 func configureSecurity(ins alamos.Instrumentation, insecure bool) (security.Provider, error) {
 	return security.NewProvider(security.ProviderConfig{
-		LoaderConfig: buildCertLoaderConfig(ins),
-		Insecure:     config.Bool(insecure),
-		KeySize:      viper.GetInt(keySizeFlag),
+		LoaderConfig:  buildCertLoaderConfig(ins),
+		Insecure:      config.Bool(insecure),
+		KeySize:       viper.GetInt(keySizeFlag),
+		RetiredKeyTTL: tokenLifetime,
 	})
 }
 
@@ -523,6 +643,42 @@ func maybeSetBasePermission(
 	})
 }
 
+// configureAuthenticator returns the authenticator used to log users in. Passwords
+// are always accepted, and ID tokens are also accepted if an OIDC issuer is configured.
+func configureAuthenticator(
+	ins alamos.Instrumentation,
+	db *gorp.DB,
+	userSvc *user.Service,
+) (auth.Authenticator, error) {
+	kv := &auth.KV{DB: db}
+	issuer := viper.GetString(oidcIssuerFlag)
+	if issuer == "" {
+		return kv, nil
+	}
+	cfg := oidc.Config{
+		Instrumentation: ins.Child("auth.oidc"),
+		Issuer:          issuer,
+		ClientID:        viper.GetString(oidcClientIDFlag),
+		UsernameClaim:   viper.GetString(oidcUsernameClaimFlag),
+		DB:              db,
+		User:            userSvc,
+	}
+	if path := viper.GetString(oidcJWKSFlag); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.KeySet, err = oidc.ParseJWKS(b); err != nil {
+			return nil, err
+		}
+	}
+	oidcAuth, err := oidc.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return auth.MultiAuthenticator{oidcAuth, kv}, nil
+}
+
 func maybeProvisionRootUser(
 	ctx context.Context,
 	db *gorp.DB,
@@ -27,6 +27,16 @@ const (
 	slowConsumerTimeoutFlag = "slow-consumer-timeout"
 	enableIntegrationsFlag  = "enable-integrations"
 	disableIntegrationsFlag = "disable-integrations"
+	certReloadIntervalFlag  = "cert-reload-interval"
+	oidcIssuerFlag          = "oidc-issuer"
+	oidcClientIDFlag        = "oidc-client-id"
+	oidcUsernameClaimFlag   = "oidc-username-claim"
+	oidcJWKSFlag            = "oidc-jwks"
+	trashRetentionFlag      = "trash-retention"
+	coldDataFlag            = "cold-data"
+	coldAfterFlag           = "cold-after"
+	diskQuotaFlag           = "disk-quota"
+	encryptionKeyFileFlag   = "encryption-key-file"
 )
 
 func configureStartFlags() {
@@ -63,6 +73,30 @@ func configureStartFlags() {
 		"Dirname where the synnax node will store its data.",
 	)
 
+	startCmd.Flags().String(
+		coldDataFlag,
+		"",
+		"Dirname of a secondary storage tier, such as a large disk or network mount, that old telemetry is moved to.",
+	)
+
+	startCmd.Flags().Duration(
+		coldAfterFlag,
+		7*24*time.Hour,
+		"How old telemetry must be before it is moved to the cold-data directory.",
+	)
+
+	startCmd.Flags().Float64(
+		diskQuotaFlag,
+		0,
+		"Maximum size, in gigabytes, of the telemetry stored by the node. The oldest telemetry is evicted when it is exceeded. 0 disables the quota.",
+	)
+
+	startCmd.Flags().String(
+		encryptionKeyFileFlag,
+		"",
+		"Path to a file holding a hex encoded 16, 24, or 32 byte AES key used to encrypt the data directory.",
+	)
+
 	startCmd.Flags().BoolP(
 		memFlag,
 		"m",
@@ -107,6 +141,42 @@ func configureStartFlags() {
 		"Terminate slow consumers of the relay after this timeout.",
 	)
 
+	startCmd.Flags().Duration(
+		certReloadIntervalFlag,
+		10*time.Second,
+		"Interval at which to check the node certificate for changes. Set to 0 to disable reloading.",
+	)
+
+	startCmd.Flags().Duration(
+		trashRetentionFlag,
+		30*24*time.Hour,
+		"How long deleted channels, ranges, workspaces, and schematics can be restored before they are purged.",
+	)
+
+	startCmd.Flags().String(
+		oidcIssuerFlag,
+		"",
+		"URL of an OIDC identity provider whose ID tokens can be used to log in.",
+	)
+
+	startCmd.Flags().String(
+		oidcClientIDFlag,
+		"",
+		"Client ID that Synnax is registered under with the OIDC identity provider.",
+	)
+
+	startCmd.Flags().String(
+		oidcUsernameClaimFlag,
+		"preferred_username",
+		"ID token claim to use as the username of OIDC users.",
+	)
+
+	startCmd.Flags().String(
+		oidcJWKSFlag,
+		"",
+		"Path to a JWKS file used to verify ID tokens instead of fetching keys from the OIDC identity provider.",
+	)
+
 	decodedName, _ := base64.StdEncoding.DecodeString("bGljZW5zZS1rZXk=")
 	decodedUsage, _ := base64.StdEncoding.DecodeString("TGljZW5zZSBrZXkgaW4gZm9ybSAiIyMjIyMjLSMjIyMjIyMjLSMjIyMjIyMjIyMiLg==")
 
@@ -0,0 +1,206 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"context"
+	"go/types"
+
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// AlarmService is the API for creating, retrieving, and deleting alarms, and for
+// acknowledging and shelving them.
+type AlarmService struct {
+	dbProvider
+	accessProvider
+	internal *alarm.Service
+}
+
+func NewAlarmService(p Provider) *AlarmService {
+	return &AlarmService{
+		dbProvider:     p.db,
+		accessProvider: p.access,
+		internal:       p.Config.Alarm,
+	}
+}
+
+type Alarm = alarm.Alarm
+
+// AlarmCreateRequest is a request to create alarms in the cluster.
+type AlarmCreateRequest struct {
+	// Alarms are the alarms to create. Alarms with an existing key are overwritten.
+	Alarms []Alarm `json:"alarms" msgpack:"alarms"`
+}
+
+// AlarmCreateResponse is a response to an AlarmCreateRequest.
+type AlarmCreateResponse struct {
+	// Alarms are the created alarms, with their keys assigned.
+	Alarms []Alarm `json:"alarms" msgpack:"alarms"`
+}
+
+// Create creates the alarms in the cluster. Alarms are evaluated by the node that
+// creates them.
+func (s *AlarmService) Create(ctx context.Context, req AlarmCreateRequest) (res AlarmCreateResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Create,
+		Objects: alarm.OntologyIDsFromAlarms(req.Alarms),
+	}); err != nil {
+		return res, err
+	}
+	return res, s.WithTx(ctx, func(tx gorp.Tx) error {
+		if err := s.internal.NewWriter(tx).CreateMany(ctx, &req.Alarms); err != nil {
+			return err
+		}
+		res.Alarms = req.Alarms
+		return nil
+	})
+}
+
+// AlarmRetrieveRequest is a request to retrieve alarms from the cluster.
+type AlarmRetrieveRequest struct {
+	Keys     []alarm.Key   `json:"keys" msgpack:"keys"`
+	Names    []string      `json:"names" msgpack:"names"`
+	Channels []channel.Key `json:"channels" msgpack:"channels"`
+	Search   string        `json:"search" msgpack:"search"`
+	Limit    int           `json:"limit" msgpack:"limit"`
+	Offset   int           `json:"offset" msgpack:"offset"`
+}
+
+// AlarmRetrieveResponse is a response to an AlarmRetrieveRequest.
+type AlarmRetrieveResponse struct {
+	Alarms []Alarm `json:"alarms" msgpack:"alarms"`
+}
+
+// Retrieve retrieves alarms from the cluster.
+func (s *AlarmService) Retrieve(ctx context.Context, req AlarmRetrieveRequest) (res AlarmRetrieveResponse, err error) {
+	q := s.internal.NewRetrieve()
+	if req.Search != "" {
+		q = q.Search(req.Search)
+	}
+	if len(req.Keys) > 0 {
+		q = q.WhereKeys(req.Keys...)
+	}
+	if len(req.Names) > 0 {
+		q = q.WhereNames(req.Names...)
+	}
+	if len(req.Channels) > 0 {
+		q = q.WhereChannels(req.Channels...)
+	}
+	if req.Limit > 0 {
+		q = q.Limit(req.Limit)
+	}
+	if req.Offset > 0 {
+		q = q.Offset(req.Offset)
+	}
+	if err = q.Entries(&res.Alarms).Exec(ctx, nil); err != nil {
+		return AlarmRetrieveResponse{}, err
+	}
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: alarm.OntologyIDsFromAlarms(res.Alarms),
+	}); err != nil {
+		return AlarmRetrieveResponse{}, err
+	}
+	return res, nil
+}
+
+// AlarmDeleteRequest is a request to delete alarms from the cluster.
+type AlarmDeleteRequest struct {
+	Keys []alarm.Key `json:"keys" msgpack:"keys"`
+}
+
+// Delete deletes alarms and their states from the cluster.
+func (s *AlarmService) Delete(ctx context.Context, req AlarmDeleteRequest) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: alarm.OntologyIDs(req.Keys),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Delete(ctx, req.Keys...)
+	})
+}
+
+// AlarmRetrieveStateRequest is a request to retrieve the current states of alarms.
+type AlarmRetrieveStateRequest struct {
+	Keys []alarm.Key `json:"keys" msgpack:"keys"`
+}
+
+// AlarmRetrieveStateResponse is a response to an AlarmRetrieveStateRequest.
+type AlarmRetrieveStateResponse struct {
+	States []alarm.State `json:"states" msgpack:"states"`
+}
+
+// RetrieveState retrieves the current states of alarms.
+func (s *AlarmService) RetrieveState(
+	ctx context.Context,
+	req AlarmRetrieveStateRequest,
+) (res AlarmRetrieveStateResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: alarm.OntologyIDs(req.Keys),
+	}); err != nil {
+		return res, err
+	}
+	res.States, err = s.internal.RetrieveStates(ctx, nil, req.Keys...)
+	return res, err
+}
+
+// AlarmAcknowledgeRequest is a request to acknowledge active alarms.
+type AlarmAcknowledgeRequest struct {
+	Keys []alarm.Key `json:"keys" msgpack:"keys"`
+}
+
+// Acknowledge acknowledges active alarms. Alarms that are not active are left
+// unchanged.
+func (s *AlarmService) Acknowledge(ctx context.Context, req AlarmAcknowledgeRequest) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: alarm.OntologyIDs(req.Keys),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Acknowledge(ctx, req.Keys...)
+	})
+}
+
+// AlarmShelveRequest is a request to shelve alarms.
+type AlarmShelveRequest struct {
+	Keys []alarm.Key `json:"keys" msgpack:"keys"`
+	// Duration is the amount of time to shelve the alarms for. A duration of zero
+	// un-shelves the alarms.
+	Duration telem.TimeSpan `json:"duration" msgpack:"duration"`
+}
+
+// Shelve suppresses alarms for a duration, or un-shelves them if the duration is zero.
+func (s *AlarmService) Shelve(ctx context.Context, req AlarmShelveRequest) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: alarm.OntologyIDs(req.Keys),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Shelve(ctx, req.Duration, req.Keys...)
+	})
+}
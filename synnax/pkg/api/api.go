@@ -27,12 +27,18 @@ import (
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
 	"github.com/synnaxlabs/synnax/pkg/service/access"
 	"github.com/synnaxlabs/synnax/pkg/service/access/rbac"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/synnax/pkg/service/alter"
 	"github.com/synnaxlabs/synnax/pkg/service/auth"
 	"github.com/synnaxlabs/synnax/pkg/service/auth/token"
 	"github.com/synnaxlabs/synnax/pkg/service/framer"
 	"github.com/synnaxlabs/synnax/pkg/service/hardware"
+	"github.com/synnaxlabs/synnax/pkg/service/ingest"
 	"github.com/synnaxlabs/synnax/pkg/service/label"
+	"github.com/synnaxlabs/synnax/pkg/service/notify"
 	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/synnax/pkg/service/replay"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
 	"github.com/synnaxlabs/synnax/pkg/service/user"
 	"github.com/synnaxlabs/synnax/pkg/service/workspace"
 	"github.com/synnaxlabs/synnax/pkg/service/workspace/lineplot"
@@ -63,7 +69,13 @@ type Config struct {
 	Table         *table.Service
 	Token         *token.Service
 	Label         *label.Service
+	Role          *role.Service
 	Hardware      *hardware.Service
+	Ingest        *ingest.Service
+	Alter         *alter.Service
+	Replay        *replay.Service
+	Alarm         *alarm.Service
+	Notify        *notify.Service
 	Authenticator auth.Authenticator
 	Enforcer      access.Enforcer
 	Cluster       dcore.Cluster
@@ -95,8 +107,14 @@ func (c Config) Validate() error {
 	validate.NotNil(v, "hardware", c.Hardware)
 	validate.NotNil(v, "insecure", c.Insecure)
 	validate.NotNil(v, "label", c.Label)
+	validate.NotNil(v, "role", c.Role)
 	validate.NotNil(v, "log", c.Log)
 	validate.NotNil(v, "table", c.Table)
+	validate.NotNil(v, "ingest", c.Ingest)
+	validate.NotNil(v, "alter", c.Alter)
+	validate.NotNil(v, "replay", c.Replay)
+	validate.NotNil(v, "alarm", c.Alarm)
+	validate.NotNil(v, "notify", c.Notify)
 	return v.Error()
 }
 
@@ -121,9 +139,15 @@ func (c Config) Override(other Config) Config {
 	c.LinePlot = override.Nil(c.LinePlot, other.LinePlot)
 	c.Log = override.Nil(c.Log, other.Log)
 	c.Label = override.Nil(c.Label, other.Label)
+	c.Role = override.Nil(c.Role, other.Role)
 	c.Enforcer = override.Nil(c.Enforcer, other.Enforcer)
 	c.Hardware = override.Nil(c.Hardware, other.Hardware)
 	c.Table = override.Nil(c.Table, other.Table)
+	c.Ingest = override.Nil(c.Ingest, other.Ingest)
+	c.Alter = override.Nil(c.Alter, other.Alter)
+	c.Replay = override.Nil(c.Replay, other.Replay)
+	c.Alarm = override.Nil(c.Alarm, other.Alarm)
+	c.Notify = override.Nil(c.Notify, other.Notify)
 	return c
 }
 
@@ -134,15 +158,20 @@ type Transport struct {
 	// USER
 	UserRename         freighter.UnaryServer[UserRenameRequest, types.Nil]
 	UserChangeUsername freighter.UnaryServer[UserChangeUsernameRequest, types.Nil]
+	UserLinkIdentity   freighter.UnaryServer[UserLinkIdentityRequest, types.Nil]
 	UserCreate         freighter.UnaryServer[UserCreateRequest, UserCreateResponse]
 	UserDelete         freighter.UnaryServer[UserDeleteRequest, types.Nil]
 	UserRetrieve       freighter.UnaryServer[UserRetrieveRequest, UserRetrieveResponse]
 	// CHANNEL
-	ChannelCreate        freighter.UnaryServer[ChannelCreateRequest, ChannelCreateResponse]
-	ChannelRetrieve      freighter.UnaryServer[ChannelRetrieveRequest, ChannelRetrieveResponse]
-	ChannelDelete        freighter.UnaryServer[ChannelDeleteRequest, types.Nil]
-	ChannelRename        freighter.UnaryServer[ChannelRenameRequest, types.Nil]
-	ChannelRetrieveGroup freighter.UnaryServer[ChannelRetrieveGroupRequest, ChannelRetrieveGroupResponse]
+	ChannelCreate         freighter.UnaryServer[ChannelCreateRequest, ChannelCreateResponse]
+	ChannelRetrieve       freighter.UnaryServer[ChannelRetrieveRequest, ChannelRetrieveResponse]
+	ChannelDelete         freighter.UnaryServer[ChannelDeleteRequest, types.Nil]
+	ChannelRestore        freighter.UnaryServer[ChannelRestoreRequest, types.Nil]
+	ChannelRename         freighter.UnaryServer[ChannelRenameRequest, types.Nil]
+	ChannelUpdateMetadata freighter.UnaryServer[ChannelUpdateMetadataRequest, types.Nil]
+	ChannelRetrieveGroup  freighter.UnaryServer[ChannelRetrieveGroupRequest, ChannelRetrieveGroupResponse]
+	ChannelAlter          freighter.UnaryServer[ChannelAlterRequest, ChannelAlterResponse]
+	ChannelRetrieveAlter  freighter.UnaryServer[ChannelRetrieveAlterRequest, ChannelRetrieveAlterResponse]
 	// CONNECTIVITY
 	ConnectivityCheck freighter.UnaryServer[types.Nil, ConnectivityCheckResponse]
 	// FRAME
@@ -154,6 +183,7 @@ type Transport struct {
 	RangeCreate       freighter.UnaryServer[RangeCreateRequest, RangeCreateResponse]
 	RangeRetrieve     freighter.UnaryServer[RangeRetrieveRequest, RangeRetrieveResponse]
 	RangeDelete       freighter.UnaryServer[RangeDeleteRequest, types.Nil]
+	RangeRestore      freighter.UnaryServer[RangeRestoreRequest, types.Nil]
 	RangeKVGet        freighter.UnaryServer[RangeKVGetRequest, RangeKVGetResponse]
 	RangeKVSet        freighter.UnaryServer[RangeKVSetRequest, types.Nil]
 	RangeKVDelete     freighter.UnaryServer[RangeKVDeleteRequest, types.Nil]
@@ -175,12 +205,14 @@ type Transport struct {
 	WorkspaceCreate    freighter.UnaryServer[WorkspaceCreateRequest, WorkspaceCreateResponse]
 	WorkspaceRetrieve  freighter.UnaryServer[WorkspaceRetrieveRequest, WorkspaceRetrieveResponse]
 	WorkspaceDelete    freighter.UnaryServer[WorkspaceDeleteRequest, types.Nil]
+	WorkspaceRestore   freighter.UnaryServer[WorkspaceRestoreRequest, types.Nil]
 	WorkspaceRename    freighter.UnaryServer[WorkspaceRenameRequest, types.Nil]
 	WorkspaceSetLayout freighter.UnaryServer[WorkspaceSetLayoutRequest, types.Nil]
 	// SCHEMATIC
 	SchematicCreate   freighter.UnaryServer[SchematicCreateRequest, SchematicCreateResponse]
 	SchematicRetrieve freighter.UnaryServer[SchematicRetrieveRequest, SchematicRetrieveResponse]
 	SchematicDelete   freighter.UnaryServer[SchematicDeleteRequest, types.Nil]
+	SchematicRestore  freighter.UnaryServer[SchematicRestoreRequest, types.Nil]
 	SchematicRename   freighter.UnaryServer[SchematicRenameRequest, types.Nil]
 	SchematicSetData  freighter.UnaryServer[SchematicSetDataRequest, types.Nil]
 	SchematicCopy     freighter.UnaryServer[SchematicCopyRequest, SchematicCopyResponse]
@@ -223,6 +255,34 @@ type Transport struct {
 	AccessCreatePolicy   freighter.UnaryServer[AccessCreatePolicyRequest, AccessCreatePolicyResponse]
 	AccessDeletePolicy   freighter.UnaryServer[AccessDeletePolicyRequest, types.Nil]
 	AccessRetrievePolicy freighter.UnaryServer[AccessRetrievePolicyRequest, AccessRetrievePolicyResponse]
+	// BACKUP
+	BackupCreate freighter.StreamServer[BackupCreateRequest, BackupCreateResponse]
+	// INGEST
+	IngestCSV freighter.StreamServer[IngestCSVRequest, IngestCSVResponse]
+	// REPLAY
+	ReplayStream freighter.StreamServer[ReplayRequest, ReplayResponse]
+	// STORAGE
+	StorageUsage          freighter.UnaryServer[StorageUsageRequest, StorageUsageResponse]
+	StorageGarbageCollect freighter.StreamServer[StorageGCRequest, StorageGCResponse]
+	// ALARM
+	AlarmCreate        freighter.UnaryServer[AlarmCreateRequest, AlarmCreateResponse]
+	AlarmRetrieve      freighter.UnaryServer[AlarmRetrieveRequest, AlarmRetrieveResponse]
+	AlarmDelete        freighter.UnaryServer[AlarmDeleteRequest, types.Nil]
+	AlarmRetrieveState freighter.UnaryServer[AlarmRetrieveStateRequest, AlarmRetrieveStateResponse]
+	AlarmAcknowledge   freighter.UnaryServer[AlarmAcknowledgeRequest, types.Nil]
+	AlarmShelve        freighter.UnaryServer[AlarmShelveRequest, types.Nil]
+	// NOTIFY
+	NotifySubscriptionCreate   freighter.UnaryServer[NotifySubscriptionCreateRequest, NotifySubscriptionCreateResponse]
+	NotifySubscriptionRetrieve freighter.UnaryServer[NotifySubscriptionRetrieveRequest, NotifySubscriptionRetrieveResponse]
+	NotifySubscriptionDelete   freighter.UnaryServer[NotifySubscriptionDeleteRequest, types.Nil]
+	NotifyDeadLetterRetrieve   freighter.UnaryServer[NotifyDeadLetterRetrieveRequest, NotifyDeadLetterRetrieveResponse]
+	// ROLE
+	RoleCreate          freighter.UnaryServer[RoleCreateRequest, RoleCreateResponse]
+	RoleRetrieve        freighter.UnaryServer[RoleRetrieveRequest, RoleRetrieveResponse]
+	RoleDelete          freighter.UnaryServer[RoleDeleteRequest, types.Nil]
+	RoleAddMembers      freighter.UnaryServer[RoleAddMembersRequest, types.Nil]
+	RoleRemoveMembers   freighter.UnaryServer[RoleRemoveMembersRequest, types.Nil]
+	RoleRetrieveMembers freighter.UnaryServer[RoleRetrieveMembersRequest, RoleRetrieveMembersResponse]
 }
 
 // API wraps all implemented API services into a single container. Protocol-specific API
@@ -245,6 +305,13 @@ type API struct {
 	Label        *LabelService
 	Hardware     *HardwareService
 	Access       *AccessService
+	Backup       *BackupService
+	Ingest       *IngestService
+	Replay       *ReplayService
+	Storage      *StorageService
+	Alarm        *AlarmService
+	Notify       *NotifyService
+	Role         *RoleService
 }
 
 // BindTo binds the API to the provided Transport implementation.
@@ -273,6 +340,7 @@ func (a *API) BindTo(t Transport) {
 		// USER
 		t.UserRename,
 		t.UserChangeUsername,
+		t.UserLinkIdentity,
 		t.UserCreate,
 		t.UserDelete,
 		t.UserRetrieve,
@@ -281,8 +349,12 @@ func (a *API) BindTo(t Transport) {
 		t.ChannelCreate,
 		t.ChannelRetrieve,
 		t.ChannelDelete,
+		t.ChannelRestore,
 		t.ChannelRename,
+		t.ChannelUpdateMetadata,
 		t.ChannelRetrieveGroup,
+		t.ChannelAlter,
+		t.ChannelRetrieveAlter,
 
 		// FRAME
 		t.FrameWriter,
@@ -305,6 +377,7 @@ func (a *API) BindTo(t Transport) {
 		t.RangeCreate,
 		t.RangeRetrieve,
 		t.RangeDelete,
+		t.RangeRestore,
 		t.RangeKVGet,
 		t.RangeKVSet,
 		t.RangeKVDelete,
@@ -316,6 +389,7 @@ func (a *API) BindTo(t Transport) {
 
 		// WORKSPACE
 		t.WorkspaceDelete,
+		t.WorkspaceRestore,
 		t.WorkspaceCreate,
 		t.WorkspaceRetrieve,
 		t.WorkspaceRename,
@@ -325,6 +399,7 @@ func (a *API) BindTo(t Transport) {
 		t.SchematicCreate,
 		t.SchematicRetrieve,
 		t.SchematicDelete,
+		t.SchematicRestore,
 		t.SchematicRename,
 		t.SchematicSetData,
 		t.SchematicCopy,
@@ -374,6 +449,41 @@ func (a *API) BindTo(t Transport) {
 		t.AccessCreatePolicy,
 		t.AccessDeletePolicy,
 		t.AccessRetrievePolicy,
+
+		// BACKUP
+		t.BackupCreate,
+
+		// INGEST
+		t.IngestCSV,
+
+		// REPLAY
+		t.ReplayStream,
+
+		// STORAGE
+		t.StorageUsage,
+		t.StorageGarbageCollect,
+
+		// ALARM
+		t.AlarmCreate,
+		t.AlarmRetrieve,
+		t.AlarmDelete,
+		t.AlarmRetrieveState,
+		t.AlarmAcknowledge,
+		t.AlarmShelve,
+
+		// NOTIFY
+		t.NotifySubscriptionCreate,
+		t.NotifySubscriptionRetrieve,
+		t.NotifySubscriptionDelete,
+		t.NotifyDeadLetterRetrieve,
+
+		// ROLE
+		t.RoleCreate,
+		t.RoleRetrieve,
+		t.RoleDelete,
+		t.RoleAddMembers,
+		t.RoleRemoveMembers,
+		t.RoleRetrieveMembers,
 	)
 
 	// AUTH
@@ -383,6 +493,7 @@ func (a *API) BindTo(t Transport) {
 	// USER
 	t.UserRename.BindHandler(a.User.Rename)
 	t.UserChangeUsername.BindHandler(a.User.ChangeUsername)
+	t.UserLinkIdentity.BindHandler(a.User.LinkIdentity)
 	t.UserCreate.BindHandler(a.User.Create)
 	t.UserDelete.BindHandler(a.User.Delete)
 	t.UserRetrieve.BindHandler(a.User.Retrieve)
@@ -392,8 +503,12 @@ func (a *API) BindTo(t Transport) {
 	t.ChannelRetrieve.BindHandler(a.Channel.Retrieve)
 	t.ConnectivityCheck.BindHandler(a.Connectivity.Check)
 	t.ChannelDelete.BindHandler(a.Channel.Delete)
+	t.ChannelRestore.BindHandler(a.Channel.Restore)
 	t.ChannelRename.BindHandler(a.Channel.Rename)
+	t.ChannelUpdateMetadata.BindHandler(a.Channel.UpdateMetadata)
 	t.ChannelRetrieveGroup.BindHandler(a.Channel.RetrieveGroup)
+	t.ChannelAlter.BindHandler(a.Channel.Alter)
+	t.ChannelRetrieveAlter.BindHandler(a.Channel.RetrieveAlter)
 
 	// FRAME
 	t.FrameWriter.BindHandler(a.Framer.Write)
@@ -416,6 +531,7 @@ func (a *API) BindTo(t Transport) {
 	t.RangeRetrieve.BindHandler(a.Range.Retrieve)
 	t.RangeCreate.BindHandler(a.Range.Create)
 	t.RangeDelete.BindHandler(a.Range.Delete)
+	t.RangeRestore.BindHandler(a.Range.Restore)
 	t.RangeRename.BindHandler(a.Range.Rename)
 	t.RangeKVGet.BindHandler(a.Range.KVGet)
 	t.RangeKVSet.BindHandler(a.Range.KVSet)
@@ -428,6 +544,7 @@ func (a *API) BindTo(t Transport) {
 	// WORKSPACE
 	t.WorkspaceCreate.BindHandler(a.Workspace.Create)
 	t.WorkspaceDelete.BindHandler(a.Workspace.Delete)
+	t.WorkspaceRestore.BindHandler(a.Workspace.Restore)
 	t.WorkspaceRetrieve.BindHandler(a.Workspace.Retrieve)
 	t.WorkspaceRename.BindHandler(a.Workspace.Rename)
 	t.WorkspaceSetLayout.BindHandler(a.Workspace.SetLayout)
@@ -436,6 +553,7 @@ func (a *API) BindTo(t Transport) {
 	t.SchematicCreate.BindHandler(a.Schematic.Create)
 	t.SchematicRetrieve.BindHandler(a.Schematic.Retrieve)
 	t.SchematicDelete.BindHandler(a.Schematic.Delete)
+	t.SchematicRestore.BindHandler(a.Schematic.Restore)
 	t.SchematicRename.BindHandler(a.Schematic.Rename)
 	t.SchematicSetData.BindHandler(a.Schematic.SetData)
 	t.SchematicCopy.BindHandler(a.Schematic.Copy)
@@ -484,6 +602,41 @@ func (a *API) BindTo(t Transport) {
 	t.AccessCreatePolicy.BindHandler(a.Access.CreatePolicy)
 	t.AccessDeletePolicy.BindHandler(a.Access.DeletePolicy)
 	t.AccessRetrievePolicy.BindHandler(a.Access.RetrievePolicy)
+
+	// BACKUP
+	t.BackupCreate.BindHandler(a.Backup.Create)
+
+	// INGEST
+	t.IngestCSV.BindHandler(a.Ingest.CSV)
+
+	// REPLAY
+	t.ReplayStream.BindHandler(a.Replay.Stream)
+
+	// STORAGE
+	t.StorageUsage.BindHandler(a.Storage.Usage)
+	t.StorageGarbageCollect.BindHandler(a.Storage.GarbageCollect)
+
+	// ALARM
+	t.AlarmCreate.BindHandler(a.Alarm.Create)
+	t.AlarmRetrieve.BindHandler(a.Alarm.Retrieve)
+	t.AlarmDelete.BindHandler(a.Alarm.Delete)
+	t.AlarmRetrieveState.BindHandler(a.Alarm.RetrieveState)
+	t.AlarmAcknowledge.BindHandler(a.Alarm.Acknowledge)
+	t.AlarmShelve.BindHandler(a.Alarm.Shelve)
+
+	// NOTIFY
+	t.NotifySubscriptionCreate.BindHandler(a.Notify.CreateSubscription)
+	t.NotifySubscriptionRetrieve.BindHandler(a.Notify.RetrieveSubscription)
+	t.NotifySubscriptionDelete.BindHandler(a.Notify.DeleteSubscription)
+	t.NotifyDeadLetterRetrieve.BindHandler(a.Notify.RetrieveDeadLetters)
+
+	// ROLE
+	t.RoleCreate.BindHandler(a.Role.Create)
+	t.RoleRetrieve.BindHandler(a.Role.Retrieve)
+	t.RoleDelete.BindHandler(a.Role.Delete)
+	t.RoleAddMembers.BindHandler(a.Role.AddMembers)
+	t.RoleRemoveMembers.BindHandler(a.Role.RemoveMembers)
+	t.RoleRetrieveMembers.BindHandler(a.Role.RetrieveMembers)
 }
 
 // New instantiates the server API using the provided Config. This should only be called
@@ -509,5 +662,12 @@ func New(configs ...Config) (API, error) {
 	api.Hardware = NewHardwareService(api.provider)
 	api.Log = NewLogService(api.provider)
 	api.Table = NewTableService(api.provider)
+	api.Backup = NewBackupService(api.provider)
+	api.Ingest = NewIngestService(api.provider)
+	api.Replay = NewReplayService(api.provider)
+	api.Storage = NewStorageService(api.provider)
+	api.Alarm = NewAlarmService(api.provider)
+	api.Notify = NewNotifyService(api.provider)
+	api.Role = NewRoleService(api.provider)
 	return api, nil
 }
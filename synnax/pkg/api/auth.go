@@ -48,11 +48,12 @@ type AuthLoginRequest struct {
 // Login attempts to authenticate a user with the provided credentials. If successful,
 // returns a response containing a valid JWT along with the user's details.
 func (s *AuthService) Login(ctx context.Context, req AuthLoginRequest) (AuthLoginResponse, error) {
-	if err := s.authenticator.Authenticate(ctx, req.InsecureCredentials); err != nil {
+	username, err := s.authenticator.Authenticate(ctx, req.InsecureCredentials)
+	if err != nil {
 		return AuthLoginResponse{}, err
 	}
 	var u user.User
-	if err := s.user.NewRetrieve().WhereUsernames(req.Username).Entry(&u).Exec(ctx, nil); err != nil {
+	if err := s.user.NewRetrieve().WhereUsernames(username).Entry(&u).Exec(ctx, nil); err != nil {
 		return AuthLoginResponse{}, err
 	}
 	tk, err := s.token.New(u.Key)
@@ -0,0 +1,100 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"bufio"
+	"context"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/distribution/cluster"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/storage"
+	"github.com/synnaxlabs/synnax/pkg/version"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// backupChunkSize is the maximum number of archive bytes sent in a single
+// BackupCreateResponse.
+const backupChunkSize = 512 * 1024
+
+// BackupService allows clients to take online backups of the storage of the node they
+// are connected to.
+type BackupService struct {
+	clusterProvider
+	accessProvider
+	storage *storage.Storage
+}
+
+func NewBackupService(p Provider) *BackupService {
+	return &BackupService{
+		clusterProvider: p.cluster,
+		accessProvider:  p.access,
+		storage:         p.Storage,
+	}
+}
+
+// BackupCreateRequest is a request to create a backup of the node's storage.
+type BackupCreateRequest struct{}
+
+// BackupCreateResponse is a chunk of a backup archive streamed back to the client.
+type BackupCreateResponse struct {
+	// Manifest describes the contents of the backup archive. It is only set on the
+	// first response in the stream.
+	Manifest storage.BackupManifest `json:"manifest" msgpack:"manifest"`
+	// Data is the next chunk of the gzipped tar archive.
+	Data []byte `json:"data" msgpack:"data"`
+}
+
+type BackupCreateStream = freighter.ServerStream[BackupCreateRequest, BackupCreateResponse]
+
+// Create receives a single request from the client and streams back a consistent,
+// point-in-time backup of the node's key-value and time-series storage. The node
+// continues to accept reads and writes while the backup is in progress, although
+// telemetry writes are briefly paused while the two engines are captured. The stream
+// is closed once the entire archive has been sent.
+func (s *BackupService) Create(ctx context.Context, stream BackupCreateStream) error {
+	if _, err := stream.Receive(); err != nil {
+		return err
+	}
+	clusterKey := s.cluster.Key()
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Create,
+		Objects: []ontology.ID{cluster.ClusterOntologyID(clusterKey)},
+	}); err != nil {
+		return err
+	}
+	m := storage.BackupManifest{
+		ClusterKey: clusterKey,
+		Version:    version.Get(),
+		CreatedAt:  telem.Now(),
+	}
+	if err := stream.Send(BackupCreateResponse{Manifest: m}); err != nil {
+		return err
+	}
+	w := bufio.NewWriterSize(backupStreamWriter{stream}, backupChunkSize)
+	if err := s.storage.Backup(ctx, w, m); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// backupStreamWriter sends each write it receives as the data of a
+// BackupCreateResponse.
+type backupStreamWriter struct{ stream BackupCreateStream }
+
+func (w backupStreamWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(BackupCreateResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
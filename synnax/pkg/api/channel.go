@@ -14,6 +14,7 @@ import (
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
 	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/alter"
 	"go/types"
 
 	"github.com/google/uuid"
@@ -41,6 +42,10 @@ type Channel struct {
 	Alias       string               `json:"alias" msgpack:"alias"`
 	Virtual     bool                 `json:"virtual" msgpack:"virtual"`
 	Internal    bool                 `json:"internal" msgpack:"internal"`
+	Unit        string               `json:"unit" msgpack:"unit"`
+	Description string               `json:"description" msgpack:"description"`
+	Scale       channel.Scale        `json:"scale" msgpack:"scale"`
+	DeletedAt   telem.TimeStamp      `json:"deleted_at" msgpack:"deleted_at"`
 }
 
 // ChannelService is the central API for all things Channel related.
@@ -49,6 +54,7 @@ type ChannelService struct {
 	accessProvider
 	internal channel.Service
 	ranger   *ranger.Service
+	alter    *alter.Service
 }
 
 func NewChannelService(p Provider) *ChannelService {
@@ -56,6 +62,7 @@ func NewChannelService(p Provider) *ChannelService {
 		accessProvider: p.access,
 		internal:       p.Config.Channel,
 		ranger:         p.Config.Ranger,
+		alter:          p.Config.Alter,
 		dbProvider:     p.db,
 	}
 }
@@ -125,6 +132,8 @@ type ChannelRetrieveRequest struct {
 	IsIndex *bool `json:"is_index" msgpack:"is_index"`
 	// Internal filters for channels that are internal if true, or are not internal if false.
 	Internal *bool `json:"internal" msgpack:"internal"`
+	// IncludeDeleted includes channels that have been moved to the trash.
+	IncludeDeleted bool `json:"include_deleted" msgpack:"include_deleted"`
 }
 
 // ChannelRetrieveResponse is the response for a ChannelRetrieveRequest.
@@ -203,6 +212,9 @@ func (s *ChannelService) Retrieve(
 	if req.Internal != nil {
 		q = q.WhereInternal(*req.Internal)
 	}
+	if req.IncludeDeleted {
+		q = q.IncludeDeleted(true)
+	}
 	if err := q.Exec(ctx, nil); err != nil {
 		return ChannelRetrieveResponse{}, err
 	}
@@ -245,6 +257,10 @@ func translateChannelsForward(channels []channel.Channel) []Channel {
 			Density:     ch.DataType.Density(),
 			Virtual:     ch.Virtual,
 			Internal:    ch.Internal,
+			Unit:        ch.Unit,
+			Description: ch.Description,
+			Scale:       ch.Scale,
+			DeletedAt:   ch.DeletedAt,
 		}
 	}
 	return translated
@@ -262,6 +278,9 @@ func translateChannelsBackward(channels []Channel) ([]channel.Channel, error) {
 			LocalIndex:  ch.Index.LocalKey(),
 			Virtual:     ch.Virtual,
 			Internal:    ch.Internal,
+			Unit:        ch.Unit,
+			Description: ch.Description,
+			Scale:       ch.Scale,
 		}
 		if ch.IsIndex {
 			tCH.LocalIndex = tCH.LocalKey
@@ -316,6 +335,27 @@ func (s *ChannelService) Delete(
 	})
 }
 
+type ChannelRestoreRequest struct {
+	Keys channel.Keys `json:"keys" msgpack:"keys" validate:"required"`
+}
+
+// Restore moves the channels with the given keys out of the trash.
+func (s *ChannelService) Restore(
+	ctx context.Context,
+	req ChannelRestoreRequest,
+) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: req.Keys.OntologyIDs(),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).RestoreMany(ctx, req.Keys)
+	})
+}
+
 type ChannelRenameRequest struct {
 	Keys  channel.Keys `json:"keys" msgpack:"keys" validate:"required"`
 	Names []string     `json:"names" msgpack:"names" validate:"required"`
@@ -337,6 +377,87 @@ func (s *ChannelService) Rename(
 	})
 }
 
+type ChannelUpdateMetadataRequest struct {
+	Keys     channel.Keys       `json:"keys" msgpack:"keys" validate:"required"`
+	Metadata []channel.Metadata `json:"metadata" msgpack:"metadata" validate:"required"`
+}
+
+func (s *ChannelService) UpdateMetadata(
+	ctx context.Context,
+	req ChannelUpdateMetadataRequest,
+) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: req.Keys.OntologyIDs(),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).UpdateManyMetadata(ctx, req.Keys, req.Metadata, false)
+	})
+}
+
+// ChannelAlterRequest is a request to change the data type or index of a channel. The
+// request must be sent to the channel's leaseholder.
+type ChannelAlterRequest struct {
+	alter.Request
+}
+
+// ChannelAlterResponse is returned by ChannelService.Alter.
+type ChannelAlterResponse struct {
+	// Job is the background job altering the channel. Its progress can be tracked
+	// using ChannelService.RetrieveAlter.
+	Job alter.Job `json:"job" msgpack:"job"`
+}
+
+// Alter starts a background job that migrates the data of a channel to a new data type
+// or index.
+func (s *ChannelService) Alter(
+	ctx context.Context,
+	req ChannelAlterRequest,
+) (res ChannelAlterResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: []ontology.ID{channel.OntologyID(req.Channel)},
+	}); err != nil {
+		return res, err
+	}
+	res.Job, err = s.alter.Start(req.Request)
+	return res, err
+}
+
+// ChannelRetrieveAlterRequest is a request to retrieve the alter jobs run by a node.
+type ChannelRetrieveAlterRequest struct {
+	// Keys are the keys of the jobs to retrieve. If empty, all jobs are retrieved.
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+}
+
+// ChannelRetrieveAlterResponse is returned by ChannelService.RetrieveAlter.
+type ChannelRetrieveAlterResponse struct {
+	Jobs []alter.Job `json:"jobs" msgpack:"jobs"`
+}
+
+// RetrieveAlter retrieves the status and progress of alter jobs.
+func (s *ChannelService) RetrieveAlter(
+	ctx context.Context,
+	req ChannelRetrieveAlterRequest,
+) (res ChannelRetrieveAlterResponse, err error) {
+	res.Jobs, err = s.alter.Retrieve(req.Keys...)
+	if err != nil {
+		return res, err
+	}
+	err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: lo.Map(res.Jobs, func(j alter.Job, _ int) ontology.ID {
+			return channel.OntologyID(j.Channel)
+		}),
+	})
+	return res, err
+}
+
 type ChannelRetrieveGroupRequest struct {
 }
 
@@ -140,6 +140,8 @@ func (s *FrameService) openIterator(ctx context.Context, srv FrameIteratorStream
 		Bounds:    req.Bounds,
 		Keys:      req.Keys,
 		ChunkSize: req.ChunkSize,
+		Resample:  req.Resample,
+		Scaled:    req.Scaled,
 	})
 	if err != nil {
 		return nil, err
@@ -199,7 +201,13 @@ func (s *FrameService) openStreamer(
 	}); err != nil {
 		return nil, err
 	}
-	reader, err := s.Internal.NewStreamer(ctx, framer.StreamerConfig{Keys: req.Keys, DownsampleFactor: req.DownsampleFactor})
+	reader, err := s.Internal.NewStreamer(ctx, framer.StreamerConfig{
+		Keys:             req.Keys,
+		DownsampleFactor: req.DownsampleFactor,
+		Start:            req.Start,
+		Deadbands:        req.Deadbands,
+		Scaled:           req.Scaled,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -197,8 +197,9 @@ func newChannel(a *api.Transport) []fgrpc.BindableTransport {
 		ServiceDesc:        &gapi.ChannelRetrieveService_ServiceDesc,
 	}
 	d := &deleteServer{
-		RequestTranslator: channelDeleteRequestTranslator{},
-		ServiceDesc:       &gapi.ChannelDeleteService_ServiceDesc,
+		RequestTranslator:  channelDeleteRequestTranslator{},
+		ResponseTranslator: fgrpc.EmptyTranslator{},
+		ServiceDesc:        &gapi.ChannelDeleteService_ServiceDesc,
 	}
 	a.ChannelCreate = c
 	a.ChannelRetrieve = r
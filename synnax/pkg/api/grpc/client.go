@@ -0,0 +1,284 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package grpc
+
+import (
+	"context"
+	"go/types"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/freighter/fgrpc"
+	"github.com/synnaxlabs/synnax/pkg/api"
+	gapi "github.com/synnaxlabs/synnax/pkg/api/grpc/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Client holds the client side of every API service that is served over gRPC. Services
+// that are not served over gRPC have no client.
+type Client struct {
+	// AUTH
+	AuthLogin freighter.UnaryClient[api.AuthLoginRequest, api.AuthLoginResponse]
+	// CHANNEL
+	ChannelCreate   freighter.UnaryClient[api.ChannelCreateRequest, api.ChannelCreateResponse]
+	ChannelRetrieve freighter.UnaryClient[api.ChannelRetrieveRequest, api.ChannelRetrieveResponse]
+	ChannelDelete   freighter.UnaryClient[api.ChannelDeleteRequest, types.Nil]
+	// RANGE
+	RangeCreate       freighter.UnaryClient[api.RangeCreateRequest, api.RangeCreateResponse]
+	RangeRetrieve     freighter.UnaryClient[api.RangeRetrieveRequest, api.RangeRetrieveResponse]
+	RangeDelete       freighter.UnaryClient[api.RangeDeleteRequest, types.Nil]
+	RangeKVGet        freighter.UnaryClient[api.RangeKVGetRequest, api.RangeKVGetResponse]
+	RangeKVSet        freighter.UnaryClient[api.RangeKVSetRequest, types.Nil]
+	RangeKVDelete     freighter.UnaryClient[api.RangeKVDeleteRequest, types.Nil]
+	RangeAliasSet     freighter.UnaryClient[api.RangeAliasSetRequest, types.Nil]
+	RangeAliasResolve freighter.UnaryClient[api.RangeAliasResolveRequest, api.RangeAliasResolveResponse]
+	RangeAliasList    freighter.UnaryClient[api.RangeAliasListRequest, api.RangeAliasListResponse]
+	RangeAliasDelete  freighter.UnaryClient[api.RangeAliasDeleteRequest, types.Nil]
+	// FRAME
+	FrameWriter   freighter.StreamClient[api.FrameWriterRequest, api.FrameWriterResponse]
+	FrameIterator freighter.StreamClient[api.FrameIteratorRequest, api.FrameIteratorResponse]
+	FrameStreamer freighter.StreamClient[api.FrameStreamerRequest, api.FrameStreamerResponse]
+	FrameDelete   freighter.UnaryClient[api.FrameDeleteRequest, types.Nil]
+}
+
+// Use adds the given middleware to every client.
+func (c Client) Use(middleware ...freighter.Middleware) {
+	for _, t := range []freighter.Transport{
+		c.AuthLogin,
+		c.ChannelCreate,
+		c.ChannelRetrieve,
+		c.ChannelDelete,
+		c.RangeCreate,
+		c.RangeRetrieve,
+		c.RangeDelete,
+		c.RangeKVGet,
+		c.RangeKVSet,
+		c.RangeKVDelete,
+		c.RangeAliasSet,
+		c.RangeAliasResolve,
+		c.RangeAliasList,
+		c.RangeAliasDelete,
+		c.FrameWriter,
+		c.FrameIterator,
+		c.FrameStreamer,
+		c.FrameDelete,
+	} {
+		t.Use(middleware...)
+	}
+}
+
+// NewClient opens the client side of the gRPC API, dialing connections from the
+// given pool.
+func NewClient(pool *fgrpc.Pool) Client {
+	return Client{
+		AuthLogin: newUnaryClient(
+			pool,
+			&gapi.AuthLoginService_ServiceDesc,
+			loginRequestTranslator{},
+			loginResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.LoginRequest) (*gapi.LoginResponse, error) {
+				return gapi.NewAuthLoginServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		ChannelCreate: newUnaryClient(
+			pool,
+			&gapi.ChannelCreateService_ServiceDesc,
+			channelCreateRequestTranslator{},
+			channelCreateResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.ChannelCreateRequest) (*gapi.ChannelCreateResponse, error) {
+				return gapi.NewChannelCreateServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		ChannelRetrieve: newUnaryClient(
+			pool,
+			&gapi.ChannelRetrieveService_ServiceDesc,
+			channelRetrieveRequestTranslator{},
+			channelRetrieveResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.ChannelRetrieveRequest) (*gapi.ChannelRetrieveResponse, error) {
+				return gapi.NewChannelRetrieveServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		ChannelDelete: newUnaryClient(
+			pool,
+			&gapi.ChannelDeleteService_ServiceDesc,
+			channelDeleteRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.ChannelDeleteRequest) (*emptypb.Empty, error) {
+				return gapi.NewChannelDeleteServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeCreate: newUnaryClient(
+			pool,
+			&gapi.RangeCreateService_ServiceDesc,
+			rangeCreateRequestTranslator{},
+			rangeCreateResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeCreateRequest) (*gapi.RangeCreateResponse, error) {
+				return gapi.NewRangeCreateServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeRetrieve: newUnaryClient(
+			pool,
+			&gapi.RangeRetrieveService_ServiceDesc,
+			rangeRetrieveRequestTranslator{},
+			rangeRetrieveResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeRetrieveRequest) (*gapi.RangeRetrieveResponse, error) {
+				return gapi.NewRangeRetrieveServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeDelete: newUnaryClient(
+			pool,
+			&gapi.RangeDeleteService_ServiceDesc,
+			rangeDeleteRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeDeleteRequest) (*emptypb.Empty, error) {
+				return gapi.NewRangeDeleteServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeKVGet: newUnaryClient(
+			pool,
+			&gapi.RangeKVGetService_ServiceDesc,
+			rangeKVGetRequestTranslator{},
+			rangeKVGetResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeKVGetRequest) (*gapi.RangeKVGetResponse, error) {
+				return gapi.NewRangeKVGetServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeKVSet: newUnaryClient(
+			pool,
+			&gapi.RangeKVSetService_ServiceDesc,
+			rangeKVSetRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeKVSetRequest) (*emptypb.Empty, error) {
+				return gapi.NewRangeKVSetServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeKVDelete: newUnaryClient(
+			pool,
+			&gapi.RangeKVDeleteService_ServiceDesc,
+			rangeKVDeleteRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeKVDeleteRequest) (*emptypb.Empty, error) {
+				return gapi.NewRangeKVDeleteServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeAliasSet: newUnaryClient(
+			pool,
+			&gapi.RangeAliasSetService_ServiceDesc,
+			rangeAliasSetRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeAliasSetRequest) (*emptypb.Empty, error) {
+				return gapi.NewRangeAliasSetServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeAliasResolve: newUnaryClient(
+			pool,
+			&gapi.RangeAliasResolveService_ServiceDesc,
+			rangeAliasResolveRequestTranslator{},
+			rangeAliasResolveResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeAliasResolveRequest) (*gapi.RangeAliasResolveResponse, error) {
+				return gapi.NewRangeAliasResolveServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeAliasList: newUnaryClient(
+			pool,
+			&gapi.RangeAliasListService_ServiceDesc,
+			rangeAliasListRequestTranslator{},
+			rangeAliasListResponseTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeAliasListRequest) (*gapi.RangeAliasListResponse, error) {
+				return gapi.NewRangeAliasListServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		RangeAliasDelete: newUnaryClient(
+			pool,
+			&gapi.RangeAliasDeleteService_ServiceDesc,
+			rangeAliasDeleteRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.RangeAliasDeleteRequest) (*emptypb.Empty, error) {
+				return gapi.NewRangeAliasDeleteServiceClient(conn).Exec(ctx, req)
+			},
+		),
+		FrameWriter: &fgrpc.StreamClientCore[
+			api.FrameWriterRequest,
+			*gapi.FrameWriterRequest,
+			api.FrameWriterResponse,
+			*gapi.FrameWriterResponse,
+		]{
+			Pool:               pool,
+			ServiceDesc:        &gapi.FrameWriterService_ServiceDesc,
+			RequestTranslator:  frameWriterRequestTranslator{},
+			ResponseTranslator: frameWriterResponseTranslator{},
+			ClientFunc: func(
+				ctx context.Context,
+				conn grpc.ClientConnInterface,
+			) (fgrpc.GRPCClientStream[*gapi.FrameWriterRequest, *gapi.FrameWriterResponse], error) {
+				return gapi.NewFrameWriterServiceClient(conn).Exec(ctx)
+			},
+		},
+		FrameIterator: &fgrpc.StreamClientCore[
+			api.FrameIteratorRequest,
+			*gapi.FrameIteratorRequest,
+			api.FrameIteratorResponse,
+			*gapi.FrameIteratorResponse,
+		]{
+			Pool:               pool,
+			ServiceDesc:        &gapi.FrameIteratorService_ServiceDesc,
+			RequestTranslator:  frameIteratorRequestTranslator{},
+			ResponseTranslator: frameIteratorResponseTranslator{},
+			ClientFunc: func(
+				ctx context.Context,
+				conn grpc.ClientConnInterface,
+			) (fgrpc.GRPCClientStream[*gapi.FrameIteratorRequest, *gapi.FrameIteratorResponse], error) {
+				return gapi.NewFrameIteratorServiceClient(conn).Exec(ctx)
+			},
+		},
+		FrameStreamer: &fgrpc.StreamClientCore[
+			api.FrameStreamerRequest,
+			*gapi.FrameStreamerRequest,
+			api.FrameStreamerResponse,
+			*gapi.FrameStreamerResponse,
+		]{
+			Pool:               pool,
+			ServiceDesc:        &gapi.FrameStreamerService_ServiceDesc,
+			RequestTranslator:  frameStreamerRequestTranslator{},
+			ResponseTranslator: frameStreamerResponseTranslator{},
+			ClientFunc: func(
+				ctx context.Context,
+				conn grpc.ClientConnInterface,
+			) (fgrpc.GRPCClientStream[*gapi.FrameStreamerRequest, *gapi.FrameStreamerResponse], error) {
+				return gapi.NewFrameStreamerServiceClient(conn).Exec(ctx)
+			},
+		},
+		FrameDelete: newUnaryClient(
+			pool,
+			&gapi.FrameDeleteService_ServiceDesc,
+			FrameDeleteRequestTranslator{},
+			fgrpc.EmptyTranslator{},
+			func(ctx context.Context, conn grpc.ClientConnInterface, req *gapi.FrameDeleteRequest) (*emptypb.Empty, error) {
+				return gapi.NewFrameDeleteServiceClient(conn).Exec(ctx, req)
+			},
+		),
+	}
+}
+
+func newUnaryClient[RQ, RQT, RS, RST freighter.Payload](
+	pool *fgrpc.Pool,
+	desc *grpc.ServiceDesc,
+	reqT fgrpc.Translator[RQ, RQT],
+	resT fgrpc.Translator[RS, RST],
+	exec func(context.Context, grpc.ClientConnInterface, RQT) (RST, error),
+) freighter.UnaryClient[RQ, RS] {
+	return &fgrpc.UnaryClient[RQ, RQT, RS, RST]{
+		Pool:               pool,
+		ServiceDesc:        desc,
+		RequestTranslator:  reqT,
+		ResponseTranslator: resT,
+		Exec:               exec,
+	}
+}
@@ -353,13 +353,14 @@ func newFramer(a *api.Transport) fgrpc.BindableTransport {
 			},
 		}
 		ds = &frameDeleteServer{
-			RequestTranslator: FrameDeleteRequestTranslator{},
-			ServiceDesc:       &gapi.FrameDeleteService_ServiceDesc,
+			RequestTranslator:  FrameDeleteRequestTranslator{},
+			ResponseTranslator: fgrpc.EmptyTranslator{},
+			ServiceDesc:        &gapi.FrameDeleteService_ServiceDesc,
 		}
 	)
 	a.FrameStreamer = ss
 	a.FrameWriter = ws
 	a.FrameIterator = is
 	a.FrameDelete = ds
-	return fgrpc.CompoundBindableTransport{ws, is, ss}
+	return fgrpc.CompoundBindableTransport{ws, is, ss, ds}
 }
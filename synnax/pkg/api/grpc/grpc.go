@@ -34,17 +34,23 @@ func New() (a api.Transport, transports []fgrpc.BindableTransport) {
 
 	// CHANNEL
 	a.ChannelRename = fnoop.UnaryServer[api.ChannelRenameRequest, types.Nil]{}
+	a.ChannelUpdateMetadata = fnoop.UnaryServer[api.ChannelUpdateMetadataRequest, types.Nil]{}
+	a.ChannelAlter = fnoop.UnaryServer[api.ChannelAlterRequest, api.ChannelAlterResponse]{}
+	a.ChannelRetrieveAlter = fnoop.UnaryServer[api.ChannelRetrieveAlterRequest, api.ChannelRetrieveAlterResponse]{}
 	a.ChannelRetrieveGroup = fnoop.UnaryServer[api.ChannelRetrieveGroupRequest, api.ChannelRetrieveGroupResponse]{}
+	a.ChannelRestore = fnoop.UnaryServer[api.ChannelRestoreRequest, types.Nil]{}
 
 	// USER
 	a.UserRename = fnoop.UnaryServer[api.UserRenameRequest, types.Nil]{}
 	a.UserChangeUsername = fnoop.UnaryServer[api.UserChangeUsernameRequest, types.Nil]{}
+	a.UserLinkIdentity = fnoop.UnaryServer[api.UserLinkIdentityRequest, types.Nil]{}
 	a.UserCreate = fnoop.UnaryServer[api.UserCreateRequest, api.UserCreateResponse]{}
 	a.UserDelete = fnoop.UnaryServer[api.UserDeleteRequest, types.Nil]{}
 	a.UserRetrieve = fnoop.UnaryServer[api.UserRetrieveRequest, api.UserRetrieveResponse]{}
 
 	// RANGE
 	a.RangeRename = fnoop.UnaryServer[api.RangeRenameRequest, types.Nil]{}
+	a.RangeRestore = fnoop.UnaryServer[api.RangeRestoreRequest, types.Nil]{}
 
 	// ONTOLOGY
 	a.OntologyRetrieve = fnoop.UnaryServer[api.OntologyRetrieveRequest, api.OntologyRetrieveResponse]{}
@@ -61,12 +67,14 @@ func New() (a api.Transport, transports []fgrpc.BindableTransport) {
 	a.WorkspaceCreate = fnoop.UnaryServer[api.WorkspaceCreateRequest, api.WorkspaceCreateResponse]{}
 	a.WorkspaceRetrieve = fnoop.UnaryServer[api.WorkspaceRetrieveRequest, api.WorkspaceRetrieveResponse]{}
 	a.WorkspaceDelete = fnoop.UnaryServer[api.WorkspaceDeleteRequest, types.Nil]{}
+	a.WorkspaceRestore = fnoop.UnaryServer[api.WorkspaceRestoreRequest, types.Nil]{}
 	a.WorkspaceRename = fnoop.UnaryServer[api.WorkspaceRenameRequest, types.Nil]{}
 	a.WorkspaceSetLayout = fnoop.UnaryServer[api.WorkspaceSetLayoutRequest, types.Nil]{}
 
 	// SCHEMATIC
 	a.SchematicCreate = fnoop.UnaryServer[api.SchematicCreateRequest, api.SchematicCreateResponse]{}
 	a.SchematicDelete = fnoop.UnaryServer[api.SchematicDeleteRequest, types.Nil]{}
+	a.SchematicRestore = fnoop.UnaryServer[api.SchematicRestoreRequest, types.Nil]{}
 	a.SchematicRetrieve = fnoop.UnaryServer[api.SchematicRetrieveRequest, api.SchematicRetrieveResponse]{}
 	a.SchematicRename = fnoop.UnaryServer[api.SchematicRenameRequest, types.Nil]{}
 	a.SchematicSetData = fnoop.UnaryServer[api.SchematicSetDataRequest, types.Nil]{}
@@ -105,5 +113,40 @@ func New() (a api.Transport, transports []fgrpc.BindableTransport) {
 	a.AccessDeletePolicy = fnoop.UnaryServer[api.AccessDeletePolicyRequest, types.Nil]{}
 	a.AccessRetrievePolicy = fnoop.UnaryServer[api.AccessRetrievePolicyRequest, api.AccessRetrievePolicyResponse]{}
 
+	// BACKUP
+	a.BackupCreate = fnoop.StreamServer[api.BackupCreateRequest, api.BackupCreateResponse]{}
+
+	// INGEST
+	a.IngestCSV = fnoop.StreamServer[api.IngestCSVRequest, api.IngestCSVResponse]{}
+
+	// REPLAY
+	a.ReplayStream = fnoop.StreamServer[api.ReplayRequest, api.ReplayResponse]{}
+
+	// STORAGE
+	a.StorageUsage = fnoop.UnaryServer[api.StorageUsageRequest, api.StorageUsageResponse]{}
+	a.StorageGarbageCollect = fnoop.StreamServer[api.StorageGCRequest, api.StorageGCResponse]{}
+
+	// ALARM
+	a.AlarmCreate = fnoop.UnaryServer[api.AlarmCreateRequest, api.AlarmCreateResponse]{}
+	a.AlarmRetrieve = fnoop.UnaryServer[api.AlarmRetrieveRequest, api.AlarmRetrieveResponse]{}
+	a.AlarmDelete = fnoop.UnaryServer[api.AlarmDeleteRequest, types.Nil]{}
+	a.AlarmRetrieveState = fnoop.UnaryServer[api.AlarmRetrieveStateRequest, api.AlarmRetrieveStateResponse]{}
+	a.AlarmAcknowledge = fnoop.UnaryServer[api.AlarmAcknowledgeRequest, types.Nil]{}
+	a.AlarmShelve = fnoop.UnaryServer[api.AlarmShelveRequest, types.Nil]{}
+
+	// NOTIFY
+	a.NotifySubscriptionCreate = fnoop.UnaryServer[api.NotifySubscriptionCreateRequest, api.NotifySubscriptionCreateResponse]{}
+	a.NotifySubscriptionRetrieve = fnoop.UnaryServer[api.NotifySubscriptionRetrieveRequest, api.NotifySubscriptionRetrieveResponse]{}
+	a.NotifySubscriptionDelete = fnoop.UnaryServer[api.NotifySubscriptionDeleteRequest, types.Nil]{}
+	a.NotifyDeadLetterRetrieve = fnoop.UnaryServer[api.NotifyDeadLetterRetrieveRequest, api.NotifyDeadLetterRetrieveResponse]{}
+
+	// ROLE
+	a.RoleCreate = fnoop.UnaryServer[api.RoleCreateRequest, api.RoleCreateResponse]{}
+	a.RoleRetrieve = fnoop.UnaryServer[api.RoleRetrieveRequest, api.RoleRetrieveResponse]{}
+	a.RoleDelete = fnoop.UnaryServer[api.RoleDeleteRequest, types.Nil]{}
+	a.RoleAddMembers = fnoop.UnaryServer[api.RoleAddMembersRequest, types.Nil]{}
+	a.RoleRemoveMembers = fnoop.UnaryServer[api.RoleRemoveMembersRequest, types.Nil]{}
+	a.RoleRetrieveMembers = fnoop.UnaryServer[api.RoleRetrieveMembersRequest, api.RoleRetrieveMembersResponse]{}
+
 	return a, transports
 }
@@ -415,9 +415,11 @@ func (t rangeDeleteRequestTranslator) Forward(
 	_ context.Context,
 	r api.RangeDeleteRequest,
 ) (*gapi.RangeDeleteRequest, error) {
-	return &gapi.RangeDeleteRequest{
-		Keys: unsafe.ReinterpretSlice[uuid.UUID, string](r.Keys),
-	}, nil
+	keys := make([]string, len(r.Keys))
+	for i := range r.Keys {
+		keys[i] = r.Keys[i].String()
+	}
+	return &gapi.RangeDeleteRequest{Keys: keys}, nil
 }
 
 func (t rangeDeleteRequestTranslator) Backward(
@@ -24,6 +24,7 @@ func New(router *fhttp.Router) (t api.Transport) {
 	// USER
 	t.UserRename = fhttp.UnaryServer[api.UserRenameRequest, types.Nil](router, false, "/api/v1/user/rename")
 	t.UserChangeUsername = fhttp.UnaryServer[api.UserChangeUsernameRequest, types.Nil](router, false, "/api/v1/user/change-username")
+	t.UserLinkIdentity = fhttp.UnaryServer[api.UserLinkIdentityRequest, types.Nil](router, false, "/api/v1/user/link-identity")
 	t.UserCreate = fhttp.UnaryServer[api.UserCreateRequest, api.UserCreateResponse](router, false, "/api/v1/user/create")
 	t.UserDelete = fhttp.UnaryServer[api.UserDeleteRequest, types.Nil](router, false, "/api/v1/user/delete")
 	t.UserRetrieve = fhttp.UnaryServer[api.UserRetrieveRequest, api.UserRetrieveResponse](router, false, "/api/v1/user/retrieve")
@@ -32,7 +33,11 @@ func New(router *fhttp.Router) (t api.Transport) {
 	t.ChannelCreate = fhttp.UnaryServer[api.ChannelCreateRequest, api.ChannelCreateResponse](router, false, "/api/v1/channel/create")
 	t.ChannelRetrieve = fhttp.UnaryServer[api.ChannelRetrieveRequest, api.ChannelRetrieveResponse](router, false, "/api/v1/channel/retrieve")
 	t.ChannelDelete = fhttp.UnaryServer[api.ChannelDeleteRequest, types.Nil](router, false, "/api/v1/channel/delete")
+	t.ChannelRestore = fhttp.UnaryServer[api.ChannelRestoreRequest, types.Nil](router, false, "/api/v1/channel/restore")
 	t.ChannelRename = fhttp.UnaryServer[api.ChannelRenameRequest, types.Nil](router, false, "/api/v1/channel/rename")
+	t.ChannelUpdateMetadata = fhttp.UnaryServer[api.ChannelUpdateMetadataRequest, types.Nil](router, false, "/api/v1/channel/update-metadata")
+	t.ChannelAlter = fhttp.UnaryServer[api.ChannelAlterRequest, api.ChannelAlterResponse](router, false, "/api/v1/channel/alter")
+	t.ChannelRetrieveAlter = fhttp.UnaryServer[api.ChannelRetrieveAlterRequest, api.ChannelRetrieveAlterResponse](router, false, "/api/v1/channel/alter/retrieve")
 	t.ChannelRetrieveGroup = fhttp.UnaryServer[api.ChannelRetrieveGroupRequest, api.ChannelRetrieveGroupResponse](router, false, "/api/v1/channel/retrieve-group")
 
 	// CONNECTIVITY
@@ -59,6 +64,7 @@ func New(router *fhttp.Router) (t api.Transport) {
 	t.RangeRetrieve = fhttp.UnaryServer[api.RangeRetrieveRequest, api.RangeRetrieveResponse](router, false, "/api/v1/range/retrieve")
 	t.RangeCreate = fhttp.UnaryServer[api.RangeCreateRequest, api.RangeCreateResponse](router, false, "/api/v1/range/create")
 	t.RangeDelete = fhttp.UnaryServer[api.RangeDeleteRequest, types.Nil](router, false, "/api/v1/range/delete")
+	t.RangeRestore = fhttp.UnaryServer[api.RangeRestoreRequest, types.Nil](router, false, "/api/v1/range/restore")
 	t.RangeRename = fhttp.UnaryServer[api.RangeRenameRequest, types.Nil](router, false, "/api/v1/range/rename")
 	t.RangeKVGet = fhttp.UnaryServer[api.RangeKVGetRequest, api.RangeKVGetResponse](router, false, "/api/v1/range/kv/get")
 	t.RangeKVSet = fhttp.UnaryServer[api.RangeKVSetRequest, types.Nil](router, false, "/api/v1/range/kv/set")
@@ -72,6 +78,7 @@ func New(router *fhttp.Router) (t api.Transport) {
 	t.WorkspaceCreate = fhttp.UnaryServer[api.WorkspaceCreateRequest, api.WorkspaceCreateResponse](router, false, "/api/v1/workspace/create")
 	t.WorkspaceRetrieve = fhttp.UnaryServer[api.WorkspaceRetrieveRequest, api.WorkspaceRetrieveResponse](router, false, "/api/v1/workspace/retrieve")
 	t.WorkspaceDelete = fhttp.UnaryServer[api.WorkspaceDeleteRequest, types.Nil](router, false, "/api/v1/workspace/delete")
+	t.WorkspaceRestore = fhttp.UnaryServer[api.WorkspaceRestoreRequest, types.Nil](router, false, "/api/v1/workspace/restore")
 	t.WorkspaceRename = fhttp.UnaryServer[api.WorkspaceRenameRequest, types.Nil](router, false, "/api/v1/workspace/rename")
 	t.WorkspaceSetLayout = fhttp.UnaryServer[api.WorkspaceSetLayoutRequest, types.Nil](router, false, "/api/v1/workspace/set-layout")
 
@@ -79,6 +86,7 @@ func New(router *fhttp.Router) (t api.Transport) {
 	t.SchematicCreate = fhttp.UnaryServer[api.SchematicCreateRequest, api.SchematicCreateResponse](router, false, "/api/v1/workspace/schematic/create")
 	t.SchematicRetrieve = fhttp.UnaryServer[api.SchematicRetrieveRequest, api.SchematicRetrieveResponse](router, false, "/api/v1/workspace/schematic/retrieve")
 	t.SchematicDelete = fhttp.UnaryServer[api.SchematicDeleteRequest, types.Nil](router, false, "/api/v1/workspace/schematic/delete")
+	t.SchematicRestore = fhttp.UnaryServer[api.SchematicRestoreRequest, types.Nil](router, false, "/api/v1/workspace/schematic/restore")
 	t.SchematicRename = fhttp.UnaryServer[api.SchematicRenameRequest, types.Nil](router, false, "/api/v1/workspace/schematic/rename")
 	t.SchematicSetData = fhttp.UnaryServer[api.SchematicSetDataRequest, types.Nil](router, false, "/api/v1/workspace/schematic/set-data")
 	t.SchematicCopy = fhttp.UnaryServer[api.SchematicCopyRequest, api.SchematicCopyResponse](router, false, "/api/v1/workspace/schematic/copy")
@@ -128,5 +136,40 @@ func New(router *fhttp.Router) (t api.Transport) {
 	t.AccessDeletePolicy = fhttp.UnaryServer[api.AccessDeletePolicyRequest, types.Nil](router, false, "/api/v1/access/policy/delete")
 	t.AccessRetrievePolicy = fhttp.UnaryServer[api.AccessRetrievePolicyRequest, api.AccessRetrievePolicyResponse](router, false, "/api/v1/access/policy/retrieve")
 
+	// BACKUP
+	t.BackupCreate = fhttp.StreamServer[api.BackupCreateRequest, api.BackupCreateResponse](router, false, "/api/v1/backup/create")
+
+	// INGEST
+	t.IngestCSV = fhttp.StreamServer[api.IngestCSVRequest, api.IngestCSVResponse](router, false, "/api/v1/ingest/csv")
+
+	// REPLAY
+	t.ReplayStream = fhttp.StreamServer[api.ReplayRequest, api.ReplayResponse](router, false, "/api/v1/replay/stream")
+
+	// STORAGE
+	t.StorageUsage = fhttp.UnaryServer[api.StorageUsageRequest, api.StorageUsageResponse](router, false, "/api/v1/storage/usage")
+	t.StorageGarbageCollect = fhttp.StreamServer[api.StorageGCRequest, api.StorageGCResponse](router, false, "/api/v1/storage/garbage-collect")
+
+	// ALARM
+	t.AlarmCreate = fhttp.UnaryServer[api.AlarmCreateRequest, api.AlarmCreateResponse](router, false, "/api/v1/alarm/create")
+	t.AlarmRetrieve = fhttp.UnaryServer[api.AlarmRetrieveRequest, api.AlarmRetrieveResponse](router, false, "/api/v1/alarm/retrieve")
+	t.AlarmDelete = fhttp.UnaryServer[api.AlarmDeleteRequest, types.Nil](router, false, "/api/v1/alarm/delete")
+	t.AlarmRetrieveState = fhttp.UnaryServer[api.AlarmRetrieveStateRequest, api.AlarmRetrieveStateResponse](router, false, "/api/v1/alarm/retrieve-state")
+	t.AlarmAcknowledge = fhttp.UnaryServer[api.AlarmAcknowledgeRequest, types.Nil](router, false, "/api/v1/alarm/acknowledge")
+	t.AlarmShelve = fhttp.UnaryServer[api.AlarmShelveRequest, types.Nil](router, false, "/api/v1/alarm/shelve")
+
+	// NOTIFY
+	t.NotifySubscriptionCreate = fhttp.UnaryServer[api.NotifySubscriptionCreateRequest, api.NotifySubscriptionCreateResponse](router, false, "/api/v1/notify/subscription/create")
+	t.NotifySubscriptionRetrieve = fhttp.UnaryServer[api.NotifySubscriptionRetrieveRequest, api.NotifySubscriptionRetrieveResponse](router, false, "/api/v1/notify/subscription/retrieve")
+	t.NotifySubscriptionDelete = fhttp.UnaryServer[api.NotifySubscriptionDeleteRequest, types.Nil](router, false, "/api/v1/notify/subscription/delete")
+	t.NotifyDeadLetterRetrieve = fhttp.UnaryServer[api.NotifyDeadLetterRetrieveRequest, api.NotifyDeadLetterRetrieveResponse](router, false, "/api/v1/notify/dead-letter/retrieve")
+
+	// ROLE
+	t.RoleCreate = fhttp.UnaryServer[api.RoleCreateRequest, api.RoleCreateResponse](router, false, "/api/v1/role/create")
+	t.RoleRetrieve = fhttp.UnaryServer[api.RoleRetrieveRequest, api.RoleRetrieveResponse](router, false, "/api/v1/role/retrieve")
+	t.RoleDelete = fhttp.UnaryServer[api.RoleDeleteRequest, types.Nil](router, false, "/api/v1/role/delete")
+	t.RoleAddMembers = fhttp.UnaryServer[api.RoleAddMembersRequest, types.Nil](router, false, "/api/v1/role/members/add")
+	t.RoleRemoveMembers = fhttp.UnaryServer[api.RoleRemoveMembersRequest, types.Nil](router, false, "/api/v1/role/members/remove")
+	t.RoleRetrieveMembers = fhttp.UnaryServer[api.RoleRetrieveMembersRequest, api.RoleRetrieveMembersResponse](router, false, "/api/v1/role/members/retrieve")
+
 	return t
 }
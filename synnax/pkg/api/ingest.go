@@ -0,0 +1,171 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/ingest"
+	"github.com/synnaxlabs/x/errors"
+)
+
+// IngestService allows clients to ingest tabular files into channels.
+type IngestService struct {
+	accessProvider
+	internal *ingest.Service
+}
+
+func NewIngestService(p Provider) *IngestService {
+	return &IngestService{
+		accessProvider: p.access,
+		internal:       p.Config.Ingest,
+	}
+}
+
+// IngestCSVRequest is a chunk of a CSV file uploaded by the client. The options of the
+// ingestion are read from the first request in the stream and ignored on the rest.
+type IngestCSVRequest struct {
+	// Data is the next chunk of the file.
+	Data []byte `json:"data" msgpack:"data"`
+	// Mapping maps the columns of the file to channels.
+	Mapping ingest.Mapping `json:"mapping" msgpack:"mapping"`
+	// Delimiter is the single character separating columns. Defaults to a comma.
+	Delimiter string `json:"delimiter" msgpack:"delimiter"`
+	// TimeFormat is the format of the index column. Detected automatically if empty.
+	TimeFormat ingest.TimeFormat `json:"time_format" msgpack:"time_format"`
+	// ChunkSize is the number of rows written in a single frame.
+	ChunkSize int `json:"chunk_size" msgpack:"chunk_size"`
+	// DryRun validates the file and reports what would be written without modifying
+	// the cluster.
+	DryRun bool `json:"dry_run" msgpack:"dry_run"`
+}
+
+// IngestCSVResponse is returned by IngestService.CSV.
+type IngestCSVResponse struct {
+	// Report describes the channels written to and any problems found in the file.
+	Report ingest.Report `json:"report" msgpack:"report"`
+}
+
+type IngestCSVStream = freighter.ServerStream[IngestCSVRequest, IngestCSVResponse]
+
+// CSV receives a CSV file streamed by the client in chunks, validates it against the
+// requested mapping, and, if the request is not a dry run, creates any missing channels
+// and writes the contents of the file to them. The subject must be allowed to update
+// the existing channels in the mapping and to create the missing ones before any rows
+// of the file are received. The file is spooled to a temporary file on the node so
+// that it can be read twice without being held in memory. A single response containing
+// the report is sent once the file has been processed.
+func (s *IngestService) CSV(ctx context.Context, stream IngestCSVStream) (err error) {
+	first, err := stream.Receive()
+	if err != nil {
+		return err
+	}
+	ingestReq := ingest.Request{
+		Mapping:    first.Mapping,
+		Delimiter:  first.Delimiter,
+		TimeFormat: first.TimeFormat,
+		ChunkSize:  first.ChunkSize,
+	}
+	f, err := os.CreateTemp("", "synnax-ingest-*.csv")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.CombineErrors(err, errors.CombineErrors(f.Close(), os.Remove(f.Name())))
+	}()
+	body := &ingestStreamReader{stream: stream, buf: first.Data}
+	var res IngestCSVResponse
+	// Only the header of the file is read from the stream while resolving, which
+	// allows access to be checked before the client uploads the rest of the file.
+	if res.Report, err = s.internal.Resolve(ctx, io.TeeReader(body, f), ingestReq); err != nil {
+		return err
+	}
+	if err = s.enforce(ctx, res.Report); err != nil {
+		return err
+	}
+	if !res.Report.Valid() {
+		return stream.Send(res)
+	}
+	if _, err = io.Copy(f, body); err != nil {
+		return err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if first.DryRun {
+		res.Report, err = s.internal.Validate(ctx, f, ingestReq)
+	} else {
+		res.Report, err = s.internal.Ingest(ctx, f, ingestReq)
+	}
+	if err != nil {
+		return err
+	}
+	return stream.Send(res)
+}
+
+// ingestStreamReader reads the data of the requests received on an IngestCSVStream,
+// returning io.EOF once the client closes its side of the stream.
+type ingestStreamReader struct {
+	stream IngestCSVStream
+	buf    []byte
+}
+
+func (r *ingestStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Receive()
+		if errors.Is(err, freighter.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		r.buf = req.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// enforce checks that the subject is allowed to write to the existing channels in the
+// report and to create the channels that do not exist.
+func (s *IngestService) enforce(ctx context.Context, r ingest.Report) error {
+	existing, missing := lo.FilterReject(
+		append([]ingest.ChannelReport{r.Index}, r.Channels...),
+		func(c ingest.ChannelReport, _ int) bool { return c.Exists },
+	)
+	toChannels := func(reports []ingest.ChannelReport) []channel.Channel {
+		return lo.Map(reports, func(c ingest.ChannelReport, _ int) channel.Channel {
+			return c.Channel
+		})
+	}
+	if len(existing) > 0 {
+		if err := s.access.Enforce(ctx, access.Request{
+			Subject: getSubject(ctx),
+			Action:  access.Update,
+			Objects: channel.OntologyIDsFromChannels(toChannels(existing)),
+		}); err != nil {
+			return err
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Create,
+		Objects: channel.OntologyIDsFromChannels(toChannels(missing)),
+	})
+}
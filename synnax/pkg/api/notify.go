@@ -0,0 +1,194 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"context"
+	"go/types"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/notify"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+// NotifyService is the API for managing webhook subscriptions and inspecting events
+// that could not be delivered to them.
+type NotifyService struct {
+	dbProvider
+	accessProvider
+	internal *notify.Service
+}
+
+func NewNotifyService(p Provider) *NotifyService {
+	return &NotifyService{
+		dbProvider:     p.db,
+		accessProvider: p.access,
+		internal:       p.Config.Notify,
+	}
+}
+
+type Subscription = notify.Subscription
+
+// NotifySubscriptionCreateRequest is a request to create webhook subscriptions.
+type NotifySubscriptionCreateRequest struct {
+	// Subscriptions are the subscriptions to create. Subscriptions with an existing key
+	// are overwritten.
+	Subscriptions []Subscription `json:"subscriptions" msgpack:"subscriptions"`
+}
+
+// NotifySubscriptionCreateResponse is a response to a NotifySubscriptionCreateRequest.
+type NotifySubscriptionCreateResponse struct {
+	// Subscriptions are the created subscriptions, with their keys assigned.
+	Subscriptions []Subscription `json:"subscriptions" msgpack:"subscriptions"`
+}
+
+// CreateSubscription creates webhook subscriptions.
+func (s *NotifyService) CreateSubscription(
+	ctx context.Context,
+	req NotifySubscriptionCreateRequest,
+) (res NotifySubscriptionCreateResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Create,
+		Objects: notify.OntologyIDsFromSubscriptions(req.Subscriptions),
+	}); err != nil {
+		return res, err
+	}
+	return res, s.WithTx(ctx, func(tx gorp.Tx) error {
+		if err := s.internal.NewWriter(tx).CreateMany(ctx, &req.Subscriptions); err != nil {
+			return err
+		}
+		res.Subscriptions = redactSecrets(req.Subscriptions)
+		return nil
+	})
+}
+
+// NotifySubscriptionRetrieveRequest is a request to retrieve webhook subscriptions.
+type NotifySubscriptionRetrieveRequest struct {
+	Keys   []uuid.UUID        `json:"keys" msgpack:"keys"`
+	Names  []string           `json:"names" msgpack:"names"`
+	Events []notify.EventType `json:"events" msgpack:"events"`
+	Search string             `json:"search" msgpack:"search"`
+	Limit  int                `json:"limit" msgpack:"limit"`
+	Offset int                `json:"offset" msgpack:"offset"`
+}
+
+// NotifySubscriptionRetrieveResponse is a response to a
+// NotifySubscriptionRetrieveRequest.
+type NotifySubscriptionRetrieveResponse struct {
+	Subscriptions []Subscription `json:"subscriptions" msgpack:"subscriptions"`
+}
+
+// RetrieveSubscription retrieves webhook subscriptions. Subscription secrets are never
+// returned.
+func (s *NotifyService) RetrieveSubscription(
+	ctx context.Context,
+	req NotifySubscriptionRetrieveRequest,
+) (res NotifySubscriptionRetrieveResponse, err error) {
+	q := s.internal.NewRetrieve()
+	if req.Search != "" {
+		q = q.Search(req.Search)
+	}
+	if len(req.Keys) > 0 {
+		q = q.WhereKeys(req.Keys...)
+	}
+	if len(req.Names) > 0 {
+		q = q.WhereNames(req.Names...)
+	}
+	if len(req.Events) > 0 {
+		q = q.WhereEvents(req.Events...)
+	}
+	if req.Limit > 0 {
+		q = q.Limit(req.Limit)
+	}
+	if req.Offset > 0 {
+		q = q.Offset(req.Offset)
+	}
+	if err = q.Entries(&res.Subscriptions).Exec(ctx, nil); err != nil {
+		return NotifySubscriptionRetrieveResponse{}, err
+	}
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: notify.OntologyIDsFromSubscriptions(res.Subscriptions),
+	}); err != nil {
+		return NotifySubscriptionRetrieveResponse{}, err
+	}
+	res.Subscriptions = redactSecrets(res.Subscriptions)
+	return res, nil
+}
+
+// NotifySubscriptionDeleteRequest is a request to delete webhook subscriptions.
+type NotifySubscriptionDeleteRequest struct {
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+}
+
+// DeleteSubscription deletes webhook subscriptions along with their dead letters.
+func (s *NotifyService) DeleteSubscription(
+	ctx context.Context,
+	req NotifySubscriptionDeleteRequest,
+) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: notify.OntologyIDs(req.Keys),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Delete(ctx, req.Keys...)
+	})
+}
+
+// NotifyDeadLetterRetrieveRequest is a request to retrieve the events that could not
+// be delivered to webhook subscriptions.
+type NotifyDeadLetterRetrieveRequest struct {
+	// Subscriptions are the keys of the subscriptions to retrieve dead letters for.
+	Subscriptions []uuid.UUID `json:"subscriptions" msgpack:"subscriptions"`
+}
+
+// NotifyDeadLetterRetrieveResponse is a response to a NotifyDeadLetterRetrieveRequest.
+type NotifyDeadLetterRetrieveResponse struct {
+	DeadLetters []notify.DeadLetter `json:"dead_letters" msgpack:"dead_letters"`
+}
+
+// RetrieveDeadLetters retrieves the events that could not be delivered to the given
+// subscriptions, or to all subscriptions if none are provided.
+func (s *NotifyService) RetrieveDeadLetters(
+	ctx context.Context,
+	req NotifyDeadLetterRetrieveRequest,
+) (res NotifyDeadLetterRetrieveResponse, err error) {
+	if res.DeadLetters, err = s.internal.RetrieveDeadLetters(ctx, nil, req.Subscriptions...); err != nil {
+		return NotifyDeadLetterRetrieveResponse{}, err
+	}
+	subs := lo.Uniq(append(
+		req.Subscriptions,
+		lo.Map(res.DeadLetters, func(d notify.DeadLetter, _ int) uuid.UUID { return d.Subscription })...,
+	))
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: notify.OntologyIDs(subs),
+	}); err != nil {
+		return NotifyDeadLetterRetrieveResponse{}, err
+	}
+	return res, nil
+}
+
+// redactSecrets removes the signing secrets from subscriptions before they are
+// returned to clients.
+func redactSecrets(subs []Subscription) []Subscription {
+	return lo.Map(subs, func(s Subscription, _ int) Subscription {
+		s.Secret = ""
+		return s
+	})
+}
@@ -73,12 +73,13 @@ func (s *RangeService) Create(ctx context.Context, req RangeCreateRequest) (res
 
 type (
 	RangeRetrieveRequest struct {
-		Keys         []uuid.UUID     `json:"keys" msgpack:"keys"`
-		Names        []string        `json:"names" msgpack:"names"`
-		Term         string          `json:"term" msgpack:"term"`
-		OverlapsWith telem.TimeRange `json:"overlaps_with" msgpack:"overlaps_with"`
-		Limit        int             `json:"limit" msgpack:"limit"`
-		Offset       int             `json:"offset" msgpack:"offset"`
+		Keys           []uuid.UUID     `json:"keys" msgpack:"keys"`
+		Names          []string        `json:"names" msgpack:"names"`
+		Term           string          `json:"term" msgpack:"term"`
+		OverlapsWith   telem.TimeRange `json:"overlaps_with" msgpack:"overlaps_with"`
+		Limit          int             `json:"limit" msgpack:"limit"`
+		Offset         int             `json:"offset" msgpack:"offset"`
+		IncludeDeleted bool            `json:"include_deleted" msgpack:"include_deleted"`
 	}
 	RangeRetrieveResponse struct {
 		Ranges []Range `json:"ranges" msgpack:"ranges"`
@@ -112,6 +113,9 @@ func (s *RangeService) Retrieve(ctx context.Context, req RangeRetrieveRequest) (
 	if req.Offset > 0 {
 		q = q.Offset(req.Offset)
 	}
+	if req.IncludeDeleted {
+		q = q.IncludeDeleted(true)
+	}
 	err := q.Exec(ctx, nil)
 	if err != nil {
 		return RangeRetrieveResponse{}, err
@@ -166,6 +170,28 @@ func (s *RangeService) Delete(ctx context.Context, req RangeDeleteRequest) (res
 	})
 }
 
+type RangeRestoreRequest struct {
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+}
+
+func (s *RangeService) Restore(ctx context.Context, req RangeRestoreRequest) (res types.Nil, _ error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: ranger.OntologyIDs(req.Keys),
+	}); err != nil {
+		return res, err
+	}
+	return res, s.WithTx(ctx, func(tx gorp.Tx) error {
+		for _, key := range req.Keys {
+			if err := s.internal.NewWriter(tx).Restore(ctx, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 type (
 	RangeKVGetRequest struct {
 		Range uuid.UUID `json:"range" msgpack:"range"`
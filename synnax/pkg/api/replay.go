@@ -0,0 +1,127 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/freighter/freightfluence"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/replay"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/confluence/plumber"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// ReplayService allows clients to replay historical data onto virtual mirror channels.
+type ReplayService struct {
+	alamos.Instrumentation
+	accessProvider
+	internal *replay.Service
+}
+
+func NewReplayService(p Provider) *ReplayService {
+	return &ReplayService{
+		Instrumentation: p.Instrumentation,
+		accessProvider:  p.access,
+		internal:        p.Config.Replay,
+	}
+}
+
+// ReplayRequest is a request to a replayer. The first request on a stream must be an
+// Open command containing the configuration of the replayer.
+type ReplayRequest struct {
+	replay.Request
+	// Config is the configuration of the replayer. Only used by the Open command.
+	Config replay.Config `json:"config" msgpack:"config"`
+}
+
+// ReplayResponse is a response from a replayer.
+type ReplayResponse struct {
+	replay.Response
+	// Channels are the mirror channels of the replayer, in the same order as the keys
+	// in its configuration. Only set in the response to the Open command.
+	Channels []channel.Channel `json:"channels" msgpack:"channels"`
+}
+
+type ReplayStream = freighter.ServerStream[ReplayRequest, ReplayResponse]
+
+// Stream opens a replayer that re-streams a past time range of a set of channels onto
+// virtual mirror channels, and then executes play, pause, seek, speed, and step
+// commands sent by the client until the client closes the stream.
+func (s *ReplayService) Stream(ctx context.Context, stream ReplayStream) error {
+	sCtx, cancel := signal.WithCancel(ctx, signal.WithInstrumentation(s.Instrumentation.Child("replay")))
+	defer cancel()
+	r, err := s.openReplayer(sCtx, stream)
+	if err != nil {
+		return err
+	}
+	var (
+		receiver = &freightfluence.TransformReceiver[replay.Request, ReplayRequest]{
+			Receiver: stream,
+			Transform: func(_ context.Context, req ReplayRequest) (replay.Request, bool, error) {
+				return req.Request, true, nil
+			},
+		}
+		sender = &freightfluence.TransformSender[replay.Response, ReplayResponse]{
+			Sender: freighter.SenderNopCloser[ReplayResponse]{StreamSender: stream},
+			Transform: func(ctx context.Context, res replay.Response) (ReplayResponse, bool, error) {
+				if res.Error != nil {
+					res.Error = errors.Encode(ctx, res.Error, false)
+				}
+				return ReplayResponse{Response: res}, true, nil
+			},
+		}
+		pipe = plumber.New()
+	)
+	plumber.SetSegment[replay.Request, replay.Response](pipe, "replayer", r)
+	plumber.SetSink[replay.Response](pipe, "sender", sender)
+	plumber.SetSource[replay.Request](pipe, "receiver", receiver)
+	plumber.MustConnect[replay.Response](pipe, "replayer", "sender", 1)
+	plumber.MustConnect[replay.Request](pipe, "receiver", "replayer", 1)
+	pipe.Flow(sCtx, confluence.CloseOutputInletsOnExit(), confluence.CancelOnFail())
+	return sCtx.Wait()
+}
+
+func (s *ReplayService) openReplayer(ctx context.Context, stream ReplayStream) (replay.Replayer, error) {
+	req, err := stream.Receive()
+	if err != nil {
+		return nil, err
+	}
+	if req.Command != replay.Open {
+		return nil, errors.Wrap(validate.Error, "[replay] - first request must be an open command")
+	}
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: framer.OntologyIDs(req.Config.Keys),
+	}); err != nil {
+		return nil, err
+	}
+	r, err := s.internal.NewReplayer(ctx, req.Config)
+	if err != nil {
+		return nil, err
+	}
+	return r, stream.Send(ReplayResponse{
+		Response: replay.Response{
+			Variant:  replay.AckResponse,
+			Command:  replay.Open,
+			Ack:      true,
+			Position: req.Config.Bounds.Start,
+		},
+		Channels: r.Mirrors(),
+	})
+}
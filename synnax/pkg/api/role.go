@@ -0,0 +1,205 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"context"
+	"go/types"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+type RoleService struct {
+	dbProvider
+	accessProvider
+	internal *role.Service
+}
+
+func NewRoleService(p Provider) *RoleService {
+	return &RoleService{
+		internal:       p.Config.Role,
+		dbProvider:     p.db,
+		accessProvider: p.access,
+	}
+}
+
+type Role = role.Role
+
+// RoleCreateRequest is a request to create roles in the cluster.
+type RoleCreateRequest struct {
+	// Roles are the roles to create.
+	Roles []Role `json:"roles" msgpack:"roles"`
+}
+
+// RoleCreateResponse is a response to a RoleCreateRequest.
+type RoleCreateResponse struct {
+	// Roles are the roles that were created.
+	Roles []Role `json:"roles" msgpack:"roles"`
+}
+
+// Create creates the roles in the cluster.
+func (s *RoleService) Create(
+	ctx context.Context,
+	req RoleCreateRequest,
+) (res RoleCreateResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Create,
+		Objects: role.OntologyIDsFromRoles(req.Roles),
+	}); err != nil {
+		return res, err
+	}
+	return res, s.WithTx(ctx, func(tx gorp.Tx) error {
+		if err := s.internal.NewWriter(tx).CreateMany(ctx, &req.Roles); err != nil {
+			return err
+		}
+		res.Roles = req.Roles
+		return nil
+	})
+}
+
+type RoleRetrieveRequest struct {
+	// Keys are the keys of the roles to retrieve.
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+	// Names are the names of the roles to retrieve.
+	Names []string `json:"names" msgpack:"names"`
+	// For retrieves the roles that the resource with the given ID is a member of,
+	// including roles inherited through membership in other roles.
+	For ontology.ID `json:"for" msgpack:"for"`
+}
+
+type RoleRetrieveResponse struct {
+	// Roles are the roles that were retrieved.
+	Roles []Role `json:"roles" msgpack:"roles"`
+}
+
+// Retrieve retrieves roles from the cluster.
+func (s *RoleService) Retrieve(
+	ctx context.Context,
+	req RoleRetrieveRequest,
+) (res RoleRetrieveResponse, err error) {
+	if !req.For.IsZero() {
+		res.Roles, err = s.internal.RetrieveFor(ctx, req.For, true, nil)
+	} else {
+		q := s.internal.NewRetrieve()
+		if len(req.Keys) != 0 {
+			q = q.WhereKeys(req.Keys...)
+		}
+		if len(req.Names) != 0 {
+			q = q.WhereNames(req.Names...)
+		}
+		err = q.Entries(&res.Roles).Exec(ctx, nil)
+	}
+	if err != nil {
+		return RoleRetrieveResponse{}, err
+	}
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: role.OntologyIDsFromRoles(res.Roles),
+	}); err != nil {
+		return RoleRetrieveResponse{}, err
+	}
+	return res, nil
+}
+
+type RoleDeleteRequest struct {
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+}
+
+// Delete deletes the roles with the given keys, removing all of their memberships.
+func (s *RoleService) Delete(
+	ctx context.Context,
+	req RoleDeleteRequest,
+) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: role.OntologyIDs(req.Keys),
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Delete(ctx, req.Keys...)
+	})
+}
+
+type RoleAddMembersRequest struct {
+	Role    uuid.UUID     `json:"role" msgpack:"role" validate:"required"`
+	Members []ontology.ID `json:"members" msgpack:"members" validate:"required"`
+}
+
+// AddMembers adds resources (typically users or other roles) as members of a role.
+func (s *RoleService) AddMembers(
+	ctx context.Context,
+	req RoleAddMembersRequest,
+) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: []ontology.ID{role.OntologyID(req.Role)},
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).AddMembers(ctx, req.Role, req.Members...)
+	})
+}
+
+type RoleRemoveMembersRequest struct {
+	Role    uuid.UUID     `json:"role" msgpack:"role" validate:"required"`
+	Members []ontology.ID `json:"members" msgpack:"members" validate:"required"`
+}
+
+// RemoveMembers removes resources from the members of a role.
+func (s *RoleService) RemoveMembers(
+	ctx context.Context,
+	req RoleRemoveMembersRequest,
+) (types.Nil, error) {
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: []ontology.ID{role.OntologyID(req.Role)},
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).RemoveMembers(ctx, req.Role, req.Members...)
+	})
+}
+
+type RoleRetrieveMembersRequest struct {
+	Role uuid.UUID `json:"role" msgpack:"role" validate:"required"`
+}
+
+type RoleRetrieveMembersResponse struct {
+	// Members are the ontology IDs of the direct members of the role.
+	Members []ontology.ID `json:"members" msgpack:"members"`
+}
+
+// RetrieveMembers retrieves the direct members of a role.
+func (s *RoleService) RetrieveMembers(
+	ctx context.Context,
+	req RoleRetrieveMembersRequest,
+) (res RoleRetrieveMembersResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: []ontology.ID{role.OntologyID(req.Role)},
+	}); err != nil {
+		return res, err
+	}
+	res.Members, err = s.internal.RetrieveMembers(ctx, req.Role, nil)
+	return res, err
+}
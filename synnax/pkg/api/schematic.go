@@ -103,6 +103,8 @@ func (s *SchematicService) SetData(ctx context.Context, req SchematicSetDataRequ
 type (
 	SchematicRetrieveRequest struct {
 		Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+		// IncludeDeleted includes schematics that have been moved to the trash.
+		IncludeDeleted bool `json:"include_deleted" msgpack:"include_deleted"`
 	}
 	SchematicRetrieveResponse struct {
 		Schematics []schematic.Schematic `json:"schematics" msgpack:"schematics"`
@@ -111,7 +113,10 @@ type (
 
 func (s *SchematicService) Retrieve(ctx context.Context, req SchematicRetrieveRequest) (res SchematicRetrieveResponse, err error) {
 	err = s.internal.NewRetrieve().
-		WhereKeys(req.Keys...).Entries(&res.Schematics).Exec(ctx, nil)
+		WhereKeys(req.Keys...).
+		IncludeDeleted(req.IncludeDeleted).
+		Entries(&res.Schematics).
+		Exec(ctx, nil)
 	if err != nil {
 		return SchematicRetrieveResponse{}, err
 	}
@@ -142,6 +147,23 @@ func (s *SchematicService) Delete(ctx context.Context, req SchematicDeleteReques
 	})
 }
 
+type SchematicRestoreRequest struct {
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+}
+
+func (s *SchematicService) Restore(ctx context.Context, req SchematicRestoreRequest) (res types.Nil, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: schematic.OntologyIDs(req.Keys),
+	}); err != nil {
+		return res, err
+	}
+	return res, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Restore(ctx, req.Keys...)
+	})
+}
+
 type (
 	SchematicCopyRequest struct {
 		Key      uuid.UUID `json:"key" msgpack:"key"`
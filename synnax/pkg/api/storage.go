@@ -0,0 +1,115 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package api
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/cluster"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/service/access"
+	framesvc "github.com/synnaxlabs/synnax/pkg/service/framer"
+)
+
+// StorageService allows clients to inspect the disk usage of the channels leased by
+// the node they are connected to, and to reclaim the space occupied by deleted data.
+type StorageService struct {
+	clusterProvider
+	accessProvider
+	internal *framesvc.Service
+}
+
+func NewStorageService(p Provider) *StorageService {
+	return &StorageService{
+		clusterProvider: p.cluster,
+		accessProvider:  p.access,
+		internal:        p.Config.Framer,
+	}
+}
+
+// StorageUsageRequest is a request to retrieve the disk usage of a set of channels.
+type StorageUsageRequest struct {
+	// Keys are the keys of the channels to retrieve the usage of. If empty, the usage
+	// of every channel leased by the node is retrieved.
+	Keys channel.Keys `json:"keys" msgpack:"keys"`
+}
+
+// StorageUsageResponse is returned by StorageService.Usage.
+type StorageUsageResponse struct {
+	// Usages are the disk usages of the requested channels, sorted by channel key.
+	Usages []framer.ChannelUsage `json:"usages" msgpack:"usages"`
+}
+
+// Usage returns the size, file count, and reclaimable space of the data files of the
+// requested channels.
+func (s *StorageService) Usage(ctx context.Context, req StorageUsageRequest) (res StorageUsageResponse, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Retrieve,
+		Objects: s.objects(req.Keys),
+	}); err != nil {
+		return res, err
+	}
+	res.Usages, err = s.internal.Usage(ctx, req.Keys...)
+	return res, err
+}
+
+// StorageGCRequest is a request to garbage collect the data files of a set of
+// channels.
+type StorageGCRequest = framer.GCConfig
+
+// StorageGCResponse reports the progress of a garbage collection after it has
+// finished with a channel.
+type StorageGCResponse = framer.GCProgress
+
+type StorageGCStream = freighter.ServerStream[StorageGCRequest, StorageGCResponse]
+
+// GarbageCollect receives a single request from the client and garbage collects the
+// requested channels, sending a response after each channel is finished. The stream
+// is closed once every channel has been garbage collected.
+func (s *StorageService) GarbageCollect(ctx context.Context, stream StorageGCStream) error {
+	req, err := stream.Receive()
+	if err != nil {
+		return err
+	}
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Update,
+		Objects: s.objects(req.Keys),
+	}); err != nil {
+		return err
+	}
+	var sendErr error
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	err = s.internal.GarbageCollect(ctx, req, func(p framer.GCProgress) {
+		if sendErr == nil {
+			if sendErr = stream.Send(p); sendErr != nil {
+				cancel()
+			}
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	return err
+}
+
+// objects returns the ontology IDs that a request over the given channels accesses,
+// which is the cluster itself if no channels are provided.
+func (s *StorageService) objects(keys channel.Keys) []ontology.ID {
+	if len(keys) == 0 {
+		return []ontology.ID{cluster.ClusterOntologyID(s.cluster.Key())}
+	}
+	return framer.OntologyIDs(keys)
+}
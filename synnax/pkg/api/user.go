@@ -159,6 +159,30 @@ func (s *UserService) Rename(ctx context.Context, req UserRenameRequest) (types.
 	})
 }
 
+type UserLinkIdentityRequest struct {
+	Key      uuid.UUID     `json:"key" msgpack:"key"`
+	Identity user.Identity `json:"identity" msgpack:"identity"`
+}
+
+// LinkIdentity links the user with the provided key to an identity from an external
+// identity provider, allowing the user to log in through that provider.
+func (s *UserService) LinkIdentity(ctx context.Context, req UserLinkIdentityRequest) (types.Nil, error) {
+	subject := getSubject(ctx)
+	if subject.Key == req.Key.String() {
+		return types.Nil{}, errors.New("you cannot link an identity to your own user through the user service")
+	}
+	if err := s.access.Enforce(ctx, access.Request{
+		Subject: subject,
+		Action:  access.Update,
+		Objects: []ontology.ID{user.OntologyID(req.Key)},
+	}); err != nil {
+		return types.Nil{}, err
+	}
+	return types.Nil{}, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).LinkIdentity(ctx, req.Key, req.Identity)
+	})
+}
+
 type (
 	UserRetrieveRequest struct {
 		Keys      []uuid.UUID `json:"keys" msgpack:"keys"`
@@ -112,6 +112,8 @@ type (
 		Author uuid.UUID   `json:"author" msgpack:"author"`
 		Limit  int         `json:"limit" msgpack:"limit"`
 		Offset int         `json:"offset" msgpack:"offset"`
+		// IncludeDeleted includes workspaces that have been moved to the trash.
+		IncludeDeleted bool `json:"include_deleted" msgpack:"include_deleted"`
 	}
 	WorkspaceRetrieveResponse struct {
 		Workspaces []workspace.Workspace `json:"workspaces" msgpack:"workspaces"`
@@ -135,6 +137,9 @@ func (s *WorkspaceService) Retrieve(
 	if req.Offset > 0 {
 		q = q.Offset(req.Offset)
 	}
+	if req.IncludeDeleted {
+		q = q.IncludeDeleted(true)
+	}
 	err = q.Entries(&res.Workspaces).Exec(ctx, nil)
 	if eErr := s.access.Enforce(ctx, access.Request{
 		Subject: getSubject(ctx),
@@ -162,3 +167,20 @@ func (s *WorkspaceService) Delete(ctx context.Context, req WorkspaceDeleteReques
 		return s.internal.NewWriter(tx).Delete(ctx, req.Keys...)
 	})
 }
+
+type WorkspaceRestoreRequest struct {
+	Keys []uuid.UUID `json:"keys" msgpack:"keys"`
+}
+
+func (s *WorkspaceService) Restore(ctx context.Context, req WorkspaceRestoreRequest) (res types.Nil, err error) {
+	if err = s.access.Enforce(ctx, access.Request{
+		Subject: getSubject(ctx),
+		Action:  access.Delete,
+		Objects: workspace.OntologyIDs(req.Keys),
+	}); err != nil {
+		return res, err
+	}
+	return res, s.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.internal.NewWriter(tx).Restore(ctx, req.Keys...)
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package channel
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/synnax/pkg/storage/ts"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// AlterConfig defines the changes made to a channel by Alterer.Alter.
+type AlterConfig struct {
+	// DataType is the new data type of the channel. The channel's existing data is cast
+	// to the new data type, and the alter fails if any sample cannot be represented in
+	// it. If empty, the data type is left unchanged.
+	DataType telem.DataType
+	// Index is the key of the new index of the channel. The index must be leased to the
+	// same node as the channel, and must have the same timestamps as the channel's
+	// current index over all of its data. If zero, the index is left unchanged.
+	Index Key
+	// OnProgress is called as the channel's data is migrated.
+	// [OPTIONAL]
+	OnProgress func(AlterProgress)
+}
+
+// AlterProgress reports the number of bytes of channel data migrated by an alter.
+type AlterProgress = ts.AlterProgress
+
+// Alterer changes the data type or index of existing channels.
+type Alterer interface {
+	// Alter rewrites the data of the channel with the given key to match the provided
+	// configuration. Alter can take a long time for channels with a lot of data, during
+	// which the channel continues to serve reads and writes. It returns an error if
+	// writers or iterators are open on the channel when the migrated data is swapped in.
+	// Alter must be called on the channel's leaseholder.
+	Alter(ctx context.Context, key Key, cfg AlterConfig) error
+}
+
+func (s *service) Alter(ctx context.Context, key Key, cfg AlterConfig) error {
+	return s.proxy.alter(ctx, key, cfg)
+}
+
+// alter migrates the storage layer data of the channel and then updates its data type
+// and index in the cluster DB. Migrating data requires direct access to the channel's
+// storage, so alter can only be executed on the channel's leaseholder.
+func (lp *leaseProxy) alter(ctx context.Context, key Key, cfg AlterConfig) error {
+	var ch Channel
+	if err := gorp.NewRetrieve[Key, Channel]().
+		WhereKeys(key).
+		Entry(&ch).
+		Exec(ctx, lp.ClusterDB); err != nil {
+		return err
+	}
+	if err := lp.validateAlter(ctx, ch, cfg); err != nil {
+		return err
+	}
+	if err := lp.TSChannel.AlterChannel(ctx, key.StorageKey(), ts.AlterConfig{
+		DataType:   cfg.DataType,
+		Index:      cfg.Index.StorageKey(),
+		OnProgress: cfg.OnProgress,
+	}); err != nil {
+		return err
+	}
+	return gorp.NewUpdate[Key, Channel]().
+		WhereKeys(key).
+		Change(func(c Channel) Channel {
+			if cfg.DataType != "" {
+				c.DataType = cfg.DataType
+				if c.Scale.Enabled() {
+					c.Scale.RawDataType = cfg.DataType
+				}
+			}
+			if cfg.Index != 0 {
+				c.LocalIndex = cfg.Index.LocalKey()
+			}
+			return c
+		}).
+		Exec(ctx, lp.ClusterDB)
+}
+
+func (lp *leaseProxy) validateAlter(ctx context.Context, ch Channel, cfg AlterConfig) error {
+	v := validate.New("channel.alter")
+	v.Ternaryf("key", ch.Internal, "cannot alter internal channel %v", ch)
+	v.Ternaryf("key", ch.Virtual, "cannot alter virtual channel %v", ch)
+	v.Ternaryf("key", ch.Leaseholder != lp.HostResolver.HostKey(),
+		"channel %v must be altered on its leaseholder node %d", ch, ch.Leaseholder)
+	v.Ternaryf("data_type", cfg.DataType == "" && cfg.Index == 0, "either a data type or index must be provided")
+	if cfg.Index != 0 {
+		v.Ternaryf("index", cfg.Index.Leaseholder() != ch.Leaseholder,
+			"index channel %d must be leased to the same node as channel %v", cfg.Index, ch)
+	}
+	if err := v.Error(); err != nil {
+		return err
+	}
+	if cfg.Index == 0 {
+		return nil
+	}
+	var idx Channel
+	if err := gorp.NewRetrieve[Key, Channel]().
+		WhereKeys(cfg.Index).
+		Entry(&idx).
+		Exec(ctx, lp.ClusterDB); err != nil {
+		return err
+	}
+	if !idx.IsIndex {
+		return errors.Wrapf(validate.Error, "channel %v is not an index", idx)
+	}
+	return nil
+}
@@ -200,6 +200,37 @@ type Channel struct {
 	// Internal determines if a channel is a channel created by Synnax or
 	// created by the user.
 	Internal bool `json:"internal" msgpack:"internal"`
+	// Unit is the engineering unit of the channel's values (e.g. "psi"). When the
+	// channel has a Scale, this is the unit of the scaled values.
+	Unit string `json:"unit" msgpack:"unit"`
+	// Description is a human-readable description of the channel.
+	Description string `json:"description" msgpack:"description"`
+	// Scale optionally converts the raw values of the channel into engineering units.
+	// See Scale for more details.
+	Scale Scale `json:"scale" msgpack:"scale"`
+	// DeletedAt is the time the channel was moved to the trash. A channel in the trash
+	// is hidden from retrieval unless explicitly requested, and can be restored until it
+	// is purged. Zero if the channel is not in the trash.
+	DeletedAt telem.TimeStamp `json:"deleted_at" msgpack:"deleted_at"`
+}
+
+// Trashed returns true if the channel is in the trash.
+func (c Channel) Trashed() bool { return c.DeletedAt != 0 }
+
+// Metadata is the set of descriptive fields of a Channel that can be changed after
+// the channel is created.
+type Metadata struct {
+	// Unit is the engineering unit of the channel's values.
+	Unit string `json:"unit" msgpack:"unit"`
+	// Description is a human-readable description of the channel.
+	Description string `json:"description" msgpack:"description"`
+	// Scale converts the raw values of the channel into engineering units.
+	Scale Scale `json:"scale" msgpack:"scale"`
+}
+
+// Metadata returns the changeable metadata of the channel.
+func (c Channel) Metadata() Metadata {
+	return Metadata{Unit: c.Unit, Description: c.Description, Scale: c.Scale}
 }
 
 func (c Channel) String() string {
@@ -16,7 +16,9 @@ import (
 	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
 	"github.com/synnaxlabs/synnax/pkg/distribution/core"
 	"github.com/synnaxlabs/synnax/pkg/distribution/core/mock"
+	"github.com/synnaxlabs/x/query"
 	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
 )
 
 var _ = Describe("Delete", Ordered, func() {
@@ -61,12 +63,66 @@ var _ = Describe("Delete", Ordered, func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(exists).To(BeFalse())
 				})
-				It("Should not be able to retrieve the channel from the storage DB", func() {
+				It("Should keep the channel in the storage DB until it is purged", func() {
 					Expect(services[1].Delete(ctx, ch.Key(), true)).To(Succeed())
+					Expect(builder.Cores[1].Storage.TS.RetrieveChannels(ctx, ch.Key().StorageKey())).To(HaveLen(1))
+					Expect(services[1].Purge(ctx, ch.Key(), true)).To(Succeed())
 					channels, err := builder.Cores[1].Storage.TS.RetrieveChannels(ctx, ch.Key().StorageKey())
 					Expect(err).To(MatchError(cesium.ErrChannelNotFound))
 					Expect(channels).To(BeEmpty())
 				})
+				It("Should retrieve the channel when deleted channels are included", func() {
+					Expect(services[1].Delete(ctx, ch.Key(), true)).To(Succeed())
+					var res channel.Channel
+					Expect(services[1].NewRetrieve().
+						WhereKeys(ch.Key()).
+						IncludeDeleted(true).
+						Entry(&res).
+						Exec(ctx, nil)).To(Succeed())
+					Expect(res.Trashed()).To(BeTrue())
+					Expect(services[1].NewRetrieve().
+						WhereKeys(ch.Key()).
+						Entry(&res).
+						Exec(ctx, nil)).To(HaveOccurredAs(query.NotFound))
+				})
+				It("Should retrieve channels deleted before a time", func() {
+					Expect(services[1].Delete(ctx, ch.Key(), true)).To(Succeed())
+					var res []channel.Channel
+					Expect(services[1].NewRetrieve().
+						WhereDeletedBefore(telem.Now()).
+						Entries(&res).
+						Exec(ctx, nil)).To(Succeed())
+					Expect(channel.KeysFromChannels(res)).To(ContainElement(ch.Key()))
+					res = nil
+					Expect(services[1].NewRetrieve().
+						WhereDeletedBefore(telem.Now() - telem.TimeStamp(telem.Hour)).
+						Entries(&res).
+						Exec(ctx, nil)).To(Succeed())
+					Expect(channel.KeysFromChannels(res)).ToNot(ContainElement(ch.Key()))
+				})
+				It("Should restore a deleted channel", func() {
+					Expect(services[1].Delete(ctx, ch.Key(), true)).To(Succeed())
+					Expect(services[1].Restore(ctx, ch.Key())).To(Succeed())
+					var res channel.Channel
+					Expect(services[1].NewRetrieve().WhereKeys(ch.Key()).Entry(&res).Exec(ctx, nil)).To(Succeed())
+					Expect(res.Trashed()).To(BeFalse())
+				})
+				It("Should not restore a channel whose index is deleted", func() {
+					Expect(services[1].DeleteMany(ctx, channel.Keys{idxCh.Key(), ch.Key()}, true)).To(Succeed())
+					Expect(services[1].Restore(ctx, ch.Key())).To(MatchError(ContainSubstring("index is deleted")))
+					Expect(services[1].RestoreMany(ctx, channel.Keys{idxCh.Key(), ch.Key()})).To(Succeed())
+				})
+				It("Should not return a deleted channel when creating a channel with the same name", func() {
+					Expect(services[1].Delete(ctx, ch.Key(), true)).To(Succeed())
+					ch2 := channel.Channel{Name: ch.Name, DataType: telem.Float64T, LocalIndex: idxCh.LocalKey}
+					Expect(services[1].CreateIfNameDoesntExist(ctx, &ch2)).To(Succeed())
+					Expect(ch2.Key()).ToNot(Equal(ch.Key()))
+				})
+				It("Should not delete internal channels unless allowed", func() {
+					internal := channel.Channel{Name: "internal", DataType: telem.Float64T, Virtual: true, Internal: true}
+					Expect(services[1].Create(ctx, &internal)).To(Succeed())
+					Expect(services[1].Delete(ctx, internal.Key(), false)).To(MatchError(ContainSubstring("internal")))
+				})
 			})
 			/*
 				Commented out as multi-node deployment currently does not work.
@@ -88,6 +88,9 @@ func (lp *leaseProxy) handle(ctx context.Context, msg CreateMessage) (CreateMess
 func (lp *leaseProxy) create(ctx context.Context, tx gorp.Tx, _channels *[]Channel, retrieveIfNameExists bool) error {
 	channels := *_channels
 	for i, ch := range channels {
+		if err := ch.Scale.validate(ch); err != nil {
+			return err
+		}
 		if ch.LocalKey != 0 {
 			channels[i].LocalKey = 0
 		}
@@ -160,6 +163,9 @@ func (lp *leaseProxy) maybeRetrieveExisting(
 	if retrieveIfNameExists {
 		names := Names(*channels)
 		if err = gorp.NewRetrieve[Key, Channel]().Where(func(c *Channel) bool {
+			if c.Trashed() {
+				return false
+			}
 			v := lo.IndexOf(names, c.Name)
 			exists := v != -1
 			if exists {
@@ -274,17 +280,6 @@ func (lp *leaseProxy) createRemote(
 	return res.Channels, nil
 }
 
-func (lp *leaseProxy) deleteByName(ctx context.Context, tx gorp.Tx, names []string, allowInternal bool) error {
-	var res []Channel
-	if err := gorp.NewRetrieve[Key, Channel]().Entries(&res).Where(func(c *Channel) bool {
-		return lo.Contains(names, c.Name)
-	}).Exec(ctx, tx); err != nil {
-		return err
-	}
-	keys := KeysFromChannels(res)
-	return lp.delete(ctx, tx, keys, allowInternal)
-}
-
 func (lp *leaseProxy) delete(ctx context.Context, tx gorp.Tx, keys Keys, allowInternal bool) error {
 	if !allowInternal {
 		var internalChannels []Channel
@@ -458,3 +453,32 @@ func (lp *leaseProxy) renameGateway(ctx context.Context, tx gorp.Tx, keys Keys,
 	}
 	return lp.TSChannel.RenameChannels(ctx, keys.Storage(), names)
 }
+
+// updateMetadata changes the metadata of the channels with the given keys. Metadata is
+// not held by the storage layer, so the update is executed against the cluster DB on
+// this node, which forwards it to the channels' leaseholders. The channels must have
+// been replicated to this node.
+func (lp *leaseProxy) updateMetadata(
+	ctx context.Context,
+	tx gorp.Tx,
+	keys Keys,
+	metadata []Metadata,
+	allowInternal bool,
+) error {
+	if len(keys) != len(metadata) {
+		return errors.Wrap(validate.Error, "keys and metadata must be the same length")
+	}
+	return gorp.NewUpdate[Key, Channel]().
+		WhereKeys(keys...).
+		ChangeErr(func(c Channel) (Channel, error) {
+			if c.Internal && !allowInternal {
+				return c, errors.Wrapf(validate.Error, "cannot update metadata of internal channel %v", c)
+			}
+			md := metadata[lo.IndexOf(keys, c.Key())]
+			c.Unit, c.Description = md.Unit, md.Description
+			c.Scale = md.Scale
+			c = applyAdjustments(c)
+			return c, c.Scale.validate(c)
+		}).
+		Exec(ctx, tx)
+}
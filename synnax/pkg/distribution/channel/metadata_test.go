@@ -0,0 +1,205 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package channel_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core/mock"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/schema"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Metadata", Ordered, func() {
+	var (
+		services map[core.NodeKey]channel.Service
+		builder  *mock.CoreBuilder
+	)
+	BeforeAll(func() { builder, services = provisionServices() })
+	AfterAll(func() {
+		Expect(builder.Close()).To(Succeed())
+		Expect(builder.Cleanup()).To(Succeed())
+	})
+	retrieve := func(svc channel.Service, key channel.Key) channel.Channel {
+		var ch channel.Channel
+		Expect(svc.NewRetrieve().WhereKeys(key).Entry(&ch).Exec(ctx, nil)).To(Succeed())
+		return ch
+	}
+
+	Describe("Create", func() {
+		It("Should create a channel with a unit, description, and scale", func() {
+			ch := channel.Channel{
+				Name:        "PT01",
+				DataType:    telem.Int16T,
+				Rate:        1 * telem.Hz,
+				Unit:        " psi ",
+				Description: "Tank pressure",
+				Scale:       channel.Scale{Type: channel.ScaleLinear, Slope: 0.5, Offset: 10},
+			}
+			Expect(services[1].Create(ctx, &ch)).To(Succeed())
+			res := retrieve(services[1], ch.Key())
+			Expect(res.Unit).To(Equal("psi"))
+			Expect(res.Description).To(Equal("Tank pressure"))
+			Expect(res.Scale.RawDataType).To(Equal(telem.Int16T))
+			Expect(res.Scale.EngineeringDataType).To(Equal(telem.Float64T))
+		})
+		DescribeTable("Invalid scales", func(ch channel.Channel, msg string) {
+			ch.Name = "PT02"
+			ch.Rate = 1 * telem.Hz
+			Expect(services[1].Create(ctx, &ch)).To(MatchError(ContainSubstring(msg)))
+		},
+			Entry("Unknown type", channel.Channel{
+				DataType: telem.Int16T,
+				Scale:    channel.Scale{Type: "log"},
+			}, "unknown scale type"),
+			Entry("Zero slope", channel.Channel{
+				DataType: telem.Int16T,
+				Scale:    channel.Scale{Type: channel.ScaleLinear},
+			}, "slope"),
+			Entry("No coefficients", channel.Channel{
+				DataType: telem.Int16T,
+				Scale:    channel.Scale{Type: channel.ScalePolynomial},
+			}, "coefficients"),
+			Entry("Mismatched raw data type", channel.Channel{
+				DataType: telem.Int16T,
+				Scale:    channel.Scale{Type: channel.ScaleLinear, Slope: 1, RawDataType: telem.Int32T},
+			}, "raw_data_type"),
+			Entry("Non-numeric engineering data type", channel.Channel{
+				DataType: telem.Int16T,
+				Scale:    channel.Scale{Type: channel.ScaleLinear, Slope: 1, EngineeringDataType: telem.StringT},
+			}, "engineering_data_type"),
+			Entry("Non-numeric channel", channel.Channel{
+				DataType: telem.UUIDT,
+				Scale:    channel.Scale{Type: channel.ScaleLinear, Slope: 1},
+			}, "not numeric"),
+			Entry("Index channel", channel.Channel{
+				DataType: telem.TimeStampT,
+				IsIndex:  true,
+				Scale:    channel.Scale{Type: channel.ScaleLinear, Slope: 1},
+			}, "cannot be scaled"),
+		)
+	})
+
+	Describe("UpdateMetadata", func() {
+		var ch channel.Channel
+		BeforeEach(func() {
+			ch = channel.Channel{Name: "TC01", DataType: telem.Float32T, Rate: 1 * telem.Hz, Unit: "C"}
+		})
+		It("Should update the metadata of a channel on the local node", func() {
+			ch.Leaseholder = 1
+			Expect(services[1].Create(ctx, &ch)).To(Succeed())
+			Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{
+				Unit:        "F",
+				Description: "Engine temperature",
+				Scale:       channel.Scale{Type: channel.ScaleLinear, Slope: 1.8, Offset: 32},
+			}, false)).To(Succeed())
+			res := retrieve(services[1], ch.Key())
+			Expect(res.Name).To(Equal("TC01"))
+			Expect(res.Unit).To(Equal("F"))
+			Expect(res.Description).To(Equal("Engine temperature"))
+			Expect(res.Scale.Slope).To(Equal(1.8))
+			Expect(res.Scale.RawDataType).To(Equal(telem.Float32T))
+		})
+		It("Should update the metadata of a channel leased to a remote node", func() {
+			ch.Leaseholder = 2
+			Expect(services[2].Create(ctx, &ch)).To(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{Unit: "K"}, false)).To(Succeed())
+			}).Should(Succeed())
+			Eventually(func(g Gomega) {
+				var res channel.Channel
+				g.Expect(services[2].NewRetrieve().WhereKeys(ch.Key()).Entry(&res).Exec(ctx, nil)).To(Succeed())
+				g.Expect(res.Unit).To(Equal("K"))
+			}).Should(Succeed())
+		})
+		It("Should remove the scale of a channel", func() {
+			ch.Scale = channel.Scale{Type: channel.ScalePolynomial, Coefficients: []float64{1, 2}}
+			Expect(services[1].Create(ctx, &ch)).To(Succeed())
+			Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{Unit: "C"}, false)).To(Succeed())
+			Expect(retrieve(services[1], ch.Key()).Scale.Enabled()).To(BeFalse())
+		})
+		It("Should not update the metadata of an internal channel", func() {
+			ch.Internal = true
+			Expect(services[1].Create(ctx, &ch)).To(Succeed())
+			Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{Unit: "F"}, false)).
+				To(HaveOccurredAs(validate.Error))
+			Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{Unit: "F"}, true)).To(Succeed())
+		})
+		It("Should not set an invalid scale", func() {
+			Expect(services[1].Create(ctx, &ch)).To(Succeed())
+			Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{
+				Scale: channel.Scale{Type: channel.ScaleLinear},
+			}, false)).To(MatchError(ContainSubstring("slope")))
+			Expect(retrieve(services[1], ch.Key()).Unit).To(Equal("C"))
+		})
+		It("Should return an error if the number of keys and metadata differ", func() {
+			Expect(services[1].UpdateManyMetadata(ctx, channel.Keys{1, 2}, nil, false)).
+				To(HaveOccurredAs(validate.Error))
+		})
+	})
+
+	Describe("Ontology", func() {
+		It("Should include the unit and description in the channel's resource", func() {
+			ch := channel.Channel{Name: "FM01", DataType: telem.Float64T, Rate: 1 * telem.Hz}
+			Expect(services[1].Create(ctx, &ch)).To(Succeed())
+			Expect(services[1].UpdateMetadata(ctx, ch.Key(), channel.Metadata{
+				Unit:        "gpm",
+				Description: "Fuel flow",
+			}, false)).To(Succeed())
+			r := MustSucceed(services[1].RetrieveResource(ctx, ch.Key().String(), nil))
+			unit, ok := schema.Get[string](r, "unit")
+			Expect(ok).To(BeTrue())
+			Expect(unit).To(Equal("gpm"))
+			description, ok := schema.Get[string](r, "description")
+			Expect(ok).To(BeTrue())
+			Expect(description).To(Equal("Fuel flow"))
+		})
+	})
+})
+
+var _ = Describe("Scale", func() {
+	DescribeTable("Apply", func(scale channel.Scale, in telem.Series, expected telem.Series) {
+		in.Alignment = telem.NewAlignmentPair(1, 2)
+		out := scale.Apply(in)
+		Expect(out.DataType).To(Equal(expected.DataType))
+		Expect(out.Data).To(Equal(expected.Data))
+		Expect(out.Alignment).To(Equal(in.Alignment))
+	},
+		Entry("Linear",
+			channel.Scale{Type: channel.ScaleLinear, Slope: 0.5, Offset: -1, RawDataType: telem.Int16T, EngineeringDataType: telem.Float64T},
+			telem.NewSeriesV[int16](-4, 0, 4),
+			telem.NewSeriesV[float64](-3, -1, 1),
+		),
+		Entry("Polynomial",
+			channel.Scale{Type: channel.ScalePolynomial, Coefficients: []float64{1, 0, 2}, RawDataType: telem.Float32T, EngineeringDataType: telem.Float32T},
+			telem.NewSeriesV[float32](0, 1, 2),
+			telem.NewSeriesV[float32](1, 3, 9),
+		),
+		Entry("Rounding and clamping integers",
+			channel.Scale{Type: channel.ScaleLinear, Slope: 100, RawDataType: telem.Float64T, EngineeringDataType: telem.Uint8T},
+			telem.NewSeriesV[float64](-1, 0.014, 1.236, 3),
+			telem.NewSeriesV[uint8](0, 1, 124, 255),
+		),
+		Entry("Disabled",
+			channel.Scale{},
+			telem.NewSeriesV[int16](1, 2),
+			telem.NewSeriesV[int16](1, 2),
+		),
+		Entry("Other data types",
+			channel.Scale{Type: channel.ScaleLinear, Slope: 2, RawDataType: telem.Int16T, EngineeringDataType: telem.Float64T},
+			telem.NewSeriesV[int32](1, 2),
+			telem.NewSeriesV[int32](1, 2),
+		),
+	)
+})
@@ -30,6 +30,7 @@ type Retrieve struct {
 	otg                       *ontology.Ontology
 	keys                      Keys
 	searchTerm                string
+	includeDeleted            bool
 	validateRetrievedChannels func(ctx context.Context, channels []Channel) ([]Channel, error)
 }
 
@@ -103,6 +104,21 @@ func (r Retrieve) WhereNames(names ...string) Retrieve {
 	return r
 }
 
+// IncludeDeleted includes channels in the trash in the results of the query if
+// includeDeleted is true. Channels in the trash are excluded by default.
+func (r Retrieve) IncludeDeleted(includeDeleted bool) Retrieve {
+	r.includeDeleted = includeDeleted
+	return r
+}
+
+// WhereDeletedBefore filters for channels that were moved to the trash before the
+// provided time. WhereDeletedBefore implies IncludeDeleted(true).
+func (r Retrieve) WhereDeletedBefore(ts telem.TimeStamp) Retrieve {
+	r.includeDeleted = true
+	r.gorp.Where(func(ch *Channel) bool { return ch.Trashed() && ch.DeletedAt < ts }, gorp.Guard())
+	return r
+}
+
 // WhereKeys filters for channels with the provided Key. This is an identical interface
 // to gorp.Retrieve.
 func (r Retrieve) WhereKeys(keys ...Key) Retrieve {
@@ -139,7 +155,7 @@ func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
 		}
 		r = r.WhereKeys(keys...)
 	}
-	err := r.gorp.Exec(ctx, gorp.OverrideTx(r.tx, tx))
+	err := r.excludeDeleted().gorp.Exec(ctx, gorp.OverrideTx(r.tx, tx))
 
 	entries := gorp.GetEntries[Key, Channel](r.gorp.Params).All()
 	channels, vErr := r.validateRetrievedChannels(ctx, entries)
@@ -151,7 +167,14 @@ func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
 // with WhereKeys, Exists will ONLY return true if ALL the keys have a matching Channel.
 // Otherwise, Exists returns true if the query has ANY results.
 func (r Retrieve) Exists(ctx context.Context, tx gorp.Tx) (bool, error) {
-	return r.gorp.Exists(ctx, gorp.OverrideTx(r.tx, tx))
+	return r.excludeDeleted().gorp.Exists(ctx, gorp.OverrideTx(r.tx, tx))
+}
+
+func (r Retrieve) excludeDeleted() Retrieve {
+	if !r.includeDeleted {
+		r.gorp.Where(func(ch *Channel) bool { return !ch.Trashed() }, gorp.Guard())
+	}
+	return r
 }
 
 func formatNameMatcher(name string) func(name string) bool {
@@ -0,0 +1,153 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package channel
+
+import (
+	"math"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// ScaleType is the function used to convert a channel's raw values into engineering
+// units.
+type ScaleType string
+
+const (
+	// ScaleNone means that the channel's values are not scaled.
+	ScaleNone ScaleType = ""
+	// ScaleLinear converts raw values using Slope*raw + Offset.
+	ScaleLinear ScaleType = "linear"
+	// ScalePolynomial converts raw values by evaluating a polynomial with the given
+	// Coefficients at each raw value.
+	ScalePolynomial ScaleType = "polynomial"
+)
+
+// Scale describes how to convert the raw values stored for a channel (e.g. ADC counts)
+// into engineering units (e.g. psi). Scales are metadata only: data is always
+// written and stored in its raw form, and is only converted when a reader asks for
+// scaled values.
+type Scale struct {
+	// Type is the scaling function to apply. A zero value disables scaling.
+	Type ScaleType `json:"type" msgpack:"type"`
+	// Slope is the multiplier for ScaleLinear.
+	Slope float64 `json:"slope" msgpack:"slope"`
+	// Offset is the value added after multiplying by Slope for ScaleLinear.
+	Offset float64 `json:"offset" msgpack:"offset"`
+	// Coefficients are the coefficients of the polynomial for ScalePolynomial,
+	// starting with the constant term.
+	Coefficients []float64 `json:"coefficients" msgpack:"coefficients"`
+	// RawDataType is the data type of the raw values. Defaults to, and must match, the
+	// data type of the channel.
+	RawDataType telem.DataType `json:"raw_data_type" msgpack:"raw_data_type"`
+	// EngineeringDataType is the data type of the scaled values. Defaults to
+	// telem.Float64T.
+	EngineeringDataType telem.DataType `json:"engineering_data_type" msgpack:"engineering_data_type"`
+}
+
+// Enabled returns true if the scale converts values.
+func (s Scale) Enabled() bool { return s.Type != ScaleNone }
+
+// withDefaults returns the scale with its data types defaulted for a channel of the
+// given data type.
+func (s Scale) withDefaults(dt telem.DataType) Scale {
+	if !s.Enabled() {
+		return s
+	}
+	if s.RawDataType == telem.UnknownT {
+		s.RawDataType = dt
+	}
+	if s.EngineeringDataType == telem.UnknownT {
+		s.EngineeringDataType = telem.Float64T
+	}
+	return s
+}
+
+// validate checks that the scale can be applied to the given channel.
+func (s Scale) validate(ch Channel) error {
+	if !s.Enabled() {
+		return nil
+	}
+	v := validate.New("channel.scale")
+	v.Ternaryf("type", !lo.Contains([]ScaleType{ScaleLinear, ScalePolynomial}, s.Type), "unknown scale type %s", s.Type)
+	v.Ternaryf("type", ch.IsIndex, "index channel %v cannot be scaled", ch)
+	v.Ternary("slope", s.Type == ScaleLinear && s.Slope == 0, "must be non-zero")
+	v.Ternary("coefficients", s.Type == ScalePolynomial && len(s.Coefficients) == 0, "must be provided")
+	v.Ternaryf("raw_data_type", s.RawDataType != ch.DataType, "must match the channel data type %s", ch.DataType)
+	v.Ternaryf("raw_data_type", !s.RawDataType.IsNumeric(), "%s is not numeric", s.RawDataType)
+	v.Ternaryf("engineering_data_type", !s.EngineeringDataType.IsNumeric(), "%s is not numeric", s.EngineeringDataType)
+	return v.Error()
+}
+
+// Apply converts a series of raw values into engineering units. Series that are not
+// of the scale's raw data type are returned unchanged.
+func (s Scale) Apply(series telem.Series) telem.Series {
+	if !s.Enabled() || series.DataType != s.RawDataType {
+		return series
+	}
+	var (
+		engDensity = int(s.EngineeringDataType.Density())
+		n          = int(series.Len())
+		out        = telem.Series{
+			DataType:  s.EngineeringDataType,
+			TimeRange: series.TimeRange,
+			Alignment: series.Alignment,
+			Data:      make([]byte, n*engDensity),
+		}
+	)
+	for i := 0; i < n; i++ {
+		raw := series.Float64At(int64(i))
+		encode(s.EngineeringDataType, out.Data[i*engDensity:(i+1)*engDensity], s.eval(raw))
+	}
+	return out
+}
+
+func (s Scale) eval(raw float64) float64 {
+	if s.Type == ScaleLinear {
+		return s.Slope*raw + s.Offset
+	}
+	var v float64
+	for i := len(s.Coefficients) - 1; i >= 0; i-- {
+		v = v*raw + s.Coefficients[i]
+	}
+	return v
+}
+
+// encode writes v into b as a sample of the given data type, rounding and clamping
+// to the representable range for integer data types.
+func encode(dt telem.DataType, b []byte, v float64) {
+	switch dt {
+	case telem.Float64T:
+		telem.MarshalF[float64](dt)(b, v)
+	case telem.Float32T:
+		telem.MarshalF[float32](dt)(b, float32(v))
+	case telem.Int64T:
+		telem.MarshalF[int64](dt)(b, int64(clamp(v, math.MinInt64, math.MaxInt64)))
+	case telem.Int32T:
+		telem.MarshalF[int64](dt)(b, int64(clamp(v, math.MinInt32, math.MaxInt32)))
+	case telem.Int16T:
+		telem.MarshalF[int64](dt)(b, int64(clamp(v, math.MinInt16, math.MaxInt16)))
+	case telem.Int8T:
+		telem.MarshalF[int64](dt)(b, int64(clamp(v, math.MinInt8, math.MaxInt8)))
+	case telem.Uint64T:
+		telem.MarshalF[uint64](dt)(b, uint64(clamp(v, 0, math.MaxUint64)))
+	case telem.Uint32T:
+		telem.MarshalF[uint64](dt)(b, uint64(clamp(v, 0, math.MaxUint32)))
+	case telem.Uint16T:
+		telem.MarshalF[uint64](dt)(b, uint64(clamp(v, 0, math.MaxUint16)))
+	default:
+		telem.MarshalF[uint64](dt)(b, uint64(clamp(v, 0, math.MaxUint8)))
+	}
+}
+
+func clamp(v, lower, upper float64) float64 {
+	return math.Max(lower, math.Min(upper, math.Round(v)))
+}
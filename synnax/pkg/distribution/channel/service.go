@@ -27,6 +27,7 @@ import (
 type Service interface {
 	Readable
 	Writeable
+	Alterer
 	ontology.Service
 	Group() group.Group
 }
@@ -0,0 +1,142 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package channel
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// trash moves the channels with the given keys to the trash. Trashing a channel only
+// changes its entry in the cluster DB, so it is executed on this node regardless of the
+// channels' leaseholders. Channels that are already in the trash keep the time they
+// were originally deleted at.
+func (lp *leaseProxy) trash(ctx context.Context, tx gorp.Tx, keys Keys, allowInternal bool) error {
+	var channels []Channel
+	if err := gorp.NewRetrieve[Key, Channel]().
+		WhereKeys(keys...).
+		Entries(&channels).
+		Exec(ctx, tx); err != nil && !errors.Is(err, query.NotFound) {
+		return err
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+	if !allowInternal {
+		internal := lo.Filter(channels, func(ch Channel, _ int) bool { return ch.Internal })
+		if len(internal) > 0 {
+			return errors.Newf("can't delete internal channel(s): %v", Names(internal))
+		}
+	}
+	if err := lp.validateTrashIndexes(ctx, tx, channels, keys); err != nil {
+		return err
+	}
+	now := telem.Now()
+	return gorp.NewUpdate[Key, Channel]().
+		WhereKeys(KeysFromChannels(channels)...).
+		Change(func(c Channel) Channel {
+			if !c.Trashed() {
+				c.DeletedAt = now
+			}
+			return c
+		}).
+		Exec(ctx, tx)
+}
+
+// validateTrashIndexes checks that none of the given channels are indexes of channels
+// that will remain outside the trash.
+func (lp *leaseProxy) validateTrashIndexes(
+	ctx context.Context,
+	tx gorp.Tx,
+	channels []Channel,
+	keys Keys,
+) error {
+	indexes := lo.Filter(channels, func(ch Channel, _ int) bool { return ch.IsIndex })
+	if len(indexes) == 0 {
+		return nil
+	}
+	indexKeys := KeysFromChannels(indexes)
+	var dependents []Channel
+	if err := gorp.NewRetrieve[Key, Channel]().
+		Where(func(c *Channel) bool {
+			return !c.IsIndex &&
+				!c.Trashed() &&
+				c.LocalIndex != 0 &&
+				indexKeys.Contains(c.Index()) &&
+				!keys.Contains(c.Key())
+		}).
+		Entries(&dependents).
+		Exec(ctx, tx); err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return errors.Wrapf(
+			validate.Error,
+			"can't delete index channel(s) with dependent channels: %v",
+			Names(dependents),
+		)
+	}
+	return nil
+}
+
+func (lp *leaseProxy) trashByName(ctx context.Context, tx gorp.Tx, names []string, allowInternal bool) error {
+	var res []Channel
+	if err := gorp.NewRetrieve[Key, Channel]().Entries(&res).Where(func(c *Channel) bool {
+		return !c.Trashed() && lo.Contains(names, c.Name)
+	}).Exec(ctx, tx); err != nil {
+		return err
+	}
+	return lp.trash(ctx, tx, KeysFromChannels(res), allowInternal)
+}
+
+// restore moves the channels with the given keys out of the trash. A channel can only
+// be restored if its index is not in the trash, or is restored along with it.
+func (lp *leaseProxy) restore(ctx context.Context, tx gorp.Tx, keys Keys) error {
+	var channels []Channel
+	if err := gorp.NewRetrieve[Key, Channel]().
+		WhereKeys(keys...).
+		Entries(&channels).
+		Exec(ctx, tx); err != nil {
+		return err
+	}
+	indexKeys := lo.Uniq(lo.FilterMap(channels, func(ch Channel, _ int) (Key, bool) {
+		return ch.Index(), !ch.IsIndex && ch.LocalIndex != 0 && !keys.Contains(ch.Index())
+	}))
+	if len(indexKeys) > 0 {
+		var trashedIndexes []Channel
+		if err := gorp.NewRetrieve[Key, Channel]().
+			WhereKeys(indexKeys...).
+			Where(func(c *Channel) bool { return c.Trashed() }).
+			Entries(&trashedIndexes).
+			Exec(ctx, tx); err != nil && !errors.Is(err, query.NotFound) {
+			return err
+		}
+		if len(trashedIndexes) > 0 {
+			return errors.Wrapf(
+				validate.Error,
+				"can't restore channel(s) whose index is deleted. Restore the index channel(s) %v as well",
+				Names(trashedIndexes),
+			)
+		}
+	}
+	return gorp.NewUpdate[Key, Channel]().
+		WhereKeys(keys...).
+		Change(func(c Channel) Channel {
+			c.DeletedAt = 0
+			return c
+		}).
+		Exec(ctx, tx)
+}
@@ -21,12 +21,29 @@ type Writer interface {
 	CreateIfNameDoesntExist(ctx context.Context, c *Channel) error
 	CreateManyIfNamesDontExist(ctx context.Context, channels *[]Channel) error
 	CreateMany(ctx context.Context, channels *[]Channel) error
+	// Delete moves the channel with the given key to the trash. The channel's data is
+	// kept until the channel is purged, and the channel can be restored until then.
 	Delete(ctx context.Context, key Key, allowInternal bool) error
+	// DeleteMany moves the channels with the given keys to the trash.
 	DeleteMany(ctx context.Context, keys []Key, allowInternal bool) error
+	// DeleteByName moves the channels with the given name to the trash.
 	DeleteByName(ctx context.Context, name string, allowInternal bool) error
+	// DeleteManyByNames moves the channels with the given names to the trash.
 	DeleteManyByNames(ctx context.Context, names []string, allowInternal bool) error
+	// Restore moves the channel with the given key out of the trash.
+	Restore(ctx context.Context, key Key) error
+	// RestoreMany moves the channels with the given keys out of the trash.
+	RestoreMany(ctx context.Context, keys []Key) error
+	// Purge permanently deletes the channel with the given key along with all of its
+	// data, regardless of whether it is in the trash.
+	Purge(ctx context.Context, key Key, allowInternal bool) error
+	// PurgeMany permanently deletes the channels with the given keys along with all of
+	// their data.
+	PurgeMany(ctx context.Context, keys []Key, allowInternal bool) error
 	Rename(ctx context.Context, key Key, newName string, allowInternal bool) error
 	RenameMany(ctx context.Context, keys []Key, newNames []string, allowInternal bool) error
+	UpdateMetadata(ctx context.Context, key Key, metadata Metadata, allowInternal bool) error
+	UpdateManyMetadata(ctx context.Context, keys []Key, metadata []Metadata, allowInternal bool) error
 }
 
 type writer struct {
@@ -63,7 +80,7 @@ func (w writer) Delete(ctx context.Context, key Key, allowInternal bool) error {
 }
 
 func (w writer) DeleteMany(ctx context.Context, keys []Key, allowInternal bool) error {
-	return w.proxy.delete(ctx, w.tx, keys, allowInternal)
+	return w.proxy.trash(ctx, w.tx, keys, allowInternal)
 }
 
 func (w writer) DeleteByName(ctx context.Context, name string, allowInternal bool) error {
@@ -71,7 +88,23 @@ func (w writer) DeleteByName(ctx context.Context, name string, allowInternal boo
 }
 
 func (w writer) DeleteManyByNames(ctx context.Context, names []string, allowInternal bool) error {
-	return w.proxy.deleteByName(ctx, w.tx, names, allowInternal)
+	return w.proxy.trashByName(ctx, w.tx, names, allowInternal)
+}
+
+func (w writer) Restore(ctx context.Context, key Key) error {
+	return w.RestoreMany(ctx, []Key{key})
+}
+
+func (w writer) RestoreMany(ctx context.Context, keys []Key) error {
+	return w.proxy.restore(ctx, w.tx, keys)
+}
+
+func (w writer) Purge(ctx context.Context, key Key, allowInternal bool) error {
+	return w.PurgeMany(ctx, []Key{key}, allowInternal)
+}
+
+func (w writer) PurgeMany(ctx context.Context, keys []Key, allowInternal bool) error {
+	return w.proxy.delete(ctx, w.tx, keys, allowInternal)
 }
 
 func (w writer) Rename(
@@ -92,8 +125,28 @@ func (w writer) RenameMany(
 	return w.proxy.rename(ctx, w.tx, keys, newNames, allowInternal)
 }
 
+func (w writer) UpdateMetadata(
+	ctx context.Context,
+	key Key,
+	metadata Metadata,
+	allowInternal bool,
+) error {
+	return w.UpdateManyMetadata(ctx, []Key{key}, []Metadata{metadata}, allowInternal)
+}
+
+func (w writer) UpdateManyMetadata(
+	ctx context.Context,
+	keys []Key,
+	metadata []Metadata,
+	allowInternal bool,
+) error {
+	return w.proxy.updateMetadata(ctx, w.tx, keys, metadata, allowInternal)
+}
+
 func applyAdjustments(c Channel) Channel {
 	c.Name = strings.TrimSpace(c.Name)
+	c.Unit = strings.TrimSpace(c.Unit)
+	c.Scale = c.Scale.withDefaults(c.DataType)
 	return c
 }
 
@@ -0,0 +1,55 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package core
+
+import (
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// Scaler converts the series of channels that have a channel.Scale from raw values
+// into engineering units.
+type Scaler map[channel.Key]channel.Scale
+
+// NewScaler returns a Scaler for the channels that have a scale enabled.
+func NewScaler(channels []channel.Channel) Scaler {
+	s := make(Scaler, len(channels))
+	for _, ch := range channels {
+		if ch.Scale.Enabled() {
+			s[ch.Key()] = ch.Scale
+		}
+	}
+	return s
+}
+
+// Scale returns a copy of the frame with the series of every scaled channel converted
+// into engineering units. The frame is returned as is if none of its channels are
+// scaled.
+func (s Scaler) Scale(fr Frame) Frame {
+	if len(s) == 0 {
+		return fr
+	}
+	var out []telem.Series
+	for i, key := range fr.Keys {
+		scale, ok := s[key]
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]telem.Series, len(fr.Series))
+			copy(out, fr.Series)
+		}
+		out[i] = scale.Apply(fr.Series[i])
+	}
+	if out == nil {
+		return fr
+	}
+	return Frame{Keys: fr.Keys, Series: out}
+}
@@ -29,6 +29,7 @@ type (
 	StreamWriter     = writer.StreamWriter
 	WriterConfig     = writer.Config
 	IteratorConfig   = iterator.Config
+	SearchConfig     = iterator.SearchConfig
 	StreamerResponse = relay.Response
 	Deleter          = deleter.Deleter
 )
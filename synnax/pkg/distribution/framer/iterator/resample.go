@@ -0,0 +1,590 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package iterator
+
+import (
+	"context"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/core"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Interpolation is the method used to compute the value of a channel at a timestamp
+// that falls between two of its samples.
+type Interpolation string
+
+const (
+	// InterpolatePrevious uses the value of the last sample at or before the timestamp.
+	// This is the default.
+	InterpolatePrevious Interpolation = "previous"
+	// InterpolateLinear linearly interpolates between the samples before and after the
+	// timestamp. Only supported for numeric channels.
+	InterpolateLinear Interpolation = "linear"
+	// InterpolateNearest uses the value of the sample closest in time to the timestamp,
+	// preferring the earlier sample on ties.
+	InterpolateNearest Interpolation = "nearest"
+)
+
+// Resample configures an iterator to return frames where every channel is resampled
+// onto a single set of timestamps, allowing channels with different indexes to be read
+// as a single table. Exactly one of Index or Rate must be set.
+//
+// Resampled frames contain one series per channel, all with the same length. When
+// resampling onto an index, the frame also contains a series for the index channel
+// holding the target timestamps. When resampling at a fixed rate, the target
+// timestamps are the multiples of the rate's period within the data, and the
+// timestamp of each sample can be computed from the start of its series' time range.
+//
+// A timestamp is only emitted once every channel either has a sample at or after it,
+// has no more data to read, or has no data read yet. When using InterpolatePrevious,
+// a channel that the iterator has read past the timestamp is also resolved, as its
+// value doesn't depend on later samples. A call to Next may therefore return fewer
+// timestamps than its span covers, with the remainder returned by later calls. Once
+// the iterator is exhausted, the remaining timestamps are flushed. Timestamps before
+// the first sample of a channel are dropped, except when using InterpolateNearest
+// with later samples of the channel already read, and timestamps after the last
+// sample of a channel hold its last value. Interpolation state is carried across consecutive calls to
+// Next, so resampled frames are seamless when iterating forwards. Calls to Prev
+// resample each span in isolation.
+type Resample struct {
+	// Index is the key of the index channel whose timestamps frames are resampled
+	// onto.
+	Index channel.Key `json:"index" msgpack:"index"`
+	// Rate is the fixed rate at which to resample frames.
+	Rate telem.Rate `json:"rate" msgpack:"rate"`
+	// Interpolation is the method used to compute channel values at the target
+	// timestamps. Defaults to InterpolatePrevious.
+	Interpolation Interpolation `json:"interpolation" msgpack:"interpolation"`
+}
+
+// Enabled returns true if the iterator should resample frames.
+func (r Resample) Enabled() bool { return r.Index != 0 || r.Rate != 0 }
+
+// Validate checks that exactly one resampling target is set and that the
+// interpolation method is known.
+func (r Resample) Validate() error {
+	v := validate.New("distribution.framer.iterator.resample")
+	v.Ternary("index", r.Index != 0 && r.Rate != 0, "cannot resample onto both an index and a rate")
+	validate.NonNegative(v, "rate", r.Rate)
+	v.Ternaryf(
+		"interpolation",
+		!lo.Contains([]Interpolation{"", InterpolatePrevious, InterpolateLinear, InterpolateNearest}, r.Interpolation),
+		"unknown interpolation %s",
+		r.Interpolation,
+	)
+	return v.Error()
+}
+
+// newResampler validates the resampling configuration against the channels being
+// read, and returns a resampler along with the full set of keys that must be read
+// to resample them, including the index channel of every channel.
+func (s *Service) newResampler(
+	ctx context.Context,
+	cfg Config,
+) (*resampler, channel.Keys, error) {
+	if err := cfg.Resample.Validate(); err != nil {
+		return nil, nil, err
+	}
+	keys := cfg.Keys
+	if cfg.Resample.Index != 0 {
+		keys = append(keys, cfg.Resample.Index).Unique()
+	}
+	var channels []channel.Channel
+	if err := s.ChannelReader.NewRetrieve().
+		WhereKeys(keys...).
+		Entries(&channels).
+		Exec(ctx, nil); err != nil {
+		return nil, nil, err
+	}
+	r := &resampler{
+		Resample: cfg.Resample,
+		sources:  make(map[channel.Key]*source),
+		cursor:   cursor{bounds: cfg.Bounds},
+		bounds:   cfg.Bounds,
+	}
+	if r.Interpolation == "" {
+		r.Interpolation = InterpolatePrevious
+	}
+	read := make(channel.Keys, 0, len(keys)*2)
+	for _, ch := range channels {
+		read = append(read, ch.Key())
+		if ch.Key() == r.Index {
+			if !ch.IsIndex {
+				return nil, nil, errors.Wrapf(validate.Error, "cannot resample onto %v because it is not an index channel", ch)
+			}
+			continue
+		}
+		if ch.Index() == 0 && ch.Rate == 0 {
+			return nil, nil, errors.Wrapf(validate.Error, "cannot resample %v because it has no index or rate", ch)
+		}
+		if r.Interpolation == InterpolateLinear && !ch.DataType.IsNumeric() && ch.DataType != telem.TimeStampT {
+			return nil, nil, errors.Wrapf(validate.Error, "cannot linearly interpolate non-numeric channel %v", ch)
+		}
+		if ch.Index() != 0 {
+			read = append(read, ch.Index())
+		}
+		// Scaled series reach the resampler in engineering units.
+		if cfg.Scaled && ch.Scale.Enabled() {
+			ch.DataType = ch.Scale.EngineeringDataType
+		}
+		r.channels = append(r.channels, ch)
+	}
+	// Output channels in the order they were requested.
+	order := make(map[channel.Key]int, len(cfg.Keys))
+	for i, k := range cfg.Keys {
+		order[k] = i
+	}
+	sort.SliceStable(r.channels, func(i, j int) bool {
+		return order[r.channels[i].Key()] < order[r.channels[j].Key()]
+	})
+	r.reset()
+	return r, read.Unique(), nil
+}
+
+// source holds the samples of a channel that are still needed to compute values at
+// pending target timestamps.
+type source struct {
+	stamps []telem.TimeStamp
+	values [][]byte
+	// read is a lower bound on the timestamp before which all data of the channel has
+	// been read. Every call to Next moves the view of the iterator forwards by its span
+	// for every channel, and the time range of each series read lies within the view.
+	read telem.TimeStamp
+	// exhausted is true once the channel has no more data to read, either because a
+	// call to Next with AutoSpan returned no data for it, or because it has been read
+	// up to the end of the iterator's bounds.
+	exhausted bool
+}
+
+// step is a request sent to the iterator, along with the bounds of the iterator when
+// it was sent.
+type step struct {
+	span   telem.TimeSpan
+	bounds telem.TimeRange
+}
+
+// cursor records the requests sent to the iterator so that the resampler knows the
+// span read by each call to Next. Every request is acknowledged exactly once and in
+// order, so the resampler pops the step of each request as its acknowledgement
+// arrives.
+type cursor struct {
+	bounds telem.TimeRange
+	mu     sync.Mutex
+	steps  []step
+}
+
+// track records the given request before it is sent to the iterator.
+func (c *cursor) track(_ context.Context, req Request) (Request, bool, error) {
+	if req.Command == SetBounds {
+		c.bounds = req.Bounds
+	}
+	c.mu.Lock()
+	c.steps = append(c.steps, step{span: req.Span, bounds: c.bounds})
+	c.mu.Unlock()
+	return req, true, nil
+}
+
+// pop returns the oldest request that has not yet been acknowledged.
+func (c *cursor) pop() (step, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.steps) == 0 {
+		return step{}, false
+	}
+	st := c.steps[0]
+	c.steps = c.steps[1:]
+	return st, true
+}
+
+// newCursor returns a segment that records the requests passing through it.
+func (r *resampler) newCursor() confluence.Segment[Request, Request] {
+	t := &confluence.LinearTransform[Request, Request]{}
+	t.Transform = r.cursor.track
+	return t
+}
+
+// resampler is a segment that sits after the synchronizer, collecting the data
+// returned by each iterator command and replacing it with a single resampled frame.
+type resampler struct {
+	Resample
+	confluence.AbstractLinear[Response, Response]
+	channels []channel.Channel
+	frames   []core.Frame
+	sources  map[channel.Key]*source
+	cursor   cursor
+	// bounds are the bounds of the iterator.
+	bounds telem.TimeRange
+	// targets are the timestamps that have not yet been emitted because some channel
+	// does not yet have enough data to compute a value at them.
+	targets []telem.TimeStamp
+	// next is the next timestamp to generate when resampling at a fixed rate.
+	next telem.TimeStamp
+	// emitted is the number of samples emitted since the last reset, used to align
+	// the series in resampled frames.
+	emitted uint32
+}
+
+// Flow implements confluence.Flow.
+func (r *resampler) Flow(ctx signal.Context, opts ...confluence.Option) {
+	o := confluence.NewOptions(opts)
+	o.AttachClosables(r.Out)
+	r.GoRange(ctx, r.process, o.Signal...)
+}
+
+func (r *resampler) process(ctx context.Context, res Response) error {
+	if res.Variant == DataResponse {
+		r.frames = append(r.frames, res.Frame)
+		return nil
+	}
+	frames := r.frames
+	r.frames = nil
+	st, ok := r.cursor.pop()
+	if ok {
+		r.bounds = st.bounds
+	}
+	var out core.Frame
+	switch res.Command {
+	case Next:
+		if err := r.ingest(frames, st.span); err != nil {
+			return err
+		}
+		// If the iterator is exhausted, there will be no more data to resolve pending
+		// timestamps with, so flush them.
+		out = r.emit(!res.Ack)
+		if !res.Ack && len(out.Keys) > 0 {
+			res.Ack = true
+		}
+	case Prev:
+		r.reset()
+		if err := r.ingest(frames, 0); err != nil {
+			return err
+		}
+		out = r.emit(true)
+		r.reset()
+	case Valid, Error:
+	default:
+		r.reset()
+	}
+	if len(out.Keys) > 0 {
+		if err := signal.SendUnderContext(ctx, r.Out.Inlet(), Response{
+			Variant: DataResponse,
+			Command: res.Command,
+			SeqNum:  res.SeqNum,
+			Frame:   out,
+		}); err != nil {
+			return err
+		}
+	}
+	return signal.SendUnderContext(ctx, r.Out.Inlet(), res)
+}
+
+func (r *resampler) reset() {
+	for _, ch := range r.channels {
+		r.sources[ch.Key()] = &source{read: r.bounds.Start}
+	}
+	r.targets = nil
+	r.next = 0
+	r.emitted = 0
+}
+
+// ingest adds the samples in the given frames, read by a call to Next or Prev with the
+// given span, to the resampler's sources, and extends the pending target timestamps.
+func (r *resampler) ingest(frames []core.Frame, span telem.TimeSpan) error {
+	fr := core.MergeFrames(frames)
+	if r.Index != 0 {
+		for _, s := range fr.Get(r.Index) {
+			r.targets = append(r.targets, telem.Unmarshal[telem.TimeStamp](s)...)
+		}
+	}
+	first, last := telem.TimeStampMax, telem.TimeStampMin
+	for _, ch := range r.channels {
+		src := r.sources[ch.Key()]
+		series := fr.Get(ch.Key())
+		if span == AutoSpan && len(series) == 0 {
+			src.exhausted = true
+		} else if span > 0 {
+			src.read = r.advance(src.read, span)
+		}
+		for _, s := range series {
+			src.read = max(src.read, s.TimeRange.End)
+			stamps, err := timestamps(fr, ch, s)
+			if err != nil {
+				return err
+			}
+			src.stamps = append(src.stamps, stamps...)
+			src.values = append(src.values, s.Split()...)
+		}
+		if src.read >= r.bounds.End {
+			src.exhausted = true
+		}
+		if len(src.stamps) > 0 {
+			first = min(first, src.stamps[0])
+			last = max(last, src.stamps[len(src.stamps)-1])
+		}
+	}
+	if r.Rate == 0 || first > last {
+		return nil
+	}
+	if r.next == 0 {
+		r.next = r.Rate.ClosestGE(first)
+	}
+	for ; r.next <= last; r.next = r.next.Add(r.Rate.Period()) {
+		r.targets = append(r.targets, r.next)
+	}
+	return nil
+}
+
+// advance moves the read position of a channel forwards by span, stopping at the end
+// of the iterator's bounds.
+func (r *resampler) advance(read telem.TimeStamp, span telem.TimeSpan) telem.TimeStamp {
+	if read >= r.bounds.End || telem.TimeSpan(r.bounds.End-read) <= span {
+		return r.bounds.End
+	}
+	return read.Add(span)
+}
+
+// timestamps returns the timestamp of each sample in the series s of channel ch.
+func timestamps(fr core.Frame, ch channel.Channel, s telem.Series) ([]telem.TimeStamp, error) {
+	if ch.IsIndex {
+		return telem.Unmarshal[telem.TimeStamp](s), nil
+	}
+	n := s.Len()
+	stamps := make([]telem.TimeStamp, n)
+	if ch.Index() == 0 {
+		for i := range stamps {
+			stamps[i] = s.TimeRange.Start.Add(ch.Rate.Span(i))
+		}
+		return stamps, nil
+	}
+	var (
+		indexes = fr.Get(ch.Index())
+		idx     telem.Series
+		found   bool
+	)
+	contains := func(idx telem.Series, align telem.AlignmentPair) bool {
+		return idx.Alignment.DomainIndex() == align.DomainIndex() &&
+			idx.Alignment.SampleIndex() <= align.SampleIndex() &&
+			int64(align.SampleIndex()-idx.Alignment.SampleIndex()) < idx.Len()
+	}
+	for i := int64(0); i < n; i++ {
+		align := s.Alignment.AddSamples(uint32(i))
+		if !found || !contains(idx, align) {
+			if idx, found = lo.Find(indexes, func(idx telem.Series) bool {
+				return contains(idx, align)
+			}); !found {
+				return nil, errors.Newf("index data for sample %v of channel %v not found", i, ch)
+			}
+		}
+		stamps[i] = telem.ValueAt[telem.TimeStamp](idx, int64(align.SampleIndex()-idx.Alignment.SampleIndex()))
+	}
+	return stamps, nil
+}
+
+// emit computes the values of every channel at the pending target timestamps,
+// returning them as a frame. If final is false, emission stops at the first
+// timestamp that is not yet resolved, as its value may depend on data that has not
+// been read yet.
+func (r *resampler) emit(final bool) core.Frame {
+	var (
+		stamps []telem.TimeStamp
+		series = make([]telem.Series, len(r.channels))
+		n      int
+	)
+	for j, ch := range r.channels {
+		series[j].DataType = ch.DataType
+	}
+	for ; n < len(r.targets); n++ {
+		t := r.targets[n]
+		if !final && !r.resolved(t) {
+			break
+		}
+		values := make([][]byte, len(r.channels))
+		ok := true
+		for j, ch := range r.channels {
+			if values[j], ok = r.value(ch, t); !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		stamps = append(stamps, t)
+		for j, v := range values {
+			series[j].Data = append(series[j].Data, v...)
+			if series[j].DataType.IsVariable() {
+				series[j].Data = append(series[j].Data, '\n')
+			}
+		}
+	}
+	r.targets = r.targets[n:]
+	r.prune()
+	if len(stamps) == 0 {
+		return core.Frame{}
+	}
+	var (
+		fr        core.Frame
+		tr        = stamps[0].Range(stamps[len(stamps)-1] + 1)
+		alignment = telem.NewAlignmentPair(0, r.emitted)
+	)
+	r.emitted += uint32(len(stamps))
+	if r.Index != 0 {
+		idx := telem.NewSeries(stamps)
+		idx.TimeRange, idx.Alignment = tr, alignment
+		fr.Keys = append(fr.Keys, r.Index)
+		fr.Series = append(fr.Series, idx)
+	}
+	for j, ch := range r.channels {
+		series[j].TimeRange, series[j].Alignment = tr, alignment
+		fr.Keys = append(fr.Keys, ch.Key())
+		fr.Series = append(fr.Series, series[j])
+	}
+	return fr
+}
+
+// resolved returns true if no data read in the future can change the value of any
+// channel at t. This is the case for a channel if it has a sample at or after t, if
+// it has no data or no more data to read, or if it has been read past t when using
+// InterpolatePrevious, whose values don't depend on later samples.
+func (r *resampler) resolved(t telem.TimeStamp) bool {
+	for _, src := range r.sources {
+		if src.exhausted || len(src.stamps) == 0 || src.stamps[len(src.stamps)-1] >= t {
+			continue
+		}
+		if r.Interpolation != InterpolatePrevious || src.read <= t {
+			return false
+		}
+	}
+	return true
+}
+
+// value computes the value of the channel at t, returning false if the channel has
+// no value at t.
+func (r *resampler) value(ch channel.Channel, t telem.TimeStamp) ([]byte, bool) {
+	src := r.sources[ch.Key()]
+	next := sort.Search(len(src.stamps), func(i int) bool { return src.stamps[i] > t })
+	prev := next - 1
+	hasPrev, hasNext := prev >= 0, next < len(src.stamps)
+	switch {
+	case hasPrev && (!hasNext || src.stamps[prev] == t):
+		return src.values[prev], true
+	case !hasPrev && (!hasNext || r.Interpolation != InterpolateNearest):
+		return nil, false
+	case !hasPrev:
+		return src.values[next], true
+	}
+	switch r.Interpolation {
+	case InterpolateNearest:
+		if t-src.stamps[prev] <= src.stamps[next]-t {
+			return src.values[prev], true
+		}
+		return src.values[next], true
+	case InterpolateLinear:
+		var (
+			num = uint64(t - src.stamps[prev])
+			den = uint64(src.stamps[next] - src.stamps[prev])
+		)
+		switch ch.DataType {
+		case telem.Int64T, telem.TimeStampT:
+			unmarshal := telem.UnmarshalF[int64](ch.DataType)
+			b := make([]byte, ch.DataType.Density())
+			v := lerpInt64(unmarshal(src.values[prev]), unmarshal(src.values[next]), num, den)
+			telem.MarshalF[int64](ch.DataType)(b, v)
+			return b, true
+		case telem.Uint64T:
+			unmarshal := telem.UnmarshalF[uint64](ch.DataType)
+			b := make([]byte, ch.DataType.Density())
+			v := lerpUint64(unmarshal(src.values[prev]), unmarshal(src.values[next]), num, den)
+			telem.MarshalF[uint64](ch.DataType)(b, v)
+			return b, true
+		}
+		var (
+			v0   = telem.UnmarshalF[float64](ch.DataType)(src.values[prev])
+			v1   = telem.UnmarshalF[float64](ch.DataType)(src.values[next])
+			frac = float64(t-src.stamps[prev]) / float64(src.stamps[next]-src.stamps[prev])
+		)
+		return encode(ch.DataType, v0+(v1-v0)*frac), true
+	default:
+		return src.values[prev], true
+	}
+}
+
+// prune discards samples that can no longer affect the value of any pending or
+// future target timestamp, retaining the last sample at or before the earliest such
+// timestamp.
+func (r *resampler) prune() {
+	for _, src := range r.sources {
+		if len(src.stamps) == 0 {
+			continue
+		}
+		keep := len(src.stamps) - 1
+		if len(r.targets) > 0 {
+			keep = sort.Search(len(src.stamps), func(i int) bool { return src.stamps[i] > r.targets[0] }) - 1
+		}
+		if keep <= 0 {
+			continue
+		}
+		src.stamps = src.stamps[keep:]
+		src.values = src.values[keep:]
+	}
+}
+
+// lerpUint64 linearly interpolates between v0 and v1 at the fraction num/den, where
+// num <= den, rounding to the nearest integer. The interpolation uses exact integer
+// arithmetic, as 64-bit values such as timestamps cannot be represented exactly as
+// float64.
+func lerpUint64(v0, v1, num, den uint64) uint64 {
+	delta := v1 - v0
+	if v1 < v0 {
+		delta = v0 - v1
+	}
+	hi, lo := bits.Mul64(delta, num)
+	q, rem := bits.Div64(hi, lo, den)
+	if rem >= den-rem {
+		q++
+	}
+	if v1 < v0 {
+		return v0 - q
+	}
+	return v0 + q
+}
+
+// lerpInt64 is lerpUint64 for signed integers. Flipping the sign bit maps int64 onto
+// uint64 while preserving order.
+func lerpInt64(v0, v1 int64, num, den uint64) int64 {
+	const signBit = 1 << 63
+	return int64(lerpUint64(uint64(v0)^signBit, uint64(v1)^signBit, num, den) ^ signBit)
+}
+
+// encode encodes a float64 as a single sample of a numeric data type, rounding to
+// the nearest integer for integer data types.
+func encode(dt telem.DataType, v float64) []byte {
+	b := make([]byte, dt.Density())
+	switch dt {
+	case telem.Float64T:
+		telem.MarshalF[float64](dt)(b, v)
+	case telem.Float32T:
+		telem.MarshalF[float32](dt)(b, float32(v))
+	case telem.Int64T, telem.TimeStampT, telem.Int32T, telem.Int16T, telem.Int8T:
+		telem.MarshalF[int64](dt)(b, int64(math.Round(v)))
+	default:
+		telem.MarshalF[uint64](dt)(b, uint64(math.Round(v)))
+	}
+	return b
+}
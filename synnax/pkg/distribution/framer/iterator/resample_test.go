@@ -0,0 +1,215 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package iterator_test
+
+import (
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/writer"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Resample", Ordered, func() {
+	var (
+		closer                   io.Closer
+		svc                      serviceContainer
+		idxA, dataA, idxB, dataB channel.Channel
+		ids, stampsB             channel.Channel
+		// stamp is far enough from zero that it cannot be represented exactly as a
+		// float64.
+		stamp = telem.TimeStamp(1_700_000_000_000_000_001)
+	)
+	write := func(start telem.TimeStamp, keys channel.Keys, series ...telem.Series) {
+		w := MustSucceed(svc.writer.New(ctx, writer.Config{Keys: keys, Start: start}))
+		Expect(w.Write(core.Frame{Keys: keys, Series: series})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+		Expect(w.Close()).To(Succeed())
+	}
+	BeforeAll(func() {
+		b, services := provision(1)
+		closer, svc = b, services[1]
+		idxA = channel.Channel{Name: "idx_a", DataType: telem.TimeStampT, IsIndex: true}
+		idxB = channel.Channel{Name: "idx_b", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &idxA)).To(Succeed())
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &idxB)).To(Succeed())
+		dataA = channel.Channel{Name: "data_a", DataType: telem.Float64T, LocalIndex: idxA.LocalKey}
+		dataB = channel.Channel{Name: "data_b", DataType: telem.Int64T, LocalIndex: idxB.LocalKey}
+		ids = channel.Channel{Name: "ids", DataType: telem.UUIDT, LocalIndex: idxB.LocalKey}
+		stampsB = channel.Channel{Name: "stamps_b", DataType: telem.TimeStampT, LocalIndex: idxB.LocalKey}
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &dataA)).To(Succeed())
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &dataB)).To(Succeed())
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &ids)).To(Succeed())
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &stampsB)).To(Succeed())
+		write(
+			telem.SecondTS,
+			channel.Keys{idxA.Key(), dataA.Key()},
+			telem.NewSecondsTSV(1, 2, 3, 4, 5),
+			telem.NewSeriesV[float64](10, 20, 30, 40, 50),
+		)
+		write(
+			1500*telem.MillisecondTS,
+			channel.Keys{idxB.Key(), dataB.Key(), ids.Key(), stampsB.Key()},
+			telem.NewSeriesV[telem.TimeStamp](1500*telem.MillisecondTS, 3500*telem.MillisecondTS),
+			telem.NewSeriesV[int64](100, 300),
+			telem.Series{DataType: telem.UUIDT, Data: make([]byte, 32)},
+			telem.NewSeriesV[telem.TimeStamp](stamp, stamp+2_000_000_002),
+		)
+	})
+	AfterAll(func() { Expect(closer.Close()).To(Succeed()) })
+
+	read := func(cfg iterator.Config, span telem.TimeSpan) core.Frame {
+		cfg.Bounds = telem.TimeRangeMax
+		iter := MustSucceed(svc.iter.New(ctx, cfg))
+		var frames []core.Frame
+		Expect(iter.SeekFirst()).To(BeTrue())
+		for iter.Next(span) {
+			frames = append(frames, iter.Value())
+		}
+		Expect(iter.Close()).To(Succeed())
+		return core.MergeFrames(frames)
+	}
+	values := func(fr core.Frame, key channel.Key) []byte {
+		var data []byte
+		for _, s := range fr.Get(key) {
+			data = append(data, s.Data...)
+		}
+		return data
+	}
+	stamps := func(secs ...float64) []telem.TimeStamp {
+		out := make([]telem.TimeStamp, len(secs))
+		for i, s := range secs {
+			out[i] = telem.TimeStamp(s * float64(telem.SecondTS))
+		}
+		return out
+	}
+
+	Describe("Index", func() {
+		DescribeTable("Interpolation", func(
+			interp iterator.Interpolation,
+			expectedStamps []telem.TimeStamp,
+			expectedA []float64,
+			expectedB []int64,
+		) {
+			fr := read(iterator.Config{
+				Keys:     channel.Keys{dataA.Key(), dataB.Key()},
+				Resample: iterator.Resample{Index: idxA.Key(), Interpolation: interp},
+			}, iterator.AutoSpan)
+			Expect(fr.Keys.Unique()).To(Equal(channel.Keys{idxA.Key(), dataA.Key(), dataB.Key()}))
+			Expect(values(fr, idxA.Key())).To(Equal(telem.NewSeries(expectedStamps).Data))
+			Expect(values(fr, dataA.Key())).To(Equal(telem.NewSeries(expectedA).Data))
+			Expect(values(fr, dataB.Key())).To(Equal(telem.NewSeries(expectedB).Data))
+		},
+			Entry("Previous", iterator.InterpolatePrevious, stamps(2, 3, 4, 5), []float64{20, 30, 40, 50}, []int64{100, 100, 300, 300}),
+			Entry("Linear", iterator.InterpolateLinear, stamps(2, 3, 4, 5), []float64{20, 30, 40, 50}, []int64{150, 250, 300, 300}),
+			Entry("Nearest", iterator.InterpolateNearest, stamps(1, 2, 3, 4, 5), []float64{10, 20, 30, 40, 50}, []int64{100, 100, 300, 300, 300}),
+		)
+		It("Should carry interpolation state across calls to Next", func() {
+			fr := read(iterator.Config{
+				Keys:     channel.Keys{dataA.Key(), dataB.Key()},
+				Resample: iterator.Resample{Index: idxA.Key(), Interpolation: iterator.InterpolateLinear},
+			}, 2*telem.Second)
+			Expect(values(fr, idxA.Key())).To(Equal(telem.NewSeries(stamps(2, 3, 4, 5)).Data))
+			Expect(values(fr, dataB.Key())).To(Equal(telem.NewSeriesV[int64](150, 250, 300, 300).Data))
+		})
+		It("Should linearly interpolate timestamps without losing precision", func() {
+			fr := read(iterator.Config{
+				Keys:     channel.Keys{stampsB.Key()},
+				Resample: iterator.Resample{Index: idxA.Key(), Interpolation: iterator.InterpolateLinear},
+			}, iterator.AutoSpan)
+			Expect(values(fr, stampsB.Key())).To(Equal(telem.NewSeriesV[telem.TimeStamp](
+				stamp+500_000_001,
+				stamp+1_500_000_002,
+				stamp+2_000_000_002,
+				stamp+2_000_000_002,
+			).Data))
+		})
+		It("Should emit timestamps that a sparse channel has been read past", func() {
+			iter := MustSucceed(svc.iter.New(ctx, iterator.Config{
+				Keys:     channel.Keys{dataB.Key()},
+				Bounds:   telem.TimeRangeMax,
+				Resample: iterator.Resample{Index: idxA.Key()},
+			}))
+			Expect(iter.SeekFirst()).To(BeTrue())
+			Expect(iter.Next(telem.Second)).To(BeTrue())
+			By("Emitting a timestamp before the next sample of the sparse channel is read")
+			Expect(iter.Next(telem.Second)).To(BeTrue())
+			Expect(values(iter.Value(), idxA.Key())).To(Equal(telem.NewSeries(stamps(2)).Data))
+			Expect(values(iter.Value(), dataB.Key())).To(Equal(telem.NewSeriesV[int64](100).Data))
+			Expect(iter.Close()).To(Succeed())
+		})
+		It("Should align the series in each resampled frame", func() {
+			fr := read(iterator.Config{
+				Keys:     channel.Keys{dataA.Key(), dataB.Key()},
+				Resample: iterator.Resample{Index: idxA.Key()},
+			}, iterator.AutoSpan)
+			Expect(fr.Series[0].Alignment).To(Equal(fr.Series[1].Alignment))
+			Expect(fr.Series[0].Alignment).To(Equal(fr.Series[2].Alignment))
+			Expect(fr.Series[0].TimeRange).To(Equal((2 * telem.SecondTS).Range(3*telem.SecondTS + 1)))
+			By("Continuing the alignment in the frame flushed after the last call to Next")
+			Expect(fr.Series).To(HaveLen(6))
+			Expect(fr.Series[3].Alignment).To(Equal(fr.Series[0].Alignment.AddSamples(2)))
+		})
+		It("Should resample a span in isolation when iterating backwards", func() {
+			iter := MustSucceed(svc.iter.New(ctx, iterator.Config{
+				Keys:     channel.Keys{dataB.Key()},
+				Bounds:   telem.TimeRangeMax,
+				Resample: iterator.Resample{Index: idxA.Key()},
+			}))
+			Expect(iter.SeekLast()).To(BeTrue())
+			Expect(iter.Prev(10 * telem.Second)).To(BeTrue())
+			Expect(values(iter.Value(), dataB.Key())).To(Equal(telem.NewSeriesV[int64](100, 100, 300, 300).Data))
+			Expect(iter.Close()).To(Succeed())
+		})
+	})
+
+	Describe("Rate", func() {
+		It("Should resample channels onto a fixed rate", func() {
+			fr := read(iterator.Config{
+				Keys:     channel.Keys{dataA.Key(), dataB.Key()},
+				Resample: iterator.Resample{Rate: 2 * telem.Hz},
+			}, iterator.AutoSpan)
+			Expect(fr.Keys.Unique()).To(Equal(channel.Keys{dataA.Key(), dataB.Key()}))
+			Expect(values(fr, dataA.Key())).To(Equal(telem.NewSeriesV[float64](10, 20, 20, 30, 30, 40, 40, 50).Data))
+			Expect(values(fr, dataB.Key())).To(Equal(telem.NewSeriesV[int64](100, 100, 100, 100, 300, 300, 300, 300).Data))
+			Expect(fr.Series[0].TimeRange.Start).To(Equal(1500 * telem.MillisecondTS))
+		})
+	})
+
+	Describe("Validation", func() {
+		It("Should not allow resampling onto both an index and a rate", func() {
+			Expect(svc.iter.New(ctx, iterator.Config{
+				Keys:     channel.Keys{dataA.Key()},
+				Bounds:   telem.TimeRangeMax,
+				Resample: iterator.Resample{Index: idxA.Key(), Rate: telem.Hz},
+			})).Error().To(MatchError(ContainSubstring("both an index and a rate")))
+		})
+		It("Should not allow resampling onto a non-index channel", func() {
+			Expect(svc.iter.New(ctx, iterator.Config{
+				Keys:     channel.Keys{dataA.Key()},
+				Bounds:   telem.TimeRangeMax,
+				Resample: iterator.Resample{Index: dataB.Key()},
+			})).Error().To(HaveOccurredAs(validate.Error))
+		})
+		It("Should not allow linear interpolation of non-numeric channels", func() {
+			Expect(svc.iter.New(ctx, iterator.Config{
+				Keys:     channel.Keys{ids.Key()},
+				Bounds:   telem.TimeRangeMax,
+				Resample: iterator.Resample{Index: idxA.Key(), Interpolation: iterator.InterpolateLinear},
+			})).Error().To(HaveOccurredAs(validate.Error))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package iterator_test
+
+import (
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/writer"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Scale", Ordered, func() {
+	var (
+		closer         io.Closer
+		svc            serviceContainer
+		idx, raw, poly channel.Channel
+	)
+	BeforeAll(func() {
+		b, services := provision(1)
+		closer, svc = b, services[1]
+		idx = channel.Channel{Name: "idx", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+		raw = channel.Channel{
+			Name:       "raw",
+			DataType:   telem.Int16T,
+			LocalIndex: idx.LocalKey,
+			Scale:      channel.Scale{Type: channel.ScaleLinear, Slope: 0.25, Offset: -1},
+		}
+		poly = channel.Channel{
+			Name:       "poly",
+			DataType:   telem.Float32T,
+			LocalIndex: idx.LocalKey,
+			Scale: channel.Scale{
+				Type:                channel.ScalePolynomial,
+				Coefficients:        []float64{0, 1, 1},
+				EngineeringDataType: telem.Int32T,
+			},
+		}
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &raw)).To(Succeed())
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &poly)).To(Succeed())
+		keys := channel.Keys{idx.Key(), raw.Key(), poly.Key()}
+		w := MustSucceed(svc.writer.New(ctx, writer.Config{Keys: keys, Start: telem.SecondTS}))
+		Expect(w.Write(core.Frame{Keys: keys, Series: []telem.Series{
+			telem.NewSecondsTSV(1, 2, 3),
+			telem.NewSeriesV[int16](4, 8, 12),
+			telem.NewSeriesV[float32](1, 2, 3),
+		}})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+		Expect(w.Close()).To(Succeed())
+	})
+	AfterAll(func() { Expect(closer.Close()).To(Succeed()) })
+
+	read := func(cfg iterator.Config) core.Frame {
+		cfg.Bounds = telem.TimeRangeMax
+		iter := MustSucceed(svc.iter.New(ctx, cfg))
+		var frames []core.Frame
+		Expect(iter.SeekFirst()).To(BeTrue())
+		for iter.Next(iterator.AutoSpan) {
+			frames = append(frames, iter.Value())
+		}
+		Expect(iter.Close()).To(Succeed())
+		return core.MergeFrames(frames)
+	}
+
+	It("Should convert the values of scaled channels into engineering units", func() {
+		fr := read(iterator.Config{Keys: channel.Keys{idx.Key(), raw.Key(), poly.Key()}, Scaled: true})
+		Expect(fr.Get(raw.Key())[0].DataType).To(Equal(telem.Float64T))
+		Expect(fr.Get(raw.Key())[0].Data).To(Equal(telem.NewSeriesV[float64](0, 1, 2).Data))
+		Expect(fr.Get(poly.Key())[0].Data).To(Equal(telem.NewSeriesV[int32](2, 6, 12).Data))
+		Expect(fr.Get(idx.Key())[0].Data).To(Equal(telem.NewSecondsTSV(1, 2, 3).Data))
+	})
+
+	It("Should return raw values when not configured to scale", func() {
+		fr := read(iterator.Config{Keys: channel.Keys{raw.Key()}})
+		Expect(fr.Get(raw.Key())[0].Data).To(Equal(telem.NewSeriesV[int16](4, 8, 12).Data))
+	})
+
+	It("Should resample scaled values", func() {
+		fr := read(iterator.Config{
+			Keys:     channel.Keys{raw.Key()},
+			Scaled:   true,
+			Resample: iterator.Resample{Rate: 2 * telem.Hz, Interpolation: iterator.InterpolateLinear},
+		})
+		var data []byte
+		for _, s := range fr.Get(raw.Key()) {
+			Expect(s.DataType).To(Equal(telem.Float64T))
+			data = append(data, s.Data...)
+		}
+		Expect(data).To(Equal(telem.NewSeriesV[float64](0, 0.5, 1, 1.5, 2).Data))
+	})
+})
@@ -0,0 +1,443 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package iterator
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"sort"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/freighter"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	dcore "github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/core"
+	"github.com/synnaxlabs/synnax/pkg/storage/ts"
+	"github.com/synnaxlabs/x/calc"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// SearchConfig configures a search for the time ranges in which a predicate over the
+// values of one or more channels holds.
+//
+// The value of a channel at a timestamp is the value of its last sample at or before
+// the timestamp. The predicate is evaluated at the timestamp of every sample of the
+// channels it references, once every channel has at least one sample, and holds when
+// it evaluates to 1, as the comparison and logical operators do. A matching range
+// starts at the first timestamp at which the predicate holds and ends at the first
+// timestamp after it at which the predicate does not, or one nanosecond after the last
+// sample if the predicate holds until the end of the data.
+//
+// The predicate is evaluated on the leaseholders of the channels it references. If
+// the channels have different leaseholders, the predicate is split at its logical
+// operators into parts that each reference the channels of a single leaseholder,
+// and the ranges in which each part holds are intersected or merged. Parts of the
+// predicate that are not joined by logical operators, such as a comparison between
+// two channels, must reference channels on the same leaseholder.
+type SearchConfig struct {
+	// Predicate is an expression in the syntax of the calc package, where identifiers
+	// are the names of the channels to search, e.g. "tc_4 > 300 && valve_state == 1".
+	// Channels must be numeric, and must not be virtual.
+	Predicate string `json:"predicate" msgpack:"predicate"`
+	// Bounds is the time range to search.
+	Bounds telem.TimeRange `json:"bounds" msgpack:"bounds"`
+	// MinDuration discards matching ranges that are shorter than it.
+	MinDuration telem.TimeSpan `json:"min_duration" msgpack:"min_duration"`
+}
+
+// Validate implements config.Config.
+func (cfg SearchConfig) Validate() error {
+	v := validate.New("distribution.framer.iterator.search")
+	validate.NotEmptyString(v, "predicate", cfg.Predicate)
+	validate.NonNegative(v, "min_duration", cfg.MinDuration)
+	v.Ternary("bounds", !cfg.Bounds.Valid(), "bounds must have a start before its end")
+	return v.Error()
+}
+
+// searchWindow is the span of data read at once when evaluating a predicate on a
+// leaseholder. Windows without data are doubled in span until data is found.
+const searchWindow = 1 * telem.Minute
+
+// Search returns the merged, non-overlapping time ranges within cfg.Bounds in which
+// cfg.Predicate holds, in ascending order. See SearchConfig for more details.
+func (s *Service) Search(ctx context.Context, cfg SearchConfig) ([]telem.TimeRange, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var predicate calc.Expression
+	if err := predicate.Build(cfg.Predicate); err != nil {
+		return nil, errors.Wrapf(validate.Error, "invalid predicate %s: %s", cfg.Predicate, err)
+	}
+	channels, err := s.retrieveSearchChannels(ctx, predicate.Identifiers())
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.planSearch(predicate.Tree(), channels, false)
+	if err != nil {
+		return nil, err
+	}
+	ranges, err := s.execSearch(ctx, plan, cfg.Bounds)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Filter(ranges, func(tr telem.TimeRange, _ int) bool {
+		return tr.Span() >= cfg.MinDuration
+	}), nil
+}
+
+// retrieveSearchChannels retrieves the channels with the given names, checking that
+// each name refers to exactly one channel that can be searched.
+func (s *Service) retrieveSearchChannels(
+	ctx context.Context,
+	names []string,
+) (map[string]channel.Channel, error) {
+	if len(names) == 0 {
+		return nil, errors.Wrap(validate.Error, "search predicate must reference at least one channel")
+	}
+	var channels []channel.Channel
+	if err := s.ChannelReader.NewRetrieve().
+		WhereNames(names...).
+		Entries(&channels).
+		Exec(ctx, nil); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]channel.Channel, len(names))
+	for _, ch := range channels {
+		if _, ok := byName[ch.Name]; ok {
+			return nil, errors.Wrapf(validate.Error, "channel name %s in search predicate is ambiguous", ch.Name)
+		}
+		if err := validateSearchChannel(ch); err != nil {
+			return nil, err
+		}
+		byName[ch.Name] = ch
+	}
+	for _, name := range names {
+		if _, ok := byName[name]; !ok {
+			return nil, errors.Wrapf(query.NotFound, "channel %s in search predicate not found", name)
+		}
+	}
+	return byName, nil
+}
+
+func validateSearchChannel(ch channel.Channel) error {
+	if ch.Virtual || ch.Free() {
+		return errors.Wrapf(validate.Error, "cannot search virtual channel %v", ch)
+	}
+	if !ch.DataType.IsNumeric() && ch.DataType != telem.TimeStampT {
+		return errors.Wrapf(validate.Error, "cannot search non-numeric channel %v", ch)
+	}
+	return nil
+}
+
+// searchPlan is a tree that describes how to evaluate a search predicate. Leaves are
+// evaluated on the leaseholder of their channels, and the ranges returned by the
+// children of a node are combined using the node's logical operator.
+type searchPlan struct {
+	// op is token.LAND or token.LOR for a node that combines the ranges of its
+	// children, and token.ILLEGAL for a leaf.
+	op       token.Token
+	children [2]*searchPlan
+	// predicate, keys, and leaseholder are only set for leaves.
+	predicate   calc.Expression
+	keys        channel.Keys
+	leaseholder dcore.NodeKey
+}
+
+// planSearch builds a plan that evaluates exp, negating it if negate is true.
+// Negations of logical operators that span multiple leaseholders are pushed down to
+// their operands so that every leaf is evaluated on a single leaseholder.
+func (s *Service) planSearch(
+	exp ast.Expr,
+	channels map[string]channel.Channel,
+	negate bool,
+) (*searchPlan, error) {
+	var (
+		names        = calc.FromTree(exp).Identifiers()
+		keys         = make(channel.Keys, len(names))
+		leaseholders = make(map[dcore.NodeKey]struct{})
+	)
+	for i, name := range names {
+		ch := channels[name]
+		keys[i] = ch.Key()
+		leaseholders[ch.Lease()] = struct{}{}
+	}
+	if len(leaseholders) <= 1 {
+		if negate {
+			exp = &ast.UnaryExpr{Op: token.NOT, X: exp}
+		}
+		leaf := &searchPlan{op: token.ILLEGAL, predicate: calc.FromTree(exp), keys: keys}
+		for l := range leaseholders {
+			leaf.leaseholder = l
+		}
+		return leaf, nil
+	}
+	switch exp := exp.(type) {
+	case *ast.UnaryExpr:
+		if exp.Op == token.NOT {
+			return s.planSearch(exp.X, channels, !negate)
+		}
+	case *ast.BinaryExpr:
+		if exp.Op != token.LAND && exp.Op != token.LOR {
+			break
+		}
+		op := exp.Op
+		if negate {
+			op = lo.Ternary(op == token.LAND, token.LOR, token.LAND)
+		}
+		x, err := s.planSearch(exp.X, channels, negate)
+		if err != nil {
+			return nil, err
+		}
+		y, err := s.planSearch(exp.Y, channels, negate)
+		if err != nil {
+			return nil, err
+		}
+		return &searchPlan{op: op, children: [2]*searchPlan{x, y}}, nil
+	}
+	return nil, errors.Wrapf(
+		validate.Error,
+		"search predicate %s compares channels %v that are on different nodes",
+		calc.FromTree(exp),
+		names,
+	)
+}
+
+// execSearch evaluates the given plan within bounds.
+func (s *Service) execSearch(
+	ctx context.Context,
+	plan *searchPlan,
+	bounds telem.TimeRange,
+) ([]telem.TimeRange, error) {
+	if plan.op != token.ILLEGAL {
+		x, err := s.execSearch(ctx, plan.children[0], bounds)
+		if err != nil {
+			return nil, err
+		}
+		y, err := s.execSearch(ctx, plan.children[1], bounds)
+		if err != nil {
+			return nil, err
+		}
+		if plan.op == token.LAND {
+			return intersectRanges(x, y), nil
+		}
+		return mergeRanges(append(x, y...)), nil
+	}
+	// A part of the predicate that references no channels is constant.
+	if len(plan.keys) == 0 {
+		if plan.predicate.Evaluate(nil) == 1 {
+			return []telem.TimeRange{bounds}, nil
+		}
+		return nil, nil
+	}
+	req := Request{Keys: plan.keys, Bounds: bounds, Predicate: plan.predicate.String()}
+	if plan.leaseholder == s.HostResolver.HostKey() {
+		return search(ctx, s.ServiceConfig, req)
+	}
+	return s.searchPeer(ctx, plan.leaseholder, req)
+}
+
+// searchPeer evaluates a search request on the given peer node.
+func (s *Service) searchPeer(
+	ctx context.Context,
+	nodeKey dcore.NodeKey,
+	req Request,
+) ([]telem.TimeRange, error) {
+	target, err := s.HostResolver.Resolve(nodeKey)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.Transport.Client().Stream(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Send(req); err != nil {
+		return nil, err
+	}
+	res, err := client.Receive()
+	if err != nil {
+		return nil, err
+	}
+	if err = client.CloseSend(); err != nil {
+		return nil, err
+	}
+	if _, err = client.Receive(); err != nil && !errors.Is(err, freighter.EOF) {
+		return nil, err
+	}
+	return res.Ranges, res.Error
+}
+
+// search evaluates the predicate of a search request against the data stored on this
+// node, returning the ranges in which it holds.
+func search(ctx context.Context, cfg ServiceConfig, req Request) ([]telem.TimeRange, error) {
+	var predicate calc.Expression
+	if err := predicate.Build(req.Predicate); err != nil {
+		return nil, errors.Wrapf(validate.Error, "invalid predicate %s: %s", req.Predicate, err)
+	}
+	var channels []channel.Channel
+	if err := cfg.ChannelReader.NewRetrieve().
+		WhereKeys(req.Keys...).
+		Entries(&channels).
+		Exec(ctx, nil); err != nil {
+		return nil, err
+	}
+	read := make(channel.Keys, 0, len(channels)*2)
+	for _, ch := range channels {
+		if err := validateSearchChannel(ch); err != nil {
+			return nil, err
+		}
+		read = append(read, ch.Key())
+		if ch.Index() != 0 {
+			read = append(read, ch.Index())
+		}
+	}
+	iter, err := cfg.TS.OpenIterator(ts.IteratorConfig{
+		Channels: read.Unique().Storage(),
+		Bounds:   req.Bounds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e := &evaluator{predicate: predicate, channels: channels, values: make(map[string]float64, len(channels))}
+	for start, span := req.Bounds.Start, searchWindow; start < req.Bounds.End; {
+		window := start.Range(req.Bounds.End)
+		if span < window.Span() {
+			window.End = start.Add(span)
+		}
+		iter.SetBounds(window)
+		if iter.SeekFirst() && iter.Next(window.Span()) {
+			if err = e.ingest(core.NewFrameFromStorage(iter.Value())); err != nil {
+				break
+			}
+			span = searchWindow
+		} else if span < telem.TimeSpanMax/2 {
+			span *= 2
+		}
+		start = window.End
+	}
+	err = errors.CombineErrors(err, iter.Error())
+	if err = errors.CombineErrors(err, iter.Close()); err != nil {
+		return nil, err
+	}
+	return e.finish(), nil
+}
+
+// evaluator evaluates a search predicate at the timestamps of the samples of its
+// channels, accumulating the ranges in which it holds.
+type evaluator struct {
+	predicate calc.Expression
+	channels  []channel.Channel
+	// values holds the value of the last sample of each channel, keyed by name.
+	values map[string]float64
+	// matching is true if the predicate held at the last evaluated timestamp, in which
+	// case start is the timestamp at which it started holding.
+	matching bool
+	start    telem.TimeStamp
+	last     telem.TimeStamp
+	ranges   []telem.TimeRange
+}
+
+var _ calc.Resolver = (*evaluator)(nil)
+
+// Resolve implements calc.Resolver.
+func (e *evaluator) Resolve(name string) (float64, error) { return e.values[name], nil }
+
+type searchSample struct {
+	stamp telem.TimeStamp
+	name  string
+	value float64
+}
+
+// ingest evaluates the predicate at the timestamps of the samples in the given frame,
+// which must come after the samples of any previously ingested frame.
+func (e *evaluator) ingest(fr core.Frame) error {
+	var samples []searchSample
+	for _, ch := range e.channels {
+		for _, s := range fr.Get(ch.Key()) {
+			stamps, err := timestamps(fr, ch, s)
+			if err != nil {
+				return err
+			}
+			for i, t := range stamps {
+				samples = append(samples, searchSample{
+					stamp: t,
+					name:  ch.Name,
+					value: s.Float64At(int64(i)),
+				})
+			}
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].stamp < samples[j].stamp })
+	for i := 0; i < len(samples); {
+		t := samples[i].stamp
+		for ; i < len(samples) && samples[i].stamp == t; i++ {
+			e.values[samples[i].name] = samples[i].value
+		}
+		if len(e.values) == len(e.channels) {
+			e.evaluate(t)
+		}
+	}
+	return nil
+}
+
+func (e *evaluator) evaluate(t telem.TimeStamp) {
+	holds := e.predicate.Evaluate(e) == 1
+	if holds && !e.matching {
+		e.start = t
+	} else if !holds && e.matching {
+		e.ranges = append(e.ranges, e.start.Range(t))
+	}
+	e.matching = holds
+	e.last = t
+}
+
+// finish closes the range in which the predicate holds at the end of the data, if
+// any, and returns the accumulated ranges.
+func (e *evaluator) finish() []telem.TimeRange {
+	if e.matching {
+		e.ranges = append(e.ranges, e.start.Range(e.last+1))
+		e.matching = false
+	}
+	return e.ranges
+}
+
+// mergeRanges sorts the given ranges and merges those that overlap or touch.
+func mergeRanges(ranges []telem.TimeRange) []telem.TimeRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := make([]telem.TimeRange, 0, len(ranges))
+	for _, tr := range ranges {
+		if n := len(merged); n > 0 && tr.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, tr.End)
+			continue
+		}
+		merged = append(merged, tr)
+	}
+	return merged
+}
+
+// intersectRanges returns the ranges covered by both a and b, which must be sorted
+// and non-overlapping.
+func intersectRanges(a, b []telem.TimeRange) []telem.TimeRange {
+	var out []telem.TimeRange
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if tr := a[i].Intersection(b[j]); tr.Start < tr.End {
+			out = append(out, tr)
+		}
+		if a[i].End < b[j].End {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package iterator_test
+
+import (
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	dcore "github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/writer"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Search", func() {
+	var (
+		closer io.Closer
+		svc    serviceContainer
+	)
+	write := func(start telem.TimeStamp, keys channel.Keys, series ...telem.Series) {
+		w := MustSucceed(svc.writer.New(ctx, writer.Config{Keys: keys, Start: start}))
+		Expect(w.Write(core.Frame{Keys: keys, Series: series})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+		Expect(w.Close()).To(Succeed())
+	}
+	// setup creates an indexed "tc" channel on the tcNode and a rate based "valve"
+	// channel on the valveNode, and writes data to them through the first node.
+	setup := func(nodes int, tcNode, valveNode dcore.NodeKey) {
+		b, services := provision(nodes)
+		closer, svc = b, services[1]
+		idx := channel.Channel{Name: "time", DataType: telem.TimeStampT, IsIndex: true, Leaseholder: tcNode}
+		Expect(svc.channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+		channels := []channel.Channel{
+			{Name: "tc", DataType: telem.Float64T, LocalIndex: idx.LocalKey, Leaseholder: tcNode},
+			{Name: "valve", DataType: telem.Uint8T, Rate: 1 * telem.Hz, Leaseholder: valveNode},
+		}
+		Expect(svc.channel.NewWriter(nil).CreateMany(ctx, &channels)).To(Succeed())
+		Eventually(func(g Gomega) {
+			var chs []channel.Channel
+			g.Expect(svc.channel.NewRetrieve().
+				WhereKeys(idx.Key(), channels[0].Key(), channels[1].Key()).
+				Entries(&chs).
+				Exec(ctx, nil)).To(Succeed())
+			g.Expect(chs).To(HaveLen(3))
+		}).Should(Succeed())
+		keys := channel.Keys{idx.Key(), channels[0].Key()}
+		write(
+			10*telem.SecondTS,
+			keys,
+			telem.NewSecondsTSV(10, 11, 12, 13, 14, 15, 16, 17, 18, 19),
+			telem.NewSeriesV[float64](100, 200, 350, 400, 250, 320, 330, 340, 100, 500),
+		)
+		write(
+			600*telem.SecondTS,
+			keys,
+			telem.NewSecondsTSV(600, 601, 602),
+			telem.NewSeriesV[float64](400, 400, 100),
+		)
+		write(
+			10*telem.SecondTS,
+			channel.Keys{channels[1].Key()},
+			telem.NewSeriesV[uint8](0, 0, 1, 1, 1, 1, 1, 1, 0, 0),
+		)
+	}
+	search := func(predicate string, bounds telem.TimeRange, minDuration telem.TimeSpan) ([]telem.TimeRange, error) {
+		return svc.iter.Search(ctx, iterator.SearchConfig{
+			Predicate:   predicate,
+			Bounds:      bounds,
+			MinDuration: minDuration,
+		})
+	}
+	secondsRange := func(start, end telem.TimeStamp) telem.TimeRange {
+		return (start * telem.SecondTS).Range(end * telem.SecondTS)
+	}
+	AfterEach(func() { Expect(closer.Close()).To(Succeed()) })
+
+	Describe("Single Node", func() {
+		BeforeEach(func() { setup(1, 1, 1) })
+
+		It("Should return the ranges in which a predicate holds", func() {
+			Expect(search("tc > 300", telem.TimeRangeMax, 0)).To(Equal([]telem.TimeRange{
+				secondsRange(12, 14),
+				secondsRange(15, 18),
+				secondsRange(19, 602),
+			}))
+		})
+
+		It("Should discard ranges shorter than the minimum duration", func() {
+			Expect(search("tc > 300", telem.TimeRangeMax, 2500*telem.Millisecond)).To(Equal([]telem.TimeRange{
+				secondsRange(15, 18),
+				secondsRange(19, 602),
+			}))
+		})
+
+		It("Should only search within the bounds", func() {
+			Expect(search("tc > 300", secondsRange(13, 16), 0)).To(Equal([]telem.TimeRange{
+				secondsRange(13, 14),
+				(15 * telem.SecondTS).Range(15*telem.SecondTS + 1),
+			}))
+		})
+
+		It("Should evaluate a predicate across multiple channels", func() {
+			Expect(search("tc > 300 && valve == 1", telem.TimeRangeMax, 0)).To(Equal([]telem.TimeRange{
+				secondsRange(12, 14),
+				secondsRange(15, 18),
+			}))
+		})
+
+		It("Should return no ranges if the predicate never holds", func() {
+			Expect(search("tc > 1000", telem.TimeRangeMax, 0)).To(BeEmpty())
+		})
+
+		It("Should return an error if a channel does not exist", func() {
+			Expect(search("pressure > 10", telem.TimeRangeMax, 0)).Error().To(HaveOccurredAs(query.NotFound))
+		})
+
+		It("Should return an error if the predicate is invalid", func() {
+			Expect(search("tc >", telem.TimeRangeMax, 0)).Error().To(HaveOccurredAs(validate.Error))
+		})
+
+		It("Should return an error if the predicate is empty", func() {
+			Expect(search("", telem.TimeRangeMax, 0)).Error().To(HaveOccurredAs(validate.FieldError{
+				Field:   "predicate",
+				Message: "field must be set",
+			}))
+		})
+	})
+
+	Describe("Multiple Nodes", func() {
+		BeforeEach(func() { setup(3, 2, 1) })
+
+		It("Should intersect the ranges found on each leaseholder", func() {
+			Expect(search("tc > 300 && valve == 1", telem.TimeRangeMax, 0)).To(Equal([]telem.TimeRange{
+				secondsRange(12, 14),
+				secondsRange(15, 18),
+			}))
+		})
+
+		It("Should merge the ranges found on each leaseholder", func() {
+			Expect(search("tc > 300 || valve == 1", telem.TimeRangeMax, 0)).To(Equal([]telem.TimeRange{
+				secondsRange(12, 18),
+				secondsRange(19, 602),
+			}))
+		})
+
+		It("Should negate a predicate that spans multiple leaseholders", func() {
+			Expect(search("!(tc > 300 && valve == 1)", telem.TimeRangeMax, 0)).To(Equal([]telem.TimeRange{
+				secondsRange(10, 12),
+				secondsRange(14, 15),
+				(18 * telem.SecondTS).Range(19*telem.SecondTS + 1),
+				(602 * telem.SecondTS).Range(602*telem.SecondTS + 1),
+			}))
+		})
+
+		It("Should not compare channels on different leaseholders", func() {
+			Expect(search("tc > valve", telem.TimeRangeMax, 0)).Error().To(HaveOccurredAs(validate.Error))
+		})
+	})
+})
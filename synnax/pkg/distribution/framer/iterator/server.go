@@ -37,6 +37,16 @@ func (sf *server) handle(ctx context.Context, server ServerStream) error {
 	if err != nil {
 		return err
 	}
+	if req.Predicate != "" {
+		ranges, err := search(ctx, sf.ServiceConfig, req)
+		return server.Send(Response{
+			Variant: AckResponse,
+			NodeKey: sf.HostResolver.HostKey(),
+			Ack:     err == nil,
+			Ranges:  ranges,
+			Error:   err,
+		})
+	}
 
 	receiver := &freightfluence.TransformReceiver[ts.IteratorRequest, Request]{Receiver: server}
 	receiver.Transform = newStorageRequestTranslator()
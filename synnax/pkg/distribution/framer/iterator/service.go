@@ -16,6 +16,7 @@ import (
 	"github.com/synnaxlabs/alamos"
 	"github.com/synnaxlabs/aspen"
 	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/core"
 	"github.com/synnaxlabs/synnax/pkg/distribution/proxy"
 	"github.com/synnaxlabs/synnax/pkg/storage/ts"
 	"github.com/synnaxlabs/x/address"
@@ -34,6 +35,12 @@ type Config struct {
 	Keys      channel.Keys    `json:"keys" msgpack:"keys"`
 	Bounds    telem.TimeRange `json:"bounds" msgpack:"bounds"`
 	ChunkSize int64           `json:"chunk_size" msgpack:"chunk_size"`
+	// Resample optionally resamples the frames returned by the iterator onto a single
+	// set of timestamps. See Resample for more details.
+	Resample Resample `json:"resample" msgpack:"resample"`
+	// Scaled sets whether the iterator converts the values of channels that have a
+	// channel.Scale into engineering units.
+	Scaled bool `json:"scaled" msgpack:"scaled"`
 }
 
 type ServiceConfig struct {
@@ -87,6 +94,9 @@ const (
 	gatewayIterAddr  address.Address = "gatewayWriter"
 	broadcasterAddr  address.Address = "broadcaster"
 	synchronizerAddr address.Address = "synchronizer"
+	resamplerAddr    address.Address = "resampler"
+	cursorAddr       address.Address = "cursor"
+	scalerAddr       address.Address = "scaler"
 )
 
 func (s *Service) New(ctx context.Context, cfg Config) (*Iterator, error) {
@@ -114,6 +124,26 @@ func (s *Service) NewStream(ctx context.Context, cfg Config) (StreamIterator, er
 	}
 	cfg.Keys = cfg.Keys.Unique()
 
+	var scaler core.Scaler
+	if cfg.Scaled {
+		var channels []channel.Channel
+		if err := s.ChannelReader.NewRetrieve().
+			WhereKeys(cfg.Keys...).
+			Entries(&channels).
+			Exec(ctx, nil); err != nil {
+			return nil, err
+		}
+		scaler = core.NewScaler(channels)
+	}
+
+	var rs *resampler
+	if cfg.Resample.Enabled() {
+		var err error
+		if rs, cfg.Keys, err = s.newResampler(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	var (
 		hostID             = s.HostResolver.HostKey()
 		batch              = proxy.BatchFactory[channel.Key]{Host: hostID}.Batch(cfg.Keys)
@@ -177,8 +207,23 @@ func (s *Service) NewStream(ctx context.Context, cfg Config) (StreamIterator, er
 		Capacity:      len(receiverAddresses),
 	}.MustRoute(pipe)
 
+	routeOutletFrom := synchronizerAddr
+	if len(scaler) > 0 {
+		plumber.SetSegment[Response, Response](pipe, scalerAddr, newScaler(scaler))
+		plumber.MustConnect[Response](pipe, routeOutletFrom, scalerAddr, 1)
+		routeOutletFrom = scalerAddr
+	}
+	if rs != nil {
+		plumber.SetSegment[Response, Response](pipe, resamplerAddr, rs)
+		plumber.MustConnect[Response](pipe, routeOutletFrom, resamplerAddr, 1)
+		routeOutletFrom = resamplerAddr
+		plumber.SetSegment[Request, Request](pipe, cursorAddr, rs.newCursor())
+		plumber.MustConnect[Request](pipe, cursorAddr, routeInletTo, 1)
+		routeInletTo = cursorAddr
+	}
+
 	seg := &plumber.Segment[Request, Response]{Pipeline: pipe}
-	lo.Must0(seg.RouteOutletFrom(synchronizerAddr))
+	lo.Must0(seg.RouteOutletFrom(routeOutletFrom))
 	lo.Must0(seg.RouteInletTo(routeInletTo))
 	return seg, nil
 }
@@ -43,3 +43,21 @@ func (a *synchronizer) sync(_ context.Context, res Response) (Response, bool, er
 	}
 	return Response{}, false, nil
 }
+
+type scaler struct {
+	internal core.Scaler
+	confluence.LinearTransform[Response, Response]
+}
+
+func newScaler(internal core.Scaler) confluence.Segment[Response, Response] {
+	s := &scaler{internal: internal}
+	s.Transform = s.scale
+	return s
+}
+
+func (s *scaler) scale(_ context.Context, res Response) (Response, bool, error) {
+	if res.Variant == DataResponse {
+		res.Frame = s.internal.Scale(res.Frame)
+	}
+	return res, true, nil
+}
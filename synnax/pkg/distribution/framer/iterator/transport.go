@@ -49,6 +49,14 @@ type Request struct {
 	Keys channel.Keys `json:"keys" msgpack:"keys"`
 	// ChunkSize should only be set when opening the Iterator.
 	ChunkSize int64 `json:"chunk_size" msgpack:"chunk_size"`
+	// Resample should only be set when opening the Iterator.
+	Resample Resample `json:"resample" msgpack:"resample"`
+	// Scaled should only be set when opening the Iterator.
+	Scaled bool `json:"scaled" msgpack:"scaled"`
+	// Predicate should only be set when opening a stream to search for the time ranges
+	// in which the predicate holds, instead of opening an Iterator. See SearchConfig
+	// for more details.
+	Predicate string `json:"predicate" msgpack:"predicate"`
 }
 
 //go:generate stringer -type=ResponseVariant
@@ -79,6 +87,9 @@ type Response struct {
 	// Error is only relevant for variant AckResponse. It is an error returned during a call to
 	// Iterator.Error
 	Error error `json:"error" msgpack:"error"`
+	// Ranges is only relevant for responses to a search. It contains the time ranges in
+	// which the search predicate holds.
+	Ranges []telem.TimeRange `json:"ranges" msgpack:"ranges"`
 }
 
 type (
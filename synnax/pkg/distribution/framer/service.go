@@ -22,6 +22,7 @@ import (
 	"github.com/synnaxlabs/x/config"
 	"github.com/synnaxlabs/x/confluence"
 	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
 	"github.com/synnaxlabs/x/validate"
 )
 
@@ -70,11 +71,15 @@ type Config struct {
 	TS            *ts.DB
 	Transport     Transport
 	HostResolver  core.HostResolver
+	// StreamerBufferSize is the maximum number of live frames a streamer buffers while
+	// it catches up on historical data. See StreamerConfig.Start.
+	// [OPTIONAL] - Defaults to 1000.
+	StreamerBufferSize int
 }
 
 var (
 	_             config.Config[Config] = Config{}
-	DefaultConfig                       = Config{}
+	DefaultConfig                       = Config{StreamerBufferSize: 1000}
 )
 
 // Validate implements config.Properties.
@@ -84,6 +89,7 @@ func (c Config) Validate() error {
 	validate.NotNil(v, "TS", c.TS)
 	validate.NotNil(v, "Transport", c.Transport)
 	validate.NotNil(v, "HostProvider", c.HostResolver)
+	validate.Positive(v, "StreamerBufferSize", c.StreamerBufferSize)
 	return v.Error()
 }
 
@@ -94,6 +100,7 @@ func (c Config) Override(other Config) Config {
 	c.TS = override.Nil(c.TS, other.TS)
 	c.Transport = override.Nil(c.Transport, other.Transport)
 	c.HostResolver = override.Nil(c.HostResolver, other.HostResolver)
+	c.StreamerBufferSize = override.Numeric(c.StreamerBufferSize, other.StreamerBufferSize)
 	return c
 }
 
@@ -153,6 +160,12 @@ func (s *Service) NewStreamIterator(ctx context.Context, cfg IteratorConfig) (St
 	return s.iterator.NewStream(ctx, cfg)
 }
 
+// Search returns the time ranges in which a predicate over the values of one or more
+// channels holds. See SearchConfig for more details.
+func (s *Service) Search(ctx context.Context, cfg SearchConfig) ([]telem.TimeRange, error) {
+	return s.iterator.Search(ctx, cfg)
+}
+
 func (s *Service) OpenWriter(ctx context.Context, cfg WriterConfig) (*Writer, error) {
 	return s.writer.New(ctx, cfg)
 }
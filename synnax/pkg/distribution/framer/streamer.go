@@ -11,6 +11,8 @@ package framer
 
 import (
 	"context"
+	"slices"
+	"sort"
 
 	"github.com/samber/lo"
 	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
@@ -21,6 +23,8 @@ import (
 	"github.com/synnaxlabs/x/confluence"
 	"github.com/synnaxlabs/x/reflect"
 	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
 )
 
 type Streamer = confluence.Segment[StreamerRequest, StreamerResponse]
@@ -29,23 +33,175 @@ type streamer struct {
 	ts                 *ts.DB
 	sendControlDigests bool
 	controlStateKey    channel.Key
+	channels           channel.Readable
+	// scaler is only set when the streamer was configured to send scaled values.
+	scaler core.Scaler
 	confluence.AbstractUnarySink[StreamerRequest]
 	confluence.AbstractUnarySource[StreamerResponse]
+	// iter is the catch-up iterator, which is only open while historical data is
+	// being read so that it doesn't hold storage read handles while streaming live
+	// data.
 	iter struct {
 		flow      confluence.Flow
 		requests  confluence.Inlet[IteratorRequest]
 		responses confluence.Outlet[IteratorResponse]
+		// ctx and opts are the context and options the streamer was flowed with,
+		// which are used to flow iterators opened to re-read historical data.
+		ctx  signal.Context
+		opts []confluence.Option
 	}
 	relay struct {
 		flow      confluence.Flow
 		requests  confluence.Inlet[relay.Request]
 		responses confluence.Outlet[relay.Response]
 	}
+	// catchUp is only set when the streamer was configured with a start timestamp.
+	catchUp *catchUp
+	// bufferSize is the maximum number of live frames buffered while catching up.
+	bufferSize int
+}
+
+// catchUp tracks the state needed to hand off from reading historical data to
+// streaming live data without gaps or duplicates. To avoid gaps, the streamer
+// subscribes to the relay before reading historical data, buffering any live frames
+// that arrive in the meantime. Live series that overlap with the historical data are
+// then trimmed by looking up their timestamps in the index series that share their
+// alignment.
+type catchUp struct {
+	// keys are the keys the caller requested. The relay subscription also includes
+	// the index channels of these keys, which are filtered out before sending frames.
+	keys channel.Keys
+	// indexes maps the key of each persisted channel to the key of its index. Index
+	// channels map to themselves.
+	indexes map[channel.Key]channel.Key
+	// highWater is the exclusive upper bound of the timestamps read from each
+	// channel's historical data. Channels are removed once live data has moved past
+	// their high water mark.
+	highWater map[channel.Key]telem.TimeStamp
+	// start is the timestamp the streamer started reading historical data from.
+	start telem.TimeStamp
+	// iterKeys are the keys of the channels whose historical data is read.
+	iterKeys channel.Keys
+	// openIter opens an iterator over the historical data of iterKeys within the
+	// given bounds.
+	openIter func(ctx context.Context, bounds telem.TimeRange) (StreamIterator, error)
+	// settled is true once the catch-up is complete and no more historical data needs
+	// to be read.
+	settled bool
+	// overflowed is set when live frames were dropped because the buffer was full
+	// while reading historical data. The dropped data is recovered by reading the
+	// history again from the high water marks.
+	overflowed bool
+}
+
+// unread returns the time range of historical data that hasn't been read yet, starting
+// at the lowest high water mark of the channels being read.
+func (c *catchUp) unread() telem.TimeRange {
+	lowest := telem.TimeStampMax
+	for _, k := range c.iterKeys {
+		hw, ok := c.highWater[k]
+		if !ok {
+			hw = c.start
+		}
+		lowest = min(lowest, hw)
+	}
+	return lowest.Range(telem.TimeStampMax)
+}
+
+// relayKeys returns the keys the relay needs to subscribe to in order to deduplicate
+// live frames for the given requested keys.
+func (c *catchUp) relayKeys(keys channel.Keys) channel.Keys {
+	rKeys := slices.Clone(keys)
+	for _, k := range keys {
+		if idx, ok := c.indexes[k]; ok && !rKeys.Contains(idx) {
+			rKeys = append(rKeys, idx)
+		}
+	}
+	return rKeys
+}
+
+// read updates the high water marks using a frame of historical data.
+func (c *catchUp) read(fr Frame) {
+	for i, k := range fr.Keys {
+		if end := fr.Series[i].TimeRange.End; end > c.highWater[k] {
+			c.highWater[k] = end
+		}
+	}
+}
+
+// dedupe removes the samples in a frame of live data that were already sent while
+// reading historical data, along with any series for channels that the caller didn't
+// request.
+func (c *catchUp) dedupe(fr Frame) Frame {
+	out := Frame{Keys: make(channel.Keys, 0, len(fr.Keys)), Series: make([]telem.Series, 0, len(fr.Series))}
+	for i, k := range fr.Keys {
+		if !c.keys.Contains(k) {
+			continue
+		}
+		s := fr.Series[i]
+		if hw, ok := c.highWater[k]; ok {
+			// Historical series can arrive without their index, so skip any series
+			// whose time range shows that it was already read.
+			if !s.TimeRange.IsZero() && s.TimeRange.End <= hw {
+				continue
+			}
+			if stamps, found := c.timestamps(fr, k, s.Alignment); found {
+				n := sort.Search(len(stamps), func(j int) bool { return stamps[j] >= hw })
+				if n == len(stamps) {
+					continue
+				}
+				if n == 0 {
+					delete(c.highWater, k)
+				}
+				s = sliceSeries(s, int64(n))
+			}
+		}
+		out.Keys = append(out.Keys, k)
+		out.Series = append(out.Series, s)
+	}
+	return out
+}
+
+// timestamps returns the timestamps of the samples in the series for the given
+// channel with the given alignment by finding the series of its index channel with
+// the same alignment in the frame.
+func (c *catchUp) timestamps(
+	fr Frame,
+	key channel.Key,
+	alignment telem.AlignmentPair,
+) ([]telem.TimeStamp, bool) {
+	idx, ok := c.indexes[key]
+	if !ok {
+		return nil, false
+	}
+	for i, k := range fr.Keys {
+		if k != idx || fr.Series[i].Alignment != alignment {
+			continue
+		}
+		s := fr.Series[i]
+		stamps := make([]telem.TimeStamp, s.Len())
+		for j := range stamps {
+			stamps[j] = telem.ValueAt[telem.TimeStamp](s, int64(j))
+		}
+		return stamps, true
+	}
+	return nil, false
+}
+
+func sliceSeries(s telem.Series, start int64) telem.Series {
+	if start == 0 {
+		return s
+	}
+	s.Data = s.Data[start*int64(s.DataType.Density()):]
+	s.Alignment = s.Alignment.AddSamples(uint32(start))
+	s.TimeRange = telem.TimeRange{}
+	return s
 }
 
 // Flow implements confluence.Flow.
 func (l *streamer) Flow(sCtx signal.Context, opts ...confluence.Option) {
 	hasIter := !reflect.IsNil(l.iter.flow)
+	l.iter.ctx, l.iter.opts = sCtx, opts
 	if hasIter {
 		l.iter.flow.Flow(sCtx, opts...)
 	}
@@ -53,63 +209,91 @@ func (l *streamer) Flow(sCtx signal.Context, opts ...confluence.Option) {
 	o.AttachClosables(l.Out)
 
 	sCtx.Go(func(ctx context.Context) error {
-		if hasIter {
-			// start off by exhausting the iterator
-		o:
-			for {
-				l.iter.requests.Inlet() <- IteratorRequest{
-					Command: iterator.Next,
-					Span:    iterator.AutoSpan,
-				}
-
-				for res := range l.iter.responses.Outlet() {
-					if res.Variant == iterator.AckResponse {
-						if res.Ack == false {
-							break o
-						}
-						break
-					}
-					l.Out.Inlet() <- StreamerResponse{
-						Frame: res.Frame,
-						Error: res.Error,
-					}
-				}
-			}
+		// Tap into the relay before reading any historical data so that we don't miss
+		// any frames written while we're catching up.
+		l.relay.flow.Flow(sCtx, append(opts, confluence.WithAddress("relay-reader"))...)
+		closeRelay := func() {
+			l.relay.requests.Close()
+			confluence.Drain(l.relay.responses)
 		}
 
-		// Close the iterator and drain the response channel
+		var buffered []relay.Response
+		defer l.closeIter()
 		if hasIter {
-			l.iter.requests.Close()
-			confluence.Drain(l.iter.responses)
+			err := l.readHistory(ctx, &buffered)
+			l.closeIter()
+			if err != nil {
+				closeRelay()
+				return err
+			}
 		}
 
-		l.relay.flow.Flow(sCtx, append(opts, confluence.WithAddress("relay-reader"))...)
-
 		if l.sendControlDigests {
 			u := l.ts.ControlUpdateToFrame(ctx, l.ts.ControlStates())
 			l.Out.Inlet() <- StreamerResponse{Frame: core.NewFrameFromStorage(u)}
 		}
 
+		// The relay subscribes to live data asynchronously, so frames written just
+		// before it started delivering may have been committed after we read the
+		// history. Once the first live frame arrives, the subscription is known to be
+		// active, so we re-read the history from the high water marks to pick up any
+		// such frames before sending the live ones. While sending, we keep buffering
+		// live frames so that a slow consumer doesn't cause the relay to drop them.
+		// If live frames were dropped because the buffer overflowed, the history is
+		// re-read until it has been read without overflowing.
+		flush := func() error {
+			if !l.settled() && (len(buffered) > 0 || l.catchUp.overflowed) {
+				err := l.rereadHistory(ctx, &buffered)
+				for err == nil && l.catchUp.overflowed {
+					err = l.rereadHistory(ctx, &buffered)
+				}
+				l.catchUp.settled = true
+				if err != nil {
+					return err
+				}
+			}
+			for len(buffered) > 0 {
+				res := buffered[0]
+				buffered = buffered[1:]
+				fr, ok := l.live(res)
+				if !ok {
+					continue
+				}
+				if err := l.sendBuffering(ctx, fr, &buffered); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err := flush(); err != nil {
+			closeRelay()
+			return err
+		}
+
 		// Then we'll tap into the Relay for stream updates
 		for {
 			select {
 			case <-ctx.Done():
-				l.relay.requests.Close()
-				confluence.Drain(l.relay.responses)
+				closeRelay()
 				return ctx.Err()
 			case res, ok := <-l.relay.responses.Outlet():
 				if !ok {
 					return nil
 				}
-				if err := signal.SendUnderContext(ctx, l.Out.Inlet(), StreamerResponse{Frame: res.Frame}); err != nil {
-					l.relay.requests.Close()
-					confluence.Drain(l.relay.responses)
+				var err error
+				if l.settled() {
+					err = l.sendLive(ctx, res)
+				} else {
+					buffered = append(buffered, res)
+					err = flush()
+				}
+				if err != nil {
+					closeRelay()
 					return err
 				}
 			case req, ok := <-l.In.Outlet():
 				if !ok {
-					l.relay.requests.Close()
-					confluence.Drain(l.relay.responses)
+					closeRelay()
 					return nil
 				}
 				if !l.sendControlDigests && lo.Contains(req.Keys, l.controlStateKey) {
@@ -117,9 +301,19 @@ func (l *streamer) Flow(sCtx signal.Context, opts ...confluence.Option) {
 					u := l.ts.ControlUpdateToFrame(ctx, l.ts.ControlStates())
 					l.Out.Inlet() <- StreamerResponse{Frame: core.NewFrameFromStorage(u)}
 				}
-				if err := signal.SendUnderContext(ctx, l.relay.requests.Inlet(), relay.Request{Keys: req.Keys}); err != nil {
-					l.relay.requests.Close()
-					confluence.Drain(l.relay.responses)
+				if l.scaler != nil {
+					if err := l.updateScaler(ctx, req.Keys); err != nil {
+						closeRelay()
+						return err
+					}
+				}
+				rKeys := req.Keys
+				if l.catchUp != nil {
+					l.catchUp.keys = req.Keys
+					rKeys = l.catchUp.relayKeys(req.Keys)
+				}
+				if err := signal.SendUnderContext(ctx, l.relay.requests.Inlet(), relay.Request{Keys: rKeys}); err != nil {
+					closeRelay()
 					return err
 				}
 			}
@@ -127,9 +321,223 @@ func (l *streamer) Flow(sCtx signal.Context, opts ...confluence.Option) {
 	}, o.Signal...)
 }
 
+// readHistory exhausts the iterator, sending all historical data to the streamer's
+// output. Live frames received from the relay in the meantime are appended to
+// buffered.
+func (l *streamer) readHistory(ctx context.Context, buffered *[]relay.Response) error {
+	ok, err := l.execIter(ctx, iterator.SeekFirst, buffered)
+	for ok && err == nil {
+		ok, err = l.execIter(ctx, iterator.Next, buffered)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = l.execIter(ctx, iterator.Error, buffered)
+	return err
+}
+
+// rereadHistory opens a new iterator to read any historical data committed after the
+// high water marks of the previous read, skipping the samples that were already sent.
+func (l *streamer) rereadHistory(ctx context.Context, buffered *[]relay.Response) error {
+	l.catchUp.overflowed = false
+	iter, err := l.catchUp.openIter(ctx, l.catchUp.unread())
+	if err != nil {
+		return err
+	}
+	l.attachIter(iter)
+	iter.Flow(l.iter.ctx, l.iter.opts...)
+	defer l.closeIter()
+	return l.readHistory(ctx, buffered)
+}
+
+// attachIter makes the given iterator the streamer's catch-up iterator.
+func (l *streamer) attachIter(iter StreamIterator) {
+	l.iter.requests, l.iter.responses = confluence.Attach(iter, 1)
+	l.iter.flow = iter
+}
+
+// closeIter closes the catch-up iterator if it is open, releasing the storage read
+// handles it holds.
+func (l *streamer) closeIter() {
+	if l.iter.requests == nil {
+		return
+	}
+	l.iter.requests.Close()
+	confluence.Drain(l.iter.responses)
+	l.iter.flow, l.iter.requests, l.iter.responses = nil, nil, nil
+}
+
+// execIter executes the given command on the catch-up iterator.
+func (l *streamer) execIter(
+	ctx context.Context,
+	cmd iterator.Command,
+	buffered *[]relay.Response,
+) (bool, error) {
+	return l.execIterRequest(ctx, IteratorRequest{Command: cmd, Span: iterator.AutoSpan}, buffered)
+}
+
+// execIterRequest executes the given request on the catch-up iterator, sending any
+// frames it returns to the streamer's output and buffering any live frames received
+// from the relay in the meantime.
+func (l *streamer) execIterRequest(
+	ctx context.Context,
+	req IteratorRequest,
+	buffered *[]relay.Response,
+) (bool, error) {
+	if err := signal.SendUnderContext(ctx, l.iter.requests.Inlet(), req); err != nil {
+		return false, err
+	}
+	relayRes := l.relay.responses.Outlet()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case res, ok := <-relayRes:
+			if !ok {
+				relayRes = nil
+				continue
+			}
+			l.buffer(buffered, res)
+		case res, ok := <-l.iter.responses.Outlet():
+			if !ok {
+				return false, nil
+			}
+			if res.Variant == iterator.AckResponse {
+				return res.Ack, res.Error
+			}
+			fr := l.catchUp.dedupe(res.Frame)
+			l.catchUp.read(res.Frame)
+			if len(fr.Keys) == 0 {
+				continue
+			}
+			fr = l.scaler.Scale(fr)
+			if err := l.sendBuffering(ctx, StreamerResponse{Frame: fr}, buffered); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// sendLive sends a frame received from the relay to the streamer's output,
+// deduplicating it against historical data if necessary.
+func (l *streamer) sendLive(ctx context.Context, res relay.Response) error {
+	out, ok := l.live(res)
+	if !ok {
+		return nil
+	}
+	return signal.SendUnderContext(ctx, l.Out.Inlet(), out)
+}
+
+// live converts a frame received from the relay into a streamer response,
+// deduplicating it against historical data if necessary. Returns false if there is
+// nothing left to send.
+func (l *streamer) live(res relay.Response) (StreamerResponse, bool) {
+	if l.catchUp != nil {
+		res.Frame = l.catchUp.dedupe(res.Frame)
+		if len(res.Frame.Keys) == 0 {
+			return StreamerResponse{}, false
+		}
+	}
+	res.Frame = l.scaler.Scale(res.Frame)
+	return StreamerResponse{Frame: res.Frame, Error: res.Error}, true
+}
+
+// sendBuffering sends the response to the streamer's output while appending any frames
+// received from the relay in the meantime to buffered. This keeps the relay from
+// dropping frames while the streamer waits on a slow consumer during catch-up. Once the
+// catch-up has settled, the streamer stops receiving from the relay when the buffer is
+// full, leaving the relay to drop frames as it would for any other slow consumer.
+func (l *streamer) sendBuffering(
+	ctx context.Context,
+	res StreamerResponse,
+	buffered *[]relay.Response,
+) error {
+	relayRes := l.relay.responses.Outlet()
+	for {
+		if l.settled() && len(*buffered) >= l.bufferSize {
+			relayRes = nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l.Out.Inlet() <- res:
+			return nil
+		case r, ok := <-relayRes:
+			if !ok {
+				relayRes = nil
+				continue
+			}
+			l.buffer(buffered, r)
+		}
+	}
+}
+
+// buffer appends a live frame to buffered. If the buffer is full while historical data
+// is still being read, the buffered frames are dropped and the catch-up is marked as
+// overflowed so that the dropped data is read from history instead. Frames for
+// virtual channels and data that has not been committed yet can't be recovered this
+// way, and are lost as they would be if the relay dropped them.
+func (l *streamer) buffer(buffered *[]relay.Response, res relay.Response) {
+	if !l.settled() && len(*buffered) >= l.bufferSize {
+		clear(*buffered)
+		*buffered = (*buffered)[:0]
+		l.catchUp.overflowed = true
+	}
+	*buffered = append(*buffered, res)
+}
+
+// settled returns true if the streamer has no historical data left to read.
+func (l *streamer) settled() bool { return l.catchUp == nil || l.catchUp.settled }
+
 type StreamerConfig struct {
 	Keys             channel.Keys `json:"keys" msgpack:"keys"`
 	DownsampleFactor int          `json:"downsample_factor" msgpack:"downsample_factor"`
+	// Start is an optional timestamp to start streaming from. If set, the streamer
+	// first sends all persisted data for its channels from Start onwards, and then
+	// seamlessly switches to sending live data.
+	Start telem.TimeStamp `json:"start" msgpack:"start"`
+	// Deadbands is an optional set of per-channel filters that suppress samples that
+	// have not changed meaningfully since the last sample sent for the channel.
+	// Deadbands are applied by the service layer streamer, and are ignored by the
+	// distribution layer.
+	Deadbands []Deadband `json:"deadbands" msgpack:"deadbands"`
+	// Scaled sets whether the streamer converts the values of channels that have a
+	// channel.Scale into engineering units before sending them. Scaled is only read
+	// when opening the streamer. The scales of the streamed channels are retrieved
+	// when the streamer is opened and whenever its keys are updated.
+	Scaled bool `json:"scaled" msgpack:"scaled"`
+}
+
+// Deadband configures report-by-exception filtering for a single channel in a
+// streamer. A sample is only forwarded if it differs from the last sample sent for the
+// channel by more than Absolute or by more than Percent of the last sample's magnitude.
+// If both thresholds are zero, a sample is forwarded whenever it changes at all.
+// Thresholds only apply to numeric channels; samples of other data types are forwarded
+// whenever their encoded value changes.
+type Deadband struct {
+	// Channel is the key of the channel to filter.
+	Channel channel.Key `json:"channel" msgpack:"channel"`
+	// Absolute is the minimum absolute difference between a sample and the last sent
+	// sample for the sample to be forwarded.
+	Absolute float64 `json:"absolute" msgpack:"absolute"`
+	// Percent is the minimum difference, as a percentage of the magnitude of the last
+	// sent sample, between a sample and the last sent sample for the sample to be
+	// forwarded.
+	Percent float64 `json:"percent" msgpack:"percent"`
+	// KeepAlive is the maximum amount of time that can pass without a sample being
+	// forwarded for the channel. If no sample has been forwarded within this interval,
+	// the next sample is forwarded regardless of whether it has changed. A zero value
+	// disables keep-alive.
+	KeepAlive telem.TimeSpan `json:"keep_alive" msgpack:"keep_alive"`
+}
+
+// Validate checks that the deadband's thresholds are non-negative.
+func (d Deadband) Validate() error {
+	v := validate.New("framer.deadband")
+	validate.NonNegative(v, "absolute", d.Absolute)
+	validate.NonNegative(v, "percent", d.Percent)
+	validate.NonNegative(v, "keep_alive", d.KeepAlive)
+	return v.Error()
 }
 
 type StreamerRequest = StreamerConfig
@@ -139,8 +547,22 @@ func (s *Service) NewStreamer(ctx context.Context, cfg StreamerConfig) (Streamer
 		ts:                 s.iterator.TS,
 		controlStateKey:    s.controlStateKey,
 		sendControlDigests: lo.Contains(cfg.Keys, s.controlStateKey),
+		channels:           s.config.ChannelReader,
+		bufferSize:         s.config.StreamerBufferSize,
+	}
+	if cfg.Scaled {
+		if err := l.updateScaler(ctx, cfg.Keys); err != nil {
+			return nil, err
+		}
+	}
+	relayKeys := cfg.Keys
+	if !cfg.Start.IsZero() {
+		var err error
+		if relayKeys, err = s.openCatchUp(ctx, l, cfg); err != nil {
+			return nil, err
+		}
 	}
-	rel, err := s.Relay.NewStreamer(ctx, relay.StreamerConfig{Keys: cfg.Keys})
+	rel, err := s.Relay.NewStreamer(ctx, relay.StreamerConfig{Keys: relayKeys})
 	if err != nil {
 		return nil, err
 	}
@@ -152,3 +574,67 @@ func (s *Service) NewStreamer(ctx context.Context, cfg StreamerConfig) (Streamer
 	l.relay.responses = relayRes
 	return l, err
 }
+
+// updateScaler retrieves the scales of the channels with the given keys.
+func (l *streamer) updateScaler(ctx context.Context, keys channel.Keys) error {
+	var channels []channel.Channel
+	if err := l.channels.NewRetrieve().
+		WhereKeys(keys...).
+		Entries(&channels).
+		Exec(ctx, nil); err != nil {
+		return err
+	}
+	l.scaler = core.NewScaler(channels)
+	return nil
+}
+
+// openCatchUp configures the streamer to read historical data from the configured
+// start timestamp, returning the keys the relay needs to subscribe to.
+func (s *Service) openCatchUp(
+	ctx context.Context,
+	l *streamer,
+	cfg StreamerConfig,
+) (channel.Keys, error) {
+	var channels []channel.Channel
+	if err := s.config.ChannelReader.NewRetrieve().
+		WhereKeys(cfg.Keys...).
+		Entries(&channels).
+		Exec(ctx, nil); err != nil {
+		return nil, err
+	}
+	c := &catchUp{
+		keys:      cfg.Keys,
+		indexes:   make(map[channel.Key]channel.Key, len(channels)),
+		highWater: make(map[channel.Key]telem.TimeStamp, len(channels)),
+		start:     cfg.Start,
+	}
+	var iterKeys channel.Keys
+	for _, ch := range channels {
+		// Virtual and free channels have no persisted data to catch up on.
+		if ch.Virtual || ch.Free() {
+			continue
+		}
+		iterKeys = append(iterKeys, ch.Key())
+		if ch.IsIndex {
+			c.indexes[ch.Key()] = ch.Key()
+		} else if idx := ch.Index(); idx != 0 {
+			c.indexes[ch.Key()] = idx
+		}
+	}
+	relayKeys := c.relayKeys(cfg.Keys)
+	if len(iterKeys) == 0 {
+		return relayKeys, nil
+	}
+	iterKeys = c.relayKeys(iterKeys)
+	c.iterKeys = iterKeys
+	c.openIter = func(ctx context.Context, bounds telem.TimeRange) (StreamIterator, error) {
+		return s.NewStreamIterator(ctx, IteratorConfig{Keys: iterKeys, Bounds: bounds})
+	}
+	iter, err := c.openIter(ctx, cfg.Start.Range(telem.TimeStampMax))
+	if err != nil {
+		return nil, err
+	}
+	l.attachIter(iter)
+	l.catchUp = c
+	return relayKeys, nil
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package framer_test
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/distribution/mock"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Streamer", func() {
+	var (
+		idx, data channel.Channel
+		keys      channel.Keys
+	)
+	BeforeEach(func() {
+		prefix := uuid.NewString()[:8]
+		idx = channel.Channel{Name: prefix + "_time", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+		data = channel.Channel{Name: prefix + "_data", DataType: telem.Int64T, LocalIndex: idx.LocalKey}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &data)).To(Succeed())
+		keys = channel.Keys{idx.Key(), data.Key()}
+	})
+	write := func(w *framer.Writer, stamps ...telem.TimeStamp) {
+		values := make([]int64, len(stamps))
+		for i, s := range stamps {
+			values[i] = int64(s)
+		}
+		Expect(w.Write(framer.Frame{
+			Keys:   keys,
+			Series: []telem.Series{telem.NewSecondsTSV(stamps...), telem.NewSeries(values)},
+		})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+	}
+	open := func(cfg framer.StreamerConfig) (
+		confluence.Inlet[framer.StreamerRequest],
+		confluence.Outlet[framer.StreamerResponse],
+		func(),
+	) {
+		s := MustSucceed(dist.Framer.NewStreamer(ctx, cfg))
+		req, res := confluence.Attach(s, 10)
+		sCtx, cancel := signal.Isolated()
+		s.Flow(sCtx, confluence.CloseOutputInletsOnExit())
+		return req, res, func() {
+			req.Close()
+			confluence.Drain(res)
+			Expect(sCtx.Wait()).To(Succeed())
+			cancel()
+		}
+	}
+	Describe("Start", func() {
+		It("Should send historical data before live data", func() {
+			w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Keys:  keys,
+				Start: telem.SecondTS,
+			}))
+			write(w, 1, 2, 3)
+			_, res, closeStreamer := open(framer.StreamerConfig{
+				Keys:  channel.Keys{data.Key()},
+				Start: 2 * telem.SecondTS,
+			})
+			var r framer.StreamerResponse
+			Eventually(res.Outlet()).Should(Receive(&r))
+			Expect(r.Frame.Keys).To(Equal(channel.Keys{data.Key()}))
+			Expect(telem.Unmarshal[int64](r.Frame.Series[0])).To(Equal([]int64{2, 3}))
+			// Give the streamer a moment to finish reading historical data.
+			time.Sleep(10 * time.Millisecond)
+			write(w, 4, 5)
+			Eventually(res.Outlet()).Should(Receive(&r))
+			Expect(r.Frame.Keys).To(Equal(channel.Keys{data.Key()}))
+			Expect(telem.Unmarshal[int64](r.Frame.Series[0])).To(Equal([]int64{4, 5}))
+			Expect(w.Close()).To(Succeed())
+			closeStreamer()
+		})
+		It("Should release historical data once it has been read", func() {
+			w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Keys:  keys,
+				Start: telem.SecondTS,
+			}))
+			write(w, 1, 2, 3)
+			Expect(w.Close()).To(Succeed())
+			_, res, closeStreamer := open(framer.StreamerConfig{
+				Keys:  channel.Keys{data.Key()},
+				Start: telem.SecondTS,
+			})
+			defer closeStreamer()
+			var r framer.StreamerResponse
+			Eventually(res.Outlet()).Should(Receive(&r))
+			Expect(telem.Unmarshal[int64](r.Frame.Series[0])).To(Equal([]int64{1, 2, 3}))
+			// Storage refuses to delete a channel while an iterator is open on it, so
+			// the delete succeeds only if the streamer closed its catch-up iterator
+			// without waiting for live data.
+			Eventually(func() error {
+				return dist.Storage.TS.DeleteChannel(data.Key().StorageKey())
+			}).Should(Succeed())
+		})
+		It("Should not skip or duplicate data written while catching up", func() {
+			w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Keys:  keys,
+				Start: telem.SecondTS,
+			}))
+			const count = 200
+			written, done := make(chan struct{}), make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				for i := 1; i <= count; i++ {
+					write(w, telem.TimeStamp(i))
+					if i == count/4 {
+						close(written)
+					}
+					// The relay drops frames for consumers that fall too far behind,
+					// so pace the writes once the streamer is catching up to keep
+					// the test from depending on how quickly the consumer is scheduled.
+					if i >= count/4 {
+						time.Sleep(time.Millisecond)
+					}
+				}
+			}()
+			<-written
+			_, res, closeStreamer := open(framer.StreamerConfig{
+				Keys:  channel.Keys{data.Key()},
+				Start: telem.SecondTS,
+			})
+			// Close the writer and streamer even if an assertion fails, so that the
+			// suite doesn't hang on shutdown.
+			DeferCleanup(func() {
+				<-done
+				Expect(w.Close()).To(Succeed())
+				closeStreamer()
+			})
+			// Receive frames as soon as they're available, since the relay also drops
+			// frames when the test itself is too slow to consume them.
+			var received []int64
+			for len(received) < count {
+				var r framer.StreamerResponse
+				Eventually(res.Outlet()).WithTimeout(5 * time.Second).Should(Receive(&r))
+				for _, s := range r.Frame.Series {
+					received = append(received, telem.Unmarshal[int64](s)...)
+				}
+			}
+			Expect(received).To(HaveLen(count))
+			for i, v := range received {
+				Expect(v).To(Equal(int64(i + 1)))
+			}
+		})
+		It("Should stream live data from virtual channels", func() {
+			v := channel.Channel{Name: uuid.NewString(), DataType: telem.Int64T, Virtual: true}
+			Expect(dist.Channel.NewWriter(nil).Create(ctx, &v)).To(Succeed())
+			_, res, closeStreamer := open(framer.StreamerConfig{
+				Keys:  channel.Keys{v.Key()},
+				Start: telem.SecondTS,
+			})
+			time.Sleep(10 * time.Millisecond)
+			w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Keys:  channel.Keys{v.Key()},
+				Start: telem.SecondTS,
+			}))
+			Expect(w.Write(framer.Frame{
+				Keys:   channel.Keys{v.Key()},
+				Series: []telem.Series{telem.NewSeriesV[int64](1, 2)},
+			})).To(BeTrue())
+			var r framer.StreamerResponse
+			Eventually(res.Outlet()).Should(Receive(&r))
+			Expect(telem.Unmarshal[int64](r.Frame.Series[0])).To(Equal([]int64{1, 2}))
+			Expect(w.Close()).To(Succeed())
+			closeStreamer()
+		})
+	})
+	Describe("Catch Up Buffer", Ordered, func() {
+		var (
+			b *mock.Builder
+			d distribution.Distribution
+		)
+		BeforeAll(func() {
+			b = mock.NewBuilder()
+			b.Framer = framer.Config{StreamerBufferSize: 2}
+			d = b.New(ctx)
+		})
+		AfterAll(func() {
+			Expect(b.Close()).To(Succeed())
+			Expect(b.Cleanup()).To(Succeed())
+		})
+		It("Should re-read history when live frames overflow the buffer", func() {
+			idx := channel.Channel{Name: "time", DataType: telem.TimeStampT, IsIndex: true}
+			Expect(d.Channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+			data := channel.Channel{Name: "data", DataType: telem.Int64T, LocalIndex: idx.LocalKey}
+			Expect(d.Channel.NewWriter(nil).Create(ctx, &data)).To(Succeed())
+			keys := channel.Keys{idx.Key(), data.Key()}
+			const count = 40
+			writeRange := func(from, to int) {
+				w := MustSucceed(d.Framer.OpenWriter(ctx, framer.WriterConfig{
+					Keys:  keys,
+					Start: telem.TimeStamp(from) * telem.SecondTS,
+				}))
+				for i := from; i < to; i++ {
+					Expect(w.Write(framer.Frame{
+						Keys: keys,
+						Series: []telem.Series{
+							telem.NewSecondsTSV(telem.TimeStamp(i)),
+							telem.NewSeriesV[int64](int64(i)),
+						},
+					})).To(BeTrue())
+					Expect(w.Commit()).To(BeTrue())
+				}
+				Expect(w.Close()).To(Succeed())
+			}
+			for i := 1; i <= count/2; i += 2 {
+				writeRange(i, i+2)
+			}
+			s := MustSucceed(d.Framer.NewStreamer(ctx, framer.StreamerConfig{
+				Keys:  channel.Keys{data.Key()},
+				Start: telem.SecondTS,
+			}))
+			req, res := confluence.Attach(s, 1)
+			sCtx, cancel := signal.Isolated()
+			s.Flow(sCtx, confluence.CloseOutputInletsOnExit())
+			// Don't receive anything until the live data has been written, so that
+			// the streamer has to buffer it while blocked on sending history.
+			Eventually(func() int { return len(res.Outlet()) }).Should(Equal(1))
+			writeRange(count/2+1, count+1)
+			var received []int64
+			for len(received) < count {
+				var r framer.StreamerResponse
+				Eventually(res.Outlet()).Should(Receive(&r))
+				for _, s := range r.Frame.Series {
+					received = append(received, telem.Unmarshal[int64](s)...)
+				}
+			}
+			Expect(received).To(HaveLen(count))
+			for i, v := range received {
+				Expect(v).To(Equal(int64(i + 1)))
+			}
+			req.Close()
+			confluence.Drain(res)
+			Expect(sCtx.Wait()).To(Succeed())
+			cancel()
+		})
+	})
+	Describe("Scaled", func() {
+		var (
+			w     *framer.Writer
+			scale = channel.Scale{Type: channel.ScaleLinear, Slope: 0.5, Offset: 1}
+		)
+		BeforeEach(func() {
+			Expect(dist.Channel.UpdateMetadata(ctx, data.Key(), channel.Metadata{Scale: scale}, false)).To(Succeed())
+			w = MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Keys:  keys,
+				Start: telem.SecondTS,
+			}))
+			DeferCleanup(func() { Expect(w.Close()).To(Succeed()) })
+		})
+		It("Should convert historical and live data into engineering units", func() {
+			write(w, 1, 2)
+			_, res, closeStreamer := open(framer.StreamerConfig{
+				Keys:   keys,
+				Start:  telem.SecondTS,
+				Scaled: true,
+			})
+			var r framer.StreamerResponse
+			Eventually(func(g Gomega) {
+				g.Eventually(res.Outlet()).Should(Receive(&r))
+				g.Expect(r.Frame.Get(data.Key())).To(HaveLen(1))
+			}).Should(Succeed())
+			Expect(r.Frame.Get(data.Key())[0].DataType).To(Equal(telem.Float64T))
+			Expect(telem.Unmarshal[float64](r.Frame.Get(data.Key())[0])).To(Equal([]float64{1.5, 2}))
+			time.Sleep(10 * time.Millisecond)
+			write(w, 4)
+			Eventually(func(g Gomega) {
+				g.Eventually(res.Outlet()).Should(Receive(&r))
+				g.Expect(r.Frame.Get(data.Key())).To(HaveLen(1))
+			}).Should(Succeed())
+			Expect(telem.Unmarshal[float64](r.Frame.Get(data.Key())[0])).To(Equal([]float64{3}))
+			closeStreamer()
+		})
+		It("Should send raw values when not configured to scale", func() {
+			_, res, closeStreamer := open(framer.StreamerConfig{Keys: keys})
+			time.Sleep(10 * time.Millisecond)
+			write(w, 4)
+			var r framer.StreamerResponse
+			Eventually(res.Outlet()).Should(Receive(&r))
+			Expect(telem.Unmarshal[int64](r.Frame.Get(data.Key())[0])).To(Equal([]int64{4}))
+			closeStreamer()
+		})
+		It("Should retrieve the scales of channels added by a request", func() {
+			req, res, closeStreamer := open(framer.StreamerConfig{
+				Keys:   channel.Keys{idx.Key()},
+				Scaled: true,
+			})
+			req.Inlet() <- framer.StreamerRequest{Keys: keys}
+			time.Sleep(10 * time.Millisecond)
+			write(w, 4)
+			var r framer.StreamerResponse
+			Eventually(res.Outlet()).Should(Receive(&r))
+			Expect(telem.Unmarshal[float64](r.Frame.Get(data.Key())[0])).To(Equal([]float64{3}))
+			closeStreamer()
+		})
+	})
+})
@@ -0,0 +1,144 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package framer
+
+import (
+	"context"
+	"sort"
+
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/proxy"
+	"github.com/synnaxlabs/synnax/pkg/storage/ts"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// ChannelUsage is the disk usage of a channel on its leaseholder. Virtual channels do
+// not store any data, so their usage is always zero.
+type ChannelUsage struct {
+	// Channel is the key of the channel.
+	Channel channel.Key `json:"channel" msgpack:"channel"`
+	// Size is the total size of the data files of the channel, including the space
+	// occupied by deleted data.
+	Size telem.Size `json:"size" msgpack:"size"`
+	// Files is the number of data files of the channel.
+	Files int `json:"files" msgpack:"files"`
+	// Reclaimable is the space occupied by deleted data that garbage collection can
+	// return to the filesystem.
+	Reclaimable telem.Size `json:"reclaimable" msgpack:"reclaimable"`
+}
+
+// GCConfig configures a garbage collection of the data files of channels started with
+// Service.GarbageCollect.
+type GCConfig struct {
+	// Keys are the keys of the channels to garbage collect. If empty, every channel
+	// leased by the node is garbage collected.
+	Keys channel.Keys `json:"keys" msgpack:"keys"`
+	// Compact rewrites every file that holds deleted data. If false, only full files
+	// whose deleted data exceeds the garbage collection threshold of the node are
+	// rewritten, in the same way as background garbage collection.
+	Compact bool `json:"compact" msgpack:"compact"`
+}
+
+// GCProgress reports the progress of Service.GarbageCollect after it has finished with
+// a channel.
+type GCProgress struct {
+	// Channel is the key of the channel that was garbage collected.
+	Channel channel.Key `json:"channel" msgpack:"channel"`
+	// Completed is the number of channels that have been garbage collected so far,
+	// including Channel.
+	Completed int `json:"completed" msgpack:"completed"`
+	// Total is the number of channels that will be garbage collected.
+	Total int `json:"total" msgpack:"total"`
+	// Reclaimed is the space returned to the filesystem by garbage collecting Channel.
+	Reclaimed telem.Size `json:"reclaimed" msgpack:"reclaimed"`
+}
+
+// Usage returns the disk usage of each of the given channels, or of every channel
+// leased by the node if no keys are provided. The usages are sorted by channel key.
+// Usage is only reported for channels leased by the node, and an error is returned if
+// any of the channels is leased by a peer.
+func (s *Service) Usage(ctx context.Context, keys ...channel.Key) ([]ChannelUsage, error) {
+	gateway, free, err := s.batchGateway(keys)
+	if err != nil {
+		return nil, err
+	}
+	var tsUsages []ts.ChannelUsage
+	if len(keys) == 0 || len(gateway) > 0 {
+		if tsUsages, err = s.config.TS.Usage(ctx, gateway.Storage()...); err != nil {
+			return nil, err
+		}
+	}
+	usages := make([]ChannelUsage, 0, len(tsUsages)+len(free))
+	for _, u := range tsUsages {
+		usages = append(usages, ChannelUsage{
+			Channel:     channel.Key(u.Channel),
+			Size:        u.Size,
+			Files:       u.Files,
+			Reclaimable: u.Reclaimable,
+		})
+	}
+	for _, key := range free {
+		usages = append(usages, ChannelUsage{Channel: key})
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Channel < usages[j].Channel })
+	return usages, nil
+}
+
+// GarbageCollect rewrites the data files of the channels in cfg.Keys, or of every
+// channel leased by the node if no keys are provided, to return the space occupied by
+// deleted data to the filesystem. Channels are garbage collected one at a time, and
+// onProgress, if not nil, is called after each one. Virtual channels store no data and
+// are ignored. Like Usage, GarbageCollect returns an error if any of the channels is
+// leased by a peer.
+func (s *Service) GarbageCollect(
+	ctx context.Context,
+	cfg GCConfig,
+	onProgress func(GCProgress),
+) error {
+	gateway, _, err := s.batchGateway(cfg.Keys)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Keys) > 0 && len(gateway) == 0 {
+		return nil
+	}
+	mode := ts.GCModeThreshold
+	if cfg.Compact {
+		mode = ts.GCModeCompact
+	}
+	var tsOnProgress func(ts.GCProgress)
+	if onProgress != nil {
+		tsOnProgress = func(p ts.GCProgress) {
+			onProgress(GCProgress{
+				Channel:   channel.Key(p.Channel),
+				Completed: p.Completed,
+				Total:     p.Total,
+				Reclaimed: p.Reclaimed,
+			})
+		}
+	}
+	return s.config.TS.GarbageCollect(ctx, mode, tsOnProgress, gateway.Storage()...)
+}
+
+// batchGateway splits the given keys into those of channels leased by the node and
+// those of free channels, returning an error if any of the channels is leased by a
+// peer.
+func (s *Service) batchGateway(keys channel.Keys) (gateway, free channel.Keys, err error) {
+	batch := proxy.BatchFactory[channel.Key]{Host: s.config.HostResolver.HostKey()}.Batch(keys)
+	for nodeKey, peerKeys := range batch.Peers {
+		return nil, nil, errors.Newf(
+			"channel(s) %s are leased by node %s, and their storage can only be managed on that node",
+			channel.Keys(peerKeys),
+			nodeKey,
+		)
+	}
+	return batch.Gateway, batch.Free, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package framer_test
+
+import (
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Usage", func() {
+	var idx, data channel.Channel
+	BeforeEach(func() {
+		prefix := uuid.NewString()[:8]
+		idx = channel.Channel{Name: prefix + "_time", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+		data = channel.Channel{Name: prefix + "_data", DataType: telem.Int64T, LocalIndex: idx.LocalKey}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &data)).To(Succeed())
+		w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+			Keys:  channel.Keys{idx.Key(), data.Key()},
+			Start: 10 * telem.SecondTS,
+		}))
+		Expect(w.Write(framer.Frame{
+			Keys: channel.Keys{idx.Key(), data.Key()},
+			Series: []telem.Series{
+				telem.NewSecondsTSV(10, 11, 12, 13),
+				telem.NewSeriesV[int64](1, 2, 3, 4),
+			},
+		})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+		Expect(w.Close()).To(Succeed())
+	})
+
+	It("Should report the usage of the given channels", func() {
+		Expect(dist.Framer.Usage(ctx, data.Key(), idx.Key())).To(Equal([]framer.ChannelUsage{
+			{Channel: idx.Key(), Size: 32, Files: 1},
+			{Channel: data.Key(), Size: 32, Files: 1},
+		}))
+	})
+
+	It("Should report the usage of every channel when no keys are given", func() {
+		usages := MustSucceed(dist.Framer.Usage(ctx))
+		Expect(usages).To(ContainElement(framer.ChannelUsage{Channel: data.Key(), Size: 32, Files: 1}))
+	})
+
+	It("Should reclaim deleted data by compacting the channels", func() {
+		Expect(dist.Framer.NewDeleter().DeleteTimeRange(ctx, data.Key(), (10 * telem.SecondTS).Range(12*telem.SecondTS))).To(Succeed())
+		Expect(dist.Framer.Usage(ctx, data.Key())).To(Equal([]framer.ChannelUsage{
+			{Channel: data.Key(), Size: 32, Files: 1, Reclaimable: 16},
+		}))
+		var progress []framer.GCProgress
+		Expect(dist.Framer.GarbageCollect(ctx, framer.GCConfig{
+			Keys:    channel.Keys{data.Key()},
+			Compact: true,
+		}, func(p framer.GCProgress) { progress = append(progress, p) })).To(Succeed())
+		Expect(progress).To(Equal([]framer.GCProgress{
+			{Channel: data.Key(), Completed: 1, Total: 1, Reclaimed: 16},
+		}))
+		Expect(dist.Framer.Usage(ctx, data.Key())).To(Equal([]framer.ChannelUsage{
+			{Channel: data.Key(), Size: 16, Files: 1},
+		}))
+	})
+
+	It("Should report zero usage for free channels", func() {
+		key := channel.NewKey(core.Free, 1)
+		Expect(dist.Framer.Usage(ctx, key)).To(Equal([]framer.ChannelUsage{{Channel: key}}))
+	})
+
+	It("Should return an error for channels leased by a peer", func() {
+		Expect(dist.Framer.Usage(ctx, channel.NewKey(2, 1))).Error().To(MatchError(ContainSubstring("leased by node")))
+		Expect(dist.Framer.GarbageCollect(ctx, framer.GCConfig{Keys: channel.Keys{channel.NewKey(2, 1)}}, nil)).
+			To(MatchError(ContainSubstring("leased by node")))
+	})
+})
@@ -39,6 +39,8 @@ type Builder struct {
 	channelNet *tmock.ChannelNetwork
 	relayNet   *tmock.FramerRelayNetwork
 	deleteNet  *tmock.FramerDeleterNetwork
+	// Framer overrides the configuration of the framer service of each node built.
+	Framer framer.Config
 }
 
 func NewBuilder(cfg ...distribution.Config) *Builder {
@@ -94,7 +96,7 @@ func (b *Builder) New(ctx context.Context) distribution.Distribution {
 		TS:              d.Storage.TS,
 		HostResolver:    d.Cluster,
 		Transport:       trans,
-	}))
+	}, b.Framer))
 
 	d.Signals = lo.Must(signals.New(signals.Config{
 		Instrumentation: d.Instrumentation,
@@ -107,6 +107,7 @@ func (iteratorRequestTranslator) Backward(
 		Stamp:     telem.TimeStamp(req.Stamp),
 		Keys:      channel.KeysFromUint32(req.Keys),
 		ChunkSize: req.ChunkSize,
+		Predicate: req.Predicate,
 	}, nil
 }
 
@@ -122,6 +123,7 @@ func (iteratorRequestTranslator) Forward(
 		Stamp:     int64(req.Stamp),
 		Keys:      req.Keys.Uint32(),
 		ChunkSize: req.ChunkSize,
+		Predicate: req.Predicate,
 	}, nil
 }
 
@@ -140,6 +142,7 @@ func (iteratorResponseTranslator) Backward(
 		Command: iterator.Command(res.Command),
 		Error:   fgrpc.DecodeError(ctx, res.Error),
 		Frame:   translateFrameForward(res.Frame),
+		Ranges:  telem.TranslateManyTimeRangesBackward(res.Ranges),
 	}, nil
 }
 
@@ -156,6 +159,7 @@ func (iteratorResponseTranslator) Forward(
 		Command: int32(res.Command),
 		Error:   fgrpc.EncodeError(ctx, res.Error, true),
 		Frame:   translateFrameBackward(res.Frame),
+		Ranges:  telem.TranslateManyTimeRangesForward(res.Ranges),
 	}, nil
 }
 
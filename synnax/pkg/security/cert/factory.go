@@ -160,6 +160,50 @@ func (c *Factory) CreateNodePairIfMissing() error {
 
 // CreateNodePair creates a new node certificate and its private key.
 func (c *Factory) CreateNodePair() error {
+	return c.createNodePair(c.NodeKeyPath, c.NodeCertPath)
+}
+
+// RotateNodePair issues a new node certificate and private key from the existing CA,
+// replacing the current node pair. If no hosts are provided in the factory
+// configuration, the hosts of the current node certificate are used. The new pair is
+// written to temporary files before being renamed over the current pair, so readers
+// never observe a partially written file.
+func (c *Factory) RotateNodePair() error {
+	if len(c.Hosts) == 0 {
+		current, _, err := c.Loader.LoadNodePair()
+		if err != nil {
+			return err
+		}
+		for _, name := range current.DNSNames {
+			c.Hosts = append(c.Hosts, address.Address(name))
+		}
+		for _, ip := range current.IPAddresses {
+			c.Hosts = append(c.Hosts, address.Address(ip.String()))
+		}
+	}
+	var (
+		keyTmp  = c.NodeKeyPath + rotateSuffix
+		certTmp = c.NodeCertPath + rotateSuffix
+	)
+	for _, p := range []string{keyTmp, certTmp} {
+		if err := c.FS.Remove(p); err != nil {
+			return err
+		}
+	}
+	if err := c.createNodePair(keyTmp, certTmp); err != nil {
+		return err
+	}
+	if err := c.FS.Rename(keyTmp, c.NodeKeyPath); err != nil {
+		return err
+	}
+	return c.FS.Rename(certTmp, c.NodeCertPath)
+}
+
+// rotateSuffix is appended to the paths of the node key and certificate while they are
+// being rotated.
+const rotateSuffix = ".rotate"
+
+func (c *Factory) createNodePair(keyPath, certPath string) error {
 	ca, caPrivate, err := c.Loader.LoadCAPair()
 	if err != nil {
 		return err
@@ -178,7 +222,7 @@ func (c *Factory) CreateNodePair() error {
 	if err != nil {
 		return err
 	}
-	if err = c.writePEM(c.NodeKeyPath, keyP, false); err != nil {
+	if err = c.writePEM(keyPath, keyP, false); err != nil {
 		return err
 	}
 
@@ -201,7 +245,7 @@ func (c *Factory) CreateNodePair() error {
 		return err
 	}
 
-	return c.writePEM(c.NodeCertPath, xpem.FromCertBytes(b) /* multi */, false)
+	return c.writePEM(certPath, xpem.FromCertBytes(b) /* multi */, false)
 }
 
 func (c *Factory) readPEM(p string) (b *pem.Block, err error) {
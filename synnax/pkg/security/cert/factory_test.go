@@ -93,4 +93,36 @@ var _ = Describe("Factory", func() {
 			Expect(err.Error()).To(ContainSubstring("no hosts provided"))
 		})
 	})
+	Describe("Node Rotation", func() {
+		It("Should replace the node pair with a new pair for the same hosts", func() {
+			f := MustSucceed(cert.NewFactory(cert.FactoryConfig{
+				LoaderConfig: cert.LoaderConfig{FS: fs},
+				Hosts:        []address.Address{"synnaxlabs.com", "127.0.0.1:9090"},
+				KeySize:      mock.SmallKeySize,
+			}))
+			Expect(f.CreateCAPair()).To(Succeed())
+			Expect(f.CreateNodePair()).To(Succeed())
+			prev := MustSucceed(f.Loader.LoadNodeTLS())
+			rotator := MustSucceed(cert.NewFactory(cert.FactoryConfig{
+				LoaderConfig: cert.LoaderConfig{FS: fs},
+				KeySize:      mock.SmallKeySize,
+			}))
+			Expect(rotator.RotateNodePair()).To(Succeed())
+			next := MustSucceed(rotator.Loader.LoadNodeTLS())
+			Expect(next.Certificate[0]).ToNot(Equal(prev.Certificate[0]))
+			c, _ := MustSucceed2(rotator.Loader.LoadNodePair())
+			Expect(c.DNSNames).To(Equal([]string{"synnaxlabs.com"}))
+			Expect(c.IPAddresses).To(HaveLen(1))
+			Expect(c.IPAddresses[0].String()).To(Equal("127.0.0.1"))
+			Expect(fs.Exists("node.key.rotate")).To(BeFalse())
+		})
+		It("Should fail if there is no node pair to rotate and no hosts are provided", func() {
+			f := MustSucceed(cert.NewFactory(cert.FactoryConfig{
+				LoaderConfig: cert.LoaderConfig{FS: fs},
+				KeySize:      mock.SmallKeySize,
+			}))
+			Expect(f.CreateCAPair()).To(Succeed())
+			Expect(f.RotateNodePair()).To(MatchError(ContainSubstring("node certificate not found")))
+		})
+	})
 })
@@ -10,10 +10,12 @@
 package security
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
+	"time"
 )
 
 // insecureProvider is an implementation of Provider for use in insecure clusters.
@@ -31,3 +33,14 @@ func (p *insecureProvider) TLS() *tls.Config { return nil }
 
 // NodePrivate implements KeyProvider.
 func (p *insecureProvider) NodePrivate() crypto.PrivateKey { return p.nodeSecret }
+
+// RetiredNodePrivate implements KeyProvider. The key of an insecure provider is never
+// replaced, so there are no retired keys.
+func (p *insecureProvider) RetiredNodePrivate() []RetiredKey { return nil }
+
+// Reload implements Reloader. Insecure providers have no TLS material to reload.
+func (p *insecureProvider) Reload() error { return nil }
+
+// Watch implements Reloader. Insecure providers have no certificates to watch, so
+// Watch returns immediately.
+func (p *insecureProvider) Watch(context.Context, time.Duration) error { return nil }
@@ -12,11 +12,19 @@ package mock
 import (
 	"crypto"
 	"crypto/rsa"
+
+	"github.com/synnaxlabs/synnax/pkg/security"
 )
 
 // KeyProvider is a mock implementation of security.KeyProvider
 // that wraps an RSA private key.
-type KeyProvider struct{ Key *rsa.PrivateKey }
+type KeyProvider struct {
+	Key     *rsa.PrivateKey
+	Retired []security.RetiredKey
+}
 
 // NodePrivate implements security.KeyProvider.
 func (m KeyProvider) NodePrivate() crypto.PrivateKey { return m.Key }
+
+// RetiredNodePrivate implements security.KeyProvider.
+func (m KeyProvider) RetiredNodePrivate() []security.RetiredKey { return m.Retired }
@@ -10,6 +10,7 @@
 package security
 
 import (
+	"context"
 	"crypto"
 	"crypto/tls"
 	"github.com/samber/lo"
@@ -17,6 +18,7 @@ import (
 	"github.com/synnaxlabs/x/config"
 	"github.com/synnaxlabs/x/override"
 	"github.com/synnaxlabs/x/validate"
+	"time"
 )
 
 // TLSProvider provides the node's TLS configuration for services that require it.
@@ -32,6 +34,37 @@ type TLSProvider interface {
 type KeyProvider interface {
 	// NodePrivate returns the private key of the node's TLS certificate.
 	NodePrivate() crypto.PrivateKey
+	// RetiredNodePrivate returns the private keys that were replaced by reloads of the
+	// node's TLS material within the retired key TTL, most recently retired first.
+	// Signatures made with a retired key before it was retired can still be verified
+	// with it.
+	RetiredNodePrivate() []RetiredKey
+}
+
+// RetiredKey is a private key that was replaced by a reload of the node's TLS
+// material.
+type RetiredKey struct {
+	// Key is the retired private key.
+	Key crypto.PrivateKey
+	// RetiredAt is the time at which the key was replaced.
+	RetiredAt time.Time
+}
+
+// Reloader reloads the node's TLS material while the node is running. Connections
+// opened after a reload use the new material, while existing connections are left
+// untouched.
+type Reloader interface {
+	// Reload reloads the node's certificate and private key from disk. If the new
+	// material fails to load, the provider continues to use the previous material. The
+	// CA certificates are only loaded once when the provider is opened, so rotating the
+	// CA requires restarting the node. If the reload replaces the node's private key,
+	// the previous key is retired and remains available through RetiredNodePrivate for
+	// RetiredKeyTTL so that session tokens signed with it stay valid until they expire.
+	Reload() error
+	// Watch polls the node's certificate and key files at the provided interval,
+	// calling Reload whenever they change. Watch blocks until the provided context is
+	// cancelled.
+	Watch(ctx context.Context, interval time.Duration) error
 }
 
 // Provider provides security information and services for the node. It's important to note
@@ -40,6 +73,7 @@ type KeyProvider interface {
 type Provider interface {
 	TLSProvider
 	KeyProvider
+	Reloader
 }
 
 // ProviderConfig is the configuration for creating a new Provider.
@@ -49,15 +83,20 @@ type ProviderConfig struct {
 	Insecure *bool
 	// KeySize is the size of private key to use in case key generation is required.
 	KeySize int
+	// RetiredKeyTTL is the duration for which a key replaced by a reload remains
+	// available through RetiredNodePrivate. It should be at least the lifetime of
+	// anything signed with the node's key.
+	RetiredKeyTTL time.Duration
 }
 
 var (
 	_ config.Config[ProviderConfig] = ProviderConfig{}
 	// DefaultServiceConfig is the default configuration for the security secureProvider.
 	DefaultServiceConfig = ProviderConfig{
-		LoaderConfig: cert.DefaultLoaderConfig,
-		Insecure:     config.Bool(true),
-		KeySize:      cert.DefaultFactoryConfig.KeySize,
+		LoaderConfig:  cert.DefaultLoaderConfig,
+		Insecure:      config.Bool(true),
+		KeySize:       cert.DefaultFactoryConfig.KeySize,
+		RetiredKeyTTL: 24 * time.Hour,
 	}
 )
 
@@ -65,6 +104,7 @@ var (
 func (s ProviderConfig) Override(other ProviderConfig) ProviderConfig {
 	s.LoaderConfig = s.LoaderConfig.Override(other.LoaderConfig)
 	s.Insecure = override.Nil(s.Insecure, other.Insecure)
+	s.RetiredKeyTTL = override.Numeric(s.RetiredKeyTTL, other.RetiredKeyTTL)
 	return s
 }
 
@@ -72,6 +112,7 @@ func (s ProviderConfig) Override(other ProviderConfig) ProviderConfig {
 func (s ProviderConfig) Validate() error {
 	v := validate.New("security.OtelProvider")
 	validate.NotNil(v, "Insecure", s.Insecure)
+	validate.Positive(v, "RetiredKeyTTL", s.RetiredKeyTTL)
 	v.Exec(s.LoaderConfig.Validate)
 	return v.Error()
 }
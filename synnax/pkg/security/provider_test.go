@@ -10,6 +10,7 @@
 package security_test
 
 import (
+	"context"
 	"crypto/tls"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -20,6 +21,7 @@ import (
 	xfs "github.com/synnaxlabs/x/io/fs"
 	. "github.com/synnaxlabs/x/testutil"
 	"os"
+	"time"
 )
 
 var _ = Describe("OtelProvider", func() {
@@ -56,6 +58,79 @@ var _ = Describe("OtelProvider", func() {
 				Expect(err).To(HaveOccurredAs(os.ErrNotExist))
 			})
 		})
+		Describe("Reload", func() {
+			var (
+				fs   xfs.FS
+				prov security.Provider
+			)
+			BeforeEach(func() {
+				fs = xfs.NewMem()
+				mock.GenerateCerts(fs)
+				prov = MustSucceed(security.NewProvider(security.ProviderConfig{
+					LoaderConfig: cert.LoaderConfig{FS: fs},
+					KeySize:      mock.SmallKeySize,
+					Insecure:     config.Bool(false),
+				}))
+			})
+			rotate := func() {
+				f := MustSucceed(cert.NewFactory(cert.FactoryConfig{
+					LoaderConfig: cert.LoaderConfig{FS: fs},
+					KeySize:      mock.SmallKeySize,
+				}))
+				Expect(f.RotateNodePair()).To(Succeed())
+			}
+			getCert := func(cfg *tls.Config) []byte {
+				return MustSucceed(cfg.GetCertificate(&tls.ClientHelloInfo{})).Certificate[0]
+			}
+			It("Should swap in the new certificate for existing TLS configurations", func() {
+				cfg := prov.TLS()
+				prev := getCert(cfg)
+				prevKey := prov.NodePrivate()
+				rotate()
+				Expect(getCert(cfg)).To(Equal(prev))
+				Expect(prov.Reload()).To(Succeed())
+				Expect(getCert(cfg)).ToNot(Equal(prev))
+				Expect(prov.NodePrivate()).ToNot(Equal(prevKey))
+				retired := prov.RetiredNodePrivate()
+				Expect(retired).To(HaveLen(1))
+				Expect(retired[0].Key).To(Equal(prevKey))
+			})
+			It("Should drop retired keys after the retired key TTL", func() {
+				prov = MustSucceed(security.NewProvider(security.ProviderConfig{
+					LoaderConfig:  cert.LoaderConfig{FS: fs},
+					KeySize:       mock.SmallKeySize,
+					Insecure:      config.Bool(false),
+					RetiredKeyTTL: 50 * time.Millisecond,
+				}))
+				rotate()
+				Expect(prov.Reload()).To(Succeed())
+				Expect(prov.RetiredNodePrivate()).To(HaveLen(1))
+				Eventually(prov.RetiredNodePrivate).Should(BeEmpty())
+			})
+			It("Should not retire the key if a reload does not change it", func() {
+				Expect(prov.Reload()).To(Succeed())
+				Expect(prov.RetiredNodePrivate()).To(BeEmpty())
+			})
+			It("Should keep the previous certificate if the new one fails to load", func() {
+				cfg := prov.TLS()
+				prev := getCert(cfg)
+				certs := MustSucceed(fs.Sub(cert.DefaultLoaderConfig.CertsDir))
+				Expect(certs.Remove("node.crt")).To(Succeed())
+				Expect(prov.Reload()).ToNot(Succeed())
+				Expect(getCert(cfg)).To(Equal(prev))
+			})
+			It("Should reload the certificate when the files change", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan error)
+				go func() { done <- prov.Watch(ctx, 5*time.Millisecond) }()
+				cfg := prov.TLS()
+				prev := getCert(cfg)
+				rotate()
+				Eventually(func() []byte { return getCert(cfg) }).ShouldNot(Equal(prev))
+				cancel()
+				Eventually(done).Should(Receive(BeNil()))
+			})
+		})
 		Describe("Node Private", func() {
 			It("Should return the node private key", func() {
 				fs := xfs.NewMem()
@@ -10,22 +10,40 @@
 package security
 
 import (
+	"context"
 	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 	"github.com/synnaxlabs/synnax/pkg/security/cert"
 	"github.com/synnaxlabs/x/errors"
+	"go.uber.org/zap"
 	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // secureProvider implements the Provider interface for use in a secure cluster.
 type secureProvider struct {
 	ProviderConfig
 	loader   *cert.Loader
-	tls      *tls.Certificate
+	tls      atomic.Pointer[tls.Certificate]
 	certPool *x509.CertPool
+	// reloadMu serializes calls to Reload.
+	reloadMu sync.Mutex
+	// certState is the state of the node's certificate files when the current TLS
+	// material was loaded. See certFileState.
+	certState string
+	// retired holds the keys replaced by calls to Reload, most recently retired
+	// first. The slice is replaced rather than modified so that it can be read
+	// without holding reloadMu.
+	retired atomic.Pointer[[]RetiredKey]
 }
 
+// maxRetiredKeys is the maximum number of retired keys kept by a secureProvider.
+const maxRetiredKeys = 8
+
 func newSecureProvider(cfg ProviderConfig) (Provider, error) {
 	l, err := cert.NewLoader(cfg.LoaderConfig)
 	if err != nil {
@@ -39,10 +57,12 @@ func newSecureProvider(cfg ProviderConfig) (Provider, error) {
 	for _, ca := range cas {
 		p.certPool.AddCert(ca)
 	}
-	p.tls, err = l.LoadNodeTLS()
+	p.certState = p.certFileState()
+	c, err := l.LoadNodeTLS()
 	if err != nil {
 		return nil, err
 	}
+	p.tls.Store(c)
 	return p, nil
 }
 
@@ -82,12 +102,100 @@ func (p *secureProvider) TLS() *tls.Config {
 }
 
 // NodePrivate implements KeyProvider.
-func (p *secureProvider) NodePrivate() crypto.PrivateKey { return p.tls.PrivateKey }
+func (p *secureProvider) NodePrivate() crypto.PrivateKey { return p.tls.Load().PrivateKey }
+
+// RetiredNodePrivate implements KeyProvider.
+func (p *secureProvider) RetiredNodePrivate() []RetiredKey {
+	r := p.retired.Load()
+	if r == nil {
+		return nil
+	}
+	// Keys are ordered by the time they were retired, so every key after the first
+	// one to outlive the TTL has outlived it too.
+	for i, k := range *r {
+		if time.Since(k.RetiredAt) >= p.RetiredKeyTTL {
+			return (*r)[:i:i]
+		}
+	}
+	return *r
+}
+
+// Reload implements Reloader.
+func (p *secureProvider) Reload() error {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	state := p.certFileState()
+	c, err := p.loader.LoadNodeTLS()
+	if err != nil {
+		return err
+	}
+	prev := p.tls.Swap(c)
+	if k, ok := prev.PrivateKey.(interface{ Equal(crypto.PrivateKey) bool }); !ok || !k.Equal(c.PrivateKey) {
+		p.retire(prev.PrivateKey)
+	}
+	p.certState = state
+	p.L.Info("reloaded node certificate")
+	return nil
+}
+
+// Watch implements Reloader.
+func (p *secureProvider) Watch(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		p.reloadMu.Lock()
+		changed := p.certFileState() != p.certState
+		p.reloadMu.Unlock()
+		if !changed {
+			continue
+		}
+		// The key and certificate may be replaced in separate operations, so a failed
+		// reload is retried on the next tick.
+		if err := p.Reload(); err != nil {
+			p.L.Warn("failed to reload node certificate", zap.Error(err))
+		}
+	}
+}
+
+// retire adds the provided key to the front of the list of retired keys, dropping the
+// keys that have outlived the retired key TTL and the oldest key if the list is full.
+// retire must be called with reloadMu held.
+func (p *secureProvider) retire(key crypto.PrivateKey) {
+	retired := append(
+		[]RetiredKey{{Key: key, RetiredAt: time.Now()}},
+		p.RetiredNodePrivate()...,
+	)
+	if len(retired) > maxRetiredKeys {
+		retired = retired[:maxRetiredKeys]
+	}
+	p.retired.Store(&retired)
+}
+
+// certFileState returns a string that changes whenever the node's key or certificate
+// files are modified.
+func (p *secureProvider) certFileState() string {
+	var state string
+	for _, path := range []string{p.loader.NodeCertPath, p.loader.NodeKeyPath} {
+		info, err := p.loader.FS.Stat(path)
+		if err != nil {
+			state += path + ":missing;"
+			continue
+		}
+		state += path + ":" + info.ModTime().String() + ":" +
+			strconv.FormatInt(info.Size(), 10) + ";"
+	}
+	return state
+}
 
-func (p *secureProvider) getClientCert(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
-	return p.tls, nil
+func (p *secureProvider) getClientCert(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return p.tls.Load(), nil
 }
 
-func (p *secureProvider) getCert(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
-	return p.tls, nil
+func (p *secureProvider) getCert(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return p.tls.Load(), nil
 }
@@ -11,6 +11,8 @@ package rbac
 
 import (
 	"context"
+
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/synnax/pkg/service/access"
 )
 
@@ -18,12 +20,29 @@ var _ access.Enforcer = (*Service)(nil)
 
 // Enforce implements the access.Enforcer interface.
 func (s *Service) Enforce(ctx context.Context, req access.Request) error {
+	subjects, err := s.expandSubject(ctx, req.Subject)
+	if err != nil {
+		return err
+	}
 	var policies []Policy
-	if err := s.NewRetriever().Entries(&policies).WhereSubjects(req.Subject).Exec(ctx, s.DB); err != nil {
+	if err = s.NewRetriever().Entries(&policies).WhereSubjects(subjects...).Exec(ctx, s.DB); err != nil {
 		return err
 	}
-	if allowRequest(req, policies) {
+	if allowRequest(req, subjects, policies) {
 		return access.Granted
 	}
 	return access.Denied
 }
+
+// expandSubject returns the given subject along with all roles it is a direct or
+// transitive member of. Policies that target any of these roles apply to the subject.
+func (s *Service) expandSubject(ctx context.Context, subject ontology.ID) ([]ontology.ID, error) {
+	if s.Role == nil {
+		return []ontology.ID{subject}, nil
+	}
+	roles, err := s.Role.RetrieveIDsFor(ctx, subject, true, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	return append([]ontology.ID{subject}, roles...), nil
+}
@@ -14,8 +14,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
 	"github.com/synnaxlabs/synnax/pkg/service/access"
 	"github.com/synnaxlabs/synnax/pkg/service/access/rbac"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
 	"github.com/synnaxlabs/synnax/pkg/service/user"
 	"github.com/synnaxlabs/x/gorp"
 	"github.com/synnaxlabs/x/kv/memkv"
@@ -224,4 +226,59 @@ var _ = Describe("enforcer", func() {
 			})).To(Succeed())
 		})
 	})
+
+	Describe("Enforce - roles", func() {
+		var (
+			otg       *ontology.Ontology
+			roleSvc   *role.Service
+			operators role.Role
+			engineers role.Role
+			member    ontology.ID
+			object    = ontology.ID{Type: "label", Key: "roles"}
+		)
+		BeforeEach(func() {
+			otg = MustSucceed(ontology.Open(ctx, ontology.Config{DB: db}))
+			g := MustSucceed(group.OpenService(group.Config{DB: db, Ontology: otg}))
+			userSvc := MustSucceed(user.NewService(ctx, user.Config{DB: db, Ontology: otg, Group: g}))
+			roleSvc = MustSucceed(role.OpenService(ctx, role.Config{DB: db, Ontology: otg, Group: g}))
+			svc = MustSucceed(rbac.NewService(rbac.Config{DB: db, Role: roleSvc}))
+			u := user.User{Username: "member"}
+			Expect(userSvc.NewWriter(nil).Create(ctx, &u)).To(Succeed())
+			member = user.OntologyID(u.Key)
+			operators, engineers = role.Role{Name: "Operators"}, role.Role{Name: "Engineers"}
+			w := roleSvc.NewWriter(nil)
+			Expect(w.Create(ctx, &operators)).To(Succeed())
+			Expect(w.Create(ctx, &engineers)).To(Succeed())
+			Expect(writer.Create(ctx, &rbac.Policy{
+				Subjects: []ontology.ID{engineers.OntologyID()},
+				Objects:  []ontology.ID{object},
+				Actions:  []access.Action{access.Retrieve},
+			})).To(Succeed())
+		})
+		AfterEach(func() {
+			Expect(otg.Close()).To(Succeed())
+		})
+		req := func() access.Request {
+			return access.Request{Subject: member, Objects: []ontology.ID{object}, Action: access.Retrieve}
+		}
+		It("Should deny a subject that is not a member of the role", func() {
+			Expect(svc.Enforce(ctx, req())).To(Equal(access.Denied))
+		})
+		It("Should allow a subject that is a member of the role", func() {
+			Expect(roleSvc.NewWriter(nil).AddMembers(ctx, engineers.Key, member)).To(Succeed())
+			Expect(svc.Enforce(ctx, req())).To(Succeed())
+		})
+		It("Should allow a subject that inherits the role through another role", func() {
+			w := roleSvc.NewWriter(nil)
+			Expect(w.AddMembers(ctx, operators.Key, member)).To(Succeed())
+			Expect(w.AddMembers(ctx, engineers.Key, operators.OntologyID())).To(Succeed())
+			Expect(svc.Enforce(ctx, req())).To(Succeed())
+		})
+		It("Should deny a subject after it is removed from the role", func() {
+			w := roleSvc.NewWriter(nil)
+			Expect(w.AddMembers(ctx, engineers.Key, member)).To(Succeed())
+			Expect(w.RemoveMembers(ctx, engineers.Key, member)).To(Succeed())
+			Expect(svc.Enforce(ctx, req())).To(Equal(access.Denied))
+		})
+	})
 })
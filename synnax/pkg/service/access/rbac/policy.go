@@ -41,33 +41,25 @@ func (p Policy) GorpKey() uuid.UUID { return p.Key }
 // SetOptions implements the gorp.Entry interface.
 func (p Policy) SetOptions() []interface{} { return nil }
 
-// allowRequest returns true if the policies allow the given access.Request.
+// allowRequest returns true if the policies allow the given access.Request on behalf
+// of the given subjects, which are the request's subject along with any roles it is a
+// member of.
 //
 // For a request to be allowed:
-//   - The request's subject must have object-action pairs for each object specified in
+//   - One of the subjects must have object-action pairs for each object specified in
 //     the request for the action specified in the request.
 //   - An object-action pair is a pair with the specified action in the request and an
 //     object that is either a type object with the correct type, or an object that
 //     exactly matches one of the requested objects.
-func allowRequest(req access.Request, policies []Policy) bool {
+func allowRequest(req access.Request, subjects []ontology.ID, policies []Policy) bool {
 	requestedObjects := make(map[ontology.ID]struct{})
 	for _, o := range req.Objects {
 		requestedObjects[o] = struct{}{}
 	}
 
 	for _, policy := range policies {
-		if !lo.Contains(policy.Subjects, req.Subject) {
-			// Policy not directly pertaining to requested subject.
-			var shouldContinue bool = true
-			for _, s := range policy.Subjects {
-				if s.IsType() && s.Type == req.Subject.Type {
-					shouldContinue = false
-					break
-				}
-			}
-			if shouldContinue {
-				continue
-			}
+		if !appliesTo(policy, subjects) {
+			continue
 		}
 		if policy.Actions != nil && !lo.Contains(policy.Actions, req.Action) && !lo.Contains(policy.Actions, access.All) {
 			// If the requested action is not described by the current policy, skip the
@@ -100,3 +92,16 @@ func allowRequest(req access.Request, policies []Policy) bool {
 
 	return len(requestedObjects) == 0
 }
+
+// appliesTo returns true if any of the policy's subjects is one of the given subjects,
+// or is a type subject matching the type of one of the given subjects.
+func appliesTo(policy Policy, subjects []ontology.ID) bool {
+	for _, ps := range policy.Subjects {
+		for _, s := range subjects {
+			if ps == s || (ps.IsType() && ps.Type == s.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -11,6 +11,7 @@ package rbac
 
 import (
 	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
 	"github.com/synnaxlabs/x/config"
 	"github.com/synnaxlabs/x/gorp"
 	"github.com/synnaxlabs/x/override"
@@ -19,6 +20,10 @@ import (
 
 type Config struct {
 	DB *gorp.DB
+	// Role is used to expand the subject of a request into the roles it is a member
+	// of, so that policies targeting those roles apply to the subject.
+	// [OPTIONAL] - If nil, policies only apply to the subjects they name directly.
+	Role *role.Service
 }
 
 var (
@@ -29,6 +34,7 @@ var (
 // Override implements [config.Config].
 func (c Config) Override(other Config) Config {
 	c.DB = override.Nil(c.DB, other.DB)
+	c.Role = override.Nil(c.Role, other.Role)
 	return c
 }
 
@@ -0,0 +1,289 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package alarm implements server-side limit checking on channels. Alarms are rules
+// stored in the cluster that define warning and critical bounds, rate-of-change limits,
+// and stale-data limits for a channel. The alarm service evaluates these rules against
+// live data from the relay, persists the state of each alarm, and writes a history of
+// alarm state transitions to internal channels.
+package alarm
+
+import (
+	"strconv"
+
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Key is a unique identifier for an alarm. The first 16 bits of the key are the key of
+// the node that created the alarm, and are used to determine which node evaluates it.
+// The last 16 bits are a node-local counter.
+type Key uint32
+
+// NewKey creates a new alarm key from the given node key and node-local key.
+func NewKey(node core.NodeKey, localKey uint16) Key {
+	return Key(uint32(node)<<16 | uint32(localKey))
+}
+
+// Node returns the key of the node that evaluates the alarm.
+func (k Key) Node() core.NodeKey { return core.NodeKey(k >> 16) }
+
+// LocalKey returns the node-local key of the alarm.
+func (k Key) LocalKey() uint16 { return uint16(uint32(k) & 0xFFFF) }
+
+// IsValid returns true if the key has a non-zero node and local key.
+func (k Key) IsValid() bool { return k.Node() != 0 && k.LocalKey() != 0 }
+
+// String implements fmt.Stringer.
+func (k Key) String() string { return strconv.Itoa(int(k)) }
+
+// Severity is the severity of an alarm condition. Higher severities are more severe.
+type Severity uint8
+
+const (
+	// SeverityNone indicates that no alarm condition is present.
+	SeverityNone Severity = iota
+	// SeverityWarning indicates that a warning condition is present.
+	SeverityWarning
+	// SeverityCritical indicates that a critical condition is present.
+	SeverityCritical
+)
+
+// Condition describes the cause of an alarm.
+type Condition string
+
+const (
+	// ConditionLow means that the value of the channel is below a lower bound.
+	ConditionLow Condition = "low"
+	// ConditionHigh means that the value of the channel is above an upper bound.
+	ConditionHigh Condition = "high"
+	// ConditionRate means that the channel is changing faster than its rate limit.
+	ConditionRate Condition = "rate"
+	// ConditionStale means that the channel has not received data within its stale
+	// timeout.
+	ConditionStale Condition = "stale"
+)
+
+// Bounds are the inclusive lower and upper bounds of the allowed values of a channel.
+type Bounds struct {
+	Low  float64 `json:"low" msgpack:"low"`
+	High float64 `json:"high" msgpack:"high"`
+}
+
+// check returns the condition violated by the given value, if any.
+func (b *Bounds) check(v float64) (Condition, bool) {
+	if b == nil {
+		return "", false
+	}
+	if v < b.Low {
+		return ConditionLow, true
+	}
+	if v > b.High {
+		return ConditionHigh, true
+	}
+	return "", false
+}
+
+// RateLimit limits the rate of change of a channel.
+type RateLimit struct {
+	// Max is the maximum allowed absolute rate of change of the channel, in units per
+	// second.
+	Max float64 `json:"max" msgpack:"max"`
+	// Severity is the severity of the alarm when the limit is exceeded.
+	Severity Severity `json:"severity" msgpack:"severity"`
+}
+
+// StaleLimit limits the amount of time a channel can go without receiving data.
+type StaleLimit struct {
+	// Timeout is the maximum amount of time between samples.
+	Timeout telem.TimeSpan `json:"timeout" msgpack:"timeout"`
+	// Severity is the severity of the alarm when the limit is exceeded.
+	Severity Severity `json:"severity" msgpack:"severity"`
+}
+
+// Alarm is a rule that checks the values of a channel against a set of limits.
+type Alarm struct {
+	// Key is a unique identifier for the alarm. Assigned by the cluster on creation.
+	Key Key `json:"key" msgpack:"key"`
+	// Name is a human-readable name for the alarm.
+	Name string `json:"name" msgpack:"name"`
+	// Channel is the key of the channel the alarm checks. The channel must have a
+	// numeric data type.
+	Channel channel.Key `json:"channel" msgpack:"channel"`
+	// Warning are the bounds outside which a warning is raised.
+	Warning *Bounds `json:"warning" msgpack:"warning"`
+	// Critical are the bounds outside which a critical alarm is raised.
+	Critical *Bounds `json:"critical" msgpack:"critical"`
+	// Rate is an optional limit on the rate of change of the channel.
+	Rate *RateLimit `json:"rate" msgpack:"rate"`
+	// Stale is an optional limit on the time between samples of the channel.
+	Stale *StaleLimit `json:"stale" msgpack:"stale"`
+}
+
+var _ gorp.Entry[Key] = Alarm{}
+
+// GorpKey implements gorp.Entry.
+func (a Alarm) GorpKey() Key { return a.Key }
+
+// SetOptions implements gorp.Entry.
+func (a Alarm) SetOptions() []interface{} { return []interface{}{a.Key.Node()} }
+
+// Validate checks that the alarm is well-formed.
+func (a Alarm) Validate() error {
+	v := validate.New("alarm")
+	validate.NotEmptyString(v, "name", a.Name)
+	validate.NonZero(v, "channel", a.Channel)
+	for field, b := range map[string]*Bounds{"warning": a.Warning, "critical": a.Critical} {
+		if b != nil {
+			v.Ternary(field, b.Low > b.High, "low must be less than or equal to high")
+		}
+	}
+	if a.Rate != nil {
+		validate.Positive(v, "rate.max", a.Rate.Max)
+		v.Ternary("rate.severity", !a.Rate.Severity.valid(), "must be a warning or critical severity")
+	}
+	if a.Stale != nil {
+		validate.Positive(v, "stale.timeout", a.Stale.Timeout)
+		v.Ternary("stale.severity", !a.Stale.Severity.valid(), "must be a warning or critical severity")
+	}
+	v.Ternary(
+		"limits",
+		a.Warning == nil && a.Critical == nil && a.Rate == nil && a.Stale == nil,
+		"at least one limit must be provided",
+	)
+	return v.Error()
+}
+
+func (s Severity) valid() bool { return s == SeverityWarning || s == SeverityCritical }
+
+// check evaluates the value limits of the alarm, returning the most severe violated
+// condition. rate is only checked if hasRate is true.
+func (a Alarm) check(value, rate float64, hasRate bool) (Severity, Condition) {
+	if c, ok := a.Critical.check(value); ok {
+		return SeverityCritical, c
+	}
+	var (
+		sev  Severity
+		cond Condition
+	)
+	if c, ok := a.Warning.check(value); ok {
+		sev, cond = SeverityWarning, c
+	}
+	if hasRate && a.Rate != nil && a.Rate.Severity > sev && abs(rate) > a.Rate.Max {
+		sev, cond = a.Rate.Severity, ConditionRate
+	}
+	return sev, cond
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Status is the status of an alarm.
+type Status uint8
+
+const (
+	// StatusCleared means that no alarm condition is present.
+	StatusCleared Status = iota
+	// StatusActive means that an alarm condition is present and has not been
+	// acknowledged.
+	StatusActive
+	// StatusAcknowledged means that an alarm condition is present and has been
+	// acknowledged by an operator.
+	StatusAcknowledged
+	// StatusShelved means that the alarm has been shelved by an operator, and will not
+	// be raised until the shelf expires.
+	StatusShelved
+)
+
+// State is the current state of an alarm.
+type State struct {
+	// Key is the key of the alarm.
+	Key Key `json:"key" msgpack:"key"`
+	// Status is the status of the alarm.
+	Status Status `json:"status" msgpack:"status"`
+	// Severity is the severity of the current alarm condition.
+	Severity Severity `json:"severity" msgpack:"severity"`
+	// Condition is the cause of the current alarm condition.
+	Condition Condition `json:"condition" msgpack:"condition"`
+	// Value is the value of the channel that caused the last transition. Zero for
+	// stale conditions.
+	Value float64 `json:"value" msgpack:"value"`
+	// Time is the time of the last transition.
+	Time telem.TimeStamp `json:"time" msgpack:"time"`
+	// ShelvedUntil is the time at which the shelf on the alarm expires. Only valid
+	// when the status is StatusShelved.
+	ShelvedUntil telem.TimeStamp `json:"shelved_until" msgpack:"shelved_until"`
+}
+
+var _ gorp.Entry[Key] = State{}
+
+// GorpKey implements gorp.Entry.
+func (s State) GorpKey() Key { return s.Key }
+
+// SetOptions implements gorp.Entry.
+func (s State) SetOptions() []interface{} { return []interface{}{s.Key.Node()} }
+
+// raise transitions the state in response to an evaluation of the alarm's limits,
+// returning the next state and true if the state changed.
+func (s State) raise(sev Severity, cond Condition, value float64, now telem.TimeStamp) (State, bool) {
+	if s.Status == StatusShelved {
+		return s, false
+	}
+	next := s
+	next.Severity, next.Condition = sev, cond
+	switch {
+	case sev == SeverityNone:
+		if s.Status == StatusCleared {
+			return s, false
+		}
+		next.Status, next.Condition = StatusCleared, ""
+	case s.Status == StatusCleared || sev > s.Severity:
+		// New conditions and escalations need to be acknowledged again.
+		next.Status = StatusActive
+	case sev == s.Severity && cond == s.Condition:
+		return s, false
+	}
+	next.Value, next.Time = value, now
+	return next, true
+}
+
+// acknowledge acknowledges an active alarm, returning false if the alarm isn't active.
+func (s State) acknowledge(now telem.TimeStamp) (State, bool) {
+	if s.Status != StatusActive {
+		return s, false
+	}
+	s.Status, s.Time = StatusAcknowledged, now
+	return s, true
+}
+
+// shelve suppresses the alarm until the given time. A zero until un-shelves the alarm.
+func (s State) shelve(until, now telem.TimeStamp) (State, bool) {
+	if until.IsZero() {
+		return s.unshelve(now)
+	}
+	s.Status, s.Severity, s.Condition = StatusShelved, SeverityNone, ""
+	s.ShelvedUntil, s.Time = until, now
+	return s, true
+}
+
+// unshelve clears a shelved alarm so that it's re-evaluated on the next sample.
+func (s State) unshelve(now telem.TimeStamp) (State, bool) {
+	if s.Status != StatusShelved {
+		return s, false
+	}
+	s.Status, s.ShelvedUntil, s.Time = StatusCleared, 0, now
+	return s, true
+}
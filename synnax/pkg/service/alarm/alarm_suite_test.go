@@ -0,0 +1,55 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution"
+	"github.com/synnaxlabs/synnax/pkg/distribution/mock"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var (
+	ctx  = context.Background()
+	_b   *mock.Builder
+	dist distribution.Distribution
+	svc  *alarm.Service
+)
+
+var _ = BeforeSuite(func() {
+	_b = mock.NewBuilder()
+	dist = _b.New(ctx)
+	svc = MustSucceed(alarm.OpenService(ctx, alarm.Config{
+		DB:                 dist.Storage.Gorpify(),
+		Ontology:           dist.Ontology,
+		Group:              dist.Group,
+		HostProvider:       dist.Cluster,
+		Channel:            dist.Channel,
+		Framer:             dist.Framer,
+		StaleCheckInterval: 5 * telem.Millisecond,
+	}))
+})
+
+var _ = AfterSuite(func() {
+	Expect(svc.Close()).To(Succeed())
+	Expect(_b.Close()).To(Succeed())
+	Expect(_b.Cleanup()).To(Succeed())
+})
+
+func TestAlarm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Alarm Suite")
+}
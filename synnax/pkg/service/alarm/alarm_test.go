@@ -0,0 +1,270 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm_test
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Alarm", func() {
+	var (
+		idx, data channel.Channel
+		start     telem.TimeStamp
+	)
+	BeforeEach(func() {
+		prefix := uuid.NewString()[:8]
+		idx = channel.Channel{Name: prefix + "_time", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+		data = channel.Channel{Name: prefix + "_data", DataType: telem.Int64T, LocalIndex: idx.LocalKey}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &data)).To(Succeed())
+		start = telem.Now()
+	})
+
+	create := func(a alarm.Alarm) alarm.Alarm {
+		Expect(svc.NewWriter(nil).Create(ctx, &a)).To(Succeed())
+		Expect(svc.Sync(ctx)).To(Succeed())
+		return a
+	}
+	// write writes the given values to the data channel, spaced one second apart.
+	write := func(values ...int64) {
+		stamps := make([]telem.TimeStamp, len(values))
+		for i := range values {
+			stamps[i] = start
+			start = start.Add(telem.Second)
+		}
+		w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+			Keys:  channel.Keys{idx.Key(), data.Key()},
+			Start: stamps[0],
+		}))
+		Expect(w.Write(framer.Frame{
+			Keys:   channel.Keys{idx.Key(), data.Key()},
+			Series: []telem.Series{telem.NewSeries(stamps), telem.NewSeries(values)},
+		})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+		Expect(w.Close()).To(Succeed())
+	}
+	state := func(k alarm.Key) alarm.State {
+		return MustSucceed(svc.RetrieveStates(ctx, nil, k))[0]
+	}
+	expectState := func(k alarm.Key, status alarm.Status, sev alarm.Severity, cond alarm.Condition) {
+		Eventually(func(g Gomega) {
+			s := state(k)
+			g.Expect(s.Status).To(Equal(status))
+			g.Expect(s.Severity).To(Equal(sev))
+			g.Expect(s.Condition).To(Equal(cond))
+		}).WithOffset(1).Should(Succeed())
+	}
+
+	Describe("Create", func() {
+		It("Should assign a key and create a cleared state", func() {
+			a := create(alarm.Alarm{
+				Name:    "pressure",
+				Channel: data.Key(),
+				Warning: &alarm.Bounds{Low: 0, High: 10},
+			})
+			Expect(a.Key.IsValid()).To(BeTrue())
+			Expect(a.Key.Node()).To(Equal(dist.Cluster.HostKey()))
+			Expect(state(a.Key).Status).To(Equal(alarm.StatusCleared))
+			var res ontology.Resource
+			Expect(dist.Ontology.NewRetrieve().
+				WhereIDs(alarm.OntologyID(a.Key)).
+				TraverseTo(ontology.Traverser{
+					Filter: func(_ *ontology.Resource, rel *ontology.Relationship) bool {
+						return rel.Type == alarm.Monitors
+					},
+					Direction: ontology.Forward,
+				}).
+				Entry(&res).
+				Exec(ctx, nil)).To(Succeed())
+			Expect(res.ID).To(Equal(channel.OntologyID(data.Key())))
+		})
+		It("Should not allow an alarm without any limits", func() {
+			err := svc.NewWriter(nil).Create(ctx, &alarm.Alarm{Name: "empty", Channel: data.Key()})
+			Expect(err).To(MatchError(ContainSubstring("at least one limit must be provided")))
+		})
+		It("Should not allow an alarm on a non-numeric channel", func() {
+			err := svc.NewWriter(nil).Create(ctx, &alarm.Alarm{
+				Name:    "time",
+				Channel: idx.Key(),
+				Warning: &alarm.Bounds{Low: 0, High: 10},
+			})
+			Expect(err).To(MatchError(ContainSubstring("alarms can only check numeric channels")))
+		})
+	})
+
+	Describe("Retrieve", func() {
+		It("Should retrieve alarms by the channel they check", func() {
+			a := create(alarm.Alarm{Name: "a", Channel: data.Key(), Critical: &alarm.Bounds{High: 5}})
+			var res []alarm.Alarm
+			Expect(svc.NewRetrieve().WhereChannels(data.Key()).Entries(&res).Exec(ctx, nil)).To(Succeed())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].Key).To(Equal(a.Key))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("Should delete the alarm and its state", func() {
+			a := create(alarm.Alarm{Name: "a", Channel: data.Key(), Critical: &alarm.Bounds{High: 5}})
+			Expect(svc.NewWriter(nil).Delete(ctx, a.Key)).To(Succeed())
+			Expect(svc.NewRetrieve().WhereKeys(a.Key).Exec(ctx, nil)).To(HaveOccurredAs(query.NotFound))
+			_, err := svc.RetrieveStates(ctx, nil, a.Key)
+			Expect(err).To(HaveOccurredAs(query.NotFound))
+		})
+	})
+
+	Describe("Limits", func() {
+		It("Should raise warning and critical alarms and clear them", func() {
+			a := create(alarm.Alarm{
+				Name:     "pressure",
+				Channel:  data.Key(),
+				Warning:  &alarm.Bounds{Low: 0, High: 10},
+				Critical: &alarm.Bounds{Low: -10, High: 20},
+			})
+			write(5)
+			Consistently(func() alarm.Status { return state(a.Key).Status }, 20*time.Millisecond).
+				Should(Equal(alarm.StatusCleared))
+			write(15)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+			Expect(state(a.Key).Value).To(Equal(15.0))
+			write(-20)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityCritical, alarm.ConditionLow)
+			write(3)
+			expectState(a.Key, alarm.StatusCleared, alarm.SeverityNone, "")
+		})
+		It("Should raise an alarm when the rate of change is exceeded", func() {
+			a := create(alarm.Alarm{
+				Name:    "rate",
+				Channel: data.Key(),
+				Rate:    &alarm.RateLimit{Max: 5, Severity: alarm.SeverityCritical},
+			})
+			write(1, 3, 6)
+			Consistently(func() alarm.Status { return state(a.Key).Status }, 20*time.Millisecond).
+				Should(Equal(alarm.StatusCleared))
+			write(20)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityCritical, alarm.ConditionRate)
+			write(22)
+			expectState(a.Key, alarm.StatusCleared, alarm.SeverityNone, "")
+		})
+		It("Should raise an alarm when a channel stops receiving data", func() {
+			a := create(alarm.Alarm{
+				Name:    "stale",
+				Channel: data.Key(),
+				Stale:   &alarm.StaleLimit{Timeout: 100 * telem.Millisecond, Severity: alarm.SeverityWarning},
+			})
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionStale)
+			write(1)
+			expectState(a.Key, alarm.StatusCleared, alarm.SeverityNone, "")
+		})
+	})
+
+	Describe("Acknowledge", func() {
+		It("Should acknowledge an active alarm until it escalates", func() {
+			a := create(alarm.Alarm{
+				Name:     "pressure",
+				Channel:  data.Key(),
+				Warning:  &alarm.Bounds{Low: 0, High: 10},
+				Critical: &alarm.Bounds{Low: -10, High: 20},
+			})
+			write(15)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+			Expect(svc.NewWriter(nil).Acknowledge(ctx, a.Key)).To(Succeed())
+			Expect(state(a.Key).Status).To(Equal(alarm.StatusAcknowledged))
+			write(12)
+			Consistently(func() alarm.Status { return state(a.Key).Status }, 20*time.Millisecond).
+				Should(Equal(alarm.StatusAcknowledged))
+			write(25)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityCritical, alarm.ConditionHigh)
+		})
+		It("Should not change the state of a cleared alarm", func() {
+			a := create(alarm.Alarm{Name: "a", Channel: data.Key(), Warning: &alarm.Bounds{High: 10}})
+			Expect(svc.NewWriter(nil).Acknowledge(ctx, a.Key)).To(Succeed())
+			Expect(state(a.Key).Status).To(Equal(alarm.StatusCleared))
+		})
+	})
+
+	Describe("Shelve", func() {
+		It("Should suppress an alarm until it is un-shelved", func() {
+			a := create(alarm.Alarm{Name: "a", Channel: data.Key(), Warning: &alarm.Bounds{High: 10}})
+			write(15)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+			Expect(svc.NewWriter(nil).Shelve(ctx, telem.Hour, a.Key)).To(Succeed())
+			s := state(a.Key)
+			Expect(s.Status).To(Equal(alarm.StatusShelved))
+			Expect(s.ShelvedUntil).To(BeNumerically(">", telem.Now()))
+			write(5, 15)
+			Consistently(func() alarm.Status { return state(a.Key).Status }, 20*time.Millisecond).
+				Should(Equal(alarm.StatusShelved))
+			Expect(svc.NewWriter(nil).Shelve(ctx, 0, a.Key)).To(Succeed())
+			Expect(state(a.Key).Status).To(Equal(alarm.StatusCleared))
+			write(16)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+		})
+		It("Should re-evaluate an alarm when its shelf expires", func() {
+			a := create(alarm.Alarm{Name: "a", Channel: data.Key(), Warning: &alarm.Bounds{High: 10}})
+			write(15)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+			Expect(svc.NewWriter(nil).Shelve(ctx, 20*telem.Millisecond, a.Key)).To(Succeed())
+			expectState(a.Key, alarm.StatusCleared, alarm.SeverityNone, "")
+			write(15)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+		})
+	})
+
+	Describe("History", func() {
+		It("Should write every state transition to the history channels", func() {
+			a := create(alarm.Alarm{Name: "a", Channel: data.Key(), Warning: &alarm.Bounds{High: 10}})
+			write(15)
+			expectState(a.Key, alarm.StatusActive, alarm.SeverityWarning, alarm.ConditionHigh)
+			Expect(svc.NewWriter(nil).Acknowledge(ctx, a.Key)).To(Succeed())
+			write(5)
+			expectState(a.Key, alarm.StatusCleared, alarm.SeverityNone, "")
+			hist := svc.HistoryChannels()
+			Expect(hist).To(HaveLen(5))
+			Eventually(func(g Gomega) {
+				iter := MustSucceed(dist.Framer.OpenIterator(ctx, framer.IteratorConfig{
+					Keys:   channel.KeysFromChannels(hist),
+					Bounds: telem.TimeRangeMax,
+				}))
+				var statuses []uint8
+				for iter.SeekFirst(); iter.Next(iterator.AutoSpan); {
+					keys := iter.Value().Get(hist[1].Key())
+					sts := iter.Value().Get(hist[2].Key())
+					for i := range keys {
+						ks, ss := telem.Unmarshal[uint32](keys[i]), telem.Unmarshal[uint8](sts[i])
+						for j := range ks {
+							if ks[j] == uint32(a.Key) {
+								statuses = append(statuses, ss[j])
+							}
+						}
+					}
+				}
+				g.Expect(iter.Close()).To(Succeed())
+				g.Expect(statuses).To(Equal([]uint8{
+					uint8(alarm.StatusCleared),
+					uint8(alarm.StatusActive),
+					uint8(alarm.StatusAcknowledged),
+					uint8(alarm.StatusCleared),
+				}))
+			}).Should(Succeed())
+		})
+	})
+})
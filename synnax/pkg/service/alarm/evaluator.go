@@ -0,0 +1,450 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	changex "github.com/synnaxlabs/x/change"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/observe"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"go.uber.org/zap"
+)
+
+// rule is an alarm being evaluated by this node, along with the data needed to evaluate
+// rate-of-change and stale limits.
+type rule struct {
+	Alarm
+	// severity and condition are the result of the last evaluation of the rule. Rules
+	// are only re-evaluated against the persisted state when these change.
+	severity  Severity
+	condition Condition
+	// prev is the previous sample received for the rule's channel.
+	prev struct {
+		value float64
+		time  telem.TimeStamp
+		ok    bool
+	}
+	// lastSeen is the wall-clock time at which data was last received for the rule's
+	// channel, or the time at which the rule was loaded.
+	lastSeen telem.TimeStamp
+}
+
+// evaluator checks the values of channels against the alarms created on this node,
+// updating their states and writing a history of state transitions.
+type evaluator struct {
+	*Service
+	shutdown  context.CancelFunc
+	wg        signal.WaitGroup
+	requests  confluence.Inlet[framer.StreamerRequest]
+	responses confluence.Outlet[framer.StreamerResponse]
+	writer    *framer.Writer
+	lastWrite telem.TimeStamp
+	rules     map[Key]*rule
+	// byChannel maps each streamed channel to the rules that check it.
+	byChannel map[channel.Key][]*rule
+	// indexes maps each checked channel to its index, if it has one.
+	indexes map[channel.Key]channel.Key
+	// pending holds changes made to alarms and states by gorp observers, which are
+	// processed by the evaluation loop. Observers are called synchronously within
+	// the committing transaction, so they must never block on the evaluation loop.
+	pending struct {
+		sync.Mutex
+		reload bool
+		states []State
+		// synced are closed once the reload requested alongside them is complete.
+		synced []chan struct{}
+	}
+	notify      chan struct{}
+	disconnects []observe.Disconnect
+}
+
+func openEvaluator(ctx context.Context, s *Service) (*evaluator, error) {
+	e := &evaluator{
+		Service:   s,
+		rules:     make(map[Key]*rule),
+		byChannel: make(map[channel.Key][]*rule),
+		indexes:   make(map[channel.Key]channel.Key),
+		notify:    make(chan struct{}, 1),
+	}
+	var err error
+	if e.writer, err = e.openHistoryWriter(ctx); err != nil {
+		return nil, err
+	}
+	streamer, err := s.Framer.NewStreamer(ctx, framer.StreamerConfig{})
+	if err != nil {
+		return nil, errors.CombineErrors(err, e.writer.Close())
+	}
+	e.requests, e.responses = confluence.Attach(streamer, 10)
+	sCtx, cancel := signal.Isolated(signal.WithInstrumentation(s.Instrumentation))
+	e.shutdown, e.wg = cancel, sCtx
+	streamer.Flow(sCtx, confluence.CloseOutputInletsOnExit())
+
+	host := s.HostProvider.HostKey()
+	e.disconnects = append(
+		e.disconnects,
+		gorp.Observe[Key, Alarm](s.DB).OnChange(func(ctx context.Context, r gorp.TxReader[Key, Alarm]) {
+			for c, ok := r.Next(ctx); ok; c, ok = r.Next(ctx) {
+				if c.Key.Node() == host {
+					e.enqueue(func() { e.pending.reload = true })
+					return
+				}
+			}
+		}),
+		gorp.Observe[Key, State](s.DB).OnChange(func(ctx context.Context, r gorp.TxReader[Key, State]) {
+			for c, ok := r.Next(ctx); ok; c, ok = r.Next(ctx) {
+				if c.Key.Node() == host && c.Variant == changex.Set {
+					e.enqueue(func() { e.pending.states = append(e.pending.states, c.Value) })
+				}
+			}
+		}),
+	)
+	e.pending.reload = true
+	e.notify <- struct{}{}
+	sCtx.Go(e.run, signal.WithKey("evaluator"), signal.RecoverWithErrOnPanic())
+	return e, nil
+}
+
+func (e *evaluator) enqueue(f func()) {
+	e.pending.Lock()
+	f()
+	e.pending.Unlock()
+	select {
+	case e.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (e *evaluator) run(ctx context.Context) error {
+	defer e.requests.Close()
+	ticker := time.NewTicker(e.StaleCheckInterval.Duration())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-e.notify:
+			e.processPending(ctx)
+		case <-ticker.C:
+			e.checkTimers(ctx)
+			// Apply the states of any alarms un-shelved by checkTimers before
+			// evaluating more data, so that they are raised again if their condition
+			// is still present.
+			e.processPending(ctx)
+		case res, ok := <-e.responses.Outlet():
+			if !ok {
+				return nil
+			}
+			e.process(ctx, res.Frame)
+		}
+	}
+}
+
+func (e *evaluator) processPending(ctx context.Context) {
+	e.pending.Lock()
+	reload, states, synced := e.pending.reload, e.pending.states, e.pending.synced
+	e.pending.reload, e.pending.states, e.pending.synced = false, nil, nil
+	e.pending.Unlock()
+	if reload {
+		if err := e.reload(ctx); err != nil {
+			e.L.Error("failed to reload alarms", zap.Error(err))
+		}
+	}
+	e.resetCleared(ctx, states)
+	for _, s := range states {
+		e.writeHistory(ctx, s)
+	}
+	for _, c := range synced {
+		close(c)
+	}
+}
+
+// resetCleared forgets the result of the last evaluation of rules whose alarms were
+// cleared by an operator un-shelving them or by their shelves expiring, so that they
+// are raised again if their condition is still present. A state may be observed after
+// the evaluator has already replaced it, so the current states are retrieved before
+// any rules are reset.
+func (e *evaluator) resetCleared(ctx context.Context, states []State) {
+	var keys []Key
+	for _, s := range states {
+		if r, ok := e.rules[s.Key]; ok && s.Status == StatusCleared && r.severity != SeverityNone {
+			keys = append(keys, s.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	current, err := e.RetrieveStates(ctx, nil, lo.Uniq(keys)...)
+	if err != nil && !errors.Is(err, query.NotFound) {
+		e.L.Error("failed to retrieve alarm states", zap.Error(err))
+		return
+	}
+	for _, s := range current {
+		if r, ok := e.rules[s.Key]; ok && s.Status == StatusCleared {
+			r.severity, r.condition = SeverityNone, ""
+		}
+	}
+}
+
+// sync reloads the alarms evaluated by this node, blocking until the reload is
+// complete. A change may be observed some time after the commit that made it returns,
+// so sync reloads explicitly instead of waiting for observed changes.
+func (e *evaluator) sync(ctx context.Context) error {
+	c := make(chan struct{})
+	e.enqueue(func() {
+		e.pending.reload = true
+		e.pending.synced = append(e.pending.synced, c)
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c:
+		return nil
+	}
+}
+
+// reload retrieves the alarms evaluated by this node and updates the channels being
+// streamed, preserving the evaluation state of existing rules.
+func (e *evaluator) reload(ctx context.Context) error {
+	var alarms []Alarm
+	if err := e.NewRetrieve().
+		WhereNode(e.HostProvider.HostKey()).
+		Entries(&alarms).
+		Exec(ctx, nil); err != nil && !errors.Is(err, query.NotFound) {
+		return err
+	}
+	var (
+		now       = telem.Now()
+		rules     = make(map[Key]*rule, len(alarms))
+		byChannel = make(map[channel.Key][]*rule, len(alarms))
+		indexes   = make(map[channel.Key]channel.Key, len(alarms))
+		added     []Key
+		channels  []channel.Channel
+	)
+	for _, a := range alarms {
+		r, ok := e.rules[a.Key]
+		if !ok {
+			r = &rule{lastSeen: now}
+			added = append(added, a.Key)
+		} else if r.Channel != a.Channel {
+			r.prev.ok = false
+		}
+		r.Alarm = a
+		rules[a.Key] = r
+		byChannel[a.Channel] = append(byChannel[a.Channel], r)
+	}
+	if len(byChannel) > 0 {
+		if err := e.Channel.NewRetrieve().
+			WhereKeys(lo.Keys(byChannel)...).
+			Entries(&channels).
+			Exec(ctx, nil); err != nil {
+			return err
+		}
+	}
+	for _, ch := range channels {
+		if ch.Index() != 0 {
+			indexes[ch.Key()] = ch.Index()
+		}
+	}
+	if len(added) > 0 {
+		states, err := e.RetrieveStates(ctx, nil, added...)
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			rules[s.Key].severity, rules[s.Key].condition = s.Severity, s.Condition
+		}
+	}
+	e.rules, e.byChannel, e.indexes = rules, byChannel, indexes
+	keys := append(lo.Keys(byChannel), lo.Values(indexes)...)
+	return e.updateKeys(ctx, lo.Uniq(keys))
+}
+
+// updateKeys updates the channels being streamed. Responses from the streamer are
+// processed while waiting to send the request to avoid deadlocking when the streamer
+// is blocked on sending a response.
+func (e *evaluator) updateKeys(ctx context.Context, keys channel.Keys) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e.requests.Inlet() <- framer.StreamerRequest{Keys: keys}:
+			return nil
+		case res, ok := <-e.responses.Outlet():
+			if !ok {
+				return nil
+			}
+			e.process(ctx, res.Frame)
+		}
+	}
+}
+
+// process evaluates the rules for every sample in the given frame.
+func (e *evaluator) process(ctx context.Context, fr framer.Frame) {
+	now := telem.Now()
+	for i, key := range fr.Keys {
+		rules := e.byChannel[key]
+		if len(rules) == 0 {
+			continue
+		}
+		series := fr.Series[i]
+		timestamps := e.timestamps(fr, key, series)
+		for j := int64(0); j < series.Len(); j++ {
+			v, ts := series.Float64At(j), now
+			if timestamps != nil {
+				ts = telem.ValueAt[telem.TimeStamp](*timestamps, j)
+			}
+			for _, r := range rules {
+				e.evaluate(ctx, r, v, ts, now)
+			}
+		}
+	}
+}
+
+// timestamps returns the index series that is aligned with the given data series in
+// the frame, or nil if there is no such series.
+func (e *evaluator) timestamps(fr framer.Frame, key channel.Key, s telem.Series) *telem.Series {
+	idx, ok := e.indexes[key]
+	if !ok {
+		return nil
+	}
+	for _, is := range fr.Get(idx) {
+		if is.Alignment == s.Alignment && is.Len() == s.Len() {
+			return &is
+		}
+	}
+	return nil
+}
+
+func (e *evaluator) evaluate(ctx context.Context, r *rule, v float64, ts, now telem.TimeStamp) {
+	var (
+		rate    float64
+		hasRate = r.prev.ok && ts.After(r.prev.time)
+	)
+	if hasRate {
+		rate = (v - r.prev.value) / r.prev.time.Span(ts).Seconds()
+	}
+	r.prev.value, r.prev.time, r.prev.ok = v, ts, true
+	r.lastSeen = now
+	sev, cond := r.check(v, rate, hasRate)
+	e.transition(ctx, r, sev, cond, v, ts)
+}
+
+// transition persists a change in the result of evaluating a rule.
+func (e *evaluator) transition(
+	ctx context.Context,
+	r *rule,
+	sev Severity,
+	cond Condition,
+	v float64,
+	ts telem.TimeStamp,
+) {
+	if sev == r.severity && cond == r.condition {
+		return
+	}
+	r.severity, r.condition = sev, cond
+	if err := e.NewWriter(nil).updateStates(ctx, []Key{r.Key}, func(s State) (State, bool) {
+		return s.raise(sev, cond, v, ts)
+	}); err != nil {
+		e.L.Error("failed to update alarm state", zap.Stringer("key", r.Key), zap.Error(err))
+	}
+}
+
+// checkTimers raises stale alarms and un-shelves alarms whose shelves have expired.
+func (e *evaluator) checkTimers(ctx context.Context) {
+	now := telem.Now()
+	for _, r := range e.rules {
+		if r.Stale != nil &&
+			r.condition != ConditionStale &&
+			r.lastSeen.Span(now) > r.Stale.Timeout {
+			e.transition(ctx, r, r.Stale.Severity, ConditionStale, 0, now)
+		}
+	}
+	var expired []State
+	if err := gorp.NewRetrieve[Key, State]().
+		Where(func(s *State) bool {
+			_, ok := e.rules[s.Key]
+			return ok && s.Status == StatusShelved && s.ShelvedUntil.BeforeEq(now)
+		}).
+		Entries(&expired).
+		Exec(ctx, e.DB); err != nil && !errors.Is(err, query.NotFound) {
+		e.L.Error("failed to retrieve shelved alarms", zap.Error(err))
+		return
+	}
+	if len(expired) == 0 {
+		return
+	}
+	keys := lo.Map(expired, func(s State, _ int) Key { return s.Key })
+	if err := e.NewWriter(nil).updateStates(ctx, keys, func(s State) (State, bool) {
+		return s.unshelve(now)
+	}); err != nil {
+		e.L.Error("failed to un-shelve alarms", zap.Error(err))
+	}
+}
+
+func (e *evaluator) openHistoryWriter(ctx context.Context) (*framer.Writer, error) {
+	e.lastWrite = telem.Now()
+	return e.Framer.OpenWriter(ctx, framer.WriterConfig{
+		Keys:  channel.KeysFromChannels(e.history),
+		Start: e.lastWrite,
+	})
+}
+
+// writeHistory appends the given state to the alarm history channels.
+func (e *evaluator) writeHistory(ctx context.Context, s State) {
+	ts := telem.Now()
+	if !ts.After(e.lastWrite) {
+		ts = e.lastWrite + 1
+	}
+	e.lastWrite = ts
+	fr := framer.Frame{
+		Keys: channel.KeysFromChannels(e.history),
+		Series: []telem.Series{
+			telem.NewSeriesV[telem.TimeStamp](ts),
+			telem.NewSeriesV[uint32](uint32(s.Key)),
+			telem.NewSeriesV[uint8](uint8(s.Status)),
+			telem.NewSeriesV[uint8](uint8(s.Severity)),
+			telem.NewSeriesV[float64](s.Value),
+		},
+	}
+	if e.writer.Write(fr) && e.writer.Commit() {
+		return
+	}
+	// The writer has accumulated an error, so we reopen it so that subsequent
+	// transitions are still recorded.
+	err := errors.CombineErrors(e.writer.Error(), e.writer.Close())
+	e.L.Error("failed to write alarm history", zap.Stringer("key", s.Key), zap.Error(err))
+	if e.writer, err = e.openHistoryWriter(ctx); err != nil {
+		e.L.Error("failed to reopen alarm history writer", zap.Error(err))
+	}
+}
+
+// Close stops the evaluator and closes the history writer.
+func (e *evaluator) Close() error {
+	for _, dc := range e.disconnects {
+		dc()
+	}
+	e.shutdown()
+	err := e.wg.Wait()
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	return errors.CombineErrors(err, e.writer.Close())
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/schema"
+	changex "github.com/synnaxlabs/x/change"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/iter"
+	"github.com/synnaxlabs/x/observe"
+)
+
+// OntologyType is the ontology type for alarms.
+const OntologyType ontology.Type = "alarm"
+
+// Monitors indicates that an alarm checks the values of a channel. The From field of
+// the relationship is the alarm, and the To field is the channel.
+const Monitors ontology.RelationshipType = "monitors"
+
+// OntologyID returns the ontology.ID for the alarm with the given key.
+func OntologyID(k Key) ontology.ID {
+	return ontology.ID{Type: OntologyType, Key: k.String()}
+}
+
+// OntologyIDs returns the ontology.IDs for the alarms with the given keys.
+func OntologyIDs(keys []Key) []ontology.ID {
+	return lo.Map(keys, func(k Key, _ int) ontology.ID { return OntologyID(k) })
+}
+
+// OntologyIDsFromAlarms returns the ontology.IDs for the given alarms.
+func OntologyIDsFromAlarms(alarms []Alarm) []ontology.ID {
+	return lo.Map(alarms, func(a Alarm, _ int) ontology.ID { return OntologyID(a.Key) })
+}
+
+// KeysFromOntologyIDs extracts the alarm keys from the given ontology.IDs.
+func KeysFromOntologyIDs(ids []ontology.ID) (keys []Key, err error) {
+	keys = make([]Key, len(ids))
+	for i, id := range ids {
+		k, err := strconv.Atoi(id.Key)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = Key(k)
+	}
+	return keys, nil
+}
+
+var _schema = &ontology.Schema{
+	Type: OntologyType,
+	Fields: map[string]schema.Field{
+		"key":     {Type: schema.Uint32},
+		"name":    {Type: schema.String},
+		"channel": {Type: schema.Uint32},
+	},
+}
+
+func newResource(a Alarm) schema.Resource {
+	e := schema.NewResource(_schema, OntologyID(a.Key), a.Name)
+	schema.Set(e, "key", uint32(a.Key))
+	schema.Set(e, "name", a.Name)
+	schema.Set(e, "channel", uint32(a.Channel))
+	return e
+}
+
+var _ ontology.Service = (*Service)(nil)
+
+type change = changex.Change[Key, Alarm]
+
+// Schema implements ontology.Service.
+func (s *Service) Schema() *schema.Schema { return _schema }
+
+// RetrieveResource implements ontology.Service.
+func (s *Service) RetrieveResource(ctx context.Context, key string, tx gorp.Tx) (ontology.Resource, error) {
+	k, err := strconv.Atoi(key)
+	if err != nil {
+		return ontology.Resource{}, err
+	}
+	var a Alarm
+	err = s.NewRetrieve().WhereKeys(Key(k)).Entry(&a).Exec(ctx, tx)
+	return newResource(a), err
+}
+
+func translateChange(c change) schema.Change {
+	return schema.Change{
+		Variant: c.Variant,
+		Key:     OntologyID(c.Key),
+		Value:   newResource(c.Value),
+	}
+}
+
+// OnChange implements ontology.Service.
+func (s *Service) OnChange(f func(ctx context.Context, nexter iter.Nexter[schema.Change])) observe.Disconnect {
+	handleChange := func(ctx context.Context, reader gorp.TxReader[Key, Alarm]) {
+		f(ctx, iter.NexterTranslator[change, schema.Change]{Wrap: reader, Translate: translateChange})
+	}
+	return gorp.Observe[Key, Alarm](s.DB).OnChange(handleChange)
+}
+
+// OpenNexter implements ontology.Service.
+func (s *Service) OpenNexter() (iter.NexterCloser[schema.Resource], error) {
+	n, err := gorp.WrapReader[Key, Alarm](s.DB).OpenNexter()
+	return iter.NexterCloserTranslator[Alarm, schema.Resource]{
+		Wrap:      n,
+		Translate: newResource,
+	}, err
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/search"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+// Retrieve is a query builder for retrieving alarms.
+type Retrieve struct {
+	baseTx     gorp.Tx
+	otg        *ontology.Ontology
+	gorp       gorp.Retrieve[Key, Alarm]
+	searchTerm string
+}
+
+// Search executes a fuzzy search for alarms whose name matches the given term.
+func (r Retrieve) Search(term string) Retrieve { r.searchTerm = term; return r }
+
+// WhereKeys filters for alarms with the given keys.
+func (r Retrieve) WhereKeys(keys ...Key) Retrieve {
+	r.gorp = r.gorp.WhereKeys(keys...)
+	return r
+}
+
+// WhereNames filters for alarms with the given names.
+func (r Retrieve) WhereNames(names ...string) Retrieve {
+	r.gorp = r.gorp.Where(func(a *Alarm) bool { return lo.Contains(names, a.Name) })
+	return r
+}
+
+// WhereChannels filters for alarms that check any of the given channels.
+func (r Retrieve) WhereChannels(keys ...channel.Key) Retrieve {
+	r.gorp = r.gorp.Where(func(a *Alarm) bool { return lo.Contains(keys, a.Channel) })
+	return r
+}
+
+// WhereNode filters for alarms that are evaluated by the given node.
+func (r Retrieve) WhereNode(node core.NodeKey) Retrieve {
+	r.gorp = r.gorp.Where(func(a *Alarm) bool { return a.Key.Node() == node })
+	return r
+}
+
+// Limit limits the number of results returned by the query.
+func (r Retrieve) Limit(limit int) Retrieve { r.gorp = r.gorp.Limit(limit); return r }
+
+// Offset sets the index of the first result returned by the query.
+func (r Retrieve) Offset(offset int) Retrieve { r.gorp = r.gorp.Offset(offset); return r }
+
+// Entry binds the alarm that the query will fill results into.
+func (r Retrieve) Entry(a *Alarm) Retrieve { r.gorp = r.gorp.Entry(a); return r }
+
+// Entries binds the slice that the query will fill results into.
+func (r Retrieve) Entries(alarms *[]Alarm) Retrieve { r.gorp = r.gorp.Entries(alarms); return r }
+
+// Exec executes the query. If tx is nil, the query is executed directly against the
+// underlying gorp.DB.
+func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
+	if r.searchTerm != "" {
+		ids, err := r.otg.SearchIDs(ctx, search.Request{
+			Type: OntologyType,
+			Term: r.searchTerm,
+		})
+		if err != nil {
+			return err
+		}
+		keys, err := KeysFromOntologyIDs(ids)
+		if err != nil {
+			return err
+		}
+		r = r.WhereKeys(keys...)
+	}
+	return r.gorp.Exec(ctx, gorp.OverrideTx(r.baseTx, tx))
+}
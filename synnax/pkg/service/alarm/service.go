@@ -0,0 +1,208 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/kv"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Config is the configuration for opening the alarm service.
+type Config struct {
+	alamos.Instrumentation
+	// DB is the database used to store alarms and their states.
+	// [REQUIRED]
+	DB *gorp.DB
+	// Ontology is used to define alarms as resources and relate them to the channels
+	// they check.
+	// [REQUIRED]
+	Ontology *ontology.Ontology
+	// Group is used to create the group that holds all alarms.
+	// [REQUIRED]
+	Group *group.Service
+	// HostProvider is used to assign alarms to nodes. Each node evaluates the alarms
+	// created on it.
+	// [REQUIRED]
+	HostProvider core.HostProvider
+	// Channel is used to retrieve the channels checked by alarms and to create the
+	// internal channels that alarm history is written to.
+	// [REQUIRED]
+	Channel channel.ReadWriteable
+	// Framer is used to stream data for the channels checked by alarms, and to write
+	// alarm history.
+	// [REQUIRED]
+	Framer framer.WriteStreamable
+	// StaleCheckInterval sets how often the service checks for stale channels and
+	// expired shelves.
+	// [OPTIONAL] - Defaults to 1 second.
+	StaleCheckInterval telem.TimeSpan
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for the alarm service. This
+	// configuration is not valid on its own, and must be overridden by the required
+	// fields.
+	DefaultConfig = Config{StaleCheckInterval: telem.Second}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.DB = override.Nil(c.DB, other.DB)
+	c.Ontology = override.Nil(c.Ontology, other.Ontology)
+	c.Group = override.Nil(c.Group, other.Group)
+	c.HostProvider = override.Nil(c.HostProvider, other.HostProvider)
+	c.Channel = override.Nil(c.Channel, other.Channel)
+	c.Framer = override.Nil(c.Framer, other.Framer)
+	c.StaleCheckInterval = override.Numeric(c.StaleCheckInterval, other.StaleCheckInterval)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("alarm")
+	validate.NotNil(v, "DB", c.DB)
+	validate.NotNil(v, "Ontology", c.Ontology)
+	validate.NotNil(v, "Group", c.Group)
+	validate.NotNil(v, "HostProvider", c.HostProvider)
+	validate.NotNil(v, "Channel", c.Channel)
+	validate.NotNil(v, "Framer", c.Framer)
+	validate.Positive(v, "StaleCheckInterval", c.StaleCheckInterval)
+	return v.Error()
+}
+
+// Service manages alarms, evaluates the alarms created on this node against live data,
+// and writes a history of alarm state transitions to internal channels.
+type Service struct {
+	Config
+	group           group.Group
+	localKeyCounter *kv.AtomicInt64Counter
+	history         []channel.Channel
+	evaluator       *evaluator
+}
+
+const localKeyCounterSuffix = ".alarm.counter"
+
+// OpenService opens a new alarm service using the given configuration. If error is nil,
+// the service is evaluating alarms and must be closed by calling Close.
+func OpenService(ctx context.Context, configs ...Config) (*Service, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	counterKey := []byte(cfg.HostProvider.HostKey().String() + localKeyCounterSuffix)
+	c, err := kv.OpenCounter(ctx, cfg.DB, counterKey)
+	if err != nil {
+		return nil, err
+	}
+	g, err := cfg.Group.CreateOrRetrieve(ctx, "Alarms", ontology.RootID)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Config: cfg, group: g, localKeyCounter: c}
+	cfg.Ontology.RegisterService(s)
+	if s.history, err = s.createHistoryChannels(ctx); err != nil {
+		return nil, err
+	}
+	s.evaluator, err = openEvaluator(ctx, s)
+	return s, err
+}
+
+// NewWriter opens a new Writer to create, delete, acknowledge, and shelve alarms. If tx
+// is nil, the writer executes directly against the underlying gorp.DB.
+func (s *Service) NewWriter(tx gorp.Tx) Writer {
+	tx = gorp.OverrideTx(s.DB, tx)
+	return Writer{
+		tx:      tx,
+		otg:     s.Ontology.NewWriter(tx),
+		group:   s.group,
+		channel: s.Channel,
+		newKey: func() (Key, error) {
+			n, err := s.localKeyCounter.Add(1)
+			return NewKey(s.HostProvider.HostKey(), uint16(n)), err
+		},
+	}
+}
+
+// NewRetrieve opens a new query to retrieve alarms.
+func (s *Service) NewRetrieve() Retrieve {
+	return Retrieve{
+		baseTx: s.DB,
+		otg:    s.Ontology,
+		gorp:   gorp.NewRetrieve[Key, Alarm](),
+	}
+}
+
+// RetrieveStates retrieves the current states of the alarms with the given keys. If tx
+// is nil, the states are retrieved directly from the underlying gorp.DB.
+func (s *Service) RetrieveStates(ctx context.Context, tx gorp.Tx, keys ...Key) ([]State, error) {
+	states := make([]State, 0, len(keys))
+	return states, gorp.NewRetrieve[Key, State]().
+		WhereKeys(keys...).
+		Entries(&states).
+		Exec(ctx, gorp.OverrideTx(s.DB, tx))
+}
+
+// HistoryChannels returns the internal channels that this node writes alarm state
+// transitions to, in the order time, key, status, severity, and value.
+func (s *Service) HistoryChannels() []channel.Channel { return s.history }
+
+// Sync blocks until the evaluator has loaded every alarm committed before Sync was
+// called, including requesting data for the channels they check. Sync is useful for
+// ensuring that an alarm is evaluated against data written after it was created.
+func (s *Service) Sync(ctx context.Context) error { return s.evaluator.sync(ctx) }
+
+// Close stops evaluating alarms and closes the history writer.
+func (s *Service) Close() error { return s.evaluator.Close() }
+
+func (s *Service) createHistoryChannels(ctx context.Context) ([]channel.Channel, error) {
+	host := s.HostProvider.HostKey()
+	name := func(field string) string { return fmt.Sprintf("sy_node_%s_alarm_%s", host, field) }
+	idx := []channel.Channel{{
+		Name:        name("time"),
+		DataType:    telem.TimeStampT,
+		IsIndex:     true,
+		Leaseholder: host,
+		Internal:    true,
+	}}
+	w := s.Channel.NewWriter(nil)
+	if err := w.CreateManyIfNamesDontExist(ctx, &idx); err != nil {
+		return nil, err
+	}
+	data := []channel.Channel{
+		{Name: name("key"), DataType: telem.Uint32T},
+		{Name: name("status"), DataType: telem.Uint8T},
+		{Name: name("severity"), DataType: telem.Uint8T},
+		{Name: name("value"), DataType: telem.Float64T},
+	}
+	for i := range data {
+		data[i].LocalIndex = idx[0].LocalKey
+		data[i].Leaseholder = host
+		data[i].Internal = true
+	}
+	if err := w.CreateManyIfNamesDontExist(ctx, &data); err != nil {
+		return nil, err
+	}
+	return append(idx, data...), nil
+}
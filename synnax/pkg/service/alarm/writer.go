@@ -0,0 +1,153 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alarm
+
+import (
+	"context"
+
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Writer is used to create, update, and delete alarms within a transaction.
+type Writer struct {
+	tx      gorp.Tx
+	otg     ontology.Writer
+	group   group.Group
+	channel channel.Readable
+	newKey  func() (Key, error)
+}
+
+// Create creates the given alarm, assigning it a key if it does not already have a
+// valid one. If an alarm with the same key already exists, it will be overwritten and
+// its state will be preserved.
+func (w Writer) Create(ctx context.Context, a *Alarm) (err error) {
+	if !a.Key.IsValid() {
+		if a.Key, err = w.newKey(); err != nil {
+			return
+		}
+	}
+	if err = a.Validate(); err != nil {
+		return
+	}
+	var ch channel.Channel
+	if err = w.channel.NewRetrieve().WhereKeys(a.Channel).Entry(&ch).Exec(ctx, w.tx); err != nil {
+		return
+	}
+	if !ch.DataType.IsNumeric() {
+		return errors.Wrapf(
+			validate.Error,
+			"[alarm] - channel %s has data type %s, but alarms can only check numeric channels",
+			ch.Name,
+			ch.DataType,
+		)
+	}
+	if err = gorp.NewCreate[Key, Alarm]().Entry(a).Exec(ctx, w.tx); err != nil {
+		return
+	}
+	exists, err := gorp.NewRetrieve[Key, State]().WhereKeys(a.Key).Exists(ctx, w.tx)
+	if err != nil {
+		return
+	}
+	if !exists {
+		st := State{Key: a.Key, Status: StatusCleared, Time: telem.Now()}
+		if err = gorp.NewCreate[Key, State]().Entry(&st).Exec(ctx, w.tx); err != nil {
+			return
+		}
+	}
+	otgID := OntologyID(a.Key)
+	if err = w.otg.DefineResource(ctx, otgID); err != nil {
+		return
+	}
+	if err = w.otg.DefineRelationship(ctx, w.group.OntologyID(), ontology.ParentOf, otgID); err != nil {
+		return
+	}
+	if err = w.otg.DeleteOutgoingRelationshipsOfType(ctx, otgID, Monitors); err != nil {
+		return
+	}
+	return w.otg.DefineRelationship(ctx, otgID, Monitors, channel.OntologyID(a.Channel))
+}
+
+// CreateMany creates the given alarms. See Create for more details.
+func (w Writer) CreateMany(ctx context.Context, alarms *[]Alarm) error {
+	for i := range *alarms {
+		if err := w.Create(ctx, &(*alarms)[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the alarms with the given keys along with their states. Delete is
+// idempotent, and will not return an error if an alarm does not exist.
+func (w Writer) Delete(ctx context.Context, keys ...Key) error {
+	for _, k := range keys {
+		if err := w.otg.DeleteResource(ctx, OntologyID(k)); err != nil {
+			return err
+		}
+	}
+	if err := gorp.NewDelete[Key, State]().WhereKeys(keys...).Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	return gorp.NewDelete[Key, Alarm]().WhereKeys(keys...).Exec(ctx, w.tx)
+}
+
+// Acknowledge acknowledges the active alarms with the given keys. Alarms that are not
+// active are left unchanged.
+func (w Writer) Acknowledge(ctx context.Context, keys ...Key) error {
+	now := telem.Now()
+	return w.updateStates(ctx, keys, func(s State) (State, bool) {
+		return s.acknowledge(now)
+	})
+}
+
+// Shelve suppresses the alarms with the given keys for the given duration, after which
+// they are re-evaluated. A duration of zero un-shelves the alarms immediately.
+func (w Writer) Shelve(ctx context.Context, duration telem.TimeSpan, keys ...Key) error {
+	now := telem.Now()
+	var until telem.TimeStamp
+	if duration > 0 {
+		until = now.Add(duration)
+	}
+	return w.updateStates(ctx, keys, func(s State) (State, bool) {
+		return s.shelve(until, now)
+	})
+}
+
+// updateStates applies f to the states of the alarms with the given keys, only writing
+// the states that f reports as changed.
+func (w Writer) updateStates(
+	ctx context.Context,
+	keys []Key,
+	f func(State) (State, bool),
+) error {
+	var states []State
+	if err := gorp.NewRetrieve[Key, State]().
+		WhereKeys(keys...).
+		Entries(&states).
+		Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	changed := make([]State, 0, len(states))
+	for _, s := range states {
+		if next, ok := f(s); ok {
+			changed = append(changed, next)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return gorp.NewCreate[Key, State]().Entries(&changed).Exec(ctx, w.tx)
+}
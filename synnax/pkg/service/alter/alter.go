@@ -0,0 +1,222 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package alter runs channel alterations (changes to a channel's data type or index)
+// as background jobs and tracks their progress.
+package alter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// Config is the configuration for opening the alter service.
+type Config struct {
+	alamos.Instrumentation
+	// Channel is used to alter channels.
+	// [REQUIRED]
+	Channel channel.Service
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for opening the alter service. This
+	// configuration is not valid on its own, and must be overridden by the required
+	// fields specified in Config.
+	DefaultConfig = Config{}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.Channel = override.Nil(c.Channel, other.Channel)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("alter")
+	validate.NotNil(v, "Channel", c.Channel)
+	return v.Error()
+}
+
+// Status is the status of an alter job.
+type Status string
+
+const (
+	// StatusRunning means the channel's data is being migrated.
+	StatusRunning Status = "running"
+	// StatusCompleted means the channel was altered successfully.
+	StatusCompleted Status = "completed"
+	// StatusFailed means the alter failed, and the channel was left unchanged.
+	StatusFailed Status = "failed"
+)
+
+// Request is a request to alter a channel.
+type Request struct {
+	// Channel is the key of the channel to alter.
+	// [REQUIRED]
+	Channel channel.Key `json:"channel" msgpack:"channel"`
+	// DataType is the new data type of the channel. If empty, the data type is left
+	// unchanged.
+	DataType telem.DataType `json:"data_type" msgpack:"data_type"`
+	// Index is the key of the new index of the channel. If zero, the index is left
+	// unchanged.
+	Index channel.Key `json:"index" msgpack:"index"`
+}
+
+// Job tracks the progress of altering a channel.
+type Job struct {
+	// Key is the unique identifier of the job.
+	Key uuid.UUID `json:"key" msgpack:"key"`
+	// Request is the alter executed by the job.
+	Request
+	// Status is the current status of the job.
+	Status Status `json:"status" msgpack:"status"`
+	// Processed is the number of bytes of channel data migrated so far.
+	Processed int64 `json:"processed" msgpack:"processed"`
+	// Total is the number of bytes of channel data known to need migration. Total
+	// can grow while the job is running if data is written to the channel.
+	Total int64 `json:"total" msgpack:"total"`
+	// Error is the reason the job failed.
+	Error string `json:"error,omitempty" msgpack:"error,omitempty"`
+	// StartedAt is the time the job started.
+	StartedAt telem.TimeStamp `json:"started_at" msgpack:"started_at"`
+	// EndedAt is the time the job completed or failed.
+	EndedAt telem.TimeStamp `json:"ended_at" msgpack:"ended_at"`
+}
+
+// Service runs alter jobs in the background. Jobs are tracked in memory, and are
+// only visible on the node that runs them.
+type Service struct {
+	Config
+	shutdown context.CancelFunc
+	wg       signal.WaitGroup
+	sCtx     signal.Context
+	mu       struct {
+		sync.RWMutex
+		jobs map[uuid.UUID]*Job
+	}
+}
+
+// OpenService opens a new alter service using the provided configuration.
+func OpenService(configs ...Config) (*Service, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Config: cfg}
+	s.mu.jobs = make(map[uuid.UUID]*Job)
+	s.sCtx, s.shutdown = signal.Isolated(signal.WithInstrumentation(cfg.Instrumentation))
+	s.wg = s.sCtx
+	return s, nil
+}
+
+// Start starts a job to alter a channel, returning the job in its initial state. Only
+// one job can run on a channel at a time.
+func (s *Service) Start(req Request) (Job, error) {
+	v := validate.New("alter")
+	validate.NonZero(v, "channel", req.Channel)
+	v.Ternary("data_type", req.DataType == "" && req.Index == 0, "either a data type or index must be provided")
+	if err := v.Error(); err != nil {
+		return Job{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.mu.jobs {
+		if j.Channel == req.Channel && j.Status == StatusRunning {
+			return Job{}, errors.Wrapf(validate.Error, "channel %d is already being altered by job %s", req.Channel, j.Key)
+		}
+	}
+	j := &Job{
+		Key:       uuid.New(),
+		Request:   req,
+		Status:    StatusRunning,
+		StartedAt: telem.Now(),
+	}
+	s.mu.jobs[j.Key] = j
+	s.sCtx.Go(func(ctx context.Context) error {
+		s.run(ctx, j.Key, req)
+		return nil
+	}, signal.WithKeyf("alter-%s", j.Key), signal.RecoverWithErrOnPanic())
+	return *j, nil
+}
+
+func (s *Service) run(ctx context.Context, key uuid.UUID, req Request) {
+	err := s.Channel.Alter(ctx, req.Channel, channel.AlterConfig{
+		DataType: req.DataType,
+		Index:    req.Index,
+		OnProgress: func(p channel.AlterProgress) {
+			s.update(key, func(j *Job) { j.Processed, j.Total = p.Processed, p.Total })
+		},
+	})
+	s.update(key, func(j *Job) {
+		j.EndedAt = telem.Now()
+		if err != nil {
+			j.Status, j.Error = StatusFailed, err.Error()
+			return
+		}
+		j.Status = StatusCompleted
+	})
+	lo.Ternary(err == nil, s.L.Info, s.L.Error)(
+		"channel alter finished",
+		zap.Stringer("job", key),
+		zap.Stringer("channel", req.Channel),
+		zap.Error(err),
+	)
+}
+
+func (s *Service) update(key uuid.UUID, f func(j *Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s.mu.jobs[key])
+}
+
+// Retrieve returns the jobs with the given keys. If no keys are provided, all jobs
+// are returned.
+func (s *Service) Retrieve(keys ...uuid.UUID) ([]Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(keys) == 0 {
+		return lo.MapToSlice(s.mu.jobs, func(_ uuid.UUID, j *Job) Job { return *j }), nil
+	}
+	jobs := make([]Job, 0, len(keys))
+	for _, k := range keys {
+		j, ok := s.mu.jobs[k]
+		if !ok {
+			return nil, errors.Wrapf(query.NotFound, "alter job %s not found", k)
+		}
+		jobs = append(jobs, *j)
+	}
+	return jobs, nil
+}
+
+// Close cancels all running jobs and waits for them to exit. Channels whose jobs are
+// canceled are left unchanged.
+func (s *Service) Close() error {
+	s.shutdown()
+	err := s.wg.Wait()
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package alter_test
+
+import (
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/service/alter"
+	"github.com/synnaxlabs/synnax/pkg/storage/ts"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Alter", func() {
+	var (
+		svc       *alter.Service
+		idx, data channel.Channel
+	)
+	BeforeEach(func() {
+		svc = MustSucceed(alter.OpenService(alter.Config{Channel: dist.Channel}))
+		idx = channel.Channel{Name: "time", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.Create(ctx, &idx)).To(Succeed())
+		data = channel.Channel{
+			Name:       "pressure",
+			DataType:   telem.Float64T,
+			LocalIndex: idx.LocalKey,
+			Scale:      channel.Scale{Type: channel.ScaleLinear, Slope: 2},
+		}
+		Expect(dist.Channel.Create(ctx, &data)).To(Succeed())
+		Expect(dist.Storage.TS.Write(ctx, telem.SecondTS, ts.Frame{
+			Keys:   []ts.ChannelKey{idx.Key().StorageKey(), data.Key().StorageKey()},
+			Series: []telem.Series{telem.NewSecondsTSV(1, 2, 3), telem.NewSeriesV[float64](1, 2, 3)},
+		})).To(Succeed())
+	})
+	AfterEach(func() { Expect(svc.Close()).To(Succeed()) })
+	wait := func(key uuid.UUID) alter.Job {
+		var job alter.Job
+		Eventually(func(g Gomega) {
+			jobs := MustSucceed(svc.Retrieve(key))
+			g.Expect(jobs).To(HaveLen(1))
+			job = jobs[0]
+			g.Expect(job.Status).ToNot(Equal(alter.StatusRunning))
+		}).Should(Succeed())
+		return job
+	}
+	retrieve := func(key channel.Key) channel.Channel {
+		var ch channel.Channel
+		Expect(dist.Channel.NewRetrieve().WhereKeys(key).Entry(&ch).Exec(ctx, nil)).To(Succeed())
+		return ch
+	}
+
+	It("Should change the data type of a channel in the background", func() {
+		job := MustSucceed(svc.Start(alter.Request{Channel: data.Key(), DataType: telem.Int32T}))
+		Expect(job.Status).To(Equal(alter.StatusRunning))
+		job = wait(job.Key)
+		Expect(job.Error).To(BeEmpty())
+		Expect(job.Status).To(Equal(alter.StatusCompleted))
+		Expect(job.EndedAt).To(BeNumerically(">=", job.StartedAt))
+		ch := retrieve(data.Key())
+		Expect(ch.DataType).To(Equal(telem.Int32T))
+		Expect(ch.Scale.RawDataType).To(Equal(telem.Int32T))
+		f := MustSucceed(dist.Storage.TS.Read(ctx, telem.TimeRangeMax, data.Key().StorageKey()))
+		Expect(f.Series).To(HaveLen(1))
+		Expect(f.Series[0].Data).To(Equal(telem.NewSeriesV[int32](1, 2, 3).Data))
+	})
+
+	It("Should change the index of a channel", func() {
+		idx2 := channel.Channel{Name: "time2", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.Create(ctx, &idx2)).To(Succeed())
+		Expect(dist.Storage.TS.WriteArray(
+			ctx,
+			idx2.Key().StorageKey(),
+			telem.SecondTS,
+			telem.NewSecondsTSV(1, 2, 3),
+		)).To(Succeed())
+		job := wait(MustSucceed(svc.Start(alter.Request{Channel: data.Key(), Index: idx2.Key()})).Key)
+		Expect(job.Error).To(BeEmpty())
+		Expect(retrieve(data.Key()).Index()).To(Equal(idx2.Key()))
+	})
+
+	It("Should report the reason a job failed", func() {
+		Expect(dist.Storage.TS.Write(ctx, 10*telem.SecondTS, ts.Frame{
+			Keys:   []ts.ChannelKey{idx.Key().StorageKey(), data.Key().StorageKey()},
+			Series: []telem.Series{telem.NewSecondsTSV(10), telem.NewSeriesV[float64](1.5)},
+		})).To(Succeed())
+		job := wait(MustSucceed(svc.Start(alter.Request{Channel: data.Key(), DataType: telem.Int32T})).Key)
+		Expect(job.Status).To(Equal(alter.StatusFailed))
+		Expect(job.Error).To(ContainSubstring("cannot be represented"))
+		Expect(retrieve(data.Key()).DataType).To(Equal(telem.Float64T))
+	})
+
+	It("Should not change the index of a channel to a channel that is not an index", func() {
+		job := wait(MustSucceed(svc.Start(alter.Request{Channel: data.Key(), Index: data.Key()})).Key)
+		Expect(job.Status).To(Equal(alter.StatusFailed))
+		Expect(job.Error).To(ContainSubstring("is not an index"))
+	})
+
+	It("Should require a data type or index", func() {
+		_, err := svc.Start(alter.Request{Channel: data.Key()})
+		Expect(err).To(MatchError(ContainSubstring("either a data type or index")))
+	})
+
+	It("Should return an error when retrieving a job that does not exist", func() {
+		_, err := svc.Retrieve(uuid.New())
+		Expect(err).To(HaveOccurredAs(query.NotFound))
+	})
+
+	It("Should retrieve all jobs", func() {
+		job := MustSucceed(svc.Start(alter.Request{Channel: data.Key(), DataType: telem.Float32T}))
+		wait(job.Key)
+		jobs := MustSucceed(svc.Retrieve())
+		Expect(jobs).To(HaveLen(1))
+		Expect(jobs[0].Key).To(Equal(job.Key))
+		Expect(jobs[0].Request).To(Equal(alter.Request{Channel: data.Key(), DataType: telem.Float32T}))
+	})
+
+	It("Should not accept an invalid request", func() {
+		_, err := svc.Start(alter.Request{DataType: telem.Int32T})
+		Expect(err).To(MatchError(ContainSubstring("channel")))
+	})
+})
@@ -18,9 +18,10 @@ import (
 // Authenticator validates the identity of a particular entity (i.e. they are who they
 // say they are).
 type Authenticator interface {
-	// Authenticate validates the identity of the entity with the given credentials. If
-	// the credentials are invalid, an InvalidCredentials error is returned.
-	Authenticate(ctx context.Context, creds InsecureCredentials) error
+	// Authenticate validates the identity of the entity with the given credentials and
+	// returns the username of the authenticated entity. If the credentials are invalid,
+	// an InvalidCredentials error is returned.
+	Authenticate(ctx context.Context, creds InsecureCredentials) (string, error)
 	// NewWriter opens a new Writer using the provided write context.
 	NewWriter(tx gorp.Tx) Writer
 }
@@ -59,13 +59,13 @@ var _ = Describe("KV", Ordered, Serial, func() {
 	})
 	Describe("Authenticating", func() {
 		It("Should return a nil error for valid credentials", func() {
-			Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
+			Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
 		})
 		It("Should return an InvalidCredentials error when the password is wrong", func() {
-			Expect(authenticator.Authenticate(ctx, invalPassCreds)).To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, invalPassCreds)).Error().To(MatchError(auth.InvalidCredentials))
 		})
 		It("Should return an InvalidCredentials error when the user can't be found", func() {
-			Expect(authenticator.Authenticate(ctx, invalUserCreds)).To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, invalUserCreds)).Error().To(MatchError(auth.InvalidCredentials))
 		})
 	})
 	Describe("Changing the username", func() {
@@ -84,45 +84,45 @@ var _ = Describe("KV", Ordered, Serial, func() {
 		When("using credentials", func() {
 			It("Should update the username", func() {
 				Expect(authenticator.NewWriter(nil).UpdateUsername(ctx, creds, newCreds.Username)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, newCreds)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, creds)).To(MatchError(auth.InvalidCredentials))
+				Expect(authenticator.Authenticate(ctx, newCreds)).To(Equal(newCreds.Username))
+				Expect(authenticator.Authenticate(ctx, creds)).Error().To(MatchError(auth.InvalidCredentials))
 			})
 			It("Should return an InvalidCredentials error when the password is wrong", func() {
 				Expect(authenticator.NewWriter(nil).UpdateUsername(ctx, invalPassCreds, newCreds.Username)).To(MatchError(auth.InvalidCredentials))
-				Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, newCreds)).To(MatchError(auth.InvalidCredentials))
+				Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
+				Expect(authenticator.Authenticate(ctx, newCreds)).Error().To(MatchError(auth.InvalidCredentials))
 			})
 			It("Should return an InvalidCredentials error when the user can't be found", func() {
 				Expect(authenticator.NewWriter(nil).UpdateUsername(ctx, invalUserCreds, newCreds.Username)).To(MatchError(auth.InvalidCredentials))
-				Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, newCreds)).To(MatchError(auth.InvalidCredentials))
+				Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
+				Expect(authenticator.Authenticate(ctx, newCreds)).Error().To(MatchError(auth.InvalidCredentials))
 			})
 			It("Should do nothing when the username is the same", func() {
 				Expect(authenticator.NewWriter(nil).UpdateUsername(ctx, creds, creds.Username)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
+				Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
 			})
 			It("Should raise a RepeatedUsername error when the username is already registered", func() {
 				Expect(authenticator.NewWriter(nil).Register(ctx, newCreds)).To(Succeed())
 				Expect(errors.Is(authenticator.NewWriter(nil).UpdateUsername(ctx, creds, newCreds.Username), auth.RepeatedUsername)).To(BeTrue())
-				Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, newCreds)).To(Succeed())
+				Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
+				Expect(authenticator.Authenticate(ctx, newCreds)).To(Equal(newCreds.Username))
 			})
 		})
 		When("using usernames", func() {
 			It("Should update the username", func() {
 				Expect(authenticator.NewWriter(nil).InsecureUpdateUsername(ctx, creds.Username, newCreds.Username)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, newCreds)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, creds)).To(MatchError(auth.InvalidCredentials))
+				Expect(authenticator.Authenticate(ctx, newCreds)).To(Equal(newCreds.Username))
+				Expect(authenticator.Authenticate(ctx, creds)).Error().To(MatchError(auth.InvalidCredentials))
 			})
 			It("Should do nothing when the username is the same", func() {
 				Expect(authenticator.NewWriter(nil).InsecureUpdateUsername(ctx, creds.Username, creds.Username)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
+				Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
 			})
 			It("Should return an RepeatedUsername error when the username is already registered", func() {
 				Expect(authenticator.NewWriter(nil).Register(ctx, newCreds)).To(Succeed())
 				Expect(errors.Is(authenticator.NewWriter(nil).InsecureUpdateUsername(ctx, creds.Username, newCreds.Username), auth.RepeatedUsername)).To(BeTrue())
-				Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
-				Expect(authenticator.Authenticate(ctx, newCreds)).To(Succeed())
+				Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
+				Expect(authenticator.Authenticate(ctx, newCreds)).To(Equal(newCreds.Username))
 			})
 		})
 	})
@@ -138,25 +138,25 @@ var _ = Describe("KV", Ordered, Serial, func() {
 		})
 		It("Should update the password", func() {
 			Expect(authenticator.NewWriter(nil).UpdatePassword(ctx, creds, newCreds.Password)).To(Succeed())
-			Expect(authenticator.Authenticate(ctx, creds)).To(MatchError(auth.InvalidCredentials))
-			Expect(authenticator.Authenticate(ctx, newCreds)).To(Succeed())
+			Expect(authenticator.Authenticate(ctx, creds)).Error().To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, newCreds)).To(Equal(newCreds.Username))
 		})
 		It("Should return an InvalidCredentials error when the password is wrong", func() {
 			Expect(authenticator.NewWriter(nil).UpdatePassword(ctx, invalPassCreds, newCreds.Password)).To(MatchError(auth.InvalidCredentials))
-			Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
-			Expect(authenticator.Authenticate(ctx, newCreds)).To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
+			Expect(authenticator.Authenticate(ctx, newCreds)).Error().To(MatchError(auth.InvalidCredentials))
 		})
 		It("Should return an InvalidCredentials error when the user can't be found", func() {
 			Expect(authenticator.NewWriter(nil).UpdatePassword(ctx, invalUserCreds, newCreds.Password)).To(MatchError(auth.InvalidCredentials))
-			Expect(authenticator.Authenticate(ctx, creds)).To(Succeed())
-			Expect(authenticator.Authenticate(ctx, newCreds)).To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, creds)).To(Equal(creds.Username))
+			Expect(authenticator.Authenticate(ctx, newCreds)).Error().To(MatchError(auth.InvalidCredentials))
 			Expect(authenticator.NewWriter(nil).InsecureDeactivate(ctx, creds.Username)).To(Succeed())
 		})
 	})
 	Describe("Deactivating", func() {
 		It("Should delete the credentials", func() {
 			Expect(authenticator.NewWriter(nil).InsecureDeactivate(ctx, creds.Username)).To(Succeed())
-			Expect(authenticator.Authenticate(ctx, creds)).To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, creds)).Error().To(MatchError(auth.InvalidCredentials))
 		})
 		It("Should be idempotent", func() {
 			for range 2 {
@@ -167,8 +167,8 @@ var _ = Describe("KV", Ordered, Serial, func() {
 			creds2 := auth.InsecureCredentials{Username: "username2", Password: "password"}
 			Expect(authenticator.NewWriter(nil).Register(ctx, creds2)).To(Succeed())
 			Expect(authenticator.NewWriter(nil).InsecureDeactivate(ctx, creds.Username, creds2.Username)).To(Succeed())
-			Expect(authenticator.Authenticate(ctx, creds)).To(MatchError(auth.InvalidCredentials))
-			Expect(authenticator.Authenticate(ctx, creds2)).To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, creds)).Error().To(MatchError(auth.InvalidCredentials))
+			Expect(authenticator.Authenticate(ctx, creds2)).Error().To(MatchError(auth.InvalidCredentials))
 		})
 	})
 })
@@ -18,13 +18,19 @@ import (
 // authenticate an entity (user, client, etc.). These credentials are NOT safe to store
 // on disk.
 type InsecureCredentials struct {
-	Username string       `json:"username" validate:"required"`
-	Password password.Raw `json:"password" validate:"required"`
+	Username string       `json:"username"`
+	Password password.Raw `json:"password"`
+	// IDToken is an identity token issued by an external identity provider (such as an
+	// OIDC ID token). When set, the username and password are not required.
+	IDToken string `json:"id_token" msgpack:"id_token"`
 }
 
 // Validate validates the InsecureCredentials.
 func (i InsecureCredentials) Validate() error {
 	v := validate.New("auth.insecure_credentials")
+	if i.IDToken != "" {
+		return nil
+	}
 	validate.NotEmptyString(v, "username", i.Username)
 	validate.NotEmptyString(v, "password", string(i.Password))
 	return v.Error()
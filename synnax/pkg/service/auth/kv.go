@@ -26,9 +26,9 @@ type KV struct{ DB *gorp.DB }
 var _ Authenticator = (*KV)(nil)
 
 // Authenticate implements Authenticator.
-func (db *KV) Authenticate(ctx context.Context, creds InsecureCredentials) error {
-	_, err := db.authenticate(ctx, creds, db.DB)
-	return err
+func (db *KV) Authenticate(ctx context.Context, creds InsecureCredentials) (string, error) {
+	secureCreds, err := db.authenticate(ctx, creds, db.DB)
+	return secureCreds.Username, err
 }
 
 func (db *KV) authenticate(
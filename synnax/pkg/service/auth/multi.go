@@ -24,14 +24,18 @@ type MultiAuthenticator []Authenticator
 var _ Authenticator = MultiAuthenticator{}
 
 // Authenticate implements the Authenticator interface.
-func (a MultiAuthenticator) Authenticate(ctx context.Context, creds InsecureCredentials) error {
+func (a MultiAuthenticator) Authenticate(
+	ctx context.Context,
+	creds InsecureCredentials,
+) (string, error) {
 	var err error
 	for _, auth := range a {
-		if err = auth.Authenticate(ctx, creds); err == nil {
-			return nil
+		var username string
+		if username, err = auth.Authenticate(ctx, creds); err == nil {
+			return username, nil
 		}
 	}
-	return err
+	return "", err
 }
 
 // NewWriter implements the Authenticator interface.
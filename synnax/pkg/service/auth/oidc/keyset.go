@@ -0,0 +1,216 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/synnaxlabs/x/errors"
+)
+
+// KeySet provides the public keys used to verify the signatures of ID tokens.
+type KeySet interface {
+	// Key returns the public key with the given key ID. If the key ID is empty and the
+	// set contains a single key, that key is returned.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// StaticKeySet is a KeySet backed by a fixed set of keys, indexed by key ID. It's
+// useful for testing and for deployments that can't reach the issuer's JWKS endpoint.
+type StaticKeySet map[string]crypto.PublicKey
+
+var _ KeySet = StaticKeySet{}
+
+// Key implements KeySet.
+func (s StaticKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if k, ok := s[kid]; ok {
+		return k, nil
+	}
+	if kid == "" && len(s) == 1 {
+		for _, k := range s {
+			return k, nil
+		}
+	}
+	return nil, errors.Newf("no key found with id %q", kid)
+}
+
+// jwk is a single JSON web key as defined in RFC 7517.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Newf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.Newf("unsupported key type %s", k.Kty)
+}
+
+func decodeBigInt(v string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(v, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// ParseJWKS parses a JSON web key set into a StaticKeySet. Keys that are not used for
+// signatures or have an unsupported type are skipped.
+func ParseJWKS(data []byte) (StaticKeySet, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, errors.Wrap(err, "invalid JWKS")
+	}
+	keys := make(StaticKeySet, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+// minRefreshInterval is the minimum time between fetches of a remote key set. It
+// prevents tokens with unknown key IDs from flooding the issuer with requests.
+const minRefreshInterval = 30 * time.Second
+
+// RemoteKeySet is a KeySet that discovers and fetches keys from the JWKS endpoint of
+// an OIDC issuer. Keys are cached, and the set is re-fetched when a token references
+// a key ID that is not in the cache (i.e. after the issuer rotates its keys).
+type RemoteKeySet struct {
+	issuer    string
+	client    *http.Client
+	mu        sync.Mutex
+	keys      StaticKeySet
+	lastFetch time.Time
+}
+
+var _ KeySet = (*RemoteKeySet)(nil)
+
+// NewRemoteKeySet returns a RemoteKeySet for the given issuer. If client is nil,
+// http.DefaultClient is used.
+func NewRemoteKeySet(issuer string, client *http.Client) *RemoteKeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteKeySet{issuer: strings.TrimSuffix(issuer, "/"), client: client}
+}
+
+// Key implements KeySet.
+func (r *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.keys != nil {
+		if k, err := r.keys.Key(ctx, kid); err == nil {
+			return k, nil
+		}
+		if time.Since(r.lastFetch) < minRefreshInterval {
+			return nil, errors.Newf("no key found with id %q", kid)
+		}
+	}
+	keys, err := r.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.keys, r.lastFetch = keys, time.Now()
+	return r.keys.Key(ctx, kid)
+}
+
+func (r *RemoteKeySet) fetch(ctx context.Context) (StaticKeySet, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	b, err := r.get(ctx, r.issuer+"/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &discovery); err != nil {
+		return nil, errors.Wrap(err, "invalid OIDC discovery document")
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document has no jwks_uri")
+	}
+	if b, err = r.get(ctx, discovery.JWKSURI); err != nil {
+		return nil, err
+	}
+	return ParseJWKS(b)
+}
+
+func (r *RemoteKeySet) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Newf("unexpected status %s fetching %s", res.Status, url)
+	}
+	return io.ReadAll(res.Body)
+}
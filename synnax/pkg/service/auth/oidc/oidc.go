@@ -0,0 +1,269 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package oidc implements an auth.Authenticator that accepts ID tokens issued by an
+// external OpenID Connect identity provider.
+package oidc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/password"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// Config is the configuration for opening an OIDC Authenticator.
+type Config struct {
+	alamos.Instrumentation
+	// Issuer is the URL of the identity provider. The iss claim of every ID token must
+	// match it exactly.
+	// [REQUIRED]
+	Issuer string
+	// ClientID is the client ID that Synnax is registered under with the identity
+	// provider. The aud claim of every ID token must contain it.
+	// [REQUIRED]
+	ClientID string
+	// KeySet provides the keys used to verify ID token signatures.
+	// [OPTIONAL] - Defaults to a RemoteKeySet that discovers the issuer's JWKS endpoint.
+	KeySet KeySet
+	// UsernameClaim is the claim used as the username of users created from ID tokens.
+	// It is not used to match tokens to existing users.
+	// [OPTIONAL] - Defaults to "preferred_username".
+	UsernameClaim string
+	// DB is the database used to create users just-in-time.
+	// [REQUIRED]
+	DB *gorp.DB
+	// User is the service used to retrieve and create the users that ID tokens map to.
+	// [REQUIRED]
+	User *user.Service
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for an OIDC Authenticator.
+	DefaultConfig = Config{UsernameClaim: "preferred_username"}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.Issuer = override.String(c.Issuer, other.Issuer)
+	c.ClientID = override.String(c.ClientID, other.ClientID)
+	c.KeySet = override.Nil(c.KeySet, other.KeySet)
+	c.UsernameClaim = override.String(c.UsernameClaim, other.UsernameClaim)
+	c.DB = override.Nil(c.DB, other.DB)
+	c.User = override.Nil(c.User, other.User)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("auth.oidc")
+	validate.NotEmptyString(v, "issuer", c.Issuer)
+	validate.NotEmptyString(v, "client_id", c.ClientID)
+	validate.NotEmptyString(v, "username_claim", c.UsernameClaim)
+	validate.NotNil(v, "db", c.DB)
+	validate.NotNil(v, "user", c.User)
+	return v.Error()
+}
+
+// Authenticator validates OIDC ID tokens against the keys of a configured issuer and
+// maps their claims to Synnax users, creating users that don't exist yet. Users are
+// matched by the issuer and subject of the token, which never change, and are never
+// linked to existing users by username. Existing users can only log in with an ID
+// token after an administrator links them with user.Writer.LinkIdentity. The root user
+// can never be authenticated through an ID token.
+//
+// Authenticator does not store any credentials, so all of its Writer methods return an
+// error. When combined with other authenticators in an auth.MultiAuthenticator, it
+// should be placed first so that operations on stored credentials fall through to the
+// authenticators that own them.
+type Authenticator struct{ cfg Config }
+
+var _ auth.Authenticator = (*Authenticator)(nil)
+
+// Open opens a new Authenticator using the provided configurations.
+func Open(configs ...Config) (*Authenticator, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.KeySet == nil {
+		cfg.KeySet = NewRemoteKeySet(cfg.Issuer, nil)
+	}
+	return &Authenticator{cfg: cfg}, nil
+}
+
+// validMethods are the signing methods accepted for ID tokens. Symmetric methods are
+// excluded, as the issuer's keys are public.
+var validMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// Authenticate implements auth.Authenticator. It returns an InvalidCredentials error if
+// the credentials don't contain an ID token or the token is invalid.
+func (a *Authenticator) Authenticate(
+	ctx context.Context,
+	creds auth.InsecureCredentials,
+) (string, error) {
+	if creds.IDToken == "" {
+		return "", auth.InvalidCredentials
+	}
+	claims, err := a.verify(ctx, creds.IDToken)
+	if err != nil {
+		a.cfg.L.Debug("rejected ID token", zap.Error(err))
+		return "", errors.Wrap(auth.InvalidCredentials, err.Error())
+	}
+	u, err := a.provision(ctx, claims)
+	if err != nil {
+		return "", err
+	}
+	return u.Username, nil
+}
+
+func (a *Authenticator) verify(ctx context.Context, idToken string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	p := &jwt.Parser{ValidMethods: validMethods}
+	if _, err := p.ParseWithClaims(idToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.cfg.KeySet.Key(ctx, kid)
+	}); err != nil {
+		return nil, err
+	}
+	if !claims.VerifyIssuer(a.cfg.Issuer, true) {
+		return nil, errors.Newf("token was not issued by %s", a.cfg.Issuer)
+	}
+	if !claims.VerifyAudience(a.cfg.ClientID, true) {
+		return nil, errors.Newf("token audience does not contain %s", a.cfg.ClientID)
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("token has no expiration")
+	}
+	return claims, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	v, _ := claims[name].(string)
+	return strings.TrimSpace(v)
+}
+
+// provision retrieves the user linked to the identity provider account in the given
+// claims, creating it if it doesn't exist yet and keeping its name in sync with the
+// identity provider.
+func (a *Authenticator) provision(ctx context.Context, claims jwt.MapClaims) (user.User, error) {
+	identity := user.Identity{Issuer: a.cfg.Issuer, Subject: stringClaim(claims, "sub")}
+	if identity.Subject == "" {
+		return user.User{}, errors.Wrap(auth.InvalidCredentials, "token has no sub claim")
+	}
+	first, last := stringClaim(claims, "given_name"), stringClaim(claims, "family_name")
+	var u user.User
+	err := a.cfg.DB.WithTx(ctx, func(tx gorp.Tx) error {
+		err := a.cfg.User.NewRetrieve().WhereIdentity(identity).Entry(&u).Exec(ctx, tx)
+		if errors.Is(err, query.NotFound) {
+			return a.create(ctx, tx, claims, identity, &u)
+		}
+		if err != nil {
+			return err
+		}
+		if (first == "" || first == u.FirstName) && (last == "" || last == u.LastName) {
+			return nil
+		}
+		if err = a.cfg.User.NewWriter(tx).ChangeName(ctx, u.Key, first, last); err != nil {
+			return err
+		}
+		return a.cfg.User.NewRetrieve().WhereKeys(u.Key).Entry(&u).Exec(ctx, tx)
+	})
+	return u, err
+}
+
+// create creates a user for an identity provider account that is not linked to any
+// existing user. If the username in the claims is already taken, the account is not
+// linked to the existing user, as usernames can be changed by the identity provider.
+// Instead, an administrator must link the account explicitly.
+func (a *Authenticator) create(
+	ctx context.Context,
+	tx gorp.Tx,
+	claims jwt.MapClaims,
+	identity user.Identity,
+	u *user.User,
+) error {
+	username := stringClaim(claims, a.cfg.UsernameClaim)
+	if username == "" {
+		return errors.Wrapf(auth.InvalidCredentials, "token has no %s claim", a.cfg.UsernameClaim)
+	}
+	*u = user.User{
+		Username:  username,
+		FirstName: stringClaim(claims, "given_name"),
+		LastName:  stringClaim(claims, "family_name"),
+		Identity:  identity,
+	}
+	err := a.cfg.User.NewWriter(tx).Create(ctx, u)
+	if errors.Is(err, auth.RepeatedUsername) {
+		return errors.Wrapf(
+			auth.InvalidCredentials,
+			"user %s already exists and must be linked to the identity provider by an administrator",
+			username,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	a.cfg.L.Info("created user from ID token", zap.String("username", username))
+	return nil
+}
+
+// NewWriter implements auth.Authenticator.
+func (a *Authenticator) NewWriter(gorp.Tx) auth.Writer { return writer{} }
+
+var errUnsupported = errors.Wrap(
+	auth.Error,
+	"credentials of users authenticated with an ID token are managed by the identity provider",
+)
+
+type writer struct{}
+
+var _ auth.Writer = writer{}
+
+// Register implements auth.Writer.
+func (writer) Register(context.Context, auth.InsecureCredentials) error {
+	return errUnsupported
+}
+
+// UpdateUsername implements auth.Writer.
+func (writer) UpdateUsername(context.Context, auth.InsecureCredentials, string) error {
+	return errUnsupported
+}
+
+// UpdatePassword implements auth.Writer.
+func (writer) UpdatePassword(context.Context, auth.InsecureCredentials, password.Raw) error {
+	return errUnsupported
+}
+
+// InsecureUpdateUsername implements auth.Writer.
+func (writer) InsecureUpdateUsername(context.Context, string, string) error {
+	return errUnsupported
+}
+
+// InsecureDeactivate implements auth.Writer.
+func (writer) InsecureDeactivate(context.Context, ...string) error {
+	return errUnsupported
+}
@@ -0,0 +1,231 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package oidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
+	"github.com/synnaxlabs/synnax/pkg/service/auth/oidc"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/kv/memkv"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+const (
+	issuer   = "https://idp.example.com"
+	clientID = "synnax"
+)
+
+func sign(key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = kid
+	return MustSucceed(t.SignedString(key))
+}
+
+func validClaims(username string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                issuer,
+		"aud":                []string{clientID},
+		"sub":                "subject-" + username,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"preferred_username": username,
+		"given_name":         "Ada",
+		"family_name":        "Lovelace",
+	}
+}
+
+func jwks(kid string, key *rsa.PublicKey) []byte {
+	enc := base64.RawURLEncoding
+	return MustSucceed(json.Marshal(map[string]any{
+		"keys": []map[string]string{{
+			"kid": kid,
+			"kty": "RSA",
+			"use": "sig",
+			"n":   enc.EncodeToString(key.N.Bytes()),
+			"e":   enc.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}))
+}
+
+var _ = Describe("OIDC", Ordered, func() {
+	var (
+		db            *gorp.DB
+		otg           *ontology.Ontology
+		userSvc       *user.Service
+		key           *rsa.PrivateKey
+		authenticator auth.Authenticator
+	)
+	BeforeAll(func() {
+		db = gorp.Wrap(memkv.New())
+		otg = MustSucceed(ontology.Open(ctx, ontology.Config{DB: db}))
+		g := MustSucceed(group.OpenService(group.Config{DB: db, Ontology: otg}))
+		userSvc = MustSucceed(user.NewService(ctx, user.Config{DB: db, Ontology: otg, Group: g}))
+		key = MustSucceed(rsa.GenerateKey(rand.Reader, 2048))
+		keys := MustSucceed(oidc.ParseJWKS(jwks("key-1", &key.PublicKey)))
+		authenticator = auth.MultiAuthenticator{
+			MustSucceed(oidc.Open(oidc.Config{
+				Issuer:   issuer,
+				ClientID: clientID,
+				KeySet:   keys,
+				DB:       db,
+				User:     userSvc,
+			})),
+			&auth.KV{DB: db},
+		}
+	})
+	AfterAll(func() {
+		Expect(otg.Close()).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+	})
+	Describe("Config", func() {
+		It("Should require an issuer and client ID", func() {
+			_, err := oidc.Open(oidc.Config{DB: db, User: userSvc})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Describe("Authenticate", func() {
+		It("Should create a user from the claims of a valid ID token", func() {
+			tk := sign(key, "key-1", validClaims("ada"))
+			username := MustSucceed(authenticator.Authenticate(ctx, auth.InsecureCredentials{IDToken: tk}))
+			Expect(username).To(Equal("ada"))
+			var u user.User
+			Expect(userSvc.NewRetrieve().WhereUsernames("ada").Entry(&u).Exec(ctx, nil)).To(Succeed())
+			Expect(u.FirstName).To(Equal("Ada"))
+			Expect(u.LastName).To(Equal("Lovelace"))
+		})
+		It("Should map repeated logins to the same user and sync its name", func() {
+			var before user.User
+			Expect(userSvc.NewRetrieve().WhereUsernames("ada").Entry(&before).Exec(ctx, nil)).To(Succeed())
+			claims := validClaims("ada")
+			claims["family_name"] = "King"
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).To(Equal("ada"))
+			var after user.User
+			Expect(userSvc.NewRetrieve().WhereUsernames("ada").Entry(&after).Exec(ctx, nil)).To(Succeed())
+			Expect(after.Key).To(Equal(before.Key))
+			Expect(after.LastName).To(Equal("King"))
+		})
+		It("Should match users by subject when the username claim changes", func() {
+			claims := validClaims("ada")
+			claims["preferred_username"] = "ada.lovelace"
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).To(Equal("ada"))
+			Expect(userSvc.UsernameExists(ctx, "ada.lovelace")).To(BeFalse())
+		})
+		It("Should not link a token to an existing user with the same username", func() {
+			creds := auth.InsecureCredentials{Username: "linus", Password: "torvalds"}
+			Expect(authenticator.NewWriter(nil).Register(ctx, creds)).To(Succeed())
+			u := user.User{Username: "linus"}
+			Expect(userSvc.NewWriter(nil).Create(ctx, &u)).To(Succeed())
+			tk := sign(key, "key-1", validClaims("linus"))
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{IDToken: tk})).
+				Error().To(MatchError(auth.InvalidCredentials))
+			By("Authenticating once an administrator links the user")
+			Expect(userSvc.NewWriter(nil).LinkIdentity(ctx, u.Key, user.Identity{
+				Issuer:  issuer,
+				Subject: "subject-linus",
+			})).To(Succeed())
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{IDToken: tk})).
+				To(Equal("linus"))
+		})
+		It("Should reject a token without a subject", func() {
+			claims := validClaims("eve")
+			delete(claims, "sub")
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).Error().To(MatchError(auth.InvalidCredentials))
+		})
+		It("Should reject a token signed by an unknown key", func() {
+			other := MustSucceed(rsa.GenerateKey(rand.Reader, 2048))
+			tk := sign(other, "key-1", validClaims("mallory"))
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{IDToken: tk})).
+				Error().To(MatchError(auth.InvalidCredentials))
+			Expect(userSvc.UsernameExists(ctx, "mallory")).To(BeFalse())
+		})
+		It("Should reject a token from a different issuer", func() {
+			claims := validClaims("eve")
+			claims["iss"] = "https://other.example.com"
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).Error().To(MatchError(auth.InvalidCredentials))
+		})
+		It("Should reject a token for a different audience", func() {
+			claims := validClaims("eve")
+			claims["aud"] = "other-client"
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).Error().To(MatchError(auth.InvalidCredentials))
+		})
+		It("Should reject an expired token", func() {
+			claims := validClaims("eve")
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).Error().To(MatchError(auth.InvalidCredentials))
+		})
+		It("Should reject a token without a username claim", func() {
+			claims := validClaims("eve")
+			delete(claims, "preferred_username")
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", claims),
+			})).Error().To(MatchError(auth.InvalidCredentials))
+		})
+		It("Should not authenticate as the root user", func() {
+			Expect(userSvc.NewWriter(nil).Create(ctx, &user.User{Username: "root", RootUser: true})).To(Succeed())
+			Expect(authenticator.Authenticate(ctx, auth.InsecureCredentials{
+				IDToken: sign(key, "key-1", validClaims("root")),
+			})).Error().To(MatchError(auth.InvalidCredentials))
+		})
+		It("Should fall through to password credentials", func() {
+			creds := auth.InsecureCredentials{Username: "grace", Password: "hopper"}
+			Expect(authenticator.NewWriter(nil).Register(ctx, creds)).To(Succeed())
+			Expect(authenticator.Authenticate(ctx, creds)).To(Equal("grace"))
+			creds.Password = "wrong"
+			Expect(authenticator.Authenticate(ctx, creds)).
+				Error().To(MatchError(auth.InvalidCredentials))
+		})
+	})
+	Describe("RemoteKeySet", func() {
+		It("Should discover and fetch keys from the issuer", func() {
+			var srv *httptest.Server
+			mux := http.NewServeMux()
+			mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+				_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": srv.URL + "/jwks"})
+			})
+			mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write(jwks("key-1", &key.PublicKey))
+			})
+			srv = httptest.NewServer(mux)
+			defer srv.Close()
+			ks := oidc.NewRemoteKeySet(srv.URL, srv.Client())
+			pub := MustSucceed(ks.Key(ctx, "key-1"))
+			Expect(pub.(*rsa.PublicKey).Equal(&key.PublicKey)).To(BeTrue())
+			_, err := ks.Key(ctx, "key-2")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -10,6 +10,7 @@
 package token
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rsa"
@@ -79,13 +80,36 @@ func (s *Service) ValidateMaybeRefresh(token string) (uuid.UUID, string, error)
 
 func (s *Service) validate(token string) (uuid.UUID, *jwt.StandardClaims, error) {
 	claims := &jwt.StandardClaims{}
-	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		return s.publicKey(), nil
-	})
+	err := parse(token, claims, s.KeyProvider.NodePrivate())
+	// Tokens signed with a key that has since been retired by a certificate reload
+	// remain valid until they expire, as long as they were issued before the key was
+	// retired. The claims are not verified until the token is parsed with the right
+	// key, so a retired key is only tried for tokens that are still within the token
+	// lifetime, and tokens that claim to outlive it are rejected.
+	now := time.Now()
+	for _, k := range s.KeyProvider.RetiredNodePrivate() {
+		if !isVerificationError(err) {
+			break
+		}
+		issuedAt := time.Unix(claims.IssuedAt, 0)
+		if issuedAt.After(k.RetiredAt) || !now.Before(issuedAt.Add(s.Expiration)) {
+			continue
+		}
+		if err = parse(token, claims, k.Key); err == nil &&
+			time.Unix(claims.ExpiresAt, 0).After(issuedAt.Add(s.Expiration)) {
+			return uuid.Nil, claims, auth.InvalidToken
+		}
+	}
 	if err != nil {
 		if isVerificationError(err) {
 			return uuid.Nil, claims, auth.InvalidToken
 		}
+		// Expired tokens are invalid, so we return an InvalidToken error to let clients
+		// know they need to log in again.
+		var vErr *jwt.ValidationError
+		if errors.As(err, &vErr) && vErr.Errors&jwt.ValidationErrorExpired != 0 {
+			return uuid.Nil, claims, errors.Wrap(auth.InvalidToken, err.Error())
+		}
 		return uuid.Nil, claims, errors.Wrap(auth.Error, err.Error())
 	}
 	id, err := uuid.Parse(claims.Issuer)
@@ -95,6 +119,13 @@ func (s *Service) validate(token string) (uuid.UUID, *jwt.StandardClaims, error)
 	return id, claims, nil
 }
 
+func parse(token string, claims *jwt.StandardClaims, key crypto.PrivateKey) error {
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return publicKey(key), nil
+	})
+	return err
+}
+
 func (s *Service) isCloseToExpired(claims *jwt.StandardClaims) bool {
 	return time.Unix(claims.ExpiresAt, 0).Sub(time.Now()) < s.RefreshThreshold
 }
@@ -119,8 +150,7 @@ func (s *Service) signingMethodAndKey() (jwt.SigningMethod, interface{}) {
 	panic("unsupported key type")
 }
 
-func (s *Service) publicKey() interface{} {
-	key := s.KeyProvider.NodePrivate()
+func publicKey(key crypto.PrivateKey) interface{} {
 	switch key.(type) {
 	case *rsa.PrivateKey:
 		return key.(*rsa.PrivateKey).Public()
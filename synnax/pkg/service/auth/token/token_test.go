@@ -13,10 +13,18 @@ import (
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/security"
+	"github.com/synnaxlabs/synnax/pkg/security/cert"
+	"github.com/synnaxlabs/synnax/pkg/security/mock"
+	"github.com/synnaxlabs/synnax/pkg/service/auth"
 	"github.com/synnaxlabs/synnax/pkg/service/auth/token"
+	"github.com/synnaxlabs/x/config"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	. "github.com/synnaxlabs/x/testutil"
 	"time"
 )
 
@@ -28,6 +36,10 @@ func (m *mockKeyService) NodePrivate() crypto.PrivateKey {
 	return m.key
 }
 
+func (m *mockKeyService) RetiredNodePrivate() []security.RetiredKey {
+	return nil
+}
+
 var _ = Describe("token", func() {
 	var (
 		svc *token.Service
@@ -55,4 +67,82 @@ var _ = Describe("token", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(issuer).To(Equal(issuer2))
 	})
+	It("Should return an InvalidToken error for an expired token", func() {
+		svc.Expiration = -time.Minute
+		token, err := svc.New(uuid.New())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = svc.Validate(token)
+		Expect(err).To(MatchError(auth.InvalidToken))
+	})
+	Describe("Certificate Reload", func() {
+		var fs xfs.FS
+		BeforeEach(func() {
+			fs = xfs.NewMem()
+			mock.GenerateCerts(fs)
+			svc.KeyProvider = MustSucceed(security.NewProvider(security.ProviderConfig{
+				LoaderConfig: cert.LoaderConfig{FS: fs},
+				KeySize:      mock.SmallKeySize,
+				Insecure:     config.Bool(false),
+			}))
+		})
+		rotate := func() {
+			f := MustSucceed(cert.NewFactory(cert.FactoryConfig{
+				LoaderConfig: cert.LoaderConfig{FS: fs},
+				KeySize:      1024,
+			}))
+			Expect(f.RotateNodePair()).To(Succeed())
+			Expect(svc.KeyProvider.(security.Provider).Reload()).To(Succeed())
+		}
+		It("Should validate a token issued before the node's key was rotated", func() {
+			issuer := uuid.New()
+			tk := MustSucceed(svc.New(issuer))
+			rotate()
+			Expect(svc.Validate(tk)).To(Equal(issuer))
+			By("Issuing new tokens with the new key")
+			tk2 := MustSucceed(svc.New(issuer))
+			Expect(svc.Validate(tk2)).To(Equal(issuer))
+		})
+		It("Should not validate a token signed with a retired key after it was retired", func() {
+			prev := MustSucceed(svc.New(uuid.New()))
+			rotate()
+			retired := svc.KeyProvider.RetiredNodePrivate()
+			Expect(retired).To(HaveLen(1))
+			forged := &token.Service{
+				KeyProvider: &mockKeyService{key: retired[0].Key.(*rsa.PrivateKey)},
+				Expiration:  5 * time.Second,
+			}
+			time.Sleep(time.Until(retired[0].RetiredAt.Truncate(time.Second).Add(time.Second)))
+			tk := MustSucceed(forged.New(uuid.New()))
+			_, err := svc.Validate(tk)
+			Expect(err).To(MatchError(auth.InvalidToken))
+			Expect(svc.Validate(prev)).ToNot(Equal(uuid.Nil))
+		})
+		Describe("Forged Claims", func() {
+			var retired security.RetiredKey
+			BeforeEach(func() {
+				rotate()
+				retired = svc.KeyProvider.RetiredNodePrivate()[0]
+			})
+			sign := func(issuedAt, expiresAt time.Time) string {
+				return MustSucceed(jwt.NewWithClaims(jwt.SigningMethodRS512, jwt.StandardClaims{
+					IssuedAt:  issuedAt.Unix(),
+					Issuer:    uuid.New().String(),
+					ExpiresAt: expiresAt.Unix(),
+				}).SignedString(retired.Key))
+			}
+			It("Should not validate a token signed with a retired key that was issued longer than the token lifetime ago", func() {
+				tk := sign(retired.RetiredAt.Add(-2*svc.Expiration), time.Now().Add(time.Hour))
+				Expect(svc.Validate(tk)).Error().To(MatchError(auth.InvalidToken))
+			})
+			It("Should not validate a token signed with a retired key that expires after the token lifetime", func() {
+				tk := sign(retired.RetiredAt.Add(-time.Second), time.Now().Add(time.Hour))
+				Expect(svc.Validate(tk)).Error().To(MatchError(auth.InvalidToken))
+			})
+			It("Should validate a token signed with a retired key within the token lifetime", func() {
+				issuedAt := retired.RetiredAt.Add(-time.Second)
+				tk := sign(issuedAt, issuedAt.Add(svc.Expiration))
+				Expect(svc.Validate(tk)).ToNot(Equal(uuid.Nil))
+			})
+		})
+	})
 })
@@ -0,0 +1,202 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package deadband implements report-by-exception filtering for streamers, forwarding
+// a channel's samples only when they differ meaningfully from the last sample sent.
+package deadband
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sync"
+
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/x/address"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/confluence/plumber"
+	"github.com/synnaxlabs/x/telem"
+)
+
+const defaultBuffer = 25
+
+// NewStreamer wraps the given streamer in a pipeline that filters its responses using
+// the deadbands in cfg. Subsequent requests with non-nil deadbands replace the
+// deadbands being applied, and channels whose deadbands are removed are no longer
+// filtered. Requests with nil deadbands, such as those that only update the streamer's
+// keys, leave them unchanged.
+func NewStreamer(
+	_ context.Context,
+	cfg framer.StreamerConfig,
+	streamer framer.Streamer,
+) (framer.Streamer, error) {
+	f := newFilter(cfg.Deadbands)
+	requests := &confluence.LinearTransform[framer.StreamerRequest, framer.StreamerRequest]{
+		Transform: func(_ context.Context, req framer.StreamerRequest) (framer.StreamerRequest, bool, error) {
+			if req.Deadbands != nil {
+				f.set(req.Deadbands)
+			}
+			return req, true, nil
+		},
+	}
+	responses := &confluence.LinearTransform[framer.StreamerResponse, framer.StreamerResponse]{
+		Transform: func(_ context.Context, res framer.StreamerResponse) (framer.StreamerResponse, bool, error) {
+			if len(res.Frame.Keys) == 0 {
+				return res, true, nil
+			}
+			res.Frame = f.apply(res.Frame, telem.Now())
+			return res, len(res.Frame.Keys) > 0, nil
+		},
+	}
+
+	pipe := plumber.New()
+	plumber.SetSegment[framer.StreamerRequest, framer.StreamerRequest](
+		pipe,
+		"requests",
+		requests,
+	)
+	plumber.SetSegment[framer.StreamerRequest, framer.StreamerResponse](
+		pipe,
+		"dist-streamer",
+		streamer,
+	)
+	plumber.SetSegment[framer.StreamerResponse, framer.StreamerResponse](
+		pipe,
+		"deadband",
+		responses,
+	)
+	plumber.MustConnect[framer.StreamerRequest](pipe, "requests", "dist-streamer", defaultBuffer)
+	plumber.MustConnect[framer.StreamerResponse](pipe, "dist-streamer", "deadband", defaultBuffer)
+	return &plumber.Segment[framer.StreamerRequest, framer.StreamerResponse]{
+		Pipeline:         pipe,
+		RouteInletsTo:    []address.Address{"requests"},
+		RouteOutletsFrom: []address.Address{"deadband"},
+	}, nil
+}
+
+// sent tracks the last sample forwarded for a channel.
+type sent struct {
+	value float64
+	raw   []byte
+	time  telem.TimeStamp
+}
+
+type filter struct {
+	mu    sync.Mutex
+	bands map[channel.Key]framer.Deadband
+	last  map[channel.Key]sent
+}
+
+func newFilter(bands []framer.Deadband) *filter {
+	f := &filter{}
+	f.set(bands)
+	return f
+}
+
+// set replaces the deadbands applied by the filter. The last sent sample is retained
+// for channels that remain filtered, so changing a threshold doesn't cause a spurious
+// sample to be forwarded.
+func (f *filter) set(bands []framer.Deadband) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bands = make(map[channel.Key]framer.Deadband, len(bands))
+	for _, b := range bands {
+		f.bands[b.Channel] = b
+	}
+	last := make(map[channel.Key]sent, len(bands))
+	for k, s := range f.last {
+		if _, ok := f.bands[k]; ok {
+			last[k] = s
+		}
+	}
+	f.last = last
+}
+
+// apply filters the frame, returning a frame containing only the samples that should
+// be forwarded. Series for channels without a deadband are passed through unchanged,
+// and series with no forwarded samples are dropped.
+func (f *filter) apply(fr framer.Frame, now telem.TimeStamp) framer.Frame {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.bands) == 0 {
+		return fr
+	}
+	var out framer.Frame
+	for i, key := range fr.Keys {
+		b, ok := f.bands[key]
+		if !ok {
+			out.Keys = append(out.Keys, key)
+			out.Series = append(out.Series, fr.Series[i])
+			continue
+		}
+		for _, s := range f.filterSeries(b, fr.Series[i], now) {
+			out.Keys = append(out.Keys, key)
+			out.Series = append(out.Series, s)
+		}
+	}
+	return out
+}
+
+// filterSeries returns the samples in the series that pass the deadband. Forwarded
+// samples are grouped into series of contiguous samples, each aligned to the position
+// of its first sample in the original series, so that they can still be matched
+// against the samples of their index channel.
+func (f *filter) filterSeries(b framer.Deadband, s telem.Series, now telem.TimeStamp) []telem.Series {
+	var (
+		samples  = s.Split()
+		numeric  = s.DataType.IsNumeric() || s.DataType == telem.TimeStampT
+		last, ok = f.last[b.Channel]
+		out      []telem.Series
+		run      *telem.Series
+	)
+	for i, raw := range samples {
+		var v float64
+		if numeric {
+			v = s.Float64At(int64(i))
+		}
+		forward := !ok ||
+			(b.KeepAlive > 0 && telem.TimeSpan(now-last.time) >= b.KeepAlive) ||
+			exceeds(b, last, v, raw, numeric)
+		if !forward {
+			run = nil
+			continue
+		}
+		last, ok = sent{value: v, raw: bytes.Clone(raw), time: now}, true
+		if run == nil {
+			out = append(out, telem.Series{
+				TimeRange: s.TimeRange,
+				DataType:  s.DataType,
+				Alignment: s.Alignment.AddSamples(uint32(i)),
+			})
+			run = &out[len(out)-1]
+		}
+		run.Data = append(run.Data, raw...)
+		if s.DataType.IsVariable() {
+			run.Data = append(run.Data, '\n')
+		}
+	}
+	if ok {
+		f.last[b.Channel] = last
+	}
+	return out
+}
+
+// exceeds returns true if the sample differs from the last sent sample by more than
+// the deadband's thresholds.
+func exceeds(b framer.Deadband, last sent, v float64, raw []byte, numeric bool) bool {
+	if !numeric || (b.Absolute == 0 && b.Percent == 0) {
+		// Compare encoded values so that change-only filtering treats NaNs and
+		// non-numeric samples consistently.
+		return !bytes.Equal(last.raw, raw)
+	}
+	diff := math.Abs(v - last.value)
+	return (b.Absolute > 0 && diff > b.Absolute) ||
+		(b.Percent > 0 && diff > math.Abs(last.value)*b.Percent/100)
+}
@@ -0,0 +1,203 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package deadband_test
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	svcframer "github.com/synnaxlabs/synnax/pkg/service/framer"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Deadband", func() {
+	var (
+		svc                 *svcframer.Service
+		values, other, text channel.Channel
+		w                   *framer.Writer
+	)
+	BeforeEach(func() {
+		svc = MustSucceed(svcframer.NewService(dist.Framer))
+		prefix := uuid.NewString()[:8]
+		values = channel.Channel{Name: prefix + "_values", DataType: telem.Float64T, Virtual: true}
+		other = channel.Channel{Name: prefix + "_other", DataType: telem.Float64T, Virtual: true}
+		text = channel.Channel{Name: prefix + "_text", DataType: telem.StringT, Virtual: true}
+		for _, ch := range []*channel.Channel{&values, &other, &text} {
+			Expect(dist.Channel.NewWriter(nil).Create(ctx, ch)).To(Succeed())
+		}
+		w = MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+			Keys:  channel.Keys{values.Key(), other.Key(), text.Key()},
+			Start: telem.Now(),
+		}))
+		DeferCleanup(func() { Expect(w.Close()).To(Succeed()) })
+	})
+	type stream struct {
+		requests confluence.Inlet[framer.StreamerRequest]
+		frames   confluence.Outlet[framer.StreamerResponse]
+	}
+	open := func(cfg framer.StreamerConfig) stream {
+		cfg.Keys = channel.Keys{values.Key(), other.Key(), text.Key()}
+		s := MustSucceed(svc.NewStreamer(ctx, cfg))
+		sCtx, cancel := signal.Isolated()
+		req, res := confluence.Attach(s, 10)
+		s.Flow(sCtx, confluence.CloseOutputInletsOnExit())
+		// Give the streamer a moment to subscribe to the relay.
+		time.Sleep(10 * time.Millisecond)
+		DeferCleanup(func() {
+			req.Close()
+			Expect(sCtx.Wait()).To(Succeed())
+			cancel()
+		})
+		return stream{requests: req, frames: res}
+	}
+	write := func(key channel.Key, series telem.Series) {
+		Expect(w.Write(framer.Frame{Keys: channel.Keys{key}, Series: []telem.Series{series}})).To(BeTrue())
+	}
+	// received collects the series received for the given key until the number of
+	// received samples reaches count, and then checks that nothing else is received.
+	received := func(s stream, key channel.Key, count int) []telem.Series {
+		var (
+			series  []telem.Series
+			samples int64
+		)
+		Eventually(func(g Gomega) {
+			var res framer.StreamerResponse
+			g.Eventually(s.frames.Outlet()).Should(Receive(&res))
+			for _, ser := range res.Frame.Get(key) {
+				series = append(series, ser)
+				samples += ser.Len()
+			}
+			g.Expect(samples).To(BeEquivalentTo(count))
+		}).Should(Succeed())
+		Consistently(s.frames.Outlet(), 20*time.Millisecond).ShouldNot(Receive())
+		return series
+	}
+	float64s := func(series []telem.Series) (out []float64) {
+		for _, s := range series {
+			out = append(out, telem.Unmarshal[float64](s)...)
+		}
+		return out
+	}
+	deadband := func(key channel.Key, b framer.Deadband) []framer.Deadband {
+		b.Channel = key
+		return []framer.Deadband{b}
+	}
+
+	Describe("Thresholds", func() {
+		It("Should only forward samples that exceed an absolute threshold", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{Absolute: 0.1})})
+			write(values.Key(), telem.NewSeriesV[float64](1, 1.05, 1.2, 1.25, 2))
+			Expect(float64s(received(s, values.Key(), 3))).To(Equal([]float64{1, 1.2, 2}))
+		})
+		It("Should only forward samples that exceed a percent threshold", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{Percent: 5})})
+			write(values.Key(), telem.NewSeriesV[float64](100, 104, 106, 111, 112))
+			Expect(float64s(received(s, values.Key(), 3))).To(Equal([]float64{100, 106, 112}))
+		})
+		It("Should only forward changed samples when no thresholds are set", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{})})
+			write(values.Key(), telem.NewSeriesV[float64](1, 1, 1, 2, 2, 1))
+			Expect(float64s(received(s, values.Key(), 3))).To(Equal([]float64{1, 2, 1}))
+		})
+		It("Should compare against the last sample sent in a previous frame", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{})})
+			write(values.Key(), telem.NewSeriesV[float64](1, 1))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{1}))
+			write(values.Key(), telem.NewSeriesV[float64](1, 1))
+			Consistently(s.frames.Outlet(), 20*time.Millisecond).ShouldNot(Receive())
+			write(values.Key(), telem.NewSeriesV[float64](1, 3))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{3}))
+		})
+		It("Should forward variable length samples only when they change", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(text.Key(), framer.Deadband{Absolute: 100})})
+			write(text.Key(), telem.NewStringsV("open", "open", "closed", "closed"))
+			series := received(s, text.Key(), 2)
+			var strs []string
+			for _, ser := range series {
+				strs = append(strs, telem.UnmarshalStrings(ser.Data)...)
+			}
+			Expect(strs).To(Equal([]string{"open", "closed"}))
+		})
+	})
+
+	Describe("Alignment", func() {
+		It("Should align forwarded samples with their position in the original series", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{})})
+			write(values.Key(), telem.NewSeriesV[float64](1, 1, 2, 3, 3))
+			series := received(s, values.Key(), 3)
+			Expect(series).To(HaveLen(2))
+			Expect(series[1].Alignment).To(Equal(series[0].Alignment.AddSamples(2)))
+			Expect(float64s(series[1:])).To(Equal([]float64{2, 3}))
+		})
+	})
+
+	Describe("Keep Alive", func() {
+		It("Should forward an unchanged sample after the keep alive interval", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{
+				KeepAlive: 50 * telem.Millisecond,
+			})})
+			write(values.Key(), telem.NewSeriesV[float64](1))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{1}))
+			time.Sleep(60 * time.Millisecond)
+			write(values.Key(), telem.NewSeriesV[float64](1))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{1}))
+		})
+	})
+
+	Describe("Unfiltered Channels", func() {
+		It("Should pass through channels without a deadband", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{})})
+			write(other.Key(), telem.NewSeriesV[float64](1, 1, 1))
+			Expect(float64s(received(s, other.Key(), 3))).To(Equal([]float64{1, 1, 1}))
+		})
+	})
+
+	Describe("Updating", func() {
+		It("Should replace the deadbands when a new request is sent", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{})})
+			write(values.Key(), telem.NewSeriesV[float64](1, 1))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{1}))
+			s.requests.Inlet() <- framer.StreamerRequest{
+				Keys:      channel.Keys{values.Key(), other.Key(), text.Key()},
+				Deadbands: []framer.Deadband{},
+			}
+			time.Sleep(10 * time.Millisecond)
+			write(values.Key(), telem.NewSeriesV[float64](1, 1))
+			Expect(float64s(received(s, values.Key(), 2))).To(Equal([]float64{1, 1}))
+		})
+		It("Should keep the deadbands when a request only updates keys", func() {
+			s := open(framer.StreamerConfig{Deadbands: deadband(values.Key(), framer.Deadband{})})
+			write(values.Key(), telem.NewSeriesV[float64](1, 1))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{1}))
+			s.requests.Inlet() <- framer.StreamerRequest{
+				Keys: channel.Keys{values.Key(), other.Key(), text.Key()},
+			}
+			time.Sleep(10 * time.Millisecond)
+			write(values.Key(), telem.NewSeriesV[float64](1, 2))
+			Expect(float64s(received(s, values.Key(), 1))).To(Equal([]float64{2}))
+		})
+	})
+
+	Describe("Validation", func() {
+		It("Should return an error if a threshold is negative", func() {
+			Expect(svc.NewStreamer(ctx, framer.StreamerConfig{
+				Keys:      channel.Keys{values.Key()},
+				Deadbands: deadband(values.Key(), framer.Deadband{Absolute: -1}),
+			})).Error().To(MatchError(ContainSubstring("absolute")))
+		})
+	})
+})
@@ -12,7 +12,9 @@ package framer
 import (
 	"context"
 
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
 	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/service/framer/deadband"
 	"github.com/synnaxlabs/synnax/pkg/service/framer/downsampler"
 )
 
@@ -36,12 +38,37 @@ func (s *Service) NewDeleter() framer.Deleter {
 	return s.Internal.NewDeleter()
 }
 
+// Usage returns the disk usage of the given channels. See framer.Service.Usage for
+// more details.
+func (s *Service) Usage(ctx context.Context, keys ...channel.Key) ([]framer.ChannelUsage, error) {
+	return s.Internal.Usage(ctx, keys...)
+}
+
+// GarbageCollect reclaims the space occupied by the deleted data of channels. See
+// framer.Service.GarbageCollect for more details.
+func (s *Service) GarbageCollect(ctx context.Context, cfg framer.GCConfig, onProgress func(framer.GCProgress)) error {
+	return s.Internal.GarbageCollect(ctx, cfg, onProgress)
+}
+
 func (s *Service) NewStreamer(ctx context.Context, cfg framer.StreamerConfig) (framer.Streamer, error) {
+	for _, d := range cfg.Deadbands {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		streamer framer.Streamer
+		err      error
+	)
 	if cfg.DownsampleFactor > 1 {
-		return downsampler.NewStreamer(ctx, cfg, s.Internal)
+		streamer, err = downsampler.NewStreamer(ctx, cfg, s.Internal)
 	} else {
-		return s.Internal.NewStreamer(ctx, cfg)
+		streamer, err = s.Internal.NewStreamer(ctx, cfg)
+	}
+	if err != nil || len(cfg.Deadbands) == 0 {
+		return streamer, err
 	}
+	return deadband.NewStreamer(ctx, cfg, streamer)
 }
 
 func NewService(framerSvc *framer.Service) (*Service, error) {
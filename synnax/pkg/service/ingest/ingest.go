@@ -0,0 +1,564 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package ingest implements bulk ingestion of historical telemetry from tabular files
+// (such as CSV exports from other systems) into Synnax channels.
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/control"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+const (
+	// DefaultChunkSize is the default number of rows written to the cluster in a single
+	// frame.
+	DefaultChunkSize = 10000
+	// maxReportedErrors is the maximum number of error messages included in a Report.
+	maxReportedErrors = 50
+)
+
+// Config is the configuration for opening the ingestion service.
+type Config struct {
+	alamos.Instrumentation
+	// DB is the database used to create channels.
+	// [REQUIRED]
+	DB *gorp.DB
+	// Channel is used to retrieve existing channels and create missing ones.
+	// [REQUIRED]
+	Channel channel.ReadWriteable
+	// Framer is used to open writers to persist ingested telemetry.
+	// [REQUIRED]
+	Framer framer.Writable
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for opening the ingestion service.
+	// This configuration is not valid on its own, and must be overridden by the
+	// required fields specified in Config.
+	DefaultConfig = Config{}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.DB = override.Nil(c.DB, other.DB)
+	c.Channel = override.Nil(c.Channel, other.Channel)
+	c.Framer = override.Nil(c.Framer, other.Framer)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("ingest")
+	validate.NotNil(v, "DB", c.DB)
+	validate.NotNil(v, "Channel", c.Channel)
+	validate.NotNil(v, "Framer", c.Framer)
+	return v.Error()
+}
+
+// Service ingests tabular files into channels.
+type Service struct{ Config }
+
+// OpenService opens a new ingestion service using the provided configuration.
+func OpenService(configs ...Config) (*Service, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{Config: cfg}, nil
+}
+
+// Column maps a column in a file to a channel.
+type Column struct {
+	// Column is the name of the column in the file header.
+	Column string `json:"column" msgpack:"column"`
+	// Channel is the name of the channel to write the column to. If a channel with the
+	// name does not exist, it will be created. Defaults to the name of the column.
+	Channel string `json:"channel" msgpack:"channel"`
+	// DataType overrides the data type of the channel created for the column. If not
+	// provided, the data type is inferred from the column's values. Ignored if the
+	// channel already exists.
+	DataType telem.DataType `json:"data_type" msgpack:"data_type"`
+}
+
+func (c Column) channelName() string { return lo.Ternary(c.Channel != "", c.Channel, c.Column) }
+
+// Mapping maps the columns of a file to channels.
+type Mapping struct {
+	// Index is the column containing the timestamp of each row, which is written to
+	// an index channel. The data type of the column is ignored.
+	Index Column `json:"index" msgpack:"index"`
+	// Columns are the data columns to ingest. Each column is written to a channel
+	// indexed by the Index channel. If empty, every column in the file other than
+	// the index column is ingested into a channel with the same name as the column.
+	Columns []Column `json:"columns" msgpack:"columns"`
+}
+
+// Request is a request to ingest a file.
+type Request struct {
+	// Mapping maps the columns of the file to channels.
+	Mapping Mapping `json:"mapping" msgpack:"mapping"`
+	// Delimiter is the single character separating columns in the file. Defaults to a
+	// comma.
+	Delimiter string `json:"delimiter" msgpack:"delimiter"`
+	// TimeFormat is the format of the index column. Defaults to TimeFormatAuto.
+	TimeFormat TimeFormat `json:"time_format" msgpack:"time_format"`
+	// ChunkSize is the number of rows written to the cluster in a single frame.
+	// Defaults to DefaultChunkSize.
+	ChunkSize int `json:"chunk_size" msgpack:"chunk_size"`
+}
+
+// ChannelReport describes the channel a column of a file is ingested into.
+type ChannelReport struct {
+	// Column is the name of the column in the file.
+	Column string `json:"column" msgpack:"column"`
+	// Channel is the channel the column is written to. If the channel does not exist,
+	// it has a zero key and will be created during ingestion.
+	Channel channel.Channel `json:"channel" msgpack:"channel"`
+	// Exists is true if the channel existed in the cluster before the ingestion.
+	Exists bool `json:"exists" msgpack:"exists"`
+}
+
+// Report is the result of validating or ingesting a file.
+type Report struct {
+	// Rows is the number of data rows in the file.
+	Rows int `json:"rows" msgpack:"rows"`
+	// TimeRange is the time range spanned by the rows in the file. The end of the
+	// range is exclusive.
+	TimeRange telem.TimeRange `json:"time_range" msgpack:"time_range"`
+	// TimeFormat is the format used to parse the index column.
+	TimeFormat TimeFormat `json:"time_format" msgpack:"time_format"`
+	// Index describes the index channel of the ingestion.
+	Index ChannelReport `json:"index" msgpack:"index"`
+	// Channels describes the data channels of the ingestion.
+	Channels []ChannelReport `json:"channels" msgpack:"channels"`
+	// ErrorCount is the total number of problems found in the file.
+	ErrorCount int `json:"error_count" msgpack:"error_count"`
+	// Errors contains messages describing the first problems found in the file.
+	Errors []string `json:"errors" msgpack:"errors"`
+}
+
+// Valid returns true if no problems were found in the file.
+func (r Report) Valid() bool { return r.ErrorCount == 0 }
+
+// Keys returns the keys of the index and data channels in the report.
+func (r Report) Keys() channel.Keys {
+	keys := make(channel.Keys, 0, len(r.Channels)+1)
+	keys = append(keys, r.Index.Channel.Key())
+	for _, ch := range r.Channels {
+		keys = append(keys, ch.Channel.Key())
+	}
+	return keys
+}
+
+func (r *Report) addError(format string, args ...any) {
+	r.ErrorCount++
+	if len(r.Errors) < maxReportedErrors {
+		r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+	}
+}
+
+// plan is the result of analyzing a file, containing everything needed to ingest it.
+type plan struct {
+	Report
+	delimiter rune
+	chunkSize int
+	indexCol  int
+	dataCols  []int
+}
+
+// Validate performs a dry run of ingesting the file in r, returning a report that
+// describes the channels that would be written to, the channels that would be created,
+// and any problems found in the file. Validate does not modify the cluster. A non-nil
+// error is only returned if the request is malformed or the file could not be read.
+func (s *Service) Validate(ctx context.Context, r io.Reader, req Request) (Report, error) {
+	p, err := s.analyze(ctx, r, req)
+	return p.Report, err
+}
+
+// Ingest validates the file in r, creates any channels that do not exist, and writes
+// the contents of the file to the cluster in chunks. The file is read twice: once to
+// validate it and once to write it. If the file fails validation, Ingest returns the
+// validation report along with an error, and does not modify the cluster.
+func (s *Service) Ingest(ctx context.Context, r io.ReadSeeker, req Request) (Report, error) {
+	ctx, span := s.T.Prod(ctx, "ingest")
+	p, err := s.analyze(ctx, r, req)
+	if err != nil {
+		return p.Report, span.EndWith(err)
+	}
+	if !p.Valid() {
+		return p.Report, span.EndWith(errors.Wrapf(
+			validate.Error,
+			"file has %d problems, first: %s",
+			p.ErrorCount,
+			p.Errors[0],
+		))
+	}
+	if p.Rows == 0 {
+		return p.Report, span.EndWith(nil)
+	}
+	if err = s.createChannels(ctx, &p); err != nil {
+		return p.Report, span.EndWith(err)
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return p.Report, span.EndWith(err)
+	}
+	return p.Report, span.EndWith(s.write(ctx, r, p))
+}
+
+// Resolve reads the header of the file in r and matches the mapped columns against
+// existing channels, returning a report that describes the channels that would be
+// written to and created without reading any rows. Resolve does not modify the
+// cluster.
+func (s *Service) Resolve(ctx context.Context, r io.Reader, req Request) (Report, error) {
+	p, _, err := s.resolve(ctx, r, req)
+	return p.Report, err
+}
+
+func (s *Service) analyze(ctx context.Context, r io.Reader, req Request) (p plan, err error) {
+	p, t, err := s.resolve(ctx, r, req)
+	if err != nil || !p.Valid() {
+		return p, err
+	}
+	return p, s.scan(t, &p)
+}
+
+// resolve parses the request and the header of the file, returning a plan with the
+// channels of the ingestion resolved and the table positioned at the first row.
+func (s *Service) resolve(ctx context.Context, r io.Reader, req Request) (p plan, t *table, err error) {
+	p.TimeFormat = req.TimeFormat
+	if err = p.TimeFormat.validate(); err != nil {
+		return p, nil, errors.Wrap(validate.Error, err.Error())
+	}
+	p.delimiter, p.chunkSize = ',', lo.Ternary(req.ChunkSize > 0, req.ChunkSize, DefaultChunkSize)
+	if req.Delimiter != "" {
+		if utf8.RuneCountInString(req.Delimiter) != 1 {
+			return p, nil, validate.FieldError{Field: "delimiter", Message: "must be a single character"}
+		}
+		p.delimiter, _ = utf8.DecodeRuneInString(req.Delimiter)
+	}
+	if req.Mapping.Index.Column == "" {
+		return p, nil, validate.FieldError{Field: "mapping.index.column", Message: "must be provided"}
+	}
+	if t, err = openTable(r, p.delimiter); err != nil {
+		return p, nil, err
+	}
+	columns := req.Mapping.Columns
+	if len(columns) == 0 {
+		for _, h := range t.header {
+			if h != req.Mapping.Index.Column {
+				columns = append(columns, Column{Column: h})
+			}
+		}
+	}
+	s.resolveColumns(ctx, &p, t, req.Mapping.Index, columns)
+	return p, t, nil
+}
+
+// resolveColumns locates the mapped columns in the header of the table and matches
+// them against existing channels, adding an error to the plan if the mapping is
+// invalid.
+func (s *Service) resolveColumns(
+	ctx context.Context,
+	p *plan,
+	t *table,
+	index Column,
+	columns []Column,
+) {
+	var (
+		names = make([]string, 0, len(columns)+1)
+		all   = append([]Column{index}, columns...)
+	)
+	for _, c := range all {
+		names = append(names, c.channelName())
+	}
+	for i, c := range all {
+		col := t.columnIndex(c.Column)
+		if col == -1 {
+			p.addError("column %s not found in file", c.Column)
+		}
+		if lo.Contains(names[:i], names[i]) {
+			p.addError("channel %s is mapped to more than once", names[i])
+		}
+		if i == 0 {
+			p.indexCol = col
+		} else {
+			p.dataCols = append(p.dataCols, col)
+		}
+	}
+	var existing []channel.Channel
+	if err := s.Channel.NewRetrieve().
+		WhereNames(lo.Map(names, func(n string, _ int) string {
+			return regexp.QuoteMeta(n)
+		})...).
+		Entries(&existing).
+		Exec(ctx, nil); err != nil {
+		p.addError("failed to retrieve channels: %s", err)
+		return
+	}
+	p.Channels = make([]ChannelReport, len(columns))
+	for i, c := range all {
+		rep := ChannelReport{
+			Column:  c.Column,
+			Channel: channel.Channel{Name: names[i], DataType: c.DataType},
+		}
+		matches := lo.Filter(existing, func(ch channel.Channel, _ int) bool {
+			return ch.Name == names[i]
+		})
+		if len(matches) > 1 {
+			p.addError("multiple channels are named %s", names[i])
+		} else if len(matches) == 1 {
+			rep.Channel, rep.Exists = matches[0], true
+		}
+		if i == 0 {
+			p.Index = rep
+		} else {
+			p.Channels[i-1] = rep
+		}
+	}
+	p.validateChannels()
+}
+
+// validateChannels checks that the channels in the plan can be written to together.
+func (p *plan) validateChannels() {
+	idx := &p.Index
+	if idx.Exists && !idx.Channel.IsIndex {
+		p.addError("channel %s is not an index channel", idx.Channel.Name)
+	}
+	if !idx.Exists {
+		idx.Channel.DataType = telem.TimeStampT
+		idx.Channel.IsIndex = true
+	}
+	for _, c := range p.Channels {
+		if !c.Exists {
+			if c.Channel.DataType != telem.UnknownT {
+				if _, err := newValueParser(c.Channel.DataType); err != nil {
+					p.addError("column %s: %s", c.Column, err)
+				}
+			}
+			continue
+		}
+		if c.Channel.IsIndex {
+			p.addError("channel %s is an index channel", c.Channel.Name)
+		} else if !idx.Exists || c.Channel.Index() != idx.Channel.Key() {
+			p.addError(
+				"channel %s is not indexed by channel %s",
+				c.Channel.Name,
+				idx.Channel.Name,
+			)
+		} else if _, err := newValueParser(c.Channel.DataType); err != nil {
+			p.addError("column %s: %s", c.Column, err)
+		}
+	}
+}
+
+// scan reads every row in the table, validating timestamps and values and inferring
+// the data types of new channels.
+func (s *Service) scan(t *table, p *plan) error {
+	var (
+		parsers    = make([]valueParser, len(p.Channels))
+		inferences = make([]*inference, len(p.Channels))
+		buf        = make([]byte, telem.Float64T.Density())
+		last       telem.TimeStamp
+	)
+	for i, c := range p.Channels {
+		if c.Channel.DataType == telem.UnknownT {
+			inferences[i] = &inference{}
+		} else {
+			parsers[i] = lo.Must(newValueParser(c.Channel.DataType))
+		}
+	}
+	for {
+		row, line, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			p.addError("%s", err)
+			return nil
+		}
+		ts, err := p.parseTime(row[p.indexCol])
+		if err != nil {
+			p.addError("line %d: column %s: %s", line, p.Index.Column, err)
+		} else if p.Rows > 0 && ts <= last {
+			p.addError("line %d: timestamps must be strictly increasing", line)
+		} else {
+			if p.Rows == 0 {
+				p.TimeRange.Start = ts
+			}
+			last = ts
+		}
+		for i, col := range p.dataCols {
+			v := row[col]
+			if v == "" {
+				p.addError("line %d: column %s: missing value", line, p.Channels[i].Column)
+				continue
+			}
+			if inferences[i] != nil {
+				err = inferences[i].observe(v)
+			} else {
+				err = parsers[i](buf, v)
+			}
+			if err != nil {
+				p.addError("line %d: column %s: %s", line, p.Channels[i].Column, err)
+			}
+		}
+		p.Rows++
+	}
+	if p.Rows > 0 {
+		p.TimeRange.End = last + 1
+	}
+	for i, inf := range inferences {
+		if inf != nil {
+			p.Channels[i].Channel.DataType = inf.dataType()
+		}
+	}
+	return nil
+}
+
+// parseTime parses a timestamp from the index column, detecting the time format from
+// the first value if necessary.
+func (p *plan) parseTime(v string) (telem.TimeStamp, error) {
+	if p.TimeFormat == TimeFormatAuto {
+		f, err := detectTimeFormat(v)
+		if err != nil {
+			return 0, err
+		}
+		p.TimeFormat = f
+	}
+	return parseTime(p.TimeFormat, v)
+}
+
+// createChannels creates the channels in the plan that do not exist yet, updating the
+// plan with the created channels.
+func (s *Service) createChannels(ctx context.Context, p *plan) error {
+	return s.DB.WithTx(ctx, func(tx gorp.Tx) error {
+		w := s.Channel.NewWriter(tx)
+		if !p.Index.Exists {
+			if err := w.Create(ctx, &p.Index.Channel); err != nil {
+				return err
+			}
+		}
+		for i := range p.Channels {
+			ch := &p.Channels[i].Channel
+			if p.Channels[i].Exists {
+				continue
+			}
+			ch.Leaseholder = p.Index.Channel.Leaseholder
+			ch.LocalIndex = p.Index.Channel.LocalKey
+			if err := w.Create(ctx, ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// write writes the rows of the table to the cluster in chunks of the plan's chunk size.
+func (s *Service) write(ctx context.Context, r io.Reader, p plan) (err error) {
+	t, err := openTable(r, p.delimiter)
+	if err != nil {
+		return err
+	}
+	w, err := s.Framer.OpenWriter(ctx, framer.WriterConfig{
+		ControlSubject:    control.Subject{Key: uuid.NewString(), Name: "ingest"},
+		Keys:              p.Keys(),
+		Start:             p.TimeRange.Start,
+		ErrOnUnauthorized: config.True(),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, w.Close()) }()
+	var (
+		dataTypes = make([]telem.DataType, 0, len(p.Channels)+1)
+		parsers   = make([]valueParser, 0, len(p.Channels)+1)
+		cols      = append([]int{p.indexCol}, p.dataCols...)
+		marshalTS = telem.MarshalF[telem.TimeStamp](telem.TimeStampT)
+	)
+	dataTypes = append(dataTypes, telem.TimeStampT)
+	parsers = append(parsers, func(b []byte, v string) error {
+		ts, err := parseTime(p.TimeFormat, v)
+		marshalTS(b, ts)
+		return err
+	})
+	for _, c := range p.Channels {
+		dataTypes = append(dataTypes, c.Channel.DataType)
+		parsers = append(parsers, lo.Must(newValueParser(c.Channel.DataType)))
+	}
+	var (
+		n      int
+		series []telem.Series
+	)
+	flush := func() error {
+		for i := range series {
+			series[i].Data = series[i].Data[:n*int(dataTypes[i].Density())]
+		}
+		fr := framer.Frame{Keys: p.Keys(), Series: series}
+		if !w.Write(fr) || !w.Commit() {
+			if err := w.Error(); err != nil {
+				return err
+			}
+			return errors.New("[ingest] - failed to write to channels")
+		}
+		series, n = nil, 0
+		return nil
+	}
+	for {
+		row, line, err := t.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if series == nil {
+			series = make([]telem.Series, len(dataTypes))
+			for i, dt := range dataTypes {
+				series[i] = telem.Series{
+					DataType: dt,
+					Data:     make([]byte, p.chunkSize*int(dt.Density())),
+				}
+			}
+		}
+		for i, col := range cols {
+			d := int(dataTypes[i].Density())
+			if err = parsers[i](series[i].Data[n*d:(n+1)*d], row[col]); err != nil {
+				return errors.Wrapf(err, "line %d", line)
+			}
+		}
+		if n++; n == p.chunkSize {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if n > 0 {
+		return flush()
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package ingest_test
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/service/ingest"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Ingest", func() {
+	var (
+		svc    *ingest.Service
+		prefix string
+	)
+	BeforeEach(func() {
+		svc = MustSucceed(ingest.OpenService(ingest.Config{
+			DB:      dist.Storage.Gorpify(),
+			Channel: dist.Channel,
+			Framer:  dist.Framer,
+		}))
+		prefix = uuid.NewString()[:8] + "_"
+	})
+	read := func(ch channel.Channel) telem.Series {
+		f := MustSucceed(dist.Storage.TS.Read(ctx, telem.TimeRangeMax, ch.Key().StorageKey()))
+		Expect(f.Series).To(HaveLen(1))
+		return f.Series[0]
+	}
+	Describe("Validate", func() {
+		It("Should infer data types and report channels to create", func() {
+			file := prefix + "time," + prefix + "count," + prefix + "temp\n" +
+				"1700000000,1,20.5\n" +
+				"1700000001,2,21\n"
+			r := MustSucceed(svc.Validate(ctx, strings.NewReader(file), ingest.Request{
+				Mapping: ingest.Mapping{Index: ingest.Column{Column: prefix + "time"}},
+			}))
+			Expect(r.Valid()).To(BeTrue())
+			Expect(r.Rows).To(Equal(2))
+			Expect(r.TimeFormat).To(Equal(ingest.TimeFormatUnixSecond))
+			Expect(r.TimeRange.Start).To(Equal(1700000000 * telem.SecondTS))
+			Expect(r.TimeRange.End).To(Equal(1700000001*telem.SecondTS + 1))
+			Expect(r.Index.Exists).To(BeFalse())
+			Expect(r.Index.Channel.IsIndex).To(BeTrue())
+			Expect(r.Channels).To(HaveLen(2))
+			Expect(r.Channels[0].Channel.DataType).To(Equal(telem.Int64T))
+			Expect(r.Channels[1].Channel.DataType).To(Equal(telem.Float64T))
+			By("Not creating any channels")
+			var chs []channel.Channel
+			Expect(dist.Channel.NewRetrieve().
+				WhereNames(prefix+"time").
+				Entries(&chs).
+				Exec(ctx, nil)).To(Succeed())
+			Expect(chs).To(BeEmpty())
+		})
+		It("Should report invalid values and out of order timestamps", func() {
+			file := "time,value\n" +
+				"2024-01-01T00:00:01Z,1\n" +
+				"2024-01-01T00:00:00Z,cat\n" +
+				"2024-01-01T00:00:02Z,\n"
+			r := MustSucceed(svc.Validate(ctx, strings.NewReader(file), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+			}))
+			Expect(r.Valid()).To(BeFalse())
+			Expect(r.TimeFormat).To(Equal(ingest.TimeFormatISO8601))
+			Expect(r.ErrorCount).To(Equal(3))
+			Expect(r.Errors[0]).To(ContainSubstring("line 3: timestamps must be strictly increasing"))
+			Expect(r.Errors[1]).To(ContainSubstring("not numeric"))
+			Expect(r.Errors[2]).To(ContainSubstring("line 4: column value: missing value"))
+		})
+		It("Should report mapped columns that are missing from the file", func() {
+			r := MustSucceed(svc.Validate(ctx, strings.NewReader("time,a\n1,2\n"), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "b", Channel: prefix + "b"}},
+				},
+			}))
+			Expect(r.Errors).To(ConsistOf("column b not found in file"))
+		})
+		It("Should validate values against the data type of existing channels", func() {
+			idx := channel.Channel{Name: prefix + "time", DataType: telem.TimeStampT, IsIndex: true}
+			Expect(dist.Channel.Create(ctx, &idx)).To(Succeed())
+			data := channel.Channel{Name: prefix + "value", DataType: telem.Uint8T, LocalIndex: idx.LocalKey}
+			Expect(dist.Channel.Create(ctx, &data)).To(Succeed())
+			file := "time,value\n1,255\n2,256\n"
+			r := MustSucceed(svc.Validate(ctx, strings.NewReader(file), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+				TimeFormat: ingest.TimeFormatUnixNanosecond,
+			}))
+			Expect(r.Index.Exists).To(BeTrue())
+			Expect(r.Channels[0].Exists).To(BeTrue())
+			Expect(r.Errors).To(ConsistOf(ContainSubstring(`cannot parse "256" as uint8`)))
+		})
+		It("Should report existing channels indexed by a different channel", func() {
+			idx := channel.Channel{Name: prefix + "time", DataType: telem.TimeStampT, IsIndex: true}
+			Expect(dist.Channel.Create(ctx, &idx)).To(Succeed())
+			data := channel.Channel{Name: prefix + "value", DataType: telem.Float64T, Rate: 1 * telem.Hz}
+			Expect(dist.Channel.Create(ctx, &data)).To(Succeed())
+			r := MustSucceed(svc.Validate(ctx, strings.NewReader("time,value\n1,1\n"), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+			}))
+			Expect(r.Errors).To(ConsistOf(ContainSubstring("is not indexed by channel")))
+		})
+		It("Should return an error if no index column is provided", func() {
+			_, err := svc.Validate(ctx, strings.NewReader("time\n1\n"), ingest.Request{})
+			Expect(err).To(MatchError(ContainSubstring("mapping.index.column")))
+		})
+	})
+	Describe("Resolve", func() {
+		It("Should resolve channels without reading any rows", func() {
+			idx := channel.Channel{Name: prefix + "time", DataType: telem.TimeStampT, IsIndex: true}
+			Expect(dist.Channel.Create(ctx, &idx)).To(Succeed())
+			file := "time,value\n1,cat\n"
+			r := MustSucceed(svc.Resolve(ctx, strings.NewReader(file), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+			}))
+			Expect(r.Valid()).To(BeTrue())
+			Expect(r.Rows).To(BeZero())
+			Expect(r.Index.Exists).To(BeTrue())
+			Expect(r.Index.Channel.Key()).To(Equal(idx.Key()))
+			Expect(r.Channels).To(HaveLen(1))
+			Expect(r.Channels[0].Exists).To(BeFalse())
+		})
+	})
+	Describe("Ingest", func() {
+		It("Should create channels and write the file in chunks", func() {
+			file := "ts;pressure;valve\n" +
+				"1000;1.5;0\n" +
+				"2000;2.5;1\n" +
+				"3000;3.5;0\n"
+			r := MustSucceed(svc.Ingest(ctx, strings.NewReader(file), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index: ingest.Column{Column: "ts", Channel: prefix + "ts"},
+					Columns: []ingest.Column{
+						{Column: "pressure", Channel: prefix + "pressure", DataType: telem.Float32T},
+						{Column: "valve", Channel: prefix + "valve", DataType: telem.Uint8T},
+					},
+				},
+				Delimiter:  ";",
+				TimeFormat: ingest.TimeFormatUnixMillisecond,
+				ChunkSize:  2,
+			}))
+			Expect(r.Rows).To(Equal(3))
+			Expect(r.Index.Channel.Key()).ToNot(BeZero())
+			Expect(r.Channels[0].Channel.LocalIndex).To(Equal(r.Index.Channel.LocalKey))
+			Expect(read(r.Index.Channel).Data).To(Equal(telem.NewSecondsTSV(1, 2, 3).Data))
+			Expect(read(r.Channels[0].Channel).Data).To(Equal(telem.NewSeriesV[float32](1.5, 2.5, 3.5).Data))
+			Expect(read(r.Channels[1].Channel).Data).To(Equal(telem.NewSeriesV[uint8](0, 1, 0).Data))
+		})
+		It("Should append to existing channels", func() {
+			req := ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+			}
+			first := MustSucceed(svc.Ingest(ctx, strings.NewReader("time,value\n1,10\n2,20\n"), req))
+			second := MustSucceed(svc.Ingest(ctx, strings.NewReader("time,value\n3,30\n"), req))
+			Expect(second.Index.Exists).To(BeTrue())
+			Expect(second.Channels[0].Channel.Key()).To(Equal(first.Channels[0].Channel.Key()))
+			f := MustSucceed(dist.Storage.TS.Read(ctx, telem.TimeRangeMax, first.Channels[0].Channel.Key().StorageKey()))
+			Expect(f.Series).To(HaveLen(2))
+			Expect(f.Series[1].Data).To(Equal(telem.NewSeriesV[int64](30).Data))
+		})
+		It("Should not modify the cluster if the file is invalid", func() {
+			r, err := svc.Ingest(ctx, strings.NewReader("time,value\n1,a\n"), ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+			})
+			Expect(err).To(HaveOccurredAs(validate.Error))
+			Expect(r.Index.Exists).To(BeFalse())
+			var chs []channel.Channel
+			Expect(dist.Channel.NewRetrieve().
+				WhereNames(prefix+"time").
+				Entries(&chs).
+				Exec(ctx, nil)).To(Succeed())
+			Expect(chs).To(BeEmpty())
+		})
+		It("Should return an error when writing over existing data", func() {
+			req := ingest.Request{
+				Mapping: ingest.Mapping{
+					Index:   ingest.Column{Column: "time", Channel: prefix + "time"},
+					Columns: []ingest.Column{{Column: "value", Channel: prefix + "value"}},
+				},
+			}
+			MustSucceed(svc.Ingest(ctx, strings.NewReader("time,value\n1,10\n2,20\n"), req))
+			_, err := svc.Ingest(ctx, strings.NewReader("time,value\n2,10\n"), req)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
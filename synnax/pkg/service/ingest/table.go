@@ -0,0 +1,219 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package ingest
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/telem"
+)
+
+// TimeFormat is the format of the values in the timestamp column of a file.
+type TimeFormat string
+
+const (
+	// TimeFormatAuto detects the format of the timestamp column from its first value.
+	// RFC3339 strings are parsed as ISO8601, and numeric values are interpreted as unix
+	// epoch timestamps with a precision inferred from their magnitude.
+	TimeFormatAuto TimeFormat = ""
+	// TimeFormatISO8601 parses timestamps as RFC3339 strings with optional fractional
+	// seconds.
+	TimeFormatISO8601 TimeFormat = "iso8601"
+	// TimeFormatUnixSecond parses timestamps as (possibly fractional) seconds since the
+	// unix epoch.
+	TimeFormatUnixSecond TimeFormat = "unix_s"
+	// TimeFormatUnixMillisecond parses timestamps as milliseconds since the unix epoch.
+	TimeFormatUnixMillisecond TimeFormat = "unix_ms"
+	// TimeFormatUnixMicrosecond parses timestamps as microseconds since the unix epoch.
+	TimeFormatUnixMicrosecond TimeFormat = "unix_us"
+	// TimeFormatUnixNanosecond parses timestamps as nanoseconds since the unix epoch.
+	TimeFormatUnixNanosecond TimeFormat = "unix_ns"
+)
+
+func (f TimeFormat) validate() error {
+	switch f {
+	case TimeFormatAuto, TimeFormatISO8601, TimeFormatUnixSecond,
+		TimeFormatUnixMillisecond, TimeFormatUnixMicrosecond, TimeFormatUnixNanosecond:
+		return nil
+	}
+	return errors.Newf("unknown time format %s", f)
+}
+
+// detectTimeFormat infers the time format of a timestamp column from its first value.
+func detectTimeFormat(v string) (TimeFormat, error) {
+	if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return TimeFormatISO8601, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return TimeFormatAuto, errors.Newf("cannot detect time format of %q", v)
+	}
+	switch f = math.Abs(f); {
+	case f < 1e11:
+		return TimeFormatUnixSecond, nil
+	case f < 1e14:
+		return TimeFormatUnixMillisecond, nil
+	case f < 1e17:
+		return TimeFormatUnixMicrosecond, nil
+	default:
+		return TimeFormatUnixNanosecond, nil
+	}
+}
+
+// parseTime parses the provided value into a timestamp using the given format, which
+// must not be TimeFormatAuto.
+func parseTime(format TimeFormat, v string) (telem.TimeStamp, error) {
+	var span telem.TimeSpan
+	switch format {
+	case TimeFormatISO8601:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return 0, errors.Newf("invalid ISO8601 timestamp %q", v)
+		}
+		return telem.NewTimeStamp(t), nil
+	case TimeFormatUnixSecond:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, errors.Newf("invalid unix timestamp %q", v)
+		}
+		return telem.TimeStamp(math.Round(f * float64(telem.Second))), nil
+	case TimeFormatUnixMillisecond:
+		span = telem.Millisecond
+	case TimeFormatUnixMicrosecond:
+		span = telem.Microsecond
+	default:
+		span = telem.Nanosecond
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, errors.Newf("invalid unix timestamp %q", v)
+	}
+	return telem.TimeStamp(i) * telem.TimeStamp(span), nil
+}
+
+// valueParser parses a string value and encodes it into b, which must be at least the
+// density of the parser's data type.
+type valueParser func(b []byte, v string) error
+
+// newValueParser returns a valueParser that encodes values as the provided data type.
+func newValueParser(dt telem.DataType) (valueParser, error) {
+	bits := int(dt.Density()) * 8
+	switch dt {
+	case telem.Float64T, telem.Float32T:
+		m := telem.MarshalF[float64](dt)
+		return func(b []byte, v string) error {
+			f, err := strconv.ParseFloat(v, bits)
+			if err != nil {
+				return errors.Newf("cannot parse %q as %s", v, dt)
+			}
+			m(b, f)
+			return nil
+		}, nil
+	case telem.Int64T, telem.Int32T, telem.Int16T, telem.Int8T:
+		m := telem.MarshalF[int64](dt)
+		return func(b []byte, v string) error {
+			i, err := strconv.ParseInt(v, 10, bits)
+			if err != nil {
+				return errors.Newf("cannot parse %q as %s", v, dt)
+			}
+			m(b, i)
+			return nil
+		}, nil
+	case telem.Uint64T, telem.Uint32T, telem.Uint16T, telem.Uint8T:
+		m := telem.MarshalF[uint64](dt)
+		return func(b []byte, v string) error {
+			u, err := strconv.ParseUint(v, 10, bits)
+			if err != nil {
+				return errors.Newf("cannot parse %q as %s", v, dt)
+			}
+			m(b, u)
+			return nil
+		}, nil
+	}
+	return nil, errors.Newf("data type %s is not supported for ingestion", dt)
+}
+
+// inference tracks the narrowest numeric data type that can represent every value seen
+// in a column.
+type inference struct {
+	notInt bool
+}
+
+func (i *inference) observe(v string) error {
+	if !i.notInt {
+		if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return nil
+		}
+		i.notInt = true
+	}
+	if _, err := strconv.ParseFloat(v, 64); err != nil {
+		return errors.Newf("%q is not numeric", v)
+	}
+	return nil
+}
+
+func (i *inference) dataType() telem.DataType {
+	if i.notInt {
+		return telem.Float64T
+	}
+	return telem.Int64T
+}
+
+// table reads the rows of a delimited file with a header.
+type table struct {
+	r      *csv.Reader
+	header []string
+}
+
+func openTable(r io.Reader, delimiter rune) (*table, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = delimiter
+	cr.ReuseRecord = true
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &table{r: cr, header: make([]string, len(header))}
+	for i, h := range header {
+		t.header[i] = strings.TrimSpace(h)
+	}
+	return t, nil
+}
+
+// next returns the next row in the table along with its line number in the file. The
+// returned row is only valid until the next call to next. Returns io.EOF when there
+// are no more rows.
+func (t *table) next() ([]string, int, error) {
+	row, err := t.r.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+	line, _ := t.r.FieldPos(0)
+	return row, line, nil
+}
+
+func (t *table) columnIndex(name string) int {
+	for i, h := range t.header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderEvent is the header containing the type of the delivered event.
+	HeaderEvent = "X-Synnax-Event"
+	// HeaderDelivery is the header containing the key of the delivered event. The key
+	// is the same across retries of the same delivery.
+	HeaderDelivery = "X-Synnax-Delivery"
+	// HeaderTimestamp is the header containing the time at which the delivery was
+	// attempted, in seconds since the Unix epoch.
+	HeaderTimestamp = "X-Synnax-Timestamp"
+	// HeaderSignature is the header containing the signature of the delivery. Only
+	// set if the subscription has a secret.
+	HeaderSignature = "X-Synnax-Signature"
+)
+
+// Sign computes the signature of a webhook delivery. The signature is the hex-encoded
+// HMAC-SHA256 of the timestamp header, a period, and the request body, keyed with the
+// subscription's secret and prefixed with "sha256=". Receivers should compute the same
+// signature and compare it with the value of HeaderSignature in constant time, and
+// reject deliveries with stale timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// statusError is returned when a webhook endpoint responds with a non-2xx status.
+type statusError struct{ code int }
+
+func (e statusError) Error() string {
+	return "webhook endpoint responded with status " + strconv.Itoa(e.code) + " " + http.StatusText(e.code)
+}
+
+// retryable returns true if a delivery that failed with the given error should be
+// retried. Transport errors, server errors, and rate limits are retried, while other
+// client errors are not, as retrying them would produce the same result.
+func retryable(err error) bool {
+	var sErr statusError
+	if errors.As(err, &sErr) {
+		return sErr.code >= http.StatusInternalServerError || sErr.code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func encode(f Format, e Event) ([]byte, error) {
+	if f == FormatSlack {
+		return json.Marshal(struct {
+			Text string `json:"text"`
+		}{Text: e.Message})
+	}
+	return json.Marshal(e)
+}
+
+// deliver delivers the event to the subscription, retrying with exponential backoff
+// until the delivery succeeds, fails with a non-retryable error, or exhausts its
+// attempts. Deliveries that don't succeed are recorded as dead letters.
+func (d *dispatcher) deliver(ctx context.Context, sub Subscription, e Event) {
+	body, err := encode(sub.Format, e)
+	if err != nil {
+		d.deadLetter(ctx, sub, e, 0, err)
+		return
+	}
+	backoff := d.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		if err = d.post(ctx, sub, e, body); err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if attempt >= d.MaxAttempts || !retryable(err) {
+			d.deadLetter(ctx, sub, e, attempt, err)
+			return
+		}
+		d.L.Debug(
+			"webhook delivery failed, retrying",
+			zap.Stringer("subscription", sub.Key),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Duration()):
+		}
+		backoff = min(backoff*2, d.MaxBackoff)
+	}
+}
+
+func (d *dispatcher) post(ctx context.Context, sub Subscription, e Event, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(e.Type))
+	req.Header.Set(HeaderDelivery, e.Key.String())
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if sub.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+	}
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	// Drain the body so that the underlying connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return statusError{code: res.StatusCode}
+	}
+	return nil
+}
+
+func (d *dispatcher) deadLetter(ctx context.Context, sub Subscription, e Event, attempts int, err error) {
+	d.L.Warn(
+		"webhook delivery failed",
+		zap.Stringer("subscription", sub.Key),
+		zap.String("event", string(e.Type)),
+		zap.Int("attempts", attempts),
+		zap.Error(err),
+	)
+	letter := DeadLetter{
+		Key:          uuid.New(),
+		Subscription: sub.Key,
+		Event:        e,
+		Attempts:     attempts,
+		Error:        err.Error(),
+		Time:         telem.Now(),
+	}
+	if err := gorp.NewCreate[uuid.UUID, DeadLetter]().Entry(&letter).Exec(ctx, d.DB); err != nil {
+		d.L.Error("failed to record dead letter", zap.Error(err))
+	}
+}
@@ -0,0 +1,420 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/signals"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/synnax/pkg/service/hardware/task"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/x/binary"
+	changex "github.com/synnaxlabs/x/change"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/observe"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"go.uber.org/zap"
+)
+
+// delivery is a unit of work for the dispatcher's workers. A delivery without a
+// subscription is an event that has not yet been matched against subscriptions.
+type delivery struct {
+	event Event
+	sub   *Subscription
+}
+
+// dispatcher turns changes to ranges, tasks, and alarms into events, and delivers them
+// to matching subscriptions. Each event is dispatched by exactly one node: range events
+// by the bootstrapper, and task and alarm events by the node that owns the task or
+// alarm.
+type dispatcher struct {
+	*Service
+	shutdown context.CancelFunc
+	wg       signal.WaitGroup
+	// queue holds deliveries that have not yet been picked up by a worker. Observers
+	// are called synchronously within the committing transaction, so they must never
+	// block on delivery.
+	queue struct {
+		sync.Mutex
+		items []delivery
+	}
+	ready chan struct{}
+	// mu guards the caches used to tell which changes are events.
+	mu struct {
+		sync.Mutex
+		// ranges holds the keys of all known ranges, so that creations can be told
+		// apart from updates.
+		ranges map[uuid.UUID]struct{}
+		// tasks holds the names of local tasks, and their last known status as
+		// reported on the sy_task_state channel.
+		tasks map[task.Key]taskInfo
+		// alarms holds the names and last known states of local alarms.
+		alarms map[alarm.Key]alarmInfo
+	}
+	disconnects []observe.Disconnect
+}
+
+type taskInfo struct {
+	name    string
+	variant task.Status
+}
+
+type alarmInfo struct {
+	name  string
+	state alarm.State
+}
+
+func openDispatcher(ctx context.Context, s *Service) (*dispatcher, error) {
+	d := &dispatcher{Service: s, ready: make(chan struct{}, 1)}
+	d.mu.ranges = make(map[uuid.UUID]struct{})
+	d.mu.tasks = make(map[task.Key]taskInfo)
+	d.mu.alarms = make(map[alarm.Key]alarmInfo)
+	if err := d.load(ctx); err != nil {
+		return nil, err
+	}
+	host := s.HostProvider.HostKey()
+	if host == core.Bootstrapper {
+		d.disconnects = append(
+			d.disconnects,
+			gorp.Observe[uuid.UUID, ranger.Range](s.DB).OnChange(d.handleRanges),
+		)
+	}
+	d.disconnects = append(
+		d.disconnects,
+		gorp.Observe[task.Key, task.Task](s.DB).OnChange(d.handleTasks),
+		gorp.Observe[alarm.Key, alarm.Alarm](s.DB).OnChange(d.handleAlarms),
+		gorp.Observe[alarm.Key, alarm.State](s.DB).OnChange(d.handleAlarmStates),
+	)
+	sCtx, cancel := signal.Isolated(signal.WithInstrumentation(s.Instrumentation))
+	d.shutdown, d.wg = cancel, sCtx
+	if s.Signals != nil {
+		obs, err := s.Signals.Subscribe(sCtx, signals.ObservableSubscriberConfig{
+			SetChannelName: "sy_task_state",
+		})
+		if err != nil {
+			return nil, errors.CombineErrors(err, d.Close())
+		}
+		d.disconnects = append(d.disconnects, obs.OnChange(d.handleTaskStateSignals))
+	}
+	for i := 0; i < s.Workers; i++ {
+		sCtx.Go(d.work, signal.WithKeyf("worker-%d", i), signal.RecoverWithErrOnPanic())
+	}
+	return d, nil
+}
+
+// load populates the dispatcher's caches with the ranges, tasks, and alarms that
+// exist when the service is opened.
+func (d *dispatcher) load(ctx context.Context) error {
+	host := d.HostProvider.HostKey()
+	if host == core.Bootstrapper {
+		var ranges []ranger.Range
+		if err := gorp.NewRetrieve[uuid.UUID, ranger.Range]().
+			Entries(&ranges).
+			Exec(ctx, d.DB); err != nil {
+			return err
+		}
+		for _, r := range ranges {
+			d.mu.ranges[r.Key] = struct{}{}
+		}
+	}
+	var tasks []task.Task
+	if err := gorp.NewRetrieve[task.Key, task.Task]().
+		Where(func(t *task.Task) bool { return t.Key.Rack().Node() == host }).
+		Entries(&tasks).
+		Exec(ctx, d.DB); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		d.mu.tasks[t.Key] = taskInfo{name: t.Name}
+	}
+	var alarms []alarm.Alarm
+	if err := gorp.NewRetrieve[alarm.Key, alarm.Alarm]().
+		Where(func(a *alarm.Alarm) bool { return a.Key.Node() == host }).
+		Entries(&alarms).
+		Exec(ctx, d.DB); err != nil {
+		return err
+	}
+	var states []alarm.State
+	if err := gorp.NewRetrieve[alarm.Key, alarm.State]().
+		Where(func(s *alarm.State) bool { return s.Key.Node() == host }).
+		Entries(&states).
+		Exec(ctx, d.DB); err != nil {
+		return err
+	}
+	for _, a := range alarms {
+		d.mu.alarms[a.Key] = alarmInfo{name: a.Name}
+	}
+	for _, s := range states {
+		info := d.mu.alarms[s.Key]
+		info.state = s
+		d.mu.alarms[s.Key] = info
+	}
+	return nil
+}
+
+func (d *dispatcher) handleRanges(ctx context.Context, r gorp.TxReader[uuid.UUID, ranger.Range]) {
+	var events []Event
+	d.mu.Lock()
+	for c, ok := r.Next(ctx); ok; c, ok = r.Next(ctx) {
+		if c.Variant == changex.Delete {
+			delete(d.mu.ranges, c.Key)
+			continue
+		}
+		if _, exists := d.mu.ranges[c.Key]; exists {
+			continue
+		}
+		d.mu.ranges[c.Key] = struct{}{}
+		events = append(events, Event{
+			Type:     EventRangeCreate,
+			Resource: ranger.OntologyID(c.Key),
+			Name:     c.Value.Name,
+			Message:  fmt.Sprintf("Range %s was created", c.Value.Name),
+		})
+	}
+	d.mu.Unlock()
+	d.dispatch(events...)
+}
+
+// handleTasks keeps the names of local tasks up to date.
+func (d *dispatcher) handleTasks(ctx context.Context, r gorp.TxReader[task.Key, task.Task]) {
+	host := d.HostProvider.HostKey()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for c, ok := r.Next(ctx); ok; c, ok = r.Next(ctx) {
+		if c.Key.Rack().Node() != host {
+			continue
+		}
+		if c.Variant == changex.Delete {
+			delete(d.mu.tasks, c.Key)
+			continue
+		}
+		info := d.mu.tasks[c.Key]
+		info.name = c.Value.Name
+		d.mu.tasks[c.Key] = info
+	}
+}
+
+// handleTaskStateSignals handles task states written to the sy_task_state channel.
+// Task states are not persisted, so this is the only source of task errors.
+func (d *dispatcher) handleTaskStateSignals(ctx context.Context, changes []changex.Change[[]byte, struct{}]) {
+	host := d.HostProvider.HostKey()
+	decoder := &binary.JSONCodec{}
+	var events []Event
+	d.mu.Lock()
+	for _, ch := range changes {
+		var state task.State
+		if err := decoder.Decode(ctx, ch.Key, &state); err != nil {
+			d.L.Warn("failed to decode task state", zap.Error(err))
+			continue
+		}
+		if state.Task.Rack().Node() == host {
+			events = d.updateTaskState(events, state)
+		}
+	}
+	d.mu.Unlock()
+	d.dispatch(events...)
+}
+
+// updateTaskState records the given state of a task, appending an event to events if
+// the task has transitioned into an error state. d.mu must be held.
+func (d *dispatcher) updateTaskState(events []Event, state task.State) []Event {
+	info := d.mu.tasks[state.Task]
+	prev := info.variant
+	info.variant = state.Variant
+	d.mu.tasks[state.Task] = info
+	if state.Variant != task.StatusError || prev == task.StatusError {
+		return events
+	}
+	name := info.name
+	if name == "" {
+		name = state.Task.String()
+	}
+	msg := fmt.Sprintf("Task %s encountered an error", name)
+	if state.Details != "" {
+		msg += ": " + string(state.Details)
+	}
+	return append(events, Event{
+		Type:     EventTaskError,
+		Resource: task.OntologyID(state.Task),
+		Name:     name,
+		Message:  msg,
+	})
+}
+
+func (d *dispatcher) handleAlarms(ctx context.Context, r gorp.TxReader[alarm.Key, alarm.Alarm]) {
+	host := d.HostProvider.HostKey()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for c, ok := r.Next(ctx); ok; c, ok = r.Next(ctx) {
+		if c.Key.Node() != host {
+			continue
+		}
+		if c.Variant == changex.Delete {
+			delete(d.mu.alarms, c.Key)
+			continue
+		}
+		info := d.mu.alarms[c.Key]
+		info.name = c.Value.Name
+		d.mu.alarms[c.Key] = info
+	}
+}
+
+func (d *dispatcher) handleAlarmStates(ctx context.Context, r gorp.TxReader[alarm.Key, alarm.State]) {
+	host := d.HostProvider.HostKey()
+	var events []Event
+	d.mu.Lock()
+	for c, ok := r.Next(ctx); ok; c, ok = r.Next(ctx) {
+		if c.Key.Node() != host || c.Variant == changex.Delete {
+			continue
+		}
+		info := d.mu.alarms[c.Key]
+		prev, next := info.state, c.Value
+		info.state = next
+		d.mu.alarms[c.Key] = info
+		name := info.name
+		if name == "" {
+			name = c.Key.String()
+		}
+		e := Event{Time: next.Time, Resource: alarm.OntologyID(c.Key), Name: name}
+		switch {
+		case next.Status == alarm.StatusActive && (prev.Status != alarm.StatusActive ||
+			prev.Severity != next.Severity || prev.Condition != next.Condition):
+			e.Type = EventAlarmActive
+			e.Message = fmt.Sprintf(
+				"Alarm %s is active: %s %s condition at value %v",
+				name,
+				severityNames[next.Severity],
+				next.Condition,
+				next.Value,
+			)
+		case next.Status == alarm.StatusCleared && (prev.Status == alarm.StatusActive ||
+			prev.Status == alarm.StatusAcknowledged):
+			e.Type = EventAlarmCleared
+			e.Message = fmt.Sprintf("Alarm %s has cleared", name)
+		default:
+			continue
+		}
+		events = append(events, e)
+	}
+	d.mu.Unlock()
+	d.dispatch(events...)
+}
+
+var severityNames = map[alarm.Severity]string{
+	alarm.SeverityNone:     "no",
+	alarm.SeverityWarning:  "warning",
+	alarm.SeverityCritical: "critical",
+}
+
+// dispatch queues the given events for matching and delivery.
+func (d *dispatcher) dispatch(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+	deliveries := make([]delivery, len(events))
+	for i, e := range events {
+		e.Key = uuid.New()
+		if e.Time == 0 {
+			e.Time = telem.Now()
+		}
+		deliveries[i] = delivery{event: e}
+	}
+	d.push(deliveries...)
+}
+
+func (d *dispatcher) push(deliveries ...delivery) {
+	d.queue.Lock()
+	d.queue.items = append(d.queue.items, deliveries...)
+	d.queue.Unlock()
+	d.signal()
+}
+
+func (d *dispatcher) pop() (delivery, bool) {
+	d.queue.Lock()
+	defer d.queue.Unlock()
+	if len(d.queue.items) == 0 {
+		return delivery{}, false
+	}
+	next := d.queue.items[0]
+	d.queue.items = d.queue.items[1:]
+	if len(d.queue.items) > 0 {
+		// Wake another worker to pick up the remaining deliveries.
+		d.signal()
+	}
+	return next, true
+}
+
+func (d *dispatcher) signal() {
+	select {
+	case d.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dispatcher) work(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.ready:
+		}
+		for next, ok := d.pop(); ok; next, ok = d.pop() {
+			if next.sub == nil {
+				d.match(ctx, next.event)
+			} else {
+				d.deliver(ctx, *next.sub, next.event)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// match queues a delivery of the given event to every subscription that matches it.
+func (d *dispatcher) match(ctx context.Context, e Event) {
+	var subs []Subscription
+	if err := d.NewRetrieve().
+		WhereEvents(e.Type).
+		Entries(&subs).
+		Exec(ctx, nil); err != nil {
+		d.L.Error("failed to retrieve subscriptions", zap.Error(err))
+		return
+	}
+	var deliveries []delivery
+	for _, s := range subs {
+		if s.matches(e) {
+			deliveries = append(deliveries, delivery{event: e, sub: &s})
+		}
+	}
+	if len(deliveries) > 0 {
+		d.push(deliveries...)
+	}
+}
+
+// Close stops dispatching events and waits for all workers to exit.
+func (d *dispatcher) Close() error {
+	for _, dc := range d.disconnects {
+		dc()
+	}
+	d.shutdown()
+	err := d.wg.Wait()
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	return err
+}
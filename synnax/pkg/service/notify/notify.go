@@ -0,0 +1,154 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package notify dispatches webhook notifications when events of interest occur in the
+// cluster, such as the creation of a range, a task entering an error state, or an
+// alarm becoming active. Users define subscriptions that select the events they're
+// interested in, and the service delivers signed HTTP callbacks to the subscription's
+// URL, retrying failed deliveries with exponential backoff and recording deliveries
+// that ultimately fail as dead letters.
+package notify
+
+import (
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// EventType is the type of event that a subscription can be notified of.
+type EventType string
+
+const (
+	// EventRangeCreate is dispatched when a range is created.
+	EventRangeCreate EventType = "range.create"
+	// EventTaskError is dispatched when a task transitions into an error state.
+	EventTaskError EventType = "task.error"
+	// EventAlarmActive is dispatched when an alarm is raised or escalated.
+	EventAlarmActive EventType = "alarm.active"
+	// EventAlarmCleared is dispatched when an active or acknowledged alarm clears.
+	EventAlarmCleared EventType = "alarm.cleared"
+)
+
+var eventTypes = []EventType{EventRangeCreate, EventTaskError, EventAlarmActive, EventAlarmCleared}
+
+// Event is an occurrence in the cluster that subscriptions can be notified of. Events
+// are delivered as the JSON body of a webhook request.
+type Event struct {
+	// Key uniquely identifies the event. Receivers can use it to de-duplicate
+	// deliveries.
+	Key uuid.UUID `json:"key" msgpack:"key"`
+	// Type is the type of the event.
+	Type EventType `json:"type" msgpack:"type"`
+	// Time is the time at which the event occurred.
+	Time telem.TimeStamp `json:"time" msgpack:"time"`
+	// Resource is the ontology ID of the range, task, or alarm that the event is about.
+	Resource ontology.ID `json:"resource" msgpack:"resource"`
+	// Name is the name of the resource.
+	Name string `json:"name" msgpack:"name"`
+	// Message is a human-readable description of the event.
+	Message string `json:"message" msgpack:"message"`
+}
+
+// Format is the format of the body of a webhook request.
+type Format string
+
+const (
+	// FormatJSON delivers the Event as a JSON object.
+	FormatJSON Format = "json"
+	// FormatSlack delivers the event message as a JSON object with a single text
+	// field, which is the format expected by Slack and compatible chat webhooks.
+	FormatSlack Format = "slack"
+)
+
+// Subscription selects a set of events to deliver to a webhook URL.
+type Subscription struct {
+	// Key is a unique identifier for the subscription. Assigned on creation if not
+	// provided.
+	Key uuid.UUID `json:"key" msgpack:"key"`
+	// Name is a human-readable name for the subscription.
+	Name string `json:"name" msgpack:"name"`
+	// URL is the HTTP or HTTPS URL that events are delivered to.
+	URL string `json:"url" msgpack:"url"`
+	// Secret is used to sign deliveries. If empty, deliveries are not signed. See Sign
+	// for details on how signatures are computed.
+	Secret string `json:"secret" msgpack:"secret"`
+	// Events are the types of events the subscription is notified of.
+	Events []EventType `json:"events" msgpack:"events"`
+	// Resources optionally restricts the subscription to events about the resources
+	// with the given ontology IDs.
+	Resources []ontology.ID `json:"resources" msgpack:"resources"`
+	// Format is the format of the body of webhook requests. Defaults to FormatJSON.
+	Format Format `json:"format" msgpack:"format"`
+}
+
+var _ gorp.Entry[uuid.UUID] = Subscription{}
+
+// GorpKey implements gorp.Entry.
+func (s Subscription) GorpKey() uuid.UUID { return s.Key }
+
+// SetOptions implements gorp.Entry.
+func (s Subscription) SetOptions() []interface{} { return nil }
+
+// Validate checks that the subscription is well-formed.
+func (s Subscription) Validate() error {
+	v := validate.New("notify.subscription")
+	validate.NotEmptyString(v, "name", s.Name)
+	validate.NotEmptySlice(v, "events", s.Events)
+	for _, e := range s.Events {
+		if v.Ternaryf("events", !lo.Contains(eventTypes, e), "unknown event type %s", e) {
+			break
+		}
+	}
+	v.Ternaryf(
+		"format",
+		s.Format != FormatJSON && s.Format != FormatSlack,
+		"unknown format %s",
+		s.Format,
+	)
+	v.Func(func() bool {
+		u, err := url.Parse(s.URL)
+		return err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == ""
+	}, "url must be an absolute http or https url")
+	return v.Error()
+}
+
+// matches returns true if the subscription should be notified of the given event.
+func (s Subscription) matches(e Event) bool {
+	return lo.Contains(s.Events, e.Type) &&
+		(len(s.Resources) == 0 || lo.Contains(s.Resources, e.Resource))
+}
+
+// DeadLetter is a record of an event that could not be delivered to a subscription.
+type DeadLetter struct {
+	// Key is a unique identifier for the dead letter.
+	Key uuid.UUID `json:"key" msgpack:"key"`
+	// Subscription is the key of the subscription the event was delivered to.
+	Subscription uuid.UUID `json:"subscription" msgpack:"subscription"`
+	// Event is the event that could not be delivered.
+	Event Event `json:"event" msgpack:"event"`
+	// Attempts is the number of delivery attempts made.
+	Attempts int `json:"attempts" msgpack:"attempts"`
+	// Error is the error returned by the last delivery attempt.
+	Error string `json:"error" msgpack:"error"`
+	// Time is the time of the last delivery attempt.
+	Time telem.TimeStamp `json:"time" msgpack:"time"`
+}
+
+var _ gorp.Entry[uuid.UUID] = DeadLetter{}
+
+// GorpKey implements gorp.Entry.
+func (d DeadLetter) GorpKey() uuid.UUID { return d.Key }
+
+// SetOptions implements gorp.Entry.
+func (d DeadLetter) SetOptions() []interface{} { return nil }
@@ -0,0 +1,89 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution"
+	"github.com/synnaxlabs/synnax/pkg/distribution/mock"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/synnax/pkg/service/hardware"
+	"github.com/synnaxlabs/synnax/pkg/service/notify"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var (
+	ctx         = context.Background()
+	_b          *mock.Builder
+	dist        distribution.Distribution
+	db          *gorp.DB
+	hardwareSvc *hardware.Service
+	rangeSvc    *ranger.Service
+	alarmSvc    *alarm.Service
+	svc         *notify.Service
+)
+
+var _ = BeforeSuite(func() {
+	_b = mock.NewBuilder()
+	dist = _b.New(ctx)
+	db = dist.Storage.Gorpify()
+	hardwareSvc = MustSucceed(hardware.OpenService(ctx, hardware.Config{
+		DB:           db,
+		Ontology:     dist.Ontology,
+		Group:        dist.Group,
+		HostProvider: dist.Cluster,
+		Signals:      dist.Signals,
+		Channel:      dist.Channel,
+	}))
+	rangeSvc = MustSucceed(ranger.OpenService(ctx, ranger.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	alarmSvc = MustSucceed(alarm.OpenService(ctx, alarm.Config{
+		DB:           db,
+		Ontology:     dist.Ontology,
+		Group:        dist.Group,
+		HostProvider: dist.Cluster,
+		Channel:      dist.Channel,
+		Framer:       dist.Framer,
+	}))
+	svc = MustSucceed(notify.OpenService(ctx, notify.Config{
+		DB:             db,
+		Ontology:       dist.Ontology,
+		Group:          dist.Group,
+		HostProvider:   dist.Cluster,
+		Signals:        dist.Signals,
+		MaxAttempts:    3,
+		InitialBackoff: telem.Millisecond,
+		MaxBackoff:     5 * telem.Millisecond,
+	}))
+})
+
+var _ = AfterSuite(func() {
+	Expect(svc.Close()).To(Succeed())
+	Expect(alarmSvc.Close()).To(Succeed())
+	Expect(rangeSvc.Close()).To(Succeed())
+	Expect(hardwareSvc.Close()).To(Succeed())
+	Expect(_b.Close()).To(Succeed())
+	Expect(_b.Cleanup()).To(Succeed())
+})
+
+func TestNotify(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Notify Suite")
+}
@@ -0,0 +1,352 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/service/alarm"
+	"github.com/synnaxlabs/synnax/pkg/service/hardware/rack"
+	"github.com/synnaxlabs/synnax/pkg/service/hardware/task"
+	"github.com/synnaxlabs/synnax/pkg/service/notify"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/x/binary"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a local webhook endpoint that records the requests it receives. It
+// responds to requests with the given statuses in order, and with 200 once they are
+// exhausted.
+type receiver struct {
+	*httptest.Server
+	requests chan request
+	mu       sync.Mutex
+	statuses []int
+}
+
+func newReceiver(statuses ...int) *receiver {
+	r := &receiver{requests: make(chan request, 100), statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer GinkgoRecover()
+		body := MustSucceed(io.ReadAll(req.Body))
+		r.requests <- request{header: req.Header.Clone(), body: body}
+		r.mu.Lock()
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	DeferCleanup(r.Close)
+	return r
+}
+
+func (r *receiver) next() request {
+	var req request
+	Eventually(r.requests).Should(Receive(&req))
+	return req
+}
+
+func (r *receiver) nextEvent() notify.Event {
+	var e notify.Event
+	Expect(json.Unmarshal(r.next().body, &e)).To(Succeed())
+	return e
+}
+
+func subscribe(sub notify.Subscription) notify.Subscription {
+	Expect(svc.NewWriter(nil).Create(ctx, &sub)).To(Succeed())
+	DeferCleanup(func() {
+		Expect(svc.NewWriter(nil).Delete(ctx, sub.Key)).To(Succeed())
+	})
+	return sub
+}
+
+func createRange(name string) ranger.Range {
+	r := ranger.Range{
+		Name:      name,
+		TimeRange: telem.TimeRange{Start: telem.SecondTS, End: telem.SecondTS * 2},
+	}
+	Expect(rangeSvc.NewWriter(db).Create(ctx, &r)).To(Succeed())
+	return r
+}
+
+var _ = Describe("Notify", func() {
+	Describe("Subscriptions", func() {
+		It("Should assign a key and default format to a subscription", func() {
+			s := subscribe(notify.Subscription{
+				Name:   "ranges",
+				URL:    "https://example.com/hook",
+				Events: []notify.EventType{notify.EventRangeCreate},
+			})
+			Expect(s.Key).ToNot(Equal(uuid.Nil))
+			Expect(s.Format).To(Equal(notify.FormatJSON))
+			var res notify.Subscription
+			Expect(svc.NewRetrieve().WhereKeys(s.Key).Entry(&res).Exec(ctx, nil)).To(Succeed())
+			Expect(res.URL).To(Equal(s.URL))
+		})
+		DescribeTable("Should validate subscriptions", func(s notify.Subscription, msg string) {
+			Expect(svc.NewWriter(nil).Create(ctx, &s)).To(MatchError(ContainSubstring(msg)))
+		},
+			Entry("no events", notify.Subscription{Name: "a", URL: "http://a.com"}, "events"),
+			Entry("unknown event", notify.Subscription{
+				Name:   "a",
+				URL:    "http://a.com",
+				Events: []notify.EventType{"cat.create"},
+			}, "unknown event type cat.create"),
+			Entry("relative url", notify.Subscription{
+				Name:   "a",
+				URL:    "/hook",
+				Events: []notify.EventType{notify.EventRangeCreate},
+			}, "url must be an absolute http or https url"),
+		)
+		It("Should retrieve subscriptions by the events they are notified of", func() {
+			s := subscribe(notify.Subscription{
+				Name:   "tasks",
+				URL:    "https://example.com/hook",
+				Events: []notify.EventType{notify.EventTaskError},
+			})
+			var res []notify.Subscription
+			Expect(svc.NewRetrieve().
+				WhereEvents(notify.EventTaskError).
+				Entries(&res).
+				Exec(ctx, nil)).To(Succeed())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].Key).To(Equal(s.Key))
+		})
+	})
+
+	Describe("Ranges", func() {
+		It("Should deliver a signed event when a range is created", func() {
+			rcv := newReceiver()
+			subscribe(notify.Subscription{
+				Name:   "ranges",
+				URL:    rcv.URL,
+				Secret: "shh",
+				Events: []notify.EventType{notify.EventRangeCreate},
+			})
+			r := createRange("hotfire")
+			req := rcv.next()
+			Expect(req.header.Get(notify.HeaderEvent)).To(Equal(string(notify.EventRangeCreate)))
+			ts := req.header.Get(notify.HeaderTimestamp)
+			Expect(req.header.Get(notify.HeaderSignature)).To(Equal(notify.Sign("shh", ts, req.body)))
+			var e notify.Event
+			Expect(json.Unmarshal(req.body, &e)).To(Succeed())
+			Expect(e.Type).To(Equal(notify.EventRangeCreate))
+			Expect(e.Resource).To(Equal(ranger.OntologyID(r.Key)))
+			Expect(e.Name).To(Equal("hotfire"))
+			Expect(req.header.Get(notify.HeaderDelivery)).To(Equal(e.Key.String()))
+
+			By("Not delivering an event when the range is updated")
+			r.Name = "hotfire 2"
+			Expect(rangeSvc.NewWriter(db).Create(ctx, &r)).To(Succeed())
+			Consistently(rcv.requests, 50*time.Millisecond).ShouldNot(Receive())
+		})
+		It("Should only deliver events for the resources a subscription selects", func() {
+			rcv := newReceiver()
+			key := uuid.New()
+			subscribe(notify.Subscription{
+				Name:      "one range",
+				URL:       rcv.URL,
+				Events:    []notify.EventType{notify.EventRangeCreate},
+				Resources: []ontology.ID{ranger.OntologyID(key)},
+			})
+			createRange("other")
+			Consistently(rcv.requests, 50*time.Millisecond).ShouldNot(Receive())
+			r := ranger.Range{Key: key, Name: "selected", TimeRange: telem.TimeRange{Start: telem.SecondTS, End: telem.SecondTS * 2}}
+			Expect(rangeSvc.NewWriter(db).Create(ctx, &r)).To(Succeed())
+			Expect(rcv.nextEvent().Name).To(Equal("selected"))
+		})
+		It("Should deliver the event message in the slack format", func() {
+			rcv := newReceiver()
+			subscribe(notify.Subscription{
+				Name:   "slack",
+				URL:    rcv.URL,
+				Events: []notify.EventType{notify.EventRangeCreate},
+				Format: notify.FormatSlack,
+			})
+			createRange("slacker")
+			var body map[string]string
+			Expect(json.Unmarshal(rcv.next().body, &body)).To(Succeed())
+			Expect(body).To(Equal(map[string]string{"text": "Range slacker was created"}))
+		})
+	})
+
+	Describe("Tasks", func() {
+		var tsk task.Task
+		BeforeEach(func() {
+			r := rack.Rack{Name: "rack"}
+			Expect(hardwareSvc.Rack.NewWriter(nil).Create(ctx, &r)).To(Succeed())
+			tsk = task.Task{Key: task.NewKey(r.Key, 0), Name: "daq"}
+			Expect(hardwareSvc.Task.NewWriter(nil).Create(ctx, &tsk)).To(Succeed())
+		})
+		It("Should deliver an event when a task state on sy_task_state is an error", func() {
+			rcv := newReceiver()
+			subscribe(notify.Subscription{
+				Name:   "tasks",
+				URL:    rcv.URL,
+				Events: []notify.EventType{notify.EventTaskError},
+			})
+			var stateCh channel.Channel
+			Expect(dist.Channel.NewRetrieve().
+				WhereNames("sy_task_state").
+				Entry(&stateCh).
+				Exec(ctx, nil)).To(Succeed())
+			w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Start: telem.Now(),
+				Keys:  []channel.Key{stateCh.Key()},
+			}))
+			writeState := func(variant task.Status) {
+				b := MustSucceed((&binary.JSONCodec{}).Encode(ctx, task.State{
+					Task:    tsk.Key,
+					Variant: variant,
+					Details: "device disconnected",
+				}))
+				Expect(w.Write(framer.Frame{
+					Keys:   []channel.Key{stateCh.Key()},
+					Series: []telem.Series{{DataType: telem.JSONT, Data: append(b, '\n')}},
+				})).To(BeTrue())
+			}
+			writeState(task.StatusError)
+			e := rcv.nextEvent()
+			Expect(e.Type).To(Equal(notify.EventTaskError))
+			Expect(e.Resource).To(Equal(task.OntologyID(tsk.Key)))
+			Expect(e.Message).To(Equal("Task daq encountered an error: device disconnected"))
+
+			By("Only delivering an event when the task transitions into an error")
+			writeState(task.StatusError)
+			Consistently(rcv.requests, 50*time.Millisecond).ShouldNot(Receive())
+			writeState(task.StatusSuccess)
+			writeState(task.StatusError)
+			Expect(rcv.nextEvent().Type).To(Equal(notify.EventTaskError))
+			Expect(w.Close()).To(Succeed())
+		})
+	})
+
+	Describe("Alarms", func() {
+		It("Should deliver events when an alarm becomes active and clears", func() {
+			rcv := newReceiver()
+			subscribe(notify.Subscription{
+				Name:   "alarms",
+				URL:    rcv.URL,
+				Events: []notify.EventType{notify.EventAlarmActive, notify.EventAlarmCleared},
+			})
+			prefix := uuid.NewString()[:8]
+			idx := channel.Channel{Name: prefix + "_time", DataType: telem.TimeStampT, IsIndex: true}
+			Expect(dist.Channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+			data := channel.Channel{Name: prefix + "_data", DataType: telem.Int64T, LocalIndex: idx.LocalKey}
+			Expect(dist.Channel.NewWriter(nil).Create(ctx, &data)).To(Succeed())
+			a := alarm.Alarm{Name: "pressure", Channel: data.Key(), Critical: &alarm.Bounds{High: 10}}
+			Expect(alarmSvc.NewWriter(nil).Create(ctx, &a)).To(Succeed())
+			// Give the evaluator a moment to subscribe to the alarm's channel.
+			time.Sleep(50 * time.Millisecond)
+			start := telem.Now()
+			w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+				Keys:  channel.Keys{idx.Key(), data.Key()},
+				Start: start,
+			}))
+			write := func(v int64) {
+				Expect(w.Write(framer.Frame{
+					Keys:   channel.Keys{idx.Key(), data.Key()},
+					Series: []telem.Series{telem.NewSeries([]telem.TimeStamp{start}), telem.NewSeries([]int64{v})},
+				})).To(BeTrue())
+				start = start.Add(telem.Second)
+			}
+			write(20)
+			e := rcv.nextEvent()
+			Expect(e.Type).To(Equal(notify.EventAlarmActive))
+			Expect(e.Resource).To(Equal(alarm.OntologyID(a.Key)))
+			Expect(e.Message).To(Equal("Alarm pressure is active: critical high condition at value 20"))
+			write(5)
+			Expect(rcv.nextEvent().Type).To(Equal(notify.EventAlarmCleared))
+			Expect(w.Close()).To(Succeed())
+			Expect(alarmSvc.NewWriter(nil).Delete(ctx, a.Key)).To(Succeed())
+		})
+	})
+
+	Describe("Delivery", func() {
+		It("Should retry failed deliveries with the same delivery key", func() {
+			rcv := newReceiver(http.StatusInternalServerError, http.StatusTooManyRequests)
+			s := subscribe(notify.Subscription{
+				Name:   "retry",
+				URL:    rcv.URL,
+				Events: []notify.EventType{notify.EventRangeCreate},
+			})
+			createRange("retried")
+			keys := make([]string, 3)
+			for i := range keys {
+				keys[i] = rcv.next().header.Get(notify.HeaderDelivery)
+			}
+			Expect(keys[1]).To(Equal(keys[0]))
+			Expect(keys[2]).To(Equal(keys[0]))
+			Consistently(func() []notify.DeadLetter {
+				return MustSucceed(svc.RetrieveDeadLetters(ctx, nil, s.Key))
+			}, 50*time.Millisecond).Should(BeEmpty())
+		})
+		It("Should record a dead letter when all attempts fail", func() {
+			rcv := newReceiver(
+				http.StatusBadGateway,
+				http.StatusBadGateway,
+				http.StatusBadGateway,
+			)
+			s := subscribe(notify.Subscription{
+				Name:   "dead",
+				URL:    rcv.URL,
+				Events: []notify.EventType{notify.EventRangeCreate},
+			})
+			r := createRange("undeliverable")
+			var letters []notify.DeadLetter
+			Eventually(func(g Gomega) {
+				letters = MustSucceed(svc.RetrieveDeadLetters(ctx, nil, s.Key))
+				g.Expect(letters).To(HaveLen(1))
+			}).Should(Succeed())
+			Expect(letters[0].Attempts).To(Equal(3))
+			Expect(letters[0].Event.Resource).To(Equal(ranger.OntologyID(r.Key)))
+			Expect(letters[0].Error).To(ContainSubstring("502"))
+			Expect(rcv.requests).To(HaveLen(3))
+
+			By("Deleting dead letters along with the subscription")
+			Expect(svc.NewWriter(nil).Delete(ctx, s.Key)).To(Succeed())
+			Expect(MustSucceed(svc.RetrieveDeadLetters(ctx, nil, s.Key))).To(BeEmpty())
+		})
+		It("Should not retry deliveries rejected by the endpoint", func() {
+			rcv := newReceiver(http.StatusBadRequest)
+			s := subscribe(notify.Subscription{
+				Name:   "rejected",
+				URL:    rcv.URL,
+				Events: []notify.EventType{notify.EventRangeCreate},
+			})
+			createRange("rejected")
+			Eventually(func(g Gomega) {
+				letters := MustSucceed(svc.RetrieveDeadLetters(ctx, nil, s.Key))
+				g.Expect(letters).To(HaveLen(1))
+				g.Expect(letters[0].Attempts).To(Equal(1))
+			}).Should(Succeed())
+			Expect(rcv.requests).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/schema"
+	changex "github.com/synnaxlabs/x/change"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/iter"
+	"github.com/synnaxlabs/x/observe"
+)
+
+const ontologyType ontology.Type = "subscription"
+
+// OntologyID constructs a unique ontology.ID for the subscription with the given key.
+func OntologyID(k uuid.UUID) ontology.ID {
+	return ontology.ID{Type: ontologyType, Key: k.String()}
+}
+
+// OntologyIDs constructs a slice of unique ontology.IDs for the subscriptions with the
+// given keys.
+func OntologyIDs(keys []uuid.UUID) []ontology.ID {
+	return lo.Map(keys, func(k uuid.UUID, _ int) ontology.ID { return OntologyID(k) })
+}
+
+// OntologyIDsFromSubscriptions constructs a slice of unique ontology.IDs for the given
+// subscriptions.
+func OntologyIDsFromSubscriptions(subs []Subscription) []ontology.ID {
+	return lo.Map(subs, func(s Subscription, _ int) ontology.ID { return OntologyID(s.Key) })
+}
+
+// KeysFromOntologyIDs extracts the subscription keys from the given ontology.IDs.
+func KeysFromOntologyIDs(ids []ontology.ID) (keys []uuid.UUID, err error) {
+	keys = make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		keys[i], err = uuid.Parse(id.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+var _schema = &ontology.Schema{
+	Type: ontologyType,
+	Fields: map[string]schema.Field{
+		"key":  {Type: schema.String},
+		"name": {Type: schema.String},
+		"url":  {Type: schema.String},
+	},
+}
+
+func newResource(s Subscription) schema.Resource {
+	e := schema.NewResource(_schema, OntologyID(s.Key), s.Name)
+	schema.Set(e, "key", s.Key.String())
+	schema.Set(e, "name", s.Name)
+	schema.Set(e, "url", s.URL)
+	return e
+}
+
+type change = changex.Change[uuid.UUID, Subscription]
+
+// Schema implements ontology.Service.
+func (s *Service) Schema() *schema.Schema { return _schema }
+
+// RetrieveResource implements ontology.Service.
+func (s *Service) RetrieveResource(ctx context.Context, key string, tx gorp.Tx) (ontology.Resource, error) {
+	k, err := uuid.Parse(key)
+	if err != nil {
+		return ontology.Resource{}, err
+	}
+	var sub Subscription
+	err = s.NewRetrieve().WhereKeys(k).Entry(&sub).Exec(ctx, tx)
+	return newResource(sub), err
+}
+
+func translateChange(c change) schema.Change {
+	return schema.Change{
+		Variant: c.Variant,
+		Key:     OntologyID(c.Key),
+		Value:   newResource(c.Value),
+	}
+}
+
+// OnChange implements ontology.Service.
+func (s *Service) OnChange(f func(ctx context.Context, nexter iter.Nexter[schema.Change])) observe.Disconnect {
+	handleChange := func(ctx context.Context, reader gorp.TxReader[uuid.UUID, Subscription]) {
+		f(ctx, iter.NexterTranslator[change, schema.Change]{Wrap: reader, Translate: translateChange})
+	}
+	return gorp.Observe[uuid.UUID, Subscription](s.DB).OnChange(handleChange)
+}
+
+// OpenNexter implements ontology.Service.
+func (s *Service) OpenNexter() (iter.NexterCloser[schema.Resource], error) {
+	n, err := gorp.WrapReader[uuid.UUID, Subscription](s.DB).OpenNexter()
+	return iter.NexterCloserTranslator[Subscription, schema.Resource]{Wrap: n, Translate: newResource}, err
+}
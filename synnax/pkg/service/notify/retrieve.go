@@ -0,0 +1,82 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/search"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+// Retrieve is a builder for querying subscriptions.
+type Retrieve struct {
+	baseTx     gorp.Tx
+	gorp       gorp.Retrieve[uuid.UUID, Subscription]
+	otg        *ontology.Ontology
+	searchTerm string
+}
+
+// Search executes a fuzzy search for subscriptions whose name matches the given term.
+func (r Retrieve) Search(term string) Retrieve { r.searchTerm = term; return r }
+
+// Limit limits the number of results that Retrieve will return.
+func (r Retrieve) Limit(limit int) Retrieve { r.gorp.Limit(limit); return r }
+
+// Offset marks the starting index of results that Retrieve will return.
+func (r Retrieve) Offset(offset int) Retrieve { r.gorp.Offset(offset); return r }
+
+// Entry binds the Subscription that Retrieve will fill results into. If multiple
+// results match the query, only the first result will be filled into the provided
+// Subscription.
+func (r Retrieve) Entry(sub *Subscription) Retrieve { r.gorp.Entry(sub); return r }
+
+// Entries binds a slice that Retrieve will fill results into.
+func (r Retrieve) Entries(subs *[]Subscription) Retrieve { r.gorp.Entries(subs); return r }
+
+// WhereKeys filters for subscriptions with the given keys.
+func (r Retrieve) WhereKeys(keys ...uuid.UUID) Retrieve { r.gorp.WhereKeys(keys...); return r }
+
+// WhereNames filters for subscriptions with the given names.
+func (r Retrieve) WhereNames(names ...string) Retrieve {
+	r.gorp.Where(func(s *Subscription) bool { return lo.Contains(names, s.Name) })
+	return r
+}
+
+// WhereEvents filters for subscriptions that are notified of any of the given event
+// types.
+func (r Retrieve) WhereEvents(events ...EventType) Retrieve {
+	r.gorp.Where(func(s *Subscription) bool { return lo.Some(s.Events, events) })
+	return r
+}
+
+// Exec executes the query. If a tx is provided, Exec will use it to execute the query.
+// Otherwise, it will execute against the underlying gorp.DB.
+func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
+	tx = gorp.OverrideTx(r.baseTx, tx)
+	if r.searchTerm != "" {
+		ids, err := r.otg.SearchIDs(ctx, search.Request{
+			Type: ontologyType,
+			Term: r.searchTerm,
+		})
+		if err != nil {
+			return err
+		}
+		keys, err := KeysFromOntologyIDs(ids)
+		if err != nil {
+			return err
+		}
+		r.gorp.WhereKeys(keys...)
+	}
+	return r.gorp.Exec(ctx, tx)
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/synnax/pkg/distribution/signals"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Config is the configuration for opening the notification service.
+type Config struct {
+	alamos.Instrumentation
+	// DB is the database used to store subscriptions and dead letters, and whose
+	// changes to ranges, tasks, and alarms are observed for events.
+	// [REQUIRED]
+	DB *gorp.DB
+	// Ontology is used to define subscriptions as resources.
+	// [REQUIRED]
+	Ontology *ontology.Ontology
+	// Group is used to create the group that holds all subscriptions.
+	// [REQUIRED]
+	Group *group.Service
+	// HostProvider is used to determine which events this node is responsible for
+	// dispatching, so that each event is delivered exactly once across the cluster.
+	// [REQUIRED]
+	HostProvider core.HostProvider
+	// Signals is used to listen for task state changes on the sy_task_state channel.
+	// If nil, task errors are not dispatched.
+	// [OPTIONAL]
+	Signals *signals.Provider
+	// Client is the HTTP client used to deliver webhooks.
+	// [OPTIONAL] - Defaults to a client with a 10 second timeout.
+	Client *http.Client
+	// Workers is the number of webhooks that can be delivered concurrently.
+	// [OPTIONAL] - Defaults to 4.
+	Workers int
+	// MaxAttempts is the number of times delivery of an event is attempted before
+	// it's recorded as a dead letter.
+	// [OPTIONAL] - Defaults to 5.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry of a failed delivery. The
+	// delay doubles after each subsequent failure, up to MaxBackoff.
+	// [OPTIONAL] - Defaults to 1 second.
+	InitialBackoff telem.TimeSpan
+	// MaxBackoff is the maximum delay between delivery attempts.
+	// [OPTIONAL] - Defaults to 1 minute.
+	MaxBackoff telem.TimeSpan
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for the notification service. This
+	// configuration is not valid on its own, and must be overridden by the required
+	// fields.
+	DefaultConfig = Config{
+		Client:         &http.Client{Timeout: (10 * telem.Second).Duration()},
+		Workers:        4,
+		MaxAttempts:    5,
+		InitialBackoff: telem.Second,
+		MaxBackoff:     telem.Minute,
+	}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.DB = override.Nil(c.DB, other.DB)
+	c.Ontology = override.Nil(c.Ontology, other.Ontology)
+	c.Group = override.Nil(c.Group, other.Group)
+	c.HostProvider = override.Nil(c.HostProvider, other.HostProvider)
+	c.Signals = override.Nil(c.Signals, other.Signals)
+	c.Client = override.Nil(c.Client, other.Client)
+	c.Workers = override.Numeric(c.Workers, other.Workers)
+	c.MaxAttempts = override.Numeric(c.MaxAttempts, other.MaxAttempts)
+	c.InitialBackoff = override.Numeric(c.InitialBackoff, other.InitialBackoff)
+	c.MaxBackoff = override.Numeric(c.MaxBackoff, other.MaxBackoff)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("notify")
+	validate.NotNil(v, "DB", c.DB)
+	validate.NotNil(v, "Ontology", c.Ontology)
+	validate.NotNil(v, "Group", c.Group)
+	validate.NotNil(v, "HostProvider", c.HostProvider)
+	validate.NotNil(v, "Client", c.Client)
+	validate.Positive(v, "Workers", c.Workers)
+	validate.Positive(v, "MaxAttempts", c.MaxAttempts)
+	validate.Positive(v, "InitialBackoff", c.InitialBackoff)
+	validate.GreaterThanEq(v, "MaxBackoff", c.MaxBackoff, c.InitialBackoff)
+	return v.Error()
+}
+
+// Service manages webhook subscriptions, and delivers the events that this node is
+// responsible for to the subscriptions that match them.
+type Service struct {
+	Config
+	group      group.Group
+	dispatcher *dispatcher
+}
+
+// OpenService opens a new notification service using the given configuration. If error
+// is nil, the service is dispatching events and must be closed by calling Close.
+func OpenService(ctx context.Context, configs ...Config) (*Service, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	g, err := cfg.Group.CreateOrRetrieve(ctx, "Subscriptions", ontology.RootID)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Config: cfg, group: g}
+	cfg.Ontology.RegisterService(s)
+	s.dispatcher, err = openDispatcher(ctx, s)
+	return s, err
+}
+
+// NewWriter opens a new Writer to create and delete subscriptions. If tx is nil, the
+// writer executes directly against the underlying gorp.DB.
+func (s *Service) NewWriter(tx gorp.Tx) Writer {
+	tx = gorp.OverrideTx(s.DB, tx)
+	return Writer{tx: tx, otg: s.Ontology.NewWriter(tx), group: s.group}
+}
+
+// NewRetrieve opens a new query to retrieve subscriptions.
+func (s *Service) NewRetrieve() Retrieve {
+	return Retrieve{
+		baseTx: s.DB,
+		otg:    s.Ontology,
+		gorp:   gorp.NewRetrieve[uuid.UUID, Subscription](),
+	}
+}
+
+// RetrieveDeadLetters retrieves the events that could not be delivered to the
+// subscriptions with the given keys. If no keys are provided, dead letters for all
+// subscriptions are retrieved. If tx is nil, the dead letters are retrieved directly
+// from the underlying gorp.DB.
+func (s *Service) RetrieveDeadLetters(
+	ctx context.Context,
+	tx gorp.Tx,
+	subscriptions ...uuid.UUID,
+) ([]DeadLetter, error) {
+	var letters []DeadLetter
+	q := gorp.NewRetrieve[uuid.UUID, DeadLetter]().Entries(&letters)
+	if len(subscriptions) > 0 {
+		q = q.Where(func(d *DeadLetter) bool { return lo.Contains(subscriptions, d.Subscription) })
+	}
+	return letters, q.Exec(ctx, gorp.OverrideTx(s.DB, tx))
+}
+
+// Close stops dispatching events. Deliveries that are in progress are abandoned.
+func (s *Service) Close() error { return s.dispatcher.Close() }
@@ -0,0 +1,77 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+// Writer wraps a transaction to create and delete subscriptions.
+type Writer struct {
+	tx    gorp.Tx
+	otg   ontology.Writer
+	group group.Group
+}
+
+// Create creates a new subscription, assigning it a unique key if one is not provided.
+// If a subscription with the same key already exists, it will be overwritten.
+func (w Writer) Create(ctx context.Context, s *Subscription) error {
+	if s.Key == uuid.Nil {
+		s.Key = uuid.New()
+	}
+	if s.Format == "" {
+		s.Format = FormatJSON
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := gorp.NewCreate[uuid.UUID, Subscription]().Entry(s).Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	otgID := OntologyID(s.Key)
+	if err := w.otg.DefineResource(ctx, otgID); err != nil {
+		return err
+	}
+	return w.otg.DefineRelationship(ctx, w.group.OntologyID(), ontology.ParentOf, otgID)
+}
+
+// CreateMany creates multiple subscriptions. If any of the subscriptions exist, they
+// will be overwritten.
+func (w Writer) CreateMany(ctx context.Context, subs *[]Subscription) error {
+	for i, s := range *subs {
+		if err := w.Create(ctx, &s); err != nil {
+			return err
+		}
+		(*subs)[i] = s
+	}
+	return nil
+}
+
+// Delete removes the subscriptions with the given keys, along with their dead letters.
+// Delete is idempotent, and will not return an error if a subscription does not exist.
+func (w Writer) Delete(ctx context.Context, keys ...uuid.UUID) error {
+	for _, k := range keys {
+		if err := w.otg.DeleteResource(ctx, OntologyID(k)); err != nil {
+			return err
+		}
+	}
+	if err := gorp.NewDelete[uuid.UUID, DeadLetter]().
+		Where(func(d *DeadLetter) bool { return lo.Contains(keys, d.Subscription) }).
+		Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	return gorp.NewDelete[uuid.UUID, Subscription]().WhereKeys(keys...).Exec(ctx, w.tx)
+}
@@ -39,8 +39,15 @@ type Range struct {
 	TimeRange telem.TimeRange `json:"time_range" msgpack:"time_range"`
 	// Color is the color used to represent the range in the UI.
 	Color string `json:"color" msgpack:"color"`
+	// DeletedAt is the time the range was moved to the trash. A range in the trash is
+	// hidden from retrieval unless explicitly requested, and can be restored until it is
+	// purged. Zero if the range is not in the trash.
+	DeletedAt telem.TimeStamp `json:"deleted_at" msgpack:"deleted_at"`
 }
 
+// Trashed returns true if the range is in the trash.
+func (r Range) Trashed() bool { return r.DeletedAt != 0 }
+
 var _ gorp.Entry[uuid.UUID] = Range{}
 
 // GorpKey implements gorp.Entry.
@@ -284,6 +284,71 @@ var _ = Describe("Ranger", Ordered, func() {
 			var retrieveR ranger.Range
 			Expect(svc.NewRetrieve().WhereKeys(r.Key).Entry(&retrieveR).Exec(ctx, tx)).ToNot(Succeed())
 		})
+		It("Should retrieve a deleted range when deleted ranges are included", func() {
+			r := ranger.Range{
+				Name:      "Range",
+				TimeRange: telem.TimeRange{Start: 5 * telem.SecondTS, End: 10 * telem.SecondTS},
+			}
+			Expect(svc.NewWriter(tx).Create(ctx, &r)).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, r.Key)).To(Succeed())
+			var retrieveR ranger.Range
+			Expect(svc.NewRetrieve().
+				WhereKeys(r.Key).
+				IncludeDeleted(true).
+				Entry(&retrieveR).
+				Exec(ctx, tx)).To(Succeed())
+			Expect(retrieveR.Trashed()).To(BeTrue())
+			var deleted []ranger.Range
+			Expect(svc.NewRetrieve().
+				WhereDeletedBefore(telem.Now()).
+				Entries(&deleted).
+				Exec(ctx, tx)).To(Succeed())
+			Expect(deleted).To(HaveLen(1))
+			Expect(deleted[0].Key).To(Equal(r.Key))
+		})
+		It("Should restore a deleted range along with its children", func() {
+			parent := ranger.Range{
+				Name:      "Parent",
+				TimeRange: telem.TimeRange{Start: 5 * telem.SecondTS, End: 10 * telem.SecondTS},
+			}
+			Expect(svc.NewWriter(tx).Create(ctx, &parent)).To(Succeed())
+			r := ranger.Range{
+				Name:      "Range",
+				TimeRange: telem.TimeRange{Start: 7 * telem.SecondTS, End: 9 * telem.SecondTS},
+			}
+			Expect(svc.NewWriter(tx).CreateWithParent(ctx, &r, parent.OntologyID())).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, parent.Key)).To(Succeed())
+			Expect(svc.NewWriter(tx).Restore(ctx, parent.Key)).To(Succeed())
+			var retrieveR ranger.Range
+			Expect(svc.NewRetrieve().WhereKeys(r.Key).Entry(&retrieveR).Exec(ctx, tx)).To(Succeed())
+			Expect(retrieveR.Trashed()).To(BeFalse())
+			Expect(MustSucceed(retrieveR.Parent(ctx)).Key).To(Equal(parent.Key))
+		})
+		It("Should permanently delete a range and its deleted children when purged", func() {
+			parent := ranger.Range{
+				Name:      "Parent",
+				TimeRange: telem.TimeRange{Start: 5 * telem.SecondTS, End: 10 * telem.SecondTS},
+			}
+			Expect(svc.NewWriter(tx).Create(ctx, &parent)).To(Succeed())
+			r := ranger.Range{
+				Name:      "Range",
+				TimeRange: telem.TimeRange{Start: 7 * telem.SecondTS, End: 9 * telem.SecondTS},
+			}
+			Expect(svc.NewWriter(tx).CreateWithParent(ctx, &r, parent.OntologyID())).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, parent.Key)).To(Succeed())
+			Expect(svc.NewWriter(tx).Purge(ctx, parent.Key)).To(Succeed())
+			var retrieveR ranger.Range
+			Expect(svc.NewRetrieve().
+				WhereKeys(r.Key).
+				IncludeDeleted(true).
+				Entry(&retrieveR).
+				Exec(ctx, tx)).To(HaveOccurredAs(query.NotFound))
+			Expect(svc.NewRetrieve().
+				WhereKeys(parent.Key).
+				IncludeDeleted(true).
+				Entry(&retrieveR).
+				Exec(ctx, tx)).To(HaveOccurredAs(query.NotFound))
+		})
 	})
 
 	Describe("KV", func() {
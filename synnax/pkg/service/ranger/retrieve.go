@@ -25,7 +25,8 @@ type Retrieve struct {
 	baseTX     gorp.Tx
 	gorp       gorp.Retrieve[uuid.UUID, Range]
 	otg        *ontology.Ontology
-	searchTerm string
+	searchTerm     string
+	includeDeleted bool
 }
 
 // Search sets a fuzzy search term that Retrieve will use to filter results.
@@ -59,6 +60,21 @@ func (r Retrieve) WhereOverlapsWith(tr telem.TimeRange) Retrieve {
 	return r
 }
 
+// IncludeDeleted includes ranges in the trash in the results of the query if
+// includeDeleted is true. Ranges in the trash are excluded by default.
+func (r Retrieve) IncludeDeleted(includeDeleted bool) Retrieve {
+	r.includeDeleted = includeDeleted
+	return r
+}
+
+// WhereDeletedBefore filters for ranges that were moved to the trash before the
+// provided time. WhereDeletedBefore implies IncludeDeleted(true).
+func (r Retrieve) WhereDeletedBefore(ts telem.TimeStamp) Retrieve {
+	r.includeDeleted = true
+	r.gorp.Where(func(rng *Range) bool { return rng.Trashed() && rng.DeletedAt < ts }, gorp.Guard())
+	return r
+}
+
 // Exec executes the query and fills the results into the provided Range or slice of
 // Ranges. It's important to note that fuzzy search will not be aware of any writes/
 // deletes executed on the tx, and will only search the underlying database.
@@ -78,6 +94,9 @@ func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
 		}
 		r = r.WhereKeys(keys...)
 	}
+	if !r.includeDeleted {
+		r.gorp.Where(func(rng *Range) bool { return !rng.Trashed() }, gorp.Guard())
+	}
 	if err := r.gorp.Exec(ctx, tx); err != nil {
 		return err
 	}
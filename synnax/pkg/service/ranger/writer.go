@@ -18,6 +18,7 @@ import (
 	"github.com/synnaxlabs/x/errors"
 	"github.com/synnaxlabs/x/gorp"
 	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
 	"github.com/synnaxlabs/x/validate"
 )
 
@@ -139,41 +140,122 @@ func (w Writer) Rename(
 	}).Exec(ctx, w.tx)
 }
 
-// Delete deletes the range with the given key. Delete will also delete all children
-// of the range. Delete is idempotent.
+// Delete moves the range with the given key to the trash, along with all of its child
+// ranges. The ranges can be restored by calling Restore until they are purged. Delete
+// is idempotent.
 func (w Writer) Delete(ctx context.Context, key uuid.UUID) error {
-	// Query the ontology to find all children of the range and delete them as well
+	exists, err := gorp.NewRetrieve[uuid.UUID, Range]().WhereKeys(key).Exists(ctx, w.tx)
+	if err != nil || !exists {
+		return err
+	}
+	keys, err := w.retrieveChildRanges(ctx, key)
+	if err != nil {
+		return err
+	}
+	now := telem.Now()
+	return gorp.NewUpdate[uuid.UUID, Range]().
+		WhereKeys(append(keys, key)...).
+		Change(func(r Range) Range {
+			if !r.Trashed() {
+				r.DeletedAt = now
+			}
+			return r
+		}).
+		Exec(ctx, w.tx)
+}
+
+// Restore moves the range with the given key out of the trash, along with any child
+// ranges that were moved to the trash with it.
+func (w Writer) Restore(ctx context.Context, key uuid.UUID) error {
+	var rng Range
+	if err := gorp.NewRetrieve[uuid.UUID, Range]().
+		WhereKeys(key).
+		Entry(&rng).
+		Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	if !rng.Trashed() {
+		return nil
+	}
+	keys, err := w.retrieveChildRanges(ctx, key)
+	if err != nil {
+		return err
+	}
+	return gorp.NewUpdate[uuid.UUID, Range]().
+		WhereKeys(append(keys, key)...).
+		Change(func(r Range) Range {
+			if r.DeletedAt == rng.DeletedAt {
+				r.DeletedAt = 0
+			}
+			return r
+		}).
+		Exec(ctx, w.tx)
+}
+
+// Purge permanently deletes the range with the given key, regardless of whether it is
+// in the trash. Purge will also delete all children of the range that are in the trash.
+// Purge is idempotent.
+func (w Writer) Purge(ctx context.Context, key uuid.UUID) error {
+	keys, err := w.retrieveChildRanges(ctx, key)
+	if err != nil {
+		return err
+	}
+	var trashed []Range
+	if err = gorp.NewRetrieve[uuid.UUID, Range]().
+		WhereKeys(keys...).
+		Where(func(r *Range) bool { return r.Trashed() }).
+		Entries(&trashed).
+		Exec(ctx, w.tx); err != nil && !errors.Is(err, query.NotFound) {
+		return err
+	}
+	keys = append(lo.Map(trashed, func(r Range, _ int) uuid.UUID { return r.Key }), key)
+	if err = gorp.NewDelete[uuid.UUID, Range]().WhereKeys(keys...).Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err = w.otgWriter.DeleteResource(ctx, OntologyID(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retrieveChildRanges returns the keys of all descendant ranges of the range with the
+// given key.
+func (w Writer) retrieveChildRanges(ctx context.Context, key uuid.UUID) ([]uuid.UUID, error) {
 	var children []ontology.Resource
 	if err := w.otgWriter.NewRetrieve().
 		WhereIDs(OntologyID(key)).
+		// Skip retrieving the resources of the range and its children, as they are
+		// hidden from the ontology while the range is in the trash.
+		ExcludeFieldData(true).
+		IncludeSchema(false).
 		TraverseTo(ontology.Children).
 		Entries(&children).
 		ExcludeFieldData(true).
+		IncludeSchema(false).
 		// The check for query.NotFound is necessary because the child may have already
 		// been deleted, and delete is idempotent.
 		Exec(ctx, w.tx); err != nil && !errors.Is(err, query.NotFound) {
-		return err
+		return nil, err
 	}
-	keys := lo.FilterMap(children, func(r ontology.Resource, _ int) (string, bool) {
-		// Don't delete anything that's not a child range
-		if r.ID.Type != OntologyType {
-			return "", false
+	var keys []uuid.UUID
+	for _, child := range children {
+		// Don't include anything that's not a child range
+		if child.ID.Type != OntologyType {
+			continue
 		}
-		return r.ID.Key, true
-	})
-	for _, k := range keys {
-		uK, err := uuid.Parse(k)
+		k, err := uuid.Parse(child.ID.Key)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if err = w.Delete(ctx, uK); err != nil {
-			return err
+		descendants, err := w.retrieveChildRanges(ctx, k)
+		if err != nil {
+			return nil, err
 		}
+		keys = append(append(keys, k), descendants...)
 	}
-	if err := gorp.NewDelete[uuid.UUID, Range]().WhereKeys(key).Exec(ctx, w.tx); err != nil {
-		return err
-	}
-	return w.otgWriter.DeleteResource(ctx, OntologyID(key))
+	return keys, nil
 }
 
 func (w Writer) validate(r Range) error {
@@ -0,0 +1,452 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package replay implements re-streaming of historical telemetry. A replayer reads a
+// past time range of a set of channels and writes it, paced at real-time, scaled, or
+// stepped speed, to virtual mirror channels. Consumers stream the mirror channels as
+// they would any live channel, and the source channels are never modified.
+package replay
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer/iterator"
+	"github.com/synnaxlabs/synnax/pkg/storage/ts"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/control"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// DefaultMirrorSuffix is the suffix appended to the name of each source channel to get
+// the name of the virtual channel its data is replayed on.
+const DefaultMirrorSuffix = "_replay"
+
+// ServiceConfig is the configuration for opening the replay service.
+type ServiceConfig struct {
+	alamos.Instrumentation
+	// Channel is used to retrieve source channels and create mirror channels.
+	// [REQUIRED]
+	Channel channel.ReadWriteable
+	// Framer is used to read historical data and write it to mirror channels.
+	// [REQUIRED]
+	Framer framer.ReadWriteable
+	// Tick is the wall-clock interval at which a playing replayer writes the data
+	// that has elapsed since its last write.
+	// [OPTIONAL] - Defaults to 50ms.
+	Tick telem.TimeSpan
+}
+
+var (
+	_ config.Config[ServiceConfig] = ServiceConfig{}
+	// DefaultServiceConfig is the default configuration for opening the replay
+	// service. This configuration is not valid on its own, and must be overridden by
+	// the required fields specified in ServiceConfig.
+	DefaultServiceConfig = ServiceConfig{Tick: 50 * telem.Millisecond}
+)
+
+// Override implements config.Config.
+func (c ServiceConfig) Override(other ServiceConfig) ServiceConfig {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.Channel = override.Nil(c.Channel, other.Channel)
+	c.Framer = override.Nil(c.Framer, other.Framer)
+	c.Tick = override.Numeric(c.Tick, other.Tick)
+	return c
+}
+
+// Validate implements config.Config.
+func (c ServiceConfig) Validate() error {
+	v := validate.New("replay")
+	validate.NotNil(v, "Channel", c.Channel)
+	validate.NotNil(v, "Framer", c.Framer)
+	validate.Positive(v, "Tick", c.Tick)
+	return v.Error()
+}
+
+// Service opens replayers.
+type Service struct{ ServiceConfig }
+
+// OpenService opens a new replay service using the provided configuration.
+func OpenService(configs ...ServiceConfig) (*Service, error) {
+	cfg, err := config.New(DefaultServiceConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{ServiceConfig: cfg}, nil
+}
+
+// Config is the configuration for opening a replayer.
+type Config struct {
+	// Keys are the keys of the channels to replay. Virtual channels have no historical
+	// data, and cannot be replayed.
+	// [REQUIRED]
+	Keys channel.Keys `json:"keys" msgpack:"keys"`
+	// Bounds is the time range to replay.
+	// [REQUIRED]
+	Bounds telem.TimeRange `json:"bounds" msgpack:"bounds"`
+	// Speed is the rate at which data is replayed relative to real-time. For example,
+	// a speed of 2 replays ten seconds of data in five seconds.
+	// [OPTIONAL] - Defaults to 1.
+	Speed float64 `json:"speed" msgpack:"speed"`
+	// MirrorSuffix is appended to the name of each source channel to get the name of
+	// the virtual channel its data is replayed on. Mirror channels that do not exist
+	// are created.
+	// [OPTIONAL] - Defaults to DefaultMirrorSuffix.
+	MirrorSuffix string `json:"mirror_suffix" msgpack:"mirror_suffix"`
+}
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Keys = override.Slice(c.Keys, other.Keys)
+	c.Bounds = override.Zero(c.Bounds, other.Bounds)
+	c.Speed = override.Numeric(c.Speed, other.Speed)
+	c.MirrorSuffix = override.String(c.MirrorSuffix, other.MirrorSuffix)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("replay")
+	validate.NotEmptySlice(v, "keys", c.Keys)
+	validate.Positive(v, "speed", c.Speed)
+	validate.NotEmptyString(v, "mirror_suffix", c.MirrorSuffix)
+	v.Ternary("bounds", !c.Bounds.Valid() || c.Bounds.IsZero(), "must be a valid, non-empty time range")
+	return v.Error()
+}
+
+// DefaultConfig is the default configuration for opening a replayer.
+var DefaultConfig = Config{Speed: 1, MirrorSuffix: DefaultMirrorSuffix}
+
+// Command is a command that can be sent to a replayer.
+type Command uint8
+
+const (
+	// Open is the command used to open a replayer. Replayers open paused at the start
+	// of their bounds.
+	Open Command = iota
+	// Play starts writing data to the mirror channels at the replayer's speed.
+	Play
+	// Pause stops writing data until the next Play command.
+	Pause
+	// Seek moves the replayer to Request.Stamp. Data before the new position is not
+	// replayed.
+	Seek
+	// SetSpeed changes the speed of the replayer to Request.Speed.
+	SetSpeed
+	// Step writes Request.Span worth of data to the mirror channels regardless of the
+	// replayer's speed. Stepping while the replayer is playing is not allowed.
+	Step
+)
+
+// Request is a request to a replayer.
+type Request struct {
+	// Command is the command to execute.
+	Command Command `json:"command" msgpack:"command"`
+	// Stamp is the position to move the replayer to. Only used by Seek commands.
+	Stamp telem.TimeStamp `json:"stamp" msgpack:"stamp"`
+	// Speed is the new speed of the replayer. Only used by SetSpeed commands.
+	Speed float64 `json:"speed" msgpack:"speed"`
+	// Span is the amount of data to replay. Only used by Step commands.
+	Span telem.TimeSpan `json:"span" msgpack:"span"`
+}
+
+// ResponseVariant distinguishes responses to commands from notifications sent by a
+// replayer while it's playing.
+type ResponseVariant uint8
+
+const (
+	// AckResponse is sent in response to every command.
+	AckResponse ResponseVariant = iota + 1
+	// StatusResponse is sent when a playing replayer reaches the end of its bounds or
+	// fails to replay data. In both cases, the replayer is paused.
+	StatusResponse
+)
+
+// Response is a response from a replayer.
+type Response struct {
+	// Variant is the variant of the response.
+	Variant ResponseVariant `json:"variant" msgpack:"variant"`
+	// Command is the command the response acknowledges. Only set for AckResponse.
+	Command Command `json:"command" msgpack:"command"`
+	// Ack is true if the command was executed successfully.
+	Ack bool `json:"ack" msgpack:"ack"`
+	// Error is the reason a command or replay failed.
+	Error error `json:"error" msgpack:"error"`
+	// Position is the timestamp up to which (exclusive) data has been replayed.
+	Position telem.TimeStamp `json:"position" msgpack:"position"`
+	// Playing is true if the replayer is currently playing.
+	Playing bool `json:"playing" msgpack:"playing"`
+	// Speed is the current speed of the replayer.
+	Speed float64 `json:"speed" msgpack:"speed"`
+	// End is true if data has been replayed up to the end of the replayer's bounds.
+	End bool `json:"end" msgpack:"end"`
+}
+
+// Replayer is a segment that replays historical data onto mirror channels in response
+// to Requests.
+type Replayer interface {
+	confluence.Segment[Request, Response]
+	// Mirrors returns the mirror channels of the replayer, in the same order as the
+	// source channels in its configuration.
+	Mirrors() []channel.Channel
+}
+
+// NewReplayer opens a new replayer using the provided configuration, creating any of
+// its mirror channels that do not already exist.
+func (s *Service) NewReplayer(ctx context.Context, cfgs ...Config) (Replayer, error) {
+	cfg, err := config.New(DefaultConfig, cfgs...)
+	if err != nil {
+		return nil, err
+	}
+	mirrors, err := s.openMirrors(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := s.Framer.OpenIterator(ctx, framer.IteratorConfig{
+		Keys:   cfg.Keys,
+		Bounds: cfg.Bounds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.Framer.OpenWriter(ctx, framer.WriterConfig{
+		ControlSubject: control.Subject{Key: uuid.NewString(), Name: "replay"},
+		Keys:           channel.KeysFromChannels(mirrors),
+		Start:          telem.Now(),
+		Mode:           ts.WriterStreamOnly,
+	})
+	if err != nil {
+		return nil, errors.CombineErrors(err, iter.Close())
+	}
+	return &replayer{
+		Instrumentation: s.Instrumentation,
+		cfg:             cfg,
+		tick:            s.Tick,
+		mirrors:         mirrors,
+		iter:            iter,
+		writer:          w,
+		position:        cfg.Bounds.Start,
+		speed:           cfg.Speed,
+	}, nil
+}
+
+// openMirrors retrieves the mirror channels for the source channels in the config,
+// creating the ones that don't exist.
+func (s *Service) openMirrors(ctx context.Context, cfg Config) ([]channel.Channel, error) {
+	var sources []channel.Channel
+	if err := s.Channel.NewRetrieve().
+		WhereKeys(cfg.Keys...).
+		Entries(&sources).
+		Exec(ctx, nil); err != nil {
+		return nil, err
+	}
+	// Retrieved channels aren't guaranteed to be in the same order as the keys.
+	sources = lo.Map(cfg.Keys, func(k channel.Key, _ int) channel.Channel {
+		src, _ := lo.Find(sources, func(ch channel.Channel) bool { return ch.Key() == k })
+		return src
+	})
+	mirrors := make([]channel.Channel, len(sources))
+	for i, src := range sources {
+		if src.Virtual {
+			return nil, validate.FieldError{
+				Field:   "keys",
+				Message: "cannot replay virtual channel " + src.Name,
+			}
+		}
+		mirrors[i] = channel.Channel{
+			Name:     src.Name + cfg.MirrorSuffix,
+			DataType: src.DataType,
+			Virtual:  true,
+		}
+	}
+	if err := s.Channel.NewWriter(nil).CreateManyIfNamesDontExist(ctx, &mirrors); err != nil {
+		return nil, err
+	}
+	for i, m := range mirrors {
+		// A channel with the mirror's name may have existed before the replay, in which
+		// case we need to make sure we won't overwrite live or persisted data.
+		if !m.Virtual || m.DataType != sources[i].DataType {
+			return nil, validate.FieldError{
+				Field:   "mirror_suffix",
+				Message: "channel " + m.Name + " exists and is not a virtual " + string(sources[i].DataType) + " channel",
+			}
+		}
+	}
+	return mirrors, nil
+}
+
+type replayer struct {
+	alamos.Instrumentation
+	confluence.AbstractUnarySink[Request]
+	confluence.AbstractUnarySource[Response]
+	cfg      Config
+	tick     telem.TimeSpan
+	mirrors  []channel.Channel
+	iter     *framer.Iterator
+	writer   *framer.Writer
+	position telem.TimeStamp
+	speed    float64
+	playing  bool
+}
+
+var _ Replayer = (*replayer)(nil)
+
+// Mirrors implements Replayer.
+func (r *replayer) Mirrors() []channel.Channel { return r.mirrors }
+
+// Flow implements confluence.Flow.
+func (r *replayer) Flow(sCtx signal.Context, opts ...confluence.Option) {
+	o := confluence.NewOptions(opts)
+	o.AttachClosables(r.Out)
+	sCtx.Go(func(ctx context.Context) (err error) {
+		defer func() {
+			err = errors.CombineErrors(err, r.writer.Close())
+			err = errors.CombineErrors(err, r.iter.Close())
+		}()
+		ticker := time.NewTicker(r.tick.Duration())
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case req, ok := <-r.In.Outlet():
+				if !ok {
+					return nil
+				}
+				wasPlaying := r.playing
+				res := r.exec(req)
+				if r.playing && !wasPlaying {
+					last = time.Now()
+				}
+				if err := signal.SendUnderContext(ctx, r.Out.Inlet(), res); err != nil {
+					return err
+				}
+			case now := <-ticker.C:
+				if !r.playing {
+					continue
+				}
+				elapsed := telem.TimeSpan(float64(now.Sub(last)) * r.speed)
+				last = now
+				if err := r.replay(elapsed); err != nil || r.position >= r.cfg.Bounds.End {
+					r.playing = false
+					if err := signal.SendUnderContext(ctx, r.Out.Inlet(), r.response(StatusResponse, err)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}, o.Signal...)
+}
+
+func (r *replayer) exec(req Request) Response {
+	res := r.response(AckResponse, r.execErr(req))
+	res.Command = req.Command
+	res.Ack = res.Error == nil
+	return res
+}
+
+func (r *replayer) execErr(req Request) error {
+	switch req.Command {
+	case Play:
+		if r.position >= r.cfg.Bounds.End {
+			return errors.Wrap(validate.Error, "[replay] - cannot play past the end of the replay bounds")
+		}
+		r.playing = true
+	case Pause:
+		r.playing = false
+	case Seek:
+		if !r.cfg.Bounds.ContainsStamp(req.Stamp) && req.Stamp != r.cfg.Bounds.End {
+			return validate.FieldError{Field: "stamp", Message: "must be within the replay bounds"}
+		}
+		r.position = req.Stamp
+	case SetSpeed:
+		if req.Speed <= 0 {
+			return validate.FieldError{Field: "speed", Message: "must be positive"}
+		}
+		r.speed = req.Speed
+	case Step:
+		if r.playing {
+			return errors.Wrap(validate.Error, "[replay] - cannot step while playing")
+		}
+		if req.Span <= 0 {
+			return validate.FieldError{Field: "span", Message: "must be positive"}
+		}
+		return r.replay(req.Span)
+	default:
+		return errors.Wrapf(validate.Error, "[replay] - invalid command: %d", req.Command)
+	}
+	return nil
+}
+
+func (r *replayer) response(variant ResponseVariant, err error) Response {
+	return Response{
+		Variant:  variant,
+		Error:    err,
+		Position: r.position,
+		Playing:  r.playing,
+		Speed:    r.speed,
+		End:      r.position >= r.cfg.Bounds.End,
+	}
+}
+
+// replay reads the next span of data after the replayer's position and writes it to
+// the mirror channels, advancing the position.
+func (r *replayer) replay(span telem.TimeSpan) error {
+	if span <= 0 {
+		return nil
+	}
+	window := r.position.SpanRange(span).BoundBy(r.cfg.Bounds)
+	if window.IsZero() {
+		return nil
+	}
+	r.iter.SetBounds(window)
+	if r.iter.SeekFirst() {
+		for r.iter.Next(iterator.AutoSpan) {
+			if err := r.write(r.iter.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := r.iter.Error(); err != nil {
+		return err
+	}
+	r.position = window.End
+	return nil
+}
+
+// write writes a frame of historical data to the mirror channels.
+func (r *replayer) write(fr framer.Frame) error {
+	if len(fr.Keys) == 0 {
+		return nil
+	}
+	mirrored := framer.Frame{
+		Keys: lo.Map(fr.Keys, func(k channel.Key, _ int) channel.Key {
+			return r.mirrors[lo.IndexOf(r.cfg.Keys, k)].Key()
+		}),
+		Series: fr.Series,
+	}
+	if !r.writer.Write(mirrored) {
+		if err := r.writer.Error(); err != nil {
+			return err
+		}
+		return errors.New("[replay] - failed to write to mirror channels")
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package replay_test
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/framer"
+	"github.com/synnaxlabs/synnax/pkg/service/replay"
+	"github.com/synnaxlabs/x/confluence"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+	"github.com/synnaxlabs/x/validate"
+)
+
+var _ = Describe("Replay", func() {
+	var (
+		svc       *replay.Service
+		idx, data channel.Channel
+		bounds    = telem.SecondTS.Range(11 * telem.SecondTS)
+	)
+	BeforeEach(func() {
+		svc = MustSucceed(replay.OpenService(replay.ServiceConfig{
+			Channel: dist.Channel,
+			Framer:  dist.Framer,
+			Tick:    5 * telem.Millisecond,
+		}))
+		prefix := uuid.NewString()[:8]
+		idx = channel.Channel{Name: prefix + "_time", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &idx)).To(Succeed())
+		data = channel.Channel{Name: prefix + "_data", DataType: telem.Int64T, LocalIndex: idx.LocalKey}
+		Expect(dist.Channel.NewWriter(nil).Create(ctx, &data)).To(Succeed())
+		w := MustSucceed(dist.Framer.OpenWriter(ctx, framer.WriterConfig{
+			Keys:  channel.Keys{idx.Key(), data.Key()},
+			Start: telem.SecondTS,
+		}))
+		Expect(w.Write(framer.Frame{
+			Keys: channel.Keys{idx.Key(), data.Key()},
+			Series: []telem.Series{
+				telem.NewSecondsTSV(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+				telem.NewSeriesV[int64](1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+			},
+		})).To(BeTrue())
+		Expect(w.Commit()).To(BeTrue())
+		Expect(w.Close()).To(Succeed())
+	})
+	type stream struct {
+		requests  confluence.Inlet[replay.Request]
+		responses confluence.Outlet[replay.Response]
+		frames    confluence.Outlet[framer.StreamerResponse]
+		close     func()
+	}
+	open := func(cfg replay.Config) (replay.Replayer, stream) {
+		r := MustSucceed(svc.NewReplayer(ctx, cfg))
+		s := MustSucceed(dist.Framer.NewStreamer(ctx, framer.StreamerConfig{
+			Keys: channel.KeysFromChannels(r.Mirrors()),
+		}))
+		sCtx, cancel := signal.Isolated()
+		req, res := confluence.Attach[replay.Request, replay.Response](r, 10)
+		sReq, frames := confluence.Attach(s, 10)
+		r.Flow(sCtx, confluence.CloseOutputInletsOnExit())
+		s.Flow(sCtx, confluence.CloseOutputInletsOnExit())
+		// Give the streamer a moment to subscribe to the relay.
+		time.Sleep(10 * time.Millisecond)
+		return r, stream{
+			requests:  req,
+			responses: res,
+			frames:    frames,
+			close: func() {
+				req.Close()
+				sReq.Close()
+				Expect(sCtx.Wait()).To(Succeed())
+				cancel()
+			},
+		}
+	}
+	exec := func(s stream, req replay.Request) replay.Response {
+		s.requests.Inlet() <- req
+		var res replay.Response
+		Eventually(s.responses.Outlet()).Should(Receive(&res))
+		Expect(res.Variant).To(Equal(replay.AckResponse))
+		Expect(res.Command).To(Equal(req.Command))
+		return res
+	}
+	received := func(s stream, key channel.Key, count int) []int64 {
+		var values []int64
+		Eventually(func(g Gomega) {
+			var res framer.StreamerResponse
+			g.Eventually(s.frames.Outlet()).Should(Receive(&res))
+			for _, series := range res.Frame.Get(key) {
+				values = append(values, telem.Unmarshal[int64](series)...)
+			}
+			g.Expect(values).To(HaveLen(count))
+		}).Should(Succeed())
+		return values
+	}
+	Describe("Mirrors", func() {
+		It("Should create virtual mirror channels for the source channels", func() {
+			r, s := open(replay.Config{Keys: channel.Keys{data.Key(), idx.Key()}, Bounds: bounds})
+			mirrors := r.Mirrors()
+			Expect(mirrors).To(HaveLen(2))
+			Expect(mirrors[0].Name).To(Equal(data.Name + replay.DefaultMirrorSuffix))
+			Expect(mirrors[0].DataType).To(Equal(telem.Int64T))
+			Expect(mirrors[0].Virtual).To(BeTrue())
+			Expect(mirrors[1].Name).To(Equal(idx.Name + replay.DefaultMirrorSuffix))
+			Expect(mirrors[1].DataType).To(Equal(telem.TimeStampT))
+			s.close()
+			By("Reusing existing mirror channels")
+			r, s = open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds})
+			Expect(r.Mirrors()[0].Key()).To(Equal(mirrors[0].Key()))
+			s.close()
+		})
+		It("Should not replay onto a channel that isn't virtual", func() {
+			conflict := channel.Channel{
+				Name:       data.Name + "_conflict",
+				DataType:   telem.Int64T,
+				LocalIndex: idx.LocalKey,
+			}
+			Expect(dist.Channel.NewWriter(nil).Create(ctx, &conflict)).To(Succeed())
+			_, err := svc.NewReplayer(ctx, replay.Config{
+				Keys:         channel.Keys{data.Key()},
+				Bounds:       bounds,
+				MirrorSuffix: "_conflict",
+			})
+			Expect(err).To(MatchError(ContainSubstring("is not a virtual int64 channel")))
+		})
+		It("Should not replay a virtual channel", func() {
+			v := channel.Channel{Name: uuid.NewString(), DataType: telem.Int64T, Virtual: true}
+			Expect(dist.Channel.NewWriter(nil).Create(ctx, &v)).To(Succeed())
+			_, err := svc.NewReplayer(ctx, replay.Config{Keys: channel.Keys{v.Key()}, Bounds: bounds})
+			Expect(err).To(MatchError(ContainSubstring("cannot replay virtual channel")))
+		})
+	})
+	Describe("Step", func() {
+		It("Should replay a span of data on each step", func() {
+			r, s := open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds})
+			mirror := r.Mirrors()[0].Key()
+			res := exec(s, replay.Request{Command: replay.Step, Span: 3 * telem.Second})
+			Expect(res.Ack).To(BeTrue())
+			Expect(res.Position).To(Equal(4 * telem.SecondTS))
+			Expect(received(s, mirror, 3)).To(Equal([]int64{1, 2, 3}))
+			res = exec(s, replay.Request{Command: replay.Step, Span: 2 * telem.Second})
+			Expect(res.Position).To(Equal(6 * telem.SecondTS))
+			Expect(received(s, mirror, 2)).To(Equal([]int64{4, 5}))
+			s.close()
+		})
+		It("Should not step past the end of the bounds", func() {
+			_, s := open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds})
+			res := exec(s, replay.Request{Command: replay.Step, Span: 100 * telem.Second})
+			Expect(res.Position).To(Equal(bounds.End))
+			Expect(res.End).To(BeTrue())
+			s.close()
+		})
+	})
+	Describe("Seek", func() {
+		It("Should move the position of the replay", func() {
+			r, s := open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds})
+			res := exec(s, replay.Request{Command: replay.Seek, Stamp: 8 * telem.SecondTS})
+			Expect(res.Ack).To(BeTrue())
+			Expect(res.Position).To(Equal(8 * telem.SecondTS))
+			exec(s, replay.Request{Command: replay.Step, Span: telem.Second})
+			Expect(received(s, r.Mirrors()[0].Key(), 1)).To(Equal([]int64{8}))
+			s.close()
+		})
+		It("Should not seek outside of the bounds", func() {
+			_, s := open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds})
+			res := exec(s, replay.Request{Command: replay.Seek, Stamp: 20 * telem.SecondTS})
+			Expect(res.Ack).To(BeFalse())
+			Expect(res.Error).To(MatchError(ContainSubstring("must be within the replay bounds")))
+			Expect(res.Position).To(Equal(bounds.Start))
+			s.close()
+		})
+	})
+	Describe("Play", func() {
+		It("Should replay all data at the configured speed and pause at the end", func() {
+			r, s := open(replay.Config{
+				Keys:   channel.Keys{data.Key()},
+				Bounds: bounds,
+				Speed:  100,
+			})
+			start := time.Now()
+			Expect(exec(s, replay.Request{Command: replay.Play}).Playing).To(BeTrue())
+			Expect(received(s, r.Mirrors()[0].Key(), 10)).To(Equal([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}))
+			var res replay.Response
+			Eventually(s.responses.Outlet()).Should(Receive(&res))
+			Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+			Expect(res.Variant).To(Equal(replay.StatusResponse))
+			Expect(res.End).To(BeTrue())
+			Expect(res.Playing).To(BeFalse())
+			Expect(res.Error).ToNot(HaveOccurred())
+			s.close()
+		})
+		It("Should stop replaying data when paused", func() {
+			r, s := open(replay.Config{
+				Keys:   channel.Keys{data.Key()},
+				Bounds: bounds,
+				Speed:  0.001,
+			})
+			exec(s, replay.Request{Command: replay.SetSpeed, Speed: 20})
+			exec(s, replay.Request{Command: replay.Play})
+			Expect(received(s, r.Mirrors()[0].Key(), 1)).To(Equal([]int64{1}))
+			res := exec(s, replay.Request{Command: replay.Pause})
+			Expect(res.Playing).To(BeFalse())
+			Expect(res.Position).To(BeNumerically("<", bounds.End))
+			Consistently(s.frames.Outlet(), 50*time.Millisecond).ShouldNot(Receive())
+			s.close()
+		})
+		It("Should not allow stepping while playing", func() {
+			_, s := open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds, Speed: 0.001})
+			exec(s, replay.Request{Command: replay.Play})
+			res := exec(s, replay.Request{Command: replay.Step, Span: telem.Second})
+			Expect(res.Ack).To(BeFalse())
+			Expect(res.Error).To(HaveOccurredAs(validate.Error))
+			s.close()
+		})
+	})
+	Describe("SetSpeed", func() {
+		It("Should not allow a non-positive speed", func() {
+			_, s := open(replay.Config{Keys: channel.Keys{data.Key()}, Bounds: bounds})
+			res := exec(s, replay.Request{Command: replay.SetSpeed, Speed: -1})
+			Expect(res.Ack).To(BeFalse())
+			Expect(res.Speed).To(Equal(1.0))
+			s.close()
+		})
+	})
+})
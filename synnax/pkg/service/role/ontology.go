@@ -0,0 +1,137 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/schema"
+	changex "github.com/synnaxlabs/x/change"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/iter"
+	"github.com/synnaxlabs/x/observe"
+)
+
+// OntologyType is the ontology type for roles.
+const OntologyType ontology.Type = "role"
+
+const (
+	// MemberOf indicates that a resource is a member of a role. When examining a
+	// Relationship of type MemberOf, the From field will be the member and the To field
+	// will be the role (i.e. From is a MemberOf To).
+	MemberOf ontology.RelationshipType = "member_of"
+)
+
+var (
+	// Roles is an ontology.Traverser that finds the roles a resource is a direct member
+	// of. Pass this traverser to ontology.Retrieve.TraverseTo.
+	Roles = ontology.Traverser{
+		Filter: func(res *ontology.Resource, rel *ontology.Relationship) bool {
+			return rel.Type == MemberOf && rel.From == res.ID
+		},
+		Direction: ontology.Forward,
+	}
+	// Members is an ontology.Traverser that finds the direct members of a role. Pass
+	// this traverser to ontology.Retrieve.TraverseTo.
+	Members = ontology.Traverser{
+		Filter: func(res *ontology.Resource, rel *ontology.Relationship) bool {
+			return rel.Type == MemberOf && rel.To == res.ID
+		},
+		Direction: ontology.Backward,
+	}
+)
+
+// OntologyID constructs a unique ontology.ID for the role with the given key.
+func OntologyID(k uuid.UUID) ontology.ID {
+	return ontology.ID{Type: OntologyType, Key: k.String()}
+}
+
+// OntologyIDs constructs a slice of unique ontology.IDs for the roles with the given
+// keys.
+func OntologyIDs(keys []uuid.UUID) []ontology.ID {
+	return lo.Map(keys, func(k uuid.UUID, _ int) ontology.ID { return OntologyID(k) })
+}
+
+// OntologyIDsFromRoles constructs a slice of unique ontology.IDs for the given roles.
+func OntologyIDsFromRoles(roles []Role) []ontology.ID {
+	return lo.Map(roles, func(r Role, _ int) ontology.ID { return OntologyID(r.Key) })
+}
+
+// KeysFromOntologyIDs extracts the role keys from the given ontology.IDs.
+func KeysFromOntologyIDs(ids []ontology.ID) (keys []uuid.UUID, err error) {
+	keys = make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		keys[i], err = uuid.Parse(id.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+var _schema = &ontology.Schema{
+	Type: OntologyType,
+	Fields: map[string]schema.Field{
+		"key":         {Type: schema.String},
+		"name":        {Type: schema.String},
+		"description": {Type: schema.String},
+	},
+}
+
+func newResource(r Role) schema.Resource {
+	e := schema.NewResource(_schema, OntologyID(r.Key), r.Name)
+	schema.Set(e, "key", r.Key.String())
+	schema.Set(e, "name", r.Name)
+	schema.Set(e, "description", r.Description)
+	return e
+}
+
+var _ ontology.Service = (*Service)(nil)
+
+type change = changex.Change[uuid.UUID, Role]
+
+// Schema implements ontology.Service.
+func (s *Service) Schema() *schema.Schema { return _schema }
+
+// RetrieveResource implements ontology.Service.
+func (s *Service) RetrieveResource(ctx context.Context, key string, tx gorp.Tx) (ontology.Resource, error) {
+	k, err := uuid.Parse(key)
+	if err != nil {
+		return ontology.Resource{}, err
+	}
+	var r Role
+	err = s.NewRetrieve().WhereKeys(k).Entry(&r).Exec(ctx, tx)
+	return newResource(r), err
+}
+
+func translateChange(c change) schema.Change {
+	return schema.Change{
+		Variant: c.Variant,
+		Key:     OntologyID(c.Key),
+		Value:   newResource(c.Value),
+	}
+}
+
+// OnChange implements ontology.Service.
+func (s *Service) OnChange(f func(ctx context.Context, nexter iter.Nexter[schema.Change])) observe.Disconnect {
+	handleChange := func(ctx context.Context, reader gorp.TxReader[uuid.UUID, Role]) {
+		f(ctx, iter.NexterTranslator[change, schema.Change]{Wrap: reader, Translate: translateChange})
+	}
+	return gorp.Observe[uuid.UUID, Role](s.DB).OnChange(handleChange)
+}
+
+// OpenNexter implements ontology.Service.
+func (s *Service) OpenNexter() (iter.NexterCloser[schema.Resource], error) {
+	n, err := gorp.WrapReader[uuid.UUID, Role](s.DB).OpenNexter()
+	return iter.NexterCloserTranslator[Role, schema.Resource]{Wrap: n, Translate: newResource}, err
+}
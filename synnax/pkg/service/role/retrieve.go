@@ -0,0 +1,49 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+// Retrieve is a builder for querying roles.
+type Retrieve struct {
+	baseTx gorp.Tx
+	gorp   gorp.Retrieve[uuid.UUID, Role]
+}
+
+// Entry binds the Role that Retrieve will fill results into. If multiple results match
+// the query, only the first result will be filled into the provided Role.
+func (r Retrieve) Entry(role *Role) Retrieve { r.gorp = r.gorp.Entry(role); return r }
+
+// Entries binds a slice that Retrieve will fill results into.
+func (r Retrieve) Entries(roles *[]Role) Retrieve { r.gorp = r.gorp.Entries(roles); return r }
+
+// WhereKeys filters for roles with the provided keys.
+func (r Retrieve) WhereKeys(keys ...uuid.UUID) Retrieve {
+	r.gorp = r.gorp.WhereKeys(keys...)
+	return r
+}
+
+// WhereNames filters for roles whose Name attribute matches one of the provided names.
+func (r Retrieve) WhereNames(names ...string) Retrieve {
+	r.gorp = r.gorp.Where(func(role *Role) bool { return lo.Contains(names, role.Name) })
+	return r
+}
+
+// Exec executes the Retrieve query. If a tx is provided, Exec will use it to execute
+// the query. Otherwise, it will execute against the underlying gorp.DB.
+func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
+	return r.gorp.Exec(ctx, gorp.OverrideTx(r.baseTx, tx))
+}
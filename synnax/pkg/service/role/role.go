@@ -0,0 +1,40 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package role implements roles: named groups of users (and other roles) that can be
+// used as the subjects of access control policies. Membership in a role is stored as
+// a MemberOf relationship in the ontology.
+package role
+
+import (
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/x/gorp"
+)
+
+// Role is a named set of members that policies can target as a single subject.
+type Role struct {
+	// Key is a unique identifier for the role.
+	Key uuid.UUID `json:"key" msgpack:"key"`
+	// Name is the human-readable name of the role.
+	Name string `json:"name" msgpack:"name"`
+	// Description is an optional description of the role's purpose.
+	Description string `json:"description" msgpack:"description"`
+}
+
+var _ gorp.Entry[uuid.UUID] = Role{}
+
+// GorpKey implements gorp.Entry.
+func (r Role) GorpKey() uuid.UUID { return r.Key }
+
+// SetOptions implements gorp.Entry.
+func (r Role) SetOptions() []interface{} { return nil }
+
+// OntologyID returns the unique ontology identifier for the role.
+func (r Role) OntologyID() ontology.ID { return OntologyID(r.Key) }
@@ -0,0 +1,108 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package role_test
+
+import (
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/synnax/pkg/service/role"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/kv/memkv"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Role", func() {
+	var (
+		db     *gorp.DB
+		otg    *ontology.Ontology
+		svc    *role.Service
+		w      role.Writer
+		userID ontology.ID
+	)
+	BeforeEach(func() {
+		db = gorp.Wrap(memkv.New())
+		otg = MustSucceed(ontology.Open(ctx, ontology.Config{DB: db}))
+		g := MustSucceed(group.OpenService(group.Config{DB: db, Ontology: otg}))
+		userSvc := MustSucceed(user.NewService(ctx, user.Config{DB: db, Ontology: otg, Group: g}))
+		_, err := role.OpenService(ctx, role.Config{})
+		Expect(err).To(HaveOccurred())
+		svc = MustSucceed(role.OpenService(ctx, role.Config{DB: db, Ontology: otg, Group: g}))
+		w = svc.NewWriter(nil)
+		u := user.User{Username: "operator"}
+		Expect(userSvc.NewWriter(nil).Create(ctx, &u)).To(Succeed())
+		userID = user.OntologyID(u.Key)
+	})
+	AfterEach(func() {
+		Expect(otg.Close()).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+	})
+	Describe("Create", func() {
+		It("Should create a role and define it in the ontology", func() {
+			r := role.Role{Name: "Operators"}
+			Expect(w.Create(ctx, &r)).To(Succeed())
+			Expect(r.Key).ToNot(Equal(uuid.Nil))
+			var res ontology.Resource
+			Expect(otg.NewRetrieve().WhereIDs(r.OntologyID()).Entry(&res).Exec(ctx, nil)).To(Succeed())
+			Expect(res.Name).To(Equal("Operators"))
+		})
+		It("Should not create a role without a name", func() {
+			Expect(w.Create(ctx, &role.Role{})).ToNot(Succeed())
+		})
+	})
+	Describe("Retrieve", func() {
+		It("Should retrieve roles by name", func() {
+			roles := []role.Role{{Name: "Operators"}, {Name: "Engineers"}}
+			Expect(w.CreateMany(ctx, &roles)).To(Succeed())
+			var res role.Role
+			Expect(svc.NewRetrieve().WhereNames("Engineers").Entry(&res).Exec(ctx, nil)).To(Succeed())
+			Expect(res.Key).To(Equal(roles[1].Key))
+		})
+	})
+	Describe("Membership", func() {
+		var operators, engineers role.Role
+		BeforeEach(func() {
+			operators, engineers = role.Role{Name: "Operators"}, role.Role{Name: "Engineers"}
+			Expect(w.Create(ctx, &operators)).To(Succeed())
+			Expect(w.Create(ctx, &engineers)).To(Succeed())
+		})
+		It("Should add and remove members of a role", func() {
+			Expect(w.AddMembers(ctx, operators.Key, userID)).To(Succeed())
+			Expect(svc.RetrieveMembers(ctx, operators.Key, nil)).To(ConsistOf(userID))
+			roles := MustSucceed(svc.RetrieveFor(ctx, userID, false, nil))
+			Expect(roles).To(HaveLen(1))
+			Expect(roles[0].Key).To(Equal(operators.Key))
+			Expect(w.RemoveMembers(ctx, operators.Key, userID)).To(Succeed())
+			Expect(svc.RetrieveMembers(ctx, operators.Key, nil)).To(BeEmpty())
+		})
+		It("Should retrieve roles inherited through nested roles", func() {
+			Expect(w.AddMembers(ctx, operators.Key, userID)).To(Succeed())
+			Expect(w.AddMembers(ctx, engineers.Key, operators.OntologyID())).To(Succeed())
+			Expect(svc.RetrieveIDsFor(ctx, userID, false, nil)).To(ConsistOf(operators.OntologyID()))
+			Expect(svc.RetrieveIDsFor(ctx, userID, true, nil)).To(ConsistOf(
+				operators.OntologyID(),
+				engineers.OntologyID(),
+			))
+		})
+		It("Should not allow cyclic memberships", func() {
+			Expect(w.AddMembers(ctx, engineers.Key, operators.OntologyID())).To(Succeed())
+			Expect(w.AddMembers(ctx, operators.Key, engineers.OntologyID())).To(MatchError(ontology.ErrCycle))
+			Expect(w.AddMembers(ctx, operators.Key, operators.OntologyID())).To(MatchError(ontology.ErrCycle))
+		})
+		It("Should remove memberships when a role is deleted", func() {
+			Expect(w.AddMembers(ctx, operators.Key, userID)).To(Succeed())
+			Expect(w.Delete(ctx, operators.Key)).To(Succeed())
+			Expect(svc.RetrieveFor(ctx, userID, true, nil)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,187 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Config is the configuration for the role service. Config is provided to the
+// OpenService method.
+type Config struct {
+	// DB specifies the database that the role service will use to store and retrieve
+	// roles.
+	// [REQUIRED]
+	DB *gorp.DB
+	// Ontology is the ontology service that the role service will use to define roles
+	// and manage their memberships.
+	// [REQUIRED]
+	Ontology *ontology.Ontology
+	// Group is used to create the top level "Roles" group that will be the parent of
+	// all roles.
+	// [REQUIRED]
+	Group *group.Service
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default for the role service. This configuration is not
+	// valid, and must be overridden with a valid configuration before the service can
+	// be opened.
+	DefaultConfig = Config{}
+)
+
+// Validate implements config.Properties.
+func (c Config) Validate() error {
+	v := validate.New("role")
+	validate.NotNil(v, "DB", c.DB)
+	validate.NotNil(v, "Ontology", c.Ontology)
+	validate.NotNil(v, "Group", c.Group)
+	return v.Error()
+}
+
+// Override implements config.Properties.
+func (c Config) Override(other Config) Config {
+	c.DB = override.Nil(c.DB, other.DB)
+	c.Ontology = override.Nil(c.Ontology, other.Ontology)
+	c.Group = override.Nil(c.Group, other.Group)
+	return c
+}
+
+// Service is the main entry point for managing roles and their memberships within
+// Synnax.
+type Service struct {
+	Config
+	group group.Group
+}
+
+const groupName = "Roles"
+
+// OpenService opens a new role service using the provided configuration.
+func OpenService(ctx context.Context, cfgs ...Config) (*Service, error) {
+	cfg, err := config.New(DefaultConfig, cfgs...)
+	if err != nil {
+		return nil, err
+	}
+	g, err := cfg.Group.CreateOrRetrieve(ctx, groupName, ontology.RootID)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Config: cfg, group: g}
+	cfg.Ontology.RegisterService(s)
+	return s, nil
+}
+
+// NewRetrieve opens a new Retrieve query to fetch roles.
+func (s *Service) NewRetrieve() Retrieve {
+	return Retrieve{baseTx: s.DB, gorp: gorp.NewRetrieve[uuid.UUID, Role]()}
+}
+
+// NewWriter opens a new Writer to create, update, and delete roles and manage their
+// members. If tx is not nil the writer will use it, otherwise it will execute
+// operations directly against the underlying gorp.DB.
+func (s *Service) NewWriter(tx gorp.Tx) Writer {
+	tx = gorp.OverrideTx(s.DB, tx)
+	return Writer{tx: tx, otg: s.Ontology.NewWriter(tx), group: s.group}
+}
+
+// RetrieveFor retrieves the roles that the given resource is a member of. If
+// transitive is true, roles inherited through membership in other roles are included
+// as well. If a tx is provided, RetrieveFor will use it to execute the query.
+// Otherwise, it will execute against the underlying gorp.DB.
+func (s *Service) RetrieveFor(
+	ctx context.Context,
+	member ontology.ID,
+	transitive bool,
+	tx gorp.Tx,
+) ([]Role, error) {
+	tx = gorp.OverrideTx(s.DB, tx)
+	ids, err := s.RetrieveIDsFor(ctx, member, transitive, tx)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	keys, err := KeysFromOntologyIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]Role, 0, len(keys))
+	return roles, s.NewRetrieve().WhereKeys(keys...).Entries(&roles).Exec(ctx, tx)
+}
+
+// RetrieveIDsFor retrieves the ontology IDs of the roles that the given resource is a
+// member of, following the same semantics as RetrieveFor.
+func (s *Service) RetrieveIDsFor(
+	ctx context.Context,
+	member ontology.ID,
+	transitive bool,
+	tx gorp.Tx,
+) ([]ontology.ID, error) {
+	tx = gorp.OverrideTx(s.DB, tx)
+	var (
+		visited  = map[ontology.ID]struct{}{member: {}}
+		frontier = []ontology.ID{member}
+		ids      []ontology.ID
+	)
+	for len(frontier) > 0 {
+		var resources []ontology.Resource
+		if err := s.Ontology.NewRetrieve().
+			WhereIDs(frontier...).
+			TraverseTo(Roles).
+			Entries(&resources).
+			ExcludeFieldData(true).
+			Exec(ctx, tx); err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, r := range resources {
+			if _, ok := visited[r.ID]; ok {
+				continue
+			}
+			visited[r.ID] = struct{}{}
+			ids = append(ids, r.ID)
+			frontier = append(frontier, r.ID)
+		}
+		if !transitive {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// RetrieveMembers retrieves the ontology IDs of the direct members of the role with
+// the given key.
+func (s *Service) RetrieveMembers(
+	ctx context.Context,
+	key uuid.UUID,
+	tx gorp.Tx,
+) ([]ontology.ID, error) {
+	var resources []ontology.Resource
+	if err := s.Ontology.NewRetrieve().
+		WhereIDs(OntologyID(key)).
+		TraverseTo(Members).
+		Entries(&resources).
+		ExcludeFieldData(true).
+		Exec(ctx, gorp.OverrideTx(s.DB, tx)); err != nil {
+		return nil, err
+	}
+	ids := make([]ontology.ID, len(resources))
+	for i, r := range resources {
+		ids[i] = r.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package role
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
+	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/validate"
+)
+
+// Writer wraps a transaction to create, update, and delete roles and manage their
+// members.
+type Writer struct {
+	tx    gorp.Tx
+	otg   ontology.Writer
+	group group.Group
+}
+
+// Create creates a new role, assigning it a unique key if one is not provided. If a
+// role with the same key already exists, it will be overwritten.
+func (w Writer) Create(ctx context.Context, r *Role) error {
+	v := validate.New("role")
+	validate.NotEmptyString(v, "name", r.Name)
+	if err := v.Error(); err != nil {
+		return err
+	}
+	if r.Key == uuid.Nil {
+		r.Key = uuid.New()
+	}
+	if err := gorp.NewCreate[uuid.UUID, Role]().Entry(r).Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	otgID := OntologyID(r.Key)
+	if err := w.otg.DefineResource(ctx, otgID); err != nil {
+		return err
+	}
+	return w.otg.DefineRelationship(ctx, w.group.OntologyID(), ontology.ParentOf, otgID)
+}
+
+// CreateMany creates multiple roles. If any of the roles exist, they will be
+// overwritten.
+func (w Writer) CreateMany(ctx context.Context, roles *[]Role) error {
+	for i := range *roles {
+		if err := w.Create(ctx, &(*roles)[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes roles from the database and ontology, along with all of their
+// memberships. Delete is idempotent, and will not return an error if a role does not
+// exist.
+func (w Writer) Delete(ctx context.Context, keys ...uuid.UUID) error {
+	if err := gorp.NewDelete[uuid.UUID, Role]().WhereKeys(keys...).Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	return w.otg.DeleteManyResources(ctx, OntologyIDs(keys))
+}
+
+// AddMembers makes the given resources members of the role with the given key. Members
+// are typically users, but may also be other roles, in which case the members of the
+// member role inherit the policies of the role. Adding a role as a member of itself
+// or of one of its own members returns an ontology.ErrCycle.
+func (w Writer) AddMembers(ctx context.Context, key uuid.UUID, members ...ontology.ID) error {
+	roleID := OntologyID(key)
+	for _, m := range members {
+		if m == roleID {
+			return ontology.ErrCycle
+		}
+		if err := w.otg.DefineRelationship(ctx, m, MemberOf, roleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveMembers removes the given resources from the role with the given key.
+// RemoveMembers is idempotent, and will not return an error if a resource is not a
+// member of the role.
+func (w Writer) RemoveMembers(ctx context.Context, key uuid.UUID, members ...ontology.ID) error {
+	roleID := OntologyID(key)
+	for _, m := range members {
+		if err := w.otg.DeleteRelationship(ctx, m, MemberOf, roleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+// Package trash permanently deletes channels, ranges, workspaces, and schematics that
+// have been in the trash for longer than a configurable retention window.
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/distribution/core"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/schematic"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/signal"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// Config is the configuration for opening the trash service.
+type Config struct {
+	alamos.Instrumentation
+	// DB is the database that ranges, workspaces, and schematics are stored in.
+	// [REQUIRED]
+	DB *gorp.DB
+	// HostProvider is used to determine which entries this node is responsible for
+	// purging. Each node purges the channels it leases, and the bootstrapper purges
+	// free channels, ranges, workspaces, and schematics.
+	// [REQUIRED]
+	HostProvider core.HostProvider
+	// Channel is used to purge channels.
+	// [REQUIRED]
+	Channel channel.ReadWriteable
+	// Ranger is used to purge ranges.
+	// [REQUIRED]
+	Ranger *ranger.Service
+	// Workspace is used to purge workspaces.
+	// [REQUIRED]
+	Workspace *workspace.Service
+	// Schematic is used to purge schematics.
+	// [REQUIRED]
+	Schematic *schematic.Service
+	// Retention is how long an entry stays in the trash before it is purged.
+	// [OPTIONAL] - Defaults to 30 days.
+	Retention telem.TimeSpan
+	// PurgeInterval sets how often the service checks for entries to purge.
+	// [OPTIONAL] - Defaults to 1 hour.
+	PurgeInterval telem.TimeSpan
+}
+
+var (
+	_ config.Config[Config] = Config{}
+	// DefaultConfig is the default configuration for opening the trash service. This
+	// configuration is not valid on its own, and must be overridden by the required
+	// fields specified in Config.
+	DefaultConfig = Config{
+		Retention:     30 * telem.Day,
+		PurgeInterval: telem.Hour,
+	}
+)
+
+// Override implements config.Config.
+func (c Config) Override(other Config) Config {
+	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
+	c.DB = override.Nil(c.DB, other.DB)
+	c.HostProvider = override.Nil(c.HostProvider, other.HostProvider)
+	c.Channel = override.Nil(c.Channel, other.Channel)
+	c.Ranger = override.Nil(c.Ranger, other.Ranger)
+	c.Workspace = override.Nil(c.Workspace, other.Workspace)
+	c.Schematic = override.Nil(c.Schematic, other.Schematic)
+	c.Retention = override.Numeric(c.Retention, other.Retention)
+	c.PurgeInterval = override.Numeric(c.PurgeInterval, other.PurgeInterval)
+	return c
+}
+
+// Validate implements config.Config.
+func (c Config) Validate() error {
+	v := validate.New("trash")
+	validate.NotNil(v, "DB", c.DB)
+	validate.NotNil(v, "HostProvider", c.HostProvider)
+	validate.NotNil(v, "Channel", c.Channel)
+	validate.NotNil(v, "Ranger", c.Ranger)
+	validate.NotNil(v, "Workspace", c.Workspace)
+	validate.NotNil(v, "Schematic", c.Schematic)
+	validate.Positive(v, "Retention", c.Retention)
+	validate.Positive(v, "PurgeInterval", c.PurgeInterval)
+	return v.Error()
+}
+
+// Service periodically purges entries that have been in the trash for longer than the
+// configured retention window.
+type Service struct {
+	Config
+	shutdown context.CancelFunc
+	wg       signal.WaitGroup
+}
+
+// OpenService opens a new trash service using the provided configuration. If error is
+// nil, the service is purging entries in the background and must be closed by calling
+// Close.
+func OpenService(configs ...Config) (*Service, error) {
+	cfg, err := config.New(DefaultConfig, configs...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Config: cfg}
+	sCtx, cancel := signal.Isolated(signal.WithInstrumentation(cfg.Instrumentation))
+	s.shutdown = cancel
+	s.wg = sCtx
+	sCtx.Go(s.run, signal.WithKey("purge"), signal.RecoverWithErrOnPanic())
+	return s, nil
+}
+
+func (s *Service) run(ctx context.Context) error {
+	ticker := time.NewTicker(s.PurgeInterval.Duration())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.Purge(ctx); err != nil {
+				s.L.Error("failed to purge trash", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Purge permanently deletes the entries that this node is responsible for that have
+// been in the trash for longer than the retention window.
+func (s *Service) Purge(ctx context.Context) error {
+	cutoff := telem.Now().Sub(s.Retention)
+	c := errors.NewCatcher(errors.WithAggregation())
+	c.Exec(func() error { return s.purgeChannels(ctx, cutoff) })
+	if s.HostProvider.HostKey().IsBootstrapper() {
+		c.Exec(func() error { return s.purgeRanges(ctx, cutoff) })
+		c.Exec(func() error { return s.purgeSchematics(ctx, cutoff) })
+		c.Exec(func() error { return s.purgeWorkspaces(ctx, cutoff) })
+	}
+	return c.Error()
+}
+
+func (s *Service) purgeChannels(ctx context.Context, cutoff telem.TimeStamp) error {
+	var channels []channel.Channel
+	if err := s.Channel.NewRetrieve().
+		WhereDeletedBefore(cutoff).
+		Entries(&channels).
+		Exec(ctx, nil); err != nil {
+		return err
+	}
+	host := s.HostProvider.HostKey()
+	keys := lo.FilterMap(channels, func(ch channel.Channel, _ int) (channel.Key, bool) {
+		return ch.Key(), ch.Leaseholder == host || (ch.Key().Free() && host.IsBootstrapper())
+	})
+	if len(keys) == 0 {
+		return nil
+	}
+	s.L.Info("purging channels from trash", zap.Stringers("channels", keys))
+	return s.DB.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.Channel.NewWriter(tx).PurgeMany(ctx, keys, true)
+	})
+}
+
+func (s *Service) purgeRanges(ctx context.Context, cutoff telem.TimeStamp) error {
+	var ranges []ranger.Range
+	if err := s.Ranger.NewRetrieve().
+		WhereDeletedBefore(cutoff).
+		Entries(&ranges).
+		Exec(ctx, nil); err != nil {
+		return err
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	s.L.Info("purging ranges from trash", zap.Int("count", len(ranges)))
+	return s.DB.WithTx(ctx, func(tx gorp.Tx) error {
+		w := s.Ranger.NewWriter(tx)
+		for _, r := range ranges {
+			if err := w.Purge(ctx, r.Key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) purgeSchematics(ctx context.Context, cutoff telem.TimeStamp) error {
+	var schematics []schematic.Schematic
+	if err := s.Schematic.NewRetrieve().
+		WhereDeletedBefore(cutoff).
+		Entries(&schematics).
+		Exec(ctx, nil); err != nil {
+		return err
+	}
+	if len(schematics) == 0 {
+		return nil
+	}
+	s.L.Info("purging schematics from trash", zap.Int("count", len(schematics)))
+	keys := lo.Map(schematics, func(s schematic.Schematic, _ int) uuid.UUID { return s.Key })
+	return s.DB.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.Schematic.NewWriter(tx).Purge(ctx, keys...)
+	})
+}
+
+func (s *Service) purgeWorkspaces(ctx context.Context, cutoff telem.TimeStamp) error {
+	var workspaces []workspace.Workspace
+	if err := s.Workspace.NewRetrieve().
+		WhereDeletedBefore(cutoff).
+		Entries(&workspaces).
+		Exec(ctx, nil); err != nil {
+		return err
+	}
+	if len(workspaces) == 0 {
+		return nil
+	}
+	s.L.Info("purging workspaces from trash", zap.Int("count", len(workspaces)))
+	keys := lo.Map(workspaces, func(ws workspace.Workspace, _ int) uuid.UUID { return ws.Key })
+	return s.DB.WithTx(ctx, func(tx gorp.Tx) error {
+		return s.Workspace.NewWriter(tx).Purge(ctx, keys...)
+	})
+}
+
+// Close stops purging entries and waits for any in-progress purge to exit.
+func (s *Service) Close() error {
+	s.shutdown()
+	err := s.wg.Wait()
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package trash_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/synnax/pkg/distribution"
+	"github.com/synnaxlabs/synnax/pkg/distribution/mock"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/schematic"
+	"github.com/synnaxlabs/x/gorp"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var (
+	ctx          = context.Background()
+	_b           *mock.Builder
+	dist         distribution.Distribution
+	db           *gorp.DB
+	rangeSvc     *ranger.Service
+	workspaceSvc *workspace.Service
+	schematicSvc *schematic.Service
+	author       user.User
+)
+
+var _ = BeforeSuite(func() {
+	_b = mock.NewBuilder()
+	dist = _b.New(ctx)
+	db = dist.Storage.Gorpify()
+	rangeSvc = MustSucceed(ranger.OpenService(ctx, ranger.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	workspaceSvc = MustSucceed(workspace.NewService(ctx, workspace.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	schematicSvc = MustSucceed(schematic.NewService(schematic.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+	}))
+	userSvc := MustSucceed(user.NewService(ctx, user.Config{
+		DB:       db,
+		Ontology: dist.Ontology,
+		Group:    dist.Group,
+	}))
+	author.Username = "trash"
+	Expect(userSvc.NewWriter(nil).Create(ctx, &author)).To(Succeed())
+})
+
+var _ = AfterSuite(func() {
+	Expect(rangeSvc.Close()).To(Succeed())
+	Expect(_b.Close()).To(Succeed())
+	Expect(_b.Cleanup()).To(Succeed())
+})
+
+func TestTrash(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Trash Suite")
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package trash_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	"github.com/synnaxlabs/synnax/pkg/distribution/channel"
+	"github.com/synnaxlabs/synnax/pkg/service/ranger"
+	"github.com/synnaxlabs/synnax/pkg/service/trash"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace"
+	"github.com/synnaxlabs/synnax/pkg/service/workspace/schematic"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Trash", func() {
+	var (
+		idx, data channel.Channel
+		rng       ranger.Range
+		ws        workspace.Workspace
+		sch       schematic.Schematic
+	)
+	BeforeEach(func() {
+		idx = channel.Channel{Name: "time", DataType: telem.TimeStampT, IsIndex: true}
+		Expect(dist.Channel.Create(ctx, &idx)).To(Succeed())
+		data = channel.Channel{Name: "pressure", DataType: telem.Float64T, LocalIndex: idx.LocalKey}
+		Expect(dist.Channel.Create(ctx, &data)).To(Succeed())
+		rng = ranger.Range{Name: "test", TimeRange: telem.TimeRange{Start: telem.SecondTS, End: 2 * telem.SecondTS}}
+		Expect(rangeSvc.NewWriter(db).Create(ctx, &rng)).To(Succeed())
+		ws = workspace.Workspace{Name: "test", Author: author.Key}
+		Expect(workspaceSvc.NewWriter(db).Create(ctx, &ws)).To(Succeed())
+		sch = schematic.Schematic{Name: "test"}
+		Expect(schematicSvc.NewWriter(db).Create(ctx, ws.Key, &sch)).To(Succeed())
+
+		Expect(dist.Channel.DeleteMany(ctx, channel.Keys{idx.Key(), data.Key()}, false)).To(Succeed())
+		Expect(rangeSvc.NewWriter(db).Delete(ctx, rng.Key)).To(Succeed())
+		Expect(schematicSvc.NewWriter(db).Delete(ctx, sch.Key)).To(Succeed())
+		Expect(workspaceSvc.NewWriter(db).Delete(ctx, ws.Key)).To(Succeed())
+	})
+	open := func(cfg trash.Config) *trash.Service {
+		cfg.DB = db
+		cfg.HostProvider = dist.Cluster
+		cfg.Channel = dist.Channel
+		cfg.Ranger = rangeSvc
+		cfg.Workspace = workspaceSvc
+		cfg.Schematic = schematicSvc
+		svc := MustSucceed(trash.OpenService(cfg))
+		DeferCleanup(func() { Expect(svc.Close()).To(Succeed()) })
+		return svc
+	}
+	expectPurged := func(g Gomega, purged bool) {
+		matchPurged := func(err error) {
+			if purged {
+				g.Expect(err).To(HaveOccurredAs(query.NotFound))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		}
+		var ch channel.Channel
+		matchPurged(dist.Channel.NewRetrieve().WhereKeys(data.Key()).IncludeDeleted(true).Entry(&ch).Exec(ctx, nil))
+		var r ranger.Range
+		matchPurged(rangeSvc.NewRetrieve().WhereKeys(rng.Key).IncludeDeleted(true).Entry(&r).Exec(ctx, nil))
+		var w workspace.Workspace
+		matchPurged(workspaceSvc.NewRetrieve().WhereKeys(ws.Key).IncludeDeleted(true).Entry(&w).Exec(ctx, nil))
+		var s schematic.Schematic
+		matchPurged(schematicSvc.NewRetrieve().WhereKeys(sch.Key).IncludeDeleted(true).Entry(&s).Exec(ctx, nil))
+	}
+
+	It("Should purge entries that have been in the trash longer than the retention window", func() {
+		svc := open(trash.Config{Retention: telem.Nanosecond})
+		Expect(svc.Purge(ctx)).To(Succeed())
+		expectPurged(Default, true)
+		_, err := dist.Storage.TS.RetrieveChannels(ctx, data.Key().StorageKey(), idx.Key().StorageKey())
+		Expect(err).To(MatchError(cesium.ErrChannelNotFound))
+	})
+
+	It("Should not purge entries within the retention window", func() {
+		svc := open(trash.Config{Retention: telem.Hour})
+		Expect(svc.Purge(ctx)).To(Succeed())
+		expectPurged(Default, false)
+		Expect(dist.Storage.TS.RetrieveChannels(ctx, data.Key().StorageKey())).To(HaveLen(1))
+	})
+
+	It("Should purge entries in the background", func() {
+		open(trash.Config{Retention: telem.Nanosecond, PurgeInterval: 5 * telem.Millisecond})
+		Eventually(func(g Gomega) { expectPurged(g, true) }).
+			WithTimeout(2 * time.Second).
+			Should(Succeed())
+	})
+
+	It("Should not accept an invalid retention window", func() {
+		_, err := trash.OpenService(trash.Config{
+			DB:           db,
+			HostProvider: dist.Cluster,
+			Channel:      dist.Channel,
+			Ranger:       rangeSvc,
+			Workspace:    workspaceSvc,
+			Schematic:    schematicSvc,
+			Retention:    -telem.Hour,
+		})
+		Expect(err).To(MatchError(ContainSubstring("Retention")))
+	})
+})
@@ -57,6 +57,15 @@ func (r Retrieve) WhereUsernames(usernames ...string) Retrieve {
 	return r
 }
 
+// WhereIdentity filters the query to only include the user linked to the given
+// identity provider account.
+func (r Retrieve) WhereIdentity(identity Identity) Retrieve {
+	r.gorp = r.gorp.Where(func(u *User) bool {
+		return !identity.IsZero() && u.Identity == identity
+	})
+	return r
+}
+
 // Exec executes the query.
 func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
 	return r.gorp.Exec(ctx, gorp.OverrideTx(r.baseTX, tx))
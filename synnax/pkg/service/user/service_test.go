@@ -162,6 +162,24 @@ var _ = Describe("User", Ordered, func() {
 			users[0].FirstName = newFirstName
 		})
 	})
+	Describe("LinkIdentity", func() {
+		identity := user.Identity{Issuer: "https://idp.example.com", Subject: "spongebob"}
+		It("Should link a user to an identity provider account", func() {
+			Expect(w.LinkIdentity(ctx, users[0].Key, identity)).To(Succeed())
+			var u user.User
+			Expect(svc.NewRetrieve().WhereIdentity(identity).Entry(&u).Exec(ctx, nil)).To(Succeed())
+			Expect(u.Key).To(Equal(users[0].Key))
+		})
+		It("Should not link an account that is linked to another user", func() {
+			Expect(w.LinkIdentity(ctx, users[1].Key, identity)).
+				To(MatchError(ContainSubstring("already linked")))
+		})
+		It("Should not match users without an identity", func() {
+			var u user.User
+			Expect(svc.NewRetrieve().WhereIdentity(user.Identity{}).Entry(&u).Exec(ctx, nil)).
+				To(HaveOccurred())
+		})
+	})
 	Describe("Delete", func() {
 		It("Should delete a single user", func() {
 			Expect(w.Delete(ctx, users[0].Key)).To(Succeed())
@@ -32,8 +32,23 @@ type User struct {
 	// RootUser is a boolean that determines if the user is a root user. Root users are
 	// the users that configure the Synnax server, and have full access to the server.
 	RootUser bool `json:"root_user" msgpack:"root_user"`
+	// Identity links the user to an account with an external identity provider. It is
+	// zero for users that do not log in through an identity provider.
+	Identity Identity `json:"identity" msgpack:"identity"`
 }
 
+// Identity identifies an account with an external OpenID Connect identity provider.
+// Unlike usernames, the issuer and subject of an account never change.
+type Identity struct {
+	// Issuer is the URL of the identity provider.
+	Issuer string `json:"issuer" msgpack:"issuer"`
+	// Subject is the identifier of the account within the identity provider.
+	Subject string `json:"subject" msgpack:"subject"`
+}
+
+// IsZero returns true if the identity does not refer to any account.
+func (i Identity) IsZero() bool { return i == Identity{} }
+
 var _ gorp.Entry[uuid.UUID] = User{}
 
 // GorpKey implements gorp.Entry.
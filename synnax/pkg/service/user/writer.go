@@ -85,6 +85,34 @@ func (w Writer) ChangeName(ctx context.Context, key uuid.UUID, first string, las
 	}).Exec(ctx, w.tx)
 }
 
+// LinkIdentity links the user with the given key to an account with an external
+// identity provider, allowing the user to log in through it. Users are never linked to
+// an identity provider implicitly, so an administrator must link existing users before
+// they can log in with an ID token. Returns an error if the user is the root user or
+// the identity is already linked to another user.
+func (w Writer) LinkIdentity(ctx context.Context, key uuid.UUID, identity Identity) error {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return errors.New("identity must have an issuer and subject")
+	}
+	var linked []User
+	if err := w.svc.NewRetrieve().
+		WhereIdentity(identity).
+		Entries(&linked).
+		Exec(ctx, w.tx); err != nil {
+		return err
+	}
+	if len(linked) > 0 && linked[0].Key != key {
+		return errors.Newf("identity is already linked to user %s", linked[0].Username)
+	}
+	return gorp.NewUpdate[uuid.UUID, User]().WhereKeys(key).ChangeErr(func(u User) (User, error) {
+		if u.RootUser {
+			return u, errors.New("cannot link the root user to an identity provider")
+		}
+		u.Identity = identity
+		return u, nil
+	}).Exec(ctx, w.tx)
+}
+
 // Delete removes the users with the given keys keys from the key-value store.
 func (w Writer) Delete(
 	ctx context.Context,
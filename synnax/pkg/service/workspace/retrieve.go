@@ -15,13 +15,15 @@ import (
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/search"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
 )
 
 type Retrieve struct {
 	baseTX     gorp.Tx
 	otg        *ontology.Ontology
 	gorp       gorp.Retrieve[uuid.UUID, Workspace]
-	searchTerm string
+	searchTerm     string
+	includeDeleted bool
 }
 
 func (r Retrieve) Search(term string) Retrieve {
@@ -51,6 +53,23 @@ func (r Retrieve) WhereAuthor(author uuid.UUID) Retrieve {
 	return r
 }
 
+// IncludeDeleted includes workspaces in the trash in the results of the query if
+// includeDeleted is true. Workspaces in the trash are excluded by default.
+func (r Retrieve) IncludeDeleted(includeDeleted bool) Retrieve {
+	r.includeDeleted = includeDeleted
+	return r
+}
+
+// WhereDeletedBefore filters for workspaces that were moved to the trash before the
+// provided time. WhereDeletedBefore implies IncludeDeleted(true).
+func (r Retrieve) WhereDeletedBefore(ts telem.TimeStamp) Retrieve {
+	r.includeDeleted = true
+	r.gorp = r.gorp.Where(func(ws *Workspace) bool {
+		return ws.Trashed() && ws.DeletedAt < ts
+	}, gorp.Guard())
+	return r
+}
+
 func (r Retrieve) Limit(limit int) Retrieve {
 	r.gorp = r.gorp.Limit(limit)
 	return r
@@ -76,5 +95,8 @@ func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
 		}
 		r = r.WhereKeys(keys...)
 	}
+	if !r.includeDeleted {
+		r.gorp = r.gorp.Where(func(ws *Workspace) bool { return !ws.Trashed() }, gorp.Guard())
+	}
 	return r.gorp.Exec(ctx, gorp.OverrideTx(r.baseTX, tx))
 }
@@ -13,14 +13,16 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
 )
 
 // Retrieve is a query builder for retrieving schematics. It should not be instantiated
 // directly, and should instead be instantiated via the NewRetrieve method on
 // schematic.Service.
 type Retrieve struct {
-	baseTX gorp.Tx
-	gorp   gorp.Retrieve[uuid.UUID, Schematic]
+	baseTX         gorp.Tx
+	gorp           gorp.Retrieve[uuid.UUID, Schematic]
+	includeDeleted bool
 }
 
 // WhereKeys filters the schematics by the given keys.
@@ -47,6 +49,26 @@ func (r Retrieve) Entries(schematics *[]Schematic) Retrieve {
 // will be stored in the pointer given to the Entry or Entries method. If tx is nil,
 // the query will be executed directly against the underlying gorp.DB provided to the
 // schematic service.
+// IncludeDeleted includes schematics in the trash in the results of the query if
+// includeDeleted is true. Schematics in the trash are excluded by default.
+func (r Retrieve) IncludeDeleted(includeDeleted bool) Retrieve {
+	r.includeDeleted = includeDeleted
+	return r
+}
+
+// WhereDeletedBefore filters for schematics that were moved to the trash before the
+// provided time. WhereDeletedBefore implies IncludeDeleted(true).
+func (r Retrieve) WhereDeletedBefore(ts telem.TimeStamp) Retrieve {
+	r.includeDeleted = true
+	r.gorp = r.gorp.Where(func(s *Schematic) bool {
+		return s.Trashed() && s.DeletedAt < ts
+	}, gorp.Guard())
+	return r
+}
+
 func (r Retrieve) Exec(ctx context.Context, tx gorp.Tx) error {
+	if !r.includeDeleted {
+		r.gorp = r.gorp.Where(func(s *Schematic) bool { return !s.Trashed() }, gorp.Guard())
+	}
 	return r.gorp.Exec(ctx, gorp.OverrideTx(r.baseTX, tx))
 }
@@ -12,6 +12,7 @@ package schematic
 import (
 	"github.com/google/uuid"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
 )
 
 // Schematic is the data for a visualization used to view and operate a schematic of a
@@ -25,8 +26,14 @@ type Schematic struct {
 	Name string `json:"name" msgpack:"name"`
 	// Data is JSON-encoded data for the schematic.
 	Data string `json:"data" msgpack:"data"`
+	// DeletedAt is the time the schematic was moved to the trash. Zero if the schematic
+	// is not in the trash.
+	DeletedAt telem.TimeStamp `json:"deleted_at" msgpack:"deleted_at"`
 }
 
+// Trashed returns true if the schematic is in the trash.
+func (s Schematic) Trashed() bool { return s.DeletedAt != 0 }
+
 var _ gorp.Entry[uuid.UUID] = Schematic{}
 
 // GorpKey implements gorp.Entry.
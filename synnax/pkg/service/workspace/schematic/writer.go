@@ -13,10 +13,13 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/synnax/pkg/service/workspace"
 	"github.com/synnaxlabs/x/errors"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
 	"github.com/synnaxlabs/x/validate"
 )
 
@@ -99,6 +102,7 @@ func (w Writer) Copy(
 		s.Key = newKey
 		s.Name = name
 		s.Snapshot = snapshot
+		s.DeletedAt = 0
 		*result = s
 		return s
 	}).Exec(ctx, w.tx); err != nil {
@@ -139,9 +143,48 @@ func (w Writer) SetData(
 }
 
 // Delete deletes the logs with the given keys.
+// Delete moves the schematics with the given keys to the trash. The schematics can be
+// restored by calling Restore until they are purged.
 func (w Writer) Delete(
 	ctx context.Context,
 	keys ...uuid.UUID,
+) error {
+	var existing []Schematic
+	if err := gorp.NewRetrieve[uuid.UUID, Schematic]().
+		WhereKeys(keys...).
+		Entries(&existing).
+		Exec(ctx, w.tx); err != nil && !errors.Is(err, query.NotFound) {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	keys = lo.Map(existing, func(s Schematic, _ int) uuid.UUID { return s.Key })
+	now := telem.Now()
+	return gorp.NewUpdate[uuid.UUID, Schematic]().WhereKeys(keys...).Change(func(s Schematic) Schematic {
+		if !s.Trashed() {
+			s.DeletedAt = now
+		}
+		return s
+	}).Exec(ctx, w.tx)
+}
+
+// Restore moves the schematics with the given keys out of the trash.
+func (w Writer) Restore(
+	ctx context.Context,
+	keys ...uuid.UUID,
+) error {
+	return gorp.NewUpdate[uuid.UUID, Schematic]().WhereKeys(keys...).Change(func(s Schematic) Schematic {
+		s.DeletedAt = 0
+		return s
+	}).Exec(ctx, w.tx)
+}
+
+// Purge permanently deletes the schematics with the given keys, regardless of whether
+// they are in the trash.
+func (w Writer) Purge(
+	ctx context.Context,
+	keys ...uuid.UUID,
 ) error {
 	err := gorp.NewDelete[uuid.UUID, Schematic]().WhereKeys(keys...).Exec(ctx, w.tx)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/synnax/pkg/service/workspace/schematic"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/query"
 	. "github.com/synnaxlabs/x/testutil"
 	"github.com/synnaxlabs/x/validate"
 )
@@ -74,4 +75,32 @@ var _ = Describe("Writer", func() {
 			Expect(svc.NewWriter(tx).SetData(ctx, cpy.Key, "data2")).To(HaveOccurredAs(validate.Error))
 		})
 	})
+	Describe("Delete", func() {
+		It("Should move a Schematic to the trash", func() {
+			s := schematic.Schematic{Name: "test", Data: "data"}
+			Expect(svc.NewWriter(tx).Create(ctx, ws.Key, &s)).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, s.Key)).To(Succeed())
+			var res schematic.Schematic
+			Expect(svc.NewRetrieve().WhereKeys(s.Key).Entry(&res).Exec(ctx, tx)).To(HaveOccurredAs(query.NotFound))
+			Expect(svc.NewRetrieve().WhereKeys(s.Key).IncludeDeleted(true).Entry(&res).Exec(ctx, tx)).To(Succeed())
+			Expect(res.Trashed()).To(BeTrue())
+		})
+		It("Should restore a deleted Schematic", func() {
+			s := schematic.Schematic{Name: "test", Data: "data"}
+			Expect(svc.NewWriter(tx).Create(ctx, ws.Key, &s)).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, s.Key)).To(Succeed())
+			Expect(svc.NewWriter(tx).Restore(ctx, s.Key)).To(Succeed())
+			var res schematic.Schematic
+			Expect(svc.NewRetrieve().WhereKeys(s.Key).Entry(&res).Exec(ctx, tx)).To(Succeed())
+			Expect(res.Trashed()).To(BeFalse())
+		})
+		It("Should permanently delete a Schematic when purged", func() {
+			s := schematic.Schematic{Name: "test", Data: "data"}
+			Expect(svc.NewWriter(tx).Create(ctx, ws.Key, &s)).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, s.Key)).To(Succeed())
+			Expect(svc.NewWriter(tx).Purge(ctx, s.Key)).To(Succeed())
+			var res schematic.Schematic
+			Expect(svc.NewRetrieve().WhereKeys(s.Key).IncludeDeleted(true).Entry(&res).Exec(ctx, tx)).To(HaveOccurredAs(query.NotFound))
+		})
+	})
 })
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/telem"
 )
 
 type Workspace struct {
@@ -20,8 +21,14 @@ type Workspace struct {
 	Name   string    `json:"name" msgpack:"name"`
 	Author uuid.UUID `json:"author" msgpack:"author"`
 	Layout string    `json:"layout" msgpack:"layout"`
+	// DeletedAt is the time the workspace was moved to the trash. Zero if the workspace
+	// is not in the trash.
+	DeletedAt telem.TimeStamp `json:"deleted_at" msgpack:"deleted_at"`
 }
 
+// Trashed returns true if the workspace is in the trash.
+func (w Workspace) Trashed() bool { return w.DeletedAt != 0 }
+
 var _ gorp.Entry[uuid.UUID] = Workspace{}
 
 // GorpKey implements gorp.Entry.
@@ -12,10 +12,14 @@ package workspace
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology"
 	"github.com/synnaxlabs/synnax/pkg/distribution/ontology/group"
 	"github.com/synnaxlabs/synnax/pkg/service/user"
+	"github.com/synnaxlabs/x/errors"
 	"github.com/synnaxlabs/x/gorp"
+	"github.com/synnaxlabs/x/query"
+	"github.com/synnaxlabs/x/telem"
 )
 
 type Writer struct {
@@ -79,9 +83,48 @@ func (w Writer) SetLayout(
 	}).Exec(ctx, w.tx)
 }
 
+// Delete moves the workspaces with the given keys to the trash. The workspaces can be
+// restored by calling Restore until they are purged.
 func (w Writer) Delete(
 	ctx context.Context,
 	keys ...uuid.UUID,
+) error {
+	var existing []Workspace
+	if err := gorp.NewRetrieve[uuid.UUID, Workspace]().
+		WhereKeys(keys...).
+		Entries(&existing).
+		Exec(ctx, w.tx); err != nil && !errors.Is(err, query.NotFound) {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	keys = lo.Map(existing, func(ws Workspace, _ int) uuid.UUID { return ws.Key })
+	now := telem.Now()
+	return gorp.NewUpdate[uuid.UUID, Workspace]().WhereKeys(keys...).Change(func(ws Workspace) Workspace {
+		if !ws.Trashed() {
+			ws.DeletedAt = now
+		}
+		return ws
+	}).Exec(ctx, w.tx)
+}
+
+// Restore moves the workspaces with the given keys out of the trash.
+func (w Writer) Restore(
+	ctx context.Context,
+	keys ...uuid.UUID,
+) error {
+	return gorp.NewUpdate[uuid.UUID, Workspace]().WhereKeys(keys...).Change(func(ws Workspace) Workspace {
+		ws.DeletedAt = 0
+		return ws
+	}).Exec(ctx, w.tx)
+}
+
+// Purge permanently deletes the workspaces with the given keys, regardless of whether
+// they are in the trash.
+func (w Writer) Purge(
+	ctx context.Context,
+	keys ...uuid.UUID,
 ) error {
 	if err := gorp.NewDelete[uuid.UUID, Workspace]().WhereKeys(keys...).Exec(ctx, w.tx); err != nil {
 		return err
@@ -55,7 +55,34 @@ var _ = Describe("Writer", func() {
 			Expect(svc.NewWriter(tx).Create(ctx, &ws)).To(Succeed())
 			Expect(svc.NewWriter(tx).Delete(ctx, ws.Key)).To(Succeed())
 			var res workspace.Workspace
+			Expect(svc.NewRetrieve().WhereKeys(ws.Key).Entry(&res).Exec(ctx, tx)).ToNot(Succeed())
+		})
+		It("Should retrieve a deleted workspace when deleted workspaces are included", func() {
+			ws := workspace.Workspace{Name: "test", Author: author.Key}
+			Expect(svc.NewWriter(tx).Create(ctx, &ws)).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, ws.Key)).To(Succeed())
+			var res workspace.Workspace
+			Expect(svc.NewRetrieve().WhereKeys(ws.Key).IncludeDeleted(true).Entry(&res).Exec(ctx, tx)).To(Succeed())
+			Expect(res.Trashed()).To(BeTrue())
+		})
+		It("Should restore a deleted workspace", func() {
+			ws := workspace.Workspace{Name: "test", Author: author.Key}
+			Expect(svc.NewWriter(tx).Create(ctx, &ws)).To(Succeed())
+			Expect(svc.NewWriter(tx).Delete(ctx, ws.Key)).To(Succeed())
+			Expect(svc.NewWriter(tx).Restore(ctx, ws.Key)).To(Succeed())
+			var res workspace.Workspace
+			Expect(svc.NewRetrieve().WhereKeys(ws.Key).Entry(&res).Exec(ctx, tx)).To(Succeed())
+			Expect(res.Trashed()).To(BeFalse())
+		})
+		It("Should permanently delete a workspace when purged", func() {
+			ws := workspace.Workspace{Name: "test", Author: author.Key}
+			Expect(svc.NewWriter(tx).Create(ctx, &ws)).To(Succeed())
+			Expect(svc.NewWriter(tx).Purge(ctx, ws.Key)).To(Succeed())
+			var res workspace.Workspace
 			Expect(gorp.NewRetrieve[uuid.UUID, workspace.Workspace]().WhereKeys(ws.Key).Entry(&res).Exec(ctx, tx)).ToNot(Succeed())
 		})
+		It("Should not return an error when deleting a workspace that does not exist", func() {
+			Expect(svc.NewWriter(tx).Delete(ctx, uuid.New())).To(Succeed())
+		})
 	})
 })
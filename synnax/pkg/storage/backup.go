@@ -0,0 +1,329 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/kv/pebblekv"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// manifestFileName is the name of the file at the start of every backup archive that
+// describes its contents.
+const manifestFileName = "manifest.json"
+
+// BackupManifest describes the contents of a backup archive.
+type BackupManifest struct {
+	// ClusterKey is the key of the cluster the node that produced the backup belongs to.
+	ClusterKey uuid.UUID `json:"cluster_key"`
+	// Version is the version of Synnax that produced the backup.
+	Version string `json:"version"`
+	// CreatedAt is the time at which the backup was started.
+	CreatedAt telem.TimeStamp `json:"created_at"`
+	// KVEngine is the key-value engine the backup was taken from.
+	KVEngine KVEngine `json:"kv_engine"`
+	// TSEngine is the time-series engine the backup was taken from.
+	TSEngine TSEngine `json:"ts_engine"`
+	// Encrypted is true if the storage the backup was taken from was encrypted. The
+	// files in the archive of an encrypted backup remain encrypted with the same key.
+	Encrypted bool `json:"encrypted"`
+	// KeyID identifies the key the backup is encrypted with, and is empty if the backup
+	// is not encrypted. See xfs.Keyring.ID.
+	KeyID string `json:"key_id,omitempty"`
+}
+
+// Backup writes a consistent, point-in-time copy of the key-value and time-series
+// engines to the provided writer as a gzipped tar archive. The archive starts with a
+// JSON encoded version of the provided manifest, and can be rehydrated into a fresh
+// data directory using Restore.
+//
+// Backup is safe to call while the storage engines are being written to. The key-value
+// store is captured using a pebble checkpoint, and the time-series engine is captured
+// using a cesium snapshot. Both are captured under a single barrier: writes to the
+// time-series engine, as well as channel creation and deletion, are blocked while the
+// key-value checkpoint is taken, so the two engines reflect the same point in time.
+// Channels are created in the time-series engine before their metadata is committed to
+// the key-value store, so the backup may contain storage directories for channels that
+// have no metadata, which are harmless and ignored by the cluster, but never the
+// reverse. Backup requires scratch space in the storage directory equal to the size of
+// the copy.
+func (s *Storage) Backup(ctx context.Context, w io.Writer, m BackupManifest) (err error) {
+	ctx, span := s.T.Prod(ctx, "backup")
+	defer func() { err = span.EndWith(err) }()
+	if *s.MemBacked {
+		return errors.New("[storage] - cannot back up memory-backed storage")
+	}
+	m.KVEngine, m.TSEngine = s.KVEngine, s.TSEngine
+	if m.Encrypted = s.keys != nil; m.Encrypted {
+		m.KeyID = s.keys.ID()
+	}
+	if m.CreatedAt == 0 {
+		m.CreatedAt = telem.Now()
+	}
+
+	tmp, err := os.MkdirTemp(s.Dirname, "backup-")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.CombineErrors(err, os.RemoveAll(tmp)) }()
+
+	s.L.Info("starting backup", zap.String("scratch", tmp))
+	tsFS, err := xfs.Default.Sub(filepath.Join(tmp, cesiumDirname))
+	if err != nil {
+		return err
+	}
+	// Keep the time-series data encrypted in the archive, as the key-value checkpoint
+	// is written through the encrypted file system of the engine.
+	if s.keys != nil {
+		tsFS = xfs.NewEncrypted(tsFS, s.keys)
+	}
+	checkpointKV := func() error {
+		if err := pebblekv.Checkpoint(s.engineKV, filepath.Join(tmp, kvDirname)); err != nil {
+			return errors.Wrap(err, "[storage] - failed to checkpoint key-value store")
+		}
+		return nil
+	}
+	if err = s.TS.SnapshotWithBarrier(ctx, tsFS, checkpointKV); err != nil {
+		return errors.Wrap(err, "[storage] - failed to snapshot time-series engine")
+	}
+	if err = writeArchive(w, tmp, m); err != nil {
+		return err
+	}
+	s.L.Info("backup complete")
+	return nil
+}
+
+func writeArchive(w io.Writer, dir string, m BackupManifest) (err error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	defer func() {
+		err = errors.CombineErrors(err, tw.Close())
+		err = errors.CombineErrors(err, gw.Close())
+	}()
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err = tw.WriteHeader(&tar.Header{
+		Name:    manifestFileName,
+		Mode:    int64(xfs.OS_USER_RW),
+		Size:    int64(len(b)),
+		ModTime: m.CreatedAt.Time(),
+	}); err != nil {
+		return err
+	}
+	if _, err = tw.Write(b); err != nil {
+		return err
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err = tw.WriteHeader(hdr); err != nil || d.IsDir() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		return errors.CombineErrors(err, f.Close())
+	})
+}
+
+// RestoreConfig is the configuration for restoring a backup using Restore.
+type RestoreConfig struct {
+	// Dirname is the storage directory to restore the backup into. The directory must
+	// either not exist or be empty.
+	// [REQUIRED]
+	Dirname string
+	// Perm is the file permissions to use for the storage directory.
+	// [OPTIONAL] - Defaults to xfs.OS_USER_RWX
+	Perm fs.FileMode
+	// ClusterKey is the key of the cluster the backup is expected to belong to. If set,
+	// Restore will return an error if the key in the backup manifest does not match.
+	// [OPTIONAL]
+	ClusterKey *uuid.UUID
+	// EncryptionKey is the key the backup was encrypted with. It must be provided if,
+	// and only if, the backup is encrypted, and Restore will return an error before
+	// restoring any files if it does not match the key recorded in the manifest. The
+	// restored files are not re-encrypted.
+	// [OPTIONAL]
+	EncryptionKey []byte
+}
+
+var (
+	_ config.Config[RestoreConfig] = RestoreConfig{}
+	// DefaultRestoreConfig is the default configuration for Restore.
+	DefaultRestoreConfig = RestoreConfig{Perm: xfs.OS_USER_RWX}
+)
+
+// Override implements config.Config.
+func (cfg RestoreConfig) Override(other RestoreConfig) RestoreConfig {
+	cfg.Dirname = override.String(cfg.Dirname, other.Dirname)
+	cfg.Perm = override.Numeric(cfg.Perm, other.Perm)
+	cfg.ClusterKey = override.Nil(cfg.ClusterKey, other.ClusterKey)
+	cfg.EncryptionKey = override.Slice(cfg.EncryptionKey, other.EncryptionKey)
+	return cfg
+}
+
+// Validate implements config.Config.
+func (cfg RestoreConfig) Validate() error {
+	v := validate.New("storage.restore")
+	validate.NotEmptyString(v, "dirname", cfg.Dirname)
+	v.Ternary("permissions", cfg.Perm == 0, "insufficient permission bits on directory")
+	return v.Error()
+}
+
+// Restore rehydrates a backup archive produced by Storage.Backup into the directory
+// specified in the provided configuration, returning the manifest of the restored
+// backup. The restored directory can be opened using Open.
+func Restore(r io.Reader, cfgs ...RestoreConfig) (BackupManifest, error) {
+	var m BackupManifest
+	cfg, err := config.New(DefaultRestoreConfig, cfgs...)
+	if err != nil {
+		return m, err
+	}
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return m, errors.Wrap(err, "[storage] - invalid backup archive")
+	}
+	tr := tar.NewReader(gr)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestFileName {
+		return m, errors.New("[storage] - backup archive is missing a manifest")
+	}
+	if err = json.NewDecoder(tr).Decode(&m); err != nil {
+		return m, errors.Wrap(err, "[storage] - failed to decode backup manifest")
+	}
+	if cfg.ClusterKey != nil && *cfg.ClusterKey != m.ClusterKey {
+		return m, errors.Newf(
+			"[storage] - backup belongs to cluster %s, expected cluster %s",
+			m.ClusterKey,
+			*cfg.ClusterKey,
+		)
+	}
+	if err = checkEncryptionKey(cfg, m); err != nil {
+		return m, err
+	}
+	if err = ensureEmptyDir(cfg); err != nil {
+		return m, err
+	}
+	for {
+		hdr, err = tr.Next()
+		if errors.Is(err, io.EOF) {
+			return m, nil
+		}
+		if err != nil {
+			return m, err
+		}
+		if err = restoreEntry(cfg, tr, hdr); err != nil {
+			return m, err
+		}
+	}
+}
+
+// checkEncryptionKey returns an error if the encryption key in the provided config
+// cannot be used to open storage restored from a backup with the given manifest.
+func checkEncryptionKey(cfg RestoreConfig, m BackupManifest) error {
+	if !m.Encrypted {
+		if cfg.EncryptionKey != nil {
+			return errors.New("[storage] - backup is not encrypted, but an encryption key was provided")
+		}
+		return nil
+	}
+	if cfg.EncryptionKey == nil {
+		return errors.New("[storage] - backup is encrypted, and the key it was encrypted with must be provided")
+	}
+	keys, err := xfs.NewKeyring(cfg.EncryptionKey)
+	if err != nil {
+		return err
+	}
+	if keys.ID() != m.KeyID {
+		return errors.New("[storage] - backup was encrypted with a different key than the one provided")
+	}
+	return nil
+}
+
+func ensureEmptyDir(cfg RestoreConfig) error {
+	if err := os.MkdirAll(cfg.Dirname, cfg.Perm); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(cfg.Dirname)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return errors.Newf(
+			"[storage] - cannot restore into non-empty directory %s",
+			cfg.Dirname,
+		)
+	}
+	return nil
+}
+
+func restoreEntry(cfg RestoreConfig, r io.Reader, hdr *tar.Header) error {
+	if !filepath.IsLocal(hdr.Name) {
+		return errors.Newf("[storage] - invalid path %s in backup archive", hdr.Name)
+	}
+	path := filepath.Join(cfg.Dirname, filepath.FromSlash(hdr.Name))
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(path, cfg.Perm)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(path), cfg.Perm); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(
+			path,
+			os.O_CREATE|os.O_WRONLY|os.O_EXCL,
+			fs.FileMode(hdr.Mode).Perm(),
+		)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, r)
+		return errors.CombineErrors(err, f.Close())
+	default:
+		return errors.Newf(
+			"[storage] - unsupported entry %s of type %v in backup archive",
+			hdr.Name,
+			hdr.Typeflag,
+		)
+	}
+}
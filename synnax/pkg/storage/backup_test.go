@@ -0,0 +1,120 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	"github.com/synnaxlabs/synnax/pkg/storage"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Backup", func() {
+	var (
+		ctx        = context.Background()
+		tempDir    string
+		store      *storage.Storage
+		clusterKey uuid.UUID
+		manifest   storage.BackupManifest
+	)
+	BeforeEach(func() {
+		tempDir = MustSucceed(os.MkdirTemp("", "synnax-backup-test"))
+		store = MustSucceed(storage.Open(storage.Config{
+			Dirname:   filepath.Join(tempDir, "storage"),
+			MemBacked: config.False(),
+		}))
+		clusterKey = uuid.New()
+		manifest = storage.BackupManifest{ClusterKey: clusterKey, Version: "0.0.0"}
+	})
+	AfterEach(func() {
+		Expect(store.Close()).To(Succeed())
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+	It("Should restore a backup of the key-value and time-series engines", func() {
+		Expect(store.KV.Set(ctx, []byte("key"), []byte("value"))).To(Succeed())
+		Expect(store.TS.CreateChannel(ctx, cesium.Channel{
+			Key:      1,
+			IsIndex:  true,
+			DataType: telem.TimeStampT,
+		})).To(Succeed())
+		Expect(store.TS.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+			[]cesium.ChannelKey{1},
+			[]telem.Series{telem.NewSecondsTSV(10, 11, 12)},
+		))).To(Succeed())
+
+		buf := &bytes.Buffer{}
+		Expect(store.Backup(ctx, buf, manifest)).To(Succeed())
+
+		By("Writing more data after the backup")
+		Expect(store.KV.Set(ctx, []byte("key"), []byte("new-value"))).To(Succeed())
+
+		By("Restoring the backup into a new directory")
+		dirname := filepath.Join(tempDir, "restored")
+		m := MustSucceed(storage.Restore(buf, storage.RestoreConfig{
+			Dirname:    dirname,
+			ClusterKey: &clusterKey,
+		}))
+		Expect(m.ClusterKey).To(Equal(clusterKey))
+		Expect(m.Version).To(Equal("0.0.0"))
+		Expect(m.KVEngine).To(Equal(storage.PebbleKV))
+		Expect(m.Encrypted).To(BeFalse())
+
+		restored := MustSucceed(storage.Open(storage.Config{
+			Dirname:   dirname,
+			MemBacked: config.False(),
+		}))
+		v, closer := MustSucceed2(restored.KV.Get(ctx, []byte("key")))
+		Expect(v).To(Equal([]byte("value")))
+		Expect(closer.Close()).To(Succeed())
+		f := MustSucceed(restored.TS.Read(ctx, telem.TimeRangeMax, 1))
+		Expect(f.Series).To(HaveLen(1))
+		Expect(f.Series[0].Data).To(Equal(telem.NewSecondsTSV(10, 11, 12).Data))
+		Expect(restored.Close()).To(Succeed())
+	})
+	It("Should return an error if the cluster key does not match", func() {
+		buf := &bytes.Buffer{}
+		Expect(store.Backup(ctx, buf, manifest)).To(Succeed())
+		otherKey := uuid.New()
+		_, err := storage.Restore(buf, storage.RestoreConfig{
+			Dirname:    filepath.Join(tempDir, "restored"),
+			ClusterKey: &otherKey,
+		})
+		Expect(err).To(MatchError(ContainSubstring("expected cluster")))
+	})
+	It("Should return an error if a key is provided for a backup that is not encrypted", func() {
+		buf := &bytes.Buffer{}
+		Expect(store.Backup(ctx, buf, manifest)).To(Succeed())
+		_, err := storage.Restore(buf, storage.RestoreConfig{
+			Dirname:       filepath.Join(tempDir, "restored"),
+			EncryptionKey: bytes.Repeat([]byte{1}, 32),
+		})
+		Expect(err).To(MatchError(ContainSubstring("not encrypted")))
+	})
+	It("Should return an error if the target directory is not empty", func() {
+		buf := &bytes.Buffer{}
+		Expect(store.Backup(ctx, buf, manifest)).To(Succeed())
+		_, err := storage.Restore(buf, storage.RestoreConfig{Dirname: store.Dirname})
+		Expect(err).To(MatchError(ContainSubstring("non-empty")))
+	})
+	It("Should return an error when backing up memory-backed storage", func() {
+		memStore := MustSucceed(storage.Open(storage.Config{MemBacked: config.True()}))
+		Expect(memStore.Backup(ctx, &bytes.Buffer{}, manifest)).ToNot(Succeed())
+		Expect(memStore.Close()).To(Succeed())
+	})
+})
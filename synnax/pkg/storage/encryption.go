@@ -0,0 +1,187 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/synnaxlabs/alamos"
+	"github.com/synnaxlabs/x/config"
+	"github.com/synnaxlabs/x/errors"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/validate"
+	"go.uber.org/zap"
+)
+
+// RotateKeyConfig is the configuration for re-encrypting the storage of a node with a
+// new key.
+type RotateKeyConfig struct {
+	alamos.Instrumentation
+	// Dirname is the storage directory of the node.
+	// [REQUIRED]
+	Dirname string
+	// ColdDirname is the cold storage directory of the node, if it has one.
+	// [OPTIONAL]
+	ColdDirname string
+	// Key is the key to re-encrypt the storage with.
+	// [REQUIRED]
+	Key []byte
+	// PreviousKeys are the keys that the storage is currently encrypted with. They may
+	// be omitted when encrypting storage that is not yet encrypted.
+	// [OPTIONAL]
+	PreviousKeys [][]byte
+	// Concurrency is the number of files to re-encrypt at once.
+	// [OPTIONAL] Default: the number of CPUs
+	Concurrency int
+}
+
+var (
+	_ config.Config[RotateKeyConfig] = RotateKeyConfig{}
+	// DefaultRotateKeyConfig is the default configuration for RotateKey.
+	DefaultRotateKeyConfig = RotateKeyConfig{Concurrency: runtime.NumCPU()}
+)
+
+// Override implements config.Config.
+func (cfg RotateKeyConfig) Override(other RotateKeyConfig) RotateKeyConfig {
+	cfg.Instrumentation = override.Zero(cfg.Instrumentation, other.Instrumentation)
+	cfg.Dirname = override.String(cfg.Dirname, other.Dirname)
+	cfg.ColdDirname = override.String(cfg.ColdDirname, other.ColdDirname)
+	cfg.Key = override.Slice(cfg.Key, other.Key)
+	cfg.PreviousKeys = override.Slice(cfg.PreviousKeys, other.PreviousKeys)
+	cfg.Concurrency = override.Numeric(cfg.Concurrency, other.Concurrency)
+	return cfg
+}
+
+// Validate implements config.Config.
+func (cfg RotateKeyConfig) Validate() error {
+	v := validate.New("storage.rotate_key")
+	validate.NotEmptyString(v, "dirname", cfg.Dirname)
+	validate.NotEmptySlice(v, "key", cfg.Key)
+	validate.Positive(v, "concurrency", cfg.Concurrency)
+	return v.Error()
+}
+
+// rekeySuffix is the suffix of the temporary files written by xfs.Reencrypt.
+const rekeySuffix = ".rekey"
+
+// RotateKey re-encrypts every file in the key-value and time-series storage of a node
+// with a new key, spreading the work across a pool of workers. Files that are not yet
+// encrypted are encrypted with the new key, so RotateKey can also encrypt the storage
+// of a node that was running without encryption. Rotation is an offline operation:
+// the node must not be running, and RotateKey acquires the lock on the storage
+// directory to ensure that it isn't. Files that are already encrypted with the new key
+// are skipped, so a rotation that fails part way through can be resumed by running it
+// again. RotateKey returns the number of files that were re-encrypted.
+func RotateKey(ctx context.Context, cfgs ...RotateKeyConfig) (count int, err error) {
+	cfg, err := config.New(DefaultRotateKeyConfig, cfgs...)
+	if err != nil {
+		return 0, err
+	}
+	keys, err := xfs.NewKeyring(cfg.Key, cfg.PreviousKeys...)
+	if err != nil {
+		return 0, err
+	}
+	releaser, err := acquireLock(Config{Dirname: cfg.Dirname}, vfs.Default)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = errors.CombineErrors(err, releaser.Close()) }()
+
+	dirs := []string{
+		filepath.Join(cfg.Dirname, kvDirname),
+		filepath.Join(cfg.Dirname, cesiumDirname),
+	}
+	if cfg.ColdDirname != "" {
+		dirs = append(dirs, filepath.Join(cfg.ColdDirname, cesiumDirname))
+	}
+	var files []string
+	for _, dir := range dirs {
+		if files, err = listFiles(dir, files); err != nil {
+			return 0, err
+		}
+	}
+	cfg.L.Info("rotating encryption key", zap.Int("files", len(files)))
+
+	var (
+		wg         sync.WaitGroup
+		paths      = make(chan string)
+		rotated    atomic.Int64
+		errMu      sync.Mutex
+		rotateErr  error
+		ctx_, stop = context.WithCancel(ctx)
+	)
+	defer stop()
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				ok, err := xfs.Reencrypt(xfs.Default, path, keys)
+				if err != nil {
+					errMu.Lock()
+					rotateErr = errors.CombineErrors(rotateErr, errors.Wrapf(err, "failed to re-encrypt %s", path))
+					errMu.Unlock()
+					stop()
+					continue
+				}
+				if ok {
+					cfg.L.Debug("re-encrypted file", zap.String("path", path))
+					rotated.Add(1)
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, path := range files {
+		select {
+		case <-ctx_.Done():
+			break dispatch
+		case paths <- path:
+		}
+	}
+	close(paths)
+	wg.Wait()
+	if rotateErr == nil {
+		rotateErr = ctx.Err()
+	}
+	return int(rotated.Load()), rotateErr
+}
+
+// listFiles appends the paths of all files within the given directory and its
+// subdirectories to files. It returns files unchanged if the directory does not
+// exist.
+func listFiles(dir string, files []string) ([]string, error) {
+	exists, err := xfs.Default.Exists(dir)
+	if err != nil || !exists {
+		return files, err
+	}
+	infos, err := xfs.Default.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		path := filepath.Join(dir, info.Name())
+		if info.IsDir() {
+			if files, err = listFiles(path, files); err != nil {
+				return nil, err
+			}
+		} else if !strings.HasSuffix(info.Name(), rekeySuffix) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
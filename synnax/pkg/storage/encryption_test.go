@@ -0,0 +1,182 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/synnaxlabs/cesium"
+	"github.com/synnaxlabs/synnax/pkg/storage"
+	"github.com/synnaxlabs/x/config"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/telem"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Encryption", func() {
+	var (
+		ctx     = context.Background()
+		tempDir string
+		dirname string
+		oldKey  = bytes.Repeat([]byte{1}, 32)
+		newKey  = bytes.Repeat([]byte{2}, 32)
+		open    = func(key []byte) (*storage.Storage, error) {
+			return storage.Open(storage.Config{
+				Dirname:       dirname,
+				MemBacked:     config.False(),
+				EncryptionKey: key,
+			})
+		}
+		expectData = func(store *storage.Storage) {
+			v, closer := MustSucceed2(store.KV.Get(ctx, []byte("key")))
+			Expect(v).To(Equal([]byte("value")))
+			Expect(closer.Close()).To(Succeed())
+			f := MustSucceed(store.TS.Read(ctx, telem.TimeRangeMax, 1))
+			Expect(f.Series).To(HaveLen(1))
+			Expect(f.Series[0].Data).To(Equal(telem.NewSecondsTSV(10, 11, 12).Data))
+		}
+		populate = func(key []byte) {
+			store := MustSucceed(open(key))
+			Expect(store.KV.Set(ctx, []byte("key"), []byte("value"))).To(Succeed())
+			Expect(store.TS.CreateChannel(ctx, cesium.Channel{
+				Key:      1,
+				IsIndex:  true,
+				DataType: telem.TimeStampT,
+			})).To(Succeed())
+			Expect(store.TS.Write(ctx, 10*telem.SecondTS, cesium.NewFrame(
+				[]cesium.ChannelKey{1},
+				[]telem.Series{telem.NewSecondsTSV(10, 11, 12)},
+			))).To(Succeed())
+			Expect(store.Close()).To(Succeed())
+		}
+	)
+	BeforeEach(func() {
+		tempDir = MustSucceed(os.MkdirTemp("", "synnax-encryption-test"))
+		dirname = filepath.Join(tempDir, "storage")
+		populate(oldKey)
+	})
+	AfterEach(func() { Expect(os.RemoveAll(tempDir)).To(Succeed()) })
+
+	It("Should read encrypted data back with the same key", func() {
+		store := MustSucceed(open(oldKey))
+		expectData(store)
+		Expect(store.Close()).To(Succeed())
+	})
+
+	It("Should not open storage with a different key", func() {
+		_, err := open(newKey)
+		Expect(err).To(HaveOccurredAs(xfs.ErrUnknownKey))
+	})
+
+	It("Should not open encrypted storage without a key", func() {
+		_, err := open(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should keep backups of encrypted storage encrypted", func() {
+		store := MustSucceed(open(oldKey))
+		buf := &bytes.Buffer{}
+		Expect(store.Backup(ctx, buf, storage.BackupManifest{})).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+		dirname = filepath.Join(tempDir, "restored")
+		m := MustSucceed(storage.Restore(buf, storage.RestoreConfig{
+			Dirname:       dirname,
+			EncryptionKey: oldKey,
+		}))
+		Expect(m.Encrypted).To(BeTrue())
+		_, err := open(nil)
+		Expect(err).To(HaveOccurred())
+		store = MustSucceed(open(oldKey))
+		expectData(store)
+		Expect(store.Close()).To(Succeed())
+	})
+
+	Describe("Restore", func() {
+		var archive []byte
+		BeforeEach(func() {
+			store := MustSucceed(open(oldKey))
+			buf := &bytes.Buffer{}
+			Expect(store.Backup(ctx, buf, storage.BackupManifest{})).To(Succeed())
+			Expect(store.Close()).To(Succeed())
+			archive = buf.Bytes()
+			dirname = filepath.Join(tempDir, "restored")
+		})
+
+		It("Should not restore an encrypted backup without a key", func() {
+			_, err := storage.Restore(bytes.NewReader(archive), storage.RestoreConfig{
+				Dirname: dirname,
+			})
+			Expect(err).To(MatchError(ContainSubstring("must be provided")))
+			Expect(dirname).ToNot(BeADirectory())
+		})
+
+		It("Should not restore an encrypted backup with a different key", func() {
+			_, err := storage.Restore(bytes.NewReader(archive), storage.RestoreConfig{
+				Dirname:       dirname,
+				EncryptionKey: newKey,
+			})
+			Expect(err).To(MatchError(ContainSubstring("different key")))
+			Expect(dirname).ToNot(BeADirectory())
+		})
+	})
+
+	Describe("RotateKey", func() {
+		It("Should re-encrypt the storage with a new key", func() {
+			n := MustSucceed(storage.RotateKey(ctx, storage.RotateKeyConfig{
+				Dirname:      dirname,
+				Key:          newKey,
+				PreviousKeys: [][]byte{oldKey},
+				Concurrency:  2,
+			}))
+			Expect(n).To(BeNumerically(">", 0))
+			_, err := open(oldKey)
+			Expect(err).To(HaveOccurredAs(xfs.ErrUnknownKey))
+			store := MustSucceed(open(newKey))
+			expectData(store)
+			Expect(store.Close()).To(Succeed())
+
+			By("Skipping files that are already encrypted with the new key")
+			Expect(storage.RotateKey(ctx, storage.RotateKeyConfig{
+				Dirname:      dirname,
+				Key:          newKey,
+				PreviousKeys: [][]byte{oldKey},
+			})).To(BeZero())
+		})
+
+		It("Should encrypt storage that is not encrypted", func() {
+			dirname = filepath.Join(tempDir, "unencrypted")
+			populate(nil)
+			Expect(storage.RotateKey(ctx, storage.RotateKeyConfig{
+				Dirname: dirname,
+				Key:     newKey,
+			})).To(BeNumerically(">", 0))
+			_, err := open(nil)
+			Expect(err).To(HaveOccurred())
+			store := MustSucceed(open(newKey))
+			expectData(store)
+			Expect(store.Close()).To(Succeed())
+		})
+
+		It("Should not rotate the key of a running node", func() {
+			store := MustSucceed(open(oldKey))
+			Expect(storage.RotateKey(ctx, storage.RotateKeyConfig{
+				Dirname:      dirname,
+				Key:          newKey,
+				PreviousKeys: [][]byte{oldKey},
+			})).Error().To(HaveOccurred())
+			Expect(store.Close()).To(Succeed())
+		})
+	})
+})
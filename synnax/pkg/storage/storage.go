@@ -41,6 +41,7 @@ import (
 	"github.com/synnaxlabs/x/kv"
 	"github.com/synnaxlabs/x/kv/pebblekv"
 	"github.com/synnaxlabs/x/override"
+	"github.com/synnaxlabs/x/telem"
 	"github.com/synnaxlabs/x/validate"
 )
 
@@ -78,6 +79,13 @@ type Storage struct {
 	TS *cesium.DB
 	// lock is the lock held on the storage directory.
 	lock io.Closer
+	// keys is used to encrypt the files of the storage, and is nil if the storage is
+	// not encrypted.
+	keys *xfs.Keyring
+	// engineKV is the key-value engine opened by Open. Layers above storage may replace
+	// KV with a wrapper around the engine, so we keep a reference for operations that
+	// need direct access to it, such as backups.
+	engineKV kv.DB
 }
 
 // Gorpify returns a gorp.DB that can be used to interact with the storage key-value store.
@@ -112,6 +120,21 @@ type Config struct {
 	// Dirname defines the root directory the Storage resides. The given directory
 	// shouldn't be used by another process while the node is running.
 	Dirname string
+	// ColdDirname is the directory of a secondary, typically larger and slower, storage
+	// tier that time-series data is moved to once it is older than ColdAfter. If
+	// empty, all data is stored in Dirname. Ignored if MemBacked is true.
+	ColdDirname string
+	// ColdAfter is the age after which time-series data is moved to ColdDirname.
+	ColdAfter telem.TimeSpan
+	// DiskQuota is the maximum total size of the time-series data stored by the node.
+	// When it is exceeded, the oldest data is evicted. If zero, the size of the data
+	// is not limited.
+	DiskQuota telem.Size
+	// EncryptionKey is the AES key used to encrypt the contents of all files in the
+	// storage directory. Must be 16, 24, or 32 bytes long. If nil, files are not
+	// encrypted. A directory must always be opened with the key it was created with,
+	// or re-encrypted with RotateKey before being opened with a new one.
+	EncryptionKey []byte
 	// Perm is the file permissions to use for the storage directory.
 	Perm fs.FileMode
 	// MemBacked defines whether the node should use a memory-backed file system.
@@ -136,6 +159,10 @@ var (
 // Override implements Config.
 func (cfg Config) Override(other Config) Config {
 	cfg.Dirname = override.String(cfg.Dirname, other.Dirname)
+	cfg.ColdDirname = override.String(cfg.ColdDirname, other.ColdDirname)
+	cfg.ColdAfter = override.Numeric(cfg.ColdAfter, other.ColdAfter)
+	cfg.DiskQuota = override.Numeric(cfg.DiskQuota, other.DiskQuota)
+	cfg.EncryptionKey = override.Slice(cfg.EncryptionKey, other.EncryptionKey)
 	cfg.Perm = override.Numeric(cfg.Perm, other.Perm)
 	cfg.KVEngine = override.Numeric(cfg.KVEngine, other.KVEngine)
 	cfg.TSEngine = override.Numeric(cfg.TSEngine, other.TSEngine)
@@ -143,6 +170,7 @@ func (cfg Config) Override(other Config) Config {
 	cfg.Instrumentation = override.Zero(cfg.Instrumentation, other.Instrumentation)
 	if *cfg.MemBacked {
 		cfg.Dirname = ""
+		cfg.ColdDirname = ""
 	}
 	return cfg
 }
@@ -154,17 +182,23 @@ func (cfg Config) Validate() error {
 	v.Ternaryf("kvEngine", !lo.Contains(kvEngines, cfg.KVEngine), "invalid key-value engine %s", cfg.KVEngine)
 	v.Ternaryf("tsEngine", !lo.Contains(tsEngines, cfg.TSEngine), "invalid time-series engine %s", cfg.TSEngine)
 	v.Ternary("permissions", cfg.Perm == 0, "insufficient permission bits on directory")
+	validate.NonNegative(v, "coldAfter", cfg.ColdAfter)
+	validate.NonNegative(v, "diskQuota", cfg.DiskQuota)
 	return v.Error()
 }
 
 // Report implements the alamos.ReportProvider interface.
 func (cfg Config) Report() alamos.Report {
 	return alamos.Report{
-		"dirname":     cfg.Dirname,
-		"permissions": cfg.Perm,
-		"mem_backed":  cfg.MemBacked,
-		"kv_engine":   cfg.KVEngine.String(),
-		"ts_engine":   cfg.TSEngine.String(),
+		"dirname":      cfg.Dirname,
+		"cold_dirname": cfg.ColdDirname,
+		"cold_after":   cfg.ColdAfter,
+		"disk_quota":   cfg.DiskQuota,
+		"encrypted":    cfg.EncryptionKey != nil,
+		"permissions":  cfg.Perm,
+		"mem_backed":   cfg.MemBacked,
+		"kv_engine":    cfg.KVEngine.String(),
+		"ts_engine":    cfg.TSEngine.String(),
 	}
 }
 
@@ -206,13 +240,25 @@ func Open(cfg Config) (s *Storage, err error) {
 	// Allow the caller to release the lock when they finish using the storage.
 	s.lock = releaser
 
+	// Encrypt the files of both engines. The lock file is left unencrypted, as it
+	// holds no data.
+	kvFS, tsFS := baseVFS, baseXFS
+	if cfg.EncryptionKey != nil {
+		if s.keys, err = xfs.NewKeyring(cfg.EncryptionKey); err != nil {
+			return s, errors.CombineErrors(err, s.lock.Close())
+		}
+		kvFS = pebblekv.NewEncryptedFS(baseVFS, s.keys)
+		tsFS = xfs.NewEncrypted(baseXFS, s.keys)
+	}
+
 	// Open the key-value storage engine.
-	if s.KV, err = openKV(cfg, baseVFS); err != nil {
+	if s.KV, err = openKV(cfg, kvFS); err != nil {
 		return s, errors.CombineErrors(err, s.lock.Close())
 	}
+	s.engineKV = s.KV
 
 	// Open the time-series engine.
-	if s.TS, err = openTS(cfg, baseXFS); err != nil {
+	if s.TS, err = openTS(cfg, tsFS); err != nil {
 		err = errors.CombineErrors(err, s.KV.Close())
 		return s, errors.CombineErrors(err, s.lock.Close())
 	}
@@ -314,9 +360,20 @@ func openTS(cfg Config, fs xfs.FS) (*ts.DB, error) {
 	if cfg.TSEngine != CesiumTS {
 		return nil, errors.Newf("[storage] - unsupported time-series engine: %s", cfg.TSEngine)
 	}
-	return ts.Open(ts.Config{
+	tsCfg := ts.Config{
 		Instrumentation: cfg.Instrumentation.Child("ts"),
 		Dirname:         filepath.Join(cfg.Dirname, cesiumDirname),
 		FS:              fs,
-	})
+	}
+	if cfg.ColdDirname != "" {
+		coldFS, err := fs.Sub(filepath.Join(cfg.ColdDirname, cesiumDirname))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open cold storage directory %s", cfg.ColdDirname)
+		}
+		tsCfg.ColdTier = &ts.ColdTierConfig{FS: coldFS, MoveAfter: cfg.ColdAfter}
+	}
+	if cfg.DiskQuota > 0 {
+		tsCfg.Quota = &ts.QuotaConfig{NodeLimit: cfg.DiskQuota}
+	}
+	return ts.Open(tsCfg)
 }
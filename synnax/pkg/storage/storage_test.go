@@ -84,6 +84,16 @@ var _ = Describe("storage", func() {
 				Expect(store.Close()).To(Succeed())
 			})
 		})
+		Describe("Cold Tier", func() {
+			It("Should store cold time-series data in the cold directory", func() {
+				cfg.ColdDirname = filepath.Join(tempDir, "cold")
+				store, err := storage.Open(cfg)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = os.Stat(filepath.Join(cfg.ColdDirname, "cesium"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(store.Close()).To(Succeed())
+			})
+		})
 	})
 	Describe("ServiceConfig", func() {
 		DescribeTable("Validate", func(
@@ -116,6 +126,20 @@ var _ = Describe("storage", func() {
 				},
 				"",
 			),
+			Entry("Negative cold tier threshold",
+				func(cfg storage.Config) storage.Config {
+					cfg.ColdAfter = -1
+					return cfg
+				},
+				"coldAfter",
+			),
+			Entry("Negative disk quota",
+				func(cfg storage.Config) storage.Config {
+					cfg.DiskQuota = -1
+					return cfg
+				},
+				"diskQuota",
+			),
 			Entry("Invalid key-value engine",
 				func(cfg storage.Config) storage.Config {
 					cfg.KVEngine = 12
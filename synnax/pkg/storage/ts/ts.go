@@ -40,6 +40,14 @@ type (
 	StreamerConfig   = cesium.StreamerConfig
 	StreamerRequest  = cesium.StreamerRequest
 	StreamerResponse = cesium.StreamerResponse
+	AlterConfig      = cesium.AlterChannelConfig
+	ColdTierConfig   = cesium.ColdTierConfig
+	QuotaConfig      = cesium.QuotaConfig
+	QuotaEvent       = cesium.QuotaEvent
+	AlterProgress    = cesium.AlterProgress
+	ChannelUsage     = cesium.ChannelUsage
+	GCMode           = cesium.GCMode
+	GCProgress       = cesium.GCProgress
 )
 
 const AutoSpan = cesium.AutoSpan
@@ -48,6 +56,10 @@ const (
 	WriterPersistOnly   = cesium.WriterPersistOnly
 	WriterStreamOnly    = cesium.WriterStreamOnly
 )
+const (
+	GCModeThreshold = cesium.GCModeThreshold
+	GCModeCompact   = cesium.GCModeCompact
+)
 
 type Config struct {
 	alamos.Instrumentation
@@ -58,6 +70,12 @@ type Config struct {
 	// FS is the file system interface that the DB will use to read and write data.
 	// [REQUIRED]
 	FS xfs.FS
+	// ColdTier configures a secondary file system that the DB moves old data to.
+	// [OPTIONAL] Default: no cold tier
+	ColdTier *ColdTierConfig
+	// Quota configures limits on the disk space used by the DB.
+	// [OPTIONAL] Default: no quotas
+	Quota *QuotaConfig
 }
 
 var (
@@ -80,6 +98,8 @@ func (c Config) Validate() error {
 func (c Config) Override(other Config) Config {
 	c.Dirname = override.String(c.Dirname, other.Dirname)
 	c.FS = override.Nil(c.FS, other.FS)
+	c.ColdTier = override.Nil(c.ColdTier, other.ColdTier)
+	c.Quota = override.Nil(c.Quota, other.Quota)
 	c.Instrumentation = override.Zero(c.Instrumentation, other.Instrumentation)
 	return c
 }
@@ -89,9 +109,15 @@ func Open(configs ...Config) (*DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	return cesium.Open(
-		cfg.Dirname,
+	opts := []cesium.Option{
 		cesium.WithFS(cfg.FS),
 		cesium.WithInstrumentation(cfg.Instrumentation),
-	)
+	}
+	if cfg.ColdTier != nil {
+		opts = append(opts, cesium.WithColdTier(cfg.ColdTier))
+	}
+	if cfg.Quota != nil {
+		opts = append(opts, cesium.WithQuota(cfg.Quota))
+	}
+	return cesium.Open(cfg.Dirname, opts...)
 }
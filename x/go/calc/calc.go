@@ -15,6 +15,7 @@ import (
 	"math"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/synnaxlabs/x/deque"
 	"github.com/synnaxlabs/x/errors"
@@ -174,6 +175,61 @@ func (e Expression) Tree() ast.Expr {
 	return e.exp
 }
 
+// FromTree returns an expression that evaluates the given AST, which must be built from
+// the node types produced by Build, such as a sub-tree of another expression.
+func FromTree(exp ast.Expr) Expression {
+	return Expression{exp: exp}
+}
+
+// Identifiers returns the unique identifiers in the expression, in the order in which
+// they first appear.
+func (e Expression) Identifiers() []string {
+	var (
+		identifiers []string
+		seen        = make(map[string]bool)
+	)
+	ast.Inspect(e.exp, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && !seen[id.Name] {
+			seen[id.Name] = true
+			identifiers = append(identifiers, id.Name)
+		}
+		return true
+	})
+	return identifiers
+}
+
+// String returns a fully parenthesized representation of the expression that Build
+// parses into an expression that evaluates identically.
+func (e Expression) String() string {
+	var b strings.Builder
+	format(&b, e.exp)
+	return b.String()
+}
+
+func format(b *strings.Builder, exp ast.Expr) {
+	switch exp := exp.(type) {
+	case *ast.BinaryExpr:
+		b.WriteString("(")
+		format(b, exp.X)
+		b.WriteString(" " + exp.Op.String() + " ")
+		format(b, exp.Y)
+		b.WriteString(")")
+	case *ast.BasicLit:
+		// Build parses a leading minus as multiplication by -1, so negative literals
+		// are written as a subtraction from zero.
+		if v, ok := strings.CutPrefix(exp.Value, "-"); ok {
+			b.WriteString("(0 - " + v + ")")
+		} else {
+			b.WriteString(exp.Value)
+		}
+	case *ast.Ident:
+		b.WriteString(exp.Name)
+	case *ast.UnaryExpr:
+		b.WriteString("!")
+		format(b, exp.X)
+	}
+}
+
 // Evaluate evaluates the expression
 func (e Expression) Evaluate(r Resolver) float64 {
 	return eval(e.exp, r)
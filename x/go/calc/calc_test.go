@@ -477,6 +477,30 @@ var _ = Describe("Calc", func() {
 			Expect(e.Evaluate(nil)).To(Equal(float64(1)))
 		})
 	})
+	Describe("Identifiers", func() {
+		It("Should return the unique identifiers in order of appearance", func() {
+			e := calc.Expression{}
+			Expect(e.Build("b > 1 && (a < b || c == 2)")).To(Succeed())
+			Expect(e.Identifiers()).To(Equal([]string{"b", "a", "c"}))
+		})
+	})
+	Describe("String", func() {
+		It("Should format an expression that builds into an equivalent expression", func() {
+			e := calc.Expression{}
+			Expect(e.Build("!(x > -2.5) || y*2 >= 3^2")).To(Succeed())
+			Expect(e.String()).To(Equal("(!(x > ((0 - 1) * 2.5)) || ((y * 2) >= (3 ^ 2)))"))
+			rebuilt := calc.Expression{}
+			Expect(rebuilt.Build(e.String())).To(Succeed())
+			r := &mockResolver{vals: map[string]float64{"x": -3, "y": 1}}
+			Expect(rebuilt.Evaluate(r)).To(Equal(e.Evaluate(r)))
+			Expect(rebuilt.Evaluate(r)).To(Equal(float64(1)))
+		})
+		It("Should format a sub-tree of an expression", func() {
+			e := calc.Expression{}
+			Expect(e.Build("a > 1 && b < 2")).To(Succeed())
+			Expect(calc.FromTree(e.Tree().(*ast.BinaryExpr).Y).String()).To(Equal("(b < 2)"))
+		})
+	})
 	Describe("Boolean Logic Tests", func() {
 		Describe("&& Tests", func() {
 			It("1 && 1 == 1", func() {
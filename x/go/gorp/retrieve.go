@@ -29,6 +29,7 @@ func NewRetrieve[K Key, E Entry[K]]() Retrieve[K, E] {
 
 type filterOptions struct {
 	required bool
+	guard    bool
 }
 
 type FilterOption func(*filterOptions)
@@ -37,6 +38,14 @@ func Required() FilterOption {
 	return func(o *filterOptions) { o.required = true }
 }
 
+// Guard marks a filter as a guard. Entries that do not pass a guard are excluded from
+// the results, but passing a guard does not count as a match for the other filters on
+// the query. Guards are useful for hiding entries regardless of the filters that a
+// caller applies.
+func Guard() FilterOption {
+	return func(o *filterOptions) { o.guard = true }
+}
+
 // Where adds the provided filter to the query. If filtering by the key of the Entry,
 // use the far more efficient WhereKeys method instead.
 func (r Retrieve[K, E]) Where(filter func(*E) bool, opts ...FilterOption) Retrieve[K, E] {
@@ -89,7 +98,8 @@ func (r Retrieve[K, E]) Entry(entry *E) Retrieve[K, E] {
 // Exec executes the Params against the provided Writer. If the WhereKeys method is set on
 // the query, Retrieve will return a query.NotFound  error if ANY of the keys do not
 // exist in the database. If Where is set on the query, Retrieve will return a query.NotFound
-// if NO keys pass the Where filter.
+// if NO keys pass the Where filter. This also applies to WhereKeys queries that bind a
+// single Entry.
 func (r Retrieve[K, E]) Exec(ctx context.Context, tx Tx) error {
 	checkForNilTx("Retriever.Exec", tx)
 	_, ok := getWhereKeys[K](r.Params)
@@ -117,15 +127,22 @@ func (f filters[K, E]) exec(entry *E) bool {
 	if len(f) == 0 {
 		return true
 	}
-	match := false
+	match, matchers := false, 0
 	for _, fil := range f {
+		if fil.guard {
+			if !fil.f(entry) {
+				return false
+			}
+			continue
+		}
+		matchers++
 		if fil.f(entry) {
 			match = true
 		} else if fil.required {
 			return false
 		}
 	}
-	return match
+	return match || matchers == 0
 }
 
 func addFilter[K Key, E Entry[K]](
@@ -264,6 +281,12 @@ func keysRetrieve[K Key, E Entry[K]](
 		}
 	}
 	entries.Replace(toReplace)
+	if err == nil && !entries.isMultiple && len(keysResult) > 0 && len(toReplace) == 0 {
+		return errors.Wrapf(
+			query.NotFound,
+			fmt.Sprintf("no %s found matching query", types.PluralName[E]()),
+		)
+	}
 	return err
 }
 
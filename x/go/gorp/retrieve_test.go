@@ -111,6 +111,14 @@ var _ = Describe("Retrieve", Ordered, func() {
 				Expect(err).To(HaveOccurred())
 				Expect(errors.Is(err, query.NotFound)).To(BeTrue())
 			})
+			It("Should return a query.NotFound error if the key does not match the where clause", func() {
+				err := gorp.NewRetrieve[int, entry]().
+					WhereKeys(entries[0].GorpKey()).
+					Where(func(e *entry) bool { return e.ID == 241241 }).
+					Entry(&entry{}).
+					Exec(ctx, tx)
+				Expect(err).To(HaveOccurredAs(query.NotFound))
+			})
 			It("Should return a query.NotFound error if the where clause matches no entry", func() {
 				err := gorp.NewRetrieve[int, entry]().
 					Where(func(e *entry) bool { return e.ID == 241241 }).
@@ -189,6 +197,27 @@ var _ = Describe("Retrieve", Ordered, func() {
 			).To(Succeed())
 			Expect(res).To(Equal([]entry{entries[1]}))
 		})
+		It("Should exclude entries that do not pass a gorp.Guard()", func() {
+			var res []entry
+			Expect(gorp.NewRetrieve[int, entry]().
+				Entries(&res).
+				Where(func(e *entry) bool { return e.ID != entries[2].ID }, gorp.Guard()).
+				Where(func(e *entry) bool { return e.ID == entries[1].ID }).
+				Where(func(e *entry) bool { return e.ID == entries[2].ID }).
+				Exec(ctx, tx),
+			).To(Succeed())
+			Expect(res).To(Equal([]entry{entries[1]}))
+		})
+		It("Should return all entries that pass a gorp.Guard() when there are no other filters", func() {
+			var res []entry
+			Expect(gorp.NewRetrieve[int, entry]().
+				Entries(&res).
+				Where(func(e *entry) bool { return e.ID != entries[2].ID }, gorp.Guard()).
+				Exec(ctx, tx),
+			).To(Succeed())
+			Expect(res).To(HaveLen(len(entries) - 1))
+			Expect(res).ToNot(ContainElement(entries[2]))
+		})
 		It("Should NOT return a query.NamesNotFound error if no entries are found", func() {
 			var res []entry
 			Expect(gorp.NewRetrieve[int, entry]().
@@ -0,0 +1,842 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package fs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	goPath "path"
+	"sync"
+
+	"github.com/synnaxlabs/x/errors"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	// ErrNotEncrypted is returned when a file read through an encrypted FS was not
+	// written by one.
+	ErrNotEncrypted = errors.New("file is not encrypted")
+	// ErrUnknownKey is returned when a file was encrypted with a key that is not in the
+	// Keyring.
+	ErrUnknownKey = errors.New("file was encrypted with an unknown key")
+	// ErrCorrupted is returned when the contents of an encrypted file fail
+	// authentication.
+	ErrCorrupted = errors.New("encrypted file is corrupted")
+)
+
+// Encrypted files consist of a header, a journal, and a sequence of independently
+// sealed blocks. Each block holds up to encBlockSize bytes of plaintext, and is stored
+// as a random nonce, the ciphertext, and the GCM authentication tag. Because every
+// block has the same size on disk except for the last, the block holding any plaintext
+// offset can be located directly, allowing random access reads and writes.
+//
+// The header holds the ID of the key the file was encrypted with and a random file ID.
+// Blocks are sealed with a key derived from both, so random nonces only need to be
+// unique among the blocks written to a single file. The file ID and the index of a
+// block are also authenticated along with each block, so blocks cannot be moved within
+// or between files without detection.
+//
+// Writing to a block that already exists re-seals it in place. Before a block that
+// may have been synced is rewritten, its sealed contents are copied to the journal and
+// synced, so that a write torn by a crash can be rolled back to the last synced
+// version of the block instead of corrupting it.
+const (
+	encMagic             = "SXEF"
+	encVersion           = 1
+	encHeaderSize        = 32
+	encKeyIDSize         = 8
+	encFileIDSize        = 16
+	encNonceSize         = 12
+	encTagSize           = 16
+	encOverhead          = encNonceSize + encTagSize
+	encBlockSize         = 4096
+	encSealedSize        = encBlockSize + encOverhead
+	encJournalHeaderSize = 12
+	encJournalSize       = encJournalHeaderSize + encSealedSize
+	encDataOffset        = encHeaderSize + encJournalSize
+)
+
+type keyID [encKeyIDSize]byte
+
+// Keyring holds the keys used to encrypt and decrypt files. New files are always
+// encrypted with the primary key, while files encrypted with any key in the Keyring
+// can be read. This allows files to be gradually re-encrypted after a key is
+// rotated.
+type Keyring struct {
+	primary keyID
+	keys    map[keyID][]byte
+}
+
+// NewKeyring creates a Keyring that encrypts files with the primary key and can
+// decrypt files encrypted with either the primary or any of the previous keys. Keys
+// must be 16, 24, or 32 bytes long to select AES-128, AES-192, or AES-256
+// respectively.
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[keyID][]byte, len(previous)+1)}
+	for i, key := range append([][]byte{primary}, previous...) {
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, errors.Wrap(err, "invalid encryption key")
+		}
+		sum := sha256.Sum256(key)
+		var id keyID
+		copy(id[:], sum[:])
+		if i == 0 {
+			k.primary = id
+		}
+		k.keys[id] = append([]byte(nil), key...)
+	}
+	return k, nil
+}
+
+// ID returns a hex encoded identifier of the primary key. The identifier does not
+// reveal the key, and is the same one written to the header of every file encrypted
+// with the key, so it can be stored alongside encrypted data to check that the right
+// key is being used before any of the data is read.
+func (k *Keyring) ID() string { return hex.EncodeToString(k.primary[:]) }
+
+// fileAEAD returns the AEAD used to seal the blocks of a file encrypted with the key
+// with the given ID. The key of the AEAD is derived from that key and the file ID.
+func (k *Keyring) fileAEAD(id keyID, fileID [encFileIDSize]byte) (cipher.AEAD, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	derived := make([]byte, len(key))
+	kdf := hkdf.New(sha256.New, key, fileID[:], []byte(encMagic))
+	if _, err := io.ReadFull(kdf, derived); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// RandomAccessFile is the subset of File required to store an encrypted file.
+type RandomAccessFile interface {
+	io.Closer
+	io.ReaderAt
+	io.WriterAt
+	Stat() (os.FileInfo, error)
+	Sync() error
+}
+
+// NewEncryptedFile wraps f so that all data written to it is encrypted with the
+// primary key of the Keyring, and all data read from it is decrypted. The flag should
+// be the flag that f was opened with. f must be readable even if it is only written
+// to, as writes that partially overwrite a block must first decrypt it.
+func NewEncryptedFile(f RandomAccessFile, keys *Keyring, flag int) (File, error) {
+	return openEncryptedFile(f, keys, flag, newEncryptedFileState())
+}
+
+// EncryptedFileInfo converts the FileInfo of an encrypted file to report the size of
+// its plaintext.
+func EncryptedFileInfo(info os.FileInfo) os.FileInfo {
+	if !info.Mode().IsRegular() {
+		return info
+	}
+	return encryptedFileInfo{FileInfo: info, size: plaintextSize(info.Size())}
+}
+
+type encryptedFileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+// Size implements os.FileInfo.
+func (i encryptedFileInfo) Size() int64 { return i.size }
+
+// plaintextSize returns the size of the plaintext stored in an encrypted file with the
+// given size on disk.
+func plaintextSize(size int64) int64 {
+	if size <= encDataOffset {
+		return 0
+	}
+	size -= encDataOffset
+	full, rem := size/encSealedSize, size%encSealedSize
+	return full*encBlockSize + max(rem-encOverhead, 0)
+}
+
+// encryptedFileState is shared between all handles to the same file in an encrypted
+// FS.
+type encryptedFileState struct {
+	// RWMutex protects readers from observing a block that is being rewritten.
+	sync.RWMutex
+	// dirty holds the blocks written since the file was last synced. Their previous
+	// contents were never synced, so they can be rewritten without being journaled.
+	dirty map[int64]struct{}
+	// journaled is the index of the block held in the journal, or -1 if the journal has
+	// not been written since the file was opened.
+	journaled int64
+}
+
+func newEncryptedFileState() *encryptedFileState {
+	return &encryptedFileState{dirty: make(map[int64]struct{}), journaled: -1}
+}
+
+type encryptedFile struct {
+	raw    RandomAccessFile
+	keys   *Keyring
+	keyID  keyID
+	aead   cipher.AEAD
+	fileID [encFileIDSize]byte
+	// state is shared between all handles to the same file in an encrypted FS.
+	state    *encryptedFileState
+	writable bool
+	append   bool
+	offset   int64
+}
+
+var _ File = (*encryptedFile)(nil)
+
+func openEncryptedFile(
+	raw RandomAccessFile,
+	keys *Keyring,
+	flag int,
+	state *encryptedFileState,
+) (*encryptedFile, error) {
+	f := &encryptedFile{
+		raw:      raw,
+		keys:     keys,
+		state:    state,
+		writable: flag&(os.O_WRONLY|os.O_RDWR) != 0,
+		append:   flag&os.O_APPEND != 0,
+	}
+	f.state.Lock()
+	defer f.state.Unlock()
+	if f.writable && flag&os.O_TRUNC != 0 {
+		if err := f.truncateRaw(0); err != nil {
+			return nil, err
+		}
+	}
+	if err := f.loadHeader(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *encryptedFile) truncateRaw(size int64) error {
+	t, ok := f.raw.(interface{ Truncate(int64) error })
+	if !ok {
+		return errors.Newf("cannot truncate file of type %T", f.raw)
+	}
+	return t.Truncate(size)
+}
+
+// loadHeader reads the header of the file, or writes it with the primary key if the
+// file is empty and writable. An empty file opened for reading has no header until it
+// is written to through another handle, so loadHeader is retried on each read until
+// it succeeds. If the file is writable, any block torn by a crash is restored from the
+// journal.
+func (f *encryptedFile) loadHeader() error {
+	if f.aead != nil {
+		return nil
+	}
+	info, err := f.raw.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		if !f.writable {
+			return nil
+		}
+		return f.writeHeader()
+	}
+	if info.Size() < encHeaderSize {
+		return ErrNotEncrypted
+	}
+	var header [encHeaderSize]byte
+	if _, err = f.raw.ReadAt(header[:], 0); err != nil {
+		return err
+	}
+	id, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+	copy(f.fileID[:], header[16:])
+	if f.aead, err = f.keys.fileAEAD(id, f.fileID); err != nil {
+		return err
+	}
+	f.keyID = id
+	if !f.writable {
+		return nil
+	}
+	return f.recoverJournal()
+}
+
+func parseHeader(header [encHeaderSize]byte) (id keyID, err error) {
+	if string(header[:4]) != encMagic {
+		return id, ErrNotEncrypted
+	}
+	if header[4] != encVersion {
+		return id, errors.Newf("unsupported encrypted file version %d", header[4])
+	}
+	copy(id[:], header[8:16])
+	return id, nil
+}
+
+// writeHeader writes the header of the file followed by an empty journal.
+func (f *encryptedFile) writeHeader() error {
+	var header [encDataOffset]byte
+	copy(header[:], encMagic)
+	header[4] = encVersion
+	copy(header[8:16], f.keys.primary[:])
+	if _, err := rand.Read(f.fileID[:]); err != nil {
+		return err
+	}
+	copy(header[16:encHeaderSize], f.fileID[:])
+	aead, err := f.keys.fileAEAD(f.keys.primary, f.fileID)
+	if err != nil {
+		return err
+	}
+	if _, err = f.raw.WriteAt(header[:], 0); err != nil {
+		return err
+	}
+	f.aead, f.keyID = aead, f.keys.primary
+	return nil
+}
+
+func (f *encryptedFile) size() (int64, error) {
+	info, err := f.raw.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return plaintextSize(info.Size()), nil
+}
+
+func (f *encryptedFile) additionalData(block int64) []byte {
+	ad := make([]byte, encFileIDSize+8)
+	copy(ad, f.fileID[:])
+	binary.LittleEndian.PutUint64(ad[encFileIDSize:], uint64(block))
+	return ad
+}
+
+func blockOffset(block int64) int64 { return encDataOffset + block*encSealedSize }
+
+// readSealed reads the sealed contents of the block at the given index. It returns an
+// empty block if the file ends before it.
+func (f *encryptedFile) readSealed(block int64) ([]byte, error) {
+	buf := make([]byte, encSealedSize)
+	n, err := f.raw.ReadAt(buf, blockOffset(block))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// open decrypts the sealed contents of the block at the given index.
+func (f *encryptedFile) open(block int64, sealed []byte) ([]byte, error) {
+	if len(sealed) <= encOverhead {
+		return nil, ErrCorrupted
+	}
+	plain, err := f.aead.Open(
+		nil,
+		sealed[:encNonceSize],
+		sealed[encNonceSize:],
+		f.additionalData(block),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(ErrCorrupted, "block %d failed authentication", block)
+	}
+	return plain, nil
+}
+
+// readBlock reads and decrypts the block at the given index. It returns an empty
+// block if the file ends before it.
+func (f *encryptedFile) readBlock(block int64) ([]byte, error) {
+	sealed, err := f.readSealed(block)
+	if err != nil || len(sealed) == 0 {
+		return nil, err
+	}
+	plain, err := f.open(block, sealed)
+	if err == nil {
+		return plain, nil
+	}
+	// A crash may have torn the block while it was being rewritten, in which case a
+	// writable handle has not yet restored it from the journal.
+	journaled, prev, ok, jErr := f.readJournal()
+	if jErr != nil || !ok || journaled != block {
+		return nil, err
+	}
+	return f.open(block, prev)
+}
+
+// writeBlock encrypts and writes the block at the given index. Every write uses a
+// fresh random nonce, so rewriting a block never reuses a nonce.
+func (f *encryptedFile) writeBlock(block int64, plain []byte) error {
+	if err := f.journal(block); err != nil {
+		return err
+	}
+	buf := make([]byte, encNonceSize, encNonceSize+len(plain)+encTagSize)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	buf = f.aead.Seal(buf, buf[:encNonceSize], plain, f.additionalData(block))
+	if _, err := f.raw.WriteAt(buf, blockOffset(block)); err != nil {
+		return err
+	}
+	f.state.dirty[block] = struct{}{}
+	return nil
+}
+
+// journal copies the sealed contents of the block at the given index to the journal
+// and syncs the file before the block is rewritten, unless the block does not exist or
+// has not been synced since it was last written.
+func (f *encryptedFile) journal(block int64) error {
+	if _, dirty := f.state.dirty[block]; dirty {
+		return nil
+	}
+	sealed, err := f.readSealed(block)
+	if err != nil || len(sealed) == 0 {
+		return err
+	}
+	// The journal still holds the last synced version of a block that has been
+	// rewritten, so that block must be synced before the journal is reused.
+	if _, dirty := f.state.dirty[f.state.journaled]; dirty {
+		if err = f.sync(); err != nil {
+			return err
+		}
+	}
+	buf := make([]byte, encJournalHeaderSize+len(sealed))
+	binary.LittleEndian.PutUint64(buf, uint64(block))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(len(sealed)))
+	copy(buf[encJournalHeaderSize:], sealed)
+	if _, err = f.raw.WriteAt(buf, encHeaderSize); err != nil {
+		return err
+	}
+	if err = f.sync(); err != nil {
+		return err
+	}
+	f.state.journaled = block
+	return nil
+}
+
+// readJournal reads the block held in the journal. It returns false if the journal is
+// empty or fails authentication.
+func (f *encryptedFile) readJournal() (block int64, sealed []byte, ok bool, err error) {
+	buf := make([]byte, encJournalSize)
+	n, err := f.raw.ReadAt(buf, encHeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, nil, false, err
+	}
+	if n < encJournalHeaderSize {
+		return 0, nil, false, nil
+	}
+	block = int64(binary.LittleEndian.Uint64(buf))
+	size := int(binary.LittleEndian.Uint32(buf[8:]))
+	if size > n-encJournalHeaderSize {
+		return 0, nil, false, nil
+	}
+	sealed = buf[encJournalHeaderSize : encJournalHeaderSize+size]
+	if _, err = f.open(block, sealed); err != nil {
+		return 0, nil, false, nil
+	}
+	return block, sealed, true, nil
+}
+
+// recoverJournal restores the block held in the journal if a crash tore it while it
+// was being rewritten.
+func (f *encryptedFile) recoverJournal() error {
+	block, sealed, ok, err := f.readJournal()
+	if err != nil || !ok {
+		return err
+	}
+	current, err := f.readSealed(block)
+	if err != nil || len(current) == 0 {
+		return err
+	}
+	if _, err = f.open(block, current); err == nil {
+		return nil
+	}
+	if _, err = f.raw.WriteAt(sealed, blockOffset(block)); err != nil {
+		return err
+	}
+	// A partial block can only be the last block in the file, so anything written
+	// after it was torn along with it.
+	if len(sealed) < encSealedSize {
+		if err = f.truncateRaw(blockOffset(block) + int64(len(sealed))); err != nil {
+			return err
+		}
+	}
+	return f.sync()
+}
+
+// discardJournal clears the journal if it holds a block at or after the given index,
+// so that a truncated block is not restored if it is later rewritten and torn.
+func (f *encryptedFile) discardJournal(from int64) error {
+	block, _, ok, err := f.readJournal()
+	if err != nil || !ok || block < from {
+		return err
+	}
+	_, err = f.raw.WriteAt(make([]byte, encJournalHeaderSize), encHeaderSize)
+	return err
+}
+
+// sync syncs the underlying file, after which every block written so far may need to
+// be journaled before it is rewritten.
+func (f *encryptedFile) sync() error {
+	if err := f.raw.Sync(); err != nil {
+		return err
+	}
+	clear(f.state.dirty)
+	return nil
+}
+
+// ReadAt implements io.ReaderAt.
+func (f *encryptedFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	f.state.RLock()
+	loaded := f.aead != nil
+	f.state.RUnlock()
+	if !loaded {
+		// Another handle may have written the header since the file was opened.
+		f.state.Lock()
+		err := f.loadHeader()
+		f.state.Unlock()
+		if err != nil {
+			return 0, err
+		}
+	}
+	f.state.RLock()
+	defer f.state.RUnlock()
+	if f.aead == nil {
+		return 0, io.EOF
+	}
+	return f.readAt(p, off)
+}
+
+func (f *encryptedFile) readAt(p []byte, off int64) (n int, err error) {
+	for n < len(p) {
+		pos := off + int64(n)
+		data, err := f.readBlock(pos / encBlockSize)
+		if err != nil {
+			return n, err
+		}
+		start := pos % encBlockSize
+		if start >= int64(len(data)) {
+			return n, io.EOF
+		}
+		n += copy(p[n:], data[start:])
+	}
+	return n, nil
+}
+
+// Read implements io.Reader.
+func (f *encryptedFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return n, err
+}
+
+// WriteAt implements io.WriterAt.
+func (f *encryptedFile) WriteAt(p []byte, off int64) (int, error) {
+	f.state.Lock()
+	defer f.state.Unlock()
+	return f.writeAt(p, off)
+}
+
+func (f *encryptedFile) writeAt(p []byte, off int64) (int, error) {
+	if !f.writable {
+		return 0, errors.New("file was not opened for writing")
+	}
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	size, err := f.size()
+	if err != nil {
+		return 0, err
+	}
+	written := len(p)
+	// Fill any gap between the end of the file and the offset with zeros.
+	if off > size {
+		p = append(make([]byte, off-size), p...)
+		off = size
+	}
+	for len(p) > 0 {
+		var (
+			block = off / encBlockSize
+			start = off % encBlockSize
+			n     = min(int64(len(p)), encBlockSize-start)
+			plain []byte
+		)
+		if start == 0 && n == encBlockSize {
+			plain = p[:n]
+		} else {
+			existing, err := f.readBlock(block)
+			if err != nil {
+				return 0, err
+			}
+			plain = make([]byte, max(int64(len(existing)), start+n))
+			copy(plain, existing)
+			copy(plain[start:], p[:n])
+		}
+		if err = f.writeBlock(block, plain); err != nil {
+			return 0, err
+		}
+		p, off = p[n:], off+n
+	}
+	return written, nil
+}
+
+// Write implements io.Writer.
+func (f *encryptedFile) Write(p []byte) (int, error) {
+	f.state.Lock()
+	defer f.state.Unlock()
+	if f.append {
+		size, err := f.size()
+		if err != nil {
+			return 0, err
+		}
+		f.offset = size
+	}
+	n, err := f.writeAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Truncate implements File.
+func (f *encryptedFile) Truncate(size int64) error {
+	f.state.Lock()
+	defer f.state.Unlock()
+	if !f.writable {
+		return errors.New("file was not opened for writing")
+	}
+	current, err := f.size()
+	if err != nil {
+		return err
+	}
+	if size >= current {
+		_, err = f.writeAt(make([]byte, size-current), current)
+		return err
+	}
+	block, rem := size/encBlockSize, size%encBlockSize
+	if rem == 0 {
+		if err = f.discardJournal(block); err != nil {
+			return err
+		}
+		return f.truncateRaw(blockOffset(block))
+	}
+	if err = f.discardJournal(block + 1); err != nil {
+		return err
+	}
+	data, err := f.readBlock(block)
+	if err != nil {
+		return err
+	}
+	if err = f.writeBlock(block, data[:rem]); err != nil {
+		return err
+	}
+	return f.truncateRaw(blockOffset(block) + rem + encOverhead)
+}
+
+// Stat implements File.
+func (f *encryptedFile) Stat() (os.FileInfo, error) {
+	info, err := f.raw.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return EncryptedFileInfo(info), nil
+}
+
+// Sync implements File.
+func (f *encryptedFile) Sync() error {
+	f.state.Lock()
+	defer f.state.Unlock()
+	return f.sync()
+}
+
+// Close implements io.Closer.
+func (f *encryptedFile) Close() error { return f.raw.Close() }
+
+// NewEncrypted wraps the given FS so that the contents of all files stored in it are
+// encrypted with AES-GCM using the keys in the given Keyring. File names and the
+// directory structure are not encrypted. Files remain randomly accessible for both
+// reads and writes.
+func NewEncrypted(fs FS, keys *Keyring) FS {
+	return &encryptedFS{base: fs, keys: keys, locks: make(map[string]*fileLock)}
+}
+
+type fileLock struct {
+	*encryptedFileState
+	refs int
+}
+
+type encryptedFS struct {
+	base FS
+	keys *Keyring
+	mu   sync.Mutex
+	// locks holds the lock shared between all open handles to each file.
+	locks map[string]*fileLock
+}
+
+var _ FS = (*encryptedFS)(nil)
+
+// Open implements FS.
+func (e *encryptedFS) Open(name string, flag int) (File, error) {
+	// The underlying file must always be readable to rewrite partially written blocks,
+	// and appends are handled by the encrypted file, as the position of the end of the
+	// plaintext differs from the end of the underlying file.
+	rawFlag := flag &^ os.O_APPEND
+	if rawFlag&os.O_WRONLY != 0 {
+		rawFlag = rawFlag&^os.O_WRONLY | os.O_RDWR
+	}
+	raw, err := e.base.Open(name, rawFlag)
+	if err != nil {
+		return nil, err
+	}
+	l := e.acquireLock(name)
+	f, err := openEncryptedFile(raw, e.keys, flag, l.encryptedFileState)
+	if err != nil {
+		e.releaseLock(name)
+		return nil, errors.CombineErrors(err, raw.Close())
+	}
+	return &lockedEncryptedFile{encryptedFile: f, release: func() { e.releaseLock(name) }}, nil
+}
+
+func (e *encryptedFS) acquireLock(name string) *fileLock {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	l, ok := e.locks[name]
+	if !ok {
+		l = &fileLock{encryptedFileState: newEncryptedFileState()}
+		e.locks[name] = l
+	}
+	l.refs++
+	return l
+}
+
+func (e *encryptedFS) releaseLock(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	l := e.locks[name]
+	if l.refs--; l.refs == 0 {
+		delete(e.locks, name)
+	}
+}
+
+type lockedEncryptedFile struct {
+	*encryptedFile
+	release func()
+	once    sync.Once
+}
+
+// Close implements io.Closer.
+func (f *lockedEncryptedFile) Close() error {
+	f.once.Do(f.release)
+	return f.encryptedFile.Close()
+}
+
+// Sub implements FS.
+func (e *encryptedFS) Sub(name string) (FS, error) {
+	if _, err := e.base.Sub(name); err != nil {
+		return nil, err
+	}
+	return &subFS{dir: name, FS: e}, nil
+}
+
+// List implements FS.
+func (e *encryptedFS) List(name string) ([]os.FileInfo, error) {
+	infos, err := e.base.List(name)
+	if err != nil {
+		return nil, err
+	}
+	for i, info := range infos {
+		infos[i] = EncryptedFileInfo(info)
+	}
+	return infos, nil
+}
+
+// Exists implements FS.
+func (e *encryptedFS) Exists(name string) (bool, error) { return e.base.Exists(name) }
+
+// Remove implements FS.
+func (e *encryptedFS) Remove(name string) error { return e.base.Remove(name) }
+
+// Rename implements FS.
+func (e *encryptedFS) Rename(name string, newName string) error {
+	return e.base.Rename(name, newName)
+}
+
+// Stat implements FS.
+func (e *encryptedFS) Stat(name string) (os.FileInfo, error) {
+	info, err := e.base.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptedFileInfo(info), nil
+}
+
+// Reencrypt re-encrypts the file with the given name in fs, which must be the FS
+// underlying an encrypted FS, with the primary key of the Keyring. The file is
+// rewritten to a temporary file that then replaces it, so the file must not be open
+// while it is re-encrypted. Files that are not encrypted are encrypted with the primary
+// key, so an unencrypted directory can be encrypted by passing each of its files to
+// Reencrypt. Reencrypt returns false if the file is empty or already encrypted with
+// the primary key.
+func Reencrypt(fs FS, name string, keys *Keyring) (reencrypted bool, err error) {
+	raw, err := fs.Open(name, os.O_RDONLY)
+	if err != nil {
+		return false, err
+	}
+	defer func() { err = errors.CombineErrors(err, raw.Close()) }()
+	var src io.ReaderAt = raw
+	enc, err := openEncryptedFile(raw, keys, os.O_RDONLY, newEncryptedFileState())
+	if err == nil {
+		if enc.aead == nil || enc.keyID == keys.primary {
+			return false, nil
+		}
+		src = enc
+	} else if !errors.Is(err, ErrNotEncrypted) {
+		return false, err
+	}
+	tmpName := goPath.Join(goPath.Dir(name), "."+goPath.Base(name)+".rekey")
+	dstRaw, err := fs.Open(tmpName, os.O_CREATE|os.O_RDWR|os.O_TRUNC)
+	if err != nil {
+		return false, err
+	}
+	dst, err := openEncryptedFile(dstRaw, keys, os.O_RDWR|os.O_TRUNC, newEncryptedFileState())
+	if err != nil {
+		return false, errors.CombineErrors(err, dstRaw.Close())
+	}
+	buf := make([]byte, 64*encBlockSize)
+	for off := int64(0); ; off += int64(len(buf)) {
+		n, rErr := src.ReadAt(buf, off)
+		if n > 0 {
+			if _, err = dst.WriteAt(buf[:n], off); err != nil {
+				break
+			}
+		}
+		if rErr != nil {
+			if !errors.Is(rErr, io.EOF) {
+				err = rErr
+			}
+			break
+		}
+	}
+	if err == nil {
+		err = dst.Sync()
+	}
+	if err = errors.CombineErrors(err, dst.Close()); err != nil {
+		return false, errors.CombineErrors(err, fs.Remove(tmpName))
+	}
+	return true, fs.Rename(tmpName, name)
+}
@@ -0,0 +1,236 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package fs_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Encrypted", func() {
+	var (
+		base    *xfs.MemFS
+		oldKey  = bytes.Repeat([]byte{1}, 32)
+		newKey  = bytes.Repeat([]byte{2}, 32)
+		keys    *xfs.Keyring
+		fs      xfs.FS
+		pattern = func(n int) []byte {
+			b := make([]byte, n)
+			for i := range b {
+				b[i] = byte(i % 251)
+			}
+			return b
+		}
+		write = func(fs xfs.FS, name string, data []byte) {
+			f := MustSucceed(fs.Open(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND))
+			MustSucceed(f.Write(data))
+			Expect(f.Close()).To(Succeed())
+		}
+		readAll = func(fs xfs.FS, name string) []byte {
+			f := MustSucceed(fs.Open(name, os.O_RDONLY))
+			b := MustSucceed(io.ReadAll(f))
+			Expect(f.Close()).To(Succeed())
+			return b
+		}
+	)
+	BeforeEach(func() {
+		base = xfs.NewMem()
+		keys = MustSucceed(xfs.NewKeyring(oldKey))
+		fs = xfs.NewEncrypted(base, keys)
+	})
+
+	It("Should not store the plaintext in the underlying file system", func() {
+		data := bytes.Repeat([]byte("tacocat"), 100)
+		write(fs, "file", data)
+		Expect(readAll(base, "file")).ToNot(ContainSubstring("tacocat"))
+		Expect(readAll(fs, "file")).To(Equal(data))
+		Expect(MustSucceed(fs.Stat("file")).Size()).To(Equal(int64(len(data))))
+	})
+
+	It("Should read at arbitrary offsets across block boundaries", func() {
+		data := pattern(10000)
+		write(fs, "file", data)
+		f := MustSucceed(fs.Open("file", os.O_RDONLY))
+		buf := make([]byte, 20)
+		MustSucceed(f.ReadAt(buf, 4090))
+		Expect(buf).To(Equal(data[4090:4110]))
+		n, err := f.ReadAt(buf, 9990)
+		Expect(err).To(MatchError(io.EOF))
+		Expect(buf[:n]).To(Equal(data[9990:]))
+		Expect(f.Close()).To(Succeed())
+	})
+
+	It("Should append through multiple handles", func() {
+		data := pattern(9000)
+		for _, chunk := range [][]byte{data[:10], data[10:5000], data[5000:]} {
+			write(fs, "file", chunk)
+		}
+		Expect(readAll(fs, "file")).To(Equal(data))
+	})
+
+	It("Should let a reader opened on an empty file read data written after it", func() {
+		w := MustSucceed(fs.Open("file", os.O_CREATE|os.O_RDONLY))
+		write(fs, "file", []byte{1, 2, 3})
+		buf := make([]byte, 3)
+		MustSucceed(w.ReadAt(buf, 0))
+		Expect(buf).To(Equal([]byte{1, 2, 3}))
+		Expect(w.Close()).To(Succeed())
+	})
+
+	It("Should overwrite data in the middle of a file", func() {
+		data := pattern(9000)
+		write(fs, "file", data)
+		f := MustSucceed(fs.Open("file", os.O_RDWR))
+		MustSucceed(f.WriteAt([]byte{1, 2, 3, 4}, 4094))
+		Expect(f.Close()).To(Succeed())
+		copy(data[4094:], []byte{1, 2, 3, 4})
+		Expect(readAll(fs, "file")).To(Equal(data))
+	})
+
+	It("Should truncate a file", func() {
+		data := pattern(9000)
+		write(fs, "file", data)
+		f := MustSucceed(fs.Open("file", os.O_RDWR))
+		Expect(f.Truncate(5000)).To(Succeed())
+		Expect(MustSucceed(f.Stat()).Size()).To(Equal(int64(5000)))
+		Expect(f.Truncate(5010)).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+		Expect(readAll(fs, "file")).To(Equal(append(data[:5000:5000], make([]byte, 10)...)))
+	})
+
+	It("Should detect tampering", func() {
+		write(fs, "file", pattern(100))
+		f := MustSucceed(base.Open("file", os.O_RDWR))
+		MustSucceed(f.WriteAt([]byte{0}, MustSucceed(f.Stat()).Size()-20))
+		Expect(f.Close()).To(Succeed())
+		f = MustSucceed(fs.Open("file", os.O_RDONLY))
+		_, err := f.ReadAt(make([]byte, 10), 0)
+		Expect(err).To(HaveOccurredAs(xfs.ErrCorrupted))
+		Expect(f.Close()).To(Succeed())
+	})
+
+	It("Should not open a file that is not encrypted", func() {
+		write(base, "file", pattern(100))
+		Expect(fs.Open("file", os.O_RDONLY)).Error().To(HaveOccurredAs(xfs.ErrNotEncrypted))
+	})
+
+	It("Should not open a file encrypted with an unknown key", func() {
+		write(fs, "file", pattern(100))
+		other := xfs.NewEncrypted(base, MustSucceed(xfs.NewKeyring(newKey)))
+		Expect(other.Open("file", os.O_RDONLY)).Error().To(HaveOccurredAs(xfs.ErrUnknownKey))
+	})
+
+	It("Should identify a keyring by its primary key", func() {
+		Expect(keys.ID()).To(Equal(MustSucceed(xfs.NewKeyring(oldKey, newKey)).ID()))
+		Expect(keys.ID()).ToNot(Equal(MustSucceed(xfs.NewKeyring(newKey, oldKey)).ID()))
+	})
+
+	It("Should not create a keyring with an invalid key", func() {
+		Expect(xfs.NewKeyring([]byte{1, 2, 3})).Error().To(HaveOccurred())
+	})
+
+	Describe("Torn Writes", func() {
+		var synced, appended []byte
+		BeforeEach(func() {
+			synced, appended = pattern(5000), pattern(300)
+		})
+		// writeTorn syncs the first part of a file and then appends to it through a
+		// file that crashes after writing the given number of bytes.
+		writeTorn := func(budget int) {
+			raw := &crashFile{File: MustSucceed(base.Open("file", os.O_CREATE|os.O_RDWR)), budget: -1}
+			f := MustSucceed(xfs.NewEncryptedFile(raw, keys, os.O_RDWR))
+			MustSucceed(f.WriteAt(synced, 0))
+			Expect(f.Sync()).To(Succeed())
+			raw.budget = budget
+			_, _ = f.WriteAt(appended, int64(len(synced)))
+			Expect(f.Close()).To(Succeed())
+		}
+
+		It("Should preserve synced data when an append to the last block is torn", func() {
+			for budget := 0; budget < 2*4096; budget += 97 {
+				base = xfs.NewMem()
+				fs = xfs.NewEncrypted(base, keys)
+				writeTorn(budget)
+				expected := Or(Equal(synced), Equal(append(synced, appended...)))
+				Expect(readAll(fs, "file")).To(expected, "budget %d", budget)
+				f := MustSucceed(fs.Open("file", os.O_RDWR))
+				Expect(f.Close()).To(Succeed())
+				Expect(readAll(fs, "file")).To(expected, "budget %d", budget)
+			}
+		})
+
+		It("Should append to a file after restoring a torn block", func() {
+			writeTorn(1500)
+			write(fs, "file", appended)
+			Expect(readAll(fs, "file")).To(Equal(append(synced, appended...)))
+		})
+	})
+
+	Describe("Reencrypt", func() {
+		It("Should re-encrypt a file with the primary key", func() {
+			data := pattern(10000)
+			write(fs, "file", data)
+			rotated := MustSucceed(xfs.NewKeyring(newKey, oldKey))
+			Expect(readAll(xfs.NewEncrypted(base, rotated), "file")).To(Equal(data))
+			Expect(xfs.Reencrypt(base, "file", rotated)).To(BeTrue())
+			Expect(xfs.Reencrypt(base, "file", rotated)).To(BeFalse())
+			Expect(readAll(xfs.NewEncrypted(base, MustSucceed(xfs.NewKeyring(newKey))), "file")).To(Equal(data))
+			Expect(MustSucceed(base.List(""))).To(HaveLen(1))
+		})
+
+		It("Should encrypt a file that is not encrypted", func() {
+			write(base, "file", pattern(10000))
+			Expect(xfs.Reencrypt(base, "file", keys)).To(BeTrue())
+			Expect(xfs.Reencrypt(base, "file", keys)).To(BeFalse())
+			Expect(readAll(fs, "file")).To(Equal(pattern(10000)))
+			Expect(MustSucceed(base.List(""))).To(HaveLen(1))
+		})
+
+		It("Should skip empty files", func() {
+			Expect(MustSucceed(base.Open("file", os.O_CREATE)).Close()).To(Succeed())
+			Expect(xfs.Reencrypt(base, "file", keys)).To(BeFalse())
+		})
+	})
+})
+
+// crashFile simulates a crash once its budget is set to a non-negative number of
+// bytes, writing no more than the budget and discarding all writes after it.
+type crashFile struct {
+	xfs.File
+	budget int
+}
+
+func (f *crashFile) WriteAt(p []byte, off int64) (int, error) {
+	if f.budget < 0 {
+		return f.File.WriteAt(p, off)
+	}
+	n := min(f.budget, len(p))
+	f.budget -= n
+	if _, err := f.File.WriteAt(p[:n], off); err != nil {
+		return 0, err
+	}
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+func (f *crashFile) Truncate(size int64) error {
+	if f.budget >= 0 {
+		return io.ErrShortWrite
+	}
+	return f.File.Truncate(size)
+}
@@ -26,6 +26,13 @@ var _ = Describe("FS", func() {
 		"osFS": func() xfs.FS {
 			return MustSucceed(xfs.Default.Sub("./testData"))
 		},
+		"encryptedMemFS": func() xfs.FS {
+			return xfs.NewEncrypted(xfs.NewMem(), MustSucceed(xfs.NewKeyring(make([]byte, 32))))
+		},
+		"encryptedOSFS": func() xfs.FS {
+			keys := MustSucceed(xfs.NewKeyring(make([]byte, 32)))
+			return MustSucceed(xfs.NewEncrypted(xfs.Default, keys).Sub("./testData"))
+		},
 	}
 
 	for fsName, makeFS := range fileSystems {
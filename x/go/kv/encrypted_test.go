@@ -0,0 +1,69 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package kv_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	xfs "github.com/synnaxlabs/x/io/fs"
+	"github.com/synnaxlabs/x/kv/pebblekv"
+	. "github.com/synnaxlabs/x/testutil"
+)
+
+var _ = Describe("Encrypted VFS", func() {
+	var (
+		base vfs.FS
+		keys *xfs.Keyring
+		open = func(keys *xfs.Keyring) (*pebble.DB, error) {
+			return pebble.Open("db", &pebble.Options{FS: pebblekv.NewEncryptedFS(base, keys)})
+		}
+	)
+	BeforeEach(func() {
+		base = vfs.NewMem()
+		keys = MustSucceed(xfs.NewKeyring(bytes.Repeat([]byte{1}, 32)))
+	})
+
+	It("Should persist encrypted data across reopens", func() {
+		db := MustSucceed(open(keys))
+		for i := 0; i < 1000; i++ {
+			Expect(db.Set([]byte(fmt.Sprintf("key-%d", i)), []byte("plaintext-value"), pebble.Sync)).To(Succeed())
+		}
+		Expect(db.Flush()).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+
+		By("Not storing the plaintext on disk")
+		for _, name := range MustSucceed(base.List("db")) {
+			f := MustSucceed(base.Open(base.PathJoin("db", name)))
+			Expect(MustSucceed(io.ReadAll(f))).ToNot(ContainSubstring("plaintext-value"))
+			Expect(f.Close()).To(Succeed())
+		}
+
+		By("Reading the data back after reopening")
+		db = MustSucceed(open(keys))
+		v, closer := MustSucceed2(db.Get([]byte("key-999")))
+		Expect(v).To(Equal([]byte("plaintext-value")))
+		Expect(closer.Close()).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+	})
+
+	It("Should not open a store encrypted with a different key", func() {
+		db := MustSucceed(open(keys))
+		Expect(db.Set([]byte("key"), []byte("value"), pebble.Sync)).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+		other := MustSucceed(xfs.NewKeyring(bytes.Repeat([]byte{2}, 32)))
+		Expect(open(other)).Error().To(HaveOccurredAs(xfs.ErrUnknownKey))
+	})
+})
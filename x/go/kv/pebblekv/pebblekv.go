@@ -88,6 +88,17 @@ func (d db) apply(ctx context.Context, txn *tx) error {
 	return nil
 }
 
+// Checkpoint writes a consistent, point-in-time copy of the provided database to the
+// given directory, which must not already exist. The copy can be opened as a
+// standalone pebble database. The provided database must have been created using Wrap.
+func Checkpoint(db_ kv.DB, dirname string) error {
+	d, ok := db_.(*db)
+	if !ok {
+		return errors.Newf("[pebblekv] - cannot checkpoint non-pebble database %T", db_)
+	}
+	return d.DB.Checkpoint(dirname, pebble.WithFlushedWAL())
+}
+
 // Report implement alamos.ReportProvider.
 func (d db) Report() alamos.Report {
 	return alamos.Report{"engine": "pebble"}
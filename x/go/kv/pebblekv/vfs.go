@@ -0,0 +1,110 @@
+// Copyright 2024 Synnax Labs, Inc.
+//
+// Use of this software is governed by the Business Source License included in the file
+// licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with the Business Source
+// License, use of this software will be governed by the Apache License, Version 2.0,
+// included in the file licenses/APL.txt.
+
+package pebblekv
+
+import (
+	"os"
+
+	"github.com/cockroachdb/pebble/vfs"
+	xfs "github.com/synnaxlabs/x/io/fs"
+)
+
+// NewEncryptedFS wraps the given pebble file system so that the contents of all files
+// stored in it are encrypted using the keys in the given Keyring. Files use the same
+// format as those of an encrypted xfs.FS, so xfs.Reencrypt can be used to re-encrypt
+// them after a key is rotated.
+func NewEncryptedFS(fs vfs.FS, keys *xfs.Keyring) vfs.FS {
+	return &encryptedFS{FS: fs, keys: keys}
+}
+
+type encryptedFS struct {
+	vfs.FS
+	keys *xfs.Keyring
+}
+
+var _ vfs.FS = (*encryptedFS)(nil)
+
+func (e *encryptedFS) wrap(f vfs.File, flag int) (vfs.File, error) {
+	ef, err := xfs.NewEncryptedFile(f, e.keys, flag)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedFile{File: ef, raw: f}, nil
+}
+
+// Create implements vfs.FS.
+func (e *encryptedFS) Create(name string) (vfs.File, error) {
+	f, err := e.FS.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.wrap(f, os.O_RDWR)
+}
+
+// Open implements vfs.FS.
+func (e *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	f, err := e.FS.Open(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return e.wrap(f, os.O_RDONLY)
+}
+
+// OpenReadWrite implements vfs.FS.
+func (e *encryptedFS) OpenReadWrite(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	f, err := e.FS.OpenReadWrite(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return e.wrap(f, os.O_RDWR)
+}
+
+// ReuseForWrite implements vfs.FS.
+func (e *encryptedFS) ReuseForWrite(oldname, newname string) (vfs.File, error) {
+	f, err := e.FS.ReuseForWrite(oldname, newname)
+	if err != nil {
+		return nil, err
+	}
+	return e.wrap(f, os.O_RDWR)
+}
+
+// Stat implements vfs.FS.
+func (e *encryptedFS) Stat(name string) (os.FileInfo, error) {
+	info, err := e.FS.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	return xfs.EncryptedFileInfo(info), nil
+}
+
+type encryptedFile struct {
+	xfs.File
+	raw vfs.File
+}
+
+var _ vfs.File = (*encryptedFile)(nil)
+
+// Preallocate implements vfs.File. Preallocation is only a hint, and is skipped as
+// the on-disk layout of an encrypted file differs from its plaintext.
+func (f *encryptedFile) Preallocate(int64, int64) error { return nil }
+
+// SyncTo implements vfs.File.
+func (f *encryptedFile) SyncTo(int64) (bool, error) { return true, f.raw.Sync() }
+
+// SyncData implements vfs.File.
+func (f *encryptedFile) SyncData() error { return f.raw.SyncData() }
+
+// Prefetch implements vfs.File. Prefetching is only a hint, and is skipped for the
+// same reason as Preallocate.
+func (f *encryptedFile) Prefetch(int64, int64) error { return nil }
+
+// Fd implements vfs.File. The underlying descriptor is not exposed, as reads from it
+// would bypass decryption.
+func (f *encryptedFile) Fd() uintptr { return vfs.InvalidFd }
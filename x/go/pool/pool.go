@@ -9,7 +9,11 @@
 
 package pool
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/synnaxlabs/x/errors"
+)
 
 type Adapter interface {
 	Healthy() bool
@@ -24,6 +28,9 @@ type Factory[K comparable, A Adapter] interface {
 
 type Pool[K comparable, A Adapter] interface {
 	Acquire(key K) (A, error)
+	// Close closes all adapters in the pool, returning the combined errors of all
+	// adapters that failed to close.
+	Close() error
 }
 
 func New[K comparable, A Adapter](factory Factory[K, A]) Pool[K, A] {
@@ -57,3 +64,16 @@ func (p *core[K, A]) new(key K) (a A, err error) {
 	p.pool[key] = append(p.pool[key], a)
 	return a, nil
 }
+
+func (p *core[K, A]) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := errors.NewCatcher(errors.WithAggregation())
+	for _, adapters := range p.pool {
+		for _, adapter := range adapters {
+			c.Exec(adapter.Close)
+		}
+	}
+	p.pool = make(map[K][]A)
+	return c.Error()
+}
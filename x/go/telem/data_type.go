@@ -26,6 +26,17 @@ func (d DataType) IsVariable() bool {
 	return d == BytesT || d == StringT || d == JSONT
 }
 
+// IsNumeric returns true if the data type holds integer or floating point values.
+// Timestamps are stored as integers, but are not considered numeric.
+func (d DataType) IsNumeric() bool {
+	switch d {
+	case Float64T, Float32T, Int64T, Int32T, Int16T, Int8T, Uint64T, Uint32T, Uint16T, Uint8T:
+		return true
+	default:
+		return false
+	}
+}
+
 func NewDataType[T any](v T) DataType {
 	t := reflect.TypeOf(v)
 	dt, ok := dataTypes[strings.ToLower(t.Name())]
@@ -22,6 +22,22 @@ func TranslateTimeRangeBackward(tr *PBTimeRange) (otr TimeRange) {
 	return
 }
 
+func TranslateManyTimeRangesForward(trs []TimeRange) []*PBTimeRange {
+	ranges := make([]*PBTimeRange, len(trs))
+	for i := range trs {
+		ranges[i] = TranslateTimeRangeForward(trs[i])
+	}
+	return ranges
+}
+
+func TranslateManyTimeRangesBackward(trs []*PBTimeRange) []TimeRange {
+	ranges := make([]TimeRange, len(trs))
+	for i := range trs {
+		ranges[i] = TranslateTimeRangeBackward(trs[i])
+	}
+	return ranges
+}
+
 func TranslateSeriesForward(s Series) *PBSeries {
 	return &PBSeries{
 		DataType:  string(s.DataType),
@@ -70,3 +70,7 @@ func ValueAt[T types.Numeric](s Series, i int64) T {
 	b := s.Data[i*int64(s.DataType.Density()) : (i+1)*int64(s.DataType.Density())]
 	return UnmarshalF[T](s.DataType)(b)
 }
+
+// Float64At returns the value at the given index in a series of a numeric or
+// timestamp data type, converted to a float64.
+func (s Series) Float64At(i int64) float64 { return ValueAt[float64](s, i) }
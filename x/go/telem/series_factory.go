@@ -153,13 +153,13 @@ func UnmarshalF[T types.Numeric](dt DataType) func(b []byte) T {
 			return T(math.Float32frombits(bits))
 		}
 	case Int64T:
-		return func(b []byte) T { return T(ByteOrder.Uint64(b)) }
+		return func(b []byte) T { return T(int64(ByteOrder.Uint64(b))) }
 	case Int32T:
-		return func(b []byte) T { return T(ByteOrder.Uint32(b)) }
+		return func(b []byte) T { return T(int32(ByteOrder.Uint32(b))) }
 	case Int16T:
-		return func(b []byte) T { return T(ByteOrder.Uint16(b)) }
+		return func(b []byte) T { return T(int16(ByteOrder.Uint16(b))) }
 	case Int8T:
-		return func(b []byte) T { return T(b[0]) }
+		return func(b []byte) T { return T(int8(b[0])) }
 	case Uint64T:
 		return func(b []byte) T { return T(ByteOrder.Uint64(b)) }
 	case Uint32T:
@@ -169,7 +169,7 @@ func UnmarshalF[T types.Numeric](dt DataType) func(b []byte) T {
 	case Uint8T:
 		return func(b []byte) T { return T(b[0]) }
 	case TimeStampT:
-		return func(b []byte) T { return T(ByteOrder.Uint64(b)) }
+		return func(b []byte) T { return T(int64(ByteOrder.Uint64(b))) }
 	}
 	panic("unsupported data type")
 }
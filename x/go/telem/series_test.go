@@ -89,4 +89,21 @@ var _ = Describe("Series", func() {
 			Expect(telem.Unmarshal[uint8](s)).To(Equal(d))
 		})
 	})
+	Describe("Float64At", func() {
+		DescribeTable("Should convert samples of numeric data types to float64", func(s telem.Series, expected float64) {
+			Expect(s.Float64At(1)).To(Equal(expected))
+		},
+			Entry("float64", telem.NewSeriesV[float64](1, -2.5), -2.5),
+			Entry("float32", telem.NewSeriesV[float32](1, -2.5), -2.5),
+			Entry("int64", telem.NewSeriesV[int64](1, -2), float64(-2)),
+			Entry("int32", telem.NewSeriesV[int32](1, -2), float64(-2)),
+			Entry("int16", telem.NewSeriesV[int16](1, -2), float64(-2)),
+			Entry("int8", telem.NewSeriesV[int8](1, -2), float64(-2)),
+			Entry("uint64", telem.NewSeriesV[uint64](1, 2), float64(2)),
+			Entry("uint32", telem.NewSeriesV[uint32](1, 2), float64(2)),
+			Entry("uint16", telem.NewSeriesV[uint16](1, 2), float64(2)),
+			Entry("uint8", telem.NewSeriesV[uint8](1, 2), float64(2)),
+			Entry("timestamp", telem.NewSecondsTSV(1, 2), float64(2*telem.SecondTS)),
+		)
+	})
 })
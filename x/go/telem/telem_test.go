@@ -398,4 +398,19 @@ var _ = Describe("Telem", func() {
 			Expect(og).To(Equal(unmarshalled))
 		})
 	})
+
+	Describe("DataType", func() {
+		Describe("IsNumeric", func() {
+			It("Should return true for integer and floating point data types", func() {
+				Expect(telem.Float32T.IsNumeric()).To(BeTrue())
+				Expect(telem.Int8T.IsNumeric()).To(BeTrue())
+				Expect(telem.Uint64T.IsNumeric()).To(BeTrue())
+			})
+			It("Should return false for other data types", func() {
+				Expect(telem.TimeStampT.IsNumeric()).To(BeFalse())
+				Expect(telem.UUIDT.IsNumeric()).To(BeFalse())
+				Expect(telem.StringT.IsNumeric()).To(BeFalse())
+			})
+		})
+	})
 })